@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"strings"
+
+	"github.com/amirasaad/fintech/pkg/money"
+)
+
+// displayMoney formats m for the terminal with the decimals and symbol the
+// currency registry gives its currency, e.g. "$1234.50 USD" or "¥1235 JPY".
+// Without a registry, or for a currency it does not list, m is formatted with
+// its own decimals and no symbol.
+func displayMoney(ctx context.Context, m *money.Money) string {
+	cur := m.Currency()
+	var symbol string
+	if currencySvc != nil {
+		meta, err := currencySvc.GetEntity(ctx, cur.Code.String())
+		if err == nil {
+			cur.Decimals = meta.Decimals
+			symbol = meta.Symbol
+		}
+	}
+	// Read the amount with the registry's decimals, which may differ from
+	// the defaults the repository assumes.
+	if exact, err := money.NewFromSmallestUnit(m.Amount(), cur); err == nil {
+		m = exact
+	}
+	return formatMoney(m, symbol)
+}
+
+// formatMoney formats m with the currency symbol before the amount and the
+// currency code after it. The symbol is left out if it is the code itself.
+func formatMoney(m *money.Money, symbol string) string {
+	code := m.CurrencyCode().String()
+	amount := m.AmountString()
+	if symbol == "" || symbol == code {
+		return amount + " " + code
+	}
+	sign := ""
+	if rest, ok := strings.CutPrefix(amount, "-"); ok {
+		sign, amount = "-", rest
+	}
+	return sign + symbol + amount + " " + code
+}
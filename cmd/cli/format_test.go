@@ -0,0 +1,41 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/amirasaad/fintech/pkg/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatMoney(t *testing.T) {
+	kwd := money.Currency{Code: money.KWD, Decimals: 3}
+	tests := []struct {
+		name   string
+		amount int64
+		cur    any
+		symbol string
+		want   string
+	}{
+		{"USD", 123450, money.USD, "$", "$1234.50 USD"},
+		{"JPY has no decimals", 1235, money.JPY, "¥", "¥1235 JPY"},
+		{"KWD has three decimals", 12345, kwd, "د.ك", "د.ك12.345 KWD"},
+		{"negative", -500, money.USD, "$", "-$5.00 USD"},
+		{"symbol is the code", 100, money.Code("CHF"), "CHF", "1.00 CHF"},
+		{"no symbol", 100, money.Code("CHF"), "", "1.00 CHF"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := money.NewFromSmallestUnit(tt.amount, tt.cur)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, formatMoney(m, tt.symbol))
+		})
+	}
+}
+
+func TestDisplayMoney_WithoutRegistry(t *testing.T) {
+	m, err := money.NewFromSmallestUnit(1235, money.JPY)
+	require.NoError(t, err)
+	assert.Equal(t, "1235 JPY", displayMoney(context.Background(), m))
+}
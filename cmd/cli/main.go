@@ -3,34 +3,62 @@ package main
 import (
 	"bufio"
 	"context"
+	"database/sql/driver"
+	"errors"
 	"flag"
 	"fmt"
 	"io"
 	"log"
 	"log/slog"
+	"net"
 	"os"
 	"strconv"
 	"strings"
+	"syscall"
+	"time"
 
 	"github.com/amirasaad/fintech/infra"
 	"github.com/amirasaad/fintech/infra/eventbus"
+	"github.com/amirasaad/fintech/infra/initializer"
+	"github.com/amirasaad/fintech/infra/provider/exchangerateapi"
+	"github.com/amirasaad/fintech/infra/provider/stripepayment"
 	infra_repository "github.com/amirasaad/fintech/infra/repository"
 	"github.com/amirasaad/fintech/pkg/app"
 	"github.com/amirasaad/fintech/pkg/commands"
 	"github.com/amirasaad/fintech/pkg/config"
+	"github.com/amirasaad/fintech/pkg/currency"
 	"github.com/amirasaad/fintech/pkg/dto"
+	"github.com/amirasaad/fintech/pkg/money"
 	"github.com/amirasaad/fintech/pkg/service/account"
 	"github.com/amirasaad/fintech/pkg/service/auth"
+	currencysvc "github.com/amirasaad/fintech/pkg/service/currency"
+	"github.com/amirasaad/fintech/pkg/validation"
 	"github.com/fatih/color"
 	"github.com/google/uuid"
 	"golang.org/x/term"
 )
 
-var userID uuid.UUID
+var (
+	userID          uuid.UUID
+	targetValidator = validation.NewExternalTargetValidator()
+	// currencySvc looks up the symbols and decimals balances are displayed
+	// with; nil if the currency registry is unavailable.
+	currencySvc *currencysvc.Service
+)
+
+// Operations failing with a connection error are tried up to retryAttempts
+// times, waiting retryBackoff before the first retry and doubling it after.
+const (
+	retryAttempts = 3
+	retryBackoff  = 500 * time.Millisecond
+)
 
 func main() {
 	verbose := flag.Bool("v", false, "enable verbose output")
+	check := flag.Bool("check", false, "check the CLI's dependencies and exit")
 	flag.Parse()
+	// "diagnose" is the same as -check.
+	*check = *check || flag.Arg(0) == "diagnose"
 
 	// Setup logging
 	var logger *slog.Logger
@@ -49,6 +77,9 @@ func main() {
 		).Fprintln(
 			os.Stderr,
 			"Failed to load application configuration:", err)
+		if *check {
+			os.Exit(1)
+		}
 		return
 	}
 
@@ -61,6 +92,13 @@ func main() {
 			cfg.ExchangeRateAPIProviders.ExchangeRateApi.ApiKey != "")
 	}
 
+	if *check {
+		if !diagnose(cfg, logger) {
+			os.Exit(1)
+		}
+		return
+	}
+
 	appEnv := os.Getenv("APP_ENV")
 	// Initialize DB connection ONCE
 	db, err := infra.NewDBConnection(cfg.DB, appEnv)
@@ -70,15 +108,25 @@ func main() {
 	}
 	// override auth
 	cfg.Auth.Strategy = "basic"
+	targetValidator = validation.NewExternalTargetValidatorFromConfig(cfg.Withdraw)
 
 	// Create UOW factory using the shared db
 	uow := infra_repository.NewUoW(db)
 	bus := eventbus.NewWithMemoryAsync(logger)
+	// Balances are displayed without symbols if the registry is unavailable.
+	currencyRegistry, err := initializer.NewCurrencyRegistry(cfg, logger)
+	if err != nil {
+		logger.Warn("Currency registry unavailable", "error", err)
+	}
 	app := app.New(&app.Deps{
-		EventBus: bus,
-		Uow:      uow,
-		Logger:   logger,
+		EventBus:         bus,
+		Uow:              uow,
+		Logger:           logger,
+		CurrencyRegistry: currencyRegistry,
 	}, cfg)
+	if currencyRegistry != nil {
+		currencySvc = app.CurrencyService
+	}
 
 	cliApp(app)
 }
@@ -151,7 +199,11 @@ func handleLogin(
 	fmt.Println()
 	password := string(bytePassword)
 
-	user, err := authSvc.Login(context.Background(), identity, password)
+	var user *dto.UserRead
+	err := withRetry("Login", func() (err error) {
+		user, err = authSvc.Login(context.Background(), identity, password)
+		return err
+	})
 	if err != nil {
 		fmt.Println(errorMsg("Login error:"), err)
 		return false
@@ -229,7 +281,10 @@ func handleCreateAccount(
 		return
 	}
 
-	fmt.Println(successMsg(fmt.Sprintf("Account created: ID=%s, Balance=%.2f", a.ID, balance)))
+	fmt.Println(successMsg(fmt.Sprintf(
+		"Account created: ID=%s, Balance=%s",
+		a.ID, displayMoney(context.Background(), balance),
+	)))
 }
 
 func handleDeposit(
@@ -252,18 +307,56 @@ func handleDeposit(
 		return
 	}
 
-	err = scv.Deposit(context.Background(), commands.Deposit{
-		UserID:    userID,
-		AccountID: uuid.MustParse(accountID),
-		Amount:    amount,
-		Currency:  "USD",
+	currencyCode, ok := promptCurrency(bufio.NewReader(os.Stdin), errorMsg)
+	if !ok {
+		return
+	}
+
+	err = withRetry("Deposit", func() error {
+		return scv.Deposit(context.Background(), commands.Deposit{
+			UserID:    userID,
+			AccountID: uuid.MustParse(accountID),
+			Amount:    amount,
+			Currency:  currencyCode,
+		})
 	})
 	if err != nil {
 		fmt.Println(errorMsg("Error depositing:"), err)
 		return
 	}
 
-	fmt.Println(successMsg(fmt.Sprintf("Deposited %.2f to account %s", amount, accountID)))
+	fmt.Println(successMsg(fmt.Sprintf(
+		"Deposited %.2f %s to account %s", amount, currencyCode, accountID)))
+}
+
+// promptCurrency asks for the currency of a deposit or withdrawal, defaulting
+// to currency.DefaultCode. The code must be supported by the currency
+// registry, or only well-formed while the registry is unavailable. It prints
+// why a code is refused and reports false.
+func promptCurrency(
+	reader *bufio.Reader,
+	errorMsg func(
+		a ...any,
+	) string,
+) (string, bool) {
+	fmt.Printf("Currency (leave blank for %s): ", currency.DefaultCode)
+	currencyCode, _ := reader.ReadString('\n')
+	currencyCode = strings.ToUpper(strings.TrimSpace(currencyCode))
+	if currencyCode == "" {
+		currencyCode = currency.DefaultCode
+	}
+	if !currency.IsValidFormat(currencyCode) {
+		fmt.Println(
+			errorMsg("Invalid currency:"),
+			"must be a 3-letter ISO 4217 code, got", currencyCode,
+		)
+		return "", false
+	}
+	if currencySvc != nil && !currencySvc.IsSupported(context.Background(), currencyCode) {
+		fmt.Println(errorMsg("Unsupported currency:"), currencyCode)
+		return "", false
+	}
+	return currencyCode, true
 }
 
 func handleWithdraw(
@@ -287,6 +380,11 @@ func handleWithdraw(
 	}
 
 	reader := bufio.NewReader(os.Stdin)
+	currencyCode, ok := promptCurrency(reader, errorMsg)
+	if !ok {
+		return
+	}
+
 	fmt.Print("Bank Account Number (leave blank if not applicable): ")
 	bankAccountNumber, _ := reader.ReadString('\n')
 	bankAccountNumber = strings.TrimSpace(bankAccountNumber)
@@ -299,16 +397,39 @@ func handleWithdraw(
 	externalWalletAddress, _ := reader.ReadString('\n')
 	externalWalletAddress = strings.TrimSpace(externalWalletAddress)
 
-	err = scv.Withdraw(context.Background(), commands.Withdraw{
-		UserID:    userID,
-		AccountID: uuid.MustParse(accountID),
-		Amount:    amount,
-		Currency:  "USD",
-		ExternalTarget: &commands.ExternalTarget{
-			BankAccountNumber:     bankAccountNumber,
-			RoutingNumber:         routingNumber,
-			ExternalWalletAddress: externalWalletAddress,
-		},
+	network := ""
+	if externalWalletAddress != "" {
+		fmt.Print("Wallet Network (e.g. bitcoin, ethereum, tron, bsc; blank to detect): ")
+		network, _ = reader.ReadString('\n')
+		network = strings.TrimSpace(network)
+	}
+
+	target := &commands.ExternalTarget{
+		BankAccountNumber:     bankAccountNumber,
+		RoutingNumber:         routingNumber,
+		ExternalWalletAddress: externalWalletAddress,
+		Network:               network,
+	}
+	if err = targetValidator.Validate(target, currencyCode); err != nil {
+		var fieldErrs validation.Errors
+		if !errors.As(err, &fieldErrs) {
+			fmt.Println(errorMsg("Invalid external target:"), err)
+			return
+		}
+		for _, fe := range fieldErrs {
+			fmt.Println(errorMsg("Invalid "+fe.Field+":"), fe.Message)
+		}
+		return
+	}
+
+	err = withRetry("Withdrawal", func() error {
+		return scv.Withdraw(context.Background(), commands.Withdraw{
+			UserID:         userID,
+			AccountID:      uuid.MustParse(accountID),
+			Amount:         amount,
+			Currency:       currencyCode,
+			ExternalTarget: target,
+		})
 	})
 	if err != nil {
 		fmt.Println(errorMsg("Error withdrawing:"), err)
@@ -316,7 +437,7 @@ func handleWithdraw(
 	}
 
 	fmt.Println(successMsg(fmt.Sprintf(
-		"Withdrew %.2f from account %s", amount, accountID)))
+		"Withdrew %.2f %s from account %s", amount, currencyCode, accountID)))
 }
 
 func handleBalance(
@@ -333,11 +454,140 @@ func handleBalance(
 	}
 
 	accountID := args[1]
-	balance, err := scv.GetBalance(context.Background(), userID, uuid.MustParse(accountID))
+	var balance *money.Money
+	err := withRetry("Fetching the balance", func() (err error) {
+		balance, err = scv.GetBalance(context.Background(), userID, uuid.MustParse(accountID))
+		return err
+	})
 	if err != nil {
 		fmt.Println(errorMsg("Error fetching balance:"), err)
 		return
 	}
 
-	fmt.Println(successMsg(fmt.Sprintf("Account %s balance: %.2f", accountID, balance)))
+	fmt.Println(successMsg(fmt.Sprintf(
+		"Account %s balance: %s",
+		accountID, displayMoney(context.Background(), balance),
+	)))
+}
+
+// withRetry runs op, retrying it with exponential backoff while it fails with
+// a connection error, e.g. while the database restarts. Other errors, such as
+// bad credentials or insufficient funds, are returned at once. what names the
+// operation in the message printed before each retry.
+func withRetry(what string, op func() error) error {
+	warnMsg := color.New(color.FgYellow, color.Bold).SprintFunc()
+	backoff := retryBackoff
+	for attempt := 1; ; attempt++ {
+		err := op()
+		if err == nil || !isRetryable(err) || attempt == retryAttempts {
+			return err
+		}
+		fmt.Println(warnMsg(fmt.Sprintf(
+			"%s failed with a connection error, retrying in %s (attempt %d of %d):",
+			what, backoff, attempt+1, retryAttempts,
+		)), err)
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+// isRetryable reports whether err is a connection error that may go away if
+// the operation is tried again.
+func isRetryable(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr) ||
+		errors.Is(err, driver.ErrBadConn) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// diagnoseTimeout bounds each dependency check.
+const diagnoseTimeout = 10 * time.Second
+
+// errSkipped marks a check for a dependency that is not configured.
+var errSkipped = errors.New("not configured")
+
+// dependencyCheck verifies that one dependency of the CLI is usable.
+type dependencyCheck struct {
+	name  string
+	check func(ctx context.Context) error
+}
+
+// diagnose checks the database, the exchange rate system and, if configured,
+// the payment provider, printing a pass or fail line for each. It reports
+// whether all configured dependencies passed.
+func diagnose(cfg *config.App, logger *slog.Logger) bool {
+	pass := color.New(color.FgGreen, color.Bold).SprintFunc()
+	fail := color.New(color.FgRed, color.Bold).SprintFunc()
+	skip := color.New(color.FgYellow, color.Bold).SprintFunc()
+
+	ok := true
+	for _, c := range []dependencyCheck{
+		{"Database", func(ctx context.Context) error { return checkDatabase(ctx, cfg) }},
+		{"Exchange rates", func(ctx context.Context) error {
+			return checkExchangeRates(ctx, cfg, logger)
+		}},
+		{"Payment provider", func(ctx context.Context) error {
+			return checkPaymentProvider(ctx, cfg, logger)
+		}},
+	} {
+		ctx, cancel := context.WithTimeout(context.Background(), diagnoseTimeout)
+		err := c.check(ctx)
+		cancel()
+		switch {
+		case err == nil:
+			fmt.Println(pass("✔ PASS"), c.name)
+		case errors.Is(err, errSkipped):
+			fmt.Println(skip("- SKIP"), c.name+":", err)
+		default:
+			ok = false
+			fmt.Println(fail("✘ FAIL"), c.name+":", err)
+		}
+		logger.Info("Dependency checked", "dependency", c.name, "error", err)
+	}
+	return ok
+}
+
+// checkDatabase connects to the database and pings it.
+func checkDatabase(ctx context.Context, cfg *config.App) error {
+	db, err := infra.NewDBConnection(cfg.DB, os.Getenv("APP_ENV"))
+	if err != nil {
+		return err
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	defer sqlDB.Close() //nolint:errcheck
+	return sqlDB.PingContext(ctx)
+}
+
+// checkExchangeRates initializes the exchange rate registry and fetches the
+// rates from the configured provider, as the server does on startup.
+func checkExchangeRates(ctx context.Context, cfg *config.App, logger *slog.Logger) error {
+	if _, err := initializer.GetExchangeRateRegistry(cfg, logger); err != nil {
+		return fmt.Errorf("registry: %w", err)
+	}
+	apiCfg := cfg.ExchangeRateAPIProviders.ExchangeRateApi
+	if apiCfg.ApiKey == "" {
+		return errors.New("EXCHANGE_RATE_PROVIDER_EXCHANGERATE_API_KEY is not set")
+	}
+	provider := exchangerateapi.NewExchangeRateAPIProvider(apiCfg, logger)
+	rates, err := provider.FetchRates(ctx, "USD")
+	if err != nil {
+		return err
+	}
+	logger.Info("Fetched exchange rates", "rates_count", len(rates))
+	return nil
+}
+
+// checkPaymentProvider verifies the Stripe API key if one is configured.
+func checkPaymentProvider(ctx context.Context, cfg *config.App, logger *slog.Logger) error {
+	if cfg.PaymentProviders == nil || cfg.PaymentProviders.Stripe == nil ||
+		cfg.PaymentProviders.Stripe.ApiKey == "" {
+		return errSkipped
+	}
+	return stripepayment.New(nil, nil, cfg.PaymentProviders.Stripe, logger, nil).
+		CheckHealth(ctx)
 }
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/amirasaad/fintech/pkg/registry"
+	currencysvc "github.com/amirasaad/fintech/pkg/service/currency"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// withCurrencies sets currencySvc to a registry of the currencies for the
+// test.
+func withCurrencies(t *testing.T, currencies ...currencysvc.Entity) {
+	t.Helper()
+	svc := currencysvc.New(
+		registry.NewEnhanced(registry.Config{Name: "test-currencies"}),
+		slog.Default(),
+	)
+	for _, c := range currencies {
+		require.NoError(t, svc.Register(context.Background(), c))
+	}
+	prev := currencySvc
+	currencySvc = svc
+	t.Cleanup(func() { currencySvc = prev })
+}
+
+func TestPromptCurrency(t *testing.T) {
+	withCurrencies(t,
+		currencysvc.Entity{Code: "USD", Name: "US Dollar", Symbol: "$", Decimals: 2, Active: true},
+		currencysvc.Entity{Code: "EUR", Name: "Euro", Symbol: "€", Decimals: 2, Active: true},
+	)
+	errorMsg := func(a ...any) string { return "" }
+	tests := []struct {
+		input  string
+		want   string
+		wantOK bool
+	}{
+		{"\n", "USD", true},
+		{"eur\n", "EUR", true},
+		{"XYZ\n", "", false},
+		{"EURO\n", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(strings.TrimSpace(tt.input), func(t *testing.T) {
+			got, ok := promptCurrency(bufio.NewReader(strings.NewReader(tt.input)), errorMsg)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
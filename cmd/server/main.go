@@ -1,16 +1,29 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
+	"github.com/amirasaad/fintech/cmd/server/swagger"
 	"github.com/amirasaad/fintech/infra/initializer"
+	"github.com/amirasaad/fintech/infra/tracing"
 	"github.com/amirasaad/fintech/pkg/app"
 	"github.com/amirasaad/fintech/pkg/config"
+	"github.com/amirasaad/fintech/pkg/registry"
 	"github.com/amirasaad/fintech/webapi"
 	log "github.com/charmbracelet/log"
 )
 
+// shutdownTimeout bounds how long in-flight requests and the final registry
+// saves may take once the server is asked to stop.
+const shutdownTimeout = 30 * time.Second
+
 // @title Fintech API
 // @version 1.0.0
 // @description Fintech API documentation
@@ -41,6 +54,12 @@ func run() error {
 		return fmt.Errorf("failed to load application configuration: %w", err)
 	}
 
+	// Export traces when an OTLP endpoint is configured
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing)
+	if err != nil {
+		return fmt.Errorf("failed to set up tracing: %w", err)
+	}
+
 	// Initialize all dependencies
 	deps, err := initializer.InitializeDependencies(cfg)
 	if err != nil {
@@ -58,9 +77,38 @@ func run() error {
 	// Create and start the application
 	app := app.New(deps, cfg)
 
+	// Document the routes under API_PREFIX, where SetupApp mounts them
+	if cfg.API != nil && cfg.API.Prefix != "" {
+		swagger.SwaggerInfo.BasePath = cfg.API.Prefix
+	}
+
 	// Setup Fiber app with all routes and middleware
 	fiberApp := webapi.SetupApp(app)
 
+	// Stop on SIGINT or SIGTERM, e.g. when the container is stopped
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Execute scheduled transfers in the background
+	if cfg.TransferScheduler == nil || cfg.TransferScheduler.Enabled {
+		go app.TransferScheduler.Run(ctx)
+	}
+
+	// Accrue daily interest on savings accounts in the background
+	if in := cfg.Interest; in != nil && in.Enabled && in.SavingsAPY > 0 {
+		go app.InterestAccruer.Run(ctx)
+	}
+
+	// Export selected domain events to the analytics sink in the background
+	if app.AnalyticsExporter != nil {
+		go app.AnalyticsExporter.Run(ctx)
+	}
+
+	// Prefetch high-traffic exchange rates without delaying startup
+	if ex := cfg.Exchange; ex != nil && ex.WarmupEnabled && len(ex.WarmupPairs) > 0 {
+		go app.ExchangeRateService.Warmup(ctx, ex.WarmupPairs, ex.WarmupTimeout)
+	}
+
 	// Start the server
 	addr := fmt.Sprintf("%s:%d", cfg.Server.Host, cfg.Server.Port)
 	logger.Info("Starting server",
@@ -69,5 +117,41 @@ func run() error {
 		"scheme", cfg.Server.Scheme,
 	)
 
-	return fiberApp.Listen(addr)
+	listenErr := make(chan error, 1)
+	go func() { listenErr <- fiberApp.Listen(addr) }()
+	select {
+	case err = <-listenErr:
+		stop()
+	case <-ctx.Done():
+	}
+
+	logger.Info("Shutting down server")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err == nil {
+		// Finish in-flight requests first, so changes they make to the
+		// currency registry are included in its final save.
+		if serr := fiberApp.ShutdownWithContext(shutdownCtx); serr != nil {
+			logger.Error("Failed to shut down server", "error", serr)
+		}
+	}
+	return errors.Join(
+		err,
+		closeCurrencyRegistry(shutdownCtx, deps.CurrencyRegistry),
+		// Flush the spans of the last requests.
+		shutdownTracing(shutdownCtx),
+	)
+}
+
+// closeCurrencyRegistry closes the currency registry provider, which saves
+// the currencies one final time when they are persisted.
+func closeCurrencyRegistry(ctx context.Context, provider registry.Provider) error {
+	closer, ok := provider.(registry.Closer)
+	if !ok {
+		return nil
+	}
+	if err := closer.Close(ctx); err != nil {
+		return fmt.Errorf("failed to close currency registry: %w", err)
+	}
+	return nil
 }
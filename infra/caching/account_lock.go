@@ -0,0 +1,91 @@
+package caching
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// DefaultAccountLockTTL is how long a lock is kept when its holder
+	// dies without releasing it, unless configured otherwise.
+	DefaultAccountLockTTL = 30 * time.Second
+
+	// accountLockRetry bounds the wait between attempts to take a held
+	// lock.
+	accountLockRetry = 50 * time.Millisecond
+	// accountUnlockTimeout bounds releasing a lock, which runs even when
+	// the operation's context is done.
+	accountUnlockTimeout = 2 * time.Second
+)
+
+// unlockScript deletes a lock only if it still holds the holder's token, so
+// a holder whose lock expired never releases the next holder's.
+var unlockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// AccountLocker locks accounts across instances with Redis: a lock is a key
+// set if absent to a token of its holder, expiring after a TTL so that an
+// instance that dies holding it does not block the account for longer. It
+// implements account.AccountLocker.
+type AccountLocker struct {
+	client *redis.Client
+	prefix string
+	ttl    time.Duration
+}
+
+// NewAccountLocker creates an AccountLocker storing keys under prefix that
+// expire after ttl, or DefaultAccountLockTTL if ttl is not positive. The TTL
+// must be longer than the operations holding the lock.
+func NewAccountLocker(client *redis.Client, prefix string, ttl time.Duration) *AccountLocker {
+	if ttl <= 0 {
+		ttl = DefaultAccountLockTTL
+	}
+	return &AccountLocker{client: client, prefix: prefix, ttl: ttl}
+}
+
+// Lock locks the account, retrying while it is held until ctx is done.
+func (l *AccountLocker) Lock(ctx context.Context, accountID uuid.UUID) (func(), error) {
+	key := l.key(accountID)
+	token := uuid.NewString()
+	wait := 5 * time.Millisecond
+	for {
+		ok, err := l.client.SetNX(ctx, key, token, l.ttl).Result()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			return nil, fmt.Errorf("failed to lock account: %w", err)
+		}
+		if ok {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(wait):
+		}
+		wait = min(2*wait, accountLockRetry)
+	}
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			ctx, cancel := context.WithTimeout(context.Background(), accountUnlockTimeout)
+			defer cancel()
+			// A lock that cannot be released expires after the TTL.
+			_ = unlockScript.Run(ctx, l.client, []string{key}, token).Err()
+		})
+	}, nil
+}
+
+func (l *AccountLocker) key(accountID uuid.UUID) string {
+	return l.prefix + "accounts:lock:" + accountID.String()
+}
@@ -0,0 +1,87 @@
+package caching
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/amirasaad/fintech/pkg/commandbus"
+	"github.com/redis/go-redis/v9"
+)
+
+// IdempotencyStore keeps the idempotency keys of commands in Redis, so a
+// request retried against another instance is recognized too. A key is
+// reserved with SET NX, which only one instance wins. It implements
+// commandbus.IdempotencyStore.
+type IdempotencyStore struct {
+	client *redis.Client
+	prefix string
+}
+
+// NewIdempotencyStore creates an IdempotencyStore storing keys under prefix.
+func NewIdempotencyStore(client *redis.Client, prefix string) *IdempotencyStore {
+	return &IdempotencyStore{client: client, prefix: prefix}
+}
+
+// Reserve implements commandbus.IdempotencyStore.
+func (s *IdempotencyStore) Reserve(
+	ctx context.Context,
+	key string,
+	ttl time.Duration,
+) (*commandbus.IdempotencyRecord, error) {
+	pending, err := json.Marshal(commandbus.IdempotencyRecord{})
+	if err != nil {
+		return nil, err
+	}
+	reserved, err := s.client.SetNX(ctx, s.key(key), pending, ttl).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to reserve idempotency key: %w", err)
+	}
+	if reserved {
+		return nil, nil
+	}
+	data, err := s.client.Get(ctx, s.key(key)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		// The key expired or was released since; take it.
+		return s.Reserve(ctx, key, ttl)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read idempotency key: %w", err)
+	}
+	var record commandbus.IdempotencyRecord
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, fmt.Errorf("invalid idempotency record: %w", err)
+	}
+	return &record, nil
+}
+
+// Complete implements commandbus.IdempotencyStore.
+func (s *IdempotencyStore) Complete(
+	ctx context.Context,
+	key string,
+	record commandbus.IdempotencyRecord,
+	ttl time.Duration,
+) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	if err := s.client.Set(ctx, s.key(key), data, ttl).Err(); err != nil {
+		return fmt.Errorf("failed to save idempotency record: %w", err)
+	}
+	return nil
+}
+
+// Release implements commandbus.IdempotencyStore.
+func (s *IdempotencyStore) Release(ctx context.Context, key string) error {
+	if err := s.client.Del(ctx, s.key(key)).Err(); err != nil {
+		return fmt.Errorf("failed to release idempotency key: %w", err)
+	}
+	return nil
+}
+
+func (s *IdempotencyStore) key(key string) string {
+	return s.prefix + "idempotency:" + key
+}
@@ -0,0 +1,54 @@
+package caching
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/amirasaad/fintech/pkg/service/maintenance"
+	"github.com/redis/go-redis/v9"
+)
+
+// MaintenanceStore keeps the maintenance state in a Redis key shared by
+// every instance. Each Load reads the key, so a switch made on one instance
+// applies to the others at once, and a Redis failure is reported rather than
+// answered from a local copy. It implements maintenance.Store.
+type MaintenanceStore struct {
+	client *redis.Client
+	key    string
+}
+
+// NewMaintenanceStore creates a MaintenanceStore storing its key under
+// prefix.
+func NewMaintenanceStore(client *redis.Client, prefix string) *MaintenanceStore {
+	return &MaintenanceStore{client: client, key: prefix + "maintenance:state"}
+}
+
+// Load returns the stored state, or nil if it was never saved.
+func (s *MaintenanceStore) Load(ctx context.Context) (*maintenance.State, error) {
+	data, err := s.client.Get(ctx, s.key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read maintenance state: %w", err)
+	}
+	var state maintenance.State
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("invalid maintenance state: %w", err)
+	}
+	return &state, nil
+}
+
+// Save stores the state without expiry.
+func (s *MaintenanceStore) Save(ctx context.Context, state maintenance.State) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("failed to encode maintenance state: %w", err)
+	}
+	if err := s.client.Set(ctx, s.key, data, 0).Err(); err != nil {
+		return fmt.Errorf("failed to save maintenance state: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package caching
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/amirasaad/fintech/pkg/dto"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// DefaultTransactionCacheTTL is how long cached transactions are kept unless
+// configured otherwise.
+const DefaultTransactionCacheTTL = time.Minute
+
+// TransactionCache caches the transaction lists of accounts in Redis. Each
+// account has a version key, incremented by Invalidate, and a list key
+// holding the list with the version it was read at; a list whose version is
+// not the account's current one is a miss. It implements
+// account.TransactionCache.
+type TransactionCache struct {
+	client *redis.Client
+	prefix string
+	ttl    time.Duration
+}
+
+// transactionCacheEntry is a cached transaction list.
+type transactionCacheEntry struct {
+	Version      int64                  `json:"version"`
+	Transactions []*dto.TransactionRead `json:"transactions"`
+}
+
+// NewTransactionCache creates a TransactionCache storing keys under prefix
+// for ttl, or DefaultTransactionCacheTTL if ttl is not positive.
+func NewTransactionCache(client *redis.Client, prefix string, ttl time.Duration) *TransactionCache {
+	if ttl <= 0 {
+		ttl = DefaultTransactionCacheTTL
+	}
+	return &TransactionCache{client: client, prefix: prefix, ttl: ttl}
+}
+
+// Get returns the cached transactions of the account and its version.
+func (c *TransactionCache) Get(
+	ctx context.Context,
+	accountID uuid.UUID,
+) ([]*dto.TransactionRead, int64, bool, error) {
+	vals, err := c.client.MGet(ctx, c.versionKey(accountID), c.listKey(accountID)).Result()
+	if err != nil {
+		return nil, 0, false, fmt.Errorf("failed to read cached transactions: %w", err)
+	}
+	var version int64
+	if v, ok := vals[0].(string); ok {
+		if version, err = strconv.ParseInt(v, 10, 64); err != nil {
+			return nil, 0, false, fmt.Errorf("invalid transaction cache version %q: %w", v, err)
+		}
+	}
+	data, ok := vals[1].(string)
+	if !ok {
+		return nil, version, false, nil
+	}
+	var entry transactionCacheEntry
+	if err := json.Unmarshal([]byte(data), &entry); err != nil {
+		// Treat a corrupt entry as a miss; Set overwrites it.
+		return nil, version, false, nil
+	}
+	if entry.Version != version || entry.Transactions == nil {
+		return nil, version, false, nil
+	}
+	return entry.Transactions, version, true, nil
+}
+
+// Set caches the transactions of the account read at version.
+func (c *TransactionCache) Set(
+	ctx context.Context,
+	accountID uuid.UUID,
+	version int64,
+	txs []*dto.TransactionRead,
+) error {
+	if txs == nil {
+		txs = []*dto.TransactionRead{}
+	}
+	data, err := json.Marshal(transactionCacheEntry{Version: version, Transactions: txs})
+	if err != nil {
+		return fmt.Errorf("failed to encode transactions: %w", err)
+	}
+	return c.client.Set(ctx, c.listKey(accountID), data, c.ttl).Err()
+}
+
+// Invalidate moves each account to a new version and drops its cached list.
+// The version outlives any list cached under the previous one, so a list
+// read before the invalidation but stored after it is never served.
+func (c *TransactionCache) Invalidate(ctx context.Context, accountIDs ...uuid.UUID) error {
+	if len(accountIDs) == 0 {
+		return nil
+	}
+	_, err := c.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, id := range accountIDs {
+			pipe.Incr(ctx, c.versionKey(id))
+			pipe.Expire(ctx, c.versionKey(id), 2*c.ttl)
+			pipe.Del(ctx, c.listKey(id))
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return fmt.Errorf("failed to invalidate cached transactions: %w", err)
+	}
+	return nil
+}
+
+func (c *TransactionCache) versionKey(accountID uuid.UUID) string {
+	return c.prefix + "transactions:version:" + accountID.String()
+}
+
+func (c *TransactionCache) listKey(accountID uuid.UUID) string {
+	return c.prefix + "transactions:list:" + accountID.String()
+}
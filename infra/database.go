@@ -2,7 +2,7 @@ package infra
 
 import (
 	"errors"
-	"time"
+	"fmt"
 
 	"github.com/amirasaad/fintech/pkg/config"
 
@@ -11,7 +11,8 @@ import (
 	"gorm.io/gorm/logger"
 )
 
-// Add appEnv as a parameter for dependency-injected environment
+// NewDBConnection opens the primary database and, when cnf.ReplicaUrls is set,
+// the read replicas that opted-in reads are sent to (see UseReadReplicas).
 func NewDBConnection(
 	cnf *config.DB,
 	appEnv string,
@@ -20,7 +21,33 @@ func NewDBConnection(
 	if databaseUrl == "" {
 		return nil, errors.New("DATABASE_URL is not set")
 	}
+	if err := cnf.Validate(); err != nil {
+		return nil, err
+	}
+
+	connection, err := openDB(cnf, databaseUrl, appEnv)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(cnf.ReplicaUrls) > 0 {
+		replicas := make([]*gorm.DB, 0, len(cnf.ReplicaUrls))
+		for i, url := range cnf.ReplicaUrls {
+			replica, err := openDB(cnf, url, appEnv)
+			if err != nil {
+				return nil, fmt.Errorf("failed to connect to read replica %d: %w", i, err)
+			}
+			replicas = append(replicas, replica)
+		}
+		if err := UseReadReplicas(connection, replicas...); err != nil {
+			return nil, err
+		}
+	}
+
+	return connection, nil
+}
 
+func openDB(cnf *config.DB, databaseUrl, appEnv string) (*gorm.DB, error) {
 	var logMode logger.LogLevel
 	if appEnv == "development" {
 		logMode = logger.Info
@@ -45,9 +72,9 @@ func NewDBConnection(
 	if err != nil {
 		return nil, err
 	}
-	sqlDB.SetMaxOpenConns(25)
-	sqlDB.SetMaxIdleConns(25)
-	sqlDB.SetConnMaxLifetime(1 * time.Hour)
+	sqlDB.SetMaxOpenConns(cnf.MaxOpenConns)
+	sqlDB.SetMaxIdleConns(cnf.MaxIdleConns)
+	sqlDB.SetConnMaxLifetime(cnf.ConnMaxLifetime)
 
 	return connection, nil
 }
@@ -3,9 +3,108 @@
 
 package eventbus
 
-import "encoding/json"
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
 
 type envelope struct {
 	Type    string          `json:"type"`
 	Payload json.RawMessage `json:"payload"`
+	// Compressed marks Payload as a JSON string holding the base64-encoded,
+	// gzip-compressed event JSON.
+	Compressed bool `json:"compressed,omitempty"`
+	// TraceContext carries the W3C trace context of the emitting request, so
+	// the handlers continue its trace.
+	TraceContext map[string]string `json:"trace_context,omitempty"`
+}
+
+const tracerName = "github.com/amirasaad/fintech/infra/eventbus"
+
+// startPublishSpan starts the producer span of emitting an event.
+func startPublishSpan(ctx context.Context, eventType string) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, "publish "+eventType,
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(attribute.String("event.type", eventType)),
+	)
+}
+
+// injectTraceContext stores the trace context of ctx in the envelope.
+func (e *envelope) injectTraceContext(ctx context.Context) {
+	carrier := propagation.MapCarrier{}
+	otel.GetTextMapPropagator().Inject(ctx, carrier)
+	if len(carrier) > 0 {
+		e.TraceContext = carrier
+	}
+}
+
+// startProcessSpan starts the consumer span of handling the event in the
+// envelope, continuing the trace it was emitted in.
+func (e envelope) startProcessSpan(ctx context.Context) (context.Context, trace.Span) {
+	ctx = otel.GetTextMapPropagator().Extract(ctx, propagation.MapCarrier(e.TraceContext))
+	return otel.Tracer(tracerName).Start(ctx, "process "+e.Type,
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(attribute.String("event.type", e.Type)),
+	)
+}
+
+// newEnvelope wraps the marshaled event data. Data of at least threshold
+// bytes is gzip-compressed; a threshold of zero or less disables compression.
+// Compression is skipped when it would not make the envelope smaller.
+func newEnvelope(eventType string, data []byte, threshold int) (envelope, error) {
+	env := envelope{Type: eventType, Payload: data}
+	if threshold <= 0 || len(data) < threshold {
+		return env, nil
+	}
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(data); err != nil {
+		return envelope{}, fmt.Errorf("gzip payload: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return envelope{}, fmt.Errorf("gzip payload: %w", err)
+	}
+	// json.Marshal encodes []byte as a base64 string.
+	compressed, err := json.Marshal(buf.Bytes())
+	if err != nil {
+		return envelope{}, fmt.Errorf("encode compressed payload: %w", err)
+	}
+	if len(compressed) >= len(data) {
+		return env, nil
+	}
+	env.Payload = compressed
+	env.Compressed = true
+	return env, nil
+}
+
+// eventData returns the event JSON carried by the envelope, decompressing it
+// if needed.
+func (e envelope) eventData() ([]byte, error) {
+	if !e.Compressed {
+		return e.Payload, nil
+	}
+	var compressed []byte
+	if err := json.Unmarshal(e.Payload, &compressed); err != nil {
+		return nil, fmt.Errorf("decode compressed payload: %w", err)
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return nil, fmt.Errorf("gunzip payload: %w", err)
+	}
+	defer func() { _ = zr.Close() }()
+	data, err := io.ReadAll(zr)
+	if err != nil {
+		return nil, fmt.Errorf("gunzip payload: %w", err)
+	}
+	return data, nil
 }
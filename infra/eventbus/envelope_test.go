@@ -0,0 +1,89 @@
+//go:build redis || kafka
+// +build redis kafka
+
+package eventbus
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestEnvelopeCompression(t *testing.T) {
+	large, err := json.Marshal(map[string]string{"note": strings.Repeat("fintech ", 200)})
+	require.NoError(t, err)
+	small := []byte(`{"note":"hi"}`)
+
+	tests := []struct {
+		name           string
+		data           []byte
+		threshold      int
+		wantCompressed bool
+	}{
+		{"disabled", large, 0, false},
+		{"below threshold", small, 64, false},
+		{"above threshold", large, 64, true},
+		// Gzip overhead would make a tiny payload larger.
+		{"not worth compressing", small, 1, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env, err := newEnvelope("test.event", tt.data, tt.threshold)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantCompressed, env.Compressed)
+
+			// Round-trip through JSON as the envelope does through Redis and the DLQ.
+			raw, err := json.Marshal(env)
+			require.NoError(t, err)
+			if tt.wantCompressed {
+				assert.Less(t, len(raw), len(tt.data))
+			}
+			var decoded envelope
+			require.NoError(t, json.Unmarshal(raw, &decoded))
+			data, err := decoded.eventData()
+			require.NoError(t, err)
+			assert.JSONEq(t, string(tt.data), string(data))
+		})
+	}
+}
+
+func TestEnvelopeEventData_Corrupt(t *testing.T) {
+	env := envelope{Type: "test.event", Payload: json.RawMessage(`"bm90IGd6aXA="`), Compressed: true}
+	_, err := env.eventData()
+	require.Error(t, err)
+}
+
+func TestEnvelopeTraceContext(t *testing.T) {
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	parent := trace.ContextWithRemoteSpanContext(context.Background(),
+		trace.NewSpanContext(trace.SpanContextConfig{
+			TraceID:    trace.TraceID{0x4b, 0xf9},
+			SpanID:     trace.SpanID{0x01},
+			TraceFlags: trace.FlagsSampled,
+		}))
+
+	env, err := newEnvelope("test.event", []byte(`{}`), 0)
+	require.NoError(t, err)
+	env.injectTraceContext(parent)
+	require.NotEmpty(t, env.TraceContext)
+
+	raw, err := json.Marshal(env)
+	require.NoError(t, err)
+	var got envelope
+	require.NoError(t, json.Unmarshal(raw, &got))
+	ctx, span := got.startProcessSpan(context.Background())
+	defer span.End()
+	assert.Equal(t, trace.TraceID{0x4b, 0xf9}, trace.SpanContextFromContext(ctx).TraceID())
+
+	// Envelopes emitted outside a trace carry no trace context.
+	env.TraceContext = nil
+	env.injectTraceContext(context.Background())
+	assert.Nil(t, env.TraceContext)
+}
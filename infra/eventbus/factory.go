@@ -0,0 +1,262 @@
+package eventbus
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/amirasaad/fintech/pkg/config"
+	"github.com/amirasaad/fintech/pkg/domain/events"
+	"github.com/amirasaad/fintech/pkg/eventbus"
+)
+
+// Event bus drivers selectable with EVENT_BUS_DRIVER.
+const (
+	DriverMemory = "memory"
+	DriverRedis  = "redis"
+	DriverKafka  = "kafka"
+)
+
+// NewFromConfig returns the event bus selected by cfg.EventBus.Driver:
+// DriverMemory (the default when no driver is set), DriverRedis or
+// DriverKafka, configured from the matching connection settings. The Redis
+// driver falls back to cfg.Redis.URL when no event bus Redis URL is set.
+// Missing connection settings and unknown drivers are errors, but if Redis or
+// Kafka cannot be reached it logs a warning and returns an in-memory bus, so
+// the application still starts.
+func NewFromConfig(cfg *config.App, logger *slog.Logger) (eventbus.Bus, error) {
+	explicitDriver := ""
+	if cfg.EventBus != nil {
+		explicitDriver = strings.TrimSpace(cfg.EventBus.Driver)
+	}
+
+	if explicitDriver == "" {
+		return NewWithMemoryAsync(logger), nil
+	}
+
+	driver := strings.TrimSpace(strings.ToLower(explicitDriver))
+	switch driver {
+	case DriverMemory:
+		return NewWithMemoryAsync(logger), nil
+	case DriverRedis:
+		redisURL := ""
+		if cfg.EventBus != nil {
+			redisURL = strings.TrimSpace(cfg.EventBus.RedisURL)
+		}
+		if redisURL == "" && cfg.Redis != nil {
+			redisURL = strings.TrimSpace(cfg.Redis.URL)
+		}
+		if redisURL == "" {
+			return nil, fmt.Errorf("event bus redis: redis url is required")
+		}
+		busConfig := &RedisEventBusConfig{
+			DLQRetryInterval: 5 * time.Minute,
+			DLQBatchSize:     10,
+			HandlerTimeout:   30 * time.Second,
+		}
+		if cfg.EventBus != nil {
+			busConfig.Namespace = strings.TrimSpace(cfg.EventBus.RedisNamespace)
+			busConfig.CompressionThreshold = cfg.EventBus.CompressionThreshold
+			busConfig.DLQAlertThreshold = cfg.EventBus.DLQAlertThreshold
+			busConfig.HandlerTimeout = cfg.EventBus.HandlerTimeout
+			busConfig.UnknownEvents = UnknownEventPolicy(cfg.EventBus.UnknownEvents)
+			busConfig.Consumers = consumerConfigs(cfg.EventBus)
+			busConfig.AckPerMessage = cfg.EventBus.AckPerMessage
+			busConfig.IdleConsumerThreshold = cfg.EventBus.IdleConsumerThreshold
+			busConfig.CleanupConsumersWithoutPending = cfg.EventBus.CleanupConsumersWithoutPending
+		}
+		bus, err := NewWithRedis(redisURL, logger, busConfig)
+		if err != nil {
+			logger.Warn("Redis event bus init failed, falling back to memory async", "error", err)
+			return NewWithMemoryAsync(logger), nil
+		}
+		return bus, nil
+	case DriverKafka:
+		if cfg.EventBus == nil {
+			return nil, fmt.Errorf("event bus kafka: configuration is required")
+		}
+		brokers := strings.TrimSpace(cfg.EventBus.KafkaBrokers)
+		if brokers == "" {
+			return nil, fmt.Errorf("event bus kafka: brokers are required")
+		}
+		caFilePath, err := ensureKafkaCAFile(cfg, logger)
+		if err != nil {
+			return nil, fmt.Errorf("event bus kafka: prepare tls ca file: %w", err)
+		}
+		certFilePath, err := ensureKafkaTLSCertFile(cfg, logger)
+		if err != nil {
+			return nil, fmt.Errorf("event bus kafka: prepare tls cert file: %w", err)
+		}
+		keyFilePath, err := ensureKafkaTLSKeyFile(cfg, logger)
+		if err != nil {
+			return nil, fmt.Errorf("event bus kafka: prepare tls key file: %w", err)
+		}
+		tlsCertSet := strings.TrimSpace(certFilePath) != ""
+		tlsKeySet := strings.TrimSpace(keyFilePath) != ""
+		if cfg.EventBus.KafkaTLSEnabled && tlsCertSet != tlsKeySet {
+			return nil, fmt.Errorf("event bus kafka: tls cert and key must be provided together")
+		}
+		saslUsernameSet := strings.TrimSpace(cfg.EventBus.KafkaSASLUsername) != ""
+		saslPasswordSet := strings.TrimSpace(cfg.EventBus.KafkaSASLPassword) != ""
+		tlsCaProvided := strings.TrimSpace(caFilePath) != ""
+		tlsInputsProvided := tlsCaProvided ||
+			tlsCertSet ||
+			tlsKeySet ||
+			cfg.EventBus.KafkaTLSSkipVerify
+
+		brokerCount := 0
+		for _, broker := range strings.Split(brokers, ",") {
+			if strings.TrimSpace(broker) != "" {
+				brokerCount++
+			}
+		}
+
+		if !cfg.EventBus.KafkaTLSEnabled && tlsInputsProvided {
+			logger.Warn("Kafka TLS settings provided but TLS disabled",
+				"tls_enabled", cfg.EventBus.KafkaTLSEnabled,
+				"tls_ca_file", strings.TrimSpace(caFilePath),
+				"tls_cert_file_set", tlsCertSet,
+				"tls_key_file_set", tlsKeySet,
+				"tls_skip_verify", cfg.EventBus.KafkaTLSSkipVerify,
+			)
+		}
+		logger.Info("Initializing Kafka event bus",
+			"brokers", brokers,
+			"brokers_count", brokerCount,
+			"group_id", strings.TrimSpace(cfg.EventBus.KafkaGroupID),
+			"topic_prefix", strings.TrimSpace(cfg.EventBus.KafkaTopic),
+			"tls_enabled", cfg.EventBus.KafkaTLSEnabled,
+			"tls_ca_file", strings.TrimSpace(caFilePath),
+			"tls_cert_file_set", tlsCertSet,
+			"tls_key_file_set", tlsKeySet,
+			"tls_skip_verify", cfg.EventBus.KafkaTLSSkipVerify,
+			"sasl_username_set", saslUsernameSet,
+			"sasl_password_set", saslPasswordSet,
+		)
+		kafkaConfig := &KafkaEventBusConfig{
+			GroupID:          strings.TrimSpace(cfg.EventBus.KafkaGroupID),
+			TopicPrefix:      strings.TrimSpace(cfg.EventBus.KafkaTopic),
+			DLQRetryInterval: 5 * time.Minute,
+			DLQBatchSize:     10,
+			SASLUsername:     strings.TrimSpace(cfg.EventBus.KafkaSASLUsername),
+			SASLPassword:     strings.TrimSpace(cfg.EventBus.KafkaSASLPassword),
+			TLSEnabled:       cfg.EventBus.KafkaTLSEnabled,
+			TLSCAFile:        strings.TrimSpace(caFilePath),
+			TLSCertFile:      strings.TrimSpace(certFilePath),
+			TLSKeyFile:       strings.TrimSpace(keyFilePath),
+			TLSSkipVerify:    cfg.EventBus.KafkaTLSSkipVerify,
+		}
+		bus, err := NewWithKafka(brokers, logger, kafkaConfig)
+		if err != nil {
+			logger.Warn("Kafka event bus init failed, falling back to memory async", "error", err)
+			return NewWithMemoryAsync(logger), nil
+		}
+		return bus, nil
+	default:
+		return nil, fmt.Errorf("unsupported event bus driver: %s", driver)
+	}
+}
+
+func ensureKafkaTLSFile(
+	pem string,
+	tempPattern string,
+	pemLabel string,
+	fileLabel string,
+	logMessage string,
+	logger *slog.Logger,
+) (string, error) {
+	pem = strings.TrimSpace(pem)
+	if pem == "" {
+		return "", nil
+	}
+
+	pem = strings.ReplaceAll(pem, "\\n", "\n")
+	if strings.TrimSpace(pem) == "" {
+		return "", fmt.Errorf("%s is empty", pemLabel)
+	}
+
+	tmpfile, err := os.CreateTemp("", tempPattern)
+	if err != nil {
+		return "", fmt.Errorf("create temp %s file: %w", fileLabel, err)
+	}
+	if err := tmpfile.Close(); err != nil {
+		return "", fmt.Errorf("close temp %s file: %w", fileLabel, err)
+	}
+	path := tmpfile.Name()
+
+	if err := os.WriteFile(path, []byte(pem), 0600); err != nil {
+		return "", fmt.Errorf("write %s file: %w", fileLabel, err)
+	}
+	if logger != nil && logMessage != "" {
+		logger.Info(logMessage, "path", path)
+	}
+
+	return path, nil
+}
+
+func ensureKafkaCAFile(cfg *config.App, logger *slog.Logger) (string, error) {
+	if cfg == nil || cfg.EventBus == nil {
+		return "", nil
+	}
+
+	return ensureKafkaTLSFile(
+		cfg.EventBus.KafkaTLSCAPem,
+		"fintech-kafka-ca-*.pem",
+		"kafka ca pem",
+		"kafka ca",
+		"Kafka CA file written",
+		logger,
+	)
+}
+
+func ensureKafkaTLSCertFile(cfg *config.App, logger *slog.Logger) (string, error) {
+	if cfg == nil || cfg.EventBus == nil {
+		return "", nil
+	}
+
+	return ensureKafkaTLSFile(
+		cfg.EventBus.KafkaTLSCertPem,
+		"fintech-kafka-cert-*.pem",
+		"kafka tls cert pem",
+		"kafka tls cert",
+		"Kafka TLS cert file written",
+		logger,
+	)
+}
+
+func ensureKafkaTLSKeyFile(cfg *config.App, logger *slog.Logger) (string, error) {
+	if cfg == nil || cfg.EventBus == nil {
+		return "", nil
+	}
+
+	return ensureKafkaTLSFile(
+		cfg.EventBus.KafkaTLSKeyPem,
+		"fintech-kafka-key-*.pem",
+		"kafka tls key pem",
+		"kafka tls key",
+		"Kafka TLS key file written",
+		logger,
+	)
+}
+
+// consumerConfigs merges the per-event-type consumer concurrency and batch
+// sizes of cfg.
+func consumerConfigs(cfg *config.EventBus) map[events.EventType]ConsumerConfig {
+	if len(cfg.ConsumerConcurrency) == 0 && len(cfg.ConsumerBatchSize) == 0 {
+		return nil
+	}
+	consumers := make(map[events.EventType]ConsumerConfig)
+	for eventType, n := range cfg.ConsumerConcurrency {
+		c := consumers[events.EventType(eventType)]
+		c.Concurrency = n
+		consumers[events.EventType(eventType)] = c
+	}
+	for eventType, n := range cfg.ConsumerBatchSize {
+		c := consumers[events.EventType(eventType)]
+		c.BatchSize = n
+		consumers[events.EventType(eventType)] = c
+	}
+	return consumers
+}
@@ -0,0 +1,102 @@
+package eventbus
+
+import (
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/amirasaad/fintech/pkg/config"
+	"github.com/amirasaad/fintech/pkg/domain/events"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewFromConfig_DefaultsToMemoryAsyncWhenNoExplicitDriver(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	cfg := &config.App{
+		Redis:    &config.Redis{URL: "redis://localhost:6379/0"},
+		EventBus: &config.EventBus{Driver: ""},
+	}
+
+	bus, err := NewFromConfig(cfg, logger)
+	require.NoError(t, err)
+	require.IsType(t, &MemoryAsyncEventBus{}, bus)
+}
+
+func TestNewFromConfig_ExplicitRedisRequiresURL(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	cfg := &config.App{
+		Redis:    &config.Redis{URL: ""},
+		EventBus: &config.EventBus{Driver: "redis", RedisURL: ""},
+	}
+
+	_, err := NewFromConfig(cfg, logger)
+	require.Error(t, err)
+}
+
+func TestNewFromConfig_RedisConnectionErrorFallsBackToMemoryAsync(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	cfg := &config.App{
+		EventBus: &config.EventBus{Driver: "redis", RedisURL: "redis://127.0.0.1:1"},
+	}
+
+	bus, err := NewFromConfig(cfg, logger)
+	require.NoError(t, err)
+	require.IsType(t, &MemoryAsyncEventBus{}, bus)
+}
+
+func TestNewFromConfig_ExplicitKafkaRequiresBrokers(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	cfg := &config.App{
+		EventBus: &config.EventBus{Driver: "kafka", KafkaBrokers: ""},
+	}
+
+	_, err := NewFromConfig(cfg, logger)
+	require.Error(t, err)
+}
+
+func TestNewFromConfig_KafkaConnectionErrorFallsBackToMemoryAsync(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	cfg := &config.App{
+		EventBus: &config.EventBus{Driver: "kafka", KafkaBrokers: "127.0.0.1:1"},
+	}
+
+	bus, err := NewFromConfig(cfg, logger)
+	require.NoError(t, err)
+	require.IsType(t, &MemoryAsyncEventBus{}, bus)
+}
+
+func TestNewFromConfig_UnsupportedDriverErrors(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	cfg := &config.App{
+		EventBus: &config.EventBus{Driver: "nope"},
+	}
+
+	_, err := NewFromConfig(cfg, logger)
+	require.Error(t, err)
+}
+
+func TestNewFromConfig_DriverIsCaseInsensitive(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	cfg := &config.App{
+		EventBus: &config.EventBus{Driver: " Memory "},
+	}
+
+	bus, err := NewFromConfig(cfg, logger)
+	require.NoError(t, err)
+	require.IsType(t, &MemoryAsyncEventBus{}, bus)
+}
+
+func TestConsumerConfigs(t *testing.T) {
+	require.Nil(t, consumerConfigs(&config.EventBus{}))
+	require.Equal(t, map[events.EventType]ConsumerConfig{
+		"Payment.Completed": {Concurrency: 4, BatchSize: 50},
+		"Payment.Failed":    {Concurrency: 2},
+		"Deposit.Requested": {BatchSize: 20},
+	}, consumerConfigs(&config.EventBus{
+		ConsumerConcurrency: map[string]int{"Payment.Completed": 4, "Payment.Failed": 2},
+		ConsumerBatchSize: map[string]int64{
+			"Payment.Completed": 50,
+			"Deposit.Requested": 20,
+		},
+	}))
+}
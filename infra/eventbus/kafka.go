@@ -20,6 +20,7 @@ import (
 	"github.com/segmentio/kafka-go"
 	"github.com/segmentio/kafka-go/sasl"
 	"github.com/segmentio/kafka-go/sasl/plain"
+	"go.opentelemetry.io/otel/codes"
 )
 
 // KafkaEventBusConfig holds configuration for the Kafka event bus.
@@ -54,7 +55,7 @@ type KafkaEventBus struct {
 	dialer  *kafka.Dialer
 	ctx     context.Context
 
-	handlers    map[events.EventType][]eventbus.HandlerFunc
+	handlers    map[events.EventType]handlerSet
 	handlersMtx sync.RWMutex
 
 	readers    map[events.EventType]*kafka.Reader
@@ -123,7 +124,7 @@ func NewWithKafka(
 		writer:   writer,
 		dialer:   dialer,
 		ctx:      ctx,
-		handlers: make(map[events.EventType][]eventbus.HandlerFunc),
+		handlers: make(map[events.EventType]handlerSet),
 		readers:  make(map[events.EventType]*kafka.Reader),
 		topics:   make(map[string]struct{}),
 		logger:   logger.With("bus", "kafka"),
@@ -173,10 +174,19 @@ func (b *KafkaEventBus) Close() error {
 	return nil
 }
 
-// Register registers an event handler for a specific event type.
+// Register registers a critical event handler for a specific event type.
 func (b *KafkaEventBus) Register(eventType events.EventType, handler eventbus.HandlerFunc) {
+	b.RegisterWithPhase(eventType, handler, eventbus.PhaseCritical)
+}
+
+// RegisterWithPhase registers an event handler for a specific event type in phase.
+func (b *KafkaEventBus) RegisterWithPhase(
+	eventType events.EventType,
+	handler eventbus.HandlerFunc,
+	phase eventbus.Phase,
+) {
 	b.handlersMtx.Lock()
-	b.handlers[eventType] = append(b.handlers[eventType], handler)
+	b.handlers[eventType] = b.handlers[eventType].add(handler, phase)
 	b.handlersMtx.Unlock()
 
 	b.ensureConsumer(eventType)
@@ -188,14 +198,18 @@ func (b *KafkaEventBus) Emit(ctx context.Context, event events.Event) error {
 		return fmt.Errorf("kafka event bus: writer not initialized")
 	}
 
-	envBytes, err := b.buildEnvelope(event)
+	ctx, span := startPublishSpan(ctx, event.Type())
+	defer span.End()
+	envBytes, err := b.buildEnvelope(ctx, event)
 	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
 		return err
 	}
 
 	eventType := events.EventType(event.Type())
 	topic := topicNameFor(b.config.TopicPrefix, eventType)
 	if err := b.ensureTopic(ctx, topic); err != nil {
+		span.SetStatus(codes.Error, err.Error())
 		return err
 	}
 
@@ -207,6 +221,7 @@ func (b *KafkaEventBus) Emit(ctx context.Context, event events.Event) error {
 	}
 
 	if err := b.writer.WriteMessages(ctx, msg); err != nil {
+		span.SetStatus(codes.Error, err.Error())
 		return fmt.Errorf("kafka event bus: publish failed: %w", err)
 	}
 	return nil
@@ -310,12 +325,21 @@ func (b *KafkaEventBus) processKafkaMessage(
 	}
 
 	handlers := b.getHandlers(evtType)
-	if len(handlers) == 0 {
+	if handlers.len() == 0 {
 		b.logger.Warn("no handlers registered for event type", "event_type", evtType, "topic", msg.Topic, "offset", msg.Offset)
 		return true, nil
 	}
 
-	success := executeHandlers(ctx, b.logger, evtType, evt, handlers, fmt.Sprintf("%d", msg.Offset))
+	msgID := fmt.Sprintf("%d", msg.Offset)
+	// The handlers continue the trace the event was emitted in.
+	spanCtx, span := env.startProcessSpan(ctx)
+	success := runPhases(handlers, func(phase []eventbus.HandlerFunc) bool {
+		return executeHandlers(spanCtx, b.logger, evtType, evt, phase, msgID)
+	}, b.logger, evtType)
+	if !success {
+		span.SetStatus(codes.Error, "event handler failed")
+	}
+	span.End()
 	if success {
 		return true, nil
 	}
@@ -345,12 +369,13 @@ func (b *KafkaEventBus) publishToDLQ(ctx context.Context, eventType events.Event
 	return nil
 }
 
-func (b *KafkaEventBus) buildEnvelope(event events.Event) ([]byte, error) {
+func (b *KafkaEventBus) buildEnvelope(ctx context.Context, event events.Event) ([]byte, error) {
 	data, err := json.Marshal(event)
 	if err != nil {
 		return nil, fmt.Errorf("kafka event bus: marshal failed: %w", err)
 	}
 	env := envelope{Type: event.Type(), Payload: data}
+	env.injectTraceContext(ctx)
 	envBytes, err := json.Marshal(env)
 	if err != nil {
 		return nil, fmt.Errorf("kafka event bus: envelope marshal failed: %w", err)
@@ -499,13 +524,10 @@ func isTopicAlreadyExists(err error) bool {
 		strings.Contains(msg, "TopicAlreadyExists")
 }
 
-func (b *KafkaEventBus) getHandlers(eventType events.EventType) []eventbus.HandlerFunc {
+func (b *KafkaEventBus) getHandlers(eventType events.EventType) handlerSet {
 	b.handlersMtx.RLock()
 	defer b.handlersMtx.RUnlock()
-	handlers := b.handlers[eventType]
-	out := make([]eventbus.HandlerFunc, len(handlers))
-	copy(out, handlers)
-	return out
+	return b.handlers[eventType].clone()
 }
 
 func (b *KafkaEventBus) startDLQRetryWorker(ctx context.Context) {
@@ -689,4 +711,4 @@ func executeHandlers(
 	return success
 }
 
-var _ eventbus.Bus = (*KafkaEventBus)(nil)
+var _ eventbus.PhasedBus = (*KafkaEventBus)(nil)
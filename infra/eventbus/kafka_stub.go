@@ -40,8 +40,15 @@ func NewWithKafka(
 func (b *KafkaEventBus) Register(eventType events.EventType, handler eventbus.HandlerFunc) {
 }
 
+func (b *KafkaEventBus) RegisterWithPhase(
+	eventType events.EventType,
+	handler eventbus.HandlerFunc,
+	phase eventbus.Phase,
+) {
+}
+
 func (b *KafkaEventBus) Emit(ctx context.Context, event events.Event) error {
 	return fmt.Errorf("kafka event bus: build with -tags kafka to enable")
 }
 
-var _ eventbus.Bus = (*KafkaEventBus)(nil)
+var _ eventbus.PhasedBus = (*KafkaEventBus)(nil)
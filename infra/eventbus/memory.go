@@ -5,6 +5,7 @@ import (
 	"github.com/amirasaad/fintech/pkg/domain/events"
 	"log/slog"
 	"sync"
+	"sync/atomic"
 
 	"github.com/amirasaad/fintech/pkg/eventbus"
 )
@@ -14,7 +15,7 @@ const MaxEventDepth = 10
 
 // MemoryEventBus is a simple in-memory implementation of the EventBus interface.
 type MemoryEventBus struct {
-	handlers  map[events.EventType][]eventbus.HandlerFunc
+	handlers  map[events.EventType]handlerSet
 	mu        sync.RWMutex
 	logger    *slog.Logger
 	published []events.Event // Added for testing purposes
@@ -24,27 +25,39 @@ type MemoryEventBus struct {
 // communication.
 func NewWithMemory(logger *slog.Logger) *MemoryEventBus {
 	return &MemoryEventBus{
-		handlers:  make(map[events.EventType][]eventbus.HandlerFunc),
+		handlers:  make(map[events.EventType]handlerSet),
 		logger:    logger.With("bus", "memory"),
 		published: make([]events.Event, 0), // Initialize the slice
 	}
 }
 
-// Register registers a handler for a specific event type.
+// Register registers a critical handler for a specific event type.
 func (b *MemoryEventBus) Register(
 	eventType events.EventType,
 	handler eventbus.HandlerFunc,
+) {
+	b.RegisterWithPhase(eventType, handler, eventbus.PhaseCritical)
+}
+
+// RegisterWithPhase registers a handler for a specific event type in phase.
+func (b *MemoryEventBus) RegisterWithPhase(
+	eventType events.EventType,
+	handler eventbus.HandlerFunc,
+	phase eventbus.Phase,
 ) {
 	b.mu.Lock()
 	defer b.mu.Unlock()
-	b.handlers[eventType] = append(b.handlers[eventType], handler)
+	b.handlers[eventType] = b.handlers[eventType].add(handler, phase)
 }
 
-// Emit dispatches the event to all registered handlers for its type.
+// Emit dispatches the event to the registered handlers for its type, one at a
+// time in registration order, critical handlers first. Best-effort handlers
+// are skipped if a critical handler fails. Handler errors are logged, not
+// returned.
 func (b *MemoryEventBus) Emit(ctx context.Context, event events.Event) error {
 	eventType := events.EventType(event.Type())
 	b.mu.RLock()
-	handlers := b.handlers[eventType]
+	handlers := b.handlers[eventType].clone()
 	b.mu.RUnlock()
 
 	// Store the published event for testing
@@ -52,9 +65,16 @@ func (b *MemoryEventBus) Emit(ctx context.Context, event events.Event) error {
 	b.published = append(b.published, event)
 	b.mu.Unlock()
 
-	for _, handler := range handlers {
-		handler(ctx, event) //nolint:errcheck
-	}
+	runPhases(handlers, func(phase []eventbus.HandlerFunc) bool {
+		success := true
+		for _, handler := range phase {
+			if err := handler(ctx, event); err != nil {
+				b.logger.Error("error handling event", "error", err, "event_type", eventType)
+				success = false
+			}
+		}
+		return success
+	}, b.logger, eventType)
 	return nil
 }
 
@@ -74,11 +94,11 @@ func (b *MemoryEventBus) Published() []events.Event {
 }
 
 // Ensure MemoryEventBus implements the EventBus interface.
-var _ eventbus.Bus = (*MemoryEventBus)(nil)
+var _ eventbus.PhasedBus = (*MemoryEventBus)(nil)
 
 // MemoryAsyncEventBus is a registry-based in-memory event bus implementation.
 type MemoryAsyncEventBus struct {
-	handlers map[events.EventType][]eventbus.HandlerFunc
+	handlers map[events.EventType]handlerSet
 	mu       sync.RWMutex
 	eventCh  chan struct {
 		ctx   context.Context
@@ -90,7 +110,7 @@ type MemoryAsyncEventBus struct {
 // NewWithMemoryAsync creates a new registry-based in-memory event bus.
 func NewWithMemoryAsync(logger *slog.Logger) *MemoryAsyncEventBus {
 	b := &MemoryAsyncEventBus{
-		handlers: make(map[events.EventType][]eventbus.HandlerFunc),
+		handlers: make(map[events.EventType]handlerSet),
 		eventCh: make(chan struct {
 			ctx   context.Context
 			event events.Event
@@ -101,13 +121,23 @@ func NewWithMemoryAsync(logger *slog.Logger) *MemoryAsyncEventBus {
 	return b
 }
 
+// Register registers a critical handler for a specific event type.
 func (b *MemoryAsyncEventBus) Register(
 	eventType events.EventType,
 	handler eventbus.HandlerFunc,
+) {
+	b.RegisterWithPhase(eventType, handler, eventbus.PhaseCritical)
+}
+
+// RegisterWithPhase registers a handler for a specific event type in phase.
+func (b *MemoryAsyncEventBus) RegisterWithPhase(
+	eventType events.EventType,
+	handler eventbus.HandlerFunc,
+	phase eventbus.Phase,
 ) {
 	b.mu.Lock()
 	defer b.mu.Unlock()
-	b.handlers[eventType] = append(b.handlers[eventType], handler)
+	b.handlers[eventType] = b.handlers[eventType].add(handler, phase)
 }
 
 func (b *MemoryAsyncEventBus) Emit(
@@ -124,31 +154,47 @@ func (b *MemoryAsyncEventBus) Emit(
 // getHandlers returns a copy of the handlers for the given event type.
 func (b *MemoryAsyncEventBus) getHandlers(
 	eventType events.EventType,
-) []eventbus.HandlerFunc {
+) handlerSet {
 	b.mu.RLock()
 	defer b.mu.RUnlock()
-	handlers := make([]eventbus.HandlerFunc, len(b.handlers[eventType]))
-	copy(handlers, b.handlers[eventType])
-	return handlers
+	return b.handlers[eventType].clone()
 }
 
+// process dispatches each event in the background. The handlers of a phase
+// run concurrently; best-effort handlers start once every critical handler
+// has succeeded.
 func (b *MemoryAsyncEventBus) process() {
 	for item := range b.eventCh {
 		eventType := events.EventType(item.event.Type())
 		handlers := b.getHandlers(eventType)
-		for _, handler := range handlers {
-			go func(
-				h eventbus.HandlerFunc,
-				ctx context.Context,
-				evt events.Event,
-			) {
-				if err := h(ctx, evt); err != nil {
-					b.log.Error("error handling event", "error", err, "event_type", eventType)
-				}
-			}(handler, item.ctx, item.event)
-		}
+		go runPhases(handlers, func(phase []eventbus.HandlerFunc) bool {
+			return b.runConcurrently(item.ctx, eventType, item.event, phase)
+		}, b.log, eventType)
+	}
+}
+
+// runConcurrently runs handlers concurrently and reports whether all succeeded.
+func (b *MemoryAsyncEventBus) runConcurrently(
+	ctx context.Context,
+	eventType events.EventType,
+	evt events.Event,
+	handlers []eventbus.HandlerFunc,
+) bool {
+	var wg sync.WaitGroup
+	var failed atomic.Bool
+	for _, handler := range handlers {
+		wg.Add(1)
+		go func(h eventbus.HandlerFunc) {
+			defer wg.Done()
+			if err := h(ctx, evt); err != nil {
+				b.log.Error("error handling event", "error", err, "event_type", eventType)
+				failed.Store(true)
+			}
+		}(handler)
 	}
+	wg.Wait()
+	return !failed.Load()
 }
 
 // Ensure MemoryRegistryEventBus implements the Bus interface.
-var _ eventbus.Bus = (*MemoryAsyncEventBus)(nil)
+var _ eventbus.PhasedBus = (*MemoryAsyncEventBus)(nil)
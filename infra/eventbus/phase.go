@@ -0,0 +1,66 @@
+package eventbus
+
+import (
+	"log/slog"
+
+	"github.com/amirasaad/fintech/pkg/domain/events"
+	"github.com/amirasaad/fintech/pkg/eventbus"
+)
+
+// handlerSet holds the handlers registered for one event type, by phase, in
+// registration order.
+type handlerSet struct {
+	critical   []eventbus.HandlerFunc
+	bestEffort []eventbus.HandlerFunc
+}
+
+// add returns the set with handler appended to phase.
+func (s handlerSet) add(handler eventbus.HandlerFunc, phase eventbus.Phase) handlerSet {
+	if phase == eventbus.PhaseBestEffort {
+		s.bestEffort = append(s.bestEffort, handler)
+	} else {
+		s.critical = append(s.critical, handler)
+	}
+	return s
+}
+
+// clone returns a copy of the set that is safe to use without holding a lock.
+func (s handlerSet) clone() handlerSet {
+	return handlerSet{
+		critical:   append([]eventbus.HandlerFunc(nil), s.critical...),
+		bestEffort: append([]eventbus.HandlerFunc(nil), s.bestEffort...),
+	}
+}
+
+// len returns the number of handlers in every phase.
+func (s handlerSet) len() int {
+	return len(s.critical) + len(s.bestEffort)
+}
+
+// runPhases runs the critical handlers and then, only if run reports that all
+// of them succeeded, the best-effort handlers. It reports whether the critical
+// handlers succeeded; best-effort failures do not change the result.
+func runPhases(
+	s handlerSet,
+	run func(handlers []eventbus.HandlerFunc) bool,
+	logger *slog.Logger,
+	eventType events.EventType,
+) bool {
+	if !run(s.critical) {
+		if len(s.bestEffort) > 0 {
+			logger.Warn(
+				"skipping best-effort handlers after critical handler failure",
+				"event_type", eventType,
+				"skipped", len(s.bestEffort),
+			)
+		}
+		return false
+	}
+	if len(s.bestEffort) > 0 && !run(s.bestEffort) {
+		logger.Warn(
+			"best-effort handler failed; event still handled",
+			"event_type", eventType,
+		)
+	}
+	return true
+}
@@ -0,0 +1,135 @@
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/amirasaad/fintech/pkg/domain/events"
+	"github.com/amirasaad/fintech/pkg/eventbus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type phaseTestEvent struct{}
+
+func (e *phaseTestEvent) Type() string { return "test.phase" }
+
+// recorder records the order handlers ran in.
+type recorder struct {
+	mu    sync.Mutex
+	calls []string
+	done  chan struct{}
+}
+
+func (r *recorder) handler(name string, err error) eventbus.HandlerFunc {
+	return func(ctx context.Context, e events.Event) error {
+		r.mu.Lock()
+		r.calls = append(r.calls, name)
+		r.mu.Unlock()
+		if r.done != nil {
+			r.done <- struct{}{}
+		}
+		return err
+	}
+}
+
+func (r *recorder) recorded() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.calls...)
+}
+
+func TestMemoryBusPhases(t *testing.T) {
+	t.Run("critical handlers run first", func(t *testing.T) {
+		bus := NewWithMemory(slog.Default())
+		rec := &recorder{}
+		eventbus.RegisterWithPhase(bus, "test.phase", rec.handler("notify", nil),
+			eventbus.PhaseBestEffort)
+		bus.Register("test.phase", rec.handler("balance", nil))
+
+		require.NoError(t, bus.Emit(context.Background(), &phaseTestEvent{}))
+		assert.Equal(t, []string{"balance", "notify"}, rec.recorded())
+	})
+
+	t.Run("critical failure skips best-effort handlers", func(t *testing.T) {
+		bus := NewWithMemory(slog.Default())
+		rec := &recorder{}
+		bus.RegisterWithPhase("test.phase", rec.handler("notify", nil), eventbus.PhaseBestEffort)
+		bus.Register("test.phase", rec.handler("balance", errors.New("boom")))
+		bus.Register("test.phase", rec.handler("ledger", nil))
+
+		require.NoError(t, bus.Emit(context.Background(), &phaseTestEvent{}))
+		assert.Equal(t, []string{"balance", "ledger"}, rec.recorded())
+	})
+
+	t.Run("best-effort failure does not stop other best-effort handlers", func(t *testing.T) {
+		bus := NewWithMemory(slog.Default())
+		rec := &recorder{}
+		bus.RegisterWithPhase("test.phase", rec.handler("email", errors.New("smtp down")),
+			eventbus.PhaseBestEffort)
+		bus.RegisterWithPhase("test.phase", rec.handler("push", nil), eventbus.PhaseBestEffort)
+
+		require.NoError(t, bus.Emit(context.Background(), &phaseTestEvent{}))
+		assert.Equal(t, []string{"email", "push"}, rec.recorded())
+	})
+}
+
+func TestMemoryAsyncBusPhases(t *testing.T) {
+	wait := func(t *testing.T, rec *recorder, n int) {
+		t.Helper()
+		for range n {
+			select {
+			case <-rec.done:
+			case <-time.After(time.Second):
+				t.Fatal("handler did not run")
+			}
+		}
+	}
+
+	bus := NewWithMemoryAsync(slog.Default())
+	rec := &recorder{done: make(chan struct{}, 4)}
+	bus.RegisterWithPhase("test.phase", rec.handler("notify", nil), eventbus.PhaseBestEffort)
+	bus.Register("test.phase", rec.handler("balance", nil))
+	require.NoError(t, bus.Emit(context.Background(), &phaseTestEvent{}))
+	wait(t, rec, 2)
+	assert.Equal(t, []string{"balance", "notify"}, rec.recorded())
+
+	bus = NewWithMemoryAsync(slog.Default())
+	rec = &recorder{done: make(chan struct{}, 4)}
+	bus.RegisterWithPhase("test.phase", rec.handler("notify", nil), eventbus.PhaseBestEffort)
+	bus.Register("test.phase", rec.handler("balance", errors.New("boom")))
+	require.NoError(t, bus.Emit(context.Background(), &phaseTestEvent{}))
+	wait(t, rec, 1)
+	select {
+	case <-rec.done:
+		t.Fatal("best-effort handler ran after a critical failure")
+	case <-time.After(50 * time.Millisecond):
+	}
+	assert.Equal(t, []string{"balance"}, rec.recorded())
+}
+
+func TestRunPhasesResult(t *testing.T) {
+	ok := func(context.Context, events.Event) error { return nil }
+	fail := func(context.Context, events.Event) error { return errors.New("boom") }
+	run := func(handlers []eventbus.HandlerFunc) bool {
+		for _, h := range handlers {
+			if h(context.Background(), &phaseTestEvent{}) != nil {
+				return false
+			}
+		}
+		return true
+	}
+
+	var set handlerSet
+	set = set.add(ok, eventbus.PhaseCritical).add(fail, eventbus.PhaseBestEffort)
+	assert.True(t, runPhases(set, run, slog.Default(), "test.phase"),
+		"best-effort failures do not fail the event")
+
+	set = set.add(fail, eventbus.PhaseCritical)
+	assert.False(t, runPhases(set, run, slog.Default(), "test.phase"))
+	assert.Equal(t, 3, set.len())
+}
@@ -9,6 +9,8 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"maps"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -16,11 +18,20 @@ import (
 	"github.com/amirasaad/fintech/pkg/domain/events"
 	"github.com/amirasaad/fintech/pkg/eventbus"
 	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel/codes"
 )
 
+// ErrHandlerTimeout is returned for a handler that did not finish within its
+// timeout. The event is routed to the DLQ like any other handler failure.
+var ErrHandlerTimeout = errors.New("event handler timed out")
+
 // RedisEventBus implements a production-ready event bus using Redis Streams.
 // RedisEventBusConfig holds configuration for the Redis event bus
 type RedisEventBusConfig struct {
+	// Namespace prefixes the names of all streams, consumer groups and
+	// consumers, e.g. "staging" gives "staging:events:payment:completed", so
+	// several environments can share one Redis. Empty means no prefix.
+	Namespace string
 	// DLQRetryInterval specifies how often to retry DLQ messages
 	DLQRetryInterval time.Duration
 	// DLQBatchSize specifies how many messages to process in each batch
@@ -31,6 +42,62 @@ type RedisEventBusConfig struct {
 	DLQInitialBackoff time.Duration
 	// DLQMaxBackoff specifies the maximum backoff duration
 	DLQMaxBackoff time.Duration
+	// HandlerTimeout bounds how long each handler may run. Zero means no
+	// timeout. RegisterWithTimeout overrides it per handler.
+	HandlerTimeout time.Duration
+	// CompressionThreshold is the event size in bytes from which envelope
+	// payloads are gzip-compressed. Zero disables compression.
+	CompressionThreshold int
+	// DLQAlertThreshold alerts when a DLQ holds more than this many messages.
+	// Zero disables alerting.
+	DLQAlertThreshold int64
+	// OnDLQThresholdExceeded, if set, is called with each alert in addition to
+	// the DLQThresholdExceeded event the bus emits.
+	OnDLQThresholdExceeded func(ctx context.Context, alert *events.DLQThresholdExceeded)
+	// UnknownEvents is what to do with messages of event types this
+	// instance does not know: UnknownEventsPark (the default when empty) or
+	// UnknownEventsDrop.
+	UnknownEvents UnknownEventPolicy
+	// Consumers overrides the consumer settings of individual event types,
+	// so high-volume types can be scaled independently. Types without an
+	// entry get one consumer reading batches of DefaultConsumerBatchSize.
+	Consumers map[events.EventType]ConsumerConfig
+	// AckPerMessage acknowledges each message as soon as it is handled.
+	// By default the messages of a batch read are acknowledged together
+	// with one XACK once the batch is handled, saving a round trip per
+	// message; after a crash the whole unacknowledged batch is handled
+	// again. Handlers with side effects run once per event either way,
+	// through common.WithProcessedEvents or common.ProcessOnce; the others
+	// must be idempotent.
+	AckPerMessage bool
+	// IdleConsumerThreshold is how long a consumer of a group must have been
+	// idle before it is removed when consuming starts. Zero means
+	// DefaultIdleConsumerThreshold.
+	IdleConsumerThreshold time.Duration
+	// CleanupConsumersWithoutPending also removes idle consumers without
+	// pending messages. By default only idle consumers with pending
+	// messages are removed.
+	CleanupConsumersWithoutPending bool
+}
+
+// DefaultIdleConsumerThreshold is how long a consumer must have been idle
+// before it is removed unless configured otherwise.
+const DefaultIdleConsumerThreshold = 5 * time.Minute
+
+// DefaultConsumerBatchSize is how many messages a consumer reads at a time
+// unless configured otherwise.
+const DefaultConsumerBatchSize int64 = 10
+
+// ConsumerConfig configures how an event type's stream is consumed.
+type ConsumerConfig struct {
+	// Concurrency is the number of consumer goroutines reading the stream,
+	// each as its own member of the consumer group. Above one, events of the
+	// type are no longer handled in the order they were emitted. Zero means
+	// one.
+	Concurrency int
+	// BatchSize is how many messages each consumer reads at a time. Zero
+	// means DefaultConsumerBatchSize.
+	BatchSize int64
 }
 
 // DefaultRedisEventBusConfig returns the default configuration for RedisEventBus
@@ -41,12 +108,15 @@ func DefaultRedisEventBusConfig() *RedisEventBusConfig {
 		DLQMaxRetries:     5,                // Maximum 5 retries per message
 		DLQInitialBackoff: 1 * time.Minute,  // Start with 1 minute backoff
 		DLQMaxBackoff:     30 * time.Minute, // Cap at 30 minutes
+		HandlerTimeout:    30 * time.Second, // Fail handlers running longer
+
+		IdleConsumerThreshold: DefaultIdleConsumerThreshold,
 	}
 }
 
 type RedisEventBus struct {
 	client      *redis.Client
-	handlers    map[events.EventType][]eventbus.HandlerFunc
+	handlers    map[events.EventType]handlerSet
 	handlersMtx sync.RWMutex
 	dlqMtx      sync.Mutex // Protects DLQ-related fields
 	logger      *slog.Logger
@@ -55,6 +125,21 @@ type RedisEventBus struct {
 	wg          sync.WaitGroup
 	dlqStopChan chan struct{}
 	dlqStopped  chan struct{}
+	// dlqAlerted records which DLQs are currently above the alert threshold.
+	dlqAlerted  map[events.EventType]bool
+	dlqAlertMtx sync.Mutex
+	// consumers holds the consumer of each event type with handlers; paused
+	// records the types whose consumer is stopped. consumersMtx is held for
+	// the whole of a pause or resume.
+	consumers    map[events.EventType]*streamConsumer
+	paused       map[events.EventType]bool
+	consumersMtx sync.Mutex
+}
+
+// streamConsumer is the goroutines reading one event type's stream.
+type streamConsumer struct {
+	stop chan struct{} // closed to stop the consumer
+	done chan struct{} // closed once all its goroutines have stopped
 }
 
 // NewWithRedis creates a new Redis-backed event bus.
@@ -118,13 +203,17 @@ func (b *RedisEventBus) Emit(
 	if err := b.validateClient(); err != nil {
 		return err
 	}
-	envBytes, err := b.buildEnvelope(event)
+	ctx, span := startPublishSpan(ctx, event.Type())
+	defer span.End()
+	envBytes, err := b.buildEnvelope(ctx, event)
 	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
 		return err
 	}
 	// Convert the event type string to EventType
 	eventType := events.EventType(event.Type())
 	if err := b.publishEnvelope(ctx, eventType, envBytes); err != nil {
+		span.SetStatus(codes.Error, err.Error())
 		return err
 	}
 	b.logger.Debug(
@@ -134,18 +223,51 @@ func (b *RedisEventBus) Emit(
 	return nil
 }
 
-// Register registers an event handler for a specific event type.
+// Register registers a critical event handler for a specific event type. The
+// handler runs with the configured HandlerTimeout.
 func (b *RedisEventBus) Register(
 	eventType events.EventType,
 	handler eventbus.HandlerFunc,
+) {
+	b.RegisterWithTimeout(eventType, handler, b.config.HandlerTimeout)
+}
+
+// RegisterWithTimeout registers a critical event handler that must finish
+// within timeout, overriding the configured HandlerTimeout. A zero timeout
+// disables the timeout for this handler.
+func (b *RedisEventBus) RegisterWithTimeout(
+	eventType events.EventType,
+	handler eventbus.HandlerFunc,
+	timeout time.Duration,
+) {
+	b.register(eventType, handler, eventbus.PhaseCritical, timeout)
+}
+
+// RegisterWithPhase registers an event handler for a specific event type in
+// phase. The handler runs with the configured HandlerTimeout.
+func (b *RedisEventBus) RegisterWithPhase(
+	eventType events.EventType,
+	handler eventbus.HandlerFunc,
+	phase eventbus.Phase,
+) {
+	b.register(eventType, handler, phase, b.config.HandlerTimeout)
+}
+
+// register adds handler in phase and starts consuming eventType.
+func (b *RedisEventBus) register(
+	eventType events.EventType,
+	handler eventbus.HandlerFunc,
+	phase eventbus.Phase,
+	timeout time.Duration,
 ) {
 	b.logger.Debug(
 		"registering handler",
 		"event_type", eventType,
+		"phase", phase,
+		"timeout", timeout,
 	)
-	b.logger.Debug("registering handler", "event_type", eventType)
 	ctx := context.Background()
-	b.registerHandler(eventType, handler)
+	b.registerHandler(eventType, withHandlerTimeout(handler, timeout), phase)
 	if err := b.startConsumerForEvent(ctx, eventType); err != nil {
 		if !errors.Is(err, redis.Nil) {
 			b.logger.Error(
@@ -164,10 +286,12 @@ func createRedisEventBus(
 	config *RedisEventBusConfig,
 ) *RedisEventBus {
 	return &RedisEventBus{
-		client:   client,
-		handlers: make(map[events.EventType][]eventbus.HandlerFunc),
-		logger:   logger.With("bus", "redis"),
-		config:   config,
+		client:    client,
+		handlers:  make(map[events.EventType]handlerSet),
+		logger:    logger.With("bus", "redis"),
+		config:    config,
+		consumers: make(map[events.EventType]*streamConsumer),
+		paused:    make(map[events.EventType]bool),
 		// channels will be initialized when the DLQ worker actually starts
 		dlqStopChan: nil,
 		dlqStopped:  nil,
@@ -179,12 +303,12 @@ func (b *RedisEventBus) initializeConsumerGroup(
 	ctx context.Context,
 	eventType events.EventType,
 ) error {
-	group := groupNameFor(eventType)
-	stream := streamNameFor(eventType)
+	group := b.groupNameFor(eventType)
+	stream := b.streamNameFor(eventType)
 	if err := b.ensureConsumerGroup(ctx, stream, group); err != nil {
 		return err
 	}
-	b.cleanupIdleConsumers(ctx, stream, group)
+	b.cleanupIdleConsumers(ctx, eventType, stream, group)
 	return nil
 }
 
@@ -241,28 +365,89 @@ func (b *RedisEventBus) ensureConsumerGroup(
 	return nil
 }
 
-// cleanupIdleConsumers removes idle consumers with pending messages.
+// cleanupIdleConsumers removes the consumers of group that have been idle
+// for longer than the configured threshold, by default only those with
+// pending messages. Consumers named as this instance's own consumers of
+// eventType are kept: every instance in the namespace derives the same
+// names, so they may belong to a live instance, and removing them would drop
+// the pending messages this instance is about to reprocess.
 func (b *RedisEventBus) cleanupIdleConsumers(
 	ctx context.Context,
+	eventType events.EventType,
 	stream,
 	group string,
 ) {
-	if consumers, err := b.client.XInfoConsumers(
-		ctx,
-		stream,
-		group,
-	).Result(); err == nil {
-		for _, consumer := range consumers {
-			idleDuration := consumer.Idle * time.Millisecond
-			if consumer.Pending > 0 && idleDuration > 5*time.Minute {
-				b.client.XGroupDelConsumer(ctx, stream, group, consumer.Name)
-			}
+	consumers, err := b.client.XInfoConsumers(ctx, stream, group).Result()
+	if err != nil {
+		b.logger.Warn(
+			"failed to list consumers for cleanup",
+			"error", err,
+			"stream", stream,
+			"group", group,
+		)
+		return
+	}
+	active := b.activeConsumerNames(eventType)
+	for _, consumer := range consumers {
+		remove, reason := b.shouldRemoveConsumer(
+			active[consumer.Name], consumer.Pending, consumer.Idle,
+		)
+		logger := b.logger.With(
+			"stream", stream,
+			"group", group,
+			"consumer", consumer.Name,
+			"pending", consumer.Pending,
+			"idle", consumer.Idle,
+			"reason", reason,
+		)
+		if !remove {
+			logger.Debug("keeping consumer")
+			continue
+		}
+		if err := b.client.XGroupDelConsumer(ctx, stream, group, consumer.Name).Err(); err != nil {
+			logger.Warn("failed to remove idle consumer", "error", err)
+			continue
 		}
+		logger.Info("removed idle consumer")
 	}
 }
 
-// buildEnvelope marshals event and wraps in envelope.
-func (b *RedisEventBus) buildEnvelope(event events.Event) ([]byte, error) {
+// shouldRemoveConsumer reports whether cleanup removes a consumer with
+// pending messages that has been idle for idle, and why.
+func (b *RedisEventBus) shouldRemoveConsumer(
+	active bool,
+	pending int64,
+	idle time.Duration,
+) (bool, string) {
+	threshold := b.config.IdleConsumerThreshold
+	if threshold <= 0 {
+		threshold = DefaultIdleConsumerThreshold
+	}
+	switch {
+	case active:
+		return false, "active consumer name"
+	case idle <= threshold:
+		return false, "not idle long enough"
+	case pending == 0 && !b.config.CleanupConsumersWithoutPending:
+		return false, "no pending messages"
+	}
+	return true, "idle"
+}
+
+// activeConsumerNames returns the names this instance consumes eventType as.
+func (b *RedisEventBus) activeConsumerNames(eventType events.EventType) map[string]bool {
+	name := b.consumerNameFor(eventType)
+	names := map[string]bool{name: true}
+	for i := 1; i < b.consumerConfigFor(eventType).Concurrency; i++ {
+		names[fmt.Sprintf("%s:%d", name, i)] = true
+	}
+	return names
+}
+
+// buildEnvelope marshals event and wraps in envelope, compressing the payload
+// if it reaches the configured threshold. The envelope carries the trace
+// context of ctx.
+func (b *RedisEventBus) buildEnvelope(ctx context.Context, event events.Event) ([]byte, error) {
 	data, err := json.Marshal(event)
 	if err != nil {
 		b.logger.Error(
@@ -273,7 +458,16 @@ func (b *RedisEventBus) buildEnvelope(event events.Event) ([]byte, error) {
 		return nil, fmt.Errorf("redis event bus: marshal failed: %w", err)
 	}
 
-	env := envelope{Type: event.Type(), Payload: data}
+	env, err := newEnvelope(event.Type(), data, b.config.CompressionThreshold)
+	if err != nil {
+		b.logger.Error(
+			"failed to compress event",
+			"error", err,
+			"event_type", event.Type(),
+		)
+		return nil, fmt.Errorf("redis event bus: compression failed: %w", err)
+	}
+	env.injectTraceContext(ctx)
 	envBytes, err := json.Marshal(env)
 	if err != nil {
 		b.logger.Error(
@@ -313,7 +507,7 @@ func (b *RedisEventBus) publishEnvelope(
 
 // prepareAddArgs prepares the XAddArgs for publishing an envelope.
 func (b *RedisEventBus) prepareAddArgs(eventType events.EventType, data []byte) *redis.XAddArgs {
-	stream := streamNameFor(eventType)
+	stream := b.streamNameFor(eventType)
 	return &redis.XAddArgs{
 		Stream: stream,
 		Values: map[string]interface{}{"event": string(data)},
@@ -321,35 +515,183 @@ func (b *RedisEventBus) prepareAddArgs(eventType events.EventType, data []byte)
 }
 
 // registerHandler safely registers a handler for the given event type.
-func (b *RedisEventBus) registerHandler(eventType events.EventType, handler eventbus.HandlerFunc) {
+func (b *RedisEventBus) registerHandler(
+	eventType events.EventType,
+	handler eventbus.HandlerFunc,
+	phase eventbus.Phase,
+) {
 	b.handlersMtx.Lock()
 	defer b.handlersMtx.Unlock()
 	b.ensureHandlersMap()
-	b.handlers[eventType] = append(b.handlers[eventType], handler)
-	b.logger.Debug("registered handler", "event_type", eventType)
+	b.handlers[eventType] = b.handlers[eventType].add(handler, phase)
+	b.logger.Debug("registered handler", "event_type", eventType, "phase", phase)
+}
+
+// withHandlerTimeout runs handler with a context canceled after timeout and
+// returns ErrHandlerTimeout if it has not finished by then. A handler that
+// ignores its context keeps running in the background, but no longer holds up
+// acknowledgment of the message.
+func withHandlerTimeout(
+	handler eventbus.HandlerFunc,
+	timeout time.Duration,
+) eventbus.HandlerFunc {
+	if timeout <= 0 {
+		return handler
+	}
+	return func(ctx context.Context, evt events.Event) error {
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		done := make(chan error, 1)
+		go func() { done <- handler(ctx, evt) }()
+
+		select {
+		case err := <-done:
+			if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return fmt.Errorf("%w after %s: %w", ErrHandlerTimeout, timeout, err)
+			}
+			return err
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return fmt.Errorf("%w after %s", ErrHandlerTimeout, timeout)
+			}
+			return ctx.Err()
+		}
+	}
 }
 
 // ensureHandlersMap initializes the handlers map if it is nil.
 func (b *RedisEventBus) ensureHandlersMap() {
 	if b.handlers == nil {
-		b.handlers = make(map[events.EventType][]eventbus.HandlerFunc)
+		b.handlers = make(map[events.EventType]handlerSet)
 	}
 }
 
-// startConsuming starts a goroutine to consume events for the given eventType.
+// startConsuming starts a goroutine to consume events for the given
+// eventType, unless one is already running or the type is paused.
 func (b *RedisEventBus) startConsuming(ctx context.Context, eventType events.EventType) {
-	go b.consume(ctx, eventType)
+	b.consumersMtx.Lock()
+	defer b.consumersMtx.Unlock()
+	b.startConsumingLocked(ctx, eventType)
+}
+
+// startConsumingLocked is startConsuming with b.consumersMtx held.
+func (b *RedisEventBus) startConsumingLocked(ctx context.Context, eventType events.EventType) {
+	if b.consumers == nil {
+		b.consumers = make(map[events.EventType]*streamConsumer)
+	}
+	if b.consumers[eventType] != nil || b.paused[eventType] {
+		return
+	}
+	c := &streamConsumer{stop: make(chan struct{}), done: make(chan struct{})}
+	b.consumers[eventType] = c
+	cfg := b.consumerConfigFor(eventType)
+	var wg sync.WaitGroup
+	for i := range cfg.Concurrency {
+		consumer := b.consumerNameFor(eventType)
+		if i > 0 {
+			consumer = fmt.Sprintf("%s:%d", consumer, i)
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			b.consume(ctx, eventType, consumer, cfg.BatchSize, c.stop)
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(c.done)
+	}()
+}
+
+// consumerConfigFor returns the consumer settings of eventType, with
+// defaults filled in.
+func (b *RedisEventBus) consumerConfigFor(eventType events.EventType) ConsumerConfig {
+	cfg := b.config.Consumers[eventType]
+	if cfg.Concurrency <= 0 {
+		cfg.Concurrency = 1
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = DefaultConsumerBatchSize
+	}
+	return cfg
+}
+
+// PauseConsumer stops consuming eventType on this instance, leaving new
+// messages in its stream. The messages already read are handled first, so
+// PauseConsumer may wait for the stream read to time out and for the
+// handlers of one batch to finish. Pausing a paused type does nothing; it
+// returns eventbus.ErrNoConsumer if no handler is registered for eventType.
+func (b *RedisEventBus) PauseConsumer(eventType events.EventType) error {
+	b.consumersMtx.Lock()
+	defer b.consumersMtx.Unlock()
+	if b.paused[eventType] {
+		return nil
+	}
+	c := b.consumers[eventType]
+	if c == nil {
+		return fmt.Errorf("%w: %s", eventbus.ErrNoConsumer, eventType)
+	}
+	close(c.stop)
+	<-c.done
+	delete(b.consumers, eventType)
+	if b.paused == nil {
+		b.paused = make(map[events.EventType]bool)
+	}
+	b.paused[eventType] = true
+	b.logger.Info("⏸️ Consumer paused", "event_type", eventType)
+	return nil
+}
+
+// ResumeConsumer restarts consuming a paused eventType. The consumer group
+// continues from its last delivered message, so the messages that arrived
+// while paused are handled in order. Resuming a running type does nothing;
+// it returns eventbus.ErrNoConsumer if no handler is registered for
+// eventType.
+func (b *RedisEventBus) ResumeConsumer(eventType events.EventType) error {
+	b.consumersMtx.Lock()
+	defer b.consumersMtx.Unlock()
+	if !b.paused[eventType] {
+		if b.consumers[eventType] == nil {
+			return fmt.Errorf("%w: %s", eventbus.ErrNoConsumer, eventType)
+		}
+		return nil
+	}
+	delete(b.paused, eventType)
+	b.startConsumingLocked(context.Background(), eventType)
+	b.logger.Info("▶️ Consumer resumed", "event_type", eventType)
+	return nil
+}
+
+// PausedConsumers returns the paused event types, sorted.
+func (b *RedisEventBus) PausedConsumers() []events.EventType {
+	b.consumersMtx.Lock()
+	defer b.consumersMtx.Unlock()
+	paused := make([]events.EventType, 0, len(b.paused))
+	for eventType := range b.paused {
+		paused = append(paused, eventType)
+	}
+	slices.Sort(paused)
+	return paused
 }
 
 // consume starts consuming messages from the
-// Redis stream and routes them to the appropriate handlers.
+// Redis stream as consumer, batchSize at a time, and routes them to the
+// appropriate handlers until stop is closed. Reads are not canceled when
+// stopping; every message read is handled, so none is left delivered but
+// unacknowledged.
+//
+// It first handles the messages still pending for consumer, read before a
+// crash but never acknowledged, and then reads new ones.
 func (b *RedisEventBus) consume(
 	ctx context.Context,
 	eventType events.EventType,
+	consumer string,
+	batchSize int64,
+	stop <-chan struct{},
 ) {
-	stream := streamNameFor(eventType)
-	group := groupNameFor(eventType)
-	consumer := consumerNameFor(eventType)
+	stream := b.streamNameFor(eventType)
+	group := b.groupNameFor(eventType)
 	b.logger.Debug(
 		"starting consumer",
 		"event_type", eventType,
@@ -358,39 +700,83 @@ func (b *RedisEventBus) consume(
 		"consumer", consumer,
 	)
 
+	// Pending messages are read after the ID of the last one handled, so
+	// those left pending again, e.g. unknown types that could not be parked,
+	// are not read over and over.
+	start := "0"
 	for {
+		select {
+		case <-stop:
+			b.logger.Debug("stopped consumer", "event_type", eventType)
+			return
+		default:
+		}
+
 		// Read messages from the stream
-		messages, err := b.readStream(ctx, stream, group, consumer)
+		messages, err := b.readStream(ctx, stream, group, consumer, batchSize, start)
+		if errors.Is(err, redis.Nil) {
+			err = nil
+		}
 		if err != nil {
-			if !errors.Is(err, redis.Nil) {
-				b.logger.Error(
-					"error reading from stream",
-					"error", err,
-					"stream", stream,
-					"group", group,
-				)
+			b.logger.Error(
+				"error reading from stream",
+				"error", err,
+				"stream", stream,
+				"group", group,
+			)
+			// Prevent tight loop on errors
+			select {
+			case <-stop:
+			case <-time.After(5 * time.Second):
 			}
-			time.Sleep(5 * time.Second) // Prevent tight loop on errors
 			continue
 		}
 
-		// Process each message
+		if start != ">" {
+			if len(messages) == 0 {
+				start = ">"
+				continue
+			}
+			start = messages[len(messages)-1].ID
+			b.logger.Info(
+				"reprocessing unacknowledged messages",
+				"event_type", eventType,
+				"consumer", consumer,
+				"count", len(messages),
+			)
+		}
+
+		// Process each message, acknowledging those handled once the batch
+		// is done. Failures were already dead-lettered one by one.
+		acks := make([]string, 0, len(messages))
 		for _, msg := range messages {
-			b.processMessage(ctx, group, msg)
+			if !b.processMessage(ctx, eventType, group, msg) {
+				continue
+			}
+			if b.config.AckPerMessage {
+				b.ackMessages(ctx, eventType, group, msg.ID)
+				continue
+			}
+			acks = append(acks, msg.ID)
 		}
+		b.ackMessages(ctx, eventType, group, acks...)
 	}
 }
 
-// readStream reads messages from a redis stream group.
+// readStream reads up to count messages from a redis stream group, new
+// ones if start is ">" and otherwise those pending for consumer after the
+// ID start.
 func (b *RedisEventBus) readStream(
 	ctx context.Context,
 	stream, group, consumer string,
+	count int64,
+	start string,
 ) ([]redis.XMessage, error) {
 	res, err := b.client.XReadGroup(ctx, &redis.XReadGroupArgs{
 		Group:    group,
 		Consumer: consumer,
-		Streams:  []string{stream, ">"},
-		Count:    10,
+		Streams:  []string{stream, start},
+		Count:    count,
 		Block:    5 * time.Second,
 		NoAck:    false,
 	}).Result()
@@ -413,12 +799,15 @@ func (b *RedisEventBus) readStream(
 	return messages, nil
 }
 
-// processMessage processes a single message from the Redis stream.
+// processMessage processes a single message read by group from the stream
+// of streamType. It reports whether the message is done with and should be
+// acknowledged: it was handled, dead-lettered, parked or cannot be handled.
 func (b *RedisEventBus) processMessage(
 	ctx context.Context,
+	streamType events.EventType,
 	group string,
 	msg redis.XMessage,
-) {
+) bool {
 	b.logger.Debug("📥 Processing message",
 		"msg_id", msg.ID,
 		"group", group,
@@ -430,7 +819,7 @@ func (b *RedisEventBus) processMessage(
 			"invalid message format, missing 'event' field",
 			"msg_id", msg.ID,
 		)
-		return
+		return false
 	}
 
 	var env envelope
@@ -440,7 +829,7 @@ func (b *RedisEventBus) processMessage(
 			"error", err,
 			"msg_id", msg.ID,
 		)
-		return
+		return false
 	}
 
 	// Convert the string event type to EventType
@@ -448,24 +837,31 @@ func (b *RedisEventBus) processMessage(
 
 	constructor, ok := events.EventTypes[evtType]
 	if !ok {
+		return b.handleUnknownEvent(ctx, streamType, env.Type, msg)
+	}
+
+	evt := constructor()
+
+	payload, err := env.eventData()
+	if err != nil {
 		b.logger.Error(
-			"unknown event type",
-			"type", env.Type,
+			"failed to decompress event",
+			"error", err,
+			"event_type", env.Type,
 			"msg_id", msg.ID,
 		)
-		_ = b.ackMessage(ctx, evtType, group, msg.ID)
-		return
+		b.deadLetter(ctx, evtType, msg, fmt.Errorf("failed to decompress event: %w", err))
+		return true
 	}
 
-	evt := constructor()
-
 	b.logger.Debug("🔍 Unmarshaling event",
 		"event_type", env.Type,
-		"payload", string(env.Payload),
+		"compressed", env.Compressed,
+		"payload", string(payload),
 	)
 
 	// Special handling for events with custom JSON unmarshaling
-	err := json.Unmarshal(env.Payload, evt)
+	err = json.Unmarshal(payload, evt)
 
 	b.logger.Debug("🔍 Unmarshaled event",
 		"event_type", env.Type,
@@ -480,71 +876,54 @@ func (b *RedisEventBus) processMessage(
 			"event_type", env.Type,
 			"msg_id", msg.ID,
 		)
-		_ = b.ackMessage(ctx, evtType, group, msg.ID)
-		return
+		b.deadLetter(ctx, evtType, msg, fmt.Errorf("failed to unmarshal event: %w", err))
+		return true
 	}
 
 	handlers := b.getHandlers(evtType)
-	if len(handlers) == 0 {
+	if handlers.len() == 0 {
 		b.logger.Warn(
 			"no handlers registered for event type",
 			"event_type", env.Type,
 			"msg_id", msg.ID,
 		)
-		_ = b.ackMessage(ctx, evtType, group, msg.ID)
-		return
+		return true
 	}
 
-	success := b.executeHandlers(
-		ctx,
-		evtType,
-		evt,
-		msg.ID,
-		handlers,
-	)
+	// Best-effort handlers only run once the critical ones succeeded, and
+	// their failures do not send the message to the DLQ.
+	// The handlers continue the trace the event was emitted in.
+	spanCtx, span := env.startProcessSpan(ctx)
+	success := runPhases(handlers, func(phase []eventbus.HandlerFunc) bool {
+		return b.executeHandlers(spanCtx, evtType, evt, msg.ID, phase)
+	}, b.logger, evtType)
+	if !success {
+		span.SetStatus(codes.Error, "event handler failed")
+	}
+	span.End()
 
-	if success {
-		if err := b.ackMessage(ctx, evtType, group, msg.ID); err != nil {
-			b.logger.Error(
-				"failed to ack message",
-				"error", err,
-				"msg_id", msg.ID,
-			)
-		}
-	} else {
+	if !success {
 		b.logger.Warn(
 			"sending message to DLQ due to handler errors",
 			"event_type", env.Type,
 			"msg_id", msg.ID,
 		)
 		b.pushToDLQ(ctx, evtType, msg.Values)
-		// Ack the original message to avoid reprocessing duplicates endlessly
-		if err := b.ackMessage(ctx, evtType, group, msg.ID); err != nil {
-			b.logger.Error(
-				"failed to ack message after DLQ push",
-				"error", err,
-				"msg_id", msg.ID,
-			)
-		}
 	}
+	// Ack a dead-lettered message too to avoid reprocessing duplicates
+	// endlessly
+	return true
 }
 
 // getHandlers retrieves a copy of the handlers for a given event type.
 func (b *RedisEventBus) getHandlers(
 	eventType events.EventType,
-) []eventbus.HandlerFunc {
+) handlerSet {
 	b.handlersMtx.RLock()
 	defer b.handlersMtx.RUnlock()
 
-	handlers, exists := b.handlers[eventType]
-	if !exists {
-		return nil
-	}
-
 	// Return a copy to avoid race conditions
-	handlersCopy := make([]eventbus.HandlerFunc, len(handlers))
-	copy(handlersCopy, handlers)
-	return handlersCopy
+	return b.handlers[eventType].clone()
 }
 
 // executeHandlers runs all handlers for an event and returns true if all succeed.
@@ -563,17 +942,23 @@ func (b *RedisEventBus) executeHandlers(
 		wg.Add(1)
 		go func(h eventbus.HandlerFunc) {
 			defer wg.Done()
-			if err := h(ctx, evt); err != nil {
-				mu.Lock()
-				success = false
-				mu.Unlock()
-				b.logger.Error(
-					"handler error",
-					"error", err,
-					"event_type", eventType,
-					"msg_id", msgID,
-				)
+			err := h(ctx, evt)
+			if err == nil {
+				return
+			}
+			mu.Lock()
+			success = false
+			mu.Unlock()
+			msg := "handler error"
+			if errors.Is(err, ErrHandlerTimeout) {
+				msg = "⏱️ handler timed out"
 			}
+			b.logger.Error(
+				msg,
+				"error", err,
+				"event_type", eventType,
+				"msg_id", msgID,
+			)
 		}(handler)
 	}
 
@@ -581,15 +966,42 @@ func (b *RedisEventBus) executeHandlers(
 	return success
 }
 
-// ackMessage acknowledges a message in the Redis stream.
-func (b *RedisEventBus) ackMessage(
+// ackMessages acknowledges messages in the Redis stream with one XACK. A
+// failure is logged; the messages stay pending and are handled again when
+// the consumer restarts.
+func (b *RedisEventBus) ackMessages(
 	ctx context.Context,
 	eventType events.EventType,
-	group, msgID string,
-) error {
-	stream := streamNameFor(eventType)
-	_, err := b.client.XAck(ctx, stream, group, msgID).Result()
-	return err
+	group string,
+	msgIDs ...string,
+) {
+	if len(msgIDs) == 0 {
+		return
+	}
+	stream := b.streamNameFor(eventType)
+	if err := b.client.XAck(ctx, stream, group, msgIDs...).Err(); err != nil {
+		b.logger.Error(
+			"failed to ack messages",
+			"error", err,
+			"event_type", eventType,
+			"msg_ids", msgIDs,
+		)
+	}
+}
+
+// deadLetter pushes a message whose event cannot be read to the DLQ, with
+// the reason in its "error" field, so that it is kept for inspection rather
+// than dropped when acknowledged.
+func (b *RedisEventBus) deadLetter(
+	ctx context.Context,
+	eventType events.EventType,
+	msg redis.XMessage,
+	reason error,
+) {
+	values := make(map[string]any, len(msg.Values)+1)
+	maps.Copy(values, msg.Values)
+	values["error"] = reason.Error()
+	b.pushToDLQ(ctx, eventType, values)
 }
 
 // pushToDLQ pushes the raw event (msg.Values) to a DLQ Redis stream for inspection or reprocessing.
@@ -598,7 +1010,7 @@ func (b *RedisEventBus) pushToDLQ(
 	eventType events.EventType,
 	values map[string]any,
 ) {
-	dlqStream := dlqStreamName(eventType)
+	dlqStream := b.dlqStreamName(eventType)
 	b.logger.Info("pushing message to DLQ",
 		"event_type", eventType,
 		"dlq_stream", dlqStream,
@@ -739,6 +1151,9 @@ func (b *RedisEventBus) startDLQRetryWorker(ctx context.Context) error {
 				logger.Debug("DLQ retry worker tick - processing DLQs")
 				start := time.Now()
 				b.processAllDLQs(ctx)
+				if b.config.UnknownEvents != UnknownEventsDrop {
+					b.replayUnknownEvents(ctx)
+				}
 				logger.Debug("DLQ processing completed", "duration", time.Since(start))
 			}
 		}
@@ -774,7 +1189,7 @@ func (b *RedisEventBus) processAllDLQs(ctx context.Context) {
 
 	// Ensure DLQ consumer group exists for each event type
 	for eventType := range events.EventTypes {
-		dlq := dlqStreamName(eventType)
+		dlq := b.dlqStreamName(eventType)
 		b.logger.Debug("ensuring consumer group for DLQ",
 			"event_type", eventType,
 			"dlq_stream", dlq,
@@ -795,7 +1210,7 @@ func (b *RedisEventBus) processAllDLQs(ctx context.Context) {
 
 	processedAny := false
 	for eventType := range events.EventTypes {
-		dlq := dlqStreamName(eventType)
+		dlq := b.dlqStreamName(eventType)
 
 		// Check if DLQ exists and has messages before processing
 		exists, err := b.client.Exists(ctx, dlq).Result()
@@ -808,6 +1223,7 @@ func (b *RedisEventBus) processAllDLQs(ctx context.Context) {
 		}
 
 		if exists == 0 {
+			b.checkDLQThreshold(ctx, eventType, dlq, 0)
 			// DLQ doesn't exist for this event type, skip
 			b.logger.Debug("DLQ does not exist, skipping",
 				"event_type", eventType,
@@ -826,6 +1242,8 @@ func (b *RedisEventBus) processAllDLQs(ctx context.Context) {
 			continue
 		}
 
+		b.checkDLQThreshold(ctx, eventType, dlq, streamLen)
+
 		if streamLen == 0 {
 			b.logger.Debug("📭 DLQ is empty, skipping",
 				"event_type", eventType,
@@ -834,7 +1252,7 @@ func (b *RedisEventBus) processAllDLQs(ctx context.Context) {
 			continue
 		}
 
-		stream := streamNameFor(eventType)
+		stream := b.streamNameFor(eventType)
 		b.logger.Info("🔄 Processing DLQ messages",
 			"event_type", eventType,
 			"dlq_stream", dlq,
@@ -862,6 +1280,55 @@ func (b *RedisEventBus) processAllDLQs(ctx context.Context) {
 	}
 }
 
+// checkDLQThreshold alerts when the DLQ of eventType holds more messages than
+// the configured threshold. It alerts once per crossing: the alert re-arms
+// only after the DLQ drains back to the threshold.
+func (b *RedisEventBus) checkDLQThreshold(
+	ctx context.Context,
+	eventType events.EventType,
+	dlq string,
+	length int64,
+) {
+	threshold := b.config.DLQAlertThreshold
+	if threshold <= 0 {
+		return
+	}
+	exceeded := length > threshold
+
+	b.dlqAlertMtx.Lock()
+	if b.dlqAlerted == nil {
+		b.dlqAlerted = make(map[events.EventType]bool)
+	}
+	alerted := b.dlqAlerted[eventType]
+	b.dlqAlerted[eventType] = exceeded
+	b.dlqAlertMtx.Unlock()
+
+	if !exceeded || alerted {
+		return
+	}
+
+	alert := events.NewDLQThresholdExceeded(string(eventType), dlq, length, threshold)
+	b.logger.Warn("🚨 DLQ threshold exceeded",
+		"event_type", eventType,
+		"dlq_stream", dlq,
+		"length", length,
+		"threshold", threshold,
+	)
+	if b.config.OnDLQThresholdExceeded != nil {
+		b.config.OnDLQThresholdExceeded(ctx, alert)
+	}
+	// Alerting about the alert's own DLQ through the bus could loop.
+	if eventType == events.EventTypeDLQThresholdExceeded {
+		return
+	}
+	if err := b.Emit(ctx, alert); err != nil {
+		b.logger.Error("failed to emit DLQ threshold alert",
+			"error", err,
+			"event_type", eventType,
+		)
+	}
+}
+
 // retryDLQ reads messages from the DLQ and republishes them to the original stream
 func (b *RedisEventBus) retryDLQ(
 	ctx context.Context,
@@ -1201,3 +1668,7 @@ func (b *RedisEventBus) calculateBackoff(attempt int) time.Duration {
 
 	return backoff
 }
+
+// Ensure RedisEventBus implements the PhasedBus interface.
+var _ eventbus.PhasedBus = (*RedisEventBus)(nil)
+var _ eventbus.PausableBus = (*RedisEventBus)(nil)
@@ -5,9 +5,15 @@ package eventbus
 
 import (
 	"context"
+	"fmt"
+	"io"
 	"log/slog"
 	"os"
+	"sync/atomic"
 	"testing"
+	"time"
+
+	"github.com/amirasaad/fintech/pkg/domain/events"
 
 	"github.com/testcontainers/testcontainers-go"
 	"github.com/testcontainers/testcontainers-go/wait"
@@ -22,6 +28,21 @@ func (e *benchmarkEvent) Type() string {
 }
 
 func setupRedisBusForBenchmark(b *testing.B) (*RedisEventBus, func()) {
+	url, cleanup := startRedisForBenchmark(b)
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	// Use default config for benchmarks
+	bus, err := NewWithRedis(url, logger, nil)
+	if err != nil {
+		cleanup()
+		b.Fatalf("Failed to create Redis event bus: %v", err)
+	}
+	return bus, cleanup
+}
+
+// startRedisForBenchmark starts a Redis container and returns its URL and a
+// cleanup function.
+func startRedisForBenchmark(b *testing.B) (string, func()) {
 	ctx := context.Background()
 	req := testcontainers.ContainerRequest{
 		Image:        "redis:7.0.5",
@@ -47,19 +68,10 @@ func setupRedisBusForBenchmark(b *testing.B) (*RedisEventBus, func()) {
 		b.Fatalf("Failed to get container host: %v", err)
 	}
 
-	url := "redis://" + host + ":" + port.Port()
-	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
-
-	// Use default config for benchmarks
-	bus, err := NewWithRedis(url, logger, nil)
-	if err != nil {
-		b.Fatalf("Failed to create Redis event bus: %v", err)
-	}
-
 	cleanup := func() {
 		_ = container.Terminate(ctx)
 	}
-	return bus, cleanup
+	return "redis://" + host + ":" + port.Port(), cleanup
 }
 
 func BenchmarkRedisEmit(b *testing.B) {
@@ -91,3 +103,111 @@ func BenchmarkRedisEmitParallel(b *testing.B) {
 		}
 	})
 }
+
+// BenchmarkRedisConsumeAck compares draining a stream when each message is
+// acknowledged on its own with acknowledging each batch read with one XACK.
+func BenchmarkRedisConsumeAck(b *testing.B) {
+	url, cleanup := startRedisForBenchmark(b)
+	defer cleanup()
+	events.EventTypes["benchmark.event"] = func() events.Event { return &benchmarkEvent{} }
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	run := 0
+	for _, perMessage := range []bool{true, false} {
+		name := "batched"
+		if perMessage {
+			name = "per-message"
+		}
+		b.Run(name, func(b *testing.B) {
+			run++
+			cfg := DefaultRedisEventBusConfig()
+			cfg.Namespace = fmt.Sprintf("ack%d", run)
+			cfg.AckPerMessage = perMessage
+			cfg.Consumers = map[events.EventType]ConsumerConfig{
+				"benchmark.event": {BatchSize: 100},
+			}
+			bus, err := NewWithRedis(url, logger, cfg)
+			if err != nil {
+				b.Fatalf("Failed to create Redis event bus: %v", err)
+			}
+
+			ctx := context.Background()
+			for i := range b.N {
+				if err := bus.Emit(ctx, &benchmarkEvent{ID: i}); err != nil {
+					b.Fatal(err)
+				}
+			}
+
+			var handled atomic.Int64
+			done := make(chan struct{})
+			b.ResetTimer()
+			bus.Register("benchmark.event", func(ctx context.Context, e events.Event) error {
+				if handled.Add(1) == int64(b.N) {
+					close(done)
+				}
+				return nil
+			})
+			<-done
+			// Wait for the last batch to be acknowledged too.
+			stream := bus.streamNameFor("benchmark.event")
+			group := bus.groupNameFor("benchmark.event")
+			for {
+				pending, err := bus.client.XPending(ctx, stream, group).Result()
+				if err != nil || pending.Count == 0 {
+					break
+				}
+				time.Sleep(time.Millisecond)
+			}
+			b.StopTimer()
+			_ = bus.PauseConsumer("benchmark.event")
+		})
+	}
+}
+
+// BenchmarkRedisConsumeHotType measures how fast a hot event type is
+// drained with one consumer and with several, for a handler that spends a
+// millisecond waiting on I/O. Each run uses its own namespace so the streams
+// start empty.
+func BenchmarkRedisConsumeHotType(b *testing.B) {
+	url, cleanup := startRedisForBenchmark(b)
+	defer cleanup()
+	events.EventTypes["benchmark.event"] = func() events.Event { return &benchmarkEvent{} }
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	run := 0
+	for _, concurrency := range []int{1, 4, 8} {
+		b.Run(fmt.Sprintf("concurrency=%d", concurrency), func(b *testing.B) {
+			run++
+			cfg := DefaultRedisEventBusConfig()
+			cfg.Namespace = fmt.Sprintf("bench%d", run)
+			cfg.Consumers = map[events.EventType]ConsumerConfig{
+				"benchmark.event": {Concurrency: concurrency},
+			}
+			bus, err := NewWithRedis(url, logger, cfg)
+			if err != nil {
+				b.Fatalf("Failed to create Redis event bus: %v", err)
+			}
+
+			ctx := context.Background()
+			for i := range b.N {
+				if err := bus.Emit(ctx, &benchmarkEvent{ID: i}); err != nil {
+					b.Fatal(err)
+				}
+			}
+
+			var handled atomic.Int64
+			done := make(chan struct{})
+			b.ResetTimer()
+			bus.Register("benchmark.event", func(ctx context.Context, e events.Event) error {
+				time.Sleep(time.Millisecond)
+				if handled.Add(1) == int64(b.N) {
+					close(done)
+				}
+				return nil
+			})
+			<-done
+			b.StopTimer()
+			_ = bus.PauseConsumer("benchmark.event")
+		})
+	}
+}
@@ -14,11 +14,32 @@ import (
 )
 
 type RedisEventBusConfig struct {
+	Namespace         string
 	DLQRetryInterval  time.Duration
 	DLQBatchSize      int64
 	DLQMaxRetries     int
 	DLQInitialBackoff time.Duration
 	DLQMaxBackoff     time.Duration
+	HandlerTimeout    time.Duration
+
+	CompressionThreshold   int
+	DLQAlertThreshold      int64
+	OnDLQThresholdExceeded func(ctx context.Context, alert *events.DLQThresholdExceeded)
+	UnknownEvents          UnknownEventPolicy
+	Consumers              map[events.EventType]ConsumerConfig
+	AckPerMessage          bool
+
+	IdleConsumerThreshold          time.Duration
+	CleanupConsumersWithoutPending bool
+}
+
+const DefaultIdleConsumerThreshold = 5 * time.Minute
+
+const DefaultConsumerBatchSize int64 = 10
+
+type ConsumerConfig struct {
+	Concurrency int
+	BatchSize   int64
 }
 
 func DefaultRedisEventBusConfig() *RedisEventBusConfig {
@@ -41,8 +62,35 @@ func NewWithRedis(
 func (b *RedisEventBus) Register(eventType events.EventType, handler eventbus.HandlerFunc) {
 }
 
+func (b *RedisEventBus) RegisterWithTimeout(
+	eventType events.EventType,
+	handler eventbus.HandlerFunc,
+	timeout time.Duration,
+) {
+}
+
+func (b *RedisEventBus) RegisterWithPhase(
+	eventType events.EventType,
+	handler eventbus.HandlerFunc,
+	phase eventbus.Phase,
+) {
+}
+
 func (b *RedisEventBus) Emit(ctx context.Context, event events.Event) error {
 	return fmt.Errorf("redis event bus: build with -tags redis to enable")
 }
 
-var _ eventbus.Bus = (*RedisEventBus)(nil)
+func (b *RedisEventBus) PauseConsumer(eventType events.EventType) error {
+	return fmt.Errorf("redis event bus: build with -tags redis to enable")
+}
+
+func (b *RedisEventBus) ResumeConsumer(eventType events.EventType) error {
+	return fmt.Errorf("redis event bus: build with -tags redis to enable")
+}
+
+func (b *RedisEventBus) PausedConsumers() []events.EventType {
+	return nil
+}
+
+var _ eventbus.PhasedBus = (*RedisEventBus)(nil)
+var _ eventbus.PausableBus = (*RedisEventBus)(nil)
@@ -10,9 +10,11 @@ import (
 	"time"
 
 	"github.com/amirasaad/fintech/pkg/domain/events"
+	"github.com/amirasaad/fintech/pkg/eventbus"
 
 	"log/slog"
 	"os"
+	"strings"
 
 	"github.com/redis/go-redis/v9"
 	"github.com/stretchr/testify/require"
@@ -150,7 +152,7 @@ func TestRedisBusDLQ(t *testing.T) {
 	// We reconstruct the Redis URL as in setupRedisBus
 	// The stream name is "dlq:test:event"
 
-	dlqStream := dlqStreamName("test.event")
+	dlqStream := bus.dlqStreamName("test.event")
 	res, err := bus.client.XRead(ctx, &redis.XReadArgs{
 		Streams: []string{dlqStream, "0"},
 		Count:   1,
@@ -162,6 +164,39 @@ func TestRedisBusDLQ(t *testing.T) {
 	require.Len(t, res[0].Messages, 1)
 }
 
+// TestRedisBusCorruptPayloadDLQ verifies that a message whose payload cannot
+// be decompressed is dead-lettered with the error instead of dropped.
+func TestRedisBusCorruptPayloadDLQ(t *testing.T) {
+	events.EventTypes["test.event"] = func() events.Event { return &TestEvent{} }
+	bus, cleanup := setupRedisBus(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	handled := make(chan struct{}, 1)
+	bus.Register("test.event", func(ctx context.Context, e events.Event) error {
+		handled <- struct{}{}
+		return nil
+	})
+
+	// "bm90IGd6aXA=" is base64 for "not gzip".
+	corrupt := `{"type":"test.event","payload":"bm90IGd6aXA=","compressed":true}`
+	require.NoError(t, bus.publishEnvelope(ctx, "test.event", []byte(corrupt)))
+	time.Sleep(2 * time.Second)
+
+	res, err := bus.client.XRead(ctx, &redis.XReadArgs{
+		Streams: []string{bus.dlqStreamName("test.event"), "0"},
+		Count:   1,
+		Block:   time.Second,
+	}).Result()
+	require.NoError(t, err)
+	require.Len(t, res, 1)
+	require.Len(t, res[0].Messages, 1)
+	values := res[0].Messages[0].Values
+	require.Equal(t, corrupt, values["event"])
+	require.Contains(t, values["error"], "failed to decompress event")
+	require.Empty(t, handled)
+}
+
 // TestRedisBusDLQRetry verifies that DLQ retry republishes messages
 // to the original stream and handlers can successfully consume them after a failure.
 func TestRedisBusDLQRetry(t *testing.T) {
@@ -203,3 +238,354 @@ func TestRedisBusDLQRetry(t *testing.T) {
 		t.Fatal("DLQ retry did not republish message in time")
 	}
 }
+
+// TestRedisBusNamespacedDLQRetry verifies that a namespaced bus consumes,
+// dead-letters and retries events only in its own streams.
+func TestRedisBusNamespacedDLQRetry(t *testing.T) {
+	events.EventTypes["test.event"] = func() events.Event { return &TestEvent{} }
+	bus, cleanup := setupRedisBus(t)
+	defer cleanup()
+	bus.config.Namespace = "staging"
+
+	ctx := context.Background()
+	fail := true
+	received := make(chan string, 1)
+	bus.Register("test.event", func(ctx context.Context, e events.Event) error {
+		if fail {
+			return fmt.Errorf("temporary failure")
+		}
+		received <- e.(*TestEvent).Message
+		return nil
+	})
+
+	require.NoError(t, bus.Emit(ctx, &TestEvent{Message: "namespaced"}))
+	time.Sleep(2 * time.Second)
+
+	n, err := bus.client.XLen(ctx, "staging:dlq:test:event").Result()
+	require.NoError(t, err)
+	require.Equal(t, int64(1), n)
+	exists, err := bus.client.Exists(ctx, "events:test:event", "dlq:test:event").Result()
+	require.NoError(t, err)
+	require.Zero(t, exists)
+
+	fail = false
+	bus.processAllDLQs(ctx)
+	select {
+	case msg := <-received:
+		require.Equal(t, "namespaced", msg)
+	case <-time.After(5 * time.Second):
+		t.Fatal("DLQ retry did not republish namespaced message in time")
+	}
+}
+
+// TestRedisBusNames verifies that stream, group and consumer names carry
+// the configured namespace.
+func TestRedisBusNames(t *testing.T) {
+	bus := createRedisEventBus(nil, slog.Default(), DefaultRedisEventBusConfig())
+	require.Equal(t, "events:payment:completed", bus.streamNameFor("Payment.Completed"))
+	require.Equal(t, "dlq:payment:completed", bus.dlqStreamName("Payment.Completed"))
+
+	bus.config.Namespace = "staging"
+	require.Equal(t, "staging:events:payment:completed", bus.streamNameFor("Payment.Completed"))
+	require.Equal(t, "staging:dlq:payment:completed", bus.dlqStreamName("Payment.Completed"))
+	require.Equal(t, "staging:group:payment:completed", bus.groupNameFor("Payment.Completed"))
+	require.Equal(
+		t,
+		"staging:consumer:payment:completed",
+		bus.consumerNameFor("Payment.Completed"),
+	)
+}
+
+// TestRedisBusCompressedDLQRetry verifies that compressed events survive the
+// round trip through the DLQ.
+func TestRedisBusCompressedDLQRetry(t *testing.T) {
+	events.EventTypes["test.event"] = func() events.Event { return &TestEvent{} }
+	bus, cleanup := setupRedisBus(t)
+	defer cleanup()
+	bus.config.CompressionThreshold = 64
+
+	ctx := context.Background()
+	message := strings.Repeat("compress me ", 50)
+
+	fail := true
+	received := make(chan string, 1)
+	bus.Register("test.event", func(ctx context.Context, e events.Event) error {
+		if fail {
+			return fmt.Errorf("temporary failure")
+		}
+		received <- e.(*TestEvent).Message
+		return nil
+	})
+
+	require.NoError(t, bus.Emit(ctx, &TestEvent{Message: message}))
+	time.Sleep(2 * time.Second)
+
+	fail = false
+	bus.processAllDLQs(ctx)
+
+	select {
+	case msg := <-received:
+		require.Equal(t, message, msg)
+	case <-time.After(5 * time.Second):
+		t.Fatal("DLQ retry did not republish compressed message in time")
+	}
+}
+
+// TestRedisBusDLQThreshold verifies that the DLQ alert fires once per crossing
+// of the threshold and re-arms after the DLQ drains.
+func TestRedisBusDLQThreshold(t *testing.T) {
+	var alerts []*events.DLQThresholdExceeded
+	bus := createRedisEventBus(nil, slog.Default(), &RedisEventBusConfig{
+		DLQAlertThreshold: 10,
+		OnDLQThresholdExceeded: func(_ context.Context, alert *events.DLQThresholdExceeded) {
+			alerts = append(alerts, alert)
+		},
+	})
+	ctx := context.Background()
+	dlq := bus.dlqStreamName("test.event")
+
+	for _, length := range []int64{5, 10, 11, 12, 30, 3, 15} {
+		bus.checkDLQThreshold(ctx, "test.event", dlq, length)
+	}
+
+	require.Len(t, alerts, 2)
+	require.Equal(t, int64(11), alerts[0].Length)
+	require.Equal(t, int64(15), alerts[1].Length)
+	require.Equal(t, "test.event", alerts[1].EventType)
+	require.Equal(t, int64(10), alerts[1].Threshold)
+}
+
+// TestRedisBusHandlerTimeout verifies that a hung handler fails the event
+// instead of blocking acknowledgment, and that timeouts are distinguishable.
+func TestRedisBusHandlerTimeout(t *testing.T) {
+	bus := createRedisEventBus(nil, slog.Default(), DefaultRedisEventBusConfig())
+	ctx := context.Background()
+	evt := &TestEvent{Message: "slow"}
+
+	hung := withHandlerTimeout(func(ctx context.Context, e events.Event) error {
+		select {} // ignores its context
+	}, 50*time.Millisecond)
+	fast := withHandlerTimeout(func(ctx context.Context, e events.Event) error {
+		return nil
+	}, 50*time.Millisecond)
+	failing := withHandlerTimeout(func(ctx context.Context, e events.Event) error {
+		return fmt.Errorf("boom")
+	}, 50*time.Millisecond)
+
+	start := time.Now()
+	err := hung(ctx, evt)
+	require.ErrorIs(t, err, ErrHandlerTimeout)
+	require.Less(t, time.Since(start), time.Second)
+	require.NoError(t, fast(ctx, evt))
+	err = failing(ctx, evt)
+	require.Error(t, err)
+	require.NotErrorIs(t, err, ErrHandlerTimeout)
+
+	require.False(t, bus.executeHandlers(ctx, "test.event", evt, "1-0",
+		[]eventbus.HandlerFunc{fast, hung}))
+	require.True(t, bus.executeHandlers(ctx, "test.event", evt, "1-0",
+		[]eventbus.HandlerFunc{fast}))
+}
+
+// TestRedisBusPauseConsumer verifies that events emitted while a consumer is
+// paused stay in the stream and are handled, in order, once it is resumed.
+func TestRedisBusPauseConsumer(t *testing.T) {
+	events.EventTypes["test.event"] = func() events.Event { return &TestEvent{} }
+	bus, cleanup := setupRedisBus(t)
+	defer cleanup()
+
+	received := make(chan string, 3)
+	bus.Register("test.event", func(ctx context.Context, e events.Event) error {
+		received <- e.(*TestEvent).Message
+		return nil
+	})
+	require.ErrorIs(t, bus.PauseConsumer("unknown.event"), eventbus.ErrNoConsumer)
+
+	require.NoError(t, bus.PauseConsumer("test.event"))
+	require.NoError(t, bus.PauseConsumer("test.event"))
+	require.Equal(t, []events.EventType{"test.event"}, bus.PausedConsumers())
+
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		require.NoError(t, bus.Emit(ctx, &TestEvent{Message: fmt.Sprintf("msg %d", i)}))
+	}
+	select {
+	case msg := <-received:
+		t.Fatalf("paused consumer handled %q", msg)
+	case <-time.After(time.Second):
+	}
+
+	require.NoError(t, bus.ResumeConsumer("test.event"))
+	require.Empty(t, bus.PausedConsumers())
+	for i := 0; i < 3; i++ {
+		select {
+		case msg := <-received:
+			require.Equal(t, fmt.Sprintf("msg %d", i), msg)
+		case <-time.After(3 * time.Second):
+			t.Fatal("resumed consumer did not handle the paused events")
+		}
+	}
+}
+
+type FutureEvent struct {
+	Message string
+}
+
+func (e *FutureEvent) Type() string {
+	return "test.future"
+}
+
+// TestRedisBusReprocessesPendingMessages verifies that messages delivered to
+// a consumer that stopped before acknowledging them, e.g. by crashing
+// mid-batch, are handled when the consumer starts again.
+func TestRedisBusReprocessesPendingMessages(t *testing.T) {
+	events.EventTypes["test.event"] = func() events.Event { return &TestEvent{} }
+	bus, cleanup := setupRedisBus(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	stream, group := bus.streamNameFor("test.event"), bus.groupNameFor("test.event")
+	require.NoError(t, bus.ensureConsumerGroup(ctx, stream, group))
+	for i := range 3 {
+		require.NoError(t, bus.Emit(ctx, &TestEvent{Message: fmt.Sprintf("msg %d", i)}))
+	}
+	// Deliver the messages without acknowledging them.
+	delivered, err := bus.readStream(ctx, stream, group, bus.consumerNameFor("test.event"), 10, ">")
+	require.NoError(t, err)
+	require.Len(t, delivered, 3)
+
+	received := make(chan string, 3)
+	bus.Register("test.event", func(ctx context.Context, e events.Event) error {
+		received <- e.(*TestEvent).Message
+		return nil
+	})
+	for i := range 3 {
+		select {
+		case msg := <-received:
+			require.Equal(t, fmt.Sprintf("msg %d", i), msg)
+		case <-time.After(3 * time.Second):
+			t.Fatal("pending message was not reprocessed")
+		}
+	}
+	require.Eventually(t, func() bool {
+		pending, err := bus.client.XPending(ctx, stream, group).Result()
+		return err == nil && pending.Count == 0
+	}, 3*time.Second, 50*time.Millisecond)
+}
+
+// TestRedisBusParksUnknownEvent verifies that a message of an event type the
+// bus does not know is parked rather than dropped, and replayed onto its own
+// stream once the type is known.
+func TestRedisBusParksUnknownEvent(t *testing.T) {
+	events.EventTypes["test.event"] = func() events.Event { return &TestEvent{} }
+	delete(events.EventTypes, "test.future")
+	defer delete(events.EventTypes, "test.future")
+	bus, cleanup := setupRedisBus(t)
+	defer cleanup()
+
+	bus.Register("test.event", func(ctx context.Context, e events.Event) error {
+		return nil
+	})
+
+	ctx := context.Background()
+	data, err := bus.buildEnvelope(ctx, &FutureEvent{Message: "from the future"})
+	require.NoError(t, err)
+	require.NoError(t, bus.publishToStream(
+		ctx,
+		bus.streamNameFor("test.event"),
+		map[string]any{"event": string(data)},
+	))
+
+	parked := bus.unknownStreamName()
+	require.Eventually(t, func() bool {
+		n, err := bus.client.XLen(ctx, parked).Result()
+		return err == nil && n == 1
+	}, 3*time.Second, 50*time.Millisecond)
+	pending, err := bus.client.XPending(
+		ctx, bus.streamNameFor("test.event"), bus.groupNameFor("test.event"),
+	).Result()
+	require.NoError(t, err)
+	require.Zero(t, pending.Count)
+
+	bus.replayUnknownEvents(ctx)
+	n, err := bus.client.XLen(ctx, parked).Result()
+	require.NoError(t, err)
+	require.EqualValues(t, 1, n, "still unknown types stay parked")
+
+	events.EventTypes["test.future"] = func() events.Event { return &FutureEvent{} }
+	bus.replayUnknownEvents(ctx)
+	n, err = bus.client.XLen(ctx, parked).Result()
+	require.NoError(t, err)
+	require.Zero(t, n)
+	n, err = bus.client.XLen(ctx, bus.streamNameFor("test.future")).Result()
+	require.NoError(t, err)
+	require.EqualValues(t, 1, n)
+}
+
+// TestRedisBusShouldRemoveConsumer verifies which idle consumers cleanup
+// removes.
+func TestRedisBusShouldRemoveConsumer(t *testing.T) {
+	bus := createRedisEventBus(nil, slog.Default(), &RedisEventBusConfig{
+		IdleConsumerThreshold: time.Minute,
+		Consumers: map[events.EventType]ConsumerConfig{
+			"test.event": {Concurrency: 2},
+		},
+	})
+	active := bus.activeConsumerNames("test.event")
+	require.Len(t, active, 2)
+	require.True(t, active[bus.consumerNameFor("test.event")])
+	require.True(t, active[bus.consumerNameFor("test.event")+":1"])
+
+	tests := []struct {
+		name    string
+		active  bool
+		pending int64
+		idle    time.Duration
+		remove  bool
+	}{
+		{"idle with pending", false, 3, 2 * time.Minute, true},
+		{"recently active", false, 3, 30 * time.Second, false},
+		{"idle without pending", false, 0, 2 * time.Minute, false},
+		{"active name", true, 3, time.Hour, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			remove, reason := bus.shouldRemoveConsumer(tt.active, tt.pending, tt.idle)
+			require.Equal(t, tt.remove, remove, reason)
+		})
+	}
+
+	bus.config.CleanupConsumersWithoutPending = true
+	remove, _ := bus.shouldRemoveConsumer(false, 0, 2*time.Minute)
+	require.True(t, remove)
+}
+
+// TestRedisBusCleanupKeepsActiveConsumers verifies that cleanup removes an
+// idle consumer left behind by another name but keeps the consumer this
+// instance is about to use, with its pending messages.
+func TestRedisBusCleanupKeepsActiveConsumers(t *testing.T) {
+	events.EventTypes["test.event"] = func() events.Event { return &TestEvent{} }
+	bus, cleanup := setupRedisBus(t)
+	defer cleanup()
+	bus.config.IdleConsumerThreshold = 100 * time.Millisecond
+
+	ctx := context.Background()
+	stream, group := bus.streamNameFor("test.event"), bus.groupNameFor("test.event")
+	require.NoError(t, bus.ensureConsumerGroup(ctx, stream, group))
+	for i := range 2 {
+		require.NoError(t, bus.Emit(ctx, &TestEvent{Message: fmt.Sprintf("msg %d", i)}))
+	}
+	own, stale := bus.consumerNameFor("test.event"), "consumer:stale"
+	_, err := bus.readStream(ctx, stream, group, own, 1, ">")
+	require.NoError(t, err)
+	_, err = bus.readStream(ctx, stream, group, stale, 1, ">")
+	require.NoError(t, err)
+	time.Sleep(200 * time.Millisecond)
+
+	bus.cleanupIdleConsumers(ctx, "test.event", stream, group)
+	consumers, err := bus.client.XInfoConsumers(ctx, stream, group).Result()
+	require.NoError(t, err)
+	require.Len(t, consumers, 1)
+	require.Equal(t, own, consumers[0].Name)
+	require.EqualValues(t, 1, consumers[0].Pending)
+}
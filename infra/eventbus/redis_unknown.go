@@ -0,0 +1,127 @@
+//go:build redis
+// +build redis
+
+package eventbus
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/amirasaad/fintech/pkg/domain/events"
+	"github.com/redis/go-redis/v9"
+)
+
+// unknownStreamName returns the stream unknown event types are parked in.
+func (b *RedisEventBus) unknownStreamName() string {
+	return b.nameFor("unknown", "events")
+}
+
+// handleUnknownEvent deals with a message of an event type this instance
+// does not know, read from the stream of streamType, and reports whether it
+// should be acknowledged. Unless the policy is UnknownEventsDrop the message
+// is parked in the unknown events stream first; if parking fails it stays
+// pending so it is not lost.
+func (b *RedisEventBus) handleUnknownEvent(
+	ctx context.Context,
+	streamType events.EventType,
+	evtType string,
+	msg redis.XMessage,
+) bool {
+	if b.config.UnknownEvents == UnknownEventsDrop {
+		b.logger.Error(
+			"unknown event type, dropping",
+			"type", evtType,
+			"msg_id", msg.ID,
+		)
+		return true
+	}
+
+	stream := b.unknownStreamName()
+	values := make(map[string]any, len(msg.Values)+3)
+	for k, v := range msg.Values {
+		values[k] = v
+	}
+	values["origin_stream"] = b.streamNameFor(streamType)
+	values["origin_id"] = msg.ID
+	values["parked_at"] = strconv.FormatInt(time.Now().UTC().Unix(), 10)
+
+	if err := b.publishToStream(ctx, stream, values); err != nil {
+		b.logger.Error(
+			"failed to park unknown event type",
+			"error", err,
+			"type", evtType,
+			"msg_id", msg.ID,
+			"stream", stream,
+		)
+		return false
+	}
+	b.logger.Warn(
+		"unknown event type, parked",
+		"type", evtType,
+		"msg_id", msg.ID,
+		"stream", stream,
+	)
+	return true
+}
+
+// replayUnknownEvents moves parked messages whose event type is now known
+// back onto their event stream. Messages of types that are still unknown
+// stay parked.
+func (b *RedisEventBus) replayUnknownEvents(ctx context.Context) {
+	stream := b.unknownStreamName()
+	msgs, err := b.client.XRangeN(
+		ctx, stream, "-", "+", int64(b.config.DLQBatchSize),
+	).Result()
+	if err != nil {
+		b.logger.Error(
+			"failed to read unknown events stream",
+			"error", err,
+			"stream", stream,
+		)
+		return
+	}
+
+	for _, msg := range msgs {
+		raw, ok := msg.Values["event"].(string)
+		if !ok {
+			continue
+		}
+		var env envelope
+		if err := json.Unmarshal([]byte(raw), &env); err != nil {
+			continue
+		}
+		evtType := events.EventType(env.Type)
+		if _, ok := events.EventTypes[evtType]; !ok {
+			continue
+		}
+
+		if err := b.publishToStream(
+			ctx,
+			b.streamNameFor(evtType),
+			map[string]any{"event": raw},
+		); err != nil {
+			b.logger.Error(
+				"failed to replay parked event",
+				"error", err,
+				"type", env.Type,
+				"msg_id", msg.ID,
+			)
+			continue
+		}
+		if err := b.client.XDel(ctx, stream, msg.ID).Err(); err != nil {
+			b.logger.Error(
+				"failed to remove replayed event from unknown events stream",
+				"error", err,
+				"msg_id", msg.ID,
+			)
+			continue
+		}
+		b.logger.Info(
+			"replayed parked event",
+			"type", env.Type,
+			"msg_id", msg.ID,
+		)
+	}
+}
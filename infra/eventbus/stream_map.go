@@ -9,23 +9,33 @@ import (
 	"strings"
 )
 
-func streamNameFor(eventType events.EventType) string {
-	return nameFor("events", eventType)
+func (b *RedisEventBus) streamNameFor(eventType events.EventType) string {
+	return b.nameFor("events", eventType)
 }
 
 // dlqStreamName returns the DLQ stream name for the given event type.
-func dlqStreamName(eventType events.EventType) string {
-	return nameFor("dlq", eventType)
+func (b *RedisEventBus) dlqStreamName(eventType events.EventType) string {
+	return b.nameFor("dlq", eventType)
 }
 
 // groupNameFor returns the Redis consumer group name for the event type.
-func groupNameFor(eventType events.EventType) string {
-	return nameFor("group", eventType)
+func (b *RedisEventBus) groupNameFor(eventType events.EventType) string {
+	return b.nameFor("group", eventType)
 }
 
 // consumerNameFor returns the Redis consumer name for the event type.
-func consumerNameFor(eventType events.EventType) string {
-	return nameFor("consumer", eventType)
+func (b *RedisEventBus) consumerNameFor(eventType events.EventType) string {
+	return b.nameFor("consumer", eventType)
+}
+
+// nameFor returns the name of kind for the event type, in the configured
+// namespace if any.
+func (b *RedisEventBus) nameFor(kind string, eventType events.EventType) string {
+	name := nameFor(kind, eventType)
+	if ns := strings.TrimSpace(b.config.Namespace); ns != "" {
+		return ns + ":" + name
+	}
+	return name
 }
 
 func nameFor(prefix string, eventType events.EventType) string {
@@ -0,0 +1,14 @@
+package eventbus
+
+// UnknownEventPolicy is what the Redis bus does with a message whose event
+// type this instance does not know, e.g. one emitted by a newer version
+// during a rolling upgrade.
+type UnknownEventPolicy string
+
+const (
+	// UnknownEventsPark moves the message to the unknown events stream,
+	// from which it is replayed once an instance knows its type.
+	UnknownEventsPark UnknownEventPolicy = "park"
+	// UnknownEventsDrop acknowledges and discards the message.
+	UnknownEventsDrop UnknownEventPolicy = "drop"
+)
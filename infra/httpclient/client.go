@@ -0,0 +1,234 @@
+// Package httpclient provides an HTTP client for outbound provider calls with
+// a per-attempt timeout, bounded retries with exponential backoff and a
+// circuit breaker.
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned without calling the upstream while the circuit
+// breaker is open.
+var ErrCircuitOpen = errors.New("circuit breaker open")
+
+// Config controls timeouts, retries and the circuit breaker.
+type Config struct {
+	// Timeout bounds each attempt. Zero means no timeout.
+	Timeout time.Duration
+	// MaxRetries is the number of retries after the first attempt.
+	MaxRetries int
+	// Backoff is the wait before the first retry; it doubles on each retry.
+	Backoff time.Duration
+	// MaxBackoff caps the wait between attempts. A Retry-After longer than
+	// this stops retrying.
+	MaxBackoff time.Duration
+	// BreakerThreshold is the number of consecutive failed calls that opens
+	// the circuit. Zero disables the breaker.
+	BreakerThreshold int
+	// BreakerCooldown is how long the circuit stays open.
+	BreakerCooldown time.Duration
+}
+
+// Client retries requests that fail with a network error, a 5xx or a 429.
+// Only requests whose body can be replayed are retried.
+type Client struct {
+	http   *http.Client
+	cfg    Config
+	logger *slog.Logger
+
+	mu        sync.Mutex
+	failures  int
+	openUntil time.Time
+
+	now   func() time.Time
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithHTTPClient sets the underlying client, e.g. to use a custom transport.
+// Its Timeout is overridden by Config.Timeout.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) { c.http = hc }
+}
+
+// New creates a Client.
+func New(cfg Config, logger *slog.Logger, opts ...Option) *Client {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	c := &Client{
+		http:   &http.Client{},
+		cfg:    cfg,
+		logger: logger,
+		now:    time.Now,
+		sleep:  sleepContext,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	c.http.Timeout = cfg.Timeout
+	return c
+}
+
+// Do sends req, retrying as configured. The returned response is the last
+// one received, which may still be a 5xx once retries are exhausted.
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	if err := c.allow(); err != nil {
+		return nil, err
+	}
+	ctx := req.Context()
+	replayable := req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+
+	for attempt := 0; ; attempt++ {
+		attemptReq, err := cloneRequest(ctx, req, attempt)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := c.http.Do(attemptReq)
+		if !shouldRetry(resp, err) || ctx.Err() != nil {
+			c.record(resp, err)
+			return resp, err
+		}
+
+		wait, ok := c.backoff(attempt, resp)
+		if !ok || !replayable || attempt >= c.cfg.MaxRetries {
+			c.record(resp, err)
+			return resp, err
+		}
+		c.logger.Warn("Retrying HTTP request",
+			"method", req.Method,
+			"url", req.URL.Redacted(),
+			"attempt", attempt+1,
+			"wait", wait,
+			"status", statusCode(resp),
+			"error", err,
+		)
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, resp.Body)
+			_ = resp.Body.Close()
+		}
+		if err := c.sleep(ctx, wait); err != nil {
+			c.record(nil, err)
+			return nil, err
+		}
+	}
+}
+
+// backoff returns how long to wait before retrying after attempt. A
+// Retry-After header takes precedence; ok is false if it asks for longer
+// than MaxBackoff.
+func (c *Client) backoff(attempt int, resp *http.Response) (time.Duration, bool) {
+	wait := c.cfg.Backoff << attempt
+	if c.cfg.MaxBackoff > 0 && (wait > c.cfg.MaxBackoff || wait < 0) {
+		wait = c.cfg.MaxBackoff
+	}
+	if resp == nil {
+		return wait, true
+	}
+	retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After"), c.now())
+	if !ok {
+		return wait, true
+	}
+	if c.cfg.MaxBackoff > 0 && retryAfter > c.cfg.MaxBackoff {
+		return 0, false
+	}
+	return retryAfter, true
+}
+
+// allow returns ErrCircuitOpen while the breaker is open.
+func (c *Client) allow() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.now().Before(c.openUntil) {
+		return fmt.Errorf("%w until %s", ErrCircuitOpen, c.openUntil.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// record updates the breaker with the outcome of a call. Once open, a single
+// failure after the cooldown reopens it.
+func (c *Client) record(resp *http.Response, err error) {
+	if c.cfg.BreakerThreshold <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !shouldRetry(resp, err) || errors.Is(err, context.Canceled) {
+		c.failures = 0
+		return
+	}
+	c.failures++
+	if c.failures >= c.cfg.BreakerThreshold {
+		c.openUntil = c.now().Add(c.cfg.BreakerCooldown)
+		c.logger.Error("Circuit breaker opened",
+			"consecutive_failures", c.failures,
+			"cooldown", c.cfg.BreakerCooldown,
+		)
+	}
+}
+
+func cloneRequest(ctx context.Context, req *http.Request, attempt int) (*http.Request, error) {
+	if attempt == 0 {
+		return req, nil
+	}
+	clone := req.Clone(ctx)
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, fmt.Errorf("failed to replay request body: %w", err)
+		}
+		clone.Body = body
+	}
+	return clone, nil
+}
+
+func shouldRetry(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	return resp.StatusCode >= http.StatusInternalServerError ||
+		resp.StatusCode == http.StatusTooManyRequests
+}
+
+// parseRetryAfter parses a Retry-After header given in seconds or as an
+// HTTP date.
+func parseRetryAfter(v string, now time.Time) (time.Duration, bool) {
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return max(t.Sub(now), 0), true
+	}
+	return 0, false
+}
+
+func statusCode(resp *http.Response) int {
+	if resp == nil {
+		return 0
+	}
+	return resp.StatusCode
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -0,0 +1,167 @@
+package httpclient
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestClient returns a client that records waits instead of sleeping.
+func newTestClient(cfg Config) (*Client, *[]time.Duration) {
+	c := New(cfg, nil)
+	var waits []time.Duration
+	c.sleep = func(_ context.Context, d time.Duration) error {
+		waits = append(waits, d)
+		return nil
+	}
+	return c, &waits
+}
+
+func get(t *testing.T, c *Client, url string) (*http.Response, error) {
+	t.Helper()
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, url, nil)
+	require.NoError(t, err)
+	resp, err := c.Do(req)
+	if resp != nil {
+		t.Cleanup(func() { _ = resp.Body.Close() })
+	}
+	return resp, err
+}
+
+func TestClient_RetriesServerErrors(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	c, waits := newTestClient(Config{MaxRetries: 3, Backoff: 100 * time.Millisecond})
+	resp, err := get(t, c, srv.URL)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, int32(3), calls.Load())
+	assert.Equal(t, []time.Duration{100 * time.Millisecond, 200 * time.Millisecond}, *waits)
+}
+
+func TestClient_DoesNotRetryClientErrors(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	c, _ := newTestClient(Config{MaxRetries: 3})
+	resp, err := get(t, c, srv.URL)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestClient_RespectsRetryAfter(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.Header().Set("Retry-After", "2")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	c, waits := newTestClient(Config{
+		MaxRetries: 1, Backoff: time.Millisecond, MaxBackoff: 5 * time.Second,
+	})
+	resp, err := get(t, c, srv.URL)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, []time.Duration{2 * time.Second}, *waits)
+
+	// A Retry-After beyond MaxBackoff is returned to the caller as is.
+	calls.Store(0)
+	c, waits = newTestClient(Config{MaxRetries: 1, MaxBackoff: time.Second})
+	resp, err = get(t, c, srv.URL)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	assert.Empty(t, *waits)
+}
+
+func TestClient_ReplaysBody(t *testing.T) {
+	var bodies []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(b))
+		if len(bodies) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	c, _ := newTestClient(Config{MaxRetries: 1})
+	req, err := http.NewRequestWithContext(
+		context.Background(), http.MethodPost, srv.URL, strings.NewReader("payload"),
+	)
+	require.NoError(t, err)
+	resp, err := c.Do(req)
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, []string{"payload", "payload"}, bodies)
+}
+
+func TestClient_CircuitBreaker(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	now := time.Now()
+	c, _ := newTestClient(Config{BreakerThreshold: 2, BreakerCooldown: time.Minute})
+	c.now = func() time.Time { return now }
+
+	for range 2 {
+		_, err := get(t, c, srv.URL)
+		require.NoError(t, err)
+	}
+	_, err := get(t, c, srv.URL)
+	require.ErrorIs(t, err, ErrCircuitOpen)
+	assert.Equal(t, int32(2), calls.Load())
+
+	// After the cooldown one request is let through; it fails and reopens.
+	now = now.Add(time.Minute)
+	_, err = get(t, c, srv.URL)
+	require.NoError(t, err)
+	_, err = get(t, c, srv.URL)
+	require.ErrorIs(t, err, ErrCircuitOpen)
+	assert.Equal(t, int32(3), calls.Load())
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	d, ok := parseRetryAfter("3", now)
+	assert.True(t, ok)
+	assert.Equal(t, 3*time.Second, d)
+
+	d, ok = parseRetryAfter(now.Add(10*time.Second).Format(http.TimeFormat), now)
+	assert.True(t, ok)
+	assert.Equal(t, 10*time.Second, d)
+
+	_, ok = parseRetryAfter("soon", now)
+	assert.False(t, ok)
+}
@@ -7,22 +7,26 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
-	"strings"
 	"time"
 
 	"github.com/amirasaad/fintech/infra"
 	"github.com/amirasaad/fintech/infra/caching"
 	infra_eventbus "github.com/amirasaad/fintech/infra/eventbus"
+	"github.com/amirasaad/fintech/infra/provider/analyticssink"
 	exchangerateapi "github.com/amirasaad/fintech/infra/provider/exchangerateapi"
+	"github.com/amirasaad/fintech/infra/provider/manualpayout"
 	stripepayment "github.com/amirasaad/fintech/infra/provider/stripepayment"
 	infra_repository "github.com/amirasaad/fintech/infra/repository"
 	currencyfixtures "github.com/amirasaad/fintech/internal/fixtures/currency"
 	"github.com/amirasaad/fintech/pkg/app"
 	"github.com/amirasaad/fintech/pkg/config"
-	"github.com/amirasaad/fintech/pkg/eventbus"
+	"github.com/amirasaad/fintech/pkg/provider/analytics"
 	"github.com/amirasaad/fintech/pkg/provider/exchange"
+	"github.com/amirasaad/fintech/pkg/provider/payment"
 
 	"github.com/amirasaad/fintech/pkg/registry"
+	"github.com/amirasaad/fintech/pkg/validation"
+	"github.com/redis/go-redis/v9"
 )
 
 // loadCurrencyFixtures loads currency metadata into the registry.
@@ -74,6 +78,29 @@ func loadCurrencyFixtures(
 	logger.Info("Successfully loaded currency fixtures", "registered_count", registeredCount)
 }
 
+// NewCurrencyRegistry creates the currency registry provider, loading the
+// currency metadata fixtures if it is empty.
+func NewCurrencyRegistry(cfg *config.App, logger *slog.Logger) (registry.Provider, error) {
+	provider, err := GetCurrencyRegistry(cfg, logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize currency registry provider: %w", err)
+	}
+
+	ctx := context.Background()
+	// Only load currency fixtures if the registry is empty
+	count, err := provider.Count(ctx)
+	if err != nil {
+		logger.Warn("Failed to check currency registry count", "error", err)
+	}
+
+	if count == 0 {
+		loadCurrencyFixtures(ctx, provider, logger)
+	} else {
+		logger.Info("Skipping currency fixtures load; registry not empty", "existing_count", count)
+	}
+	return provider, nil
+}
+
 // InitializeDependencies initializes all the application dependencies
 func InitializeDependencies(cfg *config.App) (
 	deps *app.Deps,
@@ -91,23 +118,12 @@ func InitializeDependencies(cfg *config.App) (
 	}
 
 	// Initialize currency registry with dedicated provider
-	deps.CurrencyRegistry, err = GetCurrencyRegistry(cfg, logger)
+	deps.CurrencyRegistry, err = NewCurrencyRegistry(cfg, logger)
 	if err != nil {
-		return nil, fmt.Errorf("failed to initialize currency registry provider: %w", err)
+		return nil, err
 	}
 
 	ctx := context.Background()
-	// Only load currency fixtures if the registry is empty
-	count, err := deps.CurrencyRegistry.Count(ctx)
-	if err != nil {
-		logger.Warn("Failed to check currency registry count", "error", err)
-	}
-
-	if count == 0 {
-		loadCurrencyFixtures(ctx, deps.CurrencyRegistry, logger)
-	} else {
-		logger.Info("Skipping currency fixtures load; registry not empty", "existing_count", count)
-	}
 
 	// Initialize checkout registry
 	deps.CheckoutRegistry, err = GetCheckoutRegistry(cfg, logger)
@@ -115,6 +131,12 @@ func InitializeDependencies(cfg *config.App) (
 		return nil, fmt.Errorf("failed to initialize checkout registry provider: %w", err)
 	}
 
+	// Initialize feature flag registry
+	deps.FeatureFlagRegistry, err = GetFeatureFlagRegistry(cfg, logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize feature flag registry provider: %w", err)
+	}
+
 	// Initialize exchange rate registry
 	deps.ExchangeRateRegistry, err = GetExchangeRateRegistry(cfg, logger)
 	if err != nil {
@@ -122,10 +144,21 @@ func InitializeDependencies(cfg *config.App) (
 	}
 
 	// Create the exchange rate provider
-	exchangeProvider := exchangerateapi.NewExchangeRateAPIProvider(
+	var exchangeProvider exchange.Exchange = exchangerateapi.NewExchangeRateAPIProvider(
 		cfg.ExchangeRateAPIProviders.ExchangeRateApi,
 		logger,
 	)
+	// Count provider calls against the quota, falling back to cached rates
+	// before it runs out
+	if ex := cfg.Exchange; ex != nil && ex.QuotaLimit > 0 {
+		deps.ExchangeRateQuota = exchange.NewQuotaProvider(
+			exchangeProvider,
+			ex.QuotaLimit,
+			ex.QuotaSoftLimit,
+			ex.QuotaResetDay,
+		)
+		exchangeProvider = deps.ExchangeRateQuota
+	}
 	deps.ExchangeRateProvider = exchangeProvider
 
 	// Initialize exchange rates
@@ -147,229 +180,189 @@ func InitializeDependencies(cfg *config.App) (
 		return nil, err
 	}
 
+	if sqlDB, dberr := db.DB(); dberr == nil {
+		deps.DBPool = sqlDB
+	}
+	for _, replica := range infra.ReadReplicaPools(db) {
+		deps.DBReplicaPools = append(deps.DBReplicaPools, replica)
+	}
+
 	// Initialize unit of work
 	deps.Uow = infra_repository.NewUoW(db)
 
 	// Initialize event bus
-	bus, err := initEventBus(cfg, logger)
+	bus, err := infra_eventbus.NewFromConfig(cfg, logger)
 	if err != nil {
 		return nil, err
 	}
 	deps.EventBus = bus
 
+	if stripeCfg := cfg.PaymentProviders.Stripe; stripeCfg != nil {
+		if err = validation.ValidateStatementDescriptor(
+			stripeCfg.DefaultStatementDescriptor(),
+		); err != nil {
+			return nil, fmt.Errorf("PAYMENT_PROVIDER_STRIPE_STATEMENT_DESCRIPTOR: %w", err)
+		}
+	}
+
 	// Initialize payment provider with the checkout registry and unit of work
-	deps.PaymentProvider = stripepayment.New(
+	stripeProvider := stripepayment.New(
 		bus,
 		deps.CheckoutRegistry, // Use the checkout-specific registry
 		cfg.PaymentProviders.Stripe,
 		logger,
 		deps.Uow, // Pass the repository's UnitOfWork
 	)
+	deps.PaymentProvider = stripeProvider
+	deps.PayoutProvider = newPayoutProvider(cfg.Withdraw, stripeProvider, logger)
+	deps.AnalyticsSink, deps.AnalyticsDLQ = newAnalyticsSinks(cfg.Analytics, logger)
+	if txCache := newTransactionCache(cfg, logger); txCache != nil {
+		deps.TransactionCache = txCache
+	}
+	if locker := newAccountLocker(cfg, logger); locker != nil {
+		deps.AccountLocker = locker
+	}
+	maintenanceStore, err := newMaintenanceStore(cfg, logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize maintenance store: %w", err)
+	}
+	if maintenanceStore != nil {
+		deps.MaintenanceStore = maintenanceStore
+	}
+	idempotencyStore, err := newIdempotencyStore(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize idempotency store: %w", err)
+	}
+	if idempotencyStore != nil {
+		deps.IdempotencyStore = idempotencyStore
+	}
 
 	return
 }
 
-func initEventBus(cfg *config.App, logger *slog.Logger) (eventbus.Bus, error) {
-	explicitDriver := ""
-	if cfg.EventBus != nil {
-		explicitDriver = strings.TrimSpace(cfg.EventBus.Driver)
+// newTransactionCache returns the Redis cache of account transaction lists,
+// or nil when it is disabled or Redis cannot be reached, in which case
+// transactions are read from the database.
+func newTransactionCache(cfg *config.App, logger *slog.Logger) *caching.TransactionCache {
+	if cfg.TransactionCache == nil || !cfg.TransactionCache.Enabled {
+		return nil
 	}
-
-	if explicitDriver == "" {
-		return infra_eventbus.NewWithMemoryAsync(logger), nil
+	if cfg.Redis == nil || cfg.Redis.URL == "" {
+		logger.Warn("TRANSACTION_CACHE_ENABLED requires REDIS_URL; transactions are not cached")
+		return nil
 	}
-
-	driver := strings.TrimSpace(strings.ToLower(explicitDriver))
-	switch driver {
-	case "memory":
-		return infra_eventbus.NewWithMemoryAsync(logger), nil
-	case "redis":
-		redisURL := ""
-		if cfg.EventBus != nil {
-			redisURL = strings.TrimSpace(cfg.EventBus.RedisURL)
-		}
-		if redisURL == "" && cfg.Redis != nil {
-			redisURL = strings.TrimSpace(cfg.Redis.URL)
-		}
-		if redisURL == "" {
-			return nil, fmt.Errorf("event bus redis: redis url is required")
-		}
-		busConfig := &infra_eventbus.RedisEventBusConfig{
-			DLQRetryInterval: 5 * time.Minute,
-			DLQBatchSize:     10,
-		}
-		bus, err := infra_eventbus.NewWithRedis(redisURL, logger, busConfig)
-		if err != nil {
-			logger.Warn("Redis event bus init failed, falling back to memory async", "error", err)
-			return infra_eventbus.NewWithMemoryAsync(logger), nil
-		}
-		return bus, nil
-	case "kafka":
-		if cfg.EventBus == nil {
-			return nil, fmt.Errorf("event bus kafka: configuration is required")
-		}
-		brokers := strings.TrimSpace(cfg.EventBus.KafkaBrokers)
-		if brokers == "" {
-			return nil, fmt.Errorf("event bus kafka: brokers are required")
-		}
-		caFilePath, err := ensureKafkaCAFile(cfg, logger)
-		if err != nil {
-			return nil, fmt.Errorf("event bus kafka: prepare tls ca file: %w", err)
-		}
-		certFilePath, err := ensureKafkaTLSCertFile(cfg, logger)
-		if err != nil {
-			return nil, fmt.Errorf("event bus kafka: prepare tls cert file: %w", err)
-		}
-		keyFilePath, err := ensureKafkaTLSKeyFile(cfg, logger)
-		if err != nil {
-			return nil, fmt.Errorf("event bus kafka: prepare tls key file: %w", err)
-		}
-		tlsCertSet := strings.TrimSpace(certFilePath) != ""
-		tlsKeySet := strings.TrimSpace(keyFilePath) != ""
-		if cfg.EventBus.KafkaTLSEnabled && tlsCertSet != tlsKeySet {
-			return nil, fmt.Errorf("event bus kafka: tls cert and key must be provided together")
-		}
-		saslUsernameSet := strings.TrimSpace(cfg.EventBus.KafkaSASLUsername) != ""
-		saslPasswordSet := strings.TrimSpace(cfg.EventBus.KafkaSASLPassword) != ""
-		tlsCaProvided := strings.TrimSpace(caFilePath) != ""
-		tlsInputsProvided := tlsCaProvided ||
-			tlsCertSet ||
-			tlsKeySet ||
-			cfg.EventBus.KafkaTLSSkipVerify
-
-		brokerCount := 0
-		for _, broker := range strings.Split(brokers, ",") {
-			if strings.TrimSpace(broker) != "" {
-				brokerCount++
-			}
-		}
-
-		if !cfg.EventBus.KafkaTLSEnabled && tlsInputsProvided {
-			logger.Warn("Kafka TLS settings provided but TLS disabled",
-				"tls_enabled", cfg.EventBus.KafkaTLSEnabled,
-				"tls_ca_file", strings.TrimSpace(caFilePath),
-				"tls_cert_file_set", tlsCertSet,
-				"tls_key_file_set", tlsKeySet,
-				"tls_skip_verify", cfg.EventBus.KafkaTLSSkipVerify,
-			)
-		}
-		logger.Info("Initializing Kafka event bus",
-			"brokers", brokers,
-			"brokers_count", brokerCount,
-			"group_id", strings.TrimSpace(cfg.EventBus.KafkaGroupID),
-			"topic_prefix", strings.TrimSpace(cfg.EventBus.KafkaTopic),
-			"tls_enabled", cfg.EventBus.KafkaTLSEnabled,
-			"tls_ca_file", strings.TrimSpace(caFilePath),
-			"tls_cert_file_set", tlsCertSet,
-			"tls_key_file_set", tlsKeySet,
-			"tls_skip_verify", cfg.EventBus.KafkaTLSSkipVerify,
-			"sasl_username_set", saslUsernameSet,
-			"sasl_password_set", saslPasswordSet,
-		)
-		kafkaConfig := &infra_eventbus.KafkaEventBusConfig{
-			GroupID:          strings.TrimSpace(cfg.EventBus.KafkaGroupID),
-			TopicPrefix:      strings.TrimSpace(cfg.EventBus.KafkaTopic),
-			DLQRetryInterval: 5 * time.Minute,
-			DLQBatchSize:     10,
-			SASLUsername:     strings.TrimSpace(cfg.EventBus.KafkaSASLUsername),
-			SASLPassword:     strings.TrimSpace(cfg.EventBus.KafkaSASLPassword),
-			TLSEnabled:       cfg.EventBus.KafkaTLSEnabled,
-			TLSCAFile:        strings.TrimSpace(caFilePath),
-			TLSCertFile:      strings.TrimSpace(certFilePath),
-			TLSKeyFile:       strings.TrimSpace(keyFilePath),
-			TLSSkipVerify:    cfg.EventBus.KafkaTLSSkipVerify,
-		}
-		bus, err := infra_eventbus.NewWithKafka(brokers, logger, kafkaConfig)
-		if err != nil {
-			logger.Warn("Kafka event bus init failed, falling back to memory async", "error", err)
-			return infra_eventbus.NewWithMemoryAsync(logger), nil
-		}
-		return bus, nil
-	default:
-		return nil, fmt.Errorf("unsupported event bus driver: %s", driver)
+	client, err := registry.NewRedisClient(cfg.Redis.URL)
+	if err != nil {
+		logger.Warn("Transaction cache disabled", "error", err)
+		return nil
 	}
+	logger.Info("Transaction cache enabled", "ttl", cfg.TransactionCache.TTL)
+	return caching.NewTransactionCache(
+		client,
+		cfg.Redis.KeyPrefix,
+		cfg.TransactionCache.TTL,
+	)
 }
 
-func ensureKafkaTLSFile(
-	pem string,
-	tempPattern string,
-	pemLabel string,
-	fileLabel string,
-	logMessage string,
-	logger *slog.Logger,
-) (string, error) {
-	pem = strings.TrimSpace(pem)
-	if pem == "" {
-		return "", nil
+// newAccountLocker returns the Redis lock of accounts, or nil when it is not
+// distributed or Redis cannot be reached, in which case accounts are locked
+// within the process.
+func newAccountLocker(cfg *config.App, logger *slog.Logger) *caching.AccountLocker {
+	if cfg.AccountLock == nil || !cfg.AccountLock.Distributed {
+		return nil
 	}
-
-	pem = strings.ReplaceAll(pem, "\\n", "\n")
-	if strings.TrimSpace(pem) == "" {
-		return "", fmt.Errorf("%s is empty", pemLabel)
+	if cfg.Redis == nil || cfg.Redis.URL == "" {
+		logger.Warn("ACCOUNT_LOCK_DISTRIBUTED requires REDIS_URL; accounts are locked per instance")
+		return nil
 	}
-
-	tmpfile, err := os.CreateTemp("", tempPattern)
+	client, err := registry.NewRedisClient(cfg.Redis.URL)
 	if err != nil {
-		return "", fmt.Errorf("create temp %s file: %w", fileLabel, err)
+		logger.Warn("Distributed account lock disabled", "error", err)
+		return nil
 	}
-	if err := tmpfile.Close(); err != nil {
-		return "", fmt.Errorf("close temp %s file: %w", fileLabel, err)
-	}
-	path := tmpfile.Name()
+	logger.Info("Distributed account lock enabled", "ttl", cfg.AccountLock.TTL)
+	return caching.NewAccountLocker(client, cfg.Redis.KeyPrefix, cfg.AccountLock.TTL)
+}
 
-	if err := os.WriteFile(path, []byte(pem), 0600); err != nil {
-		return "", fmt.Errorf("write %s file: %w", fileLabel, err)
+// newMaintenanceStore returns the Redis store of the maintenance switch, or
+// nil without REDIS_URL, in which case each instance keeps its own. Redis is
+// not pinged: while it cannot be reached the switch cannot be read and money
+// movements are rejected, rather than allowed by an in-memory fallback.
+func newMaintenanceStore(cfg *config.App, logger *slog.Logger) (*caching.MaintenanceStore, error) {
+	if cfg.Redis == nil || cfg.Redis.URL == "" {
+		logger.Warn("Maintenance mode is kept per instance without REDIS_URL")
+		return nil, nil
 	}
-	if logger != nil && logMessage != "" {
-		logger.Info(logMessage, "path", path)
+	client, err := newUncheckedRedisClient(cfg.Redis.URL)
+	if err != nil {
+		return nil, err
 	}
-
-	return path, nil
+	return caching.NewMaintenanceStore(client, cfg.Redis.KeyPrefix), nil
 }
 
-func ensureKafkaCAFile(cfg *config.App, logger *slog.Logger) (string, error) {
-	if cfg == nil || cfg.EventBus == nil {
-		return "", nil
+// newIdempotencyStore returns the Redis store of command idempotency keys, or
+// nil without REDIS_URL, in which case each instance keeps its own. As with
+// the maintenance switch, Redis is not pinged: while it cannot be reached,
+// commands with an idempotency key fail rather than risk running twice.
+func newIdempotencyStore(cfg *config.App) (*caching.IdempotencyStore, error) {
+	if cfg.Redis == nil || cfg.Redis.URL == "" {
+		return nil, nil
 	}
-
-	return ensureKafkaTLSFile(
-		cfg.EventBus.KafkaTLSCAPem,
-		"fintech-kafka-ca-*.pem",
-		"kafka ca pem",
-		"kafka ca",
-		"Kafka CA file written",
-		logger,
-	)
+	client, err := newUncheckedRedisClient(cfg.Redis.URL)
+	if err != nil {
+		return nil, err
+	}
+	return caching.NewIdempotencyStore(client, cfg.Redis.KeyPrefix), nil
 }
 
-func ensureKafkaTLSCertFile(cfg *config.App, logger *slog.Logger) (string, error) {
-	if cfg == nil || cfg.EventBus == nil {
-		return "", nil
+// newUncheckedRedisClient creates a Redis client without checking that Redis
+// can be reached, for stores that report its failures on every call.
+func newUncheckedRedisClient(url string) (*redis.Client, error) {
+	opt, err := redis.ParseURL(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse Redis URL: %w", err)
 	}
+	return redis.NewClient(opt), nil
+}
 
-	return ensureKafkaTLSFile(
-		cfg.EventBus.KafkaTLSCertPem,
-		"fintech-kafka-cert-*.pem",
-		"kafka tls cert pem",
-		"kafka tls cert",
-		"Kafka TLS cert file written",
-		logger,
+// newAnalyticsSinks returns the sink analytics events are exported to and
+// the dead letter file for batches it rejects; both nil when analytics
+// export is disabled.
+func newAnalyticsSinks(
+	cfg *config.Analytics,
+	logger *slog.Logger,
+) (sink, dlq analytics.Sink) {
+	if cfg == nil || !cfg.Enabled {
+		return nil, nil
+	}
+	switch cfg.Sink {
+	case config.AnalyticsSinkFile:
+		sink = analyticssink.NewFile(cfg.FilePath)
+	default:
+		sink = analyticssink.NewHTTP(cfg.URL, cfg.Token, nil)
+	}
+	if cfg.DLQPath != "" {
+		dlq = analyticssink.NewFile(cfg.DLQPath)
+	}
+	logger.Info("Analytics export enabled",
+		"sink", cfg.Sink,
+		"event_types", cfg.EventTypes,
 	)
+	return sink, dlq
 }
 
-func ensureKafkaTLSKeyFile(cfg *config.App, logger *slog.Logger) (string, error) {
-	if cfg == nil || cfg.EventBus == nil {
-		return "", nil
+// newPayoutProvider returns the provider configured to pay withdrawals out,
+// Stripe unless WITHDRAW_PAYOUT_PROVIDER is manual.
+func newPayoutProvider(
+	cfg *config.Withdraw,
+	stripeProvider payment.Payout,
+	logger *slog.Logger,
+) payment.Payout {
+	if cfg != nil && cfg.PayoutProvider == config.PayoutProviderManual {
+		logger.Info("Withdrawals are recorded for manual payout")
+		return manualpayout.New(logger)
 	}
-
-	return ensureKafkaTLSFile(
-		cfg.EventBus.KafkaTLSKeyPem,
-		"fintech-kafka-key-*.pem",
-		"kafka tls key pem",
-		"kafka tls key",
-		"Kafka TLS key file written",
-		logger,
-	)
+	return stripeProvider
 }
 
 // initializeExchangeRates fetches and caches exchange rates during application startup
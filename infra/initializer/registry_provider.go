@@ -91,6 +91,26 @@ func GetCheckoutRegistry(cfg *config.App, logger *slog.Logger) (registry.Provide
 	return GetRegistryProvider(registryCfg, logger)
 }
 
+// GetFeatureFlagRegistry creates a registry provider for feature flags. With
+// Redis configured, flag changes are shared by every instance.
+func GetFeatureFlagRegistry(cfg *config.App, logger *slog.Logger) (registry.Provider, error) {
+	keyPrefix := ""
+	if cfg.Redis != nil {
+		keyPrefix = cfg.Redis.KeyPrefix
+	}
+
+	return GetRegistryProvider(
+		&RegistryConfig{
+			Name:      "feature_flag",
+			RedisURL:  cfg.Redis.URL,
+			KeyPrefix: keyPrefix + "feature_flag:",
+			CacheSize: 100,
+			CacheTTL:  -1, // Flags change only through the admin API
+		},
+		logger,
+	)
+}
+
 // GetExchangeRateRegistry creates a registry provider for the exchange rate service
 func GetExchangeRateRegistry(cfg *config.App, logger *slog.Logger) (registry.Provider, error) {
 	if cfg.ExchangeRateCache == nil {
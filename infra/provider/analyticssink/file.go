@@ -0,0 +1,53 @@
+package analyticssink
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/amirasaad/fintech/pkg/provider/analytics"
+)
+
+// FileSink appends each event as one JSON line to a file, e.g. one shipped
+// to object storage by a log forwarder. It also serves as the dead letter
+// file for batches no sink accepted.
+type FileSink struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewFile creates a sink appending to the file at path, creating it if
+// needed.
+func NewFile(path string) *FileSink {
+	return &FileSink{path: path}
+}
+
+// Send appends the batch to the file.
+func (s *FileSink) Send(_ context.Context, events []analytics.Event) (err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := os.OpenFile(s.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open analytics file: %w", err)
+	}
+	defer func() {
+		if cerr := f.Close(); err == nil && cerr != nil {
+			err = fmt.Errorf("failed to close analytics file: %w", cerr)
+		}
+	}()
+	w := bufio.NewWriter(f)
+	enc := json.NewEncoder(w)
+	for _, e := range events {
+		if err := enc.Encode(e); err != nil {
+			return fmt.Errorf("failed to write analytics event: %w", err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("failed to write analytics file: %w", err)
+	}
+	return nil
+}
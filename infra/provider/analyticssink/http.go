@@ -0,0 +1,62 @@
+// Package analyticssink provides the sinks product analytics events are
+// exported to: an HTTP collector and a JSON lines file.
+package analyticssink
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/amirasaad/fintech/pkg/provider/analytics"
+)
+
+// defaultHTTPTimeout bounds one delivery to the collector.
+const defaultHTTPTimeout = 10 * time.Second
+
+// HTTPSink posts each batch as a JSON object {"events": [...]} to a URL.
+type HTTPSink struct {
+	url    string
+	token  string
+	client *http.Client
+}
+
+// NewHTTP creates a sink posting to url. A non-empty token is sent as a
+// bearer token. A nil client uses one with a 10 second timeout.
+func NewHTTP(url, token string, client *http.Client) *HTTPSink {
+	if client == nil {
+		client = &http.Client{Timeout: defaultHTTPTimeout}
+	}
+	return &HTTPSink{url: url, token: token, client: client}
+}
+
+// Send posts the batch. Any response other than 2xx is an error.
+func (s *HTTPSink) Send(ctx context.Context, events []analytics.Event) error {
+	body, err := json.Marshal(struct {
+		Events []analytics.Event `json:"events"`
+	}{Events: events})
+	if err != nil {
+		return fmt.Errorf("failed to encode analytics batch: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create analytics request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.token)
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send analytics batch: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("analytics sink responded %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package analyticssink
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/amirasaad/fintech/pkg/provider/analytics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var batch = []analytics.Event{
+	{ID: "1", Type: "Deposit.Requested", Data: map[string]any{"AccountID": "a"}},
+	{ID: "2", Type: "Payment.Completed", Data: map[string]any{"AccountID": "b"}},
+}
+
+func TestHTTPSink_Send(t *testing.T) {
+	var got struct {
+		Events []analytics.Event `json:"events"`
+	}
+	var auth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	err := NewHTTP(srv.URL, "tok", srv.Client()).Send(context.Background(), batch)
+
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer tok", auth)
+	require.Len(t, got.Events, 2)
+	assert.Equal(t, "Payment.Completed", got.Events[1].Type)
+}
+
+func TestHTTPSink_Send_ErrorStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	err := NewHTTP(srv.URL, "", srv.Client()).Send(context.Background(), batch)
+
+	assert.ErrorContains(t, err, "503")
+}
+
+func TestFileSink_Send_Appends(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	sink := NewFile(path)
+
+	require.NoError(t, sink.Send(context.Background(), batch))
+	require.NoError(t, sink.Send(context.Background(), batch[:1]))
+
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer func() { _ = f.Close() }()
+	var ids []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var e analytics.Event
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &e))
+		ids = append(ids, e.ID)
+	}
+	assert.Equal(t, []string{"1", "2", "1"}, ids)
+}
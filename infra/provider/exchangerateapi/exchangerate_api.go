@@ -3,12 +3,14 @@ package exchangerateapi
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
 	"time"
 
+	"github.com/amirasaad/fintech/infra/httpclient"
 	"github.com/amirasaad/fintech/pkg/config"
 	"github.com/amirasaad/fintech/pkg/provider/exchange"
 )
@@ -17,7 +19,7 @@ import (
 type exchangeRateAPI struct {
 	apiKey     string
 	baseURL    string
-	httpClient *http.Client
+	httpClient *httpclient.Client
 	logger     *slog.Logger
 	timeout    time.Duration
 }
@@ -62,9 +64,14 @@ func NewExchangeRateAPIProvider(
 	return &exchangeRateAPI{
 		apiKey:  cfg.ApiKey,
 		baseURL: fmt.Sprintf("%s/%s", cfg.ApiUrl, cfg.ApiKey),
-		httpClient: &http.Client{
-			Timeout: cfg.HTTPTimeout,
-		},
+		httpClient: httpclient.New(httpclient.Config{
+			Timeout:          cfg.HTTPTimeout,
+			MaxRetries:       cfg.MaxRetries,
+			Backoff:          cfg.RetryBackoff,
+			MaxBackoff:       cfg.RetryMaxBackoff,
+			BreakerThreshold: cfg.BreakerThreshold,
+			BreakerCooldown:  cfg.BreakerCooldown,
+		}, logger),
 		logger:  logger,
 		timeout: cfg.HTTPTimeout,
 	}
@@ -79,7 +86,7 @@ func (p *exchangeRateAPI) FetchRate(
 	// For now, we'll assume a simple call to the base URL with the API key
 	url := fmt.Sprintf("%s/%s/%s", p.baseURL, "latest", from)
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -90,7 +97,7 @@ func (p *exchangeRateAPI) FetchRate(
 
 	resp, err := p.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to make request: %w", err)
+		return nil, requestError(err)
 	}
 	defer func() {
 		if cerr := resp.Body.Close(); cerr != nil {
@@ -103,6 +110,11 @@ func (p *exchangeRateAPI) FetchRate(
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
+		var apiErr ExchangeRateAPIResponseV6
+		if json.Unmarshal(body, &apiErr) == nil &&
+			apiErr.ErrorType == errorTypeUnsupportedCode {
+			return nil, fmt.Errorf("%w: %s to %s", exchange.ErrUnsupportedPair, from, to)
+		}
 		return nil, fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(body))
 	}
 
@@ -117,7 +129,9 @@ func (p *exchangeRateAPI) FetchRate(
 
 	rate, exists := apiResp.ConversionRates[to]
 	if !exists {
-		return nil, fmt.Errorf("rate for %s not found in response", to)
+		return nil, fmt.Errorf(
+			"%w: rate for %s not found in response", exchange.ErrUnsupportedPair, to,
+		)
 	}
 
 	return &exchange.RateInfo{
@@ -149,7 +163,7 @@ func (p *exchangeRateAPI) FetchRates(
 	// Execute the request
 	resp, err := p.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to make request: %w", err)
+		return nil, requestError(err)
 	}
 	defer func() {
 		if cerr := resp.Body.Close(); cerr != nil {
@@ -162,6 +176,12 @@ func (p *exchangeRateAPI) FetchRates(
 	if err != nil {
 		return nil, fmt.Errorf("failed to read response body: %w", err)
 	}
+	if resp.StatusCode >= http.StatusInternalServerError ||
+		resp.StatusCode == http.StatusTooManyRequests {
+		return nil, fmt.Errorf(
+			"%w: API returned status %d", exchange.ErrProviderUnavailable, resp.StatusCode,
+		)
+	}
 
 	// Parse the response
 	var apiResp ExchangeRateAPIResponseV6
@@ -173,7 +193,7 @@ func (p *exchangeRateAPI) FetchRates(
 	if apiResp.Result != "success" {
 		switch apiResp.ErrorType {
 		case errorTypeUnsupportedCode:
-			return nil, fmt.Errorf("unsupported currency code")
+			return nil, fmt.Errorf("%w: unsupported currency code %s", exchange.ErrUnsupportedPair, from)
 		case errorTypeMalformedRequest:
 			return nil, fmt.Errorf("malformed request")
 		case errorTypeInvalidKey:
@@ -181,7 +201,7 @@ func (p *exchangeRateAPI) FetchRates(
 		case errorTypeInactiveAccount:
 			return nil, fmt.Errorf("inactive account")
 		case errorTypeQuotaReached:
-			return nil, fmt.Errorf("API quota reached")
+			return nil, exchange.ErrRateProviderQuotaExhausted
 		case errorTypeUnknown, "":
 			fallthrough
 		default:
@@ -210,6 +230,15 @@ func (p *exchangeRateAPI) FetchRates(
 	return results, nil
 }
 
+// requestError wraps a failed call, reporting an open circuit as the provider
+// being unavailable.
+func requestError(err error) error {
+	if errors.Is(err, httpclient.ErrCircuitOpen) {
+		return fmt.Errorf("%w: %w", exchange.ErrProviderUnavailable, err)
+	}
+	return fmt.Errorf("failed to make request: %w", err)
+}
+
 // IsSupported checks if the provider supports the given currency pair
 func (p *exchangeRateAPI) IsSupported(from string, to string) bool {
 	// Basic validation to avoid panics; provider supports standard ISO-like codes.
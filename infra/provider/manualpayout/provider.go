@@ -0,0 +1,155 @@
+// Package manualpayout provides a payout provider that records withdrawals
+// for operators to pay out by hand, for regions where Stripe Connect is not
+// available.
+package manualpayout
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/amirasaad/fintech/pkg/provider/payment"
+)
+
+// Payout is a payout request recorded for manual processing.
+type Payout struct {
+	ID     string
+	Params payment.InitiatePayoutParams
+	Status payment.PaymentStatus
+	// RequestedAt is when the payout was requested.
+	RequestedAt time.Time
+}
+
+// Provider implements payment.Payout by recording payout requests instead of
+// sending money. Payouts stay pending until an operator pays them, e.g.
+// through the pain.001 export, and completes them. Requests are kept in
+// memory and logged; the withdrawal transactions remain the durable record.
+type Provider struct {
+	mu      sync.Mutex
+	payouts map[string]*Payout
+	logger  *slog.Logger
+}
+
+// New creates a manual payout provider.
+func New(logger *slog.Logger) *Provider {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Provider{
+		payouts: make(map[string]*Payout),
+		logger:  logger.With("provider", "manual_payout"),
+	}
+}
+
+// InitiatePayout implements payment.Payout. It records the payout as pending
+// under an ID derived from the transaction, so a retried request returns the
+// payout already recorded.
+func (p *Provider) InitiatePayout(
+	ctx context.Context,
+	params *payment.InitiatePayoutParams,
+) (*payment.InitiatePayoutResponse, error) {
+	id := "manual_" + params.TransactionID.String()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, ok := p.payouts[id]; !ok {
+		p.payouts[id] = &Payout{
+			ID:          id,
+			Params:      *params,
+			Status:      payment.PaymentPending,
+			RequestedAt: time.Now().UTC(),
+		}
+		p.logger.Info("Recorded payout for manual processing",
+			"payout_id", id,
+			"transaction_id", params.TransactionID,
+			"account_id", params.AccountID,
+			"amount", params.Amount,
+			"currency", params.Currency,
+			"destination_type", params.Destination.Type,
+		)
+	}
+	return &payment.InitiatePayoutResponse{
+		PayoutID:          id,
+		PaymentProviderID: params.PaymentProviderID,
+		Status:            payment.PaymentPending,
+		Amount:            params.Amount,
+		Currency:          params.Currency,
+		FeeCurrency:       params.Currency,
+	}, nil
+}
+
+// GetPayoutStatus implements payment.Payout.
+func (p *Provider) GetPayoutStatus(
+	ctx context.Context,
+	payoutID string,
+) (payment.PaymentStatus, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	payout, ok := p.payouts[payoutID]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", payment.ErrPayoutNotFound, payoutID)
+	}
+	return payout.Status, nil
+}
+
+// CancelPayout implements payment.Payout. Only pending payouts can be
+// cancelled.
+func (p *Provider) CancelPayout(ctx context.Context, payoutID string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	payout, err := p.pending(payoutID)
+	if err != nil {
+		return fmt.Errorf("%w: %w", payment.ErrPayoutNotCancellable, err)
+	}
+	payout.Status = payment.PaymentCancelled
+	p.logger.Info("Cancelled manual payout", "payout_id", payoutID)
+	return nil
+}
+
+// Complete marks a pending payout as paid once an operator has sent the
+// money.
+func (p *Provider) Complete(payoutID string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	payout, err := p.pending(payoutID)
+	if err != nil {
+		return err
+	}
+	payout.Status = payment.PaymentCompleted
+	p.logger.Info("Completed manual payout", "payout_id", payoutID)
+	return nil
+}
+
+// Pending returns the payouts awaiting manual processing, oldest first.
+func (p *Provider) Pending() []Payout {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var pending []Payout
+	for _, payout := range p.payouts {
+		if payout.Status == payment.PaymentPending {
+			pending = append(pending, *payout)
+		}
+	}
+	sort.Slice(pending, func(i, j int) bool {
+		return pending[i].RequestedAt.Before(pending[j].RequestedAt)
+	})
+	return pending
+}
+
+// pending returns the payout with the given ID if it is still pending. The
+// caller must hold p.mu.
+func (p *Provider) pending(payoutID string) (*Payout, error) {
+	payout, ok := p.payouts[payoutID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", payment.ErrPayoutNotFound, payoutID)
+	}
+	if payout.Status != payment.PaymentPending {
+		return nil, fmt.Errorf("payout %s is %s", payoutID, payout.Status)
+	}
+	return payout, nil
+}
+
+var _ payment.Payout = (*Provider)(nil)
@@ -0,0 +1,73 @@
+package manualpayout_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/amirasaad/fintech/infra/provider/manualpayout"
+	"github.com/amirasaad/fintech/pkg/provider/payment"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProvider(t *testing.T) {
+	ctx := context.Background()
+	p := manualpayout.New(nil)
+	params := &payment.InitiatePayoutParams{
+		TransactionID: uuid.New(),
+		Amount:        5000,
+		Currency:      "eur",
+		Destination: payment.PayoutDestination{
+			Type:        payment.PayoutDestinationBankAccount,
+			BankAccount: &payment.BankAccountDetails{AccountNumber: "DE89370400440532013000"},
+		},
+	}
+
+	resp, err := p.InitiatePayout(ctx, params)
+	require.NoError(t, err)
+	assert.Equal(t, payment.PaymentPending, resp.Status)
+	assert.Equal(t, int64(5000), resp.Amount)
+
+	// A retried request returns the payout already recorded.
+	again, err := p.InitiatePayout(ctx, params)
+	require.NoError(t, err)
+	assert.Equal(t, resp.PayoutID, again.PayoutID)
+
+	pending := p.Pending()
+	require.Len(t, pending, 1)
+	assert.Equal(t, params.TransactionID, pending[0].Params.TransactionID)
+
+	status, err := p.GetPayoutStatus(ctx, resp.PayoutID)
+	require.NoError(t, err)
+	assert.Equal(t, payment.PaymentPending, status)
+
+	require.NoError(t, p.Complete(resp.PayoutID))
+	status, err = p.GetPayoutStatus(ctx, resp.PayoutID)
+	require.NoError(t, err)
+	assert.Equal(t, payment.PaymentCompleted, status)
+	assert.Empty(t, p.Pending())
+
+	err = p.CancelPayout(ctx, resp.PayoutID)
+	require.ErrorIs(t, err, payment.ErrPayoutNotCancellable)
+}
+
+func TestProvider_Cancel(t *testing.T) {
+	ctx := context.Background()
+	p := manualpayout.New(nil)
+	resp, err := p.InitiatePayout(ctx, &payment.InitiatePayoutParams{
+		TransactionID: uuid.New(),
+		Amount:        100,
+		Currency:      "usd",
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, p.CancelPayout(ctx, resp.PayoutID))
+	status, err := p.GetPayoutStatus(ctx, resp.PayoutID)
+	require.NoError(t, err)
+	assert.Equal(t, payment.PaymentCancelled, status)
+	require.Error(t, p.Complete(resp.PayoutID))
+
+	_, err = p.GetPayoutStatus(ctx, "manual_unknown")
+	require.ErrorIs(t, err, payment.ErrPayoutNotFound)
+}
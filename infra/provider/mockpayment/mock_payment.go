@@ -2,10 +2,12 @@ package mockpayment
 
 import (
 	"context"
+	"fmt"
 	"sync"
 	"time"
 
 	"github.com/amirasaad/fintech/pkg/provider/payment"
+	"github.com/google/uuid"
 )
 
 type mockPayment struct {
@@ -50,7 +52,9 @@ func (m *MockPaymentProvider) InitiatePayment(
 	go func() {
 		time.Sleep(2 * time.Second)
 		m.mu.Lock()
-		m.payments[params.TransactionID.String()].status = payment.PaymentCompleted
+		if p := m.payments[params.TransactionID.String()]; p.status == payment.PaymentPending {
+			p.status = payment.PaymentCompleted
+		}
 		m.mu.Unlock()
 	}()
 	return &payment.InitiatePaymentResponse{
@@ -86,3 +90,31 @@ func (m *MockPaymentProvider) InitiatePayout(
 		EstimatedArrivalDate: time.Now().Add(24 * time.Hour).Unix(),
 	}, nil
 }
+
+// GetPayoutStatus reports every payout as completed, matching InitiatePayout.
+func (m *MockPaymentProvider) GetPayoutStatus(
+	ctx context.Context,
+	payoutID string,
+) (payment.PaymentStatus, error) {
+	return payment.PaymentCompleted, nil
+}
+
+// CancelPayout fails because mock payouts complete immediately.
+func (m *MockPaymentProvider) CancelPayout(ctx context.Context, payoutID string) error {
+	return fmt.Errorf("%w: %s is completed", payment.ErrPayoutNotCancellable, payoutID)
+}
+
+// CancelCheckout cancels a pending mock payment, or fails if it completed.
+func (m *MockPaymentProvider) CancelCheckout(ctx context.Context, transactionID uuid.UUID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	p, ok := m.payments[transactionID.String()]
+	if !ok {
+		return nil
+	}
+	if p.status == payment.PaymentCompleted {
+		return fmt.Errorf("%w: %s is completed", payment.ErrCheckoutNotCancellable, transactionID)
+	}
+	p.status = payment.PaymentCancelled
+	return nil
+}
@@ -0,0 +1,130 @@
+package stripepayment
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/amirasaad/fintech/pkg/domain/events"
+	"github.com/amirasaad/fintech/pkg/provider/payment"
+	"github.com/google/uuid"
+	"github.com/stripe/stripe-go/v82"
+)
+
+// authorizationExpired is the failure reason of deposits whose authorization
+// Stripe released because it was not captured in time.
+const authorizationExpired = "authorization expired before capture"
+
+// CapturePayment captures amount of a payment intent created with manual
+// capture. Stripe releases the rest of the authorization and sends
+// payment_intent.succeeded, which completes the deposit with the captured
+// amount.
+func (s *StripePaymentProvider) CapturePayment(
+	ctx context.Context,
+	paymentID string,
+	amount int64,
+) error {
+	params := &stripe.PaymentIntentCaptureParams{AmountToCapture: stripe.Int64(amount)}
+	// A retried capture returns the outcome of the first one.
+	params.SetIdempotencyKey(fmt.Sprintf("capture-%s-%d", paymentID, amount))
+
+	callCtx, cancel := s.callContext(ctx)
+	defer cancel()
+	pi, err := s.client.V1PaymentIntents.Capture(callCtx, paymentID, params)
+	if err != nil {
+		var stripeErr *stripe.Error
+		if errors.As(err, &stripeErr) &&
+			stripeErr.Code == stripe.ErrorCodePaymentIntentUnexpectedState {
+			return fmt.Errorf(
+				"%w: %s: %s", payment.ErrPaymentNotCapturable, paymentID, stripeErr.Msg,
+			)
+		}
+		s.logger.Error("failed to capture payment intent",
+			"error", err,
+			"payment_intent_id", paymentID,
+		)
+		return fmt.Errorf("failed to capture payment intent: %w", err)
+	}
+
+	s.logger.Info("Captured payment intent",
+		"payment_intent_id", pi.ID,
+		"amount", amount,
+		"status", pi.Status,
+	)
+	return nil
+}
+
+// handlePaymentIntentCanceled handles payment_intent.canceled webhook events.
+// Stripe cancels payment intents created with manual capture whose
+// authorization is not captured within its window, seven days for cards; the
+// deposit then fails so it does not stay processed forever. Payment intents
+// canceled for any other reason fail their deposit too. Deposits already
+// completed or canceled are left as they are.
+func (s *StripePaymentProvider) handlePaymentIntentCanceled(
+	ctx context.Context,
+	event stripe.Event,
+	log *slog.Logger,
+) (*payment.PaymentEvent, error) {
+	var pi stripe.PaymentIntent
+	if err := json.Unmarshal(event.Data.Raw, &pi); err != nil {
+		log.Error("error parsing payment_intent.canceled", "error", err)
+		return nil, fmt.Errorf("error parsing payment_intent.canceled: %w", err)
+	}
+	log = log.With(
+		"payment_intent_id", pi.ID,
+		"cancellation_reason", pi.CancellationReason,
+	)
+
+	transactionID, err := uuid.Parse(pi.Metadata["transaction_id"])
+	if err != nil {
+		log.Warn("Skipping payment intent without transaction_id metadata")
+		return nil, nil
+	}
+	userID, err := uuid.Parse(pi.Metadata["user_id"])
+	if err != nil {
+		return nil, fmt.Errorf("invalid user_id in payment intent metadata: %w", err)
+	}
+	accountID, err := uuid.Parse(pi.Metadata["account_id"])
+	if err != nil {
+		return nil, fmt.Errorf("invalid account_id in payment intent metadata: %w", err)
+	}
+
+	reason := string(pi.CancellationReason)
+	switch pi.CancellationReason {
+	case stripe.PaymentIntentCancellationReasonAutomatic:
+		reason = authorizationExpired
+	case "":
+		reason = "payment canceled"
+	}
+	pf := events.NewPaymentFailed(
+		&events.FlowEvent{
+			ID:            uuid.New(),
+			UserID:        userID,
+			AccountID:     accountID,
+			FlowType:      "payment",
+			CorrelationID: uuid.New(),
+		},
+		events.WithFailedPaymentID(&pi.ID),
+	).WithReason(reason)
+	pf.TransactionID = transactionID
+	if err := s.bus.Emit(ctx, pf); err != nil {
+		log.Error("error emitting payment failed event", "error", err)
+		return nil, fmt.Errorf("error emitting payment failed event: %w", err)
+	}
+	log.Info("⏰ Payment intent canceled", "transaction_id", transactionID, "reason", reason)
+
+	return &payment.PaymentEvent{
+		ID:            pi.ID,
+		TransactionID: transactionID,
+		Status:        payment.PaymentFailed,
+		Amount:        pi.Amount,
+		Currency:      string(pi.Currency),
+		UserID:        userID,
+		AccountID:     accountID,
+		Metadata:      pi.Metadata,
+	}, nil
+}
+
+var _ payment.Capturer = (*StripePaymentProvider)(nil)
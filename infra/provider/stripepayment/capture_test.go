@@ -0,0 +1,78 @@
+package stripepayment
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/amirasaad/fintech/infra/eventbus"
+	"github.com/amirasaad/fintech/pkg/domain/events"
+	"github.com/amirasaad/fintech/pkg/provider/payment"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stripe/stripe-go/v82"
+)
+
+func TestHandlePaymentIntentCanceled(t *testing.T) {
+	userID, accountID, txID := uuid.New(), uuid.New(), uuid.New()
+	metadata := map[string]string{
+		"user_id":        userID.String(),
+		"account_id":     accountID.String(),
+		"transaction_id": txID.String(),
+	}
+	canceledEvent := func(t *testing.T, pi stripe.PaymentIntent) stripe.Event {
+		raw, err := json.Marshal(pi)
+		require.NoError(t, err)
+		return stripe.Event{Type: "payment_intent.canceled", Data: &stripe.EventData{Raw: raw}}
+	}
+
+	bus := eventbus.NewWithMemory(slog.Default())
+	var emitted []events.Event
+	bus.Register(events.EventTypePaymentFailed, func(_ context.Context, e events.Event) error {
+		emitted = append(emitted, e)
+		return nil
+	})
+	s := &StripePaymentProvider{bus: bus, logger: slog.Default()}
+	ctx := context.Background()
+
+	t.Run("expired authorization fails the deposit", func(t *testing.T) {
+		emitted = nil
+		pe, err := s.handlePaymentIntentCanceled(ctx, canceledEvent(t, stripe.PaymentIntent{
+			ID: "pi_1", Amount: 2500, Currency: "usd",
+			Status:             stripe.PaymentIntentStatusCanceled,
+			CancellationReason: stripe.PaymentIntentCancellationReasonAutomatic,
+			Metadata:           metadata,
+		}), slog.Default())
+		require.NoError(t, err)
+		assert.Equal(t, payment.PaymentFailed, pe.Status)
+		require.Len(t, emitted, 1)
+		pf := emitted[0].(*events.PaymentFailed)
+		assert.Equal(t, txID, pf.TransactionID)
+		assert.Equal(t, "pi_1", *pf.PaymentID)
+		assert.Equal(t, authorizationExpired, pf.Reason)
+	})
+
+	t.Run("other cancellations report their reason", func(t *testing.T) {
+		emitted = nil
+		_, err := s.handlePaymentIntentCanceled(ctx, canceledEvent(t, stripe.PaymentIntent{
+			ID: "pi_2", Amount: 2500, Currency: "usd",
+			CancellationReason: stripe.PaymentIntentCancellationReasonFraudulent,
+			Metadata:           metadata,
+		}), slog.Default())
+		require.NoError(t, err)
+		require.Len(t, emitted, 1)
+		assert.Equal(t, "fraudulent", emitted[0].(*events.PaymentFailed).Reason)
+	})
+
+	t.Run("external payment intents emit nothing", func(t *testing.T) {
+		emitted = nil
+		pe, err := s.handlePaymentIntentCanceled(ctx, canceledEvent(t, stripe.PaymentIntent{
+			ID: "pi_3", CancellationReason: stripe.PaymentIntentCancellationReasonAutomatic,
+		}), slog.Default())
+		require.NoError(t, err)
+		assert.Nil(t, pe)
+		assert.Empty(t, emitted)
+	})
+}
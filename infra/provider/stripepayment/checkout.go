@@ -0,0 +1,60 @@
+package stripepayment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/amirasaad/fintech/pkg/provider/payment"
+	"github.com/google/uuid"
+	"github.com/stripe/stripe-go/v82"
+)
+
+// CancelCheckout implements payment.CheckoutCanceler by expiring the open
+// Checkout Session of the transaction, after which Stripe no longer accepts
+// payment for it, and marking the session canceled.
+func (s *StripePaymentProvider) CancelCheckout(
+	ctx context.Context,
+	transactionID uuid.UUID,
+) error {
+	log := s.logger.With("transaction_id", transactionID)
+	se, err := s.checkoutService.GetActiveSessionByTransactionID(ctx, transactionID)
+	if err != nil {
+		return err
+	}
+	if se == nil {
+		log.Info("No open checkout session to cancel")
+		return nil
+	}
+	log = log.With("checkout_session_id", se.ID)
+
+	callCtx, cancel := s.callContext(ctx)
+	defer cancel()
+	session, err := s.client.V1CheckoutSessions.Retrieve(callCtx, se.ID, nil)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve checkout session: %w", err)
+	}
+	switch session.Status {
+	case stripe.CheckoutSessionStatusComplete:
+		return fmt.Errorf("%w: %s is paid", payment.ErrCheckoutNotCancellable, se.ID)
+	case stripe.CheckoutSessionStatusOpen:
+		if _, err := s.client.V1CheckoutSessions.Expire(callCtx, se.ID, nil); err != nil {
+			// The session can be paid between the retrieve and the expire.
+			var stripeErr *stripe.Error
+			if errors.As(err, &stripeErr) && stripeErr.Type == stripe.ErrorTypeInvalidRequest {
+				return fmt.Errorf(
+					"%w: %s: %s", payment.ErrCheckoutNotCancellable, se.ID, stripeErr.Msg,
+				)
+			}
+			return fmt.Errorf("failed to expire checkout session: %w", err)
+		}
+	}
+
+	if err := s.checkoutService.UpdateStatus(ctx, se.ID, "canceled"); err != nil {
+		return fmt.Errorf("error updating session status: %w", err)
+	}
+	log.Info("Canceled checkout session", "stripe_status", session.Status)
+	return nil
+}
+
+var _ payment.CheckoutCanceler = (*StripePaymentProvider)(nil)
@@ -0,0 +1,125 @@
+package stripepayment
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/amirasaad/fintech/pkg/dto"
+	"github.com/amirasaad/fintech/pkg/repository"
+	"github.com/amirasaad/fintech/pkg/repository/user"
+	"github.com/google/uuid"
+	"github.com/stripe/stripe-go/v82"
+)
+
+// checkoutCustomer returns the ID of the user's Stripe customer, which
+// Checkout uses to offer the payment methods saved on earlier payments. The
+// customer is created on the user's first payment and stored on the user. A
+// stored customer that no longer exists on Stripe, e.g. one deleted from the
+// dashboard, is replaced by a new one. Without a unit of work there is no
+// customer and checkoutCustomer returns "".
+func (s *StripePaymentProvider) checkoutCustomer(
+	ctx context.Context,
+	userID uuid.UUID,
+) (string, error) {
+	if s.uow == nil {
+		return "", nil
+	}
+	var customerID string
+	err := s.uow.Do(ctx, func(uow repository.UnitOfWork) error {
+		userRepo, err := customerUserRepository(uow)
+		if err != nil {
+			return err
+		}
+		u, err := userRepo.Get(ctx, userID)
+		if err != nil {
+			return fmt.Errorf("failed to get user: %w", err)
+		}
+
+		stale := ""
+		if u.StripeCustomerID != "" {
+			ok, err := s.customerExists(ctx, u.StripeCustomerID)
+			if err != nil {
+				return err
+			}
+			if ok {
+				customerID = u.StripeCustomerID
+				return nil
+			}
+			s.logger.Warn("Stored stripe customer no longer exists, creating a new one",
+				"user_id", userID,
+				"stripe_customer_id", u.StripeCustomerID,
+			)
+			stale = u.StripeCustomerID
+		}
+
+		customerID, err = s.createCustomer(ctx, u, stale)
+		if err != nil {
+			return err
+		}
+		return userRepo.Update(ctx, userID, &dto.UserUpdate{StripeCustomerID: &customerID})
+	})
+	if err != nil {
+		return "", err
+	}
+	return customerID, nil
+}
+
+// customerExists reports whether the Stripe customer id exists and has not
+// been deleted.
+func (s *StripePaymentProvider) customerExists(ctx context.Context, id string) (bool, error) {
+	callCtx, cancel := s.callContext(ctx)
+	defer cancel()
+	c, err := s.client.V1Customers.Retrieve(callCtx, id, nil)
+	if err != nil {
+		if isStripeNotFound(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to retrieve stripe customer: %w", err)
+	}
+	return !c.Deleted, nil
+}
+
+// createCustomer creates the Stripe customer of u. The idempotency key makes
+// concurrent first payments of the same user share one customer; stale is
+// the customer being replaced, if any, so a replacement is not answered with
+// the customer it replaces.
+func (s *StripePaymentProvider) createCustomer(
+	ctx context.Context,
+	u *dto.UserRead,
+	stale string,
+) (string, error) {
+	params := &stripe.CustomerCreateParams{
+		Email:    stripe.String(u.Email),
+		Metadata: map[string]string{"user_id": u.ID.String()},
+	}
+	if u.Names != "" {
+		params.Name = stripe.String(u.Names)
+	}
+	key := "customer-" + u.ID.String()
+	if stale != "" {
+		key += "-replaces-" + stale
+	}
+	params.SetIdempotencyKey(key)
+
+	callCtx, cancel := s.callContext(ctx)
+	defer cancel()
+	c, err := s.client.V1Customers.Create(callCtx, params)
+	if err != nil {
+		return "", fmt.Errorf("failed to create stripe customer: %w", err)
+	}
+	s.logger.Info("Created stripe customer", "user_id", u.ID, "stripe_customer_id", c.ID)
+	return c.ID, nil
+}
+
+// customerUserRepository returns the user repository of uow.
+func customerUserRepository(uow repository.UnitOfWork) (user.Repository, error) {
+	repoAny, err := uow.GetRepository((*user.Repository)(nil))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user repository: %w", err)
+	}
+	repo, ok := repoAny.(user.Repository)
+	if !ok {
+		return nil, fmt.Errorf("unexpected user repository type %T", repoAny)
+	}
+	return repo, nil
+}
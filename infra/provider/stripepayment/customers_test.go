@@ -0,0 +1,125 @@
+package stripepayment
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/amirasaad/fintech/internal/fixtures/mocks"
+	"github.com/amirasaad/fintech/pkg/config"
+	"github.com/amirasaad/fintech/pkg/dto"
+	"github.com/amirasaad/fintech/pkg/repository"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/stripe/stripe-go/v82"
+)
+
+// customerAPI stubs the Stripe customers API: customers holds the live
+// customers, deleted the deleted ones, and anything else is missing.
+type customerAPI struct {
+	customers map[string]bool
+	deleted   map[string]bool
+	created   int
+}
+
+func (a *customerAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	id := strings.TrimPrefix(r.URL.Path, "/v1/customers/")
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == "/v1/customers":
+		a.created++
+		_, _ = w.Write([]byte(`{"id":"cus_new","object":"customer"}`))
+	case a.customers[id]:
+		_, _ = w.Write([]byte(`{"id":"` + id + `","object":"customer"}`))
+	case a.deleted[id]:
+		_, _ = w.Write([]byte(`{"id":"` + id + `","object":"customer","deleted":true}`))
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"error":{"type":"invalid_request_error",` +
+			`"code":"resource_missing","message":"No such customer"}}`))
+	}
+}
+
+func TestCheckoutCustomer(t *testing.T) {
+	userID := uuid.New()
+	ctx := context.Background()
+
+	setup := func(t *testing.T, stored string) (*StripePaymentProvider, *customerAPI, *string) {
+		api := &customerAPI{
+			customers: map[string]bool{"cus_live": true},
+			deleted:   map[string]bool{"cus_deleted": true},
+		}
+		srv := httptest.NewServer(api)
+		t.Cleanup(srv.Close)
+		backend := stripe.GetBackendWithConfig(stripe.APIBackend, &stripe.BackendConfig{
+			URL:               stripe.String(srv.URL),
+			HTTPClient:        srv.Client(),
+			MaxNetworkRetries: stripe.Int64(0),
+		})
+
+		uow := mocks.NewUnitOfWork(t)
+		userRepo := mocks.NewUserRepository(t)
+		uow.EXPECT().Do(mock.Anything, mock.Anything).RunAndReturn(
+			func(ctx context.Context, fn func(repository.UnitOfWork) error) error {
+				return fn(uow)
+			},
+		)
+		uow.EXPECT().GetRepository(mock.Anything).Return(userRepo, nil)
+		userRepo.EXPECT().Get(mock.Anything, userID).Return(&dto.UserRead{
+			ID:               userID,
+			Email:            "payer@example.com",
+			StripeCustomerID: stored,
+		}, nil)
+		saved := stored
+		userRepo.EXPECT().Update(mock.Anything, userID, mock.Anything).RunAndReturn(
+			func(_ context.Context, _ uuid.UUID, u *dto.UserUpdate) error {
+				saved = *u.StripeCustomerID
+				return nil
+			},
+		).Maybe()
+
+		s := &StripePaymentProvider{
+			client: stripe.NewClient("sk_test", stripe.WithBackends(
+				&stripe.Backends{API: backend},
+			)),
+			cfg:    &config.Stripe{},
+			logger: slog.Default(),
+			uow:    uow,
+		}
+		return s, api, &saved
+	}
+
+	tests := []struct {
+		name        string
+		stored      string
+		want        string
+		wantCreated int
+	}{
+		{"creates a customer on the first payment", "", "cus_new", 1},
+		{"reuses the stored customer", "cus_live", "cus_live", 0},
+		{"replaces a deleted customer", "cus_deleted", "cus_new", 1},
+		{"replaces a missing customer", "cus_missing", "cus_new", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, api, saved := setup(t, tt.stored)
+			got, err := s.checkoutCustomer(ctx, userID)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.want, *saved)
+			assert.Equal(t, tt.wantCreated, api.created)
+		})
+	}
+
+	t.Run("no customer without a unit of work", func(t *testing.T) {
+		s := &StripePaymentProvider{logger: slog.Default()}
+		got, err := s.checkoutCustomer(ctx, userID)
+		require.NoError(t, err)
+		assert.Empty(t, got)
+	})
+}
@@ -0,0 +1,62 @@
+package stripepayment
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"github.com/amirasaad/fintech/pkg/domain/events"
+	"github.com/amirasaad/fintech/pkg/provider/payment"
+	"github.com/stripe/stripe-go/v82"
+)
+
+// handleDisputeFundsWithdrawn handles charge.dispute.funds_withdrawn webhook
+// events, sent when Stripe debits the platform for a disputed charge. The
+// deposit the charge funded is reversed through a PaymentReversed event,
+// keyed by the dispute so repeated webhooks reverse it once. Disputes the
+// platform later wins return the funds separately and are not handled here.
+func (s *StripePaymentProvider) handleDisputeFundsWithdrawn(
+	ctx context.Context,
+	event stripe.Event,
+	log *slog.Logger,
+) (*payment.PaymentEvent, error) {
+	var dispute stripe.Dispute
+	if err := json.Unmarshal(event.Data.Raw, &dispute); err != nil {
+		log.Error("error parsing charge.dispute.funds_withdrawn", "error", err)
+		return nil, fmt.Errorf("error parsing charge.dispute.funds_withdrawn: %w", err)
+	}
+	log = log.With("dispute_id", dispute.ID, "reason", dispute.Reason)
+
+	pe := &payment.PaymentEvent{
+		ID:       dispute.ID,
+		Status:   payment.PaymentCompleted,
+		Amount:   dispute.Amount,
+		Currency: string(dispute.Currency),
+		Metadata: dispute.Metadata,
+	}
+	if dispute.PaymentIntent == nil || dispute.PaymentIntent.ID == "" {
+		log.Warn("Skipping dispute without a payment intent")
+		return pe, nil
+	}
+
+	amount, err := s.parseAmount(dispute.Amount, string(dispute.Currency))
+	if err != nil {
+		return nil, fmt.Errorf("invalid dispute amount: %w", err)
+	}
+	evt := events.NewPaymentReversed(
+		dispute.PaymentIntent.ID,
+		dispute.ID,
+		amount,
+		string(dispute.Reason),
+	)
+	if err := s.bus.Emit(ctx, evt); err != nil {
+		log.Error("error emitting PaymentReversed event", "error", err)
+		return nil, fmt.Errorf("error emitting %s event: %w", evt.Type(), err)
+	}
+	log.Info("✅ Dispute funds withdrawn",
+		"payment_intent_id", dispute.PaymentIntent.ID,
+		"amount", amount,
+	)
+	return pe, nil
+}
@@ -0,0 +1,53 @@
+package stripepayment
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/amirasaad/fintech/infra/eventbus"
+	"github.com/amirasaad/fintech/pkg/domain/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stripe/stripe-go/v82"
+)
+
+func TestHandleDisputeFundsWithdrawn(t *testing.T) {
+	bus := eventbus.NewWithMemory(slog.Default())
+	var emitted []events.Event
+	bus.Register(events.EventTypePaymentReversed, func(_ context.Context, e events.Event) error {
+		emitted = append(emitted, e)
+		return nil
+	})
+	s := &StripePaymentProvider{bus: bus, logger: slog.Default()}
+
+	disputeEvent := func(t *testing.T, dispute stripe.Dispute) stripe.Event {
+		raw, err := json.Marshal(dispute)
+		require.NoError(t, err)
+		return stripe.Event{
+			Type: "charge.dispute.funds_withdrawn",
+			Data: &stripe.EventData{Raw: raw},
+		}
+	}
+
+	_, err := s.handleDisputeFundsWithdrawn(context.Background(), disputeEvent(t, stripe.Dispute{
+		ID: "dp_1", Amount: 4000, Currency: "usd",
+		PaymentIntent: &stripe.PaymentIntent{ID: "pi_1"},
+		Reason:        stripe.DisputeReasonFraudulent,
+	}), slog.Default())
+	require.NoError(t, err)
+	require.Len(t, emitted, 1)
+	pr := emitted[0].(*events.PaymentReversed)
+	assert.Equal(t, "pi_1", pr.PaymentID)
+	assert.Equal(t, "dp_1", pr.ReversalID)
+	assert.Equal(t, "40.00", pr.Amount.AmountString())
+	assert.Equal(t, "fraudulent", pr.Reason)
+
+	emitted = nil
+	_, err = s.handleDisputeFundsWithdrawn(context.Background(), disputeEvent(t, stripe.Dispute{
+		ID: "dp_2", Amount: 4000, Currency: "usd",
+	}), slog.Default())
+	require.NoError(t, err)
+	assert.Empty(t, emitted)
+}
@@ -0,0 +1,140 @@
+package stripepayment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/amirasaad/fintech/pkg/provider/payment"
+	"github.com/google/uuid"
+	"github.com/stripe/stripe-go/v82"
+)
+
+// stripeListPageSize is the maximum page size supported by the Stripe list API.
+const stripeListPageSize = 100
+
+// ListPayouts lists the Stripe transfers created in [from, to). Withdrawals are
+// paid out as transfers to connected accounts, tagged with the internal
+// transaction_id in their metadata. The Stripe iterator follows pagination
+// until every page has been read.
+func (s *StripePaymentProvider) ListPayouts(
+	ctx context.Context,
+	from, to time.Time,
+) ([]*payment.PayoutRecord, error) {
+	params := &stripe.TransferListParams{
+		ListParams: stripe.ListParams{Limit: stripe.Int64(stripeListPageSize)},
+		CreatedRange: &stripe.RangeQueryParams{
+			GreaterThanOrEqual: from.Unix(),
+			LesserThan:         to.Unix(),
+		},
+	}
+
+	// The timeout covers the whole listing, across pages.
+	callCtx, cancel := s.callContext(ctx)
+	defer cancel()
+	var records []*payment.PayoutRecord
+	for transfer, err := range s.client.V1Transfers.List(callCtx, params) {
+		if err != nil {
+			return nil, fmt.Errorf("failed to list stripe transfers: %w", err)
+		}
+		records = append(records, toPayoutRecord(transfer))
+	}
+	s.logger.Info("Listed stripe transfers",
+		"from", from,
+		"to", to,
+		"count", len(records),
+	)
+	return records, nil
+}
+
+func toPayoutRecord(t *stripe.Transfer) *payment.PayoutRecord {
+	txID := uuid.Nil
+	if id, err := uuid.Parse(t.Metadata["transaction_id"]); err == nil {
+		txID = id
+	}
+	return &payment.PayoutRecord{
+		ID:             t.ID,
+		TransactionID:  txID,
+		Amount:         t.Amount,
+		AmountReversed: t.AmountReversed,
+		Currency:       strings.ToUpper(string(t.Currency)),
+		Reversed:       t.Reversed,
+		CreatedAt:      time.Unix(t.Created, 0).UTC(),
+	}
+}
+
+var (
+	_ payment.Payout       = (*StripePaymentProvider)(nil)
+	_ payment.PayoutLister = (*StripePaymentProvider)(nil)
+)
+
+// GetPayoutStatus implements payment.Payout. Payouts are Stripe transfers, so
+// it retrieves the transfer.
+func (s *StripePaymentProvider) GetPayoutStatus(
+	ctx context.Context,
+	payoutID string,
+) (payment.PaymentStatus, error) {
+	callCtx, cancel := s.callContext(ctx)
+	defer cancel()
+	transfer, err := s.client.V1Transfers.Retrieve(callCtx, payoutID, nil)
+	if err != nil {
+		if isStripeNotFound(err) {
+			return "", fmt.Errorf("%w: %s", payment.ErrPayoutNotFound, payoutID)
+		}
+		return "", fmt.Errorf("failed to retrieve stripe transfer: %w", err)
+	}
+	return transferStatus(transfer), nil
+}
+
+// CancelPayout implements payment.Payout by reversing the whole transfer,
+// which returns the funds from the connected account to the platform. Stripe
+// refuses once the connected account has paid the funds out.
+func (s *StripePaymentProvider) CancelPayout(ctx context.Context, payoutID string) error {
+	callCtx, cancel := s.callContext(ctx)
+	defer cancel()
+	reversal, err := s.client.V1TransferReversals.Create(
+		callCtx,
+		&stripe.TransferReversalCreateParams{
+			ID:                   stripe.String(payoutID),
+			RefundApplicationFee: stripe.Bool(true),
+		},
+	)
+	if err != nil {
+		if isStripeNotFound(err) {
+			return fmt.Errorf("%w: %s", payment.ErrPayoutNotFound, payoutID)
+		}
+		var stripeErr *stripe.Error
+		if errors.As(err, &stripeErr) && stripeErr.Type == stripe.ErrorTypeInvalidRequest {
+			return fmt.Errorf("%w: %s: %s", payment.ErrPayoutNotCancellable, payoutID, stripeErr.Msg)
+		}
+		return fmt.Errorf("failed to reverse stripe transfer: %w", err)
+	}
+	s.logger.Info("Reversed stripe transfer",
+		"transfer_id", payoutID,
+		"reversal_id", reversal.ID,
+		"amount", reversal.Amount,
+	)
+	return nil
+}
+
+// transferStatus maps a Stripe transfer to a payout status: reversed
+// transfers have failed, and transfers with a destination payment have
+// reached the connected account.
+func transferStatus(t *stripe.Transfer) payment.PaymentStatus {
+	switch {
+	case t.Reversed:
+		return payment.PaymentFailed
+	case t.DestinationPayment != nil && t.DestinationPayment.ID != "":
+		return payment.PaymentCompleted
+	default:
+		return payment.PaymentPending
+	}
+}
+
+// isStripeNotFound reports whether err is a Stripe missing-resource error.
+func isStripeNotFound(err error) bool {
+	var stripeErr *stripe.Error
+	return errors.As(err, &stripeErr) && stripeErr.Code == stripe.ErrorCodeResourceMissing
+}
@@ -4,11 +4,13 @@ import (
 	"context"
 	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 	"maps"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 	"time"
 	"unicode"
@@ -18,6 +20,9 @@ import (
 	"github.com/amirasaad/fintech/pkg/config"
 	"github.com/amirasaad/fintech/pkg/registry"
 	"github.com/amirasaad/fintech/pkg/repository"
+	"github.com/amirasaad/fintech/pkg/repository/processedevent"
+	"github.com/amirasaad/fintech/pkg/utils"
+	"github.com/amirasaad/fintech/pkg/validation"
 
 	"github.com/stripe/stripe-go/v82/webhook"
 
@@ -103,6 +108,7 @@ func (s *StripePaymentProvider) initializeWebhookHandlers() {
 	// Payment intent events
 	s.webhookHandlers["payment_intent.succeeded"] = s.handlePaymentIntentSucceeded
 	s.webhookHandlers["payment_intent.payment_failed"] = s.handlePaymentIntentFailed
+	s.webhookHandlers["payment_intent.canceled"] = s.handlePaymentIntentCanceled
 
 	// Checkout session events
 	s.webhookHandlers["checkout.session.completed"] = s.handleCheckoutSessionCompleted
@@ -116,6 +122,12 @@ func (s *StripePaymentProvider) initializeWebhookHandlers() {
 	// Charge events
 	s.webhookHandlers["charge.succeeded"] = s.handleChargeSucceeded
 	s.webhookHandlers["charge.updated"] = s.handleChargeSucceeded
+	s.webhookHandlers["charge.refunded"] = s.handleChargeRefunded
+	s.webhookHandlers["charge.dispute.funds_withdrawn"] = s.handleDisputeFundsWithdrawn
+
+	// Refund events
+	s.webhookHandlers["refund.updated"] = s.handleRefundUpdated
+	s.webhookHandlers["refund.failed"] = s.handleRefundUpdated
 
 	// Account events
 	s.webhookHandlers["account.updated"] = s.handleAccountUpdated
@@ -242,6 +254,33 @@ func (s *StripePaymentProvider) InitiatePayment(
 	)
 	log.Info("🛒 [START] InitiatePayment")
 
+	// A retry for the same transaction reuses the session created the first time.
+	existing, err := s.checkoutService.GetActiveSessionByTransactionID(
+		ctx, params.TransactionID,
+	)
+	if err != nil {
+		log.Error("failed to look up checkout session", "error", err)
+		return nil, fmt.Errorf("failed to look up checkout session: %w", err)
+	}
+	if existing != nil {
+		log.Info("🛒 Reusing active checkout session", "session_id", existing.ID)
+		return &payment.InitiatePaymentResponse{
+			Status:    payment.PaymentPending,
+			PaymentID: existing.PaymentID,
+		}, nil
+	}
+
+	successURL, cancelURL, err := s.redirectURLs(params)
+	if err != nil {
+		log.Error("invalid checkout redirect URL", "error", err)
+		return nil, err
+	}
+	descriptor, err := s.statementDescriptor(params)
+	if err != nil {
+		log.Error("invalid statement descriptor", "error", err)
+		return nil, err
+	}
+
 	// Create checkout session
 	co, err := s.createCheckoutSession(
 		ctx,
@@ -251,6 +290,10 @@ func (s *StripePaymentProvider) InitiatePayment(
 		params.Amount,
 		params.Currency,
 		"Payment for deposit",
+		descriptor,
+		params.CaptureMethod,
+		successURL,
+		cancelURL,
 	)
 	if err != nil {
 		log.Error(
@@ -573,17 +616,30 @@ func (s *StripePaymentProvider) handleTransferReversed(
 	return payoutEvent, nil
 }
 
+// checkoutPaymentMethods returns the payment method types configured for the
+// currency in any country, since the payer's country is not known yet.
+// Checkout falls back to cards when none are configured.
+func (s *StripePaymentProvider) checkoutPaymentMethods(currency string) []string {
+	methods, err := s.cfg.PaymentMethods.For(currency, "")
+	if err != nil {
+		s.logger.Warn("invalid payment method configuration", "error", err)
+	}
+	if len(methods) == 0 {
+		return []string{"card"}
+	}
+	return methods
+}
+
 // createCheckoutSession creates a new Stripe Checkout Session
 func (s *StripePaymentProvider) createCheckoutSession(
 	ctx context.Context,
 	userID, accountID, transactionID uuid.UUID,
 	amount int64,
 	currency string,
-	description string,
+	description, statementDescriptor string,
+	captureMethod payment.CaptureMethod,
+	successURL, cancelURL string,
 ) (*CheckoutSession, error) {
-	successURL := s.ensureAbsoluteURL(s.cfg.SuccessPath)
-	cancelURL := s.ensureAbsoluteURL(s.cfg.CancelPath)
-
 	// Create metadata for the checkout session and payment intent
 	metadata := map[string]string{
 		"user_id":        userID.String(),
@@ -594,13 +650,16 @@ func (s *StripePaymentProvider) createCheckoutSession(
 	}
 
 	params := &stripe.CheckoutSessionCreateParams{
-		PaymentMethodTypes: stripe.StringSlice([]string{"card"}),
+		PaymentMethodTypes: stripe.StringSlice(s.checkoutPaymentMethods(currency)),
 		Mode:               stripe.String(string(stripe.CheckoutSessionModePayment)),
 		SuccessURL:         stripe.String(successURL),
 		CancelURL:          stripe.String(cancelURL),
 		Metadata:           metadata,
 		PaymentIntentData: &stripe.CheckoutSessionCreatePaymentIntentDataParams{
 			Metadata: metadata,
+			// Card charges only accept a suffix, shown after the account's
+			// statement descriptor prefix.
+			StatementDescriptorSuffix: stripe.String(statementDescriptor),
 		},
 		LineItems: []*stripe.CheckoutSessionCreateLineItemParams{{
 			PriceData: &stripe.CheckoutSessionCreateLineItemPriceDataParams{
@@ -612,15 +671,37 @@ func (s *StripePaymentProvider) createCheckoutSession(
 			Quantity: stripe.Int64(1),
 		}},
 	}
-	// Create the checkout session parameters
+	if captureMethod == payment.CaptureManual {
+		// Only hold the funds; they are captured with CapturePayment. Only
+		// cards can be authorized now and captured later.
+		params.PaymentMethodTypes = stripe.StringSlice([]string{"card"})
+		params.PaymentIntentData.CaptureMethod = stripe.String(
+			string(stripe.PaymentIntentCaptureMethodManual),
+		)
+	}
+	// Stripe returns the original session if a create for the same transaction
+	// is retried, e.g. after a timeout that left no internal record.
+	params.SetIdempotencyKey("checkout-session-" + transactionID.String())
 
-	// Add customer email if available
-	if userEmail, ok := ctx.Value("user_email").(string); ok && userEmail != "" {
+	// Pay as the user's Stripe customer, so saved payment methods are offered;
+	// the email alone still lets Checkout prefill the receipt address.
+	customerID, err := s.checkoutCustomer(ctx, userID)
+	if err != nil {
+		s.logger.Warn("failed to get stripe customer, checking out by email",
+			"user_id", userID,
+			"error", err,
+		)
+	}
+	if customerID != "" {
+		params.Customer = stripe.String(customerID)
+	} else if userEmail, ok := ctx.Value("user_email").(string); ok && userEmail != "" {
 		params.CustomerEmail = stripe.String(userEmail)
 	}
 
 	// Create the checkout session using the session package
-	session, err := s.client.V1CheckoutSessions.Create(ctx, params)
+	callCtx, cancel := s.callContext(ctx)
+	defer cancel()
+	session, err := s.client.V1CheckoutSessions.Create(callCtx, params)
 	if err != nil {
 		s.logger.Error(
 			"failed to create checkout session",
@@ -840,20 +921,32 @@ func (s *StripePaymentProvider) handlePaymentIntentSucceeded(
 			"currency", currencyCode)
 		return nil, fmt.Errorf("failed to create money amount: %w", err)
 	}
-	// Emit PaymentCompleted event with zero fee since we're dropping fees
-	pc := s.buildPaymentCompletedEventPayload(amount, pi.ID, parsedMeta, log)
-	if pc == nil {
-		err := fmt.Errorf("failed to build payment completed event payload")
-		log.Error(err.Error())
+	completed, err := s.completeIntentOnce(ctx, pi.ID, func() error {
+		// Emit PaymentCompleted event with zero fee since we're dropping fees
+		pc := s.buildPaymentCompletedEventPayload(amount, pi.ID, parsedMeta, log)
+		if pc == nil {
+			err := fmt.Errorf("failed to build payment completed event payload")
+			log.Error(err.Error())
+			return err
+		}
+		pc.PaymentMethod = s.paymentMethodFromIntent(ctx, &pi, log)
+		if err := s.bus.Emit(ctx, pc); err != nil {
+			log.Error("error emitting payment completed event", "error", err)
+			return fmt.Errorf("error emitting payment completed event: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
 		return nil, err
 	}
-	if err := s.bus.Emit(ctx, pc); err != nil {
-		log.Error("error emitting payment completed event", "error", err)
-		return nil, fmt.Errorf("error emitting payment completed event: %w", err)
-	}
 
-	log.Info("✅ Payment intent processed and transaction updated successfully",
-		"transaction_id", parsedMeta.TransactionID, "payment_id", pi.ID)
+	if completed {
+		log.Info("✅ Payment intent processed and transaction updated successfully",
+			"transaction_id", parsedMeta.TransactionID, "payment_id", pi.ID)
+	} else {
+		log.Info("🔁 [SKIP] Payment intent already completed",
+			"transaction_id", parsedMeta.TransactionID)
+	}
 	return &payment.PaymentEvent{
 		ID:        pi.ID,
 		Status:    payment.PaymentCompleted,
@@ -865,6 +958,48 @@ func (s *StripePaymentProvider) handlePaymentIntentSucceeded(
 	}, nil
 }
 
+// completedIntentHandler is the handler name succeeded payment intents are
+// recorded under in the processed event table.
+const completedIntentHandler = "stripe.payment_intent.succeeded"
+
+// completeIntentOnce runs complete, which emits PaymentCompleted, once per
+// payment intent. Stripe may deliver payment_intent.succeeded more than once,
+// and every PaymentCompleted credits the deposit, so the intent ID is recorded
+// as processed in the same transaction; a replayed intent is skipped and
+// completeIntentOnce reports false. If complete fails the record is rolled
+// back, so Stripe's retry completes the intent. Without a unit of work
+// complete always runs.
+func (s *StripePaymentProvider) completeIntentOnce(
+	ctx context.Context,
+	intentID string,
+	complete func() error,
+) (bool, error) {
+	if s.uow == nil {
+		return true, complete()
+	}
+	completed := false
+	err := s.uow.Do(ctx, func(uow repository.UnitOfWork) error {
+		repoAny, err := uow.GetRepository((*processedevent.Repository)(nil))
+		if err != nil {
+			return fmt.Errorf("failed to get processed event repository: %w", err)
+		}
+		repo, ok := repoAny.(processedevent.Repository)
+		if !ok {
+			return fmt.Errorf("unexpected processed event repository type %T", repoAny)
+		}
+		marked, err := repo.MarkProcessed(ctx, completedIntentHandler, intentID)
+		if err != nil || !marked {
+			return err
+		}
+		if err := complete(); err != nil {
+			return err
+		}
+		completed = true
+		return nil
+	})
+	return completed, err
+}
+
 // getFeeFromBalanceTransaction retrieves the balance transaction
 // and returns the fee amount and currency.
 func (s *StripePaymentProvider) getFeeFromBalanceTransaction(
@@ -872,7 +1007,9 @@ func (s *StripePaymentProvider) getFeeFromBalanceTransaction(
 	log *slog.Logger,
 	balanceTxID string,
 ) (int64, string, error) {
-	bt, err := s.client.V1BalanceTransactions.Retrieve(ctx, balanceTxID, nil)
+	callCtx, cancel := s.callContext(ctx)
+	defer cancel()
+	bt, err := s.client.V1BalanceTransactions.Retrieve(callCtx, balanceTxID, nil)
 	if err != nil {
 		log.Warn(
 			"Failed to retrieve balance transaction",
@@ -1129,6 +1266,57 @@ func (s *StripePaymentProvider) buildPaymentCompletedEventPayload(
 	return pc
 }
 
+// paymentMethodFromIntent returns how a payment intent was funded, from its
+// latest charge. Webhook payloads only carry the charge ID, so the charge is
+// fetched. Failures leave the method unknown rather than failing the payment.
+func (s *StripePaymentProvider) paymentMethodFromIntent(
+	ctx context.Context,
+	pi *stripe.PaymentIntent,
+	log *slog.Logger,
+) *account.PaymentMethod {
+	charge := pi.LatestCharge
+	if charge == nil || charge.ID == "" {
+		log.Warn("payment intent has no charge, payment method unknown")
+		return nil
+	}
+	if charge.PaymentMethodDetails == nil {
+		callCtx, cancel := s.callContext(ctx)
+		defer cancel()
+		fetched, err := s.client.V1Charges.Retrieve(callCtx, charge.ID, nil)
+		if err != nil {
+			log.Warn("failed to retrieve charge for payment method",
+				"error", err,
+				"charge_id", charge.ID,
+			)
+			return nil
+		}
+		charge = fetched
+	}
+	return paymentMethodFromCharge(charge)
+}
+
+// paymentMethodFromCharge extracts the payment method details of a charge.
+// Cards report brand, last4 and funding; bank debits report last4; any other
+// method (wallets, Link, ...) is recorded by type only.
+func paymentMethodFromCharge(charge *stripe.Charge) *account.PaymentMethod {
+	details := charge.PaymentMethodDetails
+	if details == nil || details.Type == "" {
+		return nil
+	}
+	pm := &account.PaymentMethod{Type: string(details.Type)}
+	switch {
+	case details.Card != nil:
+		pm.Brand = string(details.Card.Brand)
+		pm.Last4 = details.Card.Last4
+		pm.Funding = string(details.Card.Funding)
+	case details.USBankAccount != nil:
+		pm.Last4 = details.USBankAccount.Last4
+	case details.SEPADebit != nil:
+		pm.Last4 = details.SEPADebit.Last4
+	}
+	return pm
+}
+
 func (s *StripePaymentProvider) handlePaymentIntentFailed(
 	ctx context.Context,
 	event stripe.Event, log *slog.Logger) (*payment.PaymentEvent, error) {
@@ -1145,8 +1333,10 @@ func (s *StripePaymentProvider) handlePaymentIntentFailed(
 	log = log.With("payment_intent_id", paymentIntent.ID)
 
 	// Get the payment intent details
+	callCtx, cancel := s.callContext(ctx)
+	defer cancel()
 	pi, err := s.client.V1PaymentIntents.Retrieve(
-		context.Background(),
+		callCtx,
 		paymentIntent.ID,
 		nil,
 	)
@@ -1228,23 +1418,98 @@ func (s *StripePaymentProvider) handlePaymentIntentFailed(
 	}, nil
 }
 
-// ensureAbsoluteURL ensures the URL is absolute by prepending the base URL if needed
-func (s *StripePaymentProvider) ensureAbsoluteURL(path string) string {
+// callContext bounds an outbound Stripe call by the configured call timeout
+// when ctx has no deadline of its own, e.g. in webhook handlers.
+func (s *StripePaymentProvider) callContext(
+	ctx context.Context,
+) (context.Context, context.CancelFunc) {
+	return utils.BoundedContext(ctx, s.cfg.CallTimeout)
+}
+
+// CheckHealth verifies that Stripe is reachable and accepts the configured API
+// key by retrieving the account balance, which has no side effects.
+func (s *StripePaymentProvider) CheckHealth(ctx context.Context) error {
+	ctx, cancel := s.callContext(ctx)
+	defer cancel()
+	if _, err := s.client.V1Balance.Retrieve(ctx, nil); err != nil {
+		return fmt.Errorf("stripe balance check failed: %w", err)
+	}
+	return nil
+}
+
+// statementDescriptor returns the card statement text for params: its own
+// descriptor if set, otherwise the configured default. It is validated
+// against Stripe's rules before it is sent.
+func (s *StripePaymentProvider) statementDescriptor(
+	params *payment.InitiatePaymentParams,
+) (string, error) {
+	descriptor := params.StatementDescriptor
+	if descriptor == "" {
+		descriptor = s.cfg.DefaultStatementDescriptor()
+	}
+	if err := validation.ValidateStatementDescriptor(descriptor); err != nil {
+		return "", err
+	}
+	return descriptor, nil
+}
+
+// redirectURLs returns the checkout success and cancel URLs for params.
+// Per-request URLs must pass the redirect allowlist; empty ones fall back to
+// the configured paths.
+func (s *StripePaymentProvider) redirectURLs(
+	params *payment.InitiatePaymentParams,
+) (successURL, cancelURL string, err error) {
+	if params.SuccessURL != "" {
+		if err := payment.ValidateRedirectURL(
+			params.SuccessURL, s.cfg.RedirectAllowedHosts,
+		); err != nil {
+			return "", "", err
+		}
+		successURL = params.SuccessURL
+	} else if successURL, err = s.ensureAbsoluteURL(s.cfg.SuccessPath); err != nil {
+		return "", "", fmt.Errorf("invalid success path: %w", err)
+	}
+	if params.CancelURL != "" {
+		if err := payment.ValidateRedirectURL(
+			params.CancelURL, s.cfg.RedirectAllowedHosts,
+		); err != nil {
+			return "", "", err
+		}
+		cancelURL = params.CancelURL
+	} else if cancelURL, err = s.ensureAbsoluteURL(s.cfg.CancelPath); err != nil {
+		return "", "", fmt.Errorf("invalid cancel path: %w", err)
+	}
+	return successURL, cancelURL, nil
+}
+
+// ensureAbsoluteURL resolves path against the configured base URL if it is
+// relative. Stripe rejects relative redirect URLs, so it returns an error if
+// the result is still not an absolute URL with a host.
+func (s *StripePaymentProvider) ensureAbsoluteURL(path string) (string, error) {
 	if path == "" {
-		return ""
+		return "", errors.New("redirect path is empty")
 	}
 
 	u, err := url.Parse(path)
 	if err != nil {
-		return path
+		return "", fmt.Errorf("malformed redirect path %q: %w", path, err)
 	}
 
-	// If it's already an absolute URL, return as is
-	if u.IsAbs() {
-		return path
+	if !u.IsAbs() {
+		base, err := url.Parse(s.cfg.BaseURL)
+		if err != nil || !base.IsAbs() || base.Host == "" {
+			return "", fmt.Errorf(
+				"cannot resolve relative redirect path %q against base URL %q",
+				path, s.cfg.BaseURL,
+			)
+		}
+		u = base.ResolveReference(u)
 	}
 
-	return path
+	if u.Host == "" {
+		return "", fmt.Errorf("redirect URL %q has no host", u.String())
+	}
+	return u.String(), nil
 }
 
 func (s *StripePaymentProvider) handleChargeSucceeded(
@@ -1369,12 +1634,28 @@ func (s *StripePaymentProvider) createFeeEvent(
 	return feeEvent, nil
 }
 
-// InitiatePayout implements payment.Payment interface
+// InitiatePayout implements payment.Payout interface. The transfer is keyed
+// by the internal transaction: it belongs to a transfer group named after
+// the transaction and is created with an idempotency key derived from it. A
+// retried payout for the same transaction returns the transfer already in
+// its group, and concurrent retries share one transfer through the
+// idempotency key, so a transaction is never paid out twice.
 func (s *StripePaymentProvider) InitiatePayout(
 	ctx context.Context,
 	params *payment.InitiatePayoutParams,
 ) (*payment.InitiatePayoutResponse, error) {
-	// First try to get the account with capabilities expanded
+	existing, err := s.payoutTransfer(ctx, params.TransactionID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		s.logger.Info("Payout already initiated",
+			"transaction_id", params.TransactionID,
+			"payout_id", existing.ID,
+		)
+		fee, _ := strconv.ParseInt(existing.Metadata["application_fee_amount"], 10, 64)
+		return payoutResponse(params, existing, fee), nil
+	}
 
 	s.logger.Info("Initiating payout",
 		"user_id", params.UserID,
@@ -1384,18 +1665,49 @@ func (s *StripePaymentProvider) InitiatePayout(
 		"destination_id", params.PaymentProviderID,
 	)
 
+	// Transfers have no application_fee_amount; the platform keeps its fee
+	// by transferring the net amount.
+	gross, err := money.NewFromSmallestUnit(
+		params.Amount, money.Code(strings.ToUpper(params.Currency)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("invalid payout amount: %w", err)
+	}
+	fee, err := s.applicationFee(params.PaymentProviderID, gross)
+	if err != nil {
+		return nil, err
+	}
+	net, err := gross.Subtract(fee)
+	if err != nil {
+		return nil, fmt.Errorf("failed to deduct application fee: %w", err)
+	}
+	if !fee.IsZero() {
+		s.logger.Info("Withholding application fee",
+			"transaction_id", params.TransactionID,
+			"stripe_account_id", params.PaymentProviderID,
+			"gross_amount", gross.String(),
+			"application_fee", fee.String(),
+			"net_amount", net.String(),
+		)
+	}
+
 	// Create the transfer to the connected account
 	transferParams := &stripe.TransferCreateParams{
-		Amount:      stripe.Int64(params.Amount),
+		Amount:      stripe.Int64(int64(net.Amount())),
 		Currency:    stripe.String(params.Currency),
 		Destination: stripe.String(params.PaymentProviderID),
 		Description: stripe.String(params.Description),
+		// The group finds the transfer again if the payout is retried.
+		TransferGroup: stripe.String(payoutTransferGroup(params.TransactionID)),
 	}
+	transferParams.SetIdempotencyKey("payout-" + params.TransactionID.String())
 
 	// Add metadata
 	transferParams.AddMetadata("user_id", params.UserID.String())
 	transferParams.AddMetadata("account_id", params.AccountID.String())
 	transferParams.AddMetadata("transaction_id", params.TransactionID.String())
+	transferParams.AddMetadata("gross_amount", fmt.Sprintf("%d", params.Amount))
+	transferParams.AddMetadata("application_fee_amount", fmt.Sprintf("%d", fee.Amount()))
 
 	// Add any additional metadata
 	for k, v := range params.Metadata {
@@ -1403,7 +1715,9 @@ func (s *StripePaymentProvider) InitiatePayout(
 	}
 
 	// Execute the transfer
-	transfer, err := s.client.V1Transfers.Create(ctx, transferParams)
+	callCtx, cancel := s.callContext(ctx)
+	defer cancel()
+	transfer, err := s.client.V1Transfers.Create(callCtx, transferParams)
 	if err != nil {
 		s.logger.Error("failed to create transfer",
 			"error", err,
@@ -1413,15 +1727,43 @@ func (s *StripePaymentProvider) InitiatePayout(
 		return nil, fmt.Errorf("failed to create transfer: %w", err)
 	}
 
-	// Determine the status based on the transfer status
-	status := payment.PaymentPending
-	if transfer.Reversed {
-		status = payment.PaymentFailed
-	} else if transfer.DestinationPayment != nil && len(transfer.DestinationPayment.ID) > 0 {
-		// If we have a destination payment, the transfer was successful
-		status = payment.PaymentCompleted
+	return payoutResponse(params, transfer, int64(fee.Amount())), nil
+}
+
+// payoutTransferGroup returns the transfer group of the payout of a
+// transaction.
+func payoutTransferGroup(transactionID uuid.UUID) string {
+	return "payout-" + transactionID.String()
+}
+
+// payoutTransfer returns the transfer already created for the payout of a
+// transaction, or nil if there is none.
+func (s *StripePaymentProvider) payoutTransfer(
+	ctx context.Context,
+	transactionID uuid.UUID,
+) (*stripe.Transfer, error) {
+	params := &stripe.TransferListParams{
+		ListParams:    stripe.ListParams{Limit: stripe.Int64(1)},
+		TransferGroup: stripe.String(payoutTransferGroup(transactionID)),
+	}
+	callCtx, cancel := s.callContext(ctx)
+	defer cancel()
+	for transfer, err := range s.client.V1Transfers.List(callCtx, params) {
+		if err != nil {
+			return nil, fmt.Errorf("failed to look up existing transfer: %w", err)
+		}
+		return transfer, nil
 	}
+	return nil, nil
+}
 
+// payoutResponse builds the response of the payout paid by transfer, which
+// withheld an application fee of applicationFee.
+func payoutResponse(
+	params *payment.InitiatePayoutParams,
+	transfer *stripe.Transfer,
+	applicationFee int64,
+) *payment.InitiatePayoutResponse {
 	// Get the fee amount if available
 	feeAmount := int64(0)
 	if transfer.DestinationPayment != nil {
@@ -1431,11 +1773,29 @@ func (s *StripePaymentProvider) InitiatePayout(
 	return &payment.InitiatePayoutResponse{
 		PayoutID:             transfer.ID,
 		PaymentProviderID:    params.PaymentProviderID,
-		Status:               status,
+		Status:               transferStatus(transfer),
 		Amount:               transfer.Amount,
 		Currency:             string(transfer.Currency),
 		FeeAmount:            feeAmount,
 		FeeCurrency:          string(transfer.Currency),
 		EstimatedArrivalDate: transfer.Created + 2*24*60*60, // Default to 2 days from creation
-	}, nil
+		ApplicationFeeAmount: applicationFee,
+	}
+}
+
+// applicationFee computes the platform fee on a payout of amount to the given
+// connected account, using its override or the global policy.
+func (s *StripePaymentProvider) applicationFee(
+	stripeAccountID string,
+	amount *money.Money,
+) (*money.Money, error) {
+	percent, fixed, err := s.cfg.ApplicationFee.For(stripeAccountID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid application fee policy: %w", err)
+	}
+	fee, err := account.FeePolicy{Percent: percent, Fixed: fixed}.Compute(amount)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute application fee: %w", err)
+	}
+	return fee, nil
 }
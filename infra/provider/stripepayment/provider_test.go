@@ -0,0 +1,378 @@
+package stripepayment
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/amirasaad/fintech/pkg/config"
+	"github.com/amirasaad/fintech/pkg/domain/account"
+	"github.com/amirasaad/fintech/pkg/domain/events"
+	pkgeventbus "github.com/amirasaad/fintech/pkg/eventbus"
+	"github.com/amirasaad/fintech/pkg/money"
+	"github.com/amirasaad/fintech/pkg/provider/payment"
+	"github.com/amirasaad/fintech/pkg/repository"
+	"github.com/amirasaad/fintech/pkg/repository/processedevent"
+	"github.com/amirasaad/fintech/pkg/validation"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stripe/stripe-go/v82"
+)
+
+func TestEnsureAbsoluteURL(t *testing.T) {
+	s := &StripePaymentProvider{
+		cfg:    &config.Stripe{BaseURL: "https://app.example.com"},
+		logger: slog.Default(),
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		want    string
+		wantErr bool
+	}{
+		{"absolute", "https://shop.example.com/paid", "https://shop.example.com/paid", false},
+		{"relative", "/stripe/success/", "https://app.example.com/stripe/success/", false},
+		{"relative with query", "done?x=1", "https://app.example.com/done?x=1", false},
+		{"malformed", "http://[::1", "", true},
+		{"no host", "mailto:", "", true},
+		{"empty", "", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.ensureAbsoluteURL(tt.path)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	t.Run("relative without base", func(t *testing.T) {
+		s := &StripePaymentProvider{cfg: &config.Stripe{}, logger: slog.Default()}
+		_, err := s.ensureAbsoluteURL("/payment/stripe/success/")
+		require.Error(t, err)
+	})
+}
+
+func TestRedirectURLs(t *testing.T) {
+	s := &StripePaymentProvider{
+		cfg: &config.Stripe{
+			BaseURL:              "http://localhost:3000",
+			SuccessPath:          "/success",
+			CancelPath:           "https://app.example.com/cancel",
+			RedirectAllowedHosts: []string{"shop.example.com"},
+		},
+		logger: slog.Default(),
+	}
+
+	success, cancel, err := s.redirectURLs(&payment.InitiatePaymentParams{})
+	require.NoError(t, err)
+	assert.Equal(t, "http://localhost:3000/success", success)
+	assert.Equal(t, "https://app.example.com/cancel", cancel)
+
+	success, _, err = s.redirectURLs(&payment.InitiatePaymentParams{
+		SuccessURL: "https://shop.example.com/paid",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "https://shop.example.com/paid", success)
+
+	_, _, err = s.redirectURLs(&payment.InitiatePaymentParams{
+		CancelURL: "https://evil.example.com/cancel",
+	})
+	require.ErrorIs(t, err, payment.ErrRedirectURLNotAllowed)
+}
+
+func TestStatementDescriptor(t *testing.T) {
+	s := &StripePaymentProvider{cfg: &config.Stripe{Env: "test"}, logger: slog.Default()}
+
+	descriptor, err := s.statementDescriptor(&payment.InitiatePaymentParams{})
+	require.NoError(t, err)
+	assert.Equal(t, "FINTECH TEST", descriptor)
+
+	s.cfg = &config.Stripe{Env: "production"}
+	descriptor, err = s.statementDescriptor(&payment.InitiatePaymentParams{})
+	require.NoError(t, err)
+	assert.Equal(t, "FINTECH", descriptor)
+
+	s.cfg.StatementDescriptor = "ACME TOPUP"
+	descriptor, err = s.statementDescriptor(&payment.InitiatePaymentParams{})
+	require.NoError(t, err)
+	assert.Equal(t, "ACME TOPUP", descriptor)
+
+	descriptor, err = s.statementDescriptor(&payment.InitiatePaymentParams{
+		StatementDescriptor: "ORDER 1042",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "ORDER 1042", descriptor)
+
+	_, err = s.statementDescriptor(&payment.InitiatePaymentParams{
+		StatementDescriptor: "<script>",
+	})
+	require.ErrorIs(t, err, validation.ErrInvalidStatementDescriptor)
+}
+
+func TestApplicationFee(t *testing.T) {
+	s := &StripePaymentProvider{
+		cfg: &config.Stripe{ApplicationFee: &config.ApplicationFee{
+			Percent:   2.5,
+			Fixed:     0.30,
+			Overrides: map[string]string{"acct_vip": "0+0"},
+		}},
+		logger: slog.Default(),
+	}
+	amount, err := money.New(100, money.USD)
+	require.NoError(t, err)
+
+	fee, err := s.applicationFee("acct_123", amount)
+	require.NoError(t, err)
+	assert.Equal(t, "2.80", fee.AmountString())
+
+	fee, err = s.applicationFee("acct_vip", amount)
+	require.NoError(t, err)
+	assert.True(t, fee.IsZero())
+
+	small, err := money.New(0.25, money.USD)
+	require.NoError(t, err)
+	_, err = s.applicationFee("acct_123", small)
+	require.ErrorIs(t, err, account.ErrFeeExceedsAmount)
+}
+
+func TestCheckoutPaymentMethods(t *testing.T) {
+	s := &StripePaymentProvider{
+		cfg: &config.Stripe{PaymentMethods: config.PaymentMethods{
+			"card":       "*",
+			"sepa_debit": "EUR/DE|FR",
+		}},
+		logger: slog.Default(),
+	}
+	assert.Equal(t, []string{"card", "sepa_debit"}, s.checkoutPaymentMethods("EUR"))
+	assert.Equal(t, []string{"card"}, s.checkoutPaymentMethods("USD"))
+
+	s.cfg.PaymentMethods = nil
+	assert.Equal(t, []string{"card"}, s.checkoutPaymentMethods("EUR"))
+}
+
+func TestTransferStatus(t *testing.T) {
+	assert.Equal(t, payment.PaymentPending, transferStatus(&stripe.Transfer{}))
+	assert.Equal(t, payment.PaymentCompleted, transferStatus(&stripe.Transfer{
+		DestinationPayment: &stripe.Charge{ID: "py_123"},
+	}))
+	assert.Equal(t, payment.PaymentFailed, transferStatus(&stripe.Transfer{
+		Reversed:           true,
+		DestinationPayment: &stripe.Charge{ID: "py_123"},
+	}))
+}
+
+// fakeProcessedUoW is a unit of work over an in-memory processed event table
+// whose Do discards the records of failed transactions.
+type fakeProcessedUoW struct {
+	records map[string]bool
+}
+
+func (u *fakeProcessedUoW) Do(
+	_ context.Context,
+	fn func(uow repository.UnitOfWork) error,
+) error {
+	tx := &fakeProcessedUoW{records: maps.Clone(u.records)}
+	if err := fn(tx); err != nil {
+		return err
+	}
+	u.records = tx.records
+	return nil
+}
+
+func (u *fakeProcessedUoW) GetRepository(repoType any) (any, error) {
+	if repoType != (*processedevent.Repository)(nil) {
+		return nil, errors.New("unsupported repository type")
+	}
+	return u, nil
+}
+
+func (u *fakeProcessedUoW) MarkProcessed(
+	_ context.Context,
+	handler, eventKey string,
+) (bool, error) {
+	key := handler + "/" + eventKey
+	if u.records[key] {
+		return false, nil
+	}
+	u.records[key] = true
+	return true, nil
+}
+
+// completionBus counts the PaymentCompleted events emitted, each of which
+// credits a deposit, and fails emitting while err is set.
+type completionBus struct {
+	completed int
+	err       error
+}
+
+func (b *completionBus) Register(events.EventType, pkgeventbus.HandlerFunc) {}
+
+func (b *completionBus) Emit(_ context.Context, e events.Event) error {
+	if b.err != nil {
+		return b.err
+	}
+	if _, ok := e.(*events.PaymentCompleted); ok {
+		b.completed++
+	}
+	return nil
+}
+
+func TestHandlePaymentIntentSucceeded_Replayed(t *testing.T) {
+	bus := &completionBus{}
+	s := &StripePaymentProvider{
+		bus:    bus,
+		logger: slog.Default(),
+		uow:    &fakeProcessedUoW{records: map[string]bool{}},
+	}
+
+	intentEvent := func(t *testing.T, id string) stripe.Event {
+		raw, err := json.Marshal(stripe.PaymentIntent{
+			ID:             id,
+			AmountReceived: 5000,
+			Currency:       "usd",
+			Metadata: map[string]string{
+				"user_id":        uuid.NewString(),
+				"account_id":     uuid.NewString(),
+				"transaction_id": uuid.NewString(),
+				"currency":       "USD",
+			},
+		})
+		require.NoError(t, err)
+		return stripe.Event{
+			Type: "payment_intent.succeeded",
+			Data: &stripe.EventData{Raw: raw},
+		}
+	}
+	ctx := context.Background()
+
+	event := intentEvent(t, "pi_1")
+	first, err := s.handlePaymentIntentSucceeded(ctx, event, slog.Default())
+	require.NoError(t, err)
+	// Stripe delivers the same event again.
+	second, err := s.handlePaymentIntentSucceeded(ctx, event, slog.Default())
+	require.NoError(t, err)
+	assert.Equal(t, 1, bus.completed)
+	assert.Equal(t, first, second)
+
+	// A failed completion is not recorded, so Stripe's retry completes it.
+	retried := intentEvent(t, "pi_2")
+	bus.err = errors.New("bus unavailable")
+	_, err = s.handlePaymentIntentSucceeded(ctx, retried, slog.Default())
+	require.Error(t, err)
+	bus.err = nil
+	_, err = s.handlePaymentIntentSucceeded(ctx, retried, slog.Default())
+	require.NoError(t, err)
+	assert.Equal(t, 2, bus.completed)
+}
+
+// transferAPI stubs the Stripe transfers API. It keeps the transfers
+// created, by transfer group, and answers a repeated idempotency key with
+// the transfer it created for the key.
+type transferAPI struct {
+	byGroup map[string]map[string]any
+	byKey   map[string]map[string]any
+	posts   int
+	keys    []string
+}
+
+func (a *transferAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = r.ParseForm()
+	switch r.Method {
+	case http.MethodGet:
+		data := []map[string]any{}
+		if tr, ok := a.byGroup[r.Form.Get("transfer_group")]; ok {
+			data = append(data, tr)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"object": "list", "url": "/v1/transfers", "has_more": false, "data": data,
+		})
+	case http.MethodPost:
+		a.posts++
+		key := r.Header.Get("Idempotency-Key")
+		a.keys = append(a.keys, key)
+		tr, ok := a.byKey[key]
+		if !ok {
+			amount, _ := strconv.ParseInt(r.Form.Get("amount"), 10, 64)
+			tr = map[string]any{
+				"id":             fmt.Sprintf("tr_%d", len(a.byKey)+1),
+				"object":         "transfer",
+				"amount":         amount,
+				"currency":       r.Form.Get("currency"),
+				"transfer_group": r.Form.Get("transfer_group"),
+				"metadata": map[string]string{
+					"transaction_id":         r.Form.Get("metadata[transaction_id]"),
+					"application_fee_amount": r.Form.Get("metadata[application_fee_amount]"),
+				},
+			}
+			a.byKey[key] = tr
+			a.byGroup[r.Form.Get("transfer_group")] = tr
+		}
+		_ = json.NewEncoder(w).Encode(tr)
+	}
+}
+
+func TestInitiatePayout_Retried(t *testing.T) {
+	api := &transferAPI{
+		byGroup: map[string]map[string]any{},
+		byKey:   map[string]map[string]any{},
+	}
+	srv := httptest.NewServer(api)
+	t.Cleanup(srv.Close)
+	backend := stripe.GetBackendWithConfig(stripe.APIBackend, &stripe.BackendConfig{
+		URL:               stripe.String(srv.URL),
+		HTTPClient:        srv.Client(),
+		MaxNetworkRetries: stripe.Int64(0),
+	})
+	s := &StripePaymentProvider{
+		client: stripe.NewClient("sk_test", stripe.WithBackends(
+			&stripe.Backends{API: backend},
+		)),
+		cfg: &config.Stripe{ApplicationFee: &config.ApplicationFee{
+			Percent: 1,
+		}},
+		logger: slog.Default(),
+	}
+	params := &payment.InitiatePayoutParams{
+		UserID:            uuid.New(),
+		AccountID:         uuid.New(),
+		PaymentProviderID: "acct_123",
+		TransactionID:     uuid.New(),
+		Amount:            10000,
+		Currency:          "usd",
+	}
+	ctx := context.Background()
+
+	first, err := s.InitiatePayout(ctx, params)
+	require.NoError(t, err)
+	assert.Equal(t, int64(9900), first.Amount)
+	assert.Equal(t, int64(100), first.ApplicationFeeAmount)
+	assert.Equal(t, []string{"payout-" + params.TransactionID.String()}, api.keys)
+
+	// The withdrawal handler runs again for the same transaction.
+	second, err := s.InitiatePayout(ctx, params)
+	require.NoError(t, err)
+	assert.Equal(t, 1, api.posts, "a single Stripe transfer")
+	assert.Equal(t, first, second)
+
+	// Another transaction gets its own transfer.
+	other := *params
+	other.TransactionID = uuid.New()
+	third, err := s.InitiatePayout(ctx, &other)
+	require.NoError(t, err)
+	assert.NotEqual(t, first.PayoutID, third.PayoutID)
+	assert.Equal(t, 2, api.posts)
+}
@@ -0,0 +1,187 @@
+package stripepayment
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"github.com/amirasaad/fintech/pkg/domain/events"
+	"github.com/amirasaad/fintech/pkg/provider/payment"
+	"github.com/google/uuid"
+	"github.com/stripe/stripe-go/v82"
+)
+
+// RefundPayment refunds part or all of a payment intent. The refund carries
+// the internal refund transaction in its metadata so the charge.refunded
+// webhook can complete it.
+func (s *StripePaymentProvider) RefundPayment(
+	ctx context.Context,
+	params *payment.RefundPaymentParams,
+) (*payment.RefundPaymentResponse, error) {
+	refundParams := &stripe.RefundCreateParams{
+		PaymentIntent: stripe.String(params.PaymentID),
+		Amount:        stripe.Int64(params.Amount),
+	}
+	refundParams.AddMetadata("user_id", params.UserID.String())
+	refundParams.AddMetadata("account_id", params.AccountID.String())
+	refundParams.AddMetadata("transaction_id", params.TransactionID.String())
+
+	callCtx, cancel := s.callContext(ctx)
+	defer cancel()
+	refund, err := s.client.V1Refunds.Create(callCtx, refundParams)
+	if err != nil {
+		s.logger.Error("failed to create refund",
+			"error", err,
+			"payment_intent_id", params.PaymentID,
+			"transaction_id", params.TransactionID,
+		)
+		return nil, fmt.Errorf("failed to create refund: %w", err)
+	}
+
+	s.logger.Info("Created refund",
+		"refund_id", refund.ID,
+		"payment_intent_id", params.PaymentID,
+		"transaction_id", params.TransactionID,
+		"amount", refund.Amount,
+		"status", refund.Status,
+	)
+	return &payment.RefundPaymentResponse{
+		RefundID: refund.ID,
+		Status:   refundStatus(refund.Status),
+	}, nil
+}
+
+// handleChargeRefunded handles charge.refunded webhook events. The event is
+// sent for every refund of the charge and only carries the cumulative amount
+// refunded, so all of the charge's refunds are listed and an event is emitted
+// for each settled one; the handlers skip refunds they already applied.
+func (s *StripePaymentProvider) handleChargeRefunded(
+	ctx context.Context,
+	event stripe.Event,
+	log *slog.Logger,
+) (*payment.PaymentEvent, error) {
+	var charge stripe.Charge
+	if err := json.Unmarshal(event.Data.Raw, &charge); err != nil {
+		log.Error("error parsing charge.refunded", "error", err)
+		return nil, fmt.Errorf("error parsing charge.refunded: %w", err)
+	}
+	log = log.With("charge_id", charge.ID)
+
+	params := &stripe.RefundListParams{
+		ListParams: stripe.ListParams{Limit: stripe.Int64(stripeListPageSize)},
+		Charge:     stripe.String(charge.ID),
+	}
+	callCtx, cancel := s.callContext(ctx)
+	defer cancel()
+	for refund, err := range s.client.V1Refunds.List(callCtx, params) {
+		if err != nil {
+			log.Error("error listing refunds", "error", err)
+			return nil, fmt.Errorf("error listing refunds: %w", err)
+		}
+		if err := s.emitRefundEvent(ctx, refund, log); err != nil {
+			return nil, err
+		}
+	}
+
+	return &payment.PaymentEvent{
+		ID:       charge.ID,
+		Status:   payment.PaymentCompleted,
+		Amount:   charge.AmountRefunded,
+		Currency: string(charge.Currency),
+		Metadata: charge.Metadata,
+	}, nil
+}
+
+// handleRefundUpdated handles refund.updated and refund.failed webhook
+// events, which carry a single refund. Refunds can fail after succeeding,
+// e.g. when the card was closed, so both directions are reported.
+func (s *StripePaymentProvider) handleRefundUpdated(
+	ctx context.Context,
+	event stripe.Event,
+	log *slog.Logger,
+) (*payment.PaymentEvent, error) {
+	var refund stripe.Refund
+	if err := json.Unmarshal(event.Data.Raw, &refund); err != nil {
+		log.Error("error parsing refund event", "type", event.Type, "error", err)
+		return nil, fmt.Errorf("error parsing %s: %w", event.Type, err)
+	}
+	if err := s.emitRefundEvent(ctx, &refund, log); err != nil {
+		return nil, err
+	}
+
+	transactionID, _ := uuid.Parse(refund.Metadata["transaction_id"])
+	return &payment.PaymentEvent{
+		ID:            refund.ID,
+		TransactionID: transactionID,
+		Status:        refundStatus(refund.Status),
+		Amount:        refund.Amount,
+		Currency:      string(refund.Currency),
+		Metadata:      refund.Metadata,
+	}, nil
+}
+
+// emitRefundEvent emits RefundCompleted for a succeeded refund and
+// RefundFailed for a failed or canceled one; refunds still in flight emit
+// nothing. Refunds created outside the platform, e.g. from the Stripe
+// dashboard, carry no transaction metadata and are skipped.
+func (s *StripePaymentProvider) emitRefundEvent(
+	ctx context.Context,
+	refund *stripe.Refund,
+	log *slog.Logger,
+) error {
+	status := refundStatus(refund.Status)
+	if status == payment.PaymentPending {
+		return nil
+	}
+	log = log.With("refund_id", refund.ID, "refund_status", refund.Status)
+	transactionID, err := uuid.Parse(refund.Metadata["transaction_id"])
+	if err != nil {
+		log.Warn("Skipping refund without transaction_id metadata")
+		return nil
+	}
+	userID, err := uuid.Parse(refund.Metadata["user_id"])
+	if err != nil {
+		return fmt.Errorf("invalid user_id in refund metadata: %w", err)
+	}
+	accountID, err := uuid.Parse(refund.Metadata["account_id"])
+	if err != nil {
+		return fmt.Errorf("invalid account_id in refund metadata: %w", err)
+	}
+	amount, err := s.parseAmount(refund.Amount, string(refund.Currency))
+	if err != nil {
+		return fmt.Errorf("invalid refund amount: %w", err)
+	}
+
+	var evt events.Event
+	if status == payment.PaymentCompleted {
+		evt = events.NewRefundCompleted(userID, accountID, transactionID, refund.ID, amount)
+	} else {
+		reason := string(refund.FailureReason)
+		if reason == "" {
+			reason = string(refund.Status)
+		}
+		evt = events.NewRefundFailed(
+			userID, accountID, transactionID, refund.ID, amount, reason,
+		)
+	}
+	if err := s.bus.Emit(ctx, evt); err != nil {
+		log.Error("error emitting refund event", "event_type", evt.Type(), "error", err)
+		return fmt.Errorf("error emitting %s event: %w", evt.Type(), err)
+	}
+	log.Info("✅ Refund settled", "transaction_id", transactionID, "event_type", evt.Type())
+	return nil
+}
+
+func refundStatus(status stripe.RefundStatus) payment.PaymentStatus {
+	switch status {
+	case stripe.RefundStatusSucceeded:
+		return payment.PaymentCompleted
+	case stripe.RefundStatusFailed, stripe.RefundStatusCanceled:
+		return payment.PaymentFailed
+	default:
+		return payment.PaymentPending
+	}
+}
+
+var _ payment.Refunder = (*StripePaymentProvider)(nil)
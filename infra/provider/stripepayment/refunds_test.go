@@ -0,0 +1,85 @@
+package stripepayment
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/amirasaad/fintech/infra/eventbus"
+	"github.com/amirasaad/fintech/pkg/domain/events"
+	"github.com/amirasaad/fintech/pkg/provider/payment"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stripe/stripe-go/v82"
+)
+
+func TestHandleRefundUpdated(t *testing.T) {
+	userID, accountID, txID := uuid.New(), uuid.New(), uuid.New()
+	metadata := map[string]string{
+		"user_id":        userID.String(),
+		"account_id":     accountID.String(),
+		"transaction_id": txID.String(),
+	}
+	refundEvent := func(t *testing.T, refund stripe.Refund) stripe.Event {
+		raw, err := json.Marshal(refund)
+		require.NoError(t, err)
+		return stripe.Event{Type: "refund.updated", Data: &stripe.EventData{Raw: raw}}
+	}
+
+	bus := eventbus.NewWithMemory(slog.Default())
+	var emitted []events.Event
+	record := func(_ context.Context, e events.Event) error {
+		emitted = append(emitted, e)
+		return nil
+	}
+	bus.Register(events.EventTypeRefundCompleted, record)
+	bus.Register(events.EventTypeRefundFailed, record)
+	s := &StripePaymentProvider{bus: bus, logger: slog.Default()}
+	ctx := context.Background()
+
+	t.Run("succeeded refund completes", func(t *testing.T) {
+		emitted = nil
+		pe, err := s.handleRefundUpdated(ctx, refundEvent(t, stripe.Refund{
+			ID: "re_1", Amount: 2500, Currency: "usd",
+			Status: stripe.RefundStatusSucceeded, Metadata: metadata,
+		}), slog.Default())
+		require.NoError(t, err)
+		assert.Equal(t, payment.PaymentCompleted, pe.Status)
+		require.Len(t, emitted, 1)
+		rc := emitted[0].(*events.RefundCompleted)
+		assert.Equal(t, txID, rc.TransactionID)
+		assert.Equal(t, "re_1", rc.RefundID)
+		assert.Equal(t, "25.00", rc.Amount.AmountString())
+	})
+
+	t.Run("failed refund reports the reason", func(t *testing.T) {
+		emitted = nil
+		_, err := s.handleRefundUpdated(ctx, refundEvent(t, stripe.Refund{
+			ID: "re_2", Amount: 1000, Currency: "usd",
+			Status:        stripe.RefundStatusFailed,
+			FailureReason: stripe.RefundFailureReasonLostOrStolenCard,
+			Metadata:      metadata,
+		}), slog.Default())
+		require.NoError(t, err)
+		require.Len(t, emitted, 1)
+		rf := emitted[0].(*events.RefundFailed)
+		assert.Equal(t, "lost_or_stolen_card", rf.Reason)
+	})
+
+	t.Run("pending and external refunds emit nothing", func(t *testing.T) {
+		emitted = nil
+		_, err := s.handleRefundUpdated(ctx, refundEvent(t, stripe.Refund{
+			ID: "re_3", Amount: 1000, Currency: "usd",
+			Status: stripe.RefundStatusPending, Metadata: metadata,
+		}), slog.Default())
+		require.NoError(t, err)
+		_, err = s.handleRefundUpdated(ctx, refundEvent(t, stripe.Refund{
+			ID: "re_4", Amount: 1000, Currency: "usd",
+			Status: stripe.RefundStatusSucceeded,
+		}), slog.Default())
+		require.NoError(t, err)
+		assert.Empty(t, emitted)
+	})
+}
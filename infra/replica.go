@@ -0,0 +1,94 @@
+package infra
+
+import (
+	"database/sql"
+	"strings"
+	"sync/atomic"
+
+	"github.com/amirasaad/fintech/pkg/repository"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const readReplicaPlugin = "fintech:read_replica"
+
+// replicaRouter is a gorm plugin that sends opted-in reads to read replicas
+// in round-robin order.
+type replicaRouter struct {
+	replicas []*gorm.DB
+	next     atomic.Uint64
+}
+
+// UseReadReplicas lets reads issued on db go to the given replicas.
+//
+// Routing is opt-in, so the primary stays the default:
+//   - Only SELECTs whose context was marked with repository.WithReplicaReads
+//     go to a replica; the balance, account list and transaction list
+//     endpoints mark theirs.
+//   - Everything inside a transaction (UoW.Do) stays on the primary, so
+//     transactions that read and write see their own writes.
+//   - Locking reads (SELECT ... FOR UPDATE) and all writes use the primary.
+//
+// Calling it without replicas is a no-op, so reads fall back to the primary.
+func UseReadReplicas(db *gorm.DB, replicas ...*gorm.DB) error {
+	if len(replicas) == 0 {
+		return nil
+	}
+	return db.Use(&replicaRouter{replicas: replicas})
+}
+
+// ReadReplicaPools returns the connection pools of the replicas registered on
+// db with UseReadReplicas, in registration order.
+func ReadReplicaPools(db *gorm.DB) []*sql.DB {
+	r, ok := db.Config.Plugins[readReplicaPlugin].(*replicaRouter)
+	if !ok {
+		return nil
+	}
+	pools := make([]*sql.DB, 0, len(r.replicas))
+	for _, replica := range r.replicas {
+		if pool, err := replica.DB(); err == nil {
+			pools = append(pools, pool)
+		}
+	}
+	return pools
+}
+
+// Name implements gorm.Plugin.
+func (r *replicaRouter) Name() string {
+	return readReplicaPlugin
+}
+
+// Initialize implements gorm.Plugin.
+func (r *replicaRouter) Initialize(db *gorm.DB) error {
+	if err := db.Callback().Query().Before("gorm:query").
+		Register(readReplicaPlugin, r.route); err != nil {
+		return err
+	}
+	return db.Callback().Row().Before("gorm:row").
+		Register(readReplicaPlugin, r.route)
+}
+
+func (r *replicaRouter) route(db *gorm.DB) {
+	if db.Error != nil || !repository.ReplicaReadsAllowed(db.Statement.Context) {
+		return
+	}
+	if _, inTx := db.Statement.ConnPool.(gorm.TxCommitter); inTx {
+		return
+	}
+	if _, locking := db.Statement.Clauses[clause.Locking{}.Name()]; locking {
+		return
+	}
+	// Raw statements arrive with their SQL already set; only plain SELECTs are
+	// safe to send to a replica.
+	if query := db.Statement.SQL.String(); query != "" && !isSelect(query) {
+		return
+	}
+	replica := r.replicas[(r.next.Add(1)-1)%uint64(len(r.replicas))]
+	db.Statement.ConnPool = replica.ConnPool
+}
+
+func isSelect(query string) bool {
+	query = strings.ToUpper(strings.TrimSpace(query))
+	return strings.HasPrefix(query, "SELECT") && !strings.Contains(query, "FOR UPDATE") &&
+		!strings.Contains(query, "FOR SHARE")
+}
@@ -0,0 +1,117 @@
+package infra_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/amirasaad/fintech/infra"
+	"github.com/amirasaad/fintech/pkg/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var errStop = errors.New("stop")
+
+// recordingPool is a gorm.ConnPool that records which pool ran each statement.
+type recordingPool struct {
+	name string
+	log  *[]string
+}
+
+func (p *recordingPool) record() error {
+	*p.log = append(*p.log, p.name)
+	return errStop
+}
+
+func (p *recordingPool) PrepareContext(context.Context, string) (*sql.Stmt, error) {
+	return nil, p.record()
+}
+
+func (p *recordingPool) ExecContext(context.Context, string, ...any) (sql.Result, error) {
+	return nil, p.record()
+}
+
+func (p *recordingPool) QueryContext(context.Context, string, ...any) (*sql.Rows, error) {
+	return nil, p.record()
+}
+
+func (p *recordingPool) QueryRowContext(context.Context, string, ...any) *sql.Row {
+	_ = p.record()
+	return &sql.Row{}
+}
+
+func (p *recordingPool) BeginTx(context.Context, *sql.TxOptions) (gorm.ConnPool, error) {
+	return &recordingTx{recordingPool{name: p.name + "-tx", log: p.log}}, nil
+}
+
+type recordingTx struct{ recordingPool }
+
+func (*recordingTx) Commit() error   { return nil }
+func (*recordingTx) Rollback() error { return nil }
+
+type row struct{ ID int }
+
+func openRecording(t *testing.T, name string, log *[]string) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(
+		postgres.New(postgres.Config{Conn: &recordingPool{name: name, log: log}}),
+		&gorm.Config{SkipDefaultTransaction: true},
+	)
+	require.NoError(t, err)
+	return db
+}
+
+func TestUseReadReplicas(t *testing.T) {
+	var log []string
+	primary := openRecording(t, "primary", &log)
+	require.NoError(t, infra.UseReadReplicas(
+		primary,
+		openRecording(t, "replica1", &log),
+		openRecording(t, "replica2", &log),
+	))
+
+	var rows []row
+	db := primary.WithContext(repository.WithReplicaReads(context.Background()))
+	_ = db.Table("rows").Find(&rows)
+	_ = db.Table("rows").Find(&rows)
+	_ = db.Raw("SELECT 1").Scan(&rows)
+	_ = db.Table("rows").Clauses(clause.Locking{Strength: "UPDATE"}).Find(&rows)
+	_ = db.Table("rows").Create(&row{ID: 1})
+	_ = db.Exec("UPDATE rows SET id = 2")
+	_ = db.Transaction(func(tx *gorm.DB) error {
+		_ = tx.Table("rows").Find(&rows)
+		return nil
+	})
+	_ = primary.Table("rows").Find(&rows)
+	_ = primary.WithContext(repository.WithoutReplicaReads(db.Statement.Context)).
+		Table("rows").Find(&rows)
+
+	assert.Equal(t, []string{
+		"replica1",
+		"replica2",
+		"replica1",
+		"primary",
+		"primary",
+		"primary",
+		"primary-tx",
+		"primary",
+		"primary",
+	}, log)
+}
+
+func TestUseReadReplicas_NoReplicas(t *testing.T) {
+	var log []string
+	primary := openRecording(t, "primary", &log)
+	require.NoError(t, infra.UseReadReplicas(primary))
+
+	var rows []row
+	_ = primary.WithContext(repository.WithReplicaReads(context.Background())).
+		Table("rows").Find(&rows)
+	assert.Equal(t, []string{"primary"}, log)
+	assert.Empty(t, infra.ReadReplicaPools(primary))
+}
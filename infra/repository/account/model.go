@@ -1,6 +1,8 @@
 package account
 
 import (
+	"time"
+
 	"github.com/amirasaad/fintech/infra/repository/transaction"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
@@ -9,10 +11,34 @@ import (
 // Account represents an account record in the database.
 type Account struct {
 	gorm.Model
-	ID           uuid.UUID `gorm:"type:uuid;primary_key"`
-	UserID       uuid.UUID `gorm:"type:uuid"`
-	Balance      int64
-	Currency     string `gorm:"type:varchar(3);not null;default:'USD'"`
+	ID             uuid.UUID `gorm:"type:uuid;primary_key"`
+	UserID         uuid.UUID `gorm:"type:uuid"`
+	Balance        int64
+	OverdraftLimit int64  `gorm:"not null;default:0"` // In the smallest currency unit.
+	Currency       string `gorm:"type:varchar(3);not null;default:'USD'"`
+	// Reference is the human-friendly account reference; nil for accounts
+	// created before references were introduced.
+	Reference *string `gorm:"type:varchar(32);uniqueIndex"`
+	// AutoConvert is whether deposits in another currency are converted to
+	// Currency; when false they are rejected.
+	AutoConvert bool `gorm:"not null;default:true"`
+	// Type is the kind of account: checking, savings or wallet.
+	Type string `gorm:"type:varchar(16);not null;default:'checking';index"`
+	// InterestAccrued is the interest credited so far, in the smallest
+	// currency unit, and InterestAccruedOn the last day it was accrued for.
+	InterestAccrued   int64      `gorm:"not null;default:0"`
+	InterestAccruedOn *time.Time `gorm:"type:date"`
+	// LedgerBalance is the sum of the transactions verified against Balance
+	// so far, and LedgerVerifiedAt the watermark of that verification.
+	LedgerBalance    int64 `gorm:"not null;default:0"`
+	LedgerVerifiedAt *time.Time
+	// FeePolicy decides who bears the provider fee on deposits: absorb or
+	// pass_through; nil follows the global policy.
+	FeePolicy *string `gorm:"type:varchar(16)"`
+	// Status is active, or frozen while the account is held for review.
+	// Deleted accounts are closed whatever their status.
+	Status string `gorm:"type:varchar(16);not null;default:'active'"`
+
 	Transactions []transaction.Transaction
 }
 
@@ -2,11 +2,13 @@ package account
 
 import (
 	"context"
+	"time"
 
 	"github.com/amirasaad/fintech/pkg/dto"
 	"github.com/amirasaad/fintech/pkg/money"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type repository struct {
@@ -50,6 +52,32 @@ func (r *repository) Get(
 	return mapModelToDTO(&acct), nil
 }
 
+// GetForUpdate implements account.Repository.
+func (r *repository) GetForUpdate(
+	ctx context.Context,
+	id uuid.UUID,
+) (*dto.AccountRead, error) {
+	var acct Account
+	if err := r.db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&acct, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return mapModelToDTO(&acct), nil
+}
+
+// GetByReference implements account.Repository.
+func (r *repository) GetByReference(
+	ctx context.Context,
+	reference string,
+) (*dto.AccountRead, error) {
+	var acct Account
+	if err := r.db.WithContext(ctx).First(&acct, "reference = ?", reference).Error; err != nil {
+		return nil, err
+	}
+	return mapModelToDTO(&acct), nil
+}
+
 // ListByUser implements account.Repository.
 func (r *repository) ListByUser(
 	ctx context.Context,
@@ -66,15 +94,142 @@ func (r *repository) ListByUser(
 	return result, nil
 }
 
+// ListByCurrency implements account.Repository. Closed accounts are the
+// soft-deleted ones.
+func (r *repository) ListByCurrency(
+	ctx context.Context,
+	currency string,
+	includeClosed bool,
+	page, pageSize int,
+) ([]*dto.AccountRead, error) {
+	var accts []Account
+	if err := r.byCurrency(ctx, currency, includeClosed).
+		Order("created_at, id").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Find(&accts).Error; err != nil {
+		return nil, err
+	}
+	result := make([]*dto.AccountRead, 0, len(accts))
+	for i := range accts {
+		result = append(result, mapModelToDTO(&accts[i]))
+	}
+	return result, nil
+}
+
+// ListInterestDue implements account.Repository. Closed accounts are the
+// soft-deleted ones.
+func (r *repository) ListInterestDue(
+	ctx context.Context,
+	accountType string,
+	day time.Time,
+	limit int,
+) ([]*dto.AccountRead, error) {
+	var accts []Account
+	if err := r.db.WithContext(ctx).
+		Where("type = ?", accountType).
+		Where("interest_accrued_on IS NULL OR interest_accrued_on < ?", day).
+		Order("created_at, id").
+		Limit(limit).
+		Find(&accts).Error; err != nil {
+		return nil, err
+	}
+	result := make([]*dto.AccountRead, 0, len(accts))
+	for i := range accts {
+		result = append(result, mapModelToDTO(&accts[i]))
+	}
+	return result, nil
+}
+
+// TotalsByCurrency implements account.Repository.
+func (r *repository) TotalsByCurrency(
+	ctx context.Context,
+	currency string,
+	includeClosed bool,
+) (*dto.AccountTotals, error) {
+	var row struct {
+		Count   int64
+		Balance int64
+	}
+	if err := r.byCurrency(ctx, currency, includeClosed).
+		Select("COUNT(*) AS count, COALESCE(SUM(balance), 0) AS balance").
+		Scan(&row).Error; err != nil {
+		return nil, err
+	}
+	bal := money.NewFromData(row.Balance, currency)
+	return &dto.AccountTotals{
+		Currency: currency,
+		Count:    row.Count,
+		Balance:  bal.AmountFloat(),
+	}, nil
+}
+
+// GetLedgerCheckpoint implements account.Repository.
+func (r *repository) GetLedgerCheckpoint(
+	ctx context.Context,
+	id uuid.UUID,
+) (*dto.LedgerCheckpoint, error) {
+	var acct Account
+	if err := r.db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&acct, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	checkpoint := &dto.LedgerCheckpoint{
+		AccountID:     acct.ID,
+		Currency:      acct.Currency,
+		Balance:       acct.Balance,
+		LedgerBalance: acct.LedgerBalance,
+	}
+	if acct.LedgerVerifiedAt != nil {
+		checkpoint.VerifiedAt = *acct.LedgerVerifiedAt
+	}
+	return checkpoint, nil
+}
+
+// SaveLedgerCheckpoint implements account.Repository.
+func (r *repository) SaveLedgerCheckpoint(
+	ctx context.Context,
+	id uuid.UUID,
+	ledgerBalance int64,
+	verifiedAt time.Time,
+) error {
+	return r.db.WithContext(ctx).
+		Model(&Account{}).
+		Where("id = ?", id).
+		UpdateColumns(map[string]any{
+			"ledger_balance":     ledgerBalance,
+			"ledger_verified_at": verifiedAt,
+		}).Error
+}
+
+func (r *repository) byCurrency(
+	ctx context.Context,
+	currency string,
+	includeClosed bool,
+) *gorm.DB {
+	db := r.db.WithContext(ctx).Model(&Account{})
+	if includeClosed {
+		db = db.Unscoped()
+	}
+	return db.Where("currency = ?", currency)
+}
+
 // mapCreateDTOToModel maps AccountCreate DTO to GORM model.
 func mapCreateDTOToModel(create dto.AccountCreate) Account {
-	return Account{
+	acct := Account{
 		ID:       create.ID,
 		UserID:   create.UserID,
 		Balance:  0,
 		Currency: create.Currency,
+		Type:     create.Type,
+		Status:   create.Status,
 		// Add more fields as needed
 	}
+	if create.Reference != "" {
+		acct.Reference = &create.Reference
+	}
+	return acct
 }
 
 // mapUpdateDTOToModel maps AccountUpdate DTO to a map for GORM Updates.
@@ -83,9 +238,28 @@ func mapUpdateDTOToModel(update dto.AccountUpdate) map[string]any {
 	if update.Balance != nil {
 		updates["balance"] = *update.Balance
 	}
-	// if update.Status != nil {
-	// 	updates["status"] = *update.Status
-	// }
+	if update.OverdraftLimit != nil {
+		updates["overdraft_limit"] = *update.OverdraftLimit
+	}
+	if update.AutoConvert != nil {
+		updates["auto_convert"] = *update.AutoConvert
+	}
+	if update.InterestAccrued != nil {
+		updates["interest_accrued"] = *update.InterestAccrued
+	}
+	if update.InterestAccruedOn != nil {
+		updates["interest_accrued_on"] = *update.InterestAccruedOn
+	}
+	if update.FeePolicy != nil {
+		if *update.FeePolicy == "" {
+			updates["fee_policy"] = nil
+		} else {
+			updates["fee_policy"] = *update.FeePolicy
+		}
+	}
+	if update.Status != nil {
+		updates["status"] = *update.Status
+	}
 	// Add more fields as needed
 	return updates
 }
@@ -93,11 +267,46 @@ func mapUpdateDTOToModel(update dto.AccountUpdate) map[string]any {
 // mapModelToDTO maps a GORM model to a read-optimized DTO.
 func mapModelToDTO(acct *Account) *dto.AccountRead {
 	bal := money.NewFromData(acct.Balance, acct.Currency)
-	return &dto.AccountRead{
-		ID:        acct.ID,
-		UserID:    acct.UserID,
-		Balance:   bal.AmountFloat(),
-		Currency:  bal.Currency().String(),
-		CreatedAt: acct.CreatedAt,
+	overdraft := money.NewFromData(acct.OverdraftLimit, acct.Currency)
+	status := acct.Status
+	if status == "" {
+		status = "active"
+	}
+	if acct.DeletedAt.Valid {
+		status = "closed"
+	}
+	// NewFromData assumes two decimals; the exact balance uses the decimals
+	// of the currency where the code is valid.
+	exact, err := money.NewFromSmallestUnit(acct.Balance, money.Code(acct.Currency))
+	if err != nil {
+		exact = bal
+	}
+	read := &dto.AccountRead{
+		ID:             acct.ID,
+		UserID:         acct.UserID,
+		Balance:        bal.AmountFloat(),
+		OverdraftLimit: overdraft.AmountFloat(),
+		Currency:       bal.Currency().String(),
+		Status:         status,
+		CreatedAt:      acct.CreatedAt,
+		BalanceMoney:   exact,
+		AutoConvert:    acct.AutoConvert,
+		Type:           acct.Type,
+	}
+	if acct.Reference != nil {
+		read.Reference = *acct.Reference
+	}
+	if acct.FeePolicy != nil {
+		read.FeePolicy = *acct.FeePolicy
+	}
+	if interest, err := money.NewFromSmallestUnit(
+		acct.InterestAccrued, money.Code(acct.Currency),
+	); err == nil {
+		read.InterestAccrued = interest
+	}
+	if acct.InterestAccruedOn != nil {
+		on := acct.InterestAccruedOn.UTC()
+		read.InterestAccruedOn = &on
 	}
+	return read
 }
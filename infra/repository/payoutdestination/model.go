@@ -0,0 +1,25 @@
+package payoutdestination
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// PayoutDestination represents a user's saved external withdrawal target in
+// the database.
+type PayoutDestination struct {
+	gorm.Model
+	ID                    uuid.UUID `gorm:"type:uuid;primary_key"`
+	UserID                uuid.UUID `gorm:"type:uuid;not null;index"`
+	Label                 string    `gorm:"type:varchar(64)"`
+	BankAccountNumber     string    `gorm:"type:varchar(34)"`
+	RoutingNumber         string    `gorm:"type:varchar(12)"`
+	BIC                   string    `gorm:"type:varchar(11);column:bic"`
+	ExternalWalletAddress string    `gorm:"type:varchar(128)"`
+	Network               string    `gorm:"type:varchar(32)"`
+}
+
+// TableName specifies the table name for the PayoutDestination model.
+func (PayoutDestination) TableName() string {
+	return "payout_destinations"
+}
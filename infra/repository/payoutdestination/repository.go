@@ -0,0 +1,95 @@
+package payoutdestination
+
+import (
+	"context"
+
+	"github.com/amirasaad/fintech/pkg/dto"
+	repo "github.com/amirasaad/fintech/pkg/repository/payoutdestination"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type repository struct {
+	db *gorm.DB
+}
+
+// New creates a new payout destination repository using the provided *gorm.DB.
+func New(db *gorm.DB) repo.Repository {
+	return &repository{db: db}
+}
+
+// Create implements payoutdestination.Repository.
+func (r *repository) Create(
+	ctx context.Context,
+	create dto.PayoutDestinationCreate,
+) error {
+	pd := PayoutDestination{
+		ID:                    create.ID,
+		UserID:                create.UserID,
+		Label:                 create.Label,
+		BankAccountNumber:     create.BankAccountNumber,
+		RoutingNumber:         create.RoutingNumber,
+		BIC:                   create.BIC,
+		ExternalWalletAddress: create.ExternalWalletAddress,
+		Network:               create.Network,
+	}
+	return r.db.WithContext(ctx).Create(&pd).Error
+}
+
+// Get implements payoutdestination.Repository.
+func (r *repository) Get(
+	ctx context.Context,
+	id uuid.UUID,
+) (*dto.PayoutDestinationRead, error) {
+	var pd PayoutDestination
+	if err := r.db.WithContext(ctx).First(&pd, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return mapModelToReadDTO(&pd), nil
+}
+
+// ListByUser implements payoutdestination.Repository.
+func (r *repository) ListByUser(
+	ctx context.Context,
+	userID uuid.UUID,
+) ([]*dto.PayoutDestinationRead, error) {
+	var pds []PayoutDestination
+	if err := r.db.WithContext(
+		ctx,
+	).Where(
+		"user_id = ?",
+		userID,
+	).Order(
+		"created_at ASC",
+	).Find(
+		&pds,
+	).Error; err != nil {
+		return nil, err
+	}
+	result := make([]*dto.PayoutDestinationRead, 0, len(pds))
+	for i := range pds {
+		result = append(result, mapModelToReadDTO(&pds[i]))
+	}
+	return result, nil
+}
+
+// Delete implements payoutdestination.Repository.
+func (r *repository) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Delete(&PayoutDestination{}, "id = ?", id).Error
+}
+
+// --- Mappers ---
+
+func mapModelToReadDTO(pd *PayoutDestination) *dto.PayoutDestinationRead {
+	return &dto.PayoutDestinationRead{
+		ID:                    pd.ID,
+		UserID:                pd.UserID,
+		Label:                 pd.Label,
+		BankAccountNumber:     pd.BankAccountNumber,
+		RoutingNumber:         pd.RoutingNumber,
+		BIC:                   pd.BIC,
+		ExternalWalletAddress: pd.ExternalWalletAddress,
+		Network:               pd.Network,
+		CreatedAt:             pd.CreatedAt,
+	}
+}
@@ -0,0 +1,30 @@
+package pendingtransfer
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// PendingTransfer represents a transfer awaiting approval in the database.
+type PendingTransfer struct {
+	gorm.Model
+	ID            uuid.UUID  `gorm:"type:uuid;primary_key"`
+	UserID        uuid.UUID  `gorm:"type:uuid;not null;index"`
+	FromAccountID uuid.UUID  `gorm:"type:uuid;not null"`
+	ToAccountID   uuid.UUID  `gorm:"type:uuid;not null"`
+	TransactionID uuid.UUID  `gorm:"type:uuid;not null"`
+	Amount        int64      `gorm:"not null"`
+	Currency      string     `gorm:"type:varchar(3);not null"`
+	Description   string     `gorm:"type:varchar(140);not null;default:''"`
+	Status        string     `gorm:"type:varchar(16);not null;default:'pending'"`
+	DecidedBy     *uuid.UUID `gorm:"type:uuid"`
+	DecidedAt     *time.Time
+	Reason        string `gorm:"type:varchar(255)"`
+}
+
+// TableName specifies the table name for the PendingTransfer model.
+func (PendingTransfer) TableName() string {
+	return "pending_transfers"
+}
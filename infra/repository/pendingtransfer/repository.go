@@ -0,0 +1,126 @@
+package pendingtransfer
+
+import (
+	"context"
+	"time"
+
+	"github.com/amirasaad/fintech/pkg/dto"
+	"github.com/amirasaad/fintech/pkg/money"
+	repo "github.com/amirasaad/fintech/pkg/repository/pendingtransfer"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type repository struct {
+	db *gorm.DB
+}
+
+// New creates a new pending transfer repository using the provided *gorm.DB.
+func New(db *gorm.DB) repo.Repository {
+	return &repository{db: db}
+}
+
+// Create implements pendingtransfer.Repository.
+func (r *repository) Create(
+	ctx context.Context,
+	create dto.PendingTransferCreate,
+) error {
+	pt := PendingTransfer{
+		ID:            create.ID,
+		UserID:        create.UserID,
+		FromAccountID: create.FromAccountID,
+		ToAccountID:   create.ToAccountID,
+		TransactionID: create.TransactionID,
+		Amount:        create.Amount,
+		Currency:      create.Currency,
+		Description:   create.Description,
+		Status:        dto.PendingTransferPending,
+	}
+	return r.db.WithContext(ctx).Create(&pt).Error
+}
+
+// Get implements pendingtransfer.Repository.
+func (r *repository) Get(
+	ctx context.Context,
+	id uuid.UUID,
+) (*dto.PendingTransferRead, error) {
+	var pt PendingTransfer
+	if err := r.db.WithContext(ctx).First(&pt, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return mapModelToReadDTO(&pt), nil
+}
+
+// List implements pendingtransfer.Repository.
+func (r *repository) List(
+	ctx context.Context,
+	status string,
+) ([]*dto.PendingTransferRead, error) {
+	query := r.db.WithContext(ctx)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	var pts []PendingTransfer
+	if err := query.Order("created_at ASC").Find(&pts).Error; err != nil {
+		return nil, err
+	}
+	result := make([]*dto.PendingTransferRead, 0, len(pts))
+	for i := range pts {
+		result = append(result, mapModelToReadDTO(&pts[i]))
+	}
+	return result, nil
+}
+
+// Decide implements pendingtransfer.Repository.
+func (r *repository) Decide(
+	ctx context.Context,
+	id uuid.UUID,
+	status string,
+	decidedBy uuid.UUID,
+	reason string,
+) (bool, error) {
+	updates := map[string]any{
+		"status":     status,
+		"decided_by": decidedBy,
+		"decided_at": time.Now().UTC(),
+	}
+	if reason != "" {
+		updates["reason"] = reason
+	}
+	res := r.db.WithContext(
+		ctx,
+	).Model(
+		&PendingTransfer{},
+	).Where(
+		"id = ? AND status = ?",
+		id,
+		dto.PendingTransferPending,
+	).Updates(
+		updates,
+	)
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected == 1, nil
+}
+
+// --- Mappers ---
+
+func mapModelToReadDTO(pt *PendingTransfer) *dto.PendingTransferRead {
+	amount := money.NewFromData(pt.Amount, pt.Currency)
+	return &dto.PendingTransferRead{
+		ID:            pt.ID,
+		UserID:        pt.UserID,
+		FromAccountID: pt.FromAccountID,
+		ToAccountID:   pt.ToAccountID,
+		TransactionID: pt.TransactionID,
+		Amount:        amount.AmountFloat(),
+		Currency:      pt.Currency,
+		Description:   pt.Description,
+		Status:        pt.Status,
+		DecidedBy:     pt.DecidedBy,
+		DecidedAt:     pt.DecidedAt,
+		Reason:        pt.Reason,
+		CreatedAt:     pt.CreatedAt,
+	}
+}
@@ -0,0 +1,50 @@
+package pendingwithdrawal
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// PendingWithdrawal represents a withdrawal held for approval in the
+// database.
+type PendingWithdrawal struct {
+	gorm.Model
+	ID                    uuid.UUID  `gorm:"type:uuid;primary_key"`
+	UserID                uuid.UUID  `gorm:"type:uuid;not null;index"`
+	AccountID             uuid.UUID  `gorm:"type:uuid;not null"`
+	TransactionID         uuid.UUID  `gorm:"type:uuid;not null"`
+	Amount                int64      `gorm:"not null"`
+	Currency              string     `gorm:"type:varchar(3);not null"`
+	HoldAmount            int64      `gorm:"not null"`
+	HoldCurrency          string     `gorm:"type:varchar(3);not null"`
+	Description           string     `gorm:"type:varchar(140);not null;default:''"`
+	BankAccountNumber     string     `gorm:"type:varchar(34);not null;default:''"`
+	RoutingNumber         string     `gorm:"type:varchar(9);not null;default:''"`
+	BIC                   string     `gorm:"type:varchar(11);not null;default:''"`
+	ExternalWalletAddress string     `gorm:"type:varchar(128);not null;default:''"`
+	Network               string     `gorm:"type:varchar(32);not null;default:''"`
+	RequiredApprovals     int        `gorm:"not null;default:1"`
+	Status                string     `gorm:"type:varchar(16);not null;default:'pending'"`
+	DecidedBy             *uuid.UUID `gorm:"type:uuid"`
+	DecidedAt             *time.Time
+	Reason                string `gorm:"type:varchar(255)"`
+}
+
+// TableName specifies the table name for the PendingWithdrawal model.
+func (PendingWithdrawal) TableName() string {
+	return "pending_withdrawals"
+}
+
+// Approval records one approver's approval of a pending withdrawal.
+type Approval struct {
+	PendingWithdrawalID uuid.UUID `gorm:"type:uuid;primaryKey"`
+	ApproverID          uuid.UUID `gorm:"type:uuid;primaryKey"`
+	CreatedAt           time.Time
+}
+
+// TableName specifies the table name for the Approval model.
+func (Approval) TableName() string {
+	return "pending_withdrawal_approvals"
+}
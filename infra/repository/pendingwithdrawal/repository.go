@@ -0,0 +1,225 @@
+package pendingwithdrawal
+
+import (
+	"context"
+	"time"
+
+	"github.com/amirasaad/fintech/pkg/dto"
+	"github.com/amirasaad/fintech/pkg/money"
+	repo "github.com/amirasaad/fintech/pkg/repository/pendingwithdrawal"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type repository struct {
+	db *gorm.DB
+}
+
+// New creates a new pending withdrawal repository using the provided *gorm.DB.
+func New(db *gorm.DB) repo.Repository {
+	return &repository{db: db}
+}
+
+// Create implements pendingwithdrawal.Repository.
+func (r *repository) Create(
+	ctx context.Context,
+	create dto.PendingWithdrawalCreate,
+) error {
+	pw := PendingWithdrawal{
+		ID:                    create.ID,
+		UserID:                create.UserID,
+		AccountID:             create.AccountID,
+		TransactionID:         create.TransactionID,
+		Amount:                create.Amount,
+		Currency:              create.Currency,
+		HoldAmount:            create.HoldAmount,
+		HoldCurrency:          create.HoldCurrency,
+		Description:           create.Description,
+		BankAccountNumber:     create.BankAccountNumber,
+		RoutingNumber:         create.RoutingNumber,
+		BIC:                   create.BIC,
+		ExternalWalletAddress: create.ExternalWalletAddress,
+		Network:               create.Network,
+		RequiredApprovals:     create.RequiredApprovals,
+		Status:                dto.PendingWithdrawalPending,
+	}
+	return r.db.WithContext(ctx).Create(&pw).Error
+}
+
+// Get implements pendingwithdrawal.Repository.
+func (r *repository) Get(
+	ctx context.Context,
+	id uuid.UUID,
+) (*dto.PendingWithdrawalRead, error) {
+	var pw PendingWithdrawal
+	if err := r.db.WithContext(ctx).First(&pw, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	approvals, err := r.approvals(ctx, []uuid.UUID{id})
+	if err != nil {
+		return nil, err
+	}
+	return mapModelToReadDTO(&pw, approvals[id]), nil
+}
+
+// GetByTransactionID implements pendingwithdrawal.Repository.
+func (r *repository) GetByTransactionID(
+	ctx context.Context,
+	transactionID uuid.UUID,
+) (*dto.PendingWithdrawalRead, error) {
+	var pw PendingWithdrawal
+	if err := r.db.WithContext(ctx).
+		First(&pw, "transaction_id = ?", transactionID).Error; err != nil {
+		return nil, err
+	}
+	approvals, err := r.approvals(ctx, []uuid.UUID{pw.ID})
+	if err != nil {
+		return nil, err
+	}
+	return mapModelToReadDTO(&pw, approvals[pw.ID]), nil
+}
+
+// List implements pendingwithdrawal.Repository.
+func (r *repository) List(
+	ctx context.Context,
+	status string,
+) ([]*dto.PendingWithdrawalRead, error) {
+	query := r.db.WithContext(ctx)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	var pws []PendingWithdrawal
+	if err := query.Order("created_at ASC").Find(&pws).Error; err != nil {
+		return nil, err
+	}
+	ids := make([]uuid.UUID, 0, len(pws))
+	for i := range pws {
+		ids = append(ids, pws[i].ID)
+	}
+	approvals, err := r.approvals(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*dto.PendingWithdrawalRead, 0, len(pws))
+	for i := range pws {
+		result = append(result, mapModelToReadDTO(&pws[i], approvals[pws[i].ID]))
+	}
+	return result, nil
+}
+
+// Approve implements pendingwithdrawal.Repository.
+func (r *repository) Approve(
+	ctx context.Context,
+	id uuid.UUID,
+	approverID uuid.UUID,
+) ([]uuid.UUID, bool, error) {
+	// Touching the row locks it, so a concurrent approval waits and then
+	// counts this one.
+	res := r.db.WithContext(ctx).
+		Model(&PendingWithdrawal{}).
+		Where("id = ? AND status = ?", id, dto.PendingWithdrawalPending).
+		Update("updated_at", time.Now().UTC())
+	if res.Error != nil {
+		return nil, false, res.Error
+	}
+	if res.RowsAffected != 1 {
+		return nil, false, nil
+	}
+	if err := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&Approval{PendingWithdrawalID: id, ApproverID: approverID}).Error; err != nil {
+		return nil, false, err
+	}
+	approvals, err := r.approvals(ctx, []uuid.UUID{id})
+	if err != nil {
+		return nil, false, err
+	}
+	return approvals[id], true, nil
+}
+
+// Decide implements pendingwithdrawal.Repository.
+func (r *repository) Decide(
+	ctx context.Context,
+	id uuid.UUID,
+	status string,
+	decidedBy uuid.UUID,
+	reason string,
+) (bool, error) {
+	updates := map[string]any{
+		"status":     status,
+		"decided_by": decidedBy,
+		"decided_at": time.Now().UTC(),
+	}
+	if reason != "" {
+		updates["reason"] = reason
+	}
+	res := r.db.WithContext(
+		ctx,
+	).Model(
+		&PendingWithdrawal{},
+	).Where(
+		"id = ? AND status = ?",
+		id,
+		dto.PendingWithdrawalPending,
+	).Updates(
+		updates,
+	)
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected == 1, nil
+}
+
+// approvals returns the approvers of each of the withdrawals, in approval
+// order.
+func (r *repository) approvals(
+	ctx context.Context,
+	ids []uuid.UUID,
+) (map[uuid.UUID][]uuid.UUID, error) {
+	result := make(map[uuid.UUID][]uuid.UUID, len(ids))
+	if len(ids) == 0 {
+		return result, nil
+	}
+	var rows []Approval
+	if err := r.db.WithContext(ctx).
+		Where("pending_withdrawal_id IN ?", ids).
+		Order("created_at, approver_id").
+		Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	for _, a := range rows {
+		result[a.PendingWithdrawalID] = append(result[a.PendingWithdrawalID], a.ApproverID)
+	}
+	return result, nil
+}
+
+// --- Mappers ---
+
+func mapModelToReadDTO(pw *PendingWithdrawal, approvals []uuid.UUID) *dto.PendingWithdrawalRead {
+	amount := money.NewFromData(pw.Amount, pw.Currency)
+	hold := money.NewFromData(pw.HoldAmount, pw.HoldCurrency)
+	return &dto.PendingWithdrawalRead{
+		ID:                    pw.ID,
+		UserID:                pw.UserID,
+		AccountID:             pw.AccountID,
+		TransactionID:         pw.TransactionID,
+		Amount:                amount.AmountFloat(),
+		Currency:              pw.Currency,
+		HoldAmount:            hold.AmountFloat(),
+		HoldCurrency:          pw.HoldCurrency,
+		Description:           pw.Description,
+		BankAccountNumber:     pw.BankAccountNumber,
+		RoutingNumber:         pw.RoutingNumber,
+		BIC:                   pw.BIC,
+		ExternalWalletAddress: pw.ExternalWalletAddress,
+		Network:               pw.Network,
+		RequiredApprovals:     pw.RequiredApprovals,
+		Approvals:             approvals,
+		Status:                pw.Status,
+		DecidedBy:             pw.DecidedBy,
+		DecidedAt:             pw.DecidedAt,
+		Reason:                pw.Reason,
+		CreatedAt:             pw.CreatedAt,
+	}
+}
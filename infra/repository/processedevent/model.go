@@ -0,0 +1,15 @@
+package processedevent
+
+import "time"
+
+// ProcessedEvent records that a handler processed an event.
+type ProcessedEvent struct {
+	Handler     string    `gorm:"type:varchar(128);primaryKey"`
+	EventKey    string    `gorm:"type:varchar(128);primaryKey"`
+	ProcessedAt time.Time `gorm:"not null"`
+}
+
+// TableName specifies the table name for the ProcessedEvent model.
+func (ProcessedEvent) TableName() string {
+	return "processed_events"
+}
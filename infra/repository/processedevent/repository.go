@@ -0,0 +1,39 @@
+package processedevent
+
+import (
+	"context"
+	"time"
+
+	repo "github.com/amirasaad/fintech/pkg/repository/processedevent"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type repository struct {
+	db *gorm.DB
+}
+
+// New creates a new processed event repository using the provided *gorm.DB.
+func New(db *gorm.DB) repo.Repository {
+	return &repository{db: db}
+}
+
+// MarkProcessed implements processedevent.Repository. A concurrent
+// transaction recording the same pair blocks on the primary key until it
+// commits or rolls back.
+func (r *repository) MarkProcessed(
+	ctx context.Context,
+	handler, eventKey string,
+) (bool, error) {
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(
+		&ProcessedEvent{
+			Handler:     handler,
+			EventKey:    eventKey,
+			ProcessedAt: time.Now().UTC(),
+		},
+	)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
@@ -0,0 +1,29 @@
+package scheduledtransfer
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ScheduledTransfer represents a future-dated transfer in the database.
+type ScheduledTransfer struct {
+	gorm.Model
+	ID            uuid.UUID `gorm:"type:uuid;primary_key"`
+	UserID        uuid.UUID `gorm:"type:uuid;not null;index"`
+	FromAccountID uuid.UUID `gorm:"type:uuid;not null"`
+	ToAccountID   uuid.UUID `gorm:"type:uuid;not null"`
+	Amount        int64     `gorm:"not null"`
+	Currency      string    `gorm:"type:varchar(3);not null"`
+	Description   string    `gorm:"type:varchar(140);not null;default:''"`
+	Status        string    `gorm:"type:varchar(16);not null;default:'pending'"`
+	ExecuteAt     time.Time `gorm:"not null;index"`
+	ExecutedAt    *time.Time
+	FailureReason string `gorm:"type:varchar(255)"`
+}
+
+// TableName specifies the table name for the ScheduledTransfer model.
+func (ScheduledTransfer) TableName() string {
+	return "scheduled_transfers"
+}
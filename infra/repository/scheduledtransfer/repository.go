@@ -0,0 +1,156 @@
+package scheduledtransfer
+
+import (
+	"context"
+	"time"
+
+	"github.com/amirasaad/fintech/pkg/dto"
+	"github.com/amirasaad/fintech/pkg/money"
+	repo "github.com/amirasaad/fintech/pkg/repository/scheduledtransfer"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type repository struct {
+	db *gorm.DB
+}
+
+// New creates a new scheduled transfer repository using the provided *gorm.DB.
+func New(db *gorm.DB) repo.Repository {
+	return &repository{db: db}
+}
+
+// Create implements scheduledtransfer.Repository.
+func (r *repository) Create(
+	ctx context.Context,
+	create dto.ScheduledTransferCreate,
+) error {
+	st := ScheduledTransfer{
+		ID:            create.ID,
+		UserID:        create.UserID,
+		FromAccountID: create.FromAccountID,
+		ToAccountID:   create.ToAccountID,
+		Amount:        create.Amount,
+		Currency:      create.Currency,
+		Description:   create.Description,
+		Status:        dto.ScheduledTransferPending,
+		ExecuteAt:     create.ExecuteAt.UTC(),
+	}
+	return r.db.WithContext(ctx).Create(&st).Error
+}
+
+// Get implements scheduledtransfer.Repository.
+func (r *repository) Get(
+	ctx context.Context,
+	id uuid.UUID,
+) (*dto.ScheduledTransferRead, error) {
+	var st ScheduledTransfer
+	if err := r.db.WithContext(ctx).First(&st, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return mapModelToReadDTO(&st), nil
+}
+
+// ListByUser implements scheduledtransfer.Repository.
+func (r *repository) ListByUser(
+	ctx context.Context,
+	userID uuid.UUID,
+	status string,
+) ([]*dto.ScheduledTransferRead, error) {
+	query := r.db.WithContext(ctx).Where("user_id = ?", userID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	var sts []ScheduledTransfer
+	if err := query.Order("execute_at ASC").Find(&sts).Error; err != nil {
+		return nil, err
+	}
+	return mapModelsToReadDTOs(sts), nil
+}
+
+// ListDue implements scheduledtransfer.Repository.
+func (r *repository) ListDue(
+	ctx context.Context,
+	before time.Time,
+	limit int,
+) ([]*dto.ScheduledTransferRead, error) {
+	var sts []ScheduledTransfer
+	if err := r.db.WithContext(
+		ctx,
+	).Clauses(
+		clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"},
+	).Where(
+		"status = ? AND execute_at <= ?",
+		dto.ScheduledTransferPending,
+		before.UTC(),
+	).Order(
+		"execute_at ASC",
+	).Limit(
+		limit,
+	).Find(
+		&sts,
+	).Error; err != nil {
+		return nil, err
+	}
+	return mapModelsToReadDTOs(sts), nil
+}
+
+// UpdateStatus implements scheduledtransfer.Repository.
+func (r *repository) UpdateStatus(
+	ctx context.Context,
+	id uuid.UUID,
+	from, to string,
+	reason string,
+) (bool, error) {
+	updates := map[string]any{"status": to}
+	if reason != "" {
+		updates["failure_reason"] = reason
+	}
+	if to == dto.ScheduledTransferExecuted {
+		updates["executed_at"] = time.Now().UTC()
+	}
+	res := r.db.WithContext(
+		ctx,
+	).Model(
+		&ScheduledTransfer{},
+	).Where(
+		"id = ? AND status = ?",
+		id,
+		from,
+	).Updates(
+		updates,
+	)
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected == 1, nil
+}
+
+// --- Mappers ---
+
+func mapModelsToReadDTOs(sts []ScheduledTransfer) []*dto.ScheduledTransferRead {
+	result := make([]*dto.ScheduledTransferRead, 0, len(sts))
+	for i := range sts {
+		result = append(result, mapModelToReadDTO(&sts[i]))
+	}
+	return result
+}
+
+func mapModelToReadDTO(st *ScheduledTransfer) *dto.ScheduledTransferRead {
+	amount := money.NewFromData(st.Amount, st.Currency)
+	return &dto.ScheduledTransferRead{
+		ID:            st.ID,
+		UserID:        st.UserID,
+		FromAccountID: st.FromAccountID,
+		ToAccountID:   st.ToAccountID,
+		Amount:        amount.AmountFloat(),
+		Currency:      st.Currency,
+		Description:   st.Description,
+		Status:        st.Status,
+		ExecuteAt:     st.ExecuteAt,
+		ExecutedAt:    st.ExecutedAt,
+		FailureReason: st.FailureReason,
+		CreatedAt:     st.CreatedAt,
+	}
+}
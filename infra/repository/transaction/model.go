@@ -1,6 +1,8 @@
 package transaction
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
@@ -31,6 +33,43 @@ type Transaction struct {
 
 	// Fee is the transaction fee in the smallest currency unit (e.g., cents)
 	Fee *int64 `gorm:"type:bigint;default:0"`
+
+	// Payment method that funded a deposit, for receipts. Empty when unknown;
+	// brand and funding are only set for cards.
+	PaymentMethodType    string `gorm:"type:varchar(32);not null;default:''"`
+	PaymentMethodBrand   string `gorm:"type:varchar(32);not null;default:''"`
+	PaymentMethodLast4   string `gorm:"type:varchar(4);not null;default:''"`
+	PaymentMethodFunding string `gorm:"type:varchar(16);not null;default:''"`
+
+	// Description is the user's note on the transaction, already normalized.
+	Description string `gorm:"type:varchar(140);not null;default:''"`
+
+	// RefundedTransactionID is set on refunds to the deposit they refund.
+	RefundedTransactionID *uuid.UUID `gorm:"type:uuid;index"`
+
+	// Bank account a withdrawal pays out to. Empty for other transactions.
+	ExternalBankAccountNumber string `gorm:"type:varchar(34);not null;default:''"`
+	ExternalRoutingNumber     string `gorm:"type:varchar(12);not null;default:''"`
+	ExternalBIC               string `gorm:"type:varchar(11);not null;default:'';column:external_bic"`
+
+	// ExternalReference identifies a transaction imported from another
+	// system; unique per account.
+	ExternalReference *string `gorm:"type:varchar(64)"`
+
+	// PayoutEstimatedArrival is when a withdrawal's payout is estimated to
+	// arrive; nil until the payout is initiated.
+	PayoutEstimatedArrival *time.Time
+
+	// PlatformFee is the provider fee the platform absorbed instead of
+	// deducting it from the account, in the smallest currency unit, and
+	// FeePolicy the policy applied to the provider fee; nil if none was
+	// charged.
+	PlatformFee int64   `gorm:"not null;default:0"`
+	FeePolicy   *string `gorm:"type:varchar(16)"`
+
+	// LedgerAmount is what the transaction contributed to its account's
+	// verified ledger balance, in the smallest currency unit.
+	LedgerAmount int64 `gorm:"not null;default:0"`
 }
 
 // TableName specifies the table name for the Transaction model.
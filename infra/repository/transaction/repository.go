@@ -2,6 +2,7 @@ package transaction // import alias for infra/repository/transaction
 
 import (
 	"context"
+	"time"
 
 	"github.com/amirasaad/fintech/pkg/dto"
 	"github.com/amirasaad/fintech/pkg/money"
@@ -170,6 +171,175 @@ func (r *repository) ListByAccount(
 	return result, nil
 }
 
+// ListByMoneySource implements transaction.Repository.
+func (r *repository) ListByMoneySource(
+	ctx context.Context,
+	moneySources []string,
+	from, to time.Time,
+) ([]*dto.TransactionRead, error) {
+	var txs []Transaction
+	if err := r.db.WithContext(
+		ctx,
+	).Where(
+		"money_source IN ? AND created_at >= ? AND created_at < ?",
+		moneySources,
+		from,
+		to,
+	).Order(
+		"created_at",
+	).Find(
+		&txs,
+	).Error; err != nil {
+		return nil, err
+	}
+	result := make([]*dto.TransactionRead, 0, len(txs))
+	for i := range txs {
+		result = append(result, mapModelToReadDTO(&txs[i]))
+	}
+	return result, nil
+}
+
+// SumByUserMoneySource implements transaction.Repository.
+func (r *repository) SumByUserMoneySource(
+	ctx context.Context,
+	userID uuid.UUID,
+	moneySources []string,
+	since time.Time,
+) (map[string]int64, error) {
+	var rows []struct {
+		Currency string
+		Total    int64
+	}
+	if err := r.db.WithContext(
+		ctx,
+	).Model(
+		&Transaction{},
+	).Select(
+		"currency, COALESCE(SUM(ABS(amount)), 0) AS total",
+	).Where(
+		"user_id = ? AND money_source IN ? AND status NOT IN ? AND created_at >= ?",
+		userID,
+		moneySources,
+		[]string{"failed", "canceled"},
+		since,
+	).Group(
+		"currency",
+	).Scan(
+		&rows,
+	).Error; err != nil {
+		return nil, err
+	}
+	result := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		result[row.Currency] = row.Total
+	}
+	return result, nil
+}
+
+// ListPayouts implements transaction.Repository.
+func (r *repository) ListPayouts(
+	ctx context.Context,
+	userID uuid.UUID,
+	moneySources []string,
+	statuses []string,
+	page, pageSize int,
+) ([]*dto.TransactionRead, error) {
+	q := r.db.WithContext(ctx).
+		Where("user_id = ? AND money_source IN ?", userID, moneySources)
+	if len(statuses) > 0 {
+		q = q.Where("status IN ?", statuses)
+	}
+	var txs []Transaction
+	if err := q.
+		Order("created_at DESC, id").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Find(&txs).Error; err != nil {
+		return nil, err
+	}
+	result := make([]*dto.TransactionRead, 0, len(txs))
+	for i := range txs {
+		result = append(result, mapModelToReadDTO(&txs[i]))
+	}
+	return result, nil
+}
+
+// ListRefunds implements transaction.Repository.
+func (r *repository) ListRefunds(
+	ctx context.Context,
+	transactionID uuid.UUID,
+) ([]*dto.TransactionRead, error) {
+	var txs []Transaction
+	if err := r.db.WithContext(
+		ctx,
+	).Where(
+		"refunded_transaction_id = ?",
+		transactionID,
+	).Order(
+		"created_at",
+	).Find(
+		&txs,
+	).Error; err != nil {
+		return nil, err
+	}
+	result := make([]*dto.TransactionRead, 0, len(txs))
+	for i := range txs {
+		result = append(result, mapModelToReadDTO(&txs[i]))
+	}
+	return result, nil
+}
+
+// ListLedgerEntries implements transaction.Repository.
+func (r *repository) ListLedgerEntries(
+	ctx context.Context,
+	accountID uuid.UUID,
+	since time.Time,
+) ([]*dto.LedgerEntry, error) {
+	var txs []Transaction
+	if err := r.db.WithContext(
+		ctx,
+	).Select(
+		"id", "status", "amount", "fee", "ledger_amount", "updated_at",
+	).Where(
+		"account_id = ? AND updated_at >= ?",
+		accountID,
+		since,
+	).Order(
+		"updated_at",
+	).Find(
+		&txs,
+	).Error; err != nil {
+		return nil, err
+	}
+	result := make([]*dto.LedgerEntry, 0, len(txs))
+	for i := range txs {
+		entry := &dto.LedgerEntry{
+			TransactionID: txs[i].ID,
+			Status:        txs[i].Status,
+			Amount:        txs[i].Amount,
+			Counted:       txs[i].LedgerAmount,
+			UpdatedAt:     txs[i].UpdatedAt,
+		}
+		if txs[i].Fee != nil {
+			entry.Fee = *txs[i].Fee
+		}
+		result = append(result, entry)
+	}
+	return result, nil
+}
+
+// SetLedgerCounted implements transaction.Repository.
+func (r *repository) SetLedgerCounted(
+	ctx context.Context,
+	id uuid.UUID,
+	counted int64,
+) error {
+	return r.db.WithContext(ctx).
+		Model(&Transaction{}).
+		Where("id = ?", id).
+		UpdateColumn("ledger_amount", counted).Error
+}
+
 // --- Mappers ---
 
 func mapCreateDTOToModel(create dto.TransactionCreate) Transaction {
@@ -178,8 +348,26 @@ func mapCreateDTOToModel(create dto.TransactionCreate) Transaction {
 		UserID:      create.UserID,
 		AccountID:   create.AccountID,
 		Amount:      create.Amount,
+		Balance:     create.Balance,
 		Status:      create.Status,
 		MoneySource: create.MoneySource,
+		Description: create.Description,
+
+		ExternalTargetMasked:  create.ExternalTargetMasked,
+		RefundedTransactionID: create.RefundedTransactionID,
+	}
+	if create.Currency != "" {
+		tx.Currency = create.Currency
+	}
+	if create.ExternalReference != "" {
+		tx.ExternalReference = &create.ExternalReference
+	}
+	// A zero CreatedAt is set to the current time on insert
+	tx.CreatedAt = create.CreatedAt
+	if ba := create.ExternalBankAccount; ba != nil {
+		tx.ExternalBankAccountNumber = ba.Number
+		tx.ExternalRoutingNumber = ba.RoutingNumber
+		tx.ExternalBIC = ba.BIC
 	}
 
 	// Set PaymentID if it's not nil
@@ -219,6 +407,21 @@ func mapUpdateDTOToModel(update dto.TransactionUpdate) map[string]any {
 	if update.OriginalCurrency != nil {
 		updates["original_currency"] = *update.OriginalCurrency
 	}
+	if update.PayoutEstimatedArrival != nil {
+		updates["payout_estimated_arrival"] = *update.PayoutEstimatedArrival
+	}
+	if update.PlatformFee != nil {
+		updates["platform_fee"] = *update.PlatformFee
+	}
+	if update.FeePolicy != nil {
+		updates["fee_policy"] = *update.FeePolicy
+	}
+	if pm := update.PaymentMethod; pm != nil {
+		updates["payment_method_type"] = pm.Type
+		updates["payment_method_brand"] = pm.Brand
+		updates["payment_method_last4"] = pm.Last4
+		updates["payment_method_funding"] = pm.Funding
+	}
 
 	// Add more fields as needed
 	return updates
@@ -229,19 +432,66 @@ func mapModelToReadDTO(tx *Transaction) *dto.TransactionRead {
 	if err != nil {
 		panic(err)
 	}
+	var pm *dto.PaymentMethod
+	if tx.PaymentMethodType != "" {
+		pm = &dto.PaymentMethod{
+			Type:    tx.PaymentMethodType,
+			Brand:   tx.PaymentMethodBrand,
+			Last4:   tx.PaymentMethodLast4,
+			Funding: tx.PaymentMethodFunding,
+		}
+	}
+	var bank *dto.BankAccount
+	if tx.ExternalBankAccountNumber != "" {
+		bank = &dto.BankAccount{
+			Number:        tx.ExternalBankAccountNumber,
+			RoutingNumber: tx.ExternalRoutingNumber,
+			BIC:           tx.ExternalBIC,
+		}
+	}
+	var fee float64
+	if tx.Fee != nil {
+		if m, err := money.NewFromSmallestUnit(*tx.Fee, money.Code(tx.Currency)); err == nil {
+			fee = m.AmountFloat()
+		}
+	}
+	// The balance shares the amount's currency, which was validated above.
+	balance, _ := money.NewFromSmallestUnit(tx.Balance, amount.Currency())
 	dto := &dto.TransactionRead{
-		ID:        tx.ID,
-		UserID:    tx.UserID,
-		AccountID: tx.AccountID,
-		Amount:    amount.AmountFloat(),
-		Currency:  tx.Currency, // Include the currency
-		Status:    tx.Status,
-		CreatedAt: tx.CreatedAt,
+		ID:           tx.ID,
+		UserID:       tx.UserID,
+		AccountID:    tx.AccountID,
+		Amount:       amount.AmountFloat(),
+		Currency:     tx.Currency, // Include the currency
+		Balance:      balance.AmountFloat(),
+		Status:       tx.Status,
+		Fee:          fee,
+		MoneySource:  tx.MoneySource,
+		Description:  tx.Description,
+		CreatedAt:    tx.CreatedAt,
+		AmountMoney:  amount,
+		BalanceMoney: balance,
 	}
 
 	if tx.PaymentID != nil {
 		dto.PaymentID = tx.PaymentID
 	}
+	dto.PaymentMethod = pm
+	dto.RefundedTransactionID = tx.RefundedTransactionID
+	dto.ExternalBankAccount = bank
+	if tx.ExternalReference != nil {
+		dto.ExternalReference = *tx.ExternalReference
+	}
+	dto.ExternalTargetMasked = tx.ExternalTargetMasked
+	dto.PayoutEstimatedArrival = tx.PayoutEstimatedArrival
+	if tx.PlatformFee != 0 {
+		if m, err := money.NewFromSmallestUnit(tx.PlatformFee, amount.Currency()); err == nil {
+			dto.PlatformFee = m.AmountFloat()
+		}
+	}
+	if tx.FeePolicy != nil {
+		dto.FeePolicy = *tx.FeePolicy
+	}
 
 	return dto
 }
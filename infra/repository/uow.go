@@ -5,10 +5,20 @@ import (
 	"fmt"
 
 	repoaccount "github.com/amirasaad/fintech/infra/repository/account"
+	repopayoutdestination "github.com/amirasaad/fintech/infra/repository/payoutdestination"
+	repopendingtransfer "github.com/amirasaad/fintech/infra/repository/pendingtransfer"
+	repopendingwithdrawal "github.com/amirasaad/fintech/infra/repository/pendingwithdrawal"
+	repoprocessedevent "github.com/amirasaad/fintech/infra/repository/processedevent"
+	reposcheduledtransfer "github.com/amirasaad/fintech/infra/repository/scheduledtransfer"
 	repotransaction "github.com/amirasaad/fintech/infra/repository/transaction"
 	repouser "github.com/amirasaad/fintech/infra/repository/user"
 	"github.com/amirasaad/fintech/pkg/repository"
 	"github.com/amirasaad/fintech/pkg/repository/account"
+	"github.com/amirasaad/fintech/pkg/repository/payoutdestination"
+	"github.com/amirasaad/fintech/pkg/repository/pendingtransfer"
+	"github.com/amirasaad/fintech/pkg/repository/pendingwithdrawal"
+	"github.com/amirasaad/fintech/pkg/repository/processedevent"
+	"github.com/amirasaad/fintech/pkg/repository/scheduledtransfer"
 	"github.com/amirasaad/fintech/pkg/repository/transaction"
 	"github.com/amirasaad/fintech/pkg/repository/user"
 	"gorm.io/gorm"
@@ -22,6 +32,10 @@ import (
 // - Centralizes repository wiring and registry for maintainability.
 // - Prevents accidental use of the wrong DB session (which would break transactionality).
 // - Is idiomatic for Go UoW patterns and easy to mock in tests.
+//
+// When read replicas are configured (see infra.UseReadReplicas), repositories
+// obtained outside Do read from a replica if the context was marked with
+// repository.WithReplicaReads, while everything inside Do runs on the primary.
 type UoW struct {
 	db      *gorm.DB
 	tx      *gorm.DB
@@ -42,6 +56,21 @@ func NewUoW(db *gorm.DB) *UoW {
 			(*user.Repository)(nil): func(db *gorm.DB) any {
 				return repouser.New(db)
 			},
+			(*scheduledtransfer.Repository)(nil): func(db *gorm.DB) any {
+				return reposcheduledtransfer.New(db)
+			},
+			(*pendingtransfer.Repository)(nil): func(db *gorm.DB) any {
+				return repopendingtransfer.New(db)
+			},
+			(*pendingwithdrawal.Repository)(nil): func(db *gorm.DB) any {
+				return repopendingwithdrawal.New(db)
+			},
+			(*payoutdestination.Repository)(nil): func(db *gorm.DB) any {
+				return repopayoutdestination.New(db)
+			},
+			(*processedevent.Repository)(nil): func(db *gorm.DB) any {
+				return repoprocessedevent.New(db)
+			},
 		},
 	}
 }
@@ -52,8 +81,9 @@ func (u *UoW) Do(ctx context.Context, fn func(uow repository.UnitOfWork) error)
 	return WrapError(func() error {
 		return u.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 			txnUow := &UoW{
-				db: u.db,
-				tx: tx,
+				db:      u.db,
+				tx:      tx,
+				repoMap: u.repoMap,
 			}
 			return fn(txnUow)
 		})
@@ -20,6 +20,9 @@ type User struct {
 	StripeConnectAccountID           string    `gorm:"size:255;index"`
 	StripeConnectOnboardingCompleted bool      `gorm:"default:false"`
 	StripeConnectAccountStatus       string    `gorm:"size:50"`
+	StripeCustomerID                 string    `gorm:"size:255"`
+	KycTier                          string    `gorm:"size:16;not null;default:'unverified'"`
+	Role                             string    `gorm:"size:16;not null;default:'user'"`
 	CreatedAt                        time.Time
 	UpdatedAt                        time.Time
 	DeletedAt                        gorm.DeletedAt `gorm:"index"`
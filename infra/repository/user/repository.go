@@ -90,6 +90,12 @@ func (r *repository) Update(
 	if uu.StripeConnectAccountID != nil {
 		updates["stripe_connect_account_id"] = *uu.StripeConnectAccountID
 	}
+	if uu.StripeCustomerID != nil {
+		updates["stripe_customer_id"] = *uu.StripeCustomerID
+	}
+	if uu.KycTier != nil {
+		updates["kyc_tier"] = *uu.KycTier
+	}
 
 	// If no fields to update, return early
 	if len(updates) == 0 {
@@ -269,6 +275,9 @@ func mapModelToDTO(user *User) *dto.UserRead {
 		HashedPassword:         user.Password,
 		Names:                  user.Names,
 		StripeConnectAccountID: user.StripeConnectAccountID,
+		StripeCustomerID:       user.StripeCustomerID,
+		KycTier:                user.KycTier,
+		Role:                   user.Role,
 		CreatedAt:              user.CreatedAt,
 		UpdatedAt:              user.UpdatedAt,
 	}
@@ -0,0 +1,49 @@
+// Package tracing sets up OpenTelemetry tracing, exporting spans over
+// OTLP/HTTP.
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/amirasaad/fintech/pkg/config"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// Setup installs the global tracer provider and the W3C trace context
+// propagator. Without an OTLP endpoint no tracer provider is installed, so
+// spans are not recorded and tracing costs next to nothing; trace context
+// received from callers is still passed on.
+//
+// The returned function flushes buffered spans and stops the exporter. It
+// must be called before the process exits.
+func Setup(ctx context.Context, cfg *config.Tracing) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+	if cfg == nil || cfg.OTLPEndpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, err := otlptracehttp.New(ctx, otlptracehttp.WithEndpointURL(cfg.OTLPEndpoint))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP trace exporter: %w", err)
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(
+			attribute.String("service.name", cfg.ServiceName),
+		)),
+		sdktrace.WithSampler(sdktrace.ParentBased(
+			sdktrace.TraceIDRatioBased(cfg.SampleRatio),
+		)),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
@@ -180,8 +180,160 @@ func (_c *PaymentProvider_InitiatePayment_Call) RunAndReturn(run func(ctx contex
 	return _c
 }
 
-// InitiatePayout provides a mock function for the type PaymentProvider
-func (_mock *PaymentProvider) InitiatePayout(ctx context.Context, params *payment.InitiatePayoutParams) (*payment.InitiatePayoutResponse, error) {
+// NewPayoutProvider creates a new instance of PayoutProvider. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewPayoutProvider(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *PayoutProvider {
+	mock := &PayoutProvider{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// PayoutProvider is an autogenerated mock type for the Payout type
+type PayoutProvider struct {
+	mock.Mock
+}
+
+type PayoutProvider_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *PayoutProvider) EXPECT() *PayoutProvider_Expecter {
+	return &PayoutProvider_Expecter{mock: &_m.Mock}
+}
+
+// CancelPayout provides a mock function for the type PayoutProvider
+func (_mock *PayoutProvider) CancelPayout(ctx context.Context, payoutID string) error {
+	ret := _mock.Called(ctx, payoutID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CancelPayout")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = returnFunc(ctx, payoutID)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// PayoutProvider_CancelPayout_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CancelPayout'
+type PayoutProvider_CancelPayout_Call struct {
+	*mock.Call
+}
+
+// CancelPayout is a helper method to define mock.On call
+//   - ctx context.Context
+//   - payoutID string
+func (_e *PayoutProvider_Expecter) CancelPayout(ctx interface{}, payoutID interface{}) *PayoutProvider_CancelPayout_Call {
+	return &PayoutProvider_CancelPayout_Call{Call: _e.mock.On("CancelPayout", ctx, payoutID)}
+}
+
+func (_c *PayoutProvider_CancelPayout_Call) Run(run func(ctx context.Context, payoutID string)) *PayoutProvider_CancelPayout_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *PayoutProvider_CancelPayout_Call) Return(err error) *PayoutProvider_CancelPayout_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *PayoutProvider_CancelPayout_Call) RunAndReturn(run func(ctx context.Context, payoutID string) error) *PayoutProvider_CancelPayout_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetPayoutStatus provides a mock function for the type PayoutProvider
+func (_mock *PayoutProvider) GetPayoutStatus(ctx context.Context, payoutID string) (payment.PaymentStatus, error) {
+	ret := _mock.Called(ctx, payoutID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPayoutStatus")
+	}
+
+	var r0 payment.PaymentStatus
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (payment.PaymentStatus, error)); ok {
+		return returnFunc(ctx, payoutID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) payment.PaymentStatus); ok {
+		r0 = returnFunc(ctx, payoutID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(payment.PaymentStatus)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, payoutID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// PayoutProvider_GetPayoutStatus_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetPayoutStatus'
+type PayoutProvider_GetPayoutStatus_Call struct {
+	*mock.Call
+}
+
+// GetPayoutStatus is a helper method to define mock.On call
+//   - ctx context.Context
+//   - payoutID string
+func (_e *PayoutProvider_Expecter) GetPayoutStatus(ctx interface{}, payoutID interface{}) *PayoutProvider_GetPayoutStatus_Call {
+	return &PayoutProvider_GetPayoutStatus_Call{Call: _e.mock.On("GetPayoutStatus", ctx, payoutID)}
+}
+
+func (_c *PayoutProvider_GetPayoutStatus_Call) Run(run func(ctx context.Context, payoutID string)) *PayoutProvider_GetPayoutStatus_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *PayoutProvider_GetPayoutStatus_Call) Return(paymentStatus payment.PaymentStatus, err error) *PayoutProvider_GetPayoutStatus_Call {
+	_c.Call.Return(paymentStatus, err)
+	return _c
+}
+
+func (_c *PayoutProvider_GetPayoutStatus_Call) RunAndReturn(run func(ctx context.Context, payoutID string) (payment.PaymentStatus, error)) *PayoutProvider_GetPayoutStatus_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// InitiatePayout provides a mock function for the type PayoutProvider
+func (_mock *PayoutProvider) InitiatePayout(ctx context.Context, params *payment.InitiatePayoutParams) (*payment.InitiatePayoutResponse, error) {
 	ret := _mock.Called(ctx, params)
 
 	if len(ret) == 0 {
@@ -208,19 +360,19 @@ func (_mock *PaymentProvider) InitiatePayout(ctx context.Context, params *paymen
 	return r0, r1
 }
 
-// PaymentProvider_InitiatePayout_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'InitiatePayout'
-type PaymentProvider_InitiatePayout_Call struct {
+// PayoutProvider_InitiatePayout_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'InitiatePayout'
+type PayoutProvider_InitiatePayout_Call struct {
 	*mock.Call
 }
 
 // InitiatePayout is a helper method to define mock.On call
 //   - ctx context.Context
 //   - params *payment.InitiatePayoutParams
-func (_e *PaymentProvider_Expecter) InitiatePayout(ctx interface{}, params interface{}) *PaymentProvider_InitiatePayout_Call {
-	return &PaymentProvider_InitiatePayout_Call{Call: _e.mock.On("InitiatePayout", ctx, params)}
+func (_e *PayoutProvider_Expecter) InitiatePayout(ctx interface{}, params interface{}) *PayoutProvider_InitiatePayout_Call {
+	return &PayoutProvider_InitiatePayout_Call{Call: _e.mock.On("InitiatePayout", ctx, params)}
 }
 
-func (_c *PaymentProvider_InitiatePayout_Call) Run(run func(ctx context.Context, params *payment.InitiatePayoutParams)) *PaymentProvider_InitiatePayout_Call {
+func (_c *PayoutProvider_InitiatePayout_Call) Run(run func(ctx context.Context, params *payment.InitiatePayoutParams)) *PayoutProvider_InitiatePayout_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
@@ -238,12 +390,12 @@ func (_c *PaymentProvider_InitiatePayout_Call) Run(run func(ctx context.Context,
 	return _c
 }
 
-func (_c *PaymentProvider_InitiatePayout_Call) Return(initiatePayoutResponse *payment.InitiatePayoutResponse, err error) *PaymentProvider_InitiatePayout_Call {
+func (_c *PayoutProvider_InitiatePayout_Call) Return(initiatePayoutResponse *payment.InitiatePayoutResponse, err error) *PayoutProvider_InitiatePayout_Call {
 	_c.Call.Return(initiatePayoutResponse, err)
 	return _c
 }
 
-func (_c *PaymentProvider_InitiatePayout_Call) RunAndReturn(run func(ctx context.Context, params *payment.InitiatePayoutParams) (*payment.InitiatePayoutResponse, error)) *PaymentProvider_InitiatePayout_Call {
+func (_c *PayoutProvider_InitiatePayout_Call) RunAndReturn(run func(ctx context.Context, params *payment.InitiatePayoutParams) (*payment.InitiatePayoutResponse, error)) *PayoutProvider_InitiatePayout_Call {
 	_c.Call.Return(run)
 	return _c
 }
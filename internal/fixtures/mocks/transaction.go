@@ -6,6 +6,7 @@ package mocks
 
 import (
 	"context"
+	"time"
 
 	"github.com/amirasaad/fintech/pkg/dto"
 	"github.com/google/uuid"
@@ -300,6 +301,86 @@ func (_c *TransactionRepository_ListByAccount_Call) RunAndReturn(run func(ctx co
 	return _c
 }
 
+// ListByMoneySource provides a mock function for the type TransactionRepository
+func (_mock *TransactionRepository) ListByMoneySource(ctx context.Context, moneySources []string, from time.Time, to time.Time) ([]*dto.TransactionRead, error) {
+	ret := _mock.Called(ctx, moneySources, from, to)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListByMoneySource")
+	}
+
+	var r0 []*dto.TransactionRead
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []string, time.Time, time.Time) ([]*dto.TransactionRead, error)); ok {
+		return returnFunc(ctx, moneySources, from, to)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []string, time.Time, time.Time) []*dto.TransactionRead); ok {
+		r0 = returnFunc(ctx, moneySources, from, to)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*dto.TransactionRead)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, []string, time.Time, time.Time) error); ok {
+		r1 = returnFunc(ctx, moneySources, from, to)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// TransactionRepository_ListByMoneySource_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListByMoneySource'
+type TransactionRepository_ListByMoneySource_Call struct {
+	*mock.Call
+}
+
+// ListByMoneySource is a helper method to define mock.On call
+//   - ctx context.Context
+//   - moneySources []string
+//   - from time.Time
+//   - to time.Time
+func (_e *TransactionRepository_Expecter) ListByMoneySource(ctx interface{}, moneySources interface{}, from interface{}, to interface{}) *TransactionRepository_ListByMoneySource_Call {
+	return &TransactionRepository_ListByMoneySource_Call{Call: _e.mock.On("ListByMoneySource", ctx, moneySources, from, to)}
+}
+
+func (_c *TransactionRepository_ListByMoneySource_Call) Run(run func(ctx context.Context, moneySources []string, from time.Time, to time.Time)) *TransactionRepository_ListByMoneySource_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 []string
+		if args[1] != nil {
+			arg1 = args[1].([]string)
+		}
+		var arg2 time.Time
+		if args[2] != nil {
+			arg2 = args[2].(time.Time)
+		}
+		var arg3 time.Time
+		if args[3] != nil {
+			arg3 = args[3].(time.Time)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *TransactionRepository_ListByMoneySource_Call) Return(transactionReads []*dto.TransactionRead, err error) *TransactionRepository_ListByMoneySource_Call {
+	_c.Call.Return(transactionReads, err)
+	return _c
+}
+
+func (_c *TransactionRepository_ListByMoneySource_Call) RunAndReturn(run func(ctx context.Context, moneySources []string, from time.Time, to time.Time) ([]*dto.TransactionRead, error)) *TransactionRepository_ListByMoneySource_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // ListByUser provides a mock function for the type TransactionRepository
 func (_mock *TransactionRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*dto.TransactionRead, error) {
 	ret := _mock.Called(ctx, userID)
@@ -556,3 +637,380 @@ func (_c *TransactionRepository_UpsertByPaymentID_Call) RunAndReturn(run func(ct
 	_c.Call.Return(run)
 	return _c
 }
+
+// ListPayouts provides a mock function for the type TransactionRepository
+func (_mock *TransactionRepository) ListPayouts(ctx context.Context, userID uuid.UUID, moneySources []string, statuses []string, page int, pageSize int) ([]*dto.TransactionRead, error) {
+	ret := _mock.Called(ctx, userID, moneySources, statuses, page, pageSize)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListPayouts")
+	}
+
+	var r0 []*dto.TransactionRead
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, []string, []string, int, int) ([]*dto.TransactionRead, error)); ok {
+		return returnFunc(ctx, userID, moneySources, statuses, page, pageSize)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, []string, []string, int, int) []*dto.TransactionRead); ok {
+		r0 = returnFunc(ctx, userID, moneySources, statuses, page, pageSize)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*dto.TransactionRead)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, uuid.UUID, []string, []string, int, int) error); ok {
+		r1 = returnFunc(ctx, userID, moneySources, statuses, page, pageSize)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// TransactionRepository_ListPayouts_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListPayouts'
+type TransactionRepository_ListPayouts_Call struct {
+	*mock.Call
+}
+
+// ListPayouts is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID uuid.UUID
+//   - moneySources []string
+//   - statuses []string
+//   - page int
+//   - pageSize int
+func (_e *TransactionRepository_Expecter) ListPayouts(ctx interface{}, userID interface{}, moneySources interface{}, statuses interface{}, page interface{}, pageSize interface{}) *TransactionRepository_ListPayouts_Call {
+	return &TransactionRepository_ListPayouts_Call{Call: _e.mock.On("ListPayouts", ctx, userID, moneySources, statuses, page, pageSize)}
+}
+
+func (_c *TransactionRepository_ListPayouts_Call) Run(run func(ctx context.Context, userID uuid.UUID, moneySources []string, statuses []string, page int, pageSize int)) *TransactionRepository_ListPayouts_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		var arg2 []string
+		if args[2] != nil {
+			arg2 = args[2].([]string)
+		}
+		var arg3 []string
+		if args[3] != nil {
+			arg3 = args[3].([]string)
+		}
+		var arg4 int
+		if args[4] != nil {
+			arg4 = args[4].(int)
+		}
+		var arg5 int
+		if args[5] != nil {
+			arg5 = args[5].(int)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+			arg4,
+			arg5,
+		)
+	})
+	return _c
+}
+
+func (_c *TransactionRepository_ListPayouts_Call) Return(transactionReads []*dto.TransactionRead, err error) *TransactionRepository_ListPayouts_Call {
+	_c.Call.Return(transactionReads, err)
+	return _c
+}
+
+func (_c *TransactionRepository_ListPayouts_Call) RunAndReturn(run func(ctx context.Context, userID uuid.UUID, moneySources []string, statuses []string, page int, pageSize int) ([]*dto.TransactionRead, error)) *TransactionRepository_ListPayouts_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListRefunds provides a mock function for the type TransactionRepository
+func (_mock *TransactionRepository) ListRefunds(ctx context.Context, transactionID uuid.UUID) ([]*dto.TransactionRead, error) {
+	ret := _mock.Called(ctx, transactionID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListRefunds")
+	}
+
+	var r0 []*dto.TransactionRead
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]*dto.TransactionRead, error)); ok {
+		return returnFunc(ctx, transactionID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID) []*dto.TransactionRead); ok {
+		r0 = returnFunc(ctx, transactionID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*dto.TransactionRead)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = returnFunc(ctx, transactionID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// TransactionRepository_ListRefunds_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListRefunds'
+type TransactionRepository_ListRefunds_Call struct {
+	*mock.Call
+}
+
+// ListRefunds is a helper method to define mock.On call
+//   - ctx context.Context
+//   - transactionID uuid.UUID
+func (_e *TransactionRepository_Expecter) ListRefunds(ctx interface{}, transactionID interface{}) *TransactionRepository_ListRefunds_Call {
+	return &TransactionRepository_ListRefunds_Call{Call: _e.mock.On("ListRefunds", ctx, transactionID)}
+}
+
+func (_c *TransactionRepository_ListRefunds_Call) Run(run func(ctx context.Context, transactionID uuid.UUID)) *TransactionRepository_ListRefunds_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *TransactionRepository_ListRefunds_Call) Return(transactionReads []*dto.TransactionRead, err error) *TransactionRepository_ListRefunds_Call {
+	_c.Call.Return(transactionReads, err)
+	return _c
+}
+
+func (_c *TransactionRepository_ListRefunds_Call) RunAndReturn(run func(ctx context.Context, transactionID uuid.UUID) ([]*dto.TransactionRead, error)) *TransactionRepository_ListRefunds_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListLedgerEntries provides a mock function for the type TransactionRepository
+func (_mock *TransactionRepository) ListLedgerEntries(ctx context.Context, accountID uuid.UUID, since time.Time) ([]*dto.LedgerEntry, error) {
+	ret := _mock.Called(ctx, accountID, since)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListLedgerEntries")
+	}
+
+	var r0 []*dto.LedgerEntry
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, time.Time) ([]*dto.LedgerEntry, error)); ok {
+		return returnFunc(ctx, accountID, since)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, time.Time) []*dto.LedgerEntry); ok {
+		r0 = returnFunc(ctx, accountID, since)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*dto.LedgerEntry)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, uuid.UUID, time.Time) error); ok {
+		r1 = returnFunc(ctx, accountID, since)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// TransactionRepository_ListLedgerEntries_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListLedgerEntries'
+type TransactionRepository_ListLedgerEntries_Call struct {
+	*mock.Call
+}
+
+// ListLedgerEntries is a helper method to define mock.On call
+//   - ctx context.Context
+//   - accountID uuid.UUID
+//   - since time.Time
+func (_e *TransactionRepository_Expecter) ListLedgerEntries(ctx interface{}, accountID interface{}, since interface{}) *TransactionRepository_ListLedgerEntries_Call {
+	return &TransactionRepository_ListLedgerEntries_Call{Call: _e.mock.On("ListLedgerEntries", ctx, accountID, since)}
+}
+
+func (_c *TransactionRepository_ListLedgerEntries_Call) Run(run func(ctx context.Context, accountID uuid.UUID, since time.Time)) *TransactionRepository_ListLedgerEntries_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		var arg2 time.Time
+		if args[2] != nil {
+			arg2 = args[2].(time.Time)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *TransactionRepository_ListLedgerEntries_Call) Return(ledgerEntrys []*dto.LedgerEntry, err error) *TransactionRepository_ListLedgerEntries_Call {
+	_c.Call.Return(ledgerEntrys, err)
+	return _c
+}
+
+func (_c *TransactionRepository_ListLedgerEntries_Call) RunAndReturn(run func(ctx context.Context, accountID uuid.UUID, since time.Time) ([]*dto.LedgerEntry, error)) *TransactionRepository_ListLedgerEntries_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SetLedgerCounted provides a mock function for the type TransactionRepository
+func (_mock *TransactionRepository) SetLedgerCounted(ctx context.Context, id uuid.UUID, counted int64) error {
+	ret := _mock.Called(ctx, id, counted)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetLedgerCounted")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, int64) error); ok {
+		r0 = returnFunc(ctx, id, counted)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// TransactionRepository_SetLedgerCounted_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetLedgerCounted'
+type TransactionRepository_SetLedgerCounted_Call struct {
+	*mock.Call
+}
+
+// SetLedgerCounted is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uuid.UUID
+//   - counted int64
+func (_e *TransactionRepository_Expecter) SetLedgerCounted(ctx interface{}, id interface{}, counted interface{}) *TransactionRepository_SetLedgerCounted_Call {
+	return &TransactionRepository_SetLedgerCounted_Call{Call: _e.mock.On("SetLedgerCounted", ctx, id, counted)}
+}
+
+func (_c *TransactionRepository_SetLedgerCounted_Call) Run(run func(ctx context.Context, id uuid.UUID, counted int64)) *TransactionRepository_SetLedgerCounted_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		var arg2 int64
+		if args[2] != nil {
+			arg2 = args[2].(int64)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *TransactionRepository_SetLedgerCounted_Call) Return(err error) *TransactionRepository_SetLedgerCounted_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *TransactionRepository_SetLedgerCounted_Call) RunAndReturn(run func(ctx context.Context, id uuid.UUID, counted int64) error) *TransactionRepository_SetLedgerCounted_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SumByUserMoneySource provides a mock function for the type TransactionRepository
+func (_mock *TransactionRepository) SumByUserMoneySource(ctx context.Context, userID uuid.UUID, moneySources []string, since time.Time) (map[string]int64, error) {
+	ret := _mock.Called(ctx, userID, moneySources, since)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SumByUserMoneySource")
+	}
+
+	var r0 map[string]int64
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, []string, time.Time) (map[string]int64, error)); ok {
+		return returnFunc(ctx, userID, moneySources, since)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, []string, time.Time) map[string]int64); ok {
+		r0 = returnFunc(ctx, userID, moneySources, since)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]int64)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, uuid.UUID, []string, time.Time) error); ok {
+		r1 = returnFunc(ctx, userID, moneySources, since)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// TransactionRepository_SumByUserMoneySource_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SumByUserMoneySource'
+type TransactionRepository_SumByUserMoneySource_Call struct {
+	*mock.Call
+}
+
+// SumByUserMoneySource is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID uuid.UUID
+//   - moneySources []string
+//   - since time.Time
+func (_e *TransactionRepository_Expecter) SumByUserMoneySource(ctx interface{}, userID interface{}, moneySources interface{}, since interface{}) *TransactionRepository_SumByUserMoneySource_Call {
+	return &TransactionRepository_SumByUserMoneySource_Call{Call: _e.mock.On("SumByUserMoneySource", ctx, userID, moneySources, since)}
+}
+
+func (_c *TransactionRepository_SumByUserMoneySource_Call) Run(run func(ctx context.Context, userID uuid.UUID, moneySources []string, since time.Time)) *TransactionRepository_SumByUserMoneySource_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		var arg2 []string
+		if args[2] != nil {
+			arg2 = args[2].([]string)
+		}
+		var arg3 time.Time
+		if args[3] != nil {
+			arg3 = args[3].(time.Time)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *TransactionRepository_SumByUserMoneySource_Call) Return(stringToInt64 map[string]int64, err error) *TransactionRepository_SumByUserMoneySource_Call {
+	_c.Call.Return(stringToInt64, err)
+	return _c
+}
+
+func (_c *TransactionRepository_SumByUserMoneySource_Call) RunAndReturn(run func(ctx context.Context, userID uuid.UUID, moneySources []string, since time.Time) (map[string]int64, error)) *TransactionRepository_SumByUserMoneySource_Call {
+	_c.Call.Return(run)
+	return _c
+}
@@ -0,0 +1,6 @@
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS scheduled_transfers;
+
+-- +goose StatementEnd
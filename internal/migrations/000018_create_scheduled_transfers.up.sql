@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE IF NOT EXISTS scheduled_transfers (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id),
+    from_account_id UUID NOT NULL REFERENCES accounts(id),
+    to_account_id UUID NOT NULL REFERENCES accounts(id),
+    amount BIGINT NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'pending',
+    execute_at TIMESTAMPTZ NOT NULL,
+    executed_at TIMESTAMPTZ,
+    failure_reason VARCHAR(255),
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW(),
+    deleted_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_scheduled_transfers_user_id ON scheduled_transfers(user_id);
+CREATE INDEX IF NOT EXISTS idx_scheduled_transfers_due
+    ON scheduled_transfers(execute_at)
+    WHERE status = 'pending';
+
+-- +goose StatementEnd
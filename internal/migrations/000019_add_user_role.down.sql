@@ -0,0 +1,7 @@
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS role;
+
+-- +goose StatementEnd
@@ -0,0 +1,9 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Authorization role; admins are promoted manually, e.g.
+-- UPDATE users SET role = 'admin' WHERE username = '...';
+ALTER TABLE users
+    ADD COLUMN role VARCHAR(16) NOT NULL DEFAULT 'user';
+
+-- +goose StatementEnd
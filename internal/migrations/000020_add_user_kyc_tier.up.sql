@@ -0,0 +1,8 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- KYC verification tier; drives deposit and withdrawal limits
+ALTER TABLE users
+    ADD COLUMN kyc_tier VARCHAR(16) NOT NULL DEFAULT 'unverified';
+
+-- +goose StatementEnd
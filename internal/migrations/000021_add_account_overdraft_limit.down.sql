@@ -0,0 +1,7 @@
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE accounts
+    DROP COLUMN IF EXISTS overdraft_limit;
+
+-- +goose StatementEnd
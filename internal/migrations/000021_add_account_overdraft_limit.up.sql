@@ -0,0 +1,9 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- How far below zero the balance may go, in the smallest currency unit.
+-- Zero (the default) means the account has no overdraft.
+ALTER TABLE accounts
+    ADD COLUMN overdraft_limit BIGINT NOT NULL DEFAULT 0;
+
+-- +goose StatementEnd
@@ -0,0 +1,10 @@
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE transactions
+    DROP COLUMN IF EXISTS payment_method_type,
+    DROP COLUMN IF EXISTS payment_method_brand,
+    DROP COLUMN IF EXISTS payment_method_last4,
+    DROP COLUMN IF EXISTS payment_method_funding;
+
+-- +goose StatementEnd
@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Payment method that funded a deposit, for receipts. Brand and funding are
+-- only set for cards; other methods (bank debits, wallets) only have a type.
+ALTER TABLE transactions
+    ADD COLUMN payment_method_type VARCHAR(32) NOT NULL DEFAULT '',
+    ADD COLUMN payment_method_brand VARCHAR(32) NOT NULL DEFAULT '',
+    ADD COLUMN payment_method_last4 VARCHAR(4) NOT NULL DEFAULT '',
+    ADD COLUMN payment_method_funding VARCHAR(16) NOT NULL DEFAULT '';
+
+-- +goose StatementEnd
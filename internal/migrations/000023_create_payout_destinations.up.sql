@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE IF NOT EXISTS payout_destinations (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id),
+    label VARCHAR(64),
+    bank_account_number VARCHAR(34),
+    routing_number VARCHAR(12),
+    external_wallet_address VARCHAR(128),
+    network VARCHAR(32),
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW(),
+    deleted_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_payout_destinations_user_id
+    ON payout_destinations(user_id)
+    WHERE deleted_at IS NULL;
+
+-- +goose StatementEnd
@@ -0,0 +1,9 @@
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS idx_transactions_refunded_transaction_id;
+
+ALTER TABLE transactions
+    DROP COLUMN IF EXISTS refunded_transaction_id;
+
+-- +goose StatementEnd
@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Refund transactions point at the deposit they refund, so prior refunds can
+-- be netted off when validating a new one.
+ALTER TABLE transactions
+    ADD COLUMN refunded_transaction_id UUID REFERENCES transactions(id);
+
+CREATE INDEX IF NOT EXISTS idx_transactions_refunded_transaction_id
+    ON transactions(refunded_transaction_id);
+
+-- +goose StatementEnd
@@ -0,0 +1,12 @@
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE payout_destinations
+    DROP COLUMN IF EXISTS bic;
+
+ALTER TABLE transactions
+    DROP COLUMN IF EXISTS external_bic,
+    DROP COLUMN IF EXISTS external_routing_number,
+    DROP COLUMN IF EXISTS external_bank_account_number;
+
+-- +goose StatementEnd
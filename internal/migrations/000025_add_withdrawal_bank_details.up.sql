@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Withdrawals keep the bank account they pay out to, so payouts can be
+-- exported to the bank (ISO 20022 pain.001). IBAN payouts also need the BIC
+-- of the creditor's bank, which saved payout destinations now store too.
+ALTER TABLE transactions
+    ADD COLUMN external_bank_account_number VARCHAR(34) NOT NULL DEFAULT '',
+    ADD COLUMN external_routing_number VARCHAR(12) NOT NULL DEFAULT '',
+    ADD COLUMN external_bic VARCHAR(11) NOT NULL DEFAULT '';
+
+ALTER TABLE payout_destinations
+    ADD COLUMN bic VARCHAR(11);
+
+-- +goose StatementEnd
@@ -0,0 +1,13 @@
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS idx_transactions_account_id_updated_at;
+
+ALTER TABLE transactions
+    DROP COLUMN IF EXISTS ledger_amount;
+
+ALTER TABLE accounts
+    DROP COLUMN IF EXISTS ledger_verified_at,
+    DROP COLUMN IF EXISTS ledger_balance;
+
+-- +goose StatementEnd
@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Incremental ledger verification. Each account keeps the ledger balance
+-- verified so far and a watermark on transactions.updated_at; each
+-- transaction keeps the amount it contributed to that balance, so a check only
+-- reads the transactions updated since the watermark.
+ALTER TABLE accounts
+    ADD COLUMN ledger_balance BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN ledger_verified_at TIMESTAMPTZ;
+
+ALTER TABLE transactions
+    ADD COLUMN ledger_amount BIGINT NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS idx_transactions_account_id_updated_at
+    ON transactions (account_id, updated_at);
+
+-- +goose StatementEnd
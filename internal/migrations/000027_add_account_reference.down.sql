@@ -0,0 +1,9 @@
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS idx_accounts_reference;
+
+ALTER TABLE accounts
+    DROP COLUMN IF EXISTS reference;
+
+-- +goose StatementEnd
@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Human-friendly account references (e.g. FT-7K3M-9QXD-2HAB) that users can
+-- use in place of the account UUID. They are generated when an account is
+-- created, so accounts created earlier have none and are only addressable
+-- by UUID.
+ALTER TABLE accounts
+    ADD COLUMN reference VARCHAR(32);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_accounts_reference
+    ON accounts (reference);
+
+-- +goose StatementEnd
@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Events each handler has processed, recorded in the same transaction as the
+-- handler's work so that redelivered events (e.g. from the DLQ) do not
+-- repeat side effects.
+CREATE TABLE IF NOT EXISTS processed_events (
+    handler VARCHAR(128) NOT NULL,
+    event_key VARCHAR(128) NOT NULL,
+    processed_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (handler, event_key)
+);
+
+-- +goose StatementEnd
@@ -0,0 +1,10 @@
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE scheduled_transfers
+    DROP COLUMN IF EXISTS description;
+
+ALTER TABLE transactions
+    DROP COLUMN IF EXISTS description;
+
+-- +goose StatementEnd
@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Optional user-supplied notes on transactions, e.g. "Rent for March". Scheduled
+-- transfers keep theirs until they are executed.
+ALTER TABLE transactions
+    ADD COLUMN description VARCHAR(140) NOT NULL DEFAULT '';
+
+ALTER TABLE scheduled_transfers
+    ADD COLUMN description VARCHAR(140) NOT NULL DEFAULT '';
+
+-- +goose StatementEnd
@@ -0,0 +1,9 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Whether deposits in another currency are converted to the account currency.
+-- When false they are rejected. Existing accounts keep converting.
+ALTER TABLE accounts
+    ADD COLUMN auto_convert BOOLEAN NOT NULL DEFAULT TRUE;
+
+-- +goose StatementEnd
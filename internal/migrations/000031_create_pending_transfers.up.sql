@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE IF NOT EXISTS pending_transfers (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id),
+    from_account_id UUID NOT NULL REFERENCES accounts(id),
+    to_account_id UUID NOT NULL REFERENCES accounts(id),
+    transaction_id UUID NOT NULL REFERENCES transactions(id),
+    amount BIGINT NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+    description VARCHAR(140) NOT NULL DEFAULT '',
+    status VARCHAR(16) NOT NULL DEFAULT 'pending',
+    decided_by UUID REFERENCES users(id),
+    decided_at TIMESTAMPTZ,
+    reason VARCHAR(255),
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW(),
+    deleted_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_pending_transfers_user_id ON pending_transfers(user_id);
+CREATE INDEX IF NOT EXISTS idx_pending_transfers_pending
+    ON pending_transfers(created_at)
+    WHERE status = 'pending';
+
+-- +goose StatementEnd
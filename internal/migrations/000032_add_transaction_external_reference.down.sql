@@ -0,0 +1,9 @@
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS idx_transactions_account_external_reference;
+
+ALTER TABLE transactions
+    DROP COLUMN IF EXISTS external_reference;
+
+-- +goose StatementEnd
@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Reference of a transaction imported from another system, e.g. the
+-- transaction ID there. Re-importing the same reference into an account is a
+-- no-op.
+ALTER TABLE transactions
+    ADD COLUMN external_reference VARCHAR(64);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_transactions_account_external_reference
+    ON transactions(account_id, external_reference)
+    WHERE external_reference IS NOT NULL;
+
+-- +goose StatementEnd
@@ -0,0 +1,7 @@
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS stripe_customer_id;
+
+-- +goose StatementEnd
@@ -0,0 +1,9 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Stripe customer the user pays deposits as, created on the user's first
+-- payment so Checkout can reuse saved payment methods.
+ALTER TABLE users
+    ADD COLUMN stripe_customer_id VARCHAR(255);
+
+-- +goose StatementEnd
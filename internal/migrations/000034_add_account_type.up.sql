@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Kind of account: checking, savings or wallet. It decides the rules the
+-- account follows, such as how often savings can be withdrawn from.
+-- Existing accounts are checking accounts.
+ALTER TABLE accounts
+    ADD COLUMN type VARCHAR(16) NOT NULL DEFAULT 'checking';
+
+-- +goose StatementEnd
@@ -0,0 +1,10 @@
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS idx_accounts_type;
+
+ALTER TABLE accounts
+    DROP COLUMN IF EXISTS interest_accrued_on,
+    DROP COLUMN IF EXISTS interest_accrued;
+
+-- +goose StatementEnd
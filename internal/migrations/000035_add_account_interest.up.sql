@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Interest credited to the account so far, in the smallest currency unit,
+-- and the last day (UTC) interest was accrued for. Interest is accrued at
+-- most once per day.
+ALTER TABLE accounts
+    ADD COLUMN interest_accrued BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN interest_accrued_on DATE;
+
+CREATE INDEX IF NOT EXISTS idx_accounts_type ON accounts(type);
+
+-- +goose StatementEnd
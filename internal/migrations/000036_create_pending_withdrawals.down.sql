@@ -0,0 +1,7 @@
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS pending_withdrawal_approvals;
+DROP TABLE IF EXISTS pending_withdrawals;
+
+-- +goose StatementEnd
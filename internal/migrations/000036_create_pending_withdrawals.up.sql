@@ -0,0 +1,42 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE IF NOT EXISTS pending_withdrawals (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id),
+    account_id UUID NOT NULL REFERENCES accounts(id),
+    transaction_id UUID NOT NULL REFERENCES transactions(id),
+    amount BIGINT NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+    hold_amount BIGINT NOT NULL,
+    hold_currency VARCHAR(3) NOT NULL,
+    description VARCHAR(140) NOT NULL DEFAULT '',
+    bank_account_number VARCHAR(34) NOT NULL DEFAULT '',
+    routing_number VARCHAR(9) NOT NULL DEFAULT '',
+    bic VARCHAR(11) NOT NULL DEFAULT '',
+    external_wallet_address VARCHAR(128) NOT NULL DEFAULT '',
+    network VARCHAR(32) NOT NULL DEFAULT '',
+    required_approvals INTEGER NOT NULL DEFAULT 1,
+    status VARCHAR(16) NOT NULL DEFAULT 'pending',
+    decided_by UUID REFERENCES users(id),
+    decided_at TIMESTAMPTZ,
+    reason VARCHAR(255),
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW(),
+    deleted_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_pending_withdrawals_user_id ON pending_withdrawals(user_id);
+CREATE INDEX IF NOT EXISTS idx_pending_withdrawals_pending
+    ON pending_withdrawals(created_at)
+    WHERE status = 'pending';
+
+-- One row per approver, so an approver is counted once.
+CREATE TABLE IF NOT EXISTS pending_withdrawal_approvals (
+    pending_withdrawal_id UUID NOT NULL REFERENCES pending_withdrawals(id) ON DELETE CASCADE,
+    approver_id UUID NOT NULL REFERENCES users(id),
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    PRIMARY KEY (pending_withdrawal_id, approver_id)
+);
+
+-- +goose StatementEnd
@@ -0,0 +1,9 @@
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS idx_transactions_user_money_source;
+
+ALTER TABLE transactions
+    DROP COLUMN IF EXISTS payout_estimated_arrival;
+
+-- +goose StatementEnd
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- When the payout of a withdrawal is estimated to arrive, as reported by the
+-- payout provider. NULL until the payout is initiated.
+ALTER TABLE transactions
+    ADD COLUMN payout_estimated_arrival TIMESTAMPTZ;
+
+-- Serves the payout history of a user, newest first.
+CREATE INDEX IF NOT EXISTS idx_transactions_user_money_source
+    ON transactions(user_id, money_source, created_at DESC);
+
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE transactions
+    DROP COLUMN IF EXISTS fee_policy,
+    DROP COLUMN IF EXISTS platform_fee;
+
+ALTER TABLE accounts
+    DROP COLUMN IF EXISTS fee_policy;
+
+-- +goose StatementEnd
@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Who bears the provider fee on deposits to the account: absorb or
+-- pass_through. NULL follows FEE_PROVIDER_FEE_POLICY.
+ALTER TABLE accounts
+    ADD COLUMN fee_policy VARCHAR(16);
+
+-- The provider fee the platform absorbed instead of deducting it from the
+-- account, in the smallest currency unit, and the policy applied to the
+-- transaction's provider fee (NULL if it had none).
+ALTER TABLE transactions
+    ADD COLUMN platform_fee BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN fee_policy VARCHAR(16);
+
+-- +goose StatementEnd
@@ -0,0 +1,9 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Whether the account may move money: active, or frozen while it is held
+-- for review, e.g. after suspicious activity.
+ALTER TABLE accounts
+    ADD COLUMN status VARCHAR(16) NOT NULL DEFAULT 'active';
+
+-- +goose StatementEnd
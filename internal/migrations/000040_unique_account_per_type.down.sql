@@ -0,0 +1,11 @@
+-- +goose Down
+-- +goose StatementBegin
+
+-- Fails if a user holds accounts of several types in one currency.
+ALTER TABLE accounts
+    DROP CONSTRAINT IF EXISTS uidx_user_currency_type;
+
+ALTER TABLE accounts
+    ADD CONSTRAINT uidx_user_currency UNIQUE (user_id, currency);
+
+-- +goose StatementEnd
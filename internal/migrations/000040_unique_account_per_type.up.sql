@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- A user may hold one account per currency and type, e.g. a checking and a
+-- savings account in USD, so the constraint from 000016 now includes type.
+ALTER TABLE accounts
+    DROP CONSTRAINT IF EXISTS uidx_user_currency;
+
+ALTER TABLE accounts
+    ADD CONSTRAINT uidx_user_currency_type UNIQUE (user_id, currency, type);
+
+-- +goose StatementEnd
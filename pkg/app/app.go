@@ -1,14 +1,24 @@
 package app
 
 import (
+	"database/sql"
 	"log/slog"
+	"time"
 
 	"github.com/amirasaad/fintech/pkg/service/checkout"
 	exchangeSvc "github.com/amirasaad/fintech/pkg/service/exchange"
+	"github.com/amirasaad/fintech/pkg/service/featureflag"
+	"github.com/amirasaad/fintech/pkg/service/maintenance"
+	"github.com/amirasaad/fintech/pkg/service/payoutexport"
+	"github.com/amirasaad/fintech/pkg/service/reconciliation"
 	"github.com/amirasaad/fintech/pkg/service/stripeconnect"
 
+	"github.com/amirasaad/fintech/pkg/commandbus"
 	"github.com/amirasaad/fintech/pkg/config"
 	"github.com/amirasaad/fintech/pkg/eventbus"
+	analyticsHandler "github.com/amirasaad/fintech/pkg/handler/analytics"
+	"github.com/amirasaad/fintech/pkg/money"
+	"github.com/amirasaad/fintech/pkg/provider/analytics"
 	"github.com/amirasaad/fintech/pkg/provider/exchange"
 	"github.com/amirasaad/fintech/pkg/provider/payment"
 	"github.com/amirasaad/fintech/pkg/registry"
@@ -26,13 +36,37 @@ type Deps struct {
 	CurrencyRegistry     registry.Provider // For currency service
 	CheckoutRegistry     registry.Provider // For checkout service
 	ExchangeRateRegistry registry.Provider // For exchange rate service
+	FeatureFlagRegistry  registry.Provider // For feature flags; in-memory if nil
 
 	// Other dependencies
 	ExchangeRateProvider exchange.Exchange
+	ExchangeRateQuota    *exchange.QuotaProvider // Optional; counts provider calls
 	PaymentProvider      payment.Payment
+	PayoutProvider       payment.Payout // Pays withdrawals out; PaymentProvider if nil
 	Uow                  repository.UnitOfWork
 	EventBus             eventbus.Bus
 	Logger               *slog.Logger
+	DBPool               DBStatsProvider // Optional; exposes pool stats via /metrics
+	AnalyticsSink        analytics.Sink  // Optional; receives exported analytics events
+	AnalyticsDLQ         analytics.Sink  // Optional; receives batches AnalyticsSink rejected
+	// DBReplicaPools are the read replicas' pools, exposed like DBPool.
+	DBReplicaPools []DBStatsProvider
+	// TransactionCache caches account transaction lists; optional.
+	TransactionCache account.TransactionCache
+	// AccountLocker serializes operations per account across instances;
+	// accounts are locked within the process if nil.
+	AccountLocker account.AccountLocker
+	// MaintenanceStore keeps the maintenance mode switch; in-memory if nil.
+	MaintenanceStore maintenance.Store
+	// IdempotencyStore keeps the idempotency keys of commands; in-memory if
+	// nil.
+	IdempotencyStore commandbus.IdempotencyStore
+}
+
+// DBStatsProvider exposes database connection pool statistics.
+// *sql.DB satisfies it.
+type DBStatsProvider interface {
+	Stats() sql.DBStats
 }
 
 type App struct {
@@ -44,7 +78,19 @@ type App struct {
 	CurrencyService      *currencyScv.Service
 	CheckoutService      *checkout.Service
 	ExchangeRateService  *exchangeSvc.Service
+	FeatureFlagService   *featureflag.Service
+	MaintenanceService   *maintenance.Service
+	PayoutExportService  *payoutexport.Service
 	StripeConnectService stripeconnect.Service
+	TransferScheduler    *account.TransferScheduler
+	InterestAccruer      *account.InterestAccruer
+	// AnalyticsExporter is nil unless analytics export is enabled.
+	AnalyticsExporter *analyticsHandler.Exporter
+	// ReconciliationService is nil when the payment provider cannot list payouts.
+	ReconciliationService *reconciliation.Service
+	// CommandBus dispatches account commands to AccountService.
+	CommandBus     commandbus.Bus
+	CommandMetrics *commandbus.Metrics
 }
 
 func New(deps *Deps, cfg *config.App) *App {
@@ -75,12 +121,20 @@ func New(deps *Deps, cfg *config.App) *App {
 	}
 	// Initialize user service with Unit of Work
 	app.UserService = userSvc.New(deps.Uow, deps.Logger)
-	app.AccountService = account.New(
+	app.TransferScheduler = account.NewTransferScheduler(
 		deps.EventBus,
 		deps.Uow,
 		deps.Logger,
-		app.StripeConnectService,
+		cfg.TransferScheduler,
 	)
+	app.InterestAccruer = account.NewInterestAccruer(deps.Uow, deps.Logger, cfg.Interest).
+		WithTransactionCache(deps.TransactionCache)
+
+	app.PayoutExportService = payoutexport.New(deps.Uow, cfg.PayoutExport, deps.Logger)
+
+	if payouts, ok := deps.PaymentProvider.(payment.PayoutLister); ok {
+		app.ReconciliationService = reconciliation.New(deps.Uow, payouts, deps.Logger)
+	}
 
 	// Initialize services with their respective registry providers
 	app.CurrencyService = currencyScv.New(
@@ -93,11 +147,150 @@ func New(deps *Deps, cfg *config.App) *App {
 		deps.Logger,
 	)
 
+	flagRegistry := deps.FeatureFlagRegistry
+	if flagRegistry == nil {
+		flagRegistry = registry.NewBasicRegistry()
+	}
+	app.FeatureFlagService = featureflag.New(flagRegistry, deps.Logger)
+
+	var maintenanceStore maintenance.Store = maintenance.NewMemoryStore()
+	if deps.MaintenanceStore != nil {
+		maintenanceStore = deps.MaintenanceStore
+	}
+	app.MaintenanceService = maintenance.New(
+		maintenanceStore,
+		cfg.Maintenance != nil && cfg.Maintenance.Enabled,
+		deps.Logger,
+	)
+	app.TransferScheduler.WithMaintenance(app.MaintenanceService)
+	app.InterestAccruer.WithMaintenance(app.MaintenanceService)
+
 	app.ExchangeRateService = exchangeSvc.New(
 		deps.ExchangeRateRegistry,
 		deps.ExchangeRateProvider,
 		deps.Logger,
+		app.exchangeOptions()...,
 	)
 
+	var redirectHosts []string
+	if cfg.PaymentProviders != nil && cfg.PaymentProviders.Stripe != nil {
+		redirectHosts = cfg.PaymentProviders.Stripe.RedirectAllowedHosts
+	}
+	accountOpts := []account.Option{
+		account.WithCurrencyConverter(app.ExchangeRateService),
+		account.WithKycLimits(cfg.Kyc, app.ExchangeRateService),
+		account.WithRedirectAllowedHosts(redirectHosts),
+		account.WithCurrencyRegistry(app.CurrencyService),
+		account.WithInterest(cfg.Interest),
+		account.WithWithdrawalApproval(cfg.Withdraw),
+	}
+	if cfg.Auth != nil && cfg.Auth.Jwt != nil {
+		var receiptTTL time.Duration
+		if cfg.Receipts != nil {
+			receiptTTL = cfg.Receipts.TTL
+		}
+		accountOpts = append(accountOpts, account.WithReceipts(
+			cfg.Receipts.SigningKey(cfg.Auth.Jwt.Secret), receiptTTL,
+		))
+	}
+	if cfg.Account != nil {
+		accountOpts = append(
+			accountOpts,
+			account.WithReferencePrefix(cfg.Account.ReferencePrefix),
+			account.WithAccountTypeRules(cfg.Account),
+		)
+	}
+	if refunder, ok := deps.PaymentProvider.(payment.Refunder); ok {
+		accountOpts = append(accountOpts, account.WithRefunder(refunder))
+	}
+	if canceler, ok := deps.PaymentProvider.(payment.CheckoutCanceler); ok {
+		accountOpts = append(accountOpts, account.WithCheckoutCanceler(canceler))
+	}
+	if capturer, ok := deps.PaymentProvider.(payment.Capturer); ok {
+		accountOpts = append(accountOpts, account.WithCapturer(capturer))
+	}
+	if deps.TransactionCache != nil {
+		accountOpts = append(accountOpts, account.WithTransactionCache(deps.TransactionCache))
+	}
+	if cfg.AccountLock != nil {
+		accountOpts = append(accountOpts, account.WithAccountLocker(
+			deps.AccountLocker, cfg.AccountLock.Timeout,
+		))
+	}
+	app.AccountService = account.New(
+		deps.EventBus,
+		deps.Uow,
+		deps.Logger,
+		app.StripeConnectService,
+		accountOpts...,
+	)
+	app.setupTransactionCacheHandlers()
+
+	app.CommandMetrics = commandbus.NewMetrics()
+	cmdBus := commandbus.New(
+		commandbus.Tracing(),
+		app.CommandMetrics.Middleware(),
+		commandbus.Logging(deps.Logger),
+		commandbus.Authenticated(),
+		commandbus.Validation(),
+		commandbus.Idempotency(app.idempotencyStore(), app.idempotencyTTL()),
+	)
+	app.AccountService.RegisterCommands(cmdBus)
+	app.CommandBus = cmdBus
+
 	return app
 }
+
+// idempotencyStore returns the configured idempotency store, or one local to
+// the process.
+func (a *App) idempotencyStore() commandbus.IdempotencyStore {
+	if a.Deps.IdempotencyStore != nil {
+		return a.Deps.IdempotencyStore
+	}
+	return commandbus.NewMemoryIdempotencyStore()
+}
+
+// idempotencyTTL returns how long idempotency keys are kept.
+func (a *App) idempotencyTTL() time.Duration {
+	if a.Config == nil || a.Config.Idempotency == nil {
+		return commandbus.DefaultIdempotencyTTL
+	}
+	return a.Config.Idempotency.TTL
+}
+
+// payoutProvider returns the provider that pays withdrawals out: the
+// configured payout provider, or the payment provider if it can pay out.
+func (a *App) payoutProvider() payment.Payout {
+	if a.Deps.PayoutProvider != nil {
+		return a.Deps.PayoutProvider
+	}
+	payout, _ := a.Deps.PaymentProvider.(payment.Payout)
+	return payout
+}
+
+// exchangeOptions returns the exchange service options set in the config.
+// An invalid rounding mode or allowlist, which config validation rejects, is
+// left at its default.
+func (a *App) exchangeOptions() []exchangeSvc.Option {
+	if a.Config == nil {
+		return nil
+	}
+	var opts []exchangeSvc.Option
+	if a.Config.ExchangeRateCache != nil {
+		opts = append(opts, exchangeSvc.WithRateMaxAge(a.Config.ExchangeRateCache.TTL))
+	}
+	if a.Config.Exchange == nil {
+		return opts
+	}
+	if mode, err := money.ParseRoundingMode(a.Config.Exchange.RoundingMode); err == nil {
+		opts = append(opts, exchangeSvc.WithRoundingMode(mode))
+	}
+	allowlist, err := exchangeSvc.NewPairAllowlist(
+		a.Config.Exchange.AllowedPairs,
+		a.Config.Exchange.AllowedCurrencies,
+	)
+	if err == nil {
+		opts = append(opts, exchangeSvc.WithPairAllowlist(allowlist))
+	}
+	return opts
+}
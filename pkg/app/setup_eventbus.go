@@ -5,18 +5,23 @@ package app
 import (
 	"log/slog"
 
+	"github.com/amirasaad/fintech/pkg/domain/account"
 	"github.com/amirasaad/fintech/pkg/domain/events"
 	"github.com/amirasaad/fintech/pkg/eventbus"
 	"github.com/amirasaad/fintech/pkg/handler/account/deposit"
 	"github.com/amirasaad/fintech/pkg/handler/account/transfer"
 	"github.com/amirasaad/fintech/pkg/handler/account/withdraw"
+	"github.com/amirasaad/fintech/pkg/handler/analytics"
+	"github.com/amirasaad/fintech/pkg/handler/anomaly"
 	handlercommon "github.com/amirasaad/fintech/pkg/handler/common"
 	"github.com/amirasaad/fintech/pkg/handler/conversion"
 	"github.com/amirasaad/fintech/pkg/handler/fees"
+	"github.com/amirasaad/fintech/pkg/handler/ledger"
 	"github.com/amirasaad/fintech/pkg/handler/payment"
 	"github.com/amirasaad/fintech/pkg/provider/exchange"
 
 	"github.com/amirasaad/fintech/pkg/repository"
+	accountsvc "github.com/amirasaad/fintech/pkg/service/account"
 )
 
 // setupEventBus registers all event handlers with the provided event Bus.
@@ -39,6 +44,9 @@ func (a *App) setupEventBus() {
 	a.setupTransferHandlers(bus, uow, logger)
 	a.setupFeesHandlers(bus, uow, logger)
 	a.setupUserHandlers(bus, uow, logger)
+	a.setupLedgerHandlers(bus, uow, logger)
+	a.setupAnalyticsHandlers(bus, logger)
+	a.setupAnomalyHandlers(bus, uow, logger)
 
 }
 
@@ -83,10 +91,17 @@ func (a *App) setupWithdrawHandlers(
 		withdraw.HandleValidated(
 			bus,
 			uow,
-			a.Deps.PaymentProvider,
+			a.payoutProvider(),
 			a.Deps.Logger,
 		),
 	)
+	bus.Register(
+		events.EventTypeWithdrawFailed,
+		withdraw.HandleFailed(
+			uow,
+			logger,
+		),
+	)
 }
 
 func (a *App) setupPaymentHandlers(
@@ -98,14 +113,26 @@ func (a *App) setupPaymentHandlers(
 	initiatedTracker := handlercommon.NewIdempotencyTracker()
 	processedTracker := handlercommon.NewIdempotencyTracker()
 	completedTracker := handlercommon.NewIdempotencyTracker()
+	failedTracker := handlercommon.NewIdempotencyTracker()
+	refundCompletedTracker := handlercommon.NewIdempotencyTracker()
+	refundFailedTracker := handlercommon.NewIdempotencyTracker()
+	reversedTracker := handlercommon.NewIdempotencyTracker()
 
 	// Register handlers with idempotency middleware
 	bus.Register(
 		events.EventTypePaymentInitiated,
 		handlercommon.WithIdempotency(
-			payment.HandleInitiated(
-				bus,
-				a.Deps.PaymentProvider,
+			// Initiating a payment calls the provider, so processed events
+			// are also recorded durably to survive restarts and DLQ retries.
+			handlercommon.WithProcessedEvents(
+				payment.HandleInitiated(
+					bus,
+					a.Deps.PaymentProvider,
+					logger,
+				),
+				uow,
+				payment.ExtractPaymentInitiatedKey,
+				"HandleInitiated",
 				logger,
 			),
 			initiatedTracker,
@@ -141,6 +168,60 @@ func (a *App) setupPaymentHandlers(
 			logger,
 		),
 	)
+	bus.Register(
+		events.EventTypePaymentFailed,
+		handlercommon.WithIdempotency(
+			payment.HandleFailed(
+				bus,
+				uow,
+				logger,
+			),
+			failedTracker,
+			payment.ExtractPaymentFailedKey,
+			"HandleFailed",
+			logger,
+		),
+	)
+	bus.Register(
+		events.EventTypeRefundCompleted,
+		handlercommon.WithIdempotency(
+			payment.HandleRefundCompleted(
+				uow,
+				logger,
+			),
+			refundCompletedTracker,
+			payment.ExtractRefundCompletedKey,
+			"HandleRefundCompleted",
+			logger,
+		),
+	)
+	bus.Register(
+		events.EventTypeRefundFailed,
+		handlercommon.WithIdempotency(
+			payment.HandleRefundFailed(
+				uow,
+				logger,
+			),
+			refundFailedTracker,
+			payment.ExtractRefundFailedKey,
+			"HandleRefundFailed",
+			logger,
+		),
+	)
+	bus.Register(
+		events.EventTypePaymentReversed,
+		handlercommon.WithIdempotency(
+			payment.HandleReversed(
+				bus,
+				uow,
+				logger,
+			),
+			reversedTracker,
+			payment.ExtractPaymentReversedKey,
+			"HandleReversed",
+			logger,
+		),
+	)
 
 }
 
@@ -154,10 +235,113 @@ func (a *App) setupFeesHandlers(
 		fees.HandleCalculated(
 			uow,
 			logger,
+			a.feeAbsorption(),
 		),
 	)
 }
 
+// feeAbsorption returns the configured policy for provider fees on accounts
+// without a fee policy of their own; pass_through if unset or invalid.
+func (a *App) feeAbsorption() account.FeeAbsorption {
+	if a.Config == nil || a.Config.Fee == nil {
+		return account.FeeAbsorptionPassThrough
+	}
+	policy, err := account.ParseFeeAbsorption(a.Config.Fee.ProviderFeePolicy)
+	if err != nil {
+		return account.FeeAbsorptionPassThrough
+	}
+	return policy
+}
+
+// setupLedgerHandlers verifies account ledgers once the flows that move
+// money have completed. Verification is best-effort, so a failed check never
+// fails the flow it follows.
+func (a *App) setupLedgerHandlers(
+	bus eventbus.Bus,
+	uow repository.UnitOfWork,
+	logger *slog.Logger,
+) {
+	verify := ledger.HandleVerify(bus, uow, logger)
+	for _, eventType := range []events.EventType{
+		events.EventTypePaymentCompleted,
+		events.EventTypeFeesCalculated,
+		events.EventTypeRefundCompleted,
+		events.EventTypeRefundFailed,
+		events.EventTypeDepositReversed,
+		events.EventTypeTransferCompleted,
+		events.EventTypeTransferPending,
+		events.EventTypeTransferApproved,
+		events.EventTypeTransferRejected,
+		events.EventTypeWithdrawPending,
+		events.EventTypeWithdrawApproved,
+		events.EventTypeWithdrawRejected,
+	} {
+		eventbus.RegisterWithPhase(bus, eventType, verify, eventbus.PhaseBestEffort)
+	}
+}
+
+// setupTransactionCacheHandlers invalidates cached transaction lists once
+// the flows that change them have written their transactions. It runs after
+// AccountService is created, since the service owns the cache.
+func (a *App) setupTransactionCacheHandlers() {
+	if a.AccountService == nil || !a.AccountService.HasTransactionCache() {
+		return
+	}
+	invalidate := a.AccountService.HandleTransactionsChanged()
+	for _, eventType := range accountsvc.TransactionCacheEventTypes {
+		eventbus.RegisterWithPhase(a.Deps.EventBus, eventType, invalidate, eventbus.PhaseBestEffort)
+	}
+}
+
+// setupAnalyticsHandlers forwards the event types listed in
+// ANALYTICS_EVENT_TYPES to the analytics exporter. Export is best-effort, so
+// it never holds up or fails the flow that emitted the event.
+func (a *App) setupAnalyticsHandlers(
+	bus eventbus.Bus,
+	logger *slog.Logger,
+) {
+	cfg := a.Config.Analytics
+	if cfg == nil || !cfg.Enabled || a.Deps.AnalyticsSink == nil {
+		return
+	}
+	a.AnalyticsExporter = analytics.NewExporter(
+		a.Deps.AnalyticsSink,
+		a.Deps.AnalyticsDLQ,
+		logger,
+		cfg,
+	)
+	handle := a.AnalyticsExporter.Handle()
+	for _, eventType := range cfg.EventTypes {
+		if _, ok := events.EventTypes[events.EventType(eventType)]; !ok {
+			logger.Warn("unknown analytics event type", "event_type", eventType)
+		}
+		eventbus.RegisterWithPhase(
+			bus,
+			events.EventType(eventType),
+			handle,
+			eventbus.PhaseBestEffort,
+		)
+	}
+}
+
+// setupAnomalyHandlers flags accounts with abnormal activity as their
+// transactions are requested. Detection is best-effort, so it never holds up
+// or fails the flow it watches.
+func (a *App) setupAnomalyHandlers(
+	bus eventbus.Bus,
+	uow repository.UnitOfWork,
+	logger *slog.Logger,
+) {
+	cfg := a.Config.Anomaly
+	if cfg == nil || !cfg.Enabled {
+		return
+	}
+	handle := anomaly.NewDetector(bus, uow, logger, cfg).Handle()
+	for _, eventType := range anomaly.EventTypes {
+		eventbus.RegisterWithPhase(bus, eventType, handle, eventbus.PhaseBestEffort)
+	}
+}
+
 func (a *App) setupTransferHandlers(
 	bus eventbus.Bus,
 	uow repository.UnitOfWork,
@@ -243,6 +427,7 @@ func (a *App) setupConversionHandlers(
 			exchangeRateProvider,
 			logger,
 			conversionFactories,
+			a.exchangeOptions()...,
 		),
 	)
 }
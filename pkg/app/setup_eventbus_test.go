@@ -0,0 +1,93 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/amirasaad/fintech/infra/eventbus"
+	"github.com/amirasaad/fintech/infra/provider/stripepayment"
+	"github.com/amirasaad/fintech/internal/fixtures/mocks"
+	"github.com/amirasaad/fintech/pkg/config"
+	"github.com/amirasaad/fintech/pkg/domain/account"
+	"github.com/amirasaad/fintech/pkg/dto"
+	"github.com/amirasaad/fintech/pkg/registry"
+	"github.com/amirasaad/fintech/pkg/repository"
+	repotransaction "github.com/amirasaad/fintech/pkg/repository/transaction"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/stripe/stripe-go/v82"
+	"github.com/stripe/stripe-go/v82/webhook"
+)
+
+// TestCanceledPaymentIntentFailsDeposit follows a payment_intent.canceled
+// webhook through the registered handlers to the deposit transaction.
+func TestCanceledPaymentIntentFailsDeposit(t *testing.T) {
+	const secret = "whsec_test"
+	logger := slog.Default()
+	ctx := context.Background()
+	txID := uuid.New()
+
+	txRepo := mocks.NewTransactionRepository(t)
+	uow := mocks.NewUnitOfWork(t)
+	uow.EXPECT().
+		GetRepository((*repotransaction.Repository)(nil)).
+		Return(txRepo, nil)
+	uow.EXPECT().Do(mock.Anything, mock.Anything).RunAndReturn(
+		func(ctx context.Context, fn func(repository.UnitOfWork) error) error {
+			return fn(uow)
+		},
+	)
+	txRepo.EXPECT().Get(mock.Anything, txID).Return(&dto.TransactionRead{
+		ID:     txID,
+		Status: string(account.TransactionStatusProcessed),
+	}, nil).Once()
+	failed := string(account.TransactionStatusFailed)
+	txRepo.EXPECT().
+		Update(mock.Anything, txID, mock.MatchedBy(func(u dto.TransactionUpdate) bool {
+			return u.Status != nil && *u.Status == failed &&
+				u.PaymentID != nil && *u.PaymentID == "pi_1"
+		})).
+		Return(nil).
+		Once()
+
+	bus := eventbus.NewWithMemory(logger)
+	provider := stripepayment.New(
+		bus,
+		registry.NewEnhanced(registry.Config{Name: "test-checkout"}),
+		&config.Stripe{SigningSecret: secret},
+		logger,
+		uow,
+	)
+	a := &App{Deps: &Deps{EventBus: bus, Uow: uow, Logger: logger, PaymentProvider: provider}}
+	a.setupPaymentHandlers(bus, uow, logger)
+
+	raw, err := json.Marshal(stripe.PaymentIntent{
+		ID:                 "pi_1",
+		Status:             stripe.PaymentIntentStatusCanceled,
+		CancellationReason: stripe.PaymentIntentCancellationReasonAutomatic,
+		Metadata: map[string]string{
+			"user_id":        uuid.NewString(),
+			"account_id":     uuid.NewString(),
+			"transaction_id": txID.String(),
+		},
+	})
+	require.NoError(t, err)
+	payload, err := json.Marshal(map[string]any{
+		"id":          "evt_1",
+		"object":      "event",
+		"type":        "payment_intent.canceled",
+		"api_version": stripe.APIVersion,
+		"data":        map[string]any{"object": json.RawMessage(raw)},
+	})
+	require.NoError(t, err)
+	signed := webhook.GenerateTestSignedPayload(&webhook.UnsignedPayload{
+		Payload: payload,
+		Secret:  secret,
+	})
+
+	_, err = provider.HandleWebhook(ctx, signed.Payload, signed.Header)
+	require.NoError(t, err)
+}
@@ -0,0 +1,95 @@
+// Package commandbus routes command structs to their handlers through a chain
+// of cross-cutting middleware (logging, metrics, authentication, validation,
+// idempotency), so callers such as the HTTP handlers only build the command
+// and dispatch it.
+package commandbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+var (
+	// ErrNoHandler is returned when a command is dispatched with no
+	// registered handler.
+	ErrNoHandler = errors.New("no handler registered for command")
+	// ErrInvalidCommand wraps the validation error of a rejected command.
+	ErrInvalidCommand = errors.New("invalid command")
+	// ErrUnauthenticated is returned when a user-scoped command carries no user.
+	ErrUnauthenticated = errors.New("command requires an authenticated user")
+)
+
+// Command is a request to change state. Each command name is handled by
+// exactly one handler.
+type Command interface {
+	CommandName() string
+}
+
+// HandlerFunc handles a dispatched command.
+type HandlerFunc func(ctx context.Context, cmd Command) error
+
+// Middleware wraps a HandlerFunc with cross-cutting behavior.
+type Middleware func(next HandlerFunc) HandlerFunc
+
+// Bus defines a registry-based command bus.
+type Bus interface {
+	Register(name string, handler HandlerFunc)
+	Dispatch(ctx context.Context, cmd Command) error
+}
+
+// MemoryBus is an in-process Bus. Middleware is applied to every handler in
+// the order given, the first being the outermost.
+type MemoryBus struct {
+	mu         sync.RWMutex
+	handlers   map[string]HandlerFunc
+	middleware []Middleware
+}
+
+// New creates a MemoryBus that applies the given middleware to every command.
+func New(middleware ...Middleware) *MemoryBus {
+	return &MemoryBus{
+		handlers:   make(map[string]HandlerFunc),
+		middleware: middleware,
+	}
+}
+
+// Register sets the handler for the named command. Registering a second
+// handler for the same name is a programming error and panics.
+func (b *MemoryBus) Register(name string, handler HandlerFunc) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if _, ok := b.handlers[name]; ok {
+		panic(fmt.Sprintf("commandbus: duplicate handler for %q", name))
+	}
+	for i := len(b.middleware) - 1; i >= 0; i-- {
+		handler = b.middleware[i](handler)
+	}
+	b.handlers[name] = handler
+}
+
+// Dispatch runs the handler registered for cmd.
+func (b *MemoryBus) Dispatch(ctx context.Context, cmd Command) error {
+	b.mu.RLock()
+	handler, ok := b.handlers[cmd.CommandName()]
+	b.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrNoHandler, cmd.CommandName())
+	}
+	return handler(ctx, cmd)
+}
+
+// Handle registers a typed handler for commands of type C, using the name
+// reported by C's zero value.
+func Handle[C Command](b Bus, handler func(ctx context.Context, cmd C) error) {
+	var zero C
+	name := zero.CommandName()
+	b.Register(name, func(ctx context.Context, cmd Command) error {
+		c, ok := cmd.(C)
+		if !ok {
+			return fmt.Errorf("commandbus: %s dispatched as unexpected type %T", name, cmd)
+		}
+		return handler(ctx, c)
+	})
+}
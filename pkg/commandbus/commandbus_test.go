@@ -0,0 +1,209 @@
+package commandbus_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/amirasaad/fintech/infra/caching"
+	"github.com/amirasaad/fintech/pkg/commandbus"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testCommand struct {
+	UserID uuid.UUID
+	Key    string
+	Amount int
+}
+
+func (testCommand) CommandName() string             { return "test.command" }
+func (c testCommand) CommandUserID() uuid.UUID      { return c.UserID }
+func (c testCommand) CommandIdempotencyKey() string { return c.Key }
+func (c testCommand) Validate() error {
+	if c.Amount <= 0 {
+		return errors.New("amount must be positive")
+	}
+	return nil
+}
+
+type otherCommand struct{}
+
+func (otherCommand) CommandName() string { return "test.other" }
+
+func TestDispatch(t *testing.T) {
+	bus := commandbus.New()
+	var got testCommand
+	commandbus.Handle(bus, func(_ context.Context, cmd testCommand) error {
+		got = cmd
+		return nil
+	})
+
+	cmd := testCommand{UserID: uuid.New(), Amount: 5}
+	require.NoError(t, bus.Dispatch(context.Background(), cmd))
+	assert.Equal(t, cmd, got)
+
+	err := bus.Dispatch(context.Background(), otherCommand{})
+	require.ErrorIs(t, err, commandbus.ErrNoHandler)
+
+	assert.Panics(t, func() {
+		commandbus.Handle(bus, func(context.Context, testCommand) error { return nil })
+	})
+}
+
+func TestMiddlewareOrder(t *testing.T) {
+	var calls []string
+	mw := func(name string) commandbus.Middleware {
+		return func(next commandbus.HandlerFunc) commandbus.HandlerFunc {
+			return func(ctx context.Context, cmd commandbus.Command) error {
+				calls = append(calls, name)
+				return next(ctx, cmd)
+			}
+		}
+	}
+	bus := commandbus.New(mw("outer"), mw("inner"))
+	commandbus.Handle(bus, func(context.Context, otherCommand) error {
+		calls = append(calls, "handler")
+		return nil
+	})
+	require.NoError(t, bus.Dispatch(context.Background(), otherCommand{}))
+	assert.Equal(t, []string{"outer", "inner", "handler"}, calls)
+}
+
+func TestAuthenticatedAndValidation(t *testing.T) {
+	bus := commandbus.New(commandbus.Authenticated(), commandbus.Validation())
+	handled := 0
+	commandbus.Handle(bus, func(context.Context, testCommand) error {
+		handled++
+		return nil
+	})
+	ctx := context.Background()
+
+	err := bus.Dispatch(ctx, testCommand{Amount: 5})
+	require.ErrorIs(t, err, commandbus.ErrUnauthenticated)
+
+	err = bus.Dispatch(ctx, testCommand{UserID: uuid.New()})
+	require.ErrorIs(t, err, commandbus.ErrInvalidCommand)
+
+	require.NoError(t, bus.Dispatch(ctx, testCommand{UserID: uuid.New(), Amount: 5}))
+	assert.Equal(t, 1, handled)
+}
+
+func TestIdempotency(t *testing.T) {
+	bus := commandbus.New(commandbus.Idempotency(commandbus.NewMemoryIdempotencyStore(), time.Hour))
+	handled := 0
+	fail := true
+	commandbus.Handle(bus, func(context.Context, testCommand) error {
+		handled++
+		if fail {
+			return errors.New("boom")
+		}
+		return nil
+	})
+	ctx := context.Background()
+	userID := uuid.New()
+	cmd := testCommand{UserID: userID, Key: "abc", Amount: 1}
+
+	// Failures are not recorded, so the client can retry.
+	require.Error(t, bus.Dispatch(ctx, cmd))
+	fail = false
+	require.NoError(t, bus.Dispatch(ctx, cmd))
+	require.NoError(t, bus.Dispatch(ctx, cmd))
+	assert.Equal(t, 2, handled)
+
+	// A key reused for a different command is refused.
+	reused := testCommand{UserID: userID, Key: "abc", Amount: 2}
+	require.ErrorIs(t, bus.Dispatch(ctx, reused), commandbus.ErrIdempotencyKeyReused)
+	assert.Equal(t, 2, handled)
+
+	// Keys are scoped per user, and commands without a key always run.
+	require.NoError(t, bus.Dispatch(ctx, testCommand{UserID: uuid.New(), Key: "abc"}))
+	require.NoError(t, bus.Dispatch(ctx, testCommand{UserID: userID}))
+	require.NoError(t, bus.Dispatch(ctx, testCommand{UserID: userID}))
+	assert.Equal(t, 5, handled)
+}
+
+func TestIdempotency_InProgress(t *testing.T) {
+	bus := commandbus.New(commandbus.Idempotency(commandbus.NewMemoryIdempotencyStore(), time.Hour))
+	started, release := make(chan struct{}), make(chan struct{})
+	commandbus.Handle(bus, func(context.Context, testCommand) error {
+		close(started)
+		<-release
+		return nil
+	})
+	ctx := context.Background()
+	cmd := testCommand{UserID: uuid.New(), Key: "abc", Amount: 1}
+
+	done := make(chan error)
+	go func() { done <- bus.Dispatch(ctx, cmd) }()
+	<-started
+	require.ErrorIs(t, bus.Dispatch(ctx, cmd), commandbus.ErrIdempotencyKeyInUse)
+	close(release)
+	require.NoError(t, <-done)
+	require.NoError(t, bus.Dispatch(ctx, cmd))
+}
+
+func TestIdempotency_KeysExpire(t *testing.T) {
+	bus := commandbus.New(commandbus.Idempotency(
+		commandbus.NewMemoryIdempotencyStore(),
+		10*time.Millisecond,
+	))
+	handled := 0
+	commandbus.Handle(bus, func(context.Context, testCommand) error {
+		handled++
+		return nil
+	})
+	ctx := context.Background()
+	cmd := testCommand{UserID: uuid.New(), Key: "abc", Amount: 1}
+
+	require.NoError(t, bus.Dispatch(ctx, cmd))
+	require.NoError(t, bus.Dispatch(ctx, cmd))
+	assert.Equal(t, 1, handled)
+	time.Sleep(20 * time.Millisecond)
+	require.NoError(t, bus.Dispatch(ctx, cmd))
+	assert.Equal(t, 2, handled)
+}
+
+func TestIdempotency_UnreachableStore(t *testing.T) {
+	// Nothing listens on port 1, so no key can be reserved.
+	client := redis.NewClient(&redis.Options{
+		Addr:        "127.0.0.1:1",
+		DialTimeout: 100 * time.Millisecond,
+		MaxRetries:  -1,
+	})
+	t.Cleanup(func() { _ = client.Close() })
+	bus := commandbus.New(commandbus.Idempotency(caching.NewIdempotencyStore(client, "test:"), time.Hour))
+	handled := 0
+	commandbus.Handle(bus, func(context.Context, testCommand) error {
+		handled++
+		return nil
+	})
+	ctx := context.Background()
+
+	require.Error(t, bus.Dispatch(ctx, testCommand{UserID: uuid.New(), Key: "abc", Amount: 1}))
+	assert.Zero(t, handled, "a command whose key cannot be checked must not run")
+	require.NoError(t, bus.Dispatch(ctx, testCommand{UserID: uuid.New(), Amount: 1}))
+}
+
+func TestMetrics(t *testing.T) {
+	metrics := commandbus.NewMetrics()
+	bus := commandbus.New(metrics.Middleware())
+	commandbus.Handle(bus, func(_ context.Context, cmd testCommand) error {
+		if cmd.Amount < 0 {
+			return errors.New("boom")
+		}
+		return nil
+	})
+	require.NoError(t, bus.Dispatch(context.Background(), testCommand{}))
+	require.Error(t, bus.Dispatch(context.Background(), testCommand{Amount: -1}))
+
+	var b bytes.Buffer
+	metrics.WritePrometheus(&b)
+	assert.Contains(t, b.String(), "# TYPE fintech_commands_total counter\n")
+	assert.Contains(t, b.String(), `fintech_commands_total{command="test.command"} 2`+"\n")
+	assert.Contains(t, b.String(), `fintech_commands_failed_total{command="test.command"} 1`+"\n")
+}
@@ -0,0 +1,111 @@
+package commandbus
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// DefaultIdempotencyTTL is how long idempotency keys are kept unless
+// configured otherwise.
+const DefaultIdempotencyTTL = 24 * time.Hour
+
+var (
+	// ErrIdempotencyKeyReused is returned when an idempotency key is sent
+	// again with a different command.
+	ErrIdempotencyKeyReused = errors.New("idempotency key was used for a different request")
+	// ErrIdempotencyKeyInUse is returned when a command is repeated while
+	// the first one with its idempotency key is still being handled.
+	ErrIdempotencyKeyInUse = errors.New("a request with this idempotency key is in progress")
+)
+
+// IdempotencyRecord is the stored outcome of the command an idempotency key
+// was first used with. Done is false while the command is being handled.
+type IdempotencyRecord struct {
+	Fingerprint string `json:"fingerprint"`
+	Done        bool   `json:"done"`
+}
+
+// outcome returns what a repeated command with the given fingerprint gets.
+func (r *IdempotencyRecord) outcome(fingerprint string) error {
+	switch {
+	case !r.Done:
+		return ErrIdempotencyKeyInUse
+	case r.Fingerprint != fingerprint:
+		return ErrIdempotencyKeyReused
+	}
+	return nil
+}
+
+// IdempotencyStore keeps idempotency keys and their records for a TTL.
+type IdempotencyStore interface {
+	// Reserve stores key as in progress for ttl unless it is already
+	// stored, in which case it returns the stored record. It returns nil
+	// when it reserved the key.
+	Reserve(ctx context.Context, key string, ttl time.Duration) (*IdempotencyRecord, error)
+	// Complete replaces the record of a reserved key, keeping it for ttl.
+	Complete(ctx context.Context, key string, record IdempotencyRecord, ttl time.Duration) error
+	// Release removes key, so it can be reserved again.
+	Release(ctx context.Context, key string) error
+}
+
+// MemoryIdempotencyStore is an IdempotencyStore local to the process. Expired
+// keys are dropped as new ones are reserved.
+type MemoryIdempotencyStore struct {
+	mu      sync.Mutex
+	records map[string]memoryIdempotencyEntry
+}
+
+type memoryIdempotencyEntry struct {
+	record    IdempotencyRecord
+	expiresAt time.Time
+}
+
+// NewMemoryIdempotencyStore creates an empty MemoryIdempotencyStore.
+func NewMemoryIdempotencyStore() *MemoryIdempotencyStore {
+	return &MemoryIdempotencyStore{records: make(map[string]memoryIdempotencyEntry)}
+}
+
+// Reserve implements IdempotencyStore.
+func (s *MemoryIdempotencyStore) Reserve(
+	_ context.Context,
+	key string,
+	ttl time.Duration,
+) (*IdempotencyRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	for k, e := range s.records {
+		if !now.Before(e.expiresAt) {
+			delete(s.records, k)
+		}
+	}
+	if e, ok := s.records[key]; ok {
+		record := e.record
+		return &record, nil
+	}
+	s.records[key] = memoryIdempotencyEntry{expiresAt: now.Add(ttl)}
+	return nil, nil
+}
+
+// Complete implements IdempotencyStore.
+func (s *MemoryIdempotencyStore) Complete(
+	_ context.Context,
+	key string,
+	record IdempotencyRecord,
+	ttl time.Duration,
+) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records[key] = memoryIdempotencyEntry{record: record, expiresAt: time.Now().Add(ttl)}
+	return nil
+}
+
+// Release implements IdempotencyStore.
+func (s *MemoryIdempotencyStore) Release(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.records, key)
+	return nil
+}
@@ -0,0 +1,250 @@
+package commandbus
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Validatable is implemented by commands that can check their own fields.
+type Validatable interface {
+	Validate() error
+}
+
+// UserScoped is implemented by commands issued on behalf of a user.
+type UserScoped interface {
+	CommandUserID() uuid.UUID
+}
+
+// Idempotent is implemented by commands that may carry a client-supplied
+// idempotency key. An empty key disables deduplication for that command.
+type Idempotent interface {
+	CommandIdempotencyKey() string
+}
+
+// Logging logs the outcome and duration of every command.
+func Logging(logger *slog.Logger) Middleware {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context, cmd Command) error {
+			start := time.Now()
+			err := next(ctx, cmd)
+			log := logger.With(
+				"command", cmd.CommandName(),
+				"duration", time.Since(start),
+			)
+			if err != nil {
+				log.Warn("Command failed", "error", err)
+				return err
+			}
+			log.Debug("Command handled")
+			return nil
+		}
+	}
+}
+
+// Tracing records a span for every command, in the trace of the request that
+// dispatched it.
+func Tracing() Middleware {
+	tracer := otel.Tracer("github.com/amirasaad/fintech/pkg/commandbus")
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context, cmd Command) error {
+			ctx, span := tracer.Start(ctx, cmd.CommandName(),
+				trace.WithAttributes(attribute.String("command", cmd.CommandName())),
+			)
+			defer span.End()
+			if us, ok := cmd.(UserScoped); ok {
+				span.SetAttributes(attribute.String("user.id", us.CommandUserID().String()))
+			}
+			err := next(ctx, cmd)
+			if err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+			}
+			return err
+		}
+	}
+}
+
+// Authenticated rejects user-scoped commands that carry no user ID.
+func Authenticated() Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context, cmd Command) error {
+			if us, ok := cmd.(UserScoped); ok && us.CommandUserID() == uuid.Nil {
+				return fmt.Errorf("%w: %s", ErrUnauthenticated, cmd.CommandName())
+			}
+			return next(ctx, cmd)
+		}
+	}
+}
+
+// Validation rejects commands whose Validate method fails, wrapping the
+// error in ErrInvalidCommand.
+func Validation() Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context, cmd Command) error {
+			if v, ok := cmd.(Validatable); ok {
+				if err := v.Validate(); err != nil {
+					return fmt.Errorf("%w: %w", ErrInvalidCommand, err)
+				}
+			}
+			return next(ctx, cmd)
+		}
+	}
+}
+
+// Idempotency handles each idempotency key at most once within ttl, keeping
+// the keys in store. Keys are scoped by command name and, for user-scoped
+// commands, by user. A key is reserved before the handler runs and, when the
+// handler succeeds, completed with a fingerprint of the command; it is
+// released when the handler fails, so the client can retry.
+//
+// A repeated command returns the stored outcome without reaching the handler:
+// nil for the same command, ErrIdempotencyKeyReused for a different command
+// under the same key, and ErrIdempotencyKeyInUse while the first is still
+// being handled, on this instance or another sharing store. If store cannot
+// be read, the command is not handled.
+func Idempotency(store IdempotencyStore, ttl time.Duration) Middleware {
+	if ttl <= 0 {
+		ttl = DefaultIdempotencyTTL
+	}
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context, cmd Command) error {
+			ic, ok := cmd.(Idempotent)
+			if !ok || ic.CommandIdempotencyKey() == "" {
+				return next(ctx, cmd)
+			}
+			key := cmd.CommandName() + ":"
+			if us, ok := cmd.(UserScoped); ok {
+				key += us.CommandUserID().String() + ":"
+			}
+			key += ic.CommandIdempotencyKey()
+
+			fingerprint, err := commandFingerprint(cmd)
+			if err != nil {
+				return err
+			}
+			stored, err := store.Reserve(ctx, key, ttl)
+			if err != nil {
+				return fmt.Errorf("failed to reserve idempotency key: %w", err)
+			}
+			if stored != nil {
+				return stored.outcome(fingerprint)
+			}
+
+			if err := next(ctx, cmd); err != nil {
+				// A key that cannot be released stays in use until it
+				// expires, which only delays the client's retry.
+				_ = store.Release(context.WithoutCancel(ctx), key)
+				return err
+			}
+			// The command took effect; a record that cannot be saved leaves
+			// the key in use, so a retry is refused rather than run twice.
+			_ = store.Complete(context.WithoutCancel(ctx), key, IdempotencyRecord{
+				Fingerprint: fingerprint,
+				Done:        true,
+			}, ttl)
+			return nil
+		}
+	}
+}
+
+// commandFingerprint identifies the contents of cmd, so a key reused for a
+// different command is told apart from a retry.
+func commandFingerprint(cmd Command) (string, error) {
+	data, err := json.Marshal(cmd)
+	if err != nil {
+		return "", fmt.Errorf("failed to fingerprint command %s: %w", cmd.CommandName(), err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Metrics counts dispatched and failed commands and their total duration,
+// per command name.
+type Metrics struct {
+	mu       sync.Mutex
+	commands map[string]*commandStats
+}
+
+type commandStats struct {
+	total    uint64
+	failed   uint64
+	duration time.Duration
+}
+
+// NewMetrics creates an empty Metrics.
+func NewMetrics() *Metrics {
+	return &Metrics{commands: make(map[string]*commandStats)}
+}
+
+// Middleware records every command handled through it.
+func (m *Metrics) Middleware() Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context, cmd Command) error {
+			start := time.Now()
+			err := next(ctx, cmd)
+			m.record(cmd.CommandName(), time.Since(start), err)
+			return err
+		}
+	}
+}
+
+func (m *Metrics) record(name string, d time.Duration, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, ok := m.commands[name]
+	if !ok {
+		s = &commandStats{}
+		m.commands[name] = s
+	}
+	s.total++
+	s.duration += d
+	if err != nil {
+		s.failed++
+	}
+}
+
+// WritePrometheus writes the command counters in the Prometheus text format.
+func (m *Metrics) WritePrometheus(w io.Writer) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	names := make([]string, 0, len(m.commands))
+	for name := range m.commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	series := []struct {
+		name, help string
+		value      func(*commandStats) float64
+	}{
+		{"fintech_commands_total", "Total number of dispatched commands.",
+			func(s *commandStats) float64 { return float64(s.total) }},
+		{"fintech_commands_failed_total", "Total number of commands that failed.",
+			func(s *commandStats) float64 { return float64(s.failed) }},
+		{"fintech_commands_duration_seconds_total", "Total time spent handling commands.",
+			func(s *commandStats) float64 { return s.duration.Seconds() }},
+	}
+	for _, ser := range series {
+		fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s counter\n", ser.name, ser.help, ser.name)
+		for _, name := range names {
+			fmt.Fprintf(w, "%s{command=%q} %g\n", ser.name, name, ser.value(m.commands[name]))
+		}
+	}
+}
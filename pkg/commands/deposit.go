@@ -12,4 +12,32 @@ type Deposit struct {
 	MoneySource string
 	PaymentID   string
 	Timestamp   int64
+	// SuccessURL and CancelURL optionally override where checkout redirects
+	// the user. Their hosts must be in the redirect allowlist.
+	SuccessURL string
+	CancelURL  string
+	// Description is an optional note, at most
+	// account.MaxDescriptionLength characters once normalized.
+	Description string
+	// StatementDescriptor optionally overrides the text on the user's card
+	// statement. It must pass validation.ValidateStatementDescriptor.
+	StatementDescriptor string
+	// CaptureMethod is "manual" to only authorize the payment, holding the
+	// funds until the deposit is captured; empty or "automatic" captures
+	// them right away.
+	CaptureMethod string
+	// IdempotencyKey deduplicates retried requests; empty disables it.
+	IdempotencyKey string
 }
+
+// CommandName implements commandbus.Command.
+func (Deposit) CommandName() string { return NameDeposit }
+
+// CommandUserID implements commandbus.UserScoped.
+func (c Deposit) CommandUserID() uuid.UUID { return c.UserID }
+
+// CommandIdempotencyKey implements commandbus.Idempotent.
+func (c Deposit) CommandIdempotencyKey() string { return c.IdempotencyKey }
+
+// Validate implements commandbus.Validatable.
+func (c Deposit) Validate() error { return validateMovement(c.AccountID, c.Amount) }
@@ -1,9 +1,12 @@
 package commands
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 )
 
+// Transfer is a DTO for transfer operations (command pattern).
 type Transfer struct {
 	UserID        uuid.UUID
 	AccountID     uuid.UUID
@@ -11,4 +14,34 @@ type Transfer struct {
 	Currency      string
 	FromAccountID uuid.UUID
 	ToAccountID   uuid.UUID
+	// ExecuteAt schedules the transfer for a future time.
+	// The zero value executes the transfer immediately.
+	ExecuteAt time.Time
+	// RequireApproval debits the source account immediately but only credits
+	// the destination once an admin approves the transfer. It cannot be
+	// combined with ExecuteAt.
+	RequireApproval bool
+	// Description is an optional note recorded on both sides of the
+	// transfer, at most account.MaxDescriptionLength characters once
+	// normalized.
+	Description string
+	// IdempotencyKey deduplicates retried requests; empty disables it.
+	IdempotencyKey string
+}
+
+// CommandName implements commandbus.Command.
+func (Transfer) CommandName() string { return NameTransfer }
+
+// CommandUserID implements commandbus.UserScoped.
+func (c Transfer) CommandUserID() uuid.UUID { return c.UserID }
+
+// CommandIdempotencyKey implements commandbus.Idempotent.
+func (c Transfer) CommandIdempotencyKey() string { return c.IdempotencyKey }
+
+// Validate implements commandbus.Validatable.
+func (c Transfer) Validate() error {
+	if c.ToAccountID == uuid.Nil {
+		return ErrMissingAccountID
+	}
+	return validateMovement(c.AccountID, c.Amount)
 }
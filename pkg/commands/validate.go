@@ -0,0 +1,32 @@
+package commands
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+// Command names, as reported by CommandName.
+const (
+	NameDeposit  = "account.deposit"
+	NameWithdraw = "account.withdraw"
+	NameTransfer = "account.transfer"
+)
+
+var (
+	// ErrMissingAccountID is returned when a command names no account.
+	ErrMissingAccountID = errors.New("account ID is required")
+	// ErrNonPositiveAmount is returned when a command's amount is not positive.
+	ErrNonPositiveAmount = errors.New("amount must be positive")
+)
+
+// validateMovement checks the fields shared by every money movement.
+func validateMovement(accountID uuid.UUID, amount float64) error {
+	if accountID == uuid.Nil {
+		return ErrMissingAccountID
+	}
+	if amount <= 0 {
+		return ErrNonPositiveAmount
+	}
+	return nil
+}
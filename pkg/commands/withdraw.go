@@ -11,12 +11,33 @@ type Withdraw struct {
 	Currency       string
 	MoneySource    string
 	ExternalTarget *ExternalTarget // pointer for optionality
+	// Description is an optional note, at most
+	// account.MaxDescriptionLength characters once normalized.
+	Description string
+	// IdempotencyKey deduplicates retried requests; empty disables it.
+	IdempotencyKey string
 }
 
+// CommandName implements commandbus.Command.
+func (Withdraw) CommandName() string { return NameWithdraw }
+
+// CommandUserID implements commandbus.UserScoped.
+func (c Withdraw) CommandUserID() uuid.UUID { return c.UserID }
+
+// CommandIdempotencyKey implements commandbus.Idempotent.
+func (c Withdraw) CommandIdempotencyKey() string { return c.IdempotencyKey }
+
+// Validate implements commandbus.Validatable.
+func (c Withdraw) Validate() error { return validateMovement(c.AccountID, c.Amount) }
+
 // ExternalTarget represents the destination for an external withdrawal, such
 // as a bank account or wallet.
 type ExternalTarget struct {
-	BankAccountNumber     string
-	RoutingNumber         string
+	BankAccountNumber string
+	RoutingNumber     string
+	// BIC is the SWIFT/BIC code of the bank holding BankAccountNumber.
+	BIC                   string
 	ExternalWalletAddress string
+	// Network is the crypto network of ExternalWalletAddress (e.g. "ethereum").
+	Network string
 }
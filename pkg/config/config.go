@@ -1,16 +1,61 @@
 package config
 
 import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
+
+	"github.com/amirasaad/fintech/pkg/money"
 )
 
 type DB struct {
 	Url string `envconfig:"URL"`
+	// ReplicaUrls lists read replica DSNs. Reads of read-heavy endpoints are
+	// spread across them; everything else uses Url. Empty means no replicas.
+	ReplicaUrls []string `envconfig:"REPLICA_URLS"`
+	// Connection pool tuning, applied to the primary and every replica.
+	// MaxOpenConns <= 0 means unlimited open connections.
+	MaxOpenConns    int           `envconfig:"MAX_OPEN_CONNS" default:"25"`
+	MaxIdleConns    int           `envconfig:"MAX_IDLE_CONNS" default:"25"`
+	ConnMaxLifetime time.Duration `envconfig:"CONN_MAX_LIFETIME" default:"1h"`
+}
+
+// Validate checks that the connection pool settings are consistent.
+func (d *DB) Validate() error {
+	if d.MaxIdleConns < 0 {
+		return fmt.Errorf("DATABASE_MAX_IDLE_CONNS must not be negative, got %d", d.MaxIdleConns)
+	}
+	if d.ConnMaxLifetime < 0 {
+		return fmt.Errorf(
+			"DATABASE_CONN_MAX_LIFETIME must not be negative, got %s", d.ConnMaxLifetime,
+		)
+	}
+	if d.MaxOpenConns > 0 && d.MaxIdleConns > d.MaxOpenConns {
+		return fmt.Errorf(
+			"DATABASE_MAX_IDLE_CONNS (%d) must not exceed DATABASE_MAX_OPEN_CONNS (%d)",
+			d.MaxIdleConns, d.MaxOpenConns,
+		)
+	}
+	return nil
 }
 
 type Jwt struct {
 	Secret string        `envconfig:"SECRET" required:"true"`
 	Expiry time.Duration `envconfig:"EXPIRY" default:"24h"`
+	// Issuer is the iss claim of issued tokens. When set, tokens from any
+	// other issuer are rejected.
+	Issuer string `envconfig:"ISSUER" default:""`
+	// Audience is the aud claim of issued tokens. When set, tokens not
+	// intended for it are rejected.
+	Audience string `envconfig:"AUDIENCE" default:""`
 }
 type Auth struct {
 	Strategy string `envconfig:"STRATEGY" default:"jwt"`
@@ -31,6 +76,129 @@ type RateLimit struct {
 	Window      time.Duration `envconfig:"WINDOW" default:"1m"`
 }
 
+// Receipts configures shareable transaction receipt links.
+type Receipts struct {
+	// Secret keys the tokens of receipt links. Empty derives a key from
+	// AUTH_JWT_SECRET. Changing either invalidates every link issued.
+	Secret string `envconfig:"SECRET"`
+	// TTL is how long a receipt link works.
+	TTL time.Duration `envconfig:"TTL" default:"168h"`
+}
+
+// SigningKey returns the key receipt link tokens are signed with: Secret,
+// or a key derived from jwtSecret so a receipt token is never a valid JWT
+// signature or vice versa.
+func (r *Receipts) SigningKey(jwtSecret string) []byte {
+	if r != nil && r.Secret != "" {
+		return []byte(r.Secret)
+	}
+	mac := hmac.New(sha256.New, []byte(jwtSecret))
+	mac.Write([]byte("fintech receipt links"))
+	return mac.Sum(nil)
+}
+
+// TransactionCache configures caching account transaction lists in Redis
+// (REDIS_URL). Cached lists are invalidated by the events of the flows that
+// change them; TTL bounds how long a list is kept regardless.
+type TransactionCache struct {
+	Enabled bool          `envconfig:"ENABLED" default:"false"`
+	TTL     time.Duration `envconfig:"TTL" default:"1m"`
+}
+
+// AccountLock configures the lock that serializes deposits, withdrawals and
+// transfers per account. Accounts are locked within each instance unless
+// Distributed, which locks them in Redis (REDIS_URL) across instances.
+// Timeout bounds the wait for an account held by another operation; TTL
+// bounds how long a Redis lock outlives an instance that died holding it.
+type AccountLock struct {
+	Distributed bool          `envconfig:"DISTRIBUTED" default:"false"`
+	Timeout     time.Duration `envconfig:"TIMEOUT" default:"5s"`
+	TTL         time.Duration `envconfig:"TTL" default:"30s"`
+}
+
+// Idempotency configures how long the Idempotency-Key of a deposit,
+// withdrawal or transfer is remembered. Keys are kept in Redis (REDIS_URL)
+// when configured, so they apply across instances, and in each instance
+// otherwise.
+type Idempotency struct {
+	TTL time.Duration `envconfig:"TTL" default:"24h"`
+}
+
+// Convert configures the public GET /convert endpoint. Each client may make
+// MaxRequests per Window, on top of RATE_LIMIT: converting an uncached pair
+// spends the exchange rate provider's quota.
+type Convert struct {
+	MaxRequests int           `envconfig:"MAX_REQUESTS" default:"20"`
+	Window      time.Duration `envconfig:"WINDOW" default:"1m"`
+}
+
+// RequestTimeout bounds how long HTTP requests may run.
+type RequestTimeout struct {
+	Default time.Duration `envconfig:"DEFAULT" default:"30s"`
+	// Overrides maps path prefixes to timeouts, e.g.
+	// "/reconciliation:2m,/accounts:5s". The longest matching prefix wins and
+	// a zero timeout disables the limit. Prefixes are matched against the
+	// full request path, including API_PREFIX.
+	Overrides map[string]time.Duration `envconfig:"OVERRIDES"`
+}
+
+// For returns the timeout for requests to path. Zero means no timeout.
+func (r *RequestTimeout) For(path string) time.Duration {
+	if r == nil {
+		return 0
+	}
+	timeout, longest := r.Default, -1
+	for prefix, t := range r.Overrides {
+		if strings.HasPrefix(path, prefix) && len(prefix) > longest {
+			timeout, longest = t, len(prefix)
+		}
+	}
+	return timeout
+}
+
+// DefaultCORSAllowHeaders are the request headers browsers may send when
+// CORS_ALLOW_HEADERS is not set.
+var DefaultCORSAllowHeaders = []string{"Content-Type", "Authorization", "Idempotency-Key"}
+
+// CORS controls which browser origins may call the API. No origins are
+// allowed unless listed, so cross-origin requests are refused by default.
+type CORS struct {
+	// AllowOrigins lists origins such as "https://dashboard.example.com".
+	// "*" allows any origin but cannot be combined with AllowCredentials.
+	AllowOrigins []string `envconfig:"ALLOW_ORIGINS"`
+	AllowMethods []string `envconfig:"ALLOW_METHODS" default:"GET,POST,PUT,PATCH,DELETE"`
+	// AllowHeaders defaults to DefaultCORSAllowHeaders.
+	AllowHeaders []string `envconfig:"ALLOW_HEADERS"`
+	// AllowCredentials lets browsers send cookies and HTTP authentication.
+	AllowCredentials bool `envconfig:"ALLOW_CREDENTIALS" default:"false"`
+	// MaxAge is how long browsers may cache preflight responses.
+	MaxAge time.Duration `envconfig:"MAX_AGE" default:"10m"`
+}
+
+// Validate checks that every origin is a scheme and host, and that a
+// wildcard origin is not combined with credentials.
+func (c *CORS) Validate() error {
+	if c == nil {
+		return nil
+	}
+	for _, origin := range c.AllowOrigins {
+		if origin == "*" {
+			if c.AllowCredentials {
+				return fmt.Errorf(
+					"CORS_ALLOW_ORIGINS must list origins explicitly when CORS_ALLOW_CREDENTIALS is set",
+				)
+			}
+			continue
+		}
+		u, err := url.Parse(origin)
+		if err != nil || u.Scheme == "" || u.Host == "" ||
+			(u.Path != "" && u.Path != "/") || u.RawQuery != "" {
+			return fmt.Errorf("CORS_ALLOW_ORIGINS: invalid origin %q", origin)
+		}
+	}
+	return nil
+}
+
 type EventBus struct {
 	Driver             string `envconfig:"DRIVER" default:""`
 	RedisURL           string `envconfig:"REDIS_URL" default:""`
@@ -44,6 +212,93 @@ type EventBus struct {
 	KafkaTLSCertPem    string `envconfig:"KAFKA_TLS_CERT_PEM" default:""`
 	KafkaTLSKeyPem     string `envconfig:"KAFKA_TLS_KEY_PEM" default:""`
 	KafkaTLSSkipVerify bool   `envconfig:"KAFKA_TLS_SKIP_VERIFY" default:"false"`
+	// CompressionThreshold gzips Redis event payloads of at least this many
+	// bytes. Zero disables compression.
+	CompressionThreshold int `envconfig:"COMPRESSION_THRESHOLD" default:"0"`
+	// DLQAlertThreshold alerts when a Redis DLQ holds more than this many
+	// messages. Zero disables alerting.
+	DLQAlertThreshold int64 `envconfig:"DLQ_ALERT_THRESHOLD" default:"0"`
+	// HandlerTimeout bounds how long each Redis event handler may run before
+	// the event is sent to the DLQ. Zero disables the timeout.
+	HandlerTimeout time.Duration `envconfig:"HANDLER_TIMEOUT" default:"30s"`
+	// RedisNamespace prefixes the Redis stream, group and consumer names so
+	// several environments can share one Redis. Changing it orphans the
+	// streams and DLQs under the old names.
+	RedisNamespace string `envconfig:"REDIS_NAMESPACE" default:""`
+	// UnknownEvents is what the Redis bus does with events of types it does
+	// not know: park them in the unknown events stream until an instance
+	// that knows them replays them, or drop them.
+	UnknownEvents string `envconfig:"UNKNOWN_EVENTS" default:"park"`
+	// ConsumerConcurrency sets the number of Redis consumers of event types,
+	// e.g. "Payment.Completed:4". Types not listed get one. Events of a type
+	// with several consumers are not handled in order.
+	ConsumerConcurrency map[string]int `envconfig:"CONSUMER_CONCURRENCY"`
+	// ConsumerBatchSize sets how many messages each Redis consumer of event
+	// types reads at a time, e.g. "Payment.Completed:50". Types not listed
+	// read 10.
+	ConsumerBatchSize map[string]int64 `envconfig:"CONSUMER_BATCH_SIZE"`
+	// AckPerMessage makes Redis consumers acknowledge each message once it
+	// is handled instead of each batch read with a single XACK. Batches left
+	// unacknowledged by a crash are handled again either way.
+	AckPerMessage bool `envconfig:"ACK_PER_MESSAGE" default:"false"`
+	// IdleConsumerThreshold is how long a Redis consumer must have been idle
+	// before it is removed from its group when consuming starts.
+	IdleConsumerThreshold time.Duration `envconfig:"IDLE_CONSUMER_THRESHOLD" default:"5m"`
+	// CleanupConsumersWithoutPending also removes idle Redis consumers that
+	// have no pending messages, not only those that do.
+	CleanupConsumersWithoutPending bool `envconfig:"CLEANUP_CONSUMERS_WITHOUT_PENDING" default:"false"`
+}
+
+// Validate checks that the unknown events policy is park or drop, that
+// consumer concurrency and batch sizes are positive and that the idle
+// consumer threshold is not negative.
+func (e *EventBus) Validate() error {
+	if e == nil {
+		return nil
+	}
+	switch e.UnknownEvents {
+	case "", "park", "drop":
+	default:
+		return fmt.Errorf(
+			"EVENT_BUS_UNKNOWN_EVENTS: %q must be park or drop",
+			e.UnknownEvents,
+		)
+	}
+	for eventType, n := range e.ConsumerConcurrency {
+		if n <= 0 {
+			return fmt.Errorf(
+				"EVENT_BUS_CONSUMER_CONCURRENCY: %s must be positive, got %d",
+				eventType, n,
+			)
+		}
+	}
+	for eventType, n := range e.ConsumerBatchSize {
+		if n <= 0 {
+			return fmt.Errorf(
+				"EVENT_BUS_CONSUMER_BATCH_SIZE: %s must be positive, got %d",
+				eventType, n,
+			)
+		}
+	}
+	if e.IdleConsumerThreshold < 0 {
+		return fmt.Errorf(
+			"EVENT_BUS_IDLE_CONSUMER_THRESHOLD must not be negative, got %s",
+			e.IdleConsumerThreshold,
+		)
+	}
+	return nil
+}
+
+// Tracing configures OpenTelemetry tracing. Spans are only exported when
+// OTLPEndpoint is set.
+type Tracing struct {
+	// OTLPEndpoint is the URL spans are sent to over OTLP/HTTP, e.g.
+	// "http://localhost:4318". An http URL sends them unencrypted.
+	OTLPEndpoint string `envconfig:"OTLP_ENDPOINT" default:""`
+	ServiceName  string `envconfig:"SERVICE_NAME" default:"fintech"`
+	// SampleRatio is the fraction of new traces that are recorded. Requests
+	// continuing a trace follow the caller's sampling decision.
+	SampleRatio float64 `envconfig:"SAMPLE_RATIO" default:"1"`
 }
 
 //revive:disable
@@ -56,6 +311,167 @@ type Stripe struct {
 	OnboardingReturnURL  string `envconfig:"ONBOARDING_RETURN_URL" default:"http://localhost:3000/onboarding/return"`
 	OnboardingRefreshURL string `envconfig:"ONBOARDING_REFRESH_URL" default:"http://localhost:3000/onboarding/refresh"`
 	SkipTLSVerify        bool   `envconfig:"SKIP_TLS_VERIFY" default:"false"` // Skip TLS verification for development
+	// BaseURL resolves relative success and cancel paths. It defaults to the
+	// server's base URL.
+	BaseURL string `envconfig:"BASE_URL"`
+	// RedirectAllowedHosts lists the hosts deposits may use in per-request
+	// success and cancel URLs. Empty disables per-request URLs.
+	RedirectAllowedHosts []string `envconfig:"REDIRECT_ALLOWED_HOSTS"`
+	// CallTimeout bounds each Stripe API call whose context has no deadline.
+	CallTimeout time.Duration `envconfig:"CALL_TIMEOUT" default:"15s"`
+	// ApplicationFee is withheld from payouts to connected accounts.
+	ApplicationFee *ApplicationFee `envconfig:"APPLICATION_FEE"`
+	// PaymentMethods are the payment method types offered at checkout.
+	PaymentMethods PaymentMethods `envconfig:"PAYMENT_METHODS" default:"card:*"`
+	// StatementDescriptor is shown on the user's card statement for
+	// deposits that do not set their own. Empty uses
+	// DefaultStatementDescriptor.
+	StatementDescriptor string `envconfig:"STATEMENT_DESCRIPTOR"`
+}
+
+// DefaultStatementDescriptor returns the configured statement descriptor,
+// or FINTECH in production and FINTECH TEST elsewhere, so test charges are
+// recognizable as such.
+func (s *Stripe) DefaultStatementDescriptor() string {
+	if s.StatementDescriptor != "" {
+		return s.StatementDescriptor
+	}
+	if s.Env == "production" {
+		return "FINTECH"
+	}
+	return "FINTECH TEST"
+}
+
+// PaymentMethods maps Stripe payment method types to the currencies and
+// countries they are offered for, as "CURRENCIES/COUNTRIES" with lists
+// separated by "|" and "*" for any, e.g.
+// "card:*,sepa_debit:EUR/AT|BE|DE|FR,ideal:EUR/NL". The countries may be
+// omitted, meaning any.
+type PaymentMethods map[string]string
+
+// For returns the payment method types offered for the currency and country,
+// sorted by name. An empty country matches every country.
+func (p PaymentMethods) For(currency, country string) ([]string, error) {
+	var methods []string
+	for method, rule := range p {
+		currencies, countries, err := parsePaymentMethodRule(rule)
+		if err != nil {
+			return nil, fmt.Errorf("payment method %s: %w", method, err)
+		}
+		if matchesAny(currencies, currency) && (country == "" || matchesAny(countries, country)) {
+			methods = append(methods, method)
+		}
+	}
+	sort.Strings(methods)
+	return methods, nil
+}
+
+// Validate checks that every rule lists three-letter currency codes and
+// two-letter country codes, or "*".
+func (p PaymentMethods) Validate() error {
+	for method, rule := range p {
+		if _, _, err := parsePaymentMethodRule(rule); err != nil {
+			return fmt.Errorf("PAYMENT_PROVIDER_STRIPE_PAYMENT_METHODS: %s: %w", method, err)
+		}
+	}
+	return nil
+}
+
+var (
+	currencyCodePattern = regexp.MustCompile(`^[A-Z]{3}$`)
+	countryCodePattern  = regexp.MustCompile(`^[A-Z]{2}$`)
+)
+
+// parsePaymentMethodRule splits a "CURRENCIES/COUNTRIES" rule into its
+// upper-cased lists.
+func parsePaymentMethodRule(rule string) (currencies, countries []string, err error) {
+	currencyPart, countryPart, found := strings.Cut(rule, "/")
+	if !found {
+		countryPart = "*"
+	}
+	if currencies, err = parseCodeList(currencyPart, currencyCodePattern); err != nil {
+		return nil, nil, fmt.Errorf("invalid currencies %q", currencyPart)
+	}
+	if countries, err = parseCodeList(countryPart, countryCodePattern); err != nil {
+		return nil, nil, fmt.Errorf("invalid countries %q", countryPart)
+	}
+	return currencies, countries, nil
+}
+
+func parseCodeList(list string, pattern *regexp.Regexp) ([]string, error) {
+	var codes []string
+	for _, code := range strings.Split(list, "|") {
+		code = strings.ToUpper(strings.TrimSpace(code))
+		if code != "*" && !pattern.MatchString(code) {
+			return nil, fmt.Errorf("invalid code %q", code)
+		}
+		codes = append(codes, code)
+	}
+	return codes, nil
+}
+
+// matchesAny reports whether codes contains code, case insensitively, or "*".
+func matchesAny(codes []string, code string) bool {
+	for _, c := range codes {
+		if c == "*" || strings.EqualFold(c, code) {
+			return true
+		}
+	}
+	return false
+}
+
+// ApplicationFee is the platform fee policy for payouts to connected
+// accounts: Percent of the payout plus Fixed, in major units of the payout
+// currency.
+type ApplicationFee struct {
+	Percent float64 `envconfig:"PERCENT" default:"0"`
+	Fixed   float64 `envconfig:"FIXED" default:"0"`
+	// Overrides maps connected account IDs to "percent+fixed" policies,
+	// e.g. "acct_123:1.5+0.25,acct_456:0+0".
+	Overrides map[string]string `envconfig:"OVERRIDES"`
+}
+
+// For returns the percent and fixed fee for the given connected account,
+// falling back to the global policy when it has no override.
+func (a *ApplicationFee) For(accountID string) (percent, fixed float64, err error) {
+	if a == nil {
+		return 0, 0, nil
+	}
+	override, ok := a.Overrides[accountID]
+	if !ok {
+		return a.Percent, a.Fixed, nil
+	}
+	p, f, found := strings.Cut(override, "+")
+	if !found {
+		return 0, 0, fmt.Errorf("application fee override for %s must be percent+fixed", accountID)
+	}
+	if percent, err = strconv.ParseFloat(strings.TrimSpace(p), 64); err != nil {
+		return 0, 0, fmt.Errorf("invalid application fee percent for %s: %w", accountID, err)
+	}
+	if fixed, err = strconv.ParseFloat(strings.TrimSpace(f), 64); err != nil {
+		return 0, 0, fmt.Errorf("invalid application fee fixed amount for %s: %w", accountID, err)
+	}
+	return percent, fixed, nil
+}
+
+// Validate checks the global policy and every override.
+func (a *ApplicationFee) Validate() error {
+	if a == nil {
+		return nil
+	}
+	if a.Percent < 0 || a.Percent >= 100 || a.Fixed < 0 {
+		return fmt.Errorf("application fee must have 0 <= percent < 100 and fixed >= 0")
+	}
+	for id := range a.Overrides {
+		percent, fixed, err := a.For(id)
+		if err != nil {
+			return err
+		}
+		if percent < 0 || percent >= 100 || fixed < 0 {
+			return fmt.Errorf("application fee override for %s is out of range", id)
+		}
+	}
+	return nil
 }
 
 //revive:enable
@@ -67,6 +483,14 @@ type ExchangeRateApi struct {
 	ApiKey      string        `envconfig:"API_KEY"`
 	ApiUrl      string        `envconfig:"API_URL" default:""`
 	HTTPTimeout time.Duration `envconfig:"HTTP_TIMEOUT" default:"10s"`
+	// Retries on network errors, 5xx and 429, with exponential backoff.
+	MaxRetries      int           `envconfig:"MAX_RETRIES" default:"2"`
+	RetryBackoff    time.Duration `envconfig:"RETRY_BACKOFF" default:"200ms"`
+	RetryMaxBackoff time.Duration `envconfig:"RETRY_MAX_BACKOFF" default:"5s"`
+	// The circuit opens after BreakerThreshold consecutive failed calls and
+	// stays open for BreakerCooldown. Zero threshold disables it.
+	BreakerThreshold int           `envconfig:"BREAKER_THRESHOLD" default:"5"`
+	BreakerCooldown  time.Duration `envconfig:"BREAKER_COOLDOWN" default:"30s"`
 }
 
 type ExchangeRateProviders struct {
@@ -84,8 +508,431 @@ type ExchangeRateCache struct {
 	Url               string        `envconfig:"URL"`
 }
 
+// Exchange configures currency conversion.
+type Exchange struct {
+	// WarmupEnabled prefetches the rates of WarmupPairs into the rate cache
+	// on startup, so the first conversions neither wait for the provider nor
+	// fail if it is briefly down. The warmup runs in the background.
+	WarmupEnabled bool `envconfig:"WARMUP_ENABLED" default:"false"`
+	// WarmupPairs are the currency pairs to prefetch as FROM/TO codes, e.g.
+	// USD/EUR. Their inverse rates are cached too.
+	WarmupPairs []string `envconfig:"WARMUP_PAIRS" default:""`
+	// WarmupTimeout bounds the whole warmup. Pairs not fetched by then are
+	// fetched on first use as usual.
+	WarmupTimeout time.Duration `envconfig:"WARMUP_TIMEOUT" default:"10s"`
+	// QuotaLimit is the number of calls the rate provider allows per month.
+	// Zero disables quota tracking.
+	QuotaLimit int `envconfig:"QUOTA_LIMIT" default:"0"`
+	// QuotaSoftLimit is the fraction of QuotaLimit after which conversions
+	// are served from cached rates only, failing for uncached pairs, until
+	// the quota resets.
+	QuotaSoftLimit float64 `envconfig:"QUOTA_SOFT_LIMIT" default:"0.9"`
+	// QuotaResetDay is the day of the month, in UTC, on which the provider
+	// resets the quota.
+	QuotaResetDay int `envconfig:"QUOTA_RESET_DAY" default:"1"`
+	// RoundingMode is how converted amounts are rounded to the smallest unit
+	// of the target currency: half_even (banker's rounding), half_up or
+	// floor.
+	RoundingMode string `envconfig:"ROUNDING_MODE" default:"half_even"`
+	// AllowedPairs are the currency pairs, as FROM/TO codes, that may be
+	// converted. Pairs are directional. Together with AllowedCurrencies they
+	// form the conversion allowlist; when both are empty every pair the
+	// provider supports is allowed.
+	AllowedPairs []string `envconfig:"ALLOWED_PAIRS" default:""`
+	// AllowedCurrencies are currencies that may be converted to and from
+	// each other, allowing every pair between them.
+	AllowedCurrencies []string `envconfig:"ALLOWED_CURRENCIES" default:""`
+}
+
+// Validate checks that each warmup and allowed pair is two currency codes
+// separated by a slash, that each allowed currency is a currency code, that
+// the rounding mode is known and, when a quota is set, that its soft limit
+// and reset day are in range.
+func (e *Exchange) Validate() error {
+	if e == nil {
+		return nil
+	}
+	for _, pair := range e.WarmupPairs {
+		if !isCurrencyPair(pair) {
+			return fmt.Errorf(
+				"EXCHANGE_WARMUP_PAIRS: %q must be two currency codes such as USD/EUR", pair,
+			)
+		}
+	}
+	for _, pair := range e.AllowedPairs {
+		if !isCurrencyPair(pair) {
+			return fmt.Errorf(
+				"EXCHANGE_ALLOWED_PAIRS: %q must be two currency codes such as USD/EUR", pair,
+			)
+		}
+	}
+	for _, code := range e.AllowedCurrencies {
+		if !currencyCodePattern.MatchString(strings.ToUpper(strings.TrimSpace(code))) {
+			return fmt.Errorf("EXCHANGE_ALLOWED_CURRENCIES: %q must be a currency code", code)
+		}
+	}
+	if e.RoundingMode != "" {
+		if _, err := money.ParseRoundingMode(e.RoundingMode); err != nil {
+			return fmt.Errorf("EXCHANGE_ROUNDING_MODE: %w", err)
+		}
+	}
+	if e.QuotaLimit < 0 {
+		return fmt.Errorf("EXCHANGE_QUOTA_LIMIT must not be negative, got %d", e.QuotaLimit)
+	}
+	if e.QuotaLimit > 0 {
+		if e.QuotaSoftLimit <= 0 || e.QuotaSoftLimit > 1 {
+			return fmt.Errorf(
+				"EXCHANGE_QUOTA_SOFT_LIMIT must be in (0, 1], got %g", e.QuotaSoftLimit,
+			)
+		}
+		if e.QuotaResetDay < 1 || e.QuotaResetDay > 28 {
+			return fmt.Errorf(
+				"EXCHANGE_QUOTA_RESET_DAY must be between 1 and 28, got %d", e.QuotaResetDay,
+			)
+		}
+	}
+	return nil
+}
+
+// isCurrencyPair reports whether pair is two currency codes separated by a
+// slash, ignoring case and surrounding space.
+func isCurrencyPair(pair string) bool {
+	from, to, ok := strings.Cut(strings.ToUpper(strings.TrimSpace(pair)), "/")
+	return ok && currencyCodePattern.MatchString(from) && currencyCodePattern.MatchString(to)
+}
+
 type Fee struct {
 	ServiceFeePercentage float64 `envconfig:"SERVICE_FEE_PERCENTAGE" default:"0.01"`
+	// ProviderFeePolicy decides who bears the provider fee on deposits to
+	// accounts without a policy of their own: pass_through deducts it from
+	// the credited amount, absorb has the platform bear it.
+	ProviderFeePolicy string `envconfig:"PROVIDER_FEE_POLICY" default:"pass_through"`
+}
+
+// Validate checks that the provider fee policy is absorb or pass_through.
+func (f *Fee) Validate() error {
+	if f == nil {
+		return nil
+	}
+	switch f.ProviderFeePolicy {
+	case "", "pass_through", "absorb":
+		return nil
+	default:
+		return fmt.Errorf(
+			"FEE_PROVIDER_FEE_POLICY: %q must be absorb or pass_through",
+			f.ProviderFeePolicy,
+		)
+	}
+}
+
+// Account configures new accounts.
+type Account struct {
+	// ReferencePrefix starts the human-friendly reference of new accounts,
+	// such as FT in FT-7K3M-9QXD-2HAB. Up to eight letters and digits; empty
+	// for none.
+	ReferencePrefix string `envconfig:"REFERENCE_PREFIX" default:"FT"`
+
+	// Withdrawals allowed per calendar month from accounts of each type,
+	// counting outgoing transfers; zero for no limit.
+	CheckingMonthlyWithdrawals int `envconfig:"CHECKING_MONTHLY_WITHDRAWALS" default:"0"`
+	SavingsMonthlyWithdrawals  int `envconfig:"SAVINGS_MONTHLY_WITHDRAWALS" default:"6"`
+	WalletMonthlyWithdrawals   int `envconfig:"WALLET_MONTHLY_WITHDRAWALS" default:"0"`
+}
+
+// MonthlyWithdrawals returns the withdrawals allowed per month from accounts
+// of the given type, zero for no limit. Unknown types get the checking limit.
+func (a *Account) MonthlyWithdrawals(accountType string) int {
+	switch accountType {
+	case "savings":
+		return a.SavingsMonthlyWithdrawals
+	case "wallet":
+		return a.WalletMonthlyWithdrawals
+	default:
+		return a.CheckingMonthlyWithdrawals
+	}
+}
+
+// Validate checks that ReferencePrefix is at most eight letters and digits
+// and that no withdrawal count limit is negative.
+func (a *Account) Validate() error {
+	if a == nil {
+		return nil
+	}
+	if !referencePrefixPattern.MatchString(a.ReferencePrefix) {
+		return fmt.Errorf(
+			"ACCOUNT_REFERENCE_PREFIX: %q must be at most eight letters and digits",
+			a.ReferencePrefix,
+		)
+	}
+	for name, limit := range map[string]int{
+		"ACCOUNT_CHECKING_MONTHLY_WITHDRAWALS": a.CheckingMonthlyWithdrawals,
+		"ACCOUNT_SAVINGS_MONTHLY_WITHDRAWALS":  a.SavingsMonthlyWithdrawals,
+		"ACCOUNT_WALLET_MONTHLY_WITHDRAWALS":   a.WalletMonthlyWithdrawals,
+	} {
+		if limit < 0 {
+			return fmt.Errorf("%s: %d must not be negative", name, limit)
+		}
+	}
+	return nil
+}
+
+var referencePrefixPattern = regexp.MustCompile(`^[A-Za-z0-9]{0,8}$`)
+
+// Payout providers that pay withdrawals out.
+const (
+	// PayoutProviderStripe pays withdrawals out through Stripe Connect.
+	PayoutProviderStripe = "stripe"
+	// PayoutProviderManual records withdrawals for operators to pay out,
+	// e.g. through a pain.001 export, where Stripe Connect is unavailable.
+	PayoutProviderManual = "manual"
+)
+
+// Withdraw configures validation of external withdrawal targets and the
+// provider that pays withdrawals out.
+type Withdraw struct {
+	RequireRoutingNumber bool     `envconfig:"REQUIRE_ROUTING_NUMBER" default:"false"`
+	AllowIBAN            bool     `envconfig:"ALLOW_IBAN" default:"true"`
+	CryptoNetworks       []string `envconfig:"CRYPTO_NETWORKS" default:""`
+	PayoutProvider       string   `envconfig:"PAYOUT_PROVIDER" default:"stripe"`
+	// ApprovalThreshold is the amount, in ApprovalCurrency, above which a
+	// withdrawal is held until RequiredApprovals approvers other than the
+	// user approve it. Zero requires no approval.
+	ApprovalThreshold float64 `envconfig:"APPROVAL_THRESHOLD" default:"0"`
+	ApprovalCurrency  string  `envconfig:"APPROVAL_CURRENCY" default:"USD"`
+	RequiredApprovals int     `envconfig:"REQUIRED_APPROVALS" default:"1"`
+}
+
+// Validate checks that PayoutProvider is stripe or manual, and that an
+// approval threshold comes with a currency and at least one approval.
+func (w *Withdraw) Validate() error {
+	if w == nil {
+		return nil
+	}
+	switch w.PayoutProvider {
+	case PayoutProviderStripe, PayoutProviderManual:
+	default:
+		return fmt.Errorf(
+			"WITHDRAW_PAYOUT_PROVIDER: %q must be %s or %s",
+			w.PayoutProvider, PayoutProviderStripe, PayoutProviderManual,
+		)
+	}
+	if w.ApprovalThreshold < 0 {
+		return fmt.Errorf(
+			"WITHDRAW_APPROVAL_THRESHOLD: %v must not be negative", w.ApprovalThreshold,
+		)
+	}
+	if w.ApprovalThreshold > 0 {
+		if !currencyCodePattern.MatchString(w.ApprovalCurrency) {
+			return fmt.Errorf(
+				"WITHDRAW_APPROVAL_CURRENCY: %q must be a three-letter currency code",
+				w.ApprovalCurrency,
+			)
+		}
+		if w.RequiredApprovals < 1 {
+			return fmt.Errorf(
+				"WITHDRAW_REQUIRED_APPROVALS: %d must be at least 1", w.RequiredApprovals,
+			)
+		}
+	}
+	return nil
+}
+
+// PayoutExport identifies the platform's own bank account, the debtor of
+// payouts exported as ISO 20022 pain.001 credit transfers.
+type PayoutExport struct {
+	DebtorName string `envconfig:"DEBTOR_NAME"`
+	DebtorIBAN string `envconfig:"DEBTOR_IBAN"`
+	DebtorBIC  string `envconfig:"DEBTOR_BIC"`
+}
+
+// TransferScheduler configures execution of future-dated transfers.
+type TransferScheduler struct {
+	Enabled   bool          `envconfig:"ENABLED" default:"true"`
+	Interval  time.Duration `envconfig:"INTERVAL" default:"30s"`
+	BatchSize int           `envconfig:"BATCH_SIZE" default:"50"`
+}
+
+// Interest configures daily interest accrual on savings accounts.
+type Interest struct {
+	Enabled bool `envconfig:"ENABLED" default:"true"`
+	// SavingsAPY is the annual percentage yield savings accounts earn, e.g.
+	// 0.025 for 2.5%. Zero accrues no interest.
+	SavingsAPY float64       `envconfig:"SAVINGS_APY" default:"0"`
+	Interval   time.Duration `envconfig:"INTERVAL" default:"1h"`
+	BatchSize  int           `envconfig:"BATCH_SIZE" default:"100"`
+}
+
+// Validate checks that SavingsAPY is not negative.
+func (i *Interest) Validate() error {
+	if i == nil {
+		return nil
+	}
+	if i.SavingsAPY < 0 {
+		return fmt.Errorf("INTEREST_SAVINGS_APY: %v must not be negative", i.SavingsAPY)
+	}
+	return nil
+}
+
+// Anomaly configures flagging accounts with abnormal activity for fraud
+// review. Each instance tracks the deposits, withdrawals and transfers it
+// handles over a rolling Window per account and flags an account that makes
+// more than MaxTransactions within it, or a transaction over SpikeFactor times
+// the mean of its last BaselineSize amounts once it has MinBaseline of them.
+// An account is flagged at most once per Cooldown, and frozen when
+// AutoFreeze is set.
+type Anomaly struct {
+	Enabled         bool          `envconfig:"ENABLED" default:"false"`
+	Window          time.Duration `envconfig:"WINDOW" default:"1h"`
+	MaxTransactions int           `envconfig:"MAX_TRANSACTIONS" default:"20"`
+	SpikeFactor     float64       `envconfig:"SPIKE_FACTOR" default:"10"`
+	BaselineSize    int           `envconfig:"BASELINE_SIZE" default:"20"`
+	MinBaseline     int           `envconfig:"MIN_BASELINE" default:"5"`
+	Cooldown        time.Duration `envconfig:"COOLDOWN" default:"1h"`
+	AutoFreeze      bool          `envconfig:"AUTO_FREEZE" default:"false"`
+	// MaxAccounts bounds the accounts tracked; the least recently active
+	// are forgotten beyond it.
+	MaxAccounts int `envconfig:"MAX_ACCOUNTS" default:"100000"`
+}
+
+// Validate checks that enabled anomaly detection has a positive window and
+// thresholds.
+func (a *Anomaly) Validate() error {
+	if a == nil || !a.Enabled {
+		return nil
+	}
+	switch {
+	case a.Window <= 0:
+		return fmt.Errorf("ANOMALY_WINDOW must be positive, got %s", a.Window)
+	case a.MaxTransactions <= 0:
+		return fmt.Errorf("ANOMALY_MAX_TRANSACTIONS must be positive, got %d", a.MaxTransactions)
+	case a.SpikeFactor <= 1:
+		return fmt.Errorf("ANOMALY_SPIKE_FACTOR must be greater than 1, got %v", a.SpikeFactor)
+	case a.BaselineSize <= 0:
+		return fmt.Errorf("ANOMALY_BASELINE_SIZE must be positive, got %d", a.BaselineSize)
+	case a.MinBaseline <= 0 || a.MinBaseline > a.BaselineSize:
+		return fmt.Errorf(
+			"ANOMALY_MIN_BASELINE must be between 1 and ANOMALY_BASELINE_SIZE, got %d",
+			a.MinBaseline,
+		)
+	case a.Cooldown < 0:
+		return fmt.Errorf("ANOMALY_COOLDOWN must not be negative, got %s", a.Cooldown)
+	case a.MaxAccounts <= 0:
+		return fmt.Errorf("ANOMALY_MAX_ACCOUNTS must be positive, got %d", a.MaxAccounts)
+	}
+	return nil
+}
+
+// Analytics sink kinds.
+const (
+	// AnalyticsSinkHTTP posts batches of events as JSON to a URL.
+	AnalyticsSinkHTTP = "http"
+	// AnalyticsSinkFile appends events as JSON lines to a file, e.g. one
+	// shipped to object storage by a log forwarder.
+	AnalyticsSinkFile = "file"
+)
+
+// Analytics configures forwarding of selected domain events, stripped of
+// personal data, to a product analytics sink. Forwarding is best-effort and
+// never affects the flows that emit the events.
+type Analytics struct {
+	Enabled bool `envconfig:"ENABLED" default:"false"`
+	// EventTypes lists the event types forwarded, e.g.
+	// Deposit.Requested,Payment.Completed. Other events are ignored.
+	EventTypes []string `envconfig:"EVENT_TYPES" default:""`
+	// Sink is http or file.
+	Sink     string `envconfig:"SINK" default:"http"`
+	URL      string `envconfig:"URL" default:""`
+	Token    string `envconfig:"TOKEN" default:""`
+	FilePath string `envconfig:"FILE_PATH" default:""`
+	// PseudonymKey keys the HMAC that replaces user IDs, so analytics can
+	// count users without learning who they are. Rotating it breaks the link
+	// with earlier events.
+	PseudonymKey string `envconfig:"PSEUDONYM_KEY" default:""`
+	// Events are buffered up to QueueSize, then dropped, and sent in batches
+	// of BatchSize at least every FlushInterval.
+	QueueSize     int           `envconfig:"QUEUE_SIZE" default:"10000"`
+	BatchSize     int           `envconfig:"BATCH_SIZE" default:"100"`
+	FlushInterval time.Duration `envconfig:"FLUSH_INTERVAL" default:"5s"`
+	// A batch is retried MaxAttempts times in all, waiting RetryBackoff
+	// before the first retry and doubling it each time, before it is
+	// appended to the dead letter file at DLQPath.
+	MaxAttempts  int           `envconfig:"MAX_ATTEMPTS" default:"5"`
+	RetryBackoff time.Duration `envconfig:"RETRY_BACKOFF" default:"1s"`
+	DLQPath      string        `envconfig:"DLQ_PATH" default:"analytics-dlq.jsonl"`
+}
+
+// Validate checks that enabled analytics names at least one event type and
+// a sink it can write to.
+func (a *Analytics) Validate() error {
+	if a == nil || !a.Enabled {
+		return nil
+	}
+	if len(a.EventTypes) == 0 {
+		return errors.New("ANALYTICS_EVENT_TYPES: must list at least one event type")
+	}
+	switch a.Sink {
+	case AnalyticsSinkHTTP:
+		if u, err := url.Parse(a.URL); err != nil || u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("ANALYTICS_URL: %q must be an absolute URL", a.URL)
+		}
+	case AnalyticsSinkFile:
+		if a.FilePath == "" {
+			return errors.New("ANALYTICS_FILE_PATH: must be set for the file sink")
+		}
+	default:
+		return fmt.Errorf(
+			"ANALYTICS_SINK: %q must be %s or %s",
+			a.Sink, AnalyticsSinkHTTP, AnalyticsSinkFile,
+		)
+	}
+	if a.PseudonymKey == "" {
+		return errors.New("ANALYTICS_PSEUDONYM_KEY: must be set to pseudonymize user IDs")
+	}
+	return nil
+}
+
+// KycLimits are the limits for one KYC tier, in Kyc.Currency. Zero means unlimited.
+type KycLimits struct {
+	PerTransaction float64
+	Daily          float64
+	Monthly        float64
+}
+
+// Maintenance configures maintenance mode, in which deposits, withdrawals and
+// transfers are rejected. Admins can also switch it on and off at runtime.
+type Maintenance struct {
+	// Enabled forces maintenance mode on; admins cannot switch it off.
+	Enabled bool `envconfig:"ENABLED" default:"false"`
+}
+
+// Kyc maps KYC tiers to deposit and withdrawal limits. Deposits and
+// withdrawals are limited separately, each against the same tier limits.
+type Kyc struct {
+	Enabled  bool   `envconfig:"ENABLED" default:"true"`
+	Currency string `envconfig:"CURRENCY" default:"USD"`
+
+	UnverifiedPerTransaction float64 `envconfig:"UNVERIFIED_PER_TRANSACTION" default:"1000"`
+	UnverifiedDaily          float64 `envconfig:"UNVERIFIED_DAILY" default:"2000"`
+	UnverifiedMonthly        float64 `envconfig:"UNVERIFIED_MONTHLY" default:"5000"`
+	BasicPerTransaction      float64 `envconfig:"BASIC_PER_TRANSACTION" default:"10000"`
+	BasicDaily               float64 `envconfig:"BASIC_DAILY" default:"20000"`
+	BasicMonthly             float64 `envconfig:"BASIC_MONTHLY" default:"50000"`
+	VerifiedPerTransaction   float64 `envconfig:"VERIFIED_PER_TRANSACTION" default:"0"`
+	VerifiedDaily            float64 `envconfig:"VERIFIED_DAILY" default:"0"`
+	VerifiedMonthly          float64 `envconfig:"VERIFIED_MONTHLY" default:"0"`
+}
+
+// Limits returns the limits for the given tier. Unknown tiers get the
+// unverified limits.
+func (k *Kyc) Limits(tier string) KycLimits {
+	switch tier {
+	case "verified":
+		return KycLimits{k.VerifiedPerTransaction, k.VerifiedDaily, k.VerifiedMonthly}
+	case "basic":
+		return KycLimits{k.BasicPerTransaction, k.BasicDaily, k.BasicMonthly}
+	default:
+		return KycLimits{k.UnverifiedPerTransaction, k.UnverifiedDaily, k.UnverifiedMonthly}
+	}
 }
 
 type Log struct {
@@ -99,19 +946,83 @@ type Server struct {
 	Scheme string `envconfig:"SCHEME" default:"http"`
 	Host   string `envconfig:"HOST" default:"localhost"`
 	Port   int    `envconfig:"PORT" default:"3000"`
+	// TrustedProxies are the addresses or CIDR ranges of the load balancers
+	// and proxies in front of the server. The client address of a request
+	// is read from ProxyHeader only when it comes from one of them; other
+	// requests are identified by their own address, so clients cannot pick
+	// theirs by setting the header.
+	TrustedProxies []string `envconfig:"TRUSTED_PROXIES"`
+	ProxyHeader    string   `envconfig:"PROXY_HEADER" default:"X-Forwarded-For"`
+}
+
+// BaseURL returns the server's scheme, host and port as an absolute URL.
+// The port is omitted when it is zero.
+func (s *Server) BaseURL() string {
+	u := url.URL{Scheme: s.Scheme, Host: s.Host}
+	if s.Port != 0 {
+		u.Host = net.JoinHostPort(s.Host, strconv.Itoa(s.Port))
+	}
+	return u.String()
+}
+
+// API controls where the HTTP routes are mounted.
+type API struct {
+	// Prefix, e.g. "/api/v1", is prepended to every route so the API can sit
+	// behind a shared gateway. Empty mounts the routes at the root.
+	Prefix string `envconfig:"PREFIX"`
+	// OpsAtRoot keeps the health check, metrics, Swagger UI and debug routes
+	// at the root when a prefix is set, where probes and scrapers expect them.
+	OpsAtRoot bool `envconfig:"OPS_AT_ROOT" default:"true"`
+}
+
+var apiPrefixPattern = regexp.MustCompile(`^(/[A-Za-z0-9._~-]+)*$`)
+
+// Validate checks that Prefix is empty or a path of one or more segments,
+// starting with a slash and without a trailing one.
+func (a *API) Validate() error {
+	if a == nil {
+		return nil
+	}
+	if !apiPrefixPattern.MatchString(a.Prefix) {
+		return fmt.Errorf(
+			"API_PREFIX: %q must be empty or a path such as /api/v1, without a trailing slash",
+			a.Prefix,
+		)
+	}
+	return nil
 }
 
 type App struct {
 	Env                      string                 `envconfig:"APP_ENV" default:"development"`
 	Server                   *Server                `envconfig:"SERVER"`
+	API                      *API                   `envconfig:"API"`
 	Log                      *Log                   `envconfig:"LOG"`
 	DB                       *DB                    `envconfig:"DATABASE"`
 	Auth                     *Auth                  `envconfig:"AUTH"`
 	ExchangeRateCache        *ExchangeRateCache     `envconfig:"EXCHANGE_RATE_CACHE"`
 	ExchangeRateAPIProviders *ExchangeRateProviders `envconfig:"EXCHANGE_RATE_PROVIDER"`
+	Exchange                 *Exchange              `envconfig:"EXCHANGE"`
 	Redis                    *Redis                 `envconfig:"REDIS"`
 	EventBus                 *EventBus              `envconfig:"EVENT_BUS"`
 	RateLimit                *RateLimit             `envconfig:"RATE_LIMIT"`
+	Convert                  *Convert               `envconfig:"CONVERT"`
+	Receipts                 *Receipts              `envconfig:"RECEIPT"`
+	TransactionCache         *TransactionCache      `envconfig:"TRANSACTION_CACHE"`
+	AccountLock              *AccountLock           `envconfig:"ACCOUNT_LOCK"`
+	Idempotency              *Idempotency           `envconfig:"IDEMPOTENCY"`
+	RequestTimeout           *RequestTimeout        `envconfig:"REQUEST_TIMEOUT"`
+	CORS                     *CORS                  `envconfig:"CORS"`
 	PaymentProviders         *PaymentProviders      `envconfig:"PAYMENT_PROVIDER"`
 	Fee                      *Fee                   `envconfig:"FEE"`
+	Account                  *Account               `envconfig:"ACCOUNT"`
+	Withdraw                 *Withdraw              `envconfig:"WITHDRAW"`
+	PayoutExport             *PayoutExport          `envconfig:"PAYOUT_EXPORT"`
+	TransferScheduler        *TransferScheduler     `envconfig:"TRANSFER_SCHEDULER"`
+	Interest                 *Interest              `envconfig:"INTEREST"`
+	Kyc                      *Kyc                   `envconfig:"KYC"`
+	Tracing                  *Tracing               `envconfig:"TRACING"`
+	Analytics                *Analytics             `envconfig:"ANALYTICS"`
+	Anomaly                  *Anomaly               `envconfig:"ANOMALY"`
+	Maintenance              *Maintenance           `envconfig:"MAINTENANCE"`
+	Secrets                  *Secrets               `envconfig:"SECRETS"`
 }
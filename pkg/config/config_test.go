@@ -0,0 +1,320 @@
+package config_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/amirasaad/fintech/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDBValidate(t *testing.T) {
+	require.NoError(t, (&config.DB{
+		MaxOpenConns:    25,
+		MaxIdleConns:    25,
+		ConnMaxLifetime: time.Hour,
+	}).Validate())
+	require.NoError(t, (&config.DB{MaxOpenConns: 0, MaxIdleConns: 50}).Validate())
+	require.Error(t, (&config.DB{MaxOpenConns: 10, MaxIdleConns: 11}).Validate())
+	require.Error(t, (&config.DB{MaxOpenConns: 10, MaxIdleConns: -1}).Validate())
+	require.Error(t, (&config.DB{MaxOpenConns: 10, ConnMaxLifetime: -time.Second}).Validate())
+}
+
+func TestServerBaseURL(t *testing.T) {
+	require.Equal(t, "http://localhost:3000",
+		(&config.Server{Scheme: "http", Host: "localhost", Port: 3000}).BaseURL())
+	require.Equal(t, "https://example.com",
+		(&config.Server{Scheme: "https", Host: "example.com"}).BaseURL())
+}
+
+func TestApplicationFee(t *testing.T) {
+	fee := &config.ApplicationFee{
+		Percent:   2.5,
+		Fixed:     0.3,
+		Overrides: map[string]string{"acct_vip": "1+0", "acct_bad": "1"},
+	}
+	percent, fixed, err := fee.For("acct_other")
+	require.NoError(t, err)
+	require.InDelta(t, 2.5, percent, 1e-9)
+	require.InDelta(t, 0.3, fixed, 1e-9)
+
+	percent, fixed, err = fee.For("acct_vip")
+	require.NoError(t, err)
+	require.InDelta(t, 1.0, percent, 1e-9)
+	require.Zero(t, fixed)
+
+	_, _, err = fee.For("acct_bad")
+	require.Error(t, err)
+	require.Error(t, fee.Validate())
+
+	delete(fee.Overrides, "acct_bad")
+	require.NoError(t, fee.Validate())
+	require.Error(t, (&config.ApplicationFee{Percent: 100}).Validate())
+
+	var unset *config.ApplicationFee
+	percent, fixed, err = unset.For("acct_vip")
+	require.NoError(t, err)
+	require.Zero(t, percent)
+	require.Zero(t, fixed)
+}
+
+func TestRequestTimeout(t *testing.T) {
+	rt := &config.RequestTimeout{
+		Default: 30 * time.Second,
+		Overrides: map[string]time.Duration{
+			"/account":        10 * time.Second,
+			"/accounts/slow":  0,
+			"/reconciliation": 2 * time.Minute,
+		},
+	}
+	require.Equal(t, 30*time.Second, rt.For("/currency"))
+	require.Equal(t, 10*time.Second, rt.For("/account/123/balance"))
+	require.Equal(t, 10*time.Second, rt.For("/accounts"))
+	require.Zero(t, rt.For("/accounts/slow/report"))
+	require.Equal(t, 2*time.Minute, rt.For("/reconciliation/payouts"))
+
+	var unset *config.RequestTimeout
+	require.Zero(t, unset.For("/account"))
+}
+
+func TestCORSValidate(t *testing.T) {
+	require.NoError(t, (&config.CORS{
+		AllowOrigins:     []string{"https://dashboard.example.com", "http://localhost:5173"},
+		AllowCredentials: true,
+	}).Validate())
+	require.NoError(t, (&config.CORS{AllowOrigins: []string{"*"}}).Validate())
+	require.Error(t, (&config.CORS{
+		AllowOrigins:     []string{"*"},
+		AllowCredentials: true,
+	}).Validate())
+	require.Error(t, (&config.CORS{AllowOrigins: []string{"dashboard.example.com"}}).Validate())
+	require.Error(t, (&config.CORS{AllowOrigins: []string{"https://example.com/app"}}).Validate())
+
+	var unset *config.CORS
+	require.NoError(t, unset.Validate())
+}
+
+func TestLoadDefaultsCORSAllowHeaders(t *testing.T) {
+	t.Setenv("AUTH_JWT_SECRET", "secret")
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	require.Equal(t, config.DefaultCORSAllowHeaders, cfg.CORS.AllowHeaders)
+
+	t.Setenv("CORS_ALLOW_HEADERS", "Content-Type")
+	cfg, err = config.Load()
+	require.NoError(t, err)
+	require.Equal(t, []string{"Content-Type"}, cfg.CORS.AllowHeaders)
+}
+
+func TestAPIValidate(t *testing.T) {
+	for _, prefix := range []string{"", "/api", "/api/v1", "/fintech-api/v1.2"} {
+		require.NoError(t, (&config.API{Prefix: prefix}).Validate(), prefix)
+	}
+	for _, prefix := range []string{"/", "api/v1", "/api/v1/", "/api//v1", "/api v1"} {
+		require.Error(t, (&config.API{Prefix: prefix}).Validate(), prefix)
+	}
+
+	var unset *config.API
+	require.NoError(t, unset.Validate())
+}
+
+func TestAccountValidate(t *testing.T) {
+	for _, prefix := range []string{"", "FT", "acme2024"} {
+		require.NoError(t, (&config.Account{ReferencePrefix: prefix}).Validate(), prefix)
+	}
+	for _, prefix := range []string{"F-T", "TOOLONGPREFIX", "F T"} {
+		require.Error(t, (&config.Account{ReferencePrefix: prefix}).Validate(), prefix)
+	}
+	require.Error(t, (&config.Account{SavingsMonthlyWithdrawals: -1}).Validate())
+
+	var unset *config.Account
+	require.NoError(t, unset.Validate())
+}
+
+func TestInterestValidate(t *testing.T) {
+	require.NoError(t, (&config.Interest{SavingsAPY: 0.025}).Validate())
+	require.Error(t, (&config.Interest{SavingsAPY: -0.01}).Validate())
+
+	var unset *config.Interest
+	require.NoError(t, unset.Validate())
+}
+
+func TestAnomalyValidate(t *testing.T) {
+	valid := config.Anomaly{
+		Enabled:         true,
+		Window:          time.Hour,
+		MaxTransactions: 20,
+		SpikeFactor:     10,
+		BaselineSize:    20,
+		MinBaseline:     5,
+		Cooldown:        time.Hour,
+		MaxAccounts:     1000,
+	}
+	require.NoError(t, valid.Validate())
+	require.NoError(t, (&config.Anomaly{}).Validate(), "disabled")
+
+	for _, mutate := range []func(*config.Anomaly){
+		func(a *config.Anomaly) { a.Window = 0 },
+		func(a *config.Anomaly) { a.MaxTransactions = 0 },
+		func(a *config.Anomaly) { a.SpikeFactor = 1 },
+		func(a *config.Anomaly) { a.MinBaseline = 21 },
+		func(a *config.Anomaly) { a.Cooldown = -time.Second },
+		func(a *config.Anomaly) { a.MaxAccounts = 0 },
+	} {
+		invalid := valid
+		mutate(&invalid)
+		require.Error(t, invalid.Validate())
+	}
+}
+
+func TestFeeValidate(t *testing.T) {
+	for _, policy := range []string{"", "pass_through", "absorb"} {
+		require.NoError(t, (&config.Fee{ProviderFeePolicy: policy}).Validate(), policy)
+	}
+	require.Error(t, (&config.Fee{ProviderFeePolicy: "platform"}).Validate())
+	var unset *config.Fee
+	require.NoError(t, unset.Validate())
+}
+
+func TestEventBusValidate(t *testing.T) {
+	for _, policy := range []string{"", "park", "drop"} {
+		require.NoError(t, (&config.EventBus{UnknownEvents: policy}).Validate(), policy)
+	}
+	require.Error(t, (&config.EventBus{UnknownEvents: "ignore"}).Validate())
+	require.NoError(t, (&config.EventBus{
+		ConsumerConcurrency: map[string]int{"Payment.Completed": 4},
+		ConsumerBatchSize:   map[string]int64{"Payment.Completed": 50},
+	}).Validate())
+	require.Error(t, (&config.EventBus{
+		ConsumerConcurrency: map[string]int{"Payment.Completed": 0},
+	}).Validate())
+	require.Error(t, (&config.EventBus{
+		ConsumerBatchSize: map[string]int64{"Payment.Completed": -1},
+	}).Validate())
+	var unset *config.EventBus
+	require.NoError(t, unset.Validate())
+}
+
+func TestAnalyticsValidate(t *testing.T) {
+	valid := config.Analytics{
+		Enabled:      true,
+		EventTypes:   []string{"Payment.Completed"},
+		Sink:         config.AnalyticsSinkHTTP,
+		URL:          "https://analytics.example.com/events",
+		PseudonymKey: "secret",
+	}
+	require.NoError(t, valid.Validate())
+	file := valid
+	file.Sink, file.URL, file.FilePath = config.AnalyticsSinkFile, "", "/var/log/analytics.jsonl"
+	require.NoError(t, file.Validate())
+
+	noTypes := valid
+	noTypes.EventTypes = nil
+	require.Error(t, noTypes.Validate())
+	badURL := valid
+	badURL.URL = "analytics.example.com"
+	require.Error(t, badURL.Validate())
+	noPath := file
+	noPath.FilePath = ""
+	require.Error(t, noPath.Validate())
+	badSink := valid
+	badSink.Sink = "s3"
+	require.Error(t, badSink.Validate())
+	noKey := valid
+	noKey.PseudonymKey = ""
+	require.Error(t, noKey.Validate())
+
+	// Disabled analytics is not checked.
+	require.NoError(t, (&config.Analytics{Sink: "s3"}).Validate())
+	var unset *config.Analytics
+	require.NoError(t, unset.Validate())
+}
+
+func TestWithdrawValidate(t *testing.T) {
+	for _, provider := range []string{config.PayoutProviderStripe, config.PayoutProviderManual} {
+		require.NoError(t, (&config.Withdraw{PayoutProvider: provider}).Validate(), provider)
+	}
+	for _, provider := range []string{"", "Stripe", "paypal"} {
+		require.Error(t, (&config.Withdraw{PayoutProvider: provider}).Validate(), provider)
+	}
+	approval := config.Withdraw{
+		PayoutProvider:    config.PayoutProviderStripe,
+		ApprovalThreshold: 10000,
+		ApprovalCurrency:  "USD",
+		RequiredApprovals: 2,
+	}
+	require.NoError(t, approval.Validate())
+	noApprovers := approval
+	noApprovers.RequiredApprovals = 0
+	require.Error(t, noApprovers.Validate())
+	badCurrency := approval
+	badCurrency.ApprovalCurrency = "usd"
+	require.Error(t, badCurrency.Validate())
+	negative := approval
+	negative.ApprovalThreshold = -1
+	require.Error(t, negative.Validate())
+
+	var unset *config.Withdraw
+	require.NoError(t, unset.Validate())
+}
+
+func TestExchangeValidate(t *testing.T) {
+	valid := &config.Exchange{WarmupPairs: []string{"USD/EUR", "eur/gbp", " USD/JPY"}}
+	require.NoError(t, valid.Validate())
+	for _, pair := range []string{"", "USD", "USDEUR", "USD/EURO", "USD-EUR"} {
+		require.Error(t, (&config.Exchange{WarmupPairs: []string{pair}}).Validate(), pair)
+	}
+	for _, mode := range []string{"half_even", "half-up", "FLOOR"} {
+		require.NoError(t, (&config.Exchange{RoundingMode: mode}).Validate(), mode)
+	}
+	require.Error(t, (&config.Exchange{RoundingMode: "ceil"}).Validate())
+	allowlist := config.Exchange{
+		AllowedPairs:      []string{"USD/EUR", " eur/usd"},
+		AllowedCurrencies: []string{"USD", "gbp"},
+	}
+	require.NoError(t, allowlist.Validate())
+	require.Error(t, (&config.Exchange{AllowedPairs: []string{"USD-EUR"}}).Validate())
+	require.Error(t, (&config.Exchange{AllowedCurrencies: []string{"EURO"}}).Validate())
+	quota := config.Exchange{QuotaLimit: 1500, QuotaSoftLimit: 0.9, QuotaResetDay: 1}
+	require.NoError(t, quota.Validate())
+	badLimit, badSoftLimit, badResetDay := quota, quota, quota
+	badLimit.QuotaLimit = -1
+	badSoftLimit.QuotaSoftLimit = 1.5
+	badResetDay.QuotaResetDay = 31
+	require.Error(t, badLimit.Validate())
+	require.Error(t, badSoftLimit.Validate())
+	require.Error(t, badResetDay.Validate())
+
+	var unset *config.Exchange
+	require.NoError(t, unset.Validate())
+}
+
+func TestPaymentMethods(t *testing.T) {
+	methods := config.PaymentMethods{
+		"card":       "*",
+		"sepa_debit": "EUR/AT|DE|FR",
+		"ideal":      "eur/nl",
+		"us_bank":    "USD/US",
+		"klarna":     "EUR|USD",
+	}
+	require.NoError(t, methods.Validate())
+
+	for _, tt := range []struct {
+		currency, country string
+		want              []string
+	}{
+		{"EUR", "DE", []string{"card", "klarna", "sepa_debit"}},
+		{"eur", "nl", []string{"card", "ideal", "klarna"}},
+		{"USD", "DE", []string{"card", "klarna"}},
+		{"USD", "", []string{"card", "klarna", "us_bank"}},
+		{"JPY", "JP", []string{"card"}},
+	} {
+		got, err := methods.For(tt.currency, tt.country)
+		require.NoError(t, err)
+		require.Equal(t, tt.want, got, tt.currency+"/"+tt.country)
+	}
+
+	for _, rule := range []string{"", "EURO", "EUR/DEU", "EUR/", "EUR|/DE"} {
+		require.Error(t, config.PaymentMethods{"card": rule}.Validate(), rule)
+	}
+}
@@ -2,6 +2,7 @@ package config
 
 import (
 	"log/slog"
+	"slices"
 
 	"github.com/joho/godotenv"
 	"github.com/kelseyhightower/envconfig"
@@ -54,6 +55,13 @@ func loadFromEnv() (*App, error) {
 		return nil, err
 	}
 
+	if err = cfg.Secrets.Validate(); err != nil {
+		return nil, err
+	}
+	if err = resolveSecrets(&cfg); err != nil {
+		return nil, err
+	}
+
 	// Set default values if not set
 	if cfg.Env == "" {
 		cfg.Env = "development"
@@ -61,6 +69,54 @@ func loadFromEnv() (*App, error) {
 	if cfg.EventBus == nil {
 		cfg.EventBus = &EventBus{}
 	}
+	if cfg.CORS != nil && len(cfg.CORS.AllowHeaders) == 0 {
+		cfg.CORS.AllowHeaders = slices.Clone(DefaultCORSAllowHeaders)
+	}
+	if p := cfg.PaymentProviders; p != nil && p.Stripe != nil &&
+		p.Stripe.BaseURL == "" && cfg.Server != nil {
+		p.Stripe.BaseURL = cfg.Server.BaseURL()
+	}
+	if p := cfg.PaymentProviders; p != nil && p.Stripe != nil {
+		if err = p.Stripe.ApplicationFee.Validate(); err != nil {
+			return nil, err
+		}
+		if err = p.Stripe.PaymentMethods.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	if err = cfg.DB.Validate(); err != nil {
+		return nil, err
+	}
+	if err = cfg.API.Validate(); err != nil {
+		return nil, err
+	}
+	if err = cfg.CORS.Validate(); err != nil {
+		return nil, err
+	}
+	if err = cfg.Account.Validate(); err != nil {
+		return nil, err
+	}
+	if err = cfg.Withdraw.Validate(); err != nil {
+		return nil, err
+	}
+	if err = cfg.Exchange.Validate(); err != nil {
+		return nil, err
+	}
+	if err = cfg.Interest.Validate(); err != nil {
+		return nil, err
+	}
+	if err = cfg.Analytics.Validate(); err != nil {
+		return nil, err
+	}
+	if err = cfg.Anomaly.Validate(); err != nil {
+		return nil, err
+	}
+	if err = cfg.EventBus.Validate(); err != nil {
+		return nil, err
+	}
+	if err = cfg.Fee.Validate(); err != nil {
+		return nil, err
+	}
 
 	logger := slog.Default()
 	logger.Info("Environment variables loaded from .env file")
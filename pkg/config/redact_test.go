@@ -0,0 +1,158 @@
+package config_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/amirasaad/fintech/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiagnostics(t *testing.T) {
+	cfg := &config.App{
+		Env: "staging",
+		DB: &config.DB{
+			Url:             "postgres://fintech:db-pass-1@db:5432/fintech?sslmode=disable",
+			ReplicaUrls:     []string{"host=replica user=fintech password=db-pass-2 dbname=fintech"},
+			ConnMaxLifetime: time.Hour,
+		},
+		Auth: &config.Auth{Jwt: &config.Jwt{
+			Secret: "jwt-secret-1", Expiry: 15 * time.Minute, Issuer: "fintech",
+		}},
+		Redis:    &config.Redis{URL: "redis://:redis-pass-1@redis:6379/0", KeyPrefix: "fintech:"},
+		EventBus: &config.EventBus{Driver: "Kafka", KafkaSASLPassword: "kafka-pass-1"},
+		PaymentProviders: &config.PaymentProviders{Stripe: &config.Stripe{
+			ApiKey:        "sk_live_stripe-key-1",
+			SigningSecret: "whsec_stripe-secret-1",
+		}},
+		Withdraw: &config.Withdraw{PayoutProvider: config.PayoutProviderManual},
+		Analytics: &config.Analytics{
+			URL: "https://collector.example.com/ingest?api_key=analytics-key-1", Token: "",
+		},
+	}
+
+	d := cfg.Diagnostics()
+	assert.Equal(t, "staging", d.Env)
+	assert.Equal(t, "kafka", d.EventBus)
+	assert.Equal(t, "stripe", d.PaymentProvider)
+	assert.Equal(t, config.PayoutProviderManual, d.PayoutProvider)
+
+	assert.Equal(t, "****", d.Settings["AUTH_JWT_SECRET"])
+	assert.Equal(t, "****", d.Settings["PAYMENT_PROVIDER_STRIPE_API_KEY"])
+	assert.Equal(t, "", d.Settings["ANALYTICS_TOKEN"], "unset secrets stay empty")
+	assert.Equal(t, "fintech", d.Settings["AUTH_JWT_ISSUER"])
+	assert.Equal(t, "15m0s", d.Settings["AUTH_JWT_EXPIRY"])
+	assert.Equal(t, "fintech:", d.Settings["REDIS_KEY_PREFIX"])
+	assert.Equal(t,
+		"postgres://fintech:****@db:5432/fintech?sslmode=disable",
+		d.Settings["DATABASE_URL"],
+	)
+	assert.Equal(t, "redis://:****@redis:6379/0", d.Settings["REDIS_URL"])
+
+	data, err := json.Marshal(d)
+	require.NoError(t, err)
+	for _, secret := range []string{
+		"db-pass-1", "db-pass-2", "jwt-secret-1", "redis-pass-1", "kafka-pass-1",
+		"stripe-key-1", "stripe-secret-1", "analytics-key-1",
+	} {
+		assert.NotContains(t, string(data), secret)
+	}
+}
+
+func TestDiagnostics_DefaultEventBus(t *testing.T) {
+	d := (&config.App{}).Diagnostics()
+	assert.Equal(t, "memory", d.EventBus)
+	assert.Empty(t, d.PaymentProvider)
+}
+
+// sensitiveSettingPattern matches the names of settings that may hold
+// secrets. Each must be redacted or listed in notSecretSettings.
+var sensitiveSettingPattern = regexp.MustCompile(
+	`SECRET|PASSWORD|PASSWD|TOKEN|KEY|PEM|IBAN|CREDENTIAL|PRIVATE|DSN|AUTH`,
+)
+
+var notSecretSettings = map[string]bool{
+	"REDIS_KEY_PREFIX":              true,
+	"SECRETS_BACKEND":               true,
+	"SECRETS_DIR":                   true,
+	"SECRETS_VAULT_ADDR":            true,
+	"EVENT_BUS_KAFKA_TLS_CA_PEM":    true,
+	"EVENT_BUS_KAFKA_TLS_CERT_PEM":  true,
+	"EVENT_BUS_KAFKA_SASL_USERNAME": true,
+	"AUTH_STRATEGY":                 true,
+	"AUTH_JWT_ISSUER":               true,
+	"AUTH_JWT_AUDIENCE":             true,
+}
+
+// TestRedacted_Exhaustive sets every setting to a distinct value and checks
+// that no setting that looks secret is exposed, so a secret setting added
+// later is not leaked by default.
+func TestRedacted_Exhaustive(t *testing.T) {
+	cfg := &config.App{}
+	values := map[string]string{}
+	fillSettings(reflect.ValueOf(cfg).Elem(), "", values)
+
+	settings := cfg.Redacted()
+	data, err := json.Marshal(settings)
+	require.NoError(t, err)
+
+	for name, value := range values {
+		require.Contains(t, settings, name)
+		if !sensitiveSettingPattern.MatchString(name) || notSecretSettings[name] {
+			continue
+		}
+		assert.NotContains(t, string(data), value, "%s is not redacted", name)
+	}
+	for name := range notSecretSettings {
+		assert.Contains(t, values, name, "stale entry in notSecretSettings")
+	}
+}
+
+// fillSettings sets every string in the section v, allocating nil sections,
+// to a distinct value, recorded in values by environment variable.
+func fillSettings(v reflect.Value, prefix string, values map[string]string) {
+	t := v.Type()
+	for i := range t.NumField() {
+		field := t.Field(i)
+		name := field.Tag.Get("envconfig")
+		if name == "" {
+			name = strings.ToUpper(field.Name)
+		}
+		if prefix != "" {
+			name = prefix + "_" + name
+		}
+		fv := v.Field(i)
+		if fv.Kind() == reflect.Pointer && fv.Type().Elem().Kind() == reflect.Struct {
+			fv.Set(reflect.New(fv.Type().Elem()))
+			fillSettings(fv.Elem(), name, values)
+			continue
+		}
+		value := fmt.Sprintf("value-%04d-end", len(values))
+		switch fv.Kind() {
+		case reflect.String:
+			fv.SetString(value)
+		case reflect.Slice:
+			if fv.Type().Elem().Kind() != reflect.String {
+				continue
+			}
+			fv.Set(reflect.ValueOf([]string{value}).Convert(fv.Type()))
+		case reflect.Map:
+			if fv.Type().Elem().Kind() != reflect.String {
+				continue
+			}
+			m := reflect.MakeMap(fv.Type())
+			m.SetMapIndex(reflect.ValueOf("k").Convert(fv.Type().Key()),
+				reflect.ValueOf(value).Convert(fv.Type().Elem()))
+			fv.Set(m)
+		default:
+			continue
+		}
+		values[name] = value
+	}
+}
@@ -0,0 +1,306 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// fileScheme prefixes a setting whose value is the contents of a file,
+	// e.g. file:///run/secrets/stripe_secret_key.
+	fileScheme = "file://"
+	// secretScheme prefixes a setting whose value is read from the secrets
+	// backend, e.g. secret://fintech/stripe#secret_key.
+	secretScheme = "secret://"
+)
+
+// ErrSecretNotFound is returned when a referenced secret does not exist.
+var ErrSecretNotFound = errors.New("secret not found")
+
+// Secrets configures resolving secret references in the other settings, so
+// secrets do not have to be stored in the environment. It is off by default:
+// values are then used as they are.
+//
+// When enabled, a setting whose value starts with file:// is replaced by the
+// contents of that file, and one starting with secret:// by the secret it
+// names in Backend. Other values are used as they are.
+type Secrets struct {
+	Enabled bool `envconfig:"ENABLED" default:"false"`
+	// Backend resolves secret:// references: "file" reads secret://name
+	// from Dir, "vault" reads secret://path#key from a HashiCorp Vault KV
+	// engine. Others can be added with RegisterSecretBackend.
+	Backend string `envconfig:"BACKEND" default:"file"`
+	// Dir is where the file backend reads secrets from.
+	Dir string `envconfig:"DIR" default:"/run/secrets"`
+	// VaultAddr and VaultToken connect the vault backend, e.g.
+	// https://vault.internal:8200. The token may be a file:// reference.
+	VaultAddr  string        `envconfig:"VAULT_ADDR"`
+	VaultToken string        `envconfig:"VAULT_TOKEN"`
+	Timeout    time.Duration `envconfig:"TIMEOUT" default:"5s"`
+}
+
+// SecretResolver reads secrets from a secrets backend.
+type SecretResolver interface {
+	// Resolve returns the secret named by ref, the part of a secret://
+	// reference after the scheme, or ErrSecretNotFound.
+	Resolve(ctx context.Context, ref string) (string, error)
+}
+
+// SecretBackendFactory creates the SecretResolver of a secrets backend.
+type SecretBackendFactory func(cfg *Secrets) (SecretResolver, error)
+
+var (
+	secretBackendsMu sync.RWMutex
+	secretBackends   = map[string]SecretBackendFactory{
+		"file":  newFileSecretResolver,
+		"vault": newVaultSecretResolver,
+	}
+)
+
+// RegisterSecretBackend makes a secrets backend available as SECRETS_BACKEND
+// name, e.g. for AWS Secrets Manager. It must be called before Load.
+func RegisterSecretBackend(name string, factory SecretBackendFactory) {
+	secretBackendsMu.Lock()
+	defer secretBackendsMu.Unlock()
+	secretBackends[name] = factory
+}
+
+// Validate checks that the backend of enabled secrets is known.
+func (s *Secrets) Validate() error {
+	if s == nil || !s.Enabled {
+		return nil
+	}
+	secretBackendsMu.RLock()
+	_, ok := secretBackends[s.Backend]
+	secretBackendsMu.RUnlock()
+	if !ok {
+		return fmt.Errorf("SECRETS_BACKEND: unknown backend %q", s.Backend)
+	}
+	if s.Timeout <= 0 {
+		return fmt.Errorf("SECRETS_TIMEOUT must be positive, got %s", s.Timeout)
+	}
+	return nil
+}
+
+// resolveSecrets replaces the secret references in cfg's string settings with
+// the secrets they name. The settings of Secrets itself are not resolved,
+// except for a file:// VaultToken.
+func resolveSecrets(cfg *App) error {
+	s := cfg.Secrets
+	if s == nil || !s.Enabled {
+		warnUnresolvedSecrets(reflect.ValueOf(cfg).Elem(), "")
+		return nil
+	}
+	if strings.HasPrefix(s.VaultToken, fileScheme) {
+		token, err := readSecretFile(strings.TrimPrefix(s.VaultToken, fileScheme))
+		if err != nil {
+			return fmt.Errorf("SECRETS_VAULT_TOKEN: %w", err)
+		}
+		s.VaultToken = token
+	}
+
+	secretBackendsMu.RLock()
+	factory := secretBackends[s.Backend]
+	secretBackendsMu.RUnlock()
+	resolver, err := factory(s)
+	if err != nil {
+		return fmt.Errorf("failed to create %s secrets backend: %w", s.Backend, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.Timeout)
+	defer cancel()
+	return walkStrings(reflect.ValueOf(cfg).Elem(), "", func(path string, v reflect.Value) error {
+		value := v.String()
+		var (
+			secret string
+			err    error
+		)
+		switch {
+		case strings.HasPrefix(value, fileScheme):
+			secret, err = readSecretFile(strings.TrimPrefix(value, fileScheme))
+		case strings.HasPrefix(value, secretScheme):
+			secret, err = resolver.Resolve(ctx, strings.TrimPrefix(value, secretScheme))
+		default:
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to resolve secret for %s: %w", path, err)
+		}
+		v.SetString(secret)
+		return nil
+	})
+}
+
+// warnUnresolvedSecrets logs settings that look like secret references while
+// resolving them is disabled; they are used as they are.
+func warnUnresolvedSecrets(v reflect.Value, path string) {
+	_ = walkStrings(v, path, func(path string, v reflect.Value) error {
+		if value := v.String(); strings.HasPrefix(value, fileScheme) ||
+			strings.HasPrefix(value, secretScheme) {
+			slog.Default().Warn(
+				"Setting looks like a secret reference but SECRETS_ENABLED is false",
+				"setting", path,
+			)
+		}
+		return nil
+	})
+}
+
+// walkStrings calls fn for every settable string field reachable from v
+// through struct fields and non-nil struct pointers, skipping Secrets.
+func walkStrings(v reflect.Value, path string, fn func(string, reflect.Value) error) error {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return nil
+		}
+		return walkStrings(v.Elem(), path, fn)
+	case reflect.Struct:
+		if v.Type() == reflect.TypeFor[Secrets]() {
+			return nil
+		}
+		t := v.Type()
+		for i := range t.NumField() {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			name := field.Name
+			if path != "" {
+				name = path + "." + name
+			}
+			if err := walkStrings(v.Field(i), name, fn); err != nil {
+				return err
+			}
+		}
+	case reflect.String:
+		if v.CanSet() {
+			return fn(path, v)
+		}
+	}
+	return nil
+}
+
+// readSecretFile returns the contents of the secret file at path without a
+// trailing newline.
+func readSecretFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", fmt.Errorf("%w: %s", ErrSecretNotFound, path)
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+// fileSecretResolver reads secret://name from the file name in dir, e.g.
+// Docker or Kubernetes secrets mounted at /run/secrets.
+type fileSecretResolver struct {
+	dir string
+}
+
+func newFileSecretResolver(cfg *Secrets) (SecretResolver, error) {
+	return &fileSecretResolver{dir: cfg.Dir}, nil
+}
+
+func (r *fileSecretResolver) Resolve(_ context.Context, ref string) (string, error) {
+	path := filepath.Join(r.dir, filepath.Clean("/"+ref))
+	return readSecretFile(path)
+}
+
+// vaultSecretResolver reads secret://path#key from the key of the secret at
+// path in HashiCorp Vault, e.g. secret://secret/data/fintech#stripe_secret_key
+// for a KV version 2 engine mounted at secret. Each secret is read once.
+type vaultSecretResolver struct {
+	addr   string
+	token  string
+	client *http.Client
+
+	mu    sync.Mutex
+	cache map[string]map[string]any
+}
+
+func newVaultSecretResolver(cfg *Secrets) (SecretResolver, error) {
+	if cfg.VaultAddr == "" || cfg.VaultToken == "" {
+		return nil, errors.New("SECRETS_VAULT_ADDR and SECRETS_VAULT_TOKEN are required")
+	}
+	return &vaultSecretResolver{
+		addr:   strings.TrimRight(cfg.VaultAddr, "/"),
+		token:  cfg.VaultToken,
+		client: &http.Client{Timeout: cfg.Timeout},
+		cache:  map[string]map[string]any{},
+	}, nil
+}
+
+func (r *vaultSecretResolver) Resolve(ctx context.Context, ref string) (string, error) {
+	path, key, ok := strings.Cut(ref, "#")
+	if !ok || path == "" || key == "" {
+		return "", fmt.Errorf("vault reference %q must be secret://path#key", ref)
+	}
+	data, err := r.read(ctx, path)
+	if err != nil {
+		return "", err
+	}
+	value, ok := data[key].(string)
+	if !ok {
+		return "", fmt.Errorf("%w: %s#%s", ErrSecretNotFound, path, key)
+	}
+	return value, nil
+}
+
+// read returns the key-value pairs of the secret at path, from the cache if
+// it was read before.
+func (r *vaultSecretResolver) read(ctx context.Context, path string) (map[string]any, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if data, ok := r.cache[path]; ok {
+		return data, nil
+	}
+
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodGet, r.addr+"/v1/"+strings.TrimLeft(path, "/"), nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Vault-Token", r.token)
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read vault secret %s: %w", path, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, fmt.Errorf("%w: %s", ErrSecretNotFound, path)
+	case resp.StatusCode != http.StatusOK:
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("failed to read vault secret %s: %s: %s",
+			path, resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	var body struct {
+		Data map[string]any `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to decode vault secret %s: %w", path, err)
+	}
+	data := body.Data
+	// KV version 2 nests the key-value pairs under data.data.
+	if nested, ok := data["data"].(map[string]any); ok {
+		data = nested
+	}
+	r.cache[path] = data
+	return data, nil
+}
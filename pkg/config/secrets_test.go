@@ -0,0 +1,94 @@
+package config_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/amirasaad/fintech/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadResolvesSecrets(t *testing.T) {
+	dir := t.TempDir()
+	writeSecret := func(name, value string) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(value), 0o600))
+		return path
+	}
+
+	t.Run("disabled uses values as they are", func(t *testing.T) {
+		t.Setenv("AUTH_JWT_SECRET", "file://"+writeSecret("jwt", "from-file"))
+		cfg, err := config.Load()
+		require.NoError(t, err)
+		require.Equal(t, "file://"+filepath.Join(dir, "jwt"), cfg.Auth.Jwt.Secret)
+	})
+
+	t.Run("file references", func(t *testing.T) {
+		t.Setenv("SECRETS_ENABLED", "true")
+		t.Setenv("SECRETS_DIR", dir)
+		t.Setenv("AUTH_JWT_SECRET", "file://"+writeSecret("jwt", "from-file\n"))
+		t.Setenv("PAYMENT_PROVIDER_STRIPE_API_KEY", "secret://stripe_key")
+		writeSecret("stripe_key", "sk_test_123")
+		t.Setenv("DATABASE_URL", "postgres://plain")
+		cfg, err := config.Load()
+		require.NoError(t, err)
+		require.Equal(t, "from-file", cfg.Auth.Jwt.Secret)
+		require.Equal(t, "sk_test_123", cfg.PaymentProviders.Stripe.ApiKey)
+		require.Equal(t, "postgres://plain", cfg.DB.Url)
+	})
+
+	t.Run("missing secret fails", func(t *testing.T) {
+		t.Setenv("SECRETS_ENABLED", "true")
+		t.Setenv("SECRETS_DIR", dir)
+		t.Setenv("AUTH_JWT_SECRET", "secret://../jwt_missing")
+		_, err := config.Load()
+		require.ErrorIs(t, err, config.ErrSecretNotFound)
+		require.ErrorContains(t, err, "Auth.Jwt.Secret")
+	})
+
+	t.Run("vault references", func(t *testing.T) {
+		var requests int
+		vault := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			if r.Header.Get("X-Vault-Token") != "root-token" {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			if r.URL.Path != "/v1/secret/data/fintech" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, _ = w.Write([]byte(
+				`{"data":{"data":{"jwt":"from-vault","stripe":"sk_vault"},"metadata":{}}}`,
+			))
+		}))
+		defer vault.Close()
+
+		t.Setenv("SECRETS_ENABLED", "true")
+		t.Setenv("SECRETS_BACKEND", "vault")
+		t.Setenv("SECRETS_VAULT_ADDR", vault.URL)
+		t.Setenv("SECRETS_VAULT_TOKEN", "file://"+writeSecret("vault_token", "root-token\n"))
+		t.Setenv("AUTH_JWT_SECRET", "secret://secret/data/fintech#jwt")
+		t.Setenv("PAYMENT_PROVIDER_STRIPE_API_KEY", "secret://secret/data/fintech#stripe")
+		cfg, err := config.Load()
+		require.NoError(t, err)
+		require.Equal(t, "from-vault", cfg.Auth.Jwt.Secret)
+		require.Equal(t, "sk_vault", cfg.PaymentProviders.Stripe.ApiKey)
+		require.Equal(t, 1, requests)
+
+		t.Setenv("AUTH_JWT_SECRET", "secret://secret/data/fintech#missing")
+		_, err = config.Load()
+		require.ErrorIs(t, err, config.ErrSecretNotFound)
+	})
+
+	t.Run("unknown backend fails", func(t *testing.T) {
+		t.Setenv("SECRETS_ENABLED", "true")
+		t.Setenv("SECRETS_BACKEND", "nope")
+		t.Setenv("AUTH_JWT_SECRET", "plain")
+		_, err := config.Load()
+		require.Error(t, err)
+	})
+}
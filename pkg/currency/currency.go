@@ -343,6 +343,7 @@ func New(ctx context.Context, params ...string) (*Registry, error) {
 
 // NewRegistryWithPersistence creates a currency registry with persistence
 // If redisURL is provided, it will use Redis for caching
+// Call Close on shutdown so the last changes are saved.
 func NewRegistryWithPersistence(
 	ctx context.Context, persistencePath string, redisURL ...string,
 ) (*Registry, error) {
@@ -679,6 +680,21 @@ func (cr *Registry) SearchByRegion(region string) ([]Meta, error) {
 	return currencies, nil
 }
 
+// Close closes the underlying registry provider if it is a registry.Closer,
+// saving the currencies one final time when they are persisted. Registering,
+// unregistering, activating or deactivating currencies afterwards fails with
+// registry.ErrClosed.
+func (cr *Registry) Close(ctx context.Context) error {
+	closer, ok := cr.registry.(registry.Closer)
+	if !ok {
+		return nil
+	}
+	if err := closer.Close(ctx); err != nil {
+		return fmt.Errorf("failed to close currency registry: %w", err)
+	}
+	return nil
+}
+
 // GetRegistry returns the underlying registry provider
 func (cr *Registry) GetRegistry() registry.Provider {
 	return cr.registry
@@ -23,6 +23,13 @@ var (
 	// withdrawal or transfer.
 	ErrInsufficientFunds = errors.New("insufficient funds")
 
+	// ErrOverdraftLimitExceeded is returned when a withdrawal or transfer would
+	// take an account with an overdraft below its overdraft limit.
+	ErrOverdraftLimitExceeded = errors.New("overdraft limit exceeded")
+
+	// ErrNegativeOverdraftLimit is returned when an overdraft limit is negative.
+	ErrNegativeOverdraftLimit = errors.New("overdraft limit cannot be negative")
+
 	// ErrAccountNotFound is returned when an account cannot be found.
 	ErrAccountNotFound = errors.New("account not found")
 
@@ -41,34 +48,117 @@ var (
 	// ErrCurrencyMismatch is returned when there is
 	// a currency mismatch between accounts or transactions.
 	ErrCurrencyMismatch = errors.New("currency mismatch")
+	// ErrAccountNotActive is returned when an operation targets an account
+	// that is not active (e.g. closed or frozen).
+	ErrAccountNotActive = errors.New("account is not active")
+	// ErrScheduleInPast is returned when a transfer is scheduled for a time
+	// that is not in the future.
+	ErrScheduleInPast = errors.New("scheduled execution time must be in the future")
+	// ErrScheduledTransferNotPending is returned when canceling a scheduled
+	// transfer that has already been executed, canceled or failed.
+	ErrScheduledTransferNotPending = errors.New("scheduled transfer is not pending")
+	// ErrPendingTransferNotPending is returned when approving or rejecting a
+	// transfer that has already been approved or rejected.
+	ErrPendingTransferNotPending = errors.New("transfer is no longer awaiting approval")
+	// ErrApprovalTransferScheduled is returned when a transfer both requires
+	// approval and is scheduled; only immediate transfers can require approval.
+	ErrApprovalTransferScheduled = errors.New("a transfer requiring approval cannot be scheduled")
+	// ErrPendingWithdrawalNotPending is returned when approving or rejecting
+	// a withdrawal that has already been approved or rejected.
+	ErrPendingWithdrawalNotPending = errors.New("withdrawal is no longer awaiting approval")
+	// ErrSelfApproval is returned when a user approves a withdrawal they
+	// requested themselves.
+	ErrSelfApproval = errors.New("cannot approve your own withdrawal")
+	// ErrAlreadyApproved is returned when an approver approves the same
+	// withdrawal twice.
+	ErrAlreadyApproved = errors.New("withdrawal already approved by this approver")
+	// ErrCurrencyDeactivated is returned when money would move into a currency
+	// that has been deactivated. Accounts in that currency can still be
+	// withdrawn from and transferred out of while they wind down.
+	ErrCurrencyDeactivated = errors.New("currency is deactivated")
+
+	// ErrTransactionNotRefundable is returned when a refund is requested for a
+	// transaction that is not a completed deposit.
+	ErrTransactionNotRefundable = errors.New("transaction is not refundable")
+
+	// ErrRefundExceedsDeposit is returned when a refund would take the total
+	// refunded above the original deposit.
+	ErrRefundExceedsDeposit = errors.New("refund exceeds deposit")
+
+	// ErrDepositNotCancellable is returned when a deposit is canceled after
+	// it has been paid, failed or been canceled.
+	ErrDepositNotCancellable = errors.New("deposit cannot be canceled")
+
+	// ErrDepositNotCapturable is returned when a deposit is captured that is
+	// not authorized and awaiting capture.
+	ErrDepositNotCapturable = errors.New("deposit cannot be captured")
+
+	// ErrCaptureExceedsAuthorized is returned when a capture is above the
+	// amount authorized for the deposit.
+	ErrCaptureExceedsAuthorized = errors.New("capture exceeds authorized amount")
+
+	// ErrReceiptUnavailable is returned when a receipt is requested for a
+	// transaction that is not completed.
+	ErrReceiptUnavailable = errors.New("receipt unavailable for transaction")
+
+	// ErrReceiptNotFound is returned for a receipt link whose token is not
+	// valid.
+	ErrReceiptNotFound = errors.New("receipt not found")
+
+	// ErrReceiptExpired is returned for a receipt link that has expired.
+	ErrReceiptExpired = errors.New("receipt link expired")
+
+	// ErrReprocessNotSupported is returned when the events of a transaction
+	// are reprocessed whose type or status has no event to re-emit.
+	ErrReprocessNotSupported = errors.New("transaction events cannot be reprocessed")
+
+	// ErrAccountBusy is returned when another operation held the account
+	// for longer than the lock timeout.
+	ErrAccountBusy = errors.New("account is busy")
+)
+
+// Account statuses. Accounts created before statuses were recorded have none
+// and are active.
+const (
+	StatusActive = "active"
+	// StatusFrozen is an account held for review, e.g. after suspicious
+	// activity; no money leaves it until it is active again.
+	StatusFrozen = "frozen"
 )
 
+// IsActiveStatus reports whether an account with the status may move money.
+func IsActiveStatus(status string) bool {
+	return status == "" || status == StatusActive
+}
+
 // Account represents a user's financial account, encapsulating its balance and ownership.
 // It acts as an aggregate root, ensuring all state changes are consistent and valid.
 //
 // Invariants:
 // - An account must always have a valid owner (UserID).
 // - The account's balance is represented by a Money value object, ensuring currency consistency.
-// - The balance can never be negative.
+// - The balance can never go below -OverdraftLimit (zero unless opted in), except by Reverse.
 // - All operations are thread-safe, enforced by a mutex.
 type Account struct {
-	ID        uuid.UUID
-	UserID    uuid.UUID
-	Balance   *money.Money // Account balance as a Money value object.
-	UpdatedAt time.Time
-	CreatedAt time.Time
+	ID             uuid.UUID
+	UserID         uuid.UUID
+	Balance        *money.Money // Account balance as a Money value object.
+	OverdraftLimit *money.Money // How far below zero Balance may go.
+	UpdatedAt      time.Time
+	CreatedAt      time.Time
 }
 
 // Builder provides a fluent API for constructing Account instances.
 // This pattern is particularly useful for setting optional parameters and ensuring
 // that only valid accounts are constructed.
 type Builder struct {
-	id        uuid.UUID
-	userID    uuid.UUID
-	balance   int64
-	currency  money.Code
-	updatedAt time.Time
-	createdAt time.Time
+	id             uuid.UUID
+	userID         uuid.UUID
+	balance        int64
+	overdraftLimit int64
+	currency       money.Code
+	updatedAt      time.Time
+	createdAt      time.Time
 }
 
 // New creates a new Builder with sensible defaults, such as a new UUID and the default currency.
@@ -116,6 +206,13 @@ func (b *Builder) WithBalance(balance int64) *Builder {
 	return b
 }
 
+// WithOverdraftLimit sets how far below zero the balance may go, in the
+// smallest currency unit. The default of zero means no overdraft.
+func (b *Builder) WithOverdraftLimit(limit int64) *Builder {
+	b.overdraftLimit = limit
+	return b
+}
+
 // WithCreatedAt sets the creation timestamp. This is primarily for hydrating
 // an existing account from a data store.
 func (b *Builder) WithCreatedAt(t time.Time) *Builder {
@@ -148,12 +245,21 @@ func (b *Builder) Build() (*Account, error) {
 		return nil, fmt.Errorf("invalid balance: %w", err)
 	}
 
+	if b.overdraftLimit < 0 {
+		return nil, ErrNegativeOverdraftLimit
+	}
+	overdraftLimit, err := money.NewFromSmallestUnit(b.overdraftLimit, b.currency)
+	if err != nil {
+		return nil, fmt.Errorf("invalid overdraft limit: %w", err)
+	}
+
 	return &Account{
-		ID:        b.id,
-		UserID:    b.userID,
-		Balance:   balance,
-		UpdatedAt: b.updatedAt,
-		CreatedAt: b.createdAt,
+		ID:             b.id,
+		UserID:         b.userID,
+		Balance:        balance,
+		OverdraftLimit: overdraftLimit,
+		UpdatedAt:      b.updatedAt,
+		CreatedAt:      b.createdAt,
 	}, nil
 }
 
@@ -169,6 +275,71 @@ func (a *Account) SetCurrency(c money.Code) error {
 	return err
 }
 
+// AvailableBalance returns the amount that can be withdrawn or transferred:
+// the balance plus the overdraft limit. It is negative only if the balance is
+// already below the overdraft limit.
+func (a *Account) AvailableBalance() *money.Money {
+	if a.OverdraftLimit == nil {
+		return a.Balance
+	}
+	available, err := a.Balance.Add(a.OverdraftLimit)
+	if err != nil {
+		// Build guarantees both share the account currency.
+		return a.Balance
+	}
+	return available
+}
+
+// Owed returns how far the balance is below the overdraft limit: money the
+// account owes beyond the credit it was granted, recovered from later
+// deposits. It is zero unless a reversal debited more than was available.
+func (a *Account) Owed() *money.Money {
+	available := a.AvailableBalance()
+	if !available.IsNegative() {
+		return money.Zero(available.CurrencyCode())
+	}
+	return available.Abs()
+}
+
+// Reverse debits amount that the payment provider took back from a deposit,
+// e.g. after the payer disputed the charge. Unlike a withdrawal it cannot be
+// refused, so the balance may drop below the overdraft limit. It returns the
+// shortfall: the part of amount the account could not cover, which it now
+// owes. Withdrawals and transfers are refused until deposits repay it.
+func (a *Account) Reverse(amount *money.Money) (*money.Money, error) {
+	if err := a.validateAmount(amount); err != nil {
+		return nil, err
+	}
+	if !a.Balance.IsSameCurrency(amount) {
+		return nil, ErrCurrencyMismatch
+	}
+	owed := a.Owed()
+	balance, err := a.Balance.Subtract(amount)
+	if err != nil {
+		return nil, err
+	}
+	a.Balance = balance
+	return a.Owed().Subtract(owed)
+}
+
+// checkFunds reports whether amount can be debited: ErrInsufficientFunds if
+// the account has no overdraft and amount exceeds the balance, or
+// ErrOverdraftLimitExceeded if it would exceed the balance plus the overdraft.
+func (a *Account) checkFunds(amount *money.Money) error {
+	available := a.AvailableBalance()
+	exceeds, err := amount.GreaterThan(available)
+	if err != nil {
+		return err
+	}
+	if !exceeds {
+		return nil
+	}
+	if a.OverdraftLimit.IsZero() {
+		return ErrInsufficientFunds
+	}
+	return ErrOverdraftLimitExceeded
+}
+
 // validate checks all business invariants for an operation (common validation logic).
 func (a *Account) validate(userID uuid.UUID) error {
 	if a.UserID != userID {
@@ -208,7 +379,7 @@ func (a *Account) ValidateDeposit(userID uuid.UUID, amount *money.Money) (err er
 //   - Only the account owner can withdraw.
 //   - Withdrawal amount must be positive.
 //   - Withdrawal currency must match account currency.
-//   - Cannot withdraw more than the current balance.
+//   - Cannot withdraw more than the available balance (balance plus overdraft).
 //
 // Returns a Transaction or an error if any invariant is violated.
 func (a *Account) ValidateWithdraw(userID uuid.UUID, amount *money.Money) error {
@@ -218,15 +389,7 @@ func (a *Account) ValidateWithdraw(userID uuid.UUID, amount *money.Money) error
 	if err := a.validateAmount(amount); err != nil {
 		return err
 	}
-	// Sufficient funds check: do not allow negative balance
-	hasEnough, err := a.Balance.GreaterThan(amount)
-	if err != nil {
-		return err
-	}
-	if !hasEnough && !a.Balance.Equals(amount) {
-		return ErrInsufficientFunds
-	}
-	return nil
+	return a.checkFunds(amount)
 }
 
 // ValidateTransfer ensures that a funds transfer from this account to another is valid.
@@ -251,13 +414,5 @@ func (a *Account) ValidateTransfer(
 		!dest.Balance.IsSameCurrency(amount) {
 		return ErrCurrencyMismatch
 	}
-	hasEnough, err := a.Balance.GreaterThan(amount)
-	if err != nil {
-		return err
-	}
-	if !hasEnough && !a.Balance.Equals(amount) {
-		return ErrInsufficientFunds
-	}
-
-	return nil
+	return a.checkFunds(amount)
 }
@@ -120,3 +120,120 @@ func TestValidateTransfer(t *testing.T) {
 		assert.ErrorIs(t, err, domainaccount.ErrCurrencyMismatch)
 	})
 }
+
+func TestOverdraft(t *testing.T) {
+	t.Parallel()
+	userID := uuid.New()
+	receiverID := uuid.New()
+	acc, err := domainaccount.New().
+		WithUserID(userID).
+		WithCurrency("USD").
+		WithBalance(-2000).        // -20.00 USD
+		WithOverdraftLimit(10000). // 100.00 USD
+		Build()
+	require.NoError(t, err)
+	destAcc, err := domainaccount.New().
+		WithUserID(receiverID).
+		WithCurrency("USD").
+		Build()
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(8000), int64(acc.AvailableBalance().Amount()))
+
+	t.Run("within overdraft", func(t *testing.T) {
+		amount, err := money.New(80.0, money.USD)
+		require.NoError(t, err)
+		assert.NoError(t, acc.ValidateWithdraw(userID, amount))
+		assert.NoError(t, acc.ValidateTransfer(userID, receiverID, destAcc, amount))
+	})
+
+	t.Run("beyond overdraft", func(t *testing.T) {
+		amount, err := money.New(80.01, money.USD)
+		require.NoError(t, err)
+		assert.ErrorIs(t, acc.ValidateWithdraw(userID, amount),
+			domainaccount.ErrOverdraftLimitExceeded)
+		assert.ErrorIs(t, acc.ValidateTransfer(userID, receiverID, destAcc, amount),
+			domainaccount.ErrOverdraftLimitExceeded)
+	})
+
+	t.Run("negative limit", func(t *testing.T) {
+		_, err := domainaccount.New().
+			WithUserID(userID).
+			WithOverdraftLimit(-1).
+			Build()
+		assert.ErrorIs(t, err, domainaccount.ErrNegativeOverdraftLimit)
+	})
+}
+
+func TestReverse(t *testing.T) {
+	t.Parallel()
+	userID := uuid.New()
+	newAccount := func(balance int64) *domainaccount.Account {
+		acc, err := domainaccount.New().
+			WithUserID(userID).
+			WithCurrency("USD").
+			WithBalance(balance).
+			WithOverdraftLimit(5000). // 50.00 USD
+			Build()
+		require.NoError(t, err)
+		return acc
+	}
+	usd := func(amount float64) *money.Money {
+		m, err := money.New(amount, money.USD)
+		require.NoError(t, err)
+		return m
+	}
+
+	t.Run("covered by balance and overdraft", func(t *testing.T) {
+		acc := newAccount(3000)
+		shortfall, err := acc.Reverse(usd(70))
+		require.NoError(t, err)
+		assert.True(t, shortfall.IsZero())
+		assert.Equal(t, int64(-4000), int64(acc.Balance.Amount()))
+		assert.True(t, acc.Owed().IsZero())
+	})
+
+	t.Run("beyond the overdraft is owed", func(t *testing.T) {
+		acc := newAccount(3000)
+		shortfall, err := acc.Reverse(usd(100))
+		require.NoError(t, err)
+		assert.Equal(t, int64(2000), int64(shortfall.Amount()))
+		assert.Equal(t, int64(-7000), int64(acc.Balance.Amount()))
+		assert.Equal(t, int64(2000), int64(acc.Owed().Amount()))
+		assert.ErrorIs(t, acc.ValidateWithdraw(userID, usd(0.01)),
+			domainaccount.ErrOverdraftLimitExceeded)
+
+		// A second reversal is owed in full.
+		shortfall, err = acc.Reverse(usd(10))
+		require.NoError(t, err)
+		assert.Equal(t, int64(1000), int64(shortfall.Amount()))
+	})
+
+	t.Run("currency mismatch", func(t *testing.T) {
+		eur, err := money.New(10, money.EUR)
+		require.NoError(t, err)
+		_, err = newAccount(3000).Reverse(eur)
+		assert.ErrorIs(t, err, domainaccount.ErrCurrencyMismatch)
+	})
+}
+
+func TestFeePolicy(t *testing.T) {
+	amount, err := money.New(123.45, money.USD)
+	require.NoError(t, err)
+
+	fee, err := domainaccount.FeePolicy{Percent: 2.5, Fixed: 0.30}.Compute(amount)
+	require.NoError(t, err)
+	// 2.5% of 123.45 is 3.08625, rounded to 3.09, plus 0.30.
+	assert.Equal(t, "3.39", fee.AmountString())
+	assert.Equal(t, money.USD, fee.CurrencyCode())
+
+	fee, err = domainaccount.FeePolicy{}.Compute(amount)
+	require.NoError(t, err)
+	assert.True(t, fee.IsZero())
+
+	_, err = domainaccount.FeePolicy{Fixed: 123.45}.Compute(amount)
+	require.ErrorIs(t, err, domainaccount.ErrFeeExceedsAmount)
+
+	_, err = domainaccount.FeePolicy{Percent: -1}.Compute(amount)
+	require.ErrorIs(t, err, domainaccount.ErrInvalidFeePolicy)
+}
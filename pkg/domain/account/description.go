@@ -0,0 +1,49 @@
+package account
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// MaxDescriptionLength is the most characters a transaction description may
+// have. It matches the unstructured remittance information of ISO 20022
+// credit transfers, so a withdrawal's description fits on the bank
+// statement in full.
+const MaxDescriptionLength = 140
+
+// ErrInvalidDescription is returned when a transaction description is too
+// long.
+var ErrInvalidDescription = errors.New("invalid transaction description")
+
+// NormalizeDescription cleans a user-supplied transaction description. Line
+// breaks, tabs and other control or invisible formatting characters become
+// spaces, runs of spaces collapse to one and the result is trimmed. It
+// returns an error wrapping ErrInvalidDescription if the cleaned description
+// is longer than MaxDescriptionLength characters.
+func NormalizeDescription(s string) (string, error) {
+	s = strings.Join(strings.FieldsFunc(s, func(r rune) bool {
+		return unicode.IsSpace(r) || unicode.IsControl(r) ||
+			unicode.In(r, unicode.Cf) || r == utf8.RuneError
+	}), " ")
+	if n := utf8.RuneCountInString(s); n > MaxDescriptionLength {
+		return "", fmt.Errorf(
+			"%w: %d characters, at most %d allowed",
+			ErrInvalidDescription, n, MaxDescriptionLength,
+		)
+	}
+	return s, nil
+}
+
+// ExportDescription returns a description to write to exported files that
+// may be opened in a spreadsheet. A description starting with a character
+// spreadsheets read as the start of a formula (=, +, -, @) is prefixed with
+// an apostrophe, so it is shown as text instead of being evaluated.
+func ExportDescription(s string) string {
+	if s != "" && strings.ContainsRune("=+-@", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
@@ -0,0 +1,50 @@
+package account_test
+
+import (
+	"strings"
+	"testing"
+
+	domainaccount "github.com/amirasaad/fintech/pkg/domain/account"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeDescription(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"", ""},
+		{"  Rent for March ", "Rent for March"},
+		{"Rent\r\nfor\tMarch", "Rent for March"},
+		{"Rent\x00\x1b[31m for​ March", "Rent [31m for March"},
+		{"Miete für März", "Miete für März"},
+		{strings.Repeat("ä", domainaccount.MaxDescriptionLength), strings.Repeat("ä", 140)},
+	}
+	for _, tt := range tests {
+		got, err := domainaccount.NormalizeDescription(tt.in)
+		require.NoError(t, err, tt.in)
+		assert.Equal(t, tt.want, got)
+	}
+
+	_, err := domainaccount.NormalizeDescription(
+		strings.Repeat("a", domainaccount.MaxDescriptionLength+1),
+	)
+	assert.ErrorIs(t, err, domainaccount.ErrInvalidDescription)
+}
+
+func TestExportDescription(t *testing.T) {
+	t.Parallel()
+	for in, want := range map[string]string{
+		"":                     "",
+		"Rent for March":       "Rent for March",
+		"=HYPERLINK(\"x\")":    "'=HYPERLINK(\"x\")",
+		"+1 234":               "'+1 234",
+		"-2+3":                 "'-2+3",
+		"@SUM(A1:A2)":          "'@SUM(A1:A2)",
+		"Total = rent + bills": "Total = rent + bills",
+	} {
+		assert.Equal(t, want, domainaccount.ExportDescription(in), in)
+	}
+}
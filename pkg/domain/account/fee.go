@@ -1,6 +1,11 @@
 package account
 
-import "github.com/amirasaad/fintech/pkg/money"
+import (
+	"errors"
+	"fmt"
+
+	"github.com/amirasaad/fintech/pkg/money"
+)
 
 // FeeType represents the type of fee.
 type FeeType string
@@ -12,10 +17,86 @@ const (
 	FeeTypeService FeeType = "service"
 	// FeeTypeConversion is the fee for currency conversion.
 	FeeTypeConversion FeeType = "conversion"
+	// FeeTypeApplication is the platform fee withheld from payouts to
+	// connected accounts.
+	FeeTypeApplication FeeType = "application"
+)
+
+var (
+	// ErrInvalidFeePolicy is returned when a fee policy has negative values.
+	ErrInvalidFeePolicy = errors.New("invalid fee policy")
+	// ErrFeeExceedsAmount is returned when a fee would consume the whole amount.
+	ErrFeeExceedsAmount = errors.New("fee exceeds amount")
+	// ErrInvalidFeeAbsorption is returned for an unknown fee absorption policy.
+	ErrInvalidFeeAbsorption = errors.New("fee absorption policy must be absorb or pass_through")
+)
+
+// FeeAbsorption decides who bears the provider fee charged on a deposit.
+type FeeAbsorption string
+
+const (
+	// FeeAbsorptionPassThrough deducts the provider fee from the account, so
+	// the net amount is credited.
+	FeeAbsorptionPassThrough FeeAbsorption = "pass_through"
+	// FeeAbsorptionAbsorb credits the full amount; the platform bears the
+	// provider fee, which is recorded as a platform cost.
+	FeeAbsorptionAbsorb FeeAbsorption = "absorb"
 )
 
+// ParseFeeAbsorption returns the fee absorption policy named s. It returns
+// ErrInvalidFeeAbsorption for anything but absorb and pass_through.
+func ParseFeeAbsorption(s string) (FeeAbsorption, error) {
+	switch p := FeeAbsorption(s); p {
+	case FeeAbsorptionPassThrough, FeeAbsorptionAbsorb:
+		return p, nil
+	default:
+		return "", fmt.Errorf("%w: %q", ErrInvalidFeeAbsorption, s)
+	}
+}
+
 // Fee represents a fee associated with a transaction.
 type Fee struct {
 	Amount *money.Money
 	Type   FeeType
 }
+
+// FeePolicy is a percentage of the amount plus a fixed fee. Fixed is in
+// major units of the amount's currency.
+type FeePolicy struct {
+	Percent float64
+	Fixed   float64
+}
+
+// IsZero reports whether the policy charges nothing.
+func (p FeePolicy) IsZero() bool {
+	return p.Percent == 0 && p.Fixed == 0
+}
+
+// Compute returns the fee for amount, in amount's currency. The percentage
+// part is rounded to the nearest smallest unit. It returns ErrFeeExceedsAmount
+// if the fee is not less than amount.
+func (p FeePolicy) Compute(amount *money.Money) (*money.Money, error) {
+	if p.Percent < 0 || p.Fixed < 0 {
+		return nil, fmt.Errorf("%w: percent %v, fixed %v", ErrInvalidFeePolicy, p.Percent, p.Fixed)
+	}
+	fee, err := amount.Multiply(p.Percent / 100)
+	if err != nil {
+		return nil, err
+	}
+	fixed, err := money.New(p.Fixed, amount.Currency())
+	if err != nil {
+		return nil, err
+	}
+	if fee, err = fee.Add(fixed); err != nil {
+		return nil, err
+	}
+	if fee.IsZero() {
+		return fee, nil
+	}
+	if covered, err := fee.LessThan(amount); err != nil {
+		return nil, err
+	} else if !covered {
+		return nil, fmt.Errorf("%w: fee %s on %s", ErrFeeExceedsAmount, fee, amount)
+	}
+	return fee, nil
+}
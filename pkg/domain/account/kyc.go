@@ -0,0 +1,40 @@
+package account
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/amirasaad/fintech/pkg/money"
+)
+
+// ErrKycLimitExceeded is returned when a deposit or withdrawal would exceed
+// the limits of the user's KYC tier.
+var ErrKycLimitExceeded = errors.New("KYC limit exceeded")
+
+// KYC limit windows reported in KycLimitError.
+const (
+	KycWindowPerTransaction = "per-transaction"
+	KycWindowDaily          = "daily"
+	KycWindowMonthly        = "monthly"
+)
+
+// KycLimitError names the tier and the limit that would be exceeded.
+// It unwraps to ErrKycLimitExceeded.
+type KycLimitError struct {
+	Tier   string
+	Window string       // KycWindowPerTransaction, KycWindowDaily or KycWindowMonthly
+	Limit  *money.Money // The configured limit
+}
+
+// Error implements the error interface.
+func (e *KycLimitError) Error() string {
+	return fmt.Sprintf(
+		"%s: %s limit of %s for KYC tier %q",
+		ErrKycLimitExceeded, e.Window, e.Limit, e.Tier,
+	)
+}
+
+// Unwrap returns ErrKycLimitExceeded.
+func (e *KycLimitError) Unwrap() error {
+	return ErrKycLimitExceeded
+}
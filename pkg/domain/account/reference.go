@@ -0,0 +1,117 @@
+package account
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidReference is returned when an account reference is malformed or
+// fails its check character.
+var ErrInvalidReference = errors.New("invalid account reference")
+
+const (
+	// referenceAlphabet is Crockford's base32 alphabet. It leaves out I, L, O
+	// and U, so a reference never contains characters that are easily
+	// confused with one another.
+	referenceAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+	// referenceRandom is the number of random symbols in a reference, 55 bits
+	// in all; one check symbol follows them.
+	referenceRandom = 11
+	referenceLength = referenceRandom + 1
+	referenceGroup  = 4
+
+	// maxReferencePrefix is the longest prefix a reference may have.
+	maxReferencePrefix = 8
+)
+
+// NewReference generates a human-friendly account reference such as
+// FT-7K3M-9QXD-2HAP: the optional prefix followed by eleven random base32
+// symbols and a check symbol, in groups of four. The prefix must be at most
+// eight letters and digits.
+func NewReference(prefix string) (string, error) {
+	prefix = strings.ToUpper(prefix)
+	if !validReferencePrefix(prefix) {
+		return "", fmt.Errorf("%w: prefix %q", ErrInvalidReference, prefix)
+	}
+	random := make([]byte, referenceRandom)
+	if _, err := rand.Read(random); err != nil {
+		return "", fmt.Errorf("failed to generate account reference: %w", err)
+	}
+	code := make([]byte, 0, referenceLength)
+	for _, b := range random {
+		code = append(code, referenceAlphabet[b%32])
+	}
+	code = append(code, referenceCheck(code))
+	return formatReference(prefix, string(code)), nil
+}
+
+// ParseReference validates an account reference entered by a user and
+// returns it in the canonical form NewReference generates. It is case
+// insensitive, ignores spaces and hyphens, reads I and L as 1 and O as 0,
+// and rejects references whose check symbol does not match.
+func ParseReference(s string) (string, error) {
+	compact := strings.ToUpper(strings.NewReplacer(" ", "", "-", "").Replace(s))
+	if len(compact) < referenceLength || len(compact) > referenceLength+maxReferencePrefix {
+		return "", fmt.Errorf("%w: %q", ErrInvalidReference, s)
+	}
+	prefix := compact[:len(compact)-referenceLength]
+	if !validReferencePrefix(prefix) {
+		return "", fmt.Errorf("%w: %q", ErrInvalidReference, s)
+	}
+	code := []byte(compact[len(prefix):])
+	for i, c := range code {
+		switch c {
+		case 'I', 'L':
+			code[i] = '1'
+		case 'O':
+			code[i] = '0'
+		}
+		if strings.IndexByte(referenceAlphabet, code[i]) < 0 {
+			return "", fmt.Errorf("%w: %q", ErrInvalidReference, s)
+		}
+	}
+	if referenceCheck(code[:referenceRandom]) != code[referenceRandom] {
+		return "", fmt.Errorf("%w: check symbol mismatch in %q", ErrInvalidReference, s)
+	}
+	return formatReference(prefix, string(code)), nil
+}
+
+// referenceCheck returns the Luhn mod 32 check symbol of code, which catches
+// any single mistyped symbol and most swaps of adjacent symbols.
+func referenceCheck(code []byte) byte {
+	const n = len(referenceAlphabet)
+	sum, factor := 0, 2
+	for i := len(code) - 1; i >= 0; i-- {
+		addend := factor * strings.IndexByte(referenceAlphabet, code[i])
+		sum += addend/n + addend%n
+		factor = 3 - factor
+	}
+	return referenceAlphabet[(n-sum%n)%n]
+}
+
+func validReferencePrefix(prefix string) bool {
+	if len(prefix) > maxReferencePrefix {
+		return false
+	}
+	for _, c := range prefix {
+		if (c < 'A' || c > 'Z') && (c < '0' || c > '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// formatReference joins prefix and code, splitting code in groups of four.
+func formatReference(prefix, code string) string {
+	parts := make([]string, 0, 4)
+	if prefix != "" {
+		parts = append(parts, prefix)
+	}
+	for i := 0; i < len(code); i += referenceGroup {
+		parts = append(parts, code[i:i+referenceGroup])
+	}
+	return strings.Join(parts, "-")
+}
@@ -0,0 +1,84 @@
+package account_test
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	domainaccount "github.com/amirasaad/fintech/pkg/domain/account"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewReference(t *testing.T) {
+	t.Parallel()
+	format := regexp.MustCompile(`^FT(-[0-9A-HJKMNP-TV-Z]{4}){3}$`)
+	seen := map[string]bool{}
+	for range 1000 {
+		ref, err := domainaccount.NewReference("ft")
+		require.NoError(t, err)
+		assert.Regexp(t, format, ref)
+		assert.False(t, seen[ref], "duplicate reference %s", ref)
+		seen[ref] = true
+
+		parsed, err := domainaccount.ParseReference(ref)
+		require.NoError(t, err)
+		assert.Equal(t, ref, parsed)
+	}
+
+	ref, err := domainaccount.NewReference("")
+	require.NoError(t, err)
+	assert.Len(t, ref, 14)
+
+	for _, prefix := range []string{"F-T", "TOOLONGPREFIX", "Ü"} {
+		_, err := domainaccount.NewReference(prefix)
+		assert.ErrorIs(t, err, domainaccount.ErrInvalidReference, prefix)
+	}
+}
+
+func TestParseReference(t *testing.T) {
+	t.Parallel()
+	ref, err := domainaccount.NewReference("FT")
+	require.NoError(t, err)
+	code := strings.ReplaceAll(strings.TrimPrefix(ref, "FT-"), "-", "")
+
+	t.Run("normalizes what users type", func(t *testing.T) {
+		for _, input := range []string{
+			ref,
+			strings.ToLower(ref),
+			"FT" + code,
+			"ft " + code[:4] + " " + code[4:8] + " " + code[8:],
+		} {
+			got, err := domainaccount.ParseReference(input)
+			require.NoError(t, err, input)
+			assert.Equal(t, ref, got)
+		}
+	})
+
+	t.Run("reads ambiguous characters as digits", func(t *testing.T) {
+		valid := "FT-0000-0000-111V"
+		_, err := domainaccount.ParseReference(valid)
+		require.NoError(t, err)
+		got, err := domainaccount.ParseReference("ft-oooo-OOOO-iLlv")
+		require.NoError(t, err)
+		assert.Equal(t, valid, got)
+	})
+
+	t.Run("rejects mistyped references", func(t *testing.T) {
+		_, err := domainaccount.ParseReference("FT-7K3M-9QXD-2HAB")
+		require.NoError(t, err)
+		for _, input := range []string{
+			"",
+			"FT-7K3M-9QXD-2HBB",           // mistyped symbol
+			"FT-K73M-9QXD-2HAB",           // swapped symbols
+			"FT-7K3M-9QXD-2HUB",           // U is not in the alphabet
+			"7K3M-9QXD",                   // too short
+			"TOOLONGFT-7K3M-9QXD-2HAB",    // prefix too long
+			"F_T-7K3M-9QXD-2HAB",          // invalid prefix
+			"FT-7K3M-9QXD-2HAB-7K3M-9QXD", // too long
+		} {
+			_, err := domainaccount.ParseReference(input)
+			assert.ErrorIs(t, err, domainaccount.ErrInvalidReference, input)
+		}
+	})
+}
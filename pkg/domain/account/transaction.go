@@ -1,6 +1,7 @@
 package account
 
 import (
+	"slices"
 	"time"
 
 	"github.com/amirasaad/fintech/pkg/money"
@@ -24,23 +25,80 @@ type TransactionStatus string
 
 // Transaction status constants define the lifecycle of a transaction.
 const (
+	// TransactionStatusCreated indicates that a deposit or withdrawal
+	// has been recorded and its payment not yet initiated.
+	TransactionStatusCreated TransactionStatus = "created"
 	// TransactionStatusPending indicates that a transaction
 	// has been initiated and is awaiting completion.
 	TransactionStatusPending TransactionStatus = "pending"
+	// TransactionStatusProcessed indicates that the payment provider
+	// has accepted the payment and it is awaiting settlement.
+	TransactionStatusProcessed TransactionStatus = "processed"
 	// TransactionStatusCompleted indicates that a transaction
 	// has been completed successfully.
 	TransactionStatusCompleted TransactionStatus = "completed"
 	// TransactionStatusFailed indicates that a transaction
 	// has been failed.
 	TransactionStatusFailed TransactionStatus = "failed"
+	// TransactionStatusCanceled indicates that the user canceled
+	// a deposit before paying it.
+	TransactionStatusCanceled TransactionStatus = "canceled"
 )
 
+// transactionTransitions lists the statuses each status may move to. A
+// transaction only moves forward, so an event that arrives after a later one
+// cannot move it back. Failed may still move to completed because a provider
+// can succeed a payment after reporting a failed attempt. Completed and
+// canceled are terminal.
+var transactionTransitions = map[TransactionStatus][]TransactionStatus{
+	TransactionStatusCreated: {
+		TransactionStatusPending,
+		TransactionStatusProcessed,
+		TransactionStatusCompleted,
+		TransactionStatusFailed,
+		TransactionStatusCanceled,
+	},
+	TransactionStatusPending: {
+		TransactionStatusProcessed,
+		TransactionStatusCompleted,
+		TransactionStatusFailed,
+		TransactionStatusCanceled,
+	},
+	TransactionStatusProcessed: {
+		TransactionStatusCompleted,
+		TransactionStatusFailed,
+	},
+	TransactionStatusFailed: {
+		TransactionStatusCompleted,
+	},
+	TransactionStatusCompleted: {},
+	TransactionStatusCanceled:  {},
+}
+
+// CanTransitionTo reports whether a transaction in status s may move to
+// status next. Moving to the same status is not a transition, and a status
+// outside the lifecycle may not move at all.
+func (s TransactionStatus) CanTransitionTo(next TransactionStatus) bool {
+	return slices.Contains(transactionTransitions[s], next)
+}
+
 // ExternalTarget represents the destination for an external withdrawal,
 // such as a bank account or wallet.
 type ExternalTarget struct {
 	BankAccountNumber     string
 	RoutingNumber         string
 	ExternalWalletAddress string
+	Network               string
+}
+
+// PaymentMethod describes how an incoming payment was funded, for receipts.
+// Brand and Funding are only set for cards; other methods such as bank debits
+// and wallets carry their Type and, where the provider reports one, Last4.
+type PaymentMethod struct {
+	Type    string // Provider method type, e.g. "card", "us_bank_account", "link"
+	Brand   string // Card brand, e.g. "visa"
+	Last4   string // Last four digits of the card or bank account
+	Funding string // Card funding: "credit", "debit", "prepaid" or "unknown"
 }
 
 // Transaction represents a financial transaction, capturing all details of a
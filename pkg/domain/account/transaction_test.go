@@ -0,0 +1,54 @@
+package account_test
+
+import (
+	"testing"
+
+	domainaccount "github.com/amirasaad/fintech/pkg/domain/account"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransactionStatus_CanTransitionTo(t *testing.T) {
+	t.Parallel()
+	const (
+		created   = domainaccount.TransactionStatusCreated
+		pending   = domainaccount.TransactionStatusPending
+		processed = domainaccount.TransactionStatusProcessed
+		completed = domainaccount.TransactionStatusCompleted
+		failed    = domainaccount.TransactionStatusFailed
+		canceled  = domainaccount.TransactionStatusCanceled
+	)
+	tests := []struct {
+		from, to domainaccount.TransactionStatus
+		want     bool
+	}{
+		{created, pending, true},
+		{created, processed, true},
+		{pending, processed, true},
+		{pending, completed, true},
+		{processed, completed, true},
+		{processed, failed, true},
+		{failed, completed, true},
+		{completed, pending, false},
+		{completed, processed, false},
+		{completed, failed, false},
+		{completed, completed, false},
+		{processed, pending, false},
+		{processed, created, false},
+		{failed, processed, false},
+		{failed, failed, false},
+		{created, canceled, true},
+		{pending, canceled, true},
+		{processed, canceled, false},
+		{completed, canceled, false},
+		{canceled, completed, false},
+		{canceled, pending, false},
+		{canceled, failed, false},
+		{"", completed, false},
+		{"refunded", completed, false},
+		{pending, "refunded", false},
+	}
+	for _, tt := range tests {
+		got := tt.from.CanTransitionTo(tt.to)
+		assert.Equal(t, tt.want, got, "%q -> %q", tt.from, tt.to)
+	}
+}
@@ -0,0 +1,66 @@
+package account
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Type is the kind of an account, which decides the rules it follows.
+type Type string
+
+const (
+	// TypeChecking is an everyday account, the default.
+	TypeChecking Type = "checking"
+	// TypeSavings is an account that earns interest and limits how often
+	// money can be withdrawn.
+	TypeSavings Type = "savings"
+	// TypeWallet is an account holding crypto currency.
+	TypeWallet Type = "wallet"
+)
+
+var (
+	// ErrInvalidAccountType is returned when an account is created with an
+	// unknown type.
+	ErrInvalidAccountType = errors.New("invalid account type")
+
+	// ErrWithdrawalCountExceeded is returned when a withdrawal or outgoing
+	// transfer would exceed the number of withdrawals the account type allows
+	// per month.
+	ErrWithdrawalCountExceeded = errors.New("monthly withdrawal count exceeded")
+)
+
+// ParseType returns the account type named s, or TypeChecking if s is empty.
+func ParseType(s string) (Type, error) {
+	switch t := Type(s); t {
+	case "":
+		return TypeChecking, nil
+	case TypeChecking, TypeSavings, TypeWallet:
+		return t, nil
+	}
+	return "", fmt.Errorf("%w: %q", ErrInvalidAccountType, s)
+}
+
+// String returns the name of the type.
+func (t Type) String() string {
+	return string(t)
+}
+
+// WithdrawalCountError names the account type and its monthly withdrawal
+// count limit. It unwraps to ErrWithdrawalCountExceeded.
+type WithdrawalCountError struct {
+	Type  Type
+	Limit int
+}
+
+// Error implements the error interface.
+func (e *WithdrawalCountError) Error() string {
+	return fmt.Sprintf(
+		"%s: %s accounts allow %d withdrawals per month",
+		ErrWithdrawalCountExceeded, e.Type, e.Limit,
+	)
+}
+
+// Unwrap returns ErrWithdrawalCountExceeded.
+func (e *WithdrawalCountError) Unwrap() error {
+	return ErrWithdrawalCountExceeded
+}
@@ -11,6 +11,17 @@ type DepositRequested struct {
 	Amount        *money.Money
 	Source        string
 	TransactionID uuid.UUID
+	// Optional per-request checkout redirects; empty uses the provider config.
+	SuccessURL string
+	CancelURL  string
+	// Description is the user's note on the deposit; empty if none.
+	Description string
+	// StatementDescriptor overrides the provider's default card statement
+	// text; empty uses the default.
+	StatementDescriptor string
+	// CaptureMethod is when the payment's funds are captured; empty captures
+	// them automatically.
+	CaptureMethod string
 }
 
 func (e DepositRequested) Type() string { return EventTypeDepositRequested.String() }
@@ -41,3 +52,13 @@ type DepositFailed struct {
 }
 
 func (e DepositFailed) Type() string { return EventTypeDepositFailed.String() }
+
+// DepositCanceled is emitted when the user cancels a deposit before paying
+// it.
+type DepositCanceled struct {
+	FlowEvent
+	TransactionID uuid.UUID
+	Amount        *money.Money
+}
+
+func (e DepositCanceled) Type() string { return EventTypeDepositCanceled.String() }
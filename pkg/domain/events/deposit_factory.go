@@ -42,6 +42,29 @@ func WithDepositSource(source string) DepositRequestedOpt {
 	}
 }
 
+// WithDepositRedirectURLs sets the per-request checkout success and cancel URLs
+func WithDepositRedirectURLs(successURL, cancelURL string) DepositRequestedOpt {
+	return func(e *DepositRequested) {
+		e.SuccessURL = successURL
+		e.CancelURL = cancelURL
+	}
+}
+
+// WithDepositDescription sets the user's note on the deposit
+func WithDepositDescription(description string) DepositRequestedOpt {
+	return func(e *DepositRequested) { e.Description = description }
+}
+
+// WithDepositStatementDescriptor sets the card statement text of the deposit
+func WithDepositStatementDescriptor(descriptor string) DepositRequestedOpt {
+	return func(e *DepositRequested) { e.StatementDescriptor = descriptor }
+}
+
+// WithDepositCaptureMethod sets when the deposit's payment is captured
+func WithDepositCaptureMethod(method string) DepositRequestedOpt {
+	return func(e *DepositRequested) { e.CaptureMethod = method }
+}
+
 // NewDepositRequested creates a new DepositRequested event with the given
 // parameters
 func NewDepositRequested(
@@ -133,3 +156,22 @@ func NewDepositFailed(
 
 	return df
 }
+
+// NewDepositCanceled creates a new DepositCanceled event.
+func NewDepositCanceled(
+	userID, accountID, transactionID uuid.UUID,
+	amount *money.Money,
+) *DepositCanceled {
+	return &DepositCanceled{
+		FlowEvent: FlowEvent{
+			ID:            uuid.New(),
+			FlowType:      "deposit",
+			UserID:        userID,
+			AccountID:     accountID,
+			CorrelationID: uuid.New(),
+			Timestamp:     time.Now(),
+		},
+		TransactionID: transactionID,
+		Amount:        amount,
+	}
+}
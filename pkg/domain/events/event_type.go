@@ -10,21 +10,34 @@ const (
 	EventTypePaymentProcessed EventType = "Payment.Processed"
 	EventTypePaymentCompleted EventType = "Payment.Completed"
 	EventTypePaymentFailed    EventType = "Payment.Failed"
+	EventTypePaymentReversed  EventType = "Payment.Reversed"
+
+	// Refund events
+	EventTypeRefundInitiated EventType = "Refund.Initiated"
+	EventTypeRefundCompleted EventType = "Refund.Completed"
+	EventTypeRefundFailed    EventType = "Refund.Failed"
 
 	// Deposit events
 	EventTypeDepositRequested         EventType = "Deposit.Requested"
 	EventTypeDepositCurrencyConverted EventType = "Deposit.CurrencyConverted"
 	EventTypeDepositValidated         EventType = "Deposit.Validated"
 	EventTypeDepositFailed            EventType = "Deposit.Failed"
+	EventTypeDepositReversed          EventType = "Deposit.Reversed"
+	EventTypeDepositCanceled          EventType = "Deposit.Canceled"
 
 	// Withdraw events
 	EventTypeWithdrawRequested         EventType = "Withdraw.Requested"
 	EventTypeWithdrawCurrencyConverted EventType = "Withdraw.CurrencyConverted"
 	EventTypeWithdrawValidated         EventType = "Withdraw.Validated"
 	EventTypeWithdrawFailed            EventType = "Withdraw.Failed"
+	EventTypeWithdrawPending           EventType = "Withdraw.Pending"
+	EventTypeWithdrawApproved          EventType = "Withdraw.Approved"
+	EventTypeWithdrawRejected          EventType = "Withdraw.Rejected"
 
 	// UserOnboardingCompleted event
 	EventTypeUserOnboardingCompleted EventType = "User.OnboardingCompleted"
+	// KycLimitReached event
+	EventTypeKycLimitReached EventType = "User.KycLimitReached"
 
 	// Transfer events
 	EventTypeTransferRequested         EventType = "Transfer.Requested"
@@ -33,6 +46,9 @@ const (
 	EventTypeTransferPaid              EventType = "Transfer.Paid"
 	EventTypeTransferCompleted         EventType = "Transfer.Completed"
 	EventTypeTransferFailed            EventType = "Transfer.Failed"
+	EventTypeTransferPending           EventType = "Transfer.Pending"
+	EventTypeTransferApproved          EventType = "Transfer.Approved"
+	EventTypeTransferRejected          EventType = "Transfer.Rejected"
 
 	// Fee events
 	EventTypeFeesCalculated EventType = "Fees.Calculated"
@@ -41,6 +57,15 @@ const (
 	EventTypeCurrencyConversionRequested EventType = "CurrencyConversion.Requested"
 	EventTypeCurrencyConverted           EventType = "CurrencyConversion.Converted"
 	EventTypeCurrencyConversionFailed    EventType = "CurrencyConversion.Failed"
+
+	// Event bus events
+	EventTypeDLQThresholdExceeded EventType = "EventBus.DLQThresholdExceeded"
+
+	// Ledger events
+	EventTypeLedgerDiscrepancyDetected EventType = "Ledger.DiscrepancyDetected"
+
+	// Fraud events
+	EventTypeSuspiciousActivityDetected EventType = "Fraud.SuspiciousActivityDetected"
 )
 
 // String returns the string representation of the event type.
@@ -0,0 +1,35 @@
+package events
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DLQThresholdExceeded is emitted by the event bus when the dead-letter queue
+// of an event type grows past the configured alert threshold.
+type DLQThresholdExceeded struct {
+	ID        uuid.UUID
+	EventType string // Event type whose DLQ crossed the threshold
+	DLQStream string // Name of the DLQ stream
+	Length    int64  // Number of messages in the DLQ
+	Threshold int64  // The configured threshold
+	Timestamp time.Time
+}
+
+func (e DLQThresholdExceeded) Type() string { return EventTypeDLQThresholdExceeded.String() }
+
+// NewDLQThresholdExceeded creates a new DLQThresholdExceeded event.
+func NewDLQThresholdExceeded(
+	eventType, dlqStream string,
+	length, threshold int64,
+) *DLQThresholdExceeded {
+	return &DLQThresholdExceeded{
+		ID:        uuid.New(),
+		EventType: eventType,
+		DLQStream: dlqStream,
+		Length:    length,
+		Threshold: threshold,
+		Timestamp: time.Now(),
+	}
+}
@@ -0,0 +1,42 @@
+package events
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Rules that flag suspicious account activity.
+const (
+	// SuspiciousRuleVelocity flags more transactions within the window than
+	// the account may make.
+	SuspiciousRuleVelocity = "velocity"
+	// SuspiciousRuleAmountSpike flags a transaction far larger than the
+	// account's usual amounts.
+	SuspiciousRuleAmountSpike = "amount_spike"
+)
+
+// SuspiciousActivityDetected is emitted when an account's activity crosses
+// an anomaly threshold, for fraud review. Amount and Baseline are in the
+// smallest unit of Currency.
+type SuspiciousActivityDetected struct {
+	ID        uuid.UUID
+	AccountID uuid.UUID
+	UserID    uuid.UUID
+	Rule      string // SuspiciousRuleVelocity or SuspiciousRuleAmountSpike
+	Reason    string
+	// TransactionCount is the number of transactions within Window,
+	// including the one that crossed the threshold.
+	TransactionCount int
+	Window           time.Duration
+	Amount           int64 // The transaction that crossed the threshold
+	Baseline         int64 // The account's usual amount; zero for velocity
+	Currency         string
+	TriggeredBy      string // Event type of the transaction
+	Frozen           bool   // Whether the account was frozen
+	Timestamp        time.Time
+}
+
+func (e SuspiciousActivityDetected) Type() string {
+	return EventTypeSuspiciousActivityDetected.String()
+}
@@ -0,0 +1,44 @@
+package events
+
+import (
+	"time"
+
+	"github.com/amirasaad/fintech/pkg/money"
+	"github.com/google/uuid"
+)
+
+// KycLimitReached is emitted when a deposit or withdrawal is rejected because
+// it would exceed the limits of the user's KYC tier, so the user can be
+// prompted to upgrade their verification.
+type KycLimitReached struct {
+	FlowEvent
+	Tier      string       // The user's KYC tier
+	Window    string       // per-transaction, daily or monthly
+	Limit     *money.Money // The limit that would be exceeded
+	Attempted *money.Money // The rejected amount
+}
+
+func (e KycLimitReached) Type() string { return EventTypeKycLimitReached.String() }
+
+// NewKycLimitReached creates a new KycLimitReached event. flowType is the
+// rejected operation (deposit or withdraw).
+func NewKycLimitReached(
+	userID, accountID uuid.UUID,
+	flowType, tier, window string,
+	limit, attempted *money.Money,
+) *KycLimitReached {
+	return &KycLimitReached{
+		FlowEvent: FlowEvent{
+			ID:            uuid.New(),
+			FlowType:      flowType,
+			UserID:        userID,
+			AccountID:     accountID,
+			CorrelationID: uuid.New(),
+			Timestamp:     time.Now(),
+		},
+		Tier:      tier,
+		Window:    window,
+		Limit:     limit,
+		Attempted: attempted,
+	}
+}
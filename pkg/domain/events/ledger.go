@@ -0,0 +1,44 @@
+package events
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// LedgerDiscrepancyDetected is emitted when the stored balance of an account
+// no longer matches the sum of its completed transactions. Amounts are in the
+// smallest unit of Currency.
+type LedgerDiscrepancyDetected struct {
+	ID            uuid.UUID
+	AccountID     uuid.UUID
+	Currency      string
+	Balance       int64  // The stored account balance
+	LedgerBalance int64  // The balance recomputed from the transactions
+	Difference    int64  // Balance minus LedgerBalance
+	TriggeredBy   string // Event type whose handling ran the check
+	Timestamp     time.Time
+}
+
+func (e LedgerDiscrepancyDetected) Type() string {
+	return EventTypeLedgerDiscrepancyDetected.String()
+}
+
+// NewLedgerDiscrepancyDetected creates a new LedgerDiscrepancyDetected event.
+func NewLedgerDiscrepancyDetected(
+	accountID uuid.UUID,
+	currency string,
+	balance, ledgerBalance int64,
+	triggeredBy string,
+) *LedgerDiscrepancyDetected {
+	return &LedgerDiscrepancyDetected{
+		ID:            uuid.New(),
+		AccountID:     accountID,
+		Currency:      currency,
+		Balance:       balance,
+		LedgerBalance: ledgerBalance,
+		Difference:    balance - ledgerBalance,
+		TriggeredBy:   triggeredBy,
+		Timestamp:     time.Now(),
+	}
+}
@@ -1,6 +1,7 @@
 package events
 
 import (
+	"github.com/amirasaad/fintech/pkg/domain/account"
 	"github.com/amirasaad/fintech/pkg/money"
 	"github.com/google/uuid"
 )
@@ -12,6 +13,13 @@ type PaymentInitiated struct {
 	TransactionID uuid.UUID
 	PaymentID     *string // Pointer to allow NULL in database
 	Status        string
+	// Optional per-request checkout redirects; empty uses the provider config.
+	SuccessURL string
+	CancelURL  string
+	// Optional card statement text; empty uses the provider config.
+	StatementDescriptor string
+	// Optional capture method; empty captures automatically.
+	CaptureMethod string
 }
 
 func (e PaymentInitiated) Type() string { return EventTypePaymentInitiated.String() }
@@ -67,6 +75,8 @@ func (e *PaymentProcessed) WithAmount(m *money.Money) *PaymentProcessed {
 // PaymentCompleted is an event for when a payment is completed.
 type PaymentCompleted struct {
 	PaymentInitiated
+	// PaymentMethod is how the payment was funded, if the provider reported it.
+	PaymentMethod *account.PaymentMethod
 }
 
 func (e PaymentCompleted) Type() string { return EventTypePaymentCompleted.String() }
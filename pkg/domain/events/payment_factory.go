@@ -1,6 +1,7 @@
 package events
 
 import (
+	"github.com/amirasaad/fintech/pkg/domain/account"
 	"time"
 
 	"github.com/google/uuid"
@@ -93,6 +94,11 @@ func WithPaymentID(paymentID *string) PaymentCompletedOpt {
 	}
 }
 
+// WithPaymentMethod sets how the payment was funded for the PaymentCompletedEvent
+func WithPaymentMethod(pm *account.PaymentMethod) PaymentCompletedOpt {
+	return func(e *PaymentCompleted) { e.PaymentMethod = pm }
+}
+
 // WithCorrelationID sets the correlation ID for the PaymentCompletedEvent
 func WithCorrelationID(correlationID uuid.UUID) PaymentCompletedOpt {
 	return func(e *PaymentCompleted) { e.CorrelationID = correlationID }
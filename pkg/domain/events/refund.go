@@ -0,0 +1,99 @@
+package events
+
+import (
+	"time"
+
+	"github.com/amirasaad/fintech/pkg/money"
+	"github.com/google/uuid"
+)
+
+// RefundInitiated is emitted when a deposit refund has been submitted to the
+// payment provider. The balance is only debited once the refund completes.
+type RefundInitiated struct {
+	FlowEvent
+	TransactionID         uuid.UUID    // The refund transaction
+	RefundedTransactionID uuid.UUID    // The deposit being refunded
+	RefundID              string       // The provider's refund ID
+	Amount                *money.Money // The refunded amount
+}
+
+func (e RefundInitiated) Type() string { return EventTypeRefundInitiated.String() }
+
+// RefundCompleted is emitted when the payment provider reports that a refund
+// succeeded.
+type RefundCompleted struct {
+	FlowEvent
+	TransactionID uuid.UUID    // The refund transaction
+	RefundID      string       // The provider's refund ID
+	Amount        *money.Money // The refunded amount
+}
+
+func (e RefundCompleted) Type() string { return EventTypeRefundCompleted.String() }
+
+// RefundFailed is emitted when the payment provider reports that a refund
+// failed or was canceled, possibly after it had already succeeded.
+type RefundFailed struct {
+	FlowEvent
+	TransactionID uuid.UUID    // The refund transaction
+	RefundID      string       // The provider's refund ID
+	Amount        *money.Money // The amount that was to be refunded
+	Reason        string
+}
+
+func (e RefundFailed) Type() string { return EventTypeRefundFailed.String() }
+
+// NewRefundInitiated creates a new RefundInitiated event.
+func NewRefundInitiated(
+	userID, accountID, transactionID, refundedTransactionID uuid.UUID,
+	refundID string,
+	amount *money.Money,
+) *RefundInitiated {
+	return &RefundInitiated{
+		FlowEvent:             newRefundFlowEvent(userID, accountID),
+		TransactionID:         transactionID,
+		RefundedTransactionID: refundedTransactionID,
+		RefundID:              refundID,
+		Amount:                amount,
+	}
+}
+
+// NewRefundCompleted creates a new RefundCompleted event.
+func NewRefundCompleted(
+	userID, accountID, transactionID uuid.UUID,
+	refundID string,
+	amount *money.Money,
+) *RefundCompleted {
+	return &RefundCompleted{
+		FlowEvent:     newRefundFlowEvent(userID, accountID),
+		TransactionID: transactionID,
+		RefundID:      refundID,
+		Amount:        amount,
+	}
+}
+
+// NewRefundFailed creates a new RefundFailed event.
+func NewRefundFailed(
+	userID, accountID, transactionID uuid.UUID,
+	refundID string,
+	amount *money.Money,
+	reason string,
+) *RefundFailed {
+	return &RefundFailed{
+		FlowEvent:     newRefundFlowEvent(userID, accountID),
+		TransactionID: transactionID,
+		RefundID:      refundID,
+		Amount:        amount,
+		Reason:        reason,
+	}
+}
+
+func newRefundFlowEvent(userID, accountID uuid.UUID) FlowEvent {
+	return FlowEvent{
+		ID:            uuid.New(),
+		FlowType:      "refund",
+		UserID:        userID,
+		AccountID:     accountID,
+		CorrelationID: uuid.New(),
+		Timestamp:     time.Now(),
+	}
+}
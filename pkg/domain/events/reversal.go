@@ -0,0 +1,86 @@
+package events
+
+import (
+	"time"
+
+	"github.com/amirasaad/fintech/pkg/money"
+	"github.com/google/uuid"
+)
+
+// PaymentReversed is emitted when the payment provider takes back the money
+// of a completed payment after the fact, e.g. when the payer disputes the
+// charge with their bank.
+type PaymentReversed struct {
+	FlowEvent
+	PaymentID  string       // The reversed payment, as recorded on the deposit
+	ReversalID string       // The provider's ID of the reversal, e.g. a dispute
+	Amount     *money.Money // The amount taken back
+	Reason     string
+}
+
+func (e PaymentReversed) Type() string { return EventTypePaymentReversed.String() }
+
+// DepositReversed is emitted once the amount of a reversed payment has been
+// debited from the account its deposit credited.
+type DepositReversed struct {
+	FlowEvent
+	TransactionID        uuid.UUID    // The reversal transaction
+	DepositTransactionID uuid.UUID    // The deposit being reversed
+	ReversalID           string       // The provider's ID of the reversal
+	Amount               *money.Money // The amount debited
+	// Shortfall is the part of Amount the account's balance and overdraft
+	// did not cover. The account owes it until later deposits recover it;
+	// zero if the reversal was covered.
+	Shortfall *money.Money
+	Reason    string
+}
+
+func (e DepositReversed) Type() string { return EventTypeDepositReversed.String() }
+
+// PendingRecovery reports whether the reversal left the account owing money.
+func (e DepositReversed) PendingRecovery() bool { return e.Shortfall.IsPositive() }
+
+// NewPaymentReversed creates a new PaymentReversed event. The user and
+// account are not known to the provider and are left unset.
+func NewPaymentReversed(
+	paymentID, reversalID string,
+	amount *money.Money,
+	reason string,
+) *PaymentReversed {
+	return &PaymentReversed{
+		FlowEvent:  newReversalFlowEvent(uuid.Nil, uuid.Nil),
+		PaymentID:  paymentID,
+		ReversalID: reversalID,
+		Amount:     amount,
+		Reason:     reason,
+	}
+}
+
+// NewDepositReversed creates a new DepositReversed event.
+func NewDepositReversed(
+	userID, accountID, transactionID, depositTransactionID uuid.UUID,
+	reversalID string,
+	amount, shortfall *money.Money,
+	reason string,
+) *DepositReversed {
+	return &DepositReversed{
+		FlowEvent:            newReversalFlowEvent(userID, accountID),
+		TransactionID:        transactionID,
+		DepositTransactionID: depositTransactionID,
+		ReversalID:           reversalID,
+		Amount:               amount,
+		Shortfall:            shortfall,
+		Reason:               reason,
+	}
+}
+
+func newReversalFlowEvent(userID, accountID uuid.UUID) FlowEvent {
+	return FlowEvent{
+		ID:            uuid.New(),
+		FlowType:      "reversal",
+		UserID:        userID,
+		AccountID:     accountID,
+		CorrelationID: uuid.New(),
+		Timestamp:     time.Now(),
+	}
+}
@@ -17,6 +17,9 @@ type TransferRequested struct {
 	Timestamp     time.Time
 	TransactionID uuid.UUID
 	Fee           int64
+	// Description is the user's note on the transfer, recorded on both the
+	// outgoing and the incoming transaction; empty if none.
+	Description string
 }
 
 func (e *TransferRequested) Type() string {
@@ -82,3 +85,36 @@ type TransferFailed struct {
 func (e TransferFailed) Type() string {
 	return EventTypeTransferFailed.String()
 }
+
+// TransferPending is emitted when a transfer that requires approval has
+// debited its source account. The destination is only credited once the
+// transfer is approved; rejecting it returns the money to the source.
+type TransferPending struct {
+	FlowEvent
+	PendingTransferID uuid.UUID
+	DestAccountID     uuid.UUID
+	TransactionID     uuid.UUID // The outgoing transaction on the source account
+	Amount            *money.Money
+	Description       string
+}
+
+func (e TransferPending) Type() string { return EventTypeTransferPending.String() }
+
+// TransferApproved is emitted when a pending transfer has been approved and
+// its destination account credited.
+type TransferApproved struct {
+	TransferPending
+	ApprovedBy uuid.UUID
+}
+
+func (e TransferApproved) Type() string { return EventTypeTransferApproved.String() }
+
+// TransferRejected is emitted when a pending transfer has been rejected and
+// its amount returned to the source account.
+type TransferRejected struct {
+	TransferPending
+	RejectedBy uuid.UUID
+	Reason     string
+}
+
+func (e TransferRejected) Type() string { return EventTypeTransferRejected.String() }
@@ -23,6 +23,11 @@ func WithTransferDestAccountID(id uuid.UUID) TransferRequestedOpt {
 	return func(e *TransferRequested) { e.DestAccountID = id }
 }
 
+// WithTransferDescription sets the user's note on the transfer
+func WithTransferDescription(description string) TransferRequestedOpt {
+	return func(e *TransferRequested) { e.Description = description }
+}
+
 func NewTransferRequested(
 	userID, accountID, correlationID uuid.UUID,
 	opts ...TransferRequestedOpt,
@@ -132,3 +137,51 @@ func NewTransferCompleted(
 	}
 	return tc
 }
+
+// --- TransferPending ---
+
+// NewTransferPending creates a new TransferPending event for the pending
+// transfer pendingID, whose amount left accountID in transaction txID.
+func NewTransferPending(
+	userID, accountID, destAccountID, pendingID, txID uuid.UUID,
+	amount *money.Money,
+	description string,
+) *TransferPending {
+	return &TransferPending{
+		FlowEvent: FlowEvent{
+			ID:            uuid.New(),
+			FlowType:      "transfer",
+			UserID:        userID,
+			AccountID:     accountID,
+			CorrelationID: pendingID,
+			Timestamp:     time.Now(),
+		},
+		PendingTransferID: pendingID,
+		DestAccountID:     destAccountID,
+		TransactionID:     txID,
+		Amount:            amount,
+		Description:       description,
+	}
+}
+
+// NewTransferApproved creates a new TransferApproved event for the pending
+// transfer tp, approved by approvedBy.
+func NewTransferApproved(tp *TransferPending, approvedBy uuid.UUID) *TransferApproved {
+	ta := &TransferApproved{TransferPending: *tp, ApprovedBy: approvedBy}
+	ta.ID = uuid.New()
+	ta.Timestamp = time.Now()
+	return ta
+}
+
+// NewTransferRejected creates a new TransferRejected event for the pending
+// transfer tp, rejected by rejectedBy.
+func NewTransferRejected(
+	tp *TransferPending,
+	rejectedBy uuid.UUID,
+	reason string,
+) *TransferRejected {
+	tr := &TransferRejected{TransferPending: *tp, RejectedBy: rejectedBy, Reason: reason}
+	tr.ID = uuid.New()
+	tr.Timestamp = time.Now()
+	return tr
+}
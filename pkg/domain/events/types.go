@@ -5,18 +5,24 @@ var EventTypes = map[EventType]func() Event{
 	EventTypePaymentInitiated: func() Event { return &PaymentInitiated{} },
 	EventTypePaymentCompleted: func() Event { return &PaymentCompleted{} },
 	EventTypePaymentProcessed: func() Event { return &PaymentProcessed{} },
+	EventTypePaymentReversed:  func() Event { return &PaymentReversed{} },
 	EventTypeDepositRequested: func() Event { return &DepositRequested{} },
 	EventTypeDepositCurrencyConverted: func() Event {
 		return &DepositCurrencyConverted{}
 	},
 	EventTypeDepositValidated:  func() Event { return &DepositValidated{} },
 	EventTypeDepositFailed:     func() Event { return &DepositFailed{} },
+	EventTypeDepositReversed:   func() Event { return &DepositReversed{} },
+	EventTypeDepositCanceled:   func() Event { return &DepositCanceled{} },
 	EventTypeWithdrawRequested: func() Event { return &WithdrawRequested{} },
 	EventTypeWithdrawCurrencyConverted: func() Event {
 		return &WithdrawCurrencyConverted{}
 	},
 	EventTypeWithdrawValidated: func() Event { return &WithdrawValidated{} },
 	EventTypeWithdrawFailed:    func() Event { return &WithdrawFailed{} },
+	EventTypeWithdrawPending:   func() Event { return &WithdrawPending{} },
+	EventTypeWithdrawApproved:  func() Event { return &WithdrawApproved{} },
+	EventTypeWithdrawRejected:  func() Event { return &WithdrawRejected{} },
 	EventTypeTransferRequested: func() Event { return &TransferRequested{} },
 	EventTypeTransferCurrencyConverted: func() Event {
 		return &TransferCurrencyConverted{}
@@ -24,6 +30,9 @@ var EventTypes = map[EventType]func() Event{
 	EventTypeTransferValidated: func() Event { return &TransferValidated{} },
 	EventTypeTransferCompleted: func() Event { return &TransferCompleted{} },
 	EventTypeTransferFailed:    func() Event { return &TransferFailed{} },
+	EventTypeTransferPending:   func() Event { return &TransferPending{} },
+	EventTypeTransferApproved:  func() Event { return &TransferApproved{} },
+	EventTypeTransferRejected:  func() Event { return &TransferRejected{} },
 	EventTypeCurrencyConversionRequested: func() Event {
 		return &CurrencyConversionRequested{}
 	},
@@ -33,4 +42,14 @@ var EventTypes = map[EventType]func() Event{
 	},
 
 	EventTypeFeesCalculated: func() Event { return &FeesCalculated{} },
+
+	EventTypeDLQThresholdExceeded: func() Event { return &DLQThresholdExceeded{} },
+
+	EventTypeLedgerDiscrepancyDetected: func() Event {
+		return &LedgerDiscrepancyDetected{}
+	},
+
+	EventTypeSuspiciousActivityDetected: func() Event {
+		return &SuspiciousActivityDetected{}
+	},
 }
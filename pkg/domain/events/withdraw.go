@@ -17,10 +17,18 @@ type WithdrawRequested struct {
 	Amount                *money.Money
 	BankAccountNumber     string
 	RoutingNumber         string
+	BIC                   string
 	ExternalWalletAddress string
+	Network               string
+	Description           string // User's note on the withdrawal; empty if none
 	Timestamp             time.Time
 	PaymentID             string // Added for payment provider integration
 	Fee                   int64
+	// HoldTransactionID is the hold transaction of a withdrawal approved
+	// after being held: it already debited the amount, in the account
+	// currency, and stands for the withdrawal instead of a new debit. Nil
+	// for withdrawals never held.
+	HoldTransactionID uuid.UUID
 }
 
 func (e *WithdrawRequested) Type() string {
@@ -70,6 +78,40 @@ type WithdrawFailed struct {
 
 func (e WithdrawFailed) Type() string { return EventTypeWithdrawFailed.String() }
 
+// WithdrawPending is emitted when a withdrawal above the approval threshold
+// has been held: its amount is debited from the account until
+// RequiredApprovals approvers approve it, which starts the withdrawal, or one
+// rejects it, which returns the amount. Approvers are notified from it.
+type WithdrawPending struct {
+	FlowEvent
+	PendingWithdrawalID uuid.UUID
+	TransactionID       uuid.UUID // The hold transaction on the account
+	Amount              *money.Money
+	Description         string
+	RequiredApprovals   int
+}
+
+func (e WithdrawPending) Type() string { return EventTypeWithdrawPending.String() }
+
+// WithdrawApproved is emitted when a held withdrawal has been approved by
+// the required number of approvers and started.
+type WithdrawApproved struct {
+	WithdrawPending
+	ApprovedBy []uuid.UUID
+}
+
+func (e WithdrawApproved) Type() string { return EventTypeWithdrawApproved.String() }
+
+// WithdrawRejected is emitted when a held withdrawal has been rejected and
+// its amount returned to the account.
+type WithdrawRejected struct {
+	WithdrawPending
+	RejectedBy uuid.UUID
+	Reason     string
+}
+
+func (e WithdrawRejected) Type() string { return EventTypeWithdrawRejected.String() }
+
 // UserOnboardingCompleted is emitted when a user completes the Stripe onboarding process.
 
 type UserOnboardingCompleted struct {
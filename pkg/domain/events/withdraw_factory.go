@@ -32,6 +32,37 @@ func WithWithdrawBankAccountNumber(accountNumber string) WithdrawRequestedOpt {
 	return func(e *WithdrawRequested) { e.BankAccountNumber = accountNumber }
 }
 
+// WithWithdrawRoutingNumber sets the routing number for the withdraw request
+func WithWithdrawRoutingNumber(routingNumber string) WithdrawRequestedOpt {
+	return func(e *WithdrawRequested) { e.RoutingNumber = routingNumber }
+}
+
+// WithWithdrawBIC sets the BIC of the bank holding the account for the
+// withdraw request
+func WithWithdrawBIC(bic string) WithdrawRequestedOpt {
+	return func(e *WithdrawRequested) { e.BIC = bic }
+}
+
+// WithWithdrawExternalWallet sets the external wallet address and its
+// network for the withdraw request
+func WithWithdrawExternalWallet(address, network string) WithdrawRequestedOpt {
+	return func(e *WithdrawRequested) {
+		e.ExternalWalletAddress = address
+		e.Network = network
+	}
+}
+
+// WithWithdrawDescription sets the user's note on the withdraw request
+func WithWithdrawDescription(description string) WithdrawRequestedOpt {
+	return func(e *WithdrawRequested) { e.Description = description }
+}
+
+// WithWithdrawHold starts the withdrawal from the hold transaction txID,
+// which already debited its amount.
+func WithWithdrawHold(txID uuid.UUID) WithdrawRequestedOpt {
+	return func(e *WithdrawRequested) { e.HoldTransactionID = txID }
+}
+
 func NewWithdrawRequested(
 	userID, accountID, correlationID uuid.UUID,
 	opts ...WithdrawRequestedOpt,
@@ -121,6 +152,53 @@ func NewWithdrawFailed(
 	return wf
 }
 
+// NewWithdrawPending creates a new WithdrawPending event for the held
+// withdrawal pendingID, whose amount is held by transaction txID.
+func NewWithdrawPending(
+	userID, accountID, pendingID, txID uuid.UUID,
+	amount *money.Money,
+	description string,
+	requiredApprovals int,
+) *WithdrawPending {
+	return &WithdrawPending{
+		FlowEvent: FlowEvent{
+			ID:            uuid.New(),
+			FlowType:      "withdraw",
+			UserID:        userID,
+			AccountID:     accountID,
+			CorrelationID: pendingID,
+			Timestamp:     time.Now(),
+		},
+		PendingWithdrawalID: pendingID,
+		TransactionID:       txID,
+		Amount:              amount,
+		Description:         description,
+		RequiredApprovals:   requiredApprovals,
+	}
+}
+
+// NewWithdrawApproved creates a new WithdrawApproved event for the held
+// withdrawal wp, approved by approvedBy.
+func NewWithdrawApproved(wp *WithdrawPending, approvedBy []uuid.UUID) *WithdrawApproved {
+	wa := &WithdrawApproved{WithdrawPending: *wp, ApprovedBy: approvedBy}
+	wa.ID = uuid.New()
+	wa.Timestamp = time.Now()
+	return wa
+}
+
+// NewWithdrawRejected creates a new WithdrawRejected event for the held
+// withdrawal wp, rejected by rejectedBy.
+func NewWithdrawRejected(
+	wp *WithdrawPending,
+	rejectedBy uuid.UUID,
+	reason string,
+) *WithdrawRejected {
+	wr := &WithdrawRejected{WithdrawPending: *wp, RejectedBy: rejectedBy, Reason: reason}
+	wr.ID = uuid.New()
+	wr.Timestamp = time.Now()
+	return wr
+}
+
 func NewUserOnboardingCompleted(userID uuid.UUID, stripeAccountID string) *UserOnboardingCompleted {
 	return &UserOnboardingCompleted{
 		FlowEvent: FlowEvent{
@@ -0,0 +1,21 @@
+package user
+
+// KYC tiers, from least to most verified. Each tier maps to its own deposit
+// and withdrawal limits (see config.Kyc).
+const (
+	// KycTierUnverified is the tier of users who have not completed any KYC.
+	KycTierUnverified = "unverified"
+	// KycTierBasic is the tier of users who completed basic identity checks.
+	KycTierBasic = "basic"
+	// KycTierVerified is the tier of fully verified users.
+	KycTierVerified = "verified"
+)
+
+// IsValidKycTier reports whether tier is a known KYC tier.
+func IsValidKycTier(tier string) bool {
+	switch tier {
+	case KycTierUnverified, KycTierBasic, KycTierVerified:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,12 @@
+package user
+
+// Authorization roles. Every user has RoleUser unless promoted.
+const (
+	// RoleUser is the role of regular customers.
+	RoleUser = "user"
+	// RoleAdmin is the role of operators allowed to use the /admin endpoints.
+	RoleAdmin = "admin"
+	// RoleApprover is the role of operators allowed to approve and reject
+	// withdrawals held for approval, alongside admins.
+	RoleApprover = "approver"
+)
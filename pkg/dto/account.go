@@ -3,34 +3,87 @@ package dto
 import (
 	"time"
 
+	"github.com/amirasaad/fintech/pkg/money"
 	"github.com/google/uuid"
 )
 
 // AccountRead is a read-optimized DTO for account queries, API responses, and reporting.
 type AccountRead struct {
 	ID        uuid.UUID // Unique account identifier
+	Reference string    // Human-friendly reference; empty for older accounts
 	UserID    uuid.UUID // User who owns the account
-	Balance   float64   // Account balance
-	Currency  string
-	Status    string    // Account status (e.g., active, closed)
-	CreatedAt time.Time // Timestamp of account creation
-	UpdatedAt time.Time // Timestamp of last update
+	// Balance is the account balance in the main currency unit.
+	//
+	// Deprecated: float64 cannot represent every amount exactly; use
+	// BalanceMoney.
+	Balance        float64
+	OverdraftLimit float64 // How far below zero Balance may go; zero means none
+	Currency       string
+	Status         string    // Account status (e.g., active, closed)
+	CreatedAt      time.Time // Timestamp of account creation
+	UpdatedAt      time.Time // Timestamp of last update
+	// BalanceMoney is the exact account balance.
+	BalanceMoney *money.Money
+	// AutoConvert is whether deposits in another currency are converted to
+	// Currency; when false they are rejected.
+	AutoConvert bool
+	// Type is the kind of account: checking, savings or wallet.
+	Type string
+	// InterestAccrued is the interest credited to the account so far, and
+	// InterestAccruedOn the last day (UTC) it was accrued for; nil if never.
+	InterestAccrued   *money.Money
+	InterestAccruedOn *time.Time
+	// InterestAPY is the annual percentage yield the account earns, e.g.
+	// 0.025 for 2.5%; zero if it earns no interest.
+	InterestAPY float64
+	// FeePolicy decides who bears the provider fee on deposits, absorb or
+	// pass_through; empty if the account follows the global policy.
+	FeePolicy string
 	// Add more fields as needed for queries
 }
 
+// AccountTotals aggregates the accounts matched by a query.
+type AccountTotals struct {
+	Currency string  // Currency of the accounts and of Balance
+	Count    int64   // Number of accounts
+	Balance  float64 // Sum of the account balances
+}
+
 // AccountCreate is a DTO for creating a new account.
 type AccountCreate struct {
-	ID       uuid.UUID
-	UserID   uuid.UUID // User who owns the account
-	Balance  int64     // Initial balance
-	Status   string    // Initial status
-	Currency string
+	ID        uuid.UUID
+	Reference string    // Human-friendly account reference
+	UserID    uuid.UUID // User who owns the account
+	Balance   int64     // Initial balance
+	Status    string    // Initial status
+	Currency  string
+	Type      string // Kind of account; checking if empty
 	// Add more fields as needed for creation
 }
 
 // AccountUpdate is a DTO for updating one or more fields of an account.
 type AccountUpdate struct {
-	Balance *int64  // Optional balance update
-	Status  *string // Optional status update
+	Balance        *int64  // Optional balance update
+	OverdraftLimit *int64  // Optional overdraft limit update, in the smallest unit
+	AutoConvert    *bool   // Optional auto-conversion setting update
+	Status         *string // Optional status update
+	// InterestAccrued and InterestAccruedOn record an interest accrual.
+	InterestAccrued   *int64
+	InterestAccruedOn *time.Time
+	// FeePolicy sets the account's fee absorption policy; empty clears it.
+	FeePolicy *string
 	// Add more fields as needed for partial updates
 }
+
+// LedgerCheckpoint is an account's balance and the ledger balance verified so
+// far. Amounts are in the smallest currency unit.
+type LedgerCheckpoint struct {
+	AccountID     uuid.UUID
+	Currency      string
+	Balance       int64
+	LedgerBalance int64
+	// VerifiedAt is the watermark of the last verification: LedgerBalance
+	// includes every transaction last updated before it. Zero if the ledger
+	// has never been verified.
+	VerifiedAt time.Time
+}
@@ -0,0 +1,63 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Payout destination types.
+const (
+	PayoutDestinationBankAccount    = "bank_account"
+	PayoutDestinationExternalWallet = "external_wallet"
+)
+
+// PayoutDestinationRead is a read-optimized DTO for a user's saved external
+// withdrawal target. It carries the full account details so withdrawals can
+// use it; API responses must only expose Last4.
+type PayoutDestinationRead struct {
+	ID                    uuid.UUID
+	UserID                uuid.UUID
+	Label                 string
+	BankAccountNumber     string
+	RoutingNumber         string
+	BIC                   string
+	ExternalWalletAddress string
+	Network               string // Wallet network, e.g. ethereum
+	CreatedAt             time.Time
+}
+
+// Type returns PayoutDestinationExternalWallet for wallet destinations and
+// PayoutDestinationBankAccount otherwise.
+func (d *PayoutDestinationRead) Type() string {
+	if d.ExternalWalletAddress != "" && d.BankAccountNumber == "" {
+		return PayoutDestinationExternalWallet
+	}
+	return PayoutDestinationBankAccount
+}
+
+// Last4 returns the last four characters of the bank account number or, for
+// wallets, of the wallet address. It is the only part of the destination that
+// may be shown to users.
+func (d *PayoutDestinationRead) Last4() string {
+	s := d.BankAccountNumber
+	if d.Type() == PayoutDestinationExternalWallet {
+		s = d.ExternalWalletAddress
+	}
+	if len(s) <= 4 {
+		return s
+	}
+	return s[len(s)-4:]
+}
+
+// PayoutDestinationCreate is a DTO for persisting a new payout destination.
+type PayoutDestinationCreate struct {
+	ID                    uuid.UUID
+	UserID                uuid.UUID
+	Label                 string
+	BankAccountNumber     string
+	RoutingNumber         string
+	BIC                   string
+	ExternalWalletAddress string
+	Network               string
+}
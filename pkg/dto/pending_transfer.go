@@ -0,0 +1,43 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Pending transfer statuses.
+const (
+	PendingTransferPending  = "pending"
+	PendingTransferApproved = "approved"
+	PendingTransferRejected = "rejected"
+)
+
+// PendingTransferRead is a read-optimized DTO for transfers awaiting approval.
+type PendingTransferRead struct {
+	ID            uuid.UUID // Pending transfer identifier
+	UserID        uuid.UUID // User who requested the transfer
+	FromAccountID uuid.UUID // Source account, debited when the transfer was requested
+	ToAccountID   uuid.UUID // Destination account, credited on approval
+	TransactionID uuid.UUID // Outgoing transaction on the source account
+	Amount        float64   // Transfer amount
+	Currency      string    // Transfer currency, shared by both accounts
+	Description   string    // User-supplied note; empty if none
+	Status        string    // pending, approved or rejected
+	DecidedBy     *uuid.UUID
+	DecidedAt     *time.Time
+	Reason        string // Rejection reason; empty if none
+	CreatedAt     time.Time
+}
+
+// PendingTransferCreate is a DTO for persisting a new pending transfer.
+type PendingTransferCreate struct {
+	ID            uuid.UUID
+	UserID        uuid.UUID
+	FromAccountID uuid.UUID
+	ToAccountID   uuid.UUID
+	TransactionID uuid.UUID
+	Amount        int64 // Amount in the smallest currency unit
+	Currency      string
+	Description   string
+}
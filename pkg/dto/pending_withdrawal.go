@@ -0,0 +1,73 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Pending withdrawal statuses.
+const (
+	PendingWithdrawalPending  = "pending"
+	PendingWithdrawalApproved = "approved"
+	PendingWithdrawalRejected = "rejected"
+)
+
+// PendingWithdrawalRead is a read-optimized DTO for withdrawals held for
+// approval.
+type PendingWithdrawalRead struct {
+	ID            uuid.UUID // Pending withdrawal identifier
+	UserID        uuid.UUID // User who requested the withdrawal
+	AccountID     uuid.UUID // Account the withdrawal is from
+	TransactionID uuid.UUID // Hold transaction on the account
+	Amount        float64   // Requested amount
+	Currency      string    // Requested currency
+	HoldAmount    float64   // Amount held, in HoldCurrency
+	HoldCurrency  string    // Account currency
+	Description   string    // User-supplied note; empty if none
+	// Payout target, passed on to the withdrawal once approved.
+	BankAccountNumber     string
+	RoutingNumber         string
+	BIC                   string
+	ExternalWalletAddress string
+	Network               string
+	RequiredApprovals     int
+	Approvals             []uuid.UUID // Approvers so far, in approval order
+	Status                string      // pending, approved or rejected
+	DecidedBy             *uuid.UUID  // Final approver, or the rejecter
+	DecidedAt             *time.Time
+	Reason                string // Rejection reason; empty if none
+	CreatedAt             time.Time
+}
+
+// PendingWithdrawalCreate is a DTO for persisting a new pending withdrawal.
+type PendingWithdrawalCreate struct {
+	ID                    uuid.UUID
+	UserID                uuid.UUID
+	AccountID             uuid.UUID
+	TransactionID         uuid.UUID
+	Amount                int64 // Amount in the smallest unit of Currency
+	Currency              string
+	HoldAmount            int64 // Amount in the smallest unit of HoldCurrency
+	HoldCurrency          string
+	Description           string
+	BankAccountNumber     string
+	RoutingNumber         string
+	BIC                   string
+	ExternalWalletAddress string
+	Network               string
+	RequiredApprovals     int
+}
+
+// Last4 returns the last four characters of the bank account number, or of
+// the wallet address for crypto withdrawals, for display.
+func (w *PendingWithdrawalRead) Last4() string {
+	s := w.BankAccountNumber
+	if s == "" {
+		s = w.ExternalWalletAddress
+	}
+	if len(s) <= 4 {
+		return s
+	}
+	return s[len(s)-4:]
+}
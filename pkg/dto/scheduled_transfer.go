@@ -0,0 +1,43 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Scheduled transfer statuses.
+const (
+	ScheduledTransferPending  = "pending"
+	ScheduledTransferExecuted = "executed"
+	ScheduledTransferCanceled = "canceled"
+	ScheduledTransferFailed   = "failed"
+)
+
+// ScheduledTransferRead is a read-optimized DTO for scheduled transfers.
+type ScheduledTransferRead struct {
+	ID            uuid.UUID // Scheduled transfer identifier, reused as the transfer event ID
+	UserID        uuid.UUID // User who scheduled the transfer
+	FromAccountID uuid.UUID // Source account
+	ToAccountID   uuid.UUID // Destination account
+	Amount        float64   // Transfer amount
+	Currency      string    // Transfer currency
+	Description   string    // User-supplied note; empty if none
+	Status        string    // pending, executed, canceled or failed
+	ExecuteAt     time.Time // When the transfer should be executed
+	ExecutedAt    *time.Time
+	FailureReason string
+	CreatedAt     time.Time
+}
+
+// ScheduledTransferCreate is a DTO for persisting a new scheduled transfer.
+type ScheduledTransferCreate struct {
+	ID            uuid.UUID
+	UserID        uuid.UUID
+	FromAccountID uuid.UUID
+	ToAccountID   uuid.UUID
+	Amount        int64 // Amount in the smallest currency unit
+	Currency      string
+	Description   string
+	ExecuteAt     time.Time
+}
@@ -3,23 +3,61 @@ package dto
 import (
 	"time"
 
+	"github.com/amirasaad/fintech/pkg/money"
 	"github.com/google/uuid"
 )
 
 // TransactionRead is a read-optimized DTO for transaction queries, API responses, and reporting.
 type TransactionRead struct {
-	ID              uuid.UUID // Unique transaction identifier
-	UserID          uuid.UUID // User who owns the transaction
-	AccountID       uuid.UUID // Account associated with the transaction
-	Amount          float64   // Transaction amount (use string for high precision if needed)
-	Currency        string    // Transaction currency
-	Balance         float64   // Account balance after transaction
+	ID        uuid.UUID // Unique transaction identifier
+	UserID    uuid.UUID // User who owns the transaction
+	AccountID uuid.UUID // Account associated with the transaction
+	// Amount is the transaction amount in the main currency unit.
+	//
+	// Deprecated: float64 cannot represent every amount exactly; use
+	// AmountMoney.
+	Amount   float64
+	Currency string // Transaction currency
+	// Balance is the account balance after the transaction in the main
+	// currency unit.
+	//
+	// Deprecated: float64 cannot represent every amount exactly; use
+	// BalanceMoney.
+	Balance         float64
 	Status          string    // Transaction status (e.g., completed, pending)
 	PaymentID       *string   // External payment provider ID
 	CreatedAt       time.Time // Timestamp of transaction creation
 	Fee             float64   // Total transaction fee
 	ConvertedAmount float64   // Converted amount after conversion
 	TargetCurrency  string    // Target currency after conversion
+	MoneySource     string    // Origin of funds (e.g., deposit, withdraw, transfer)
+	Description     string    // User-supplied note; empty if none
+	// PaymentMethod is how a deposit was funded; nil if unknown.
+	PaymentMethod *PaymentMethod
+	// RefundedTransactionID is the deposit a refund transaction refunds.
+	RefundedTransactionID *uuid.UUID
+	// ExternalBankAccount is the bank account a withdrawal pays out to; nil
+	// for other transactions and for withdrawals to wallets.
+	ExternalBankAccount *BankAccount
+	// AmountMoney is the exact transaction amount.
+	AmountMoney *money.Money
+	// BalanceMoney is the exact account balance after the transaction.
+	BalanceMoney *money.Money
+	// ExternalReference identifies a transaction imported from another
+	// system; empty otherwise.
+	ExternalReference string
+	// ExternalTargetMasked is the last four characters of the bank account
+	// or wallet a withdrawal pays out to, e.g. ****6789; empty otherwise.
+	ExternalTargetMasked string
+	// PayoutEstimatedArrival is when a withdrawal's payout is estimated to
+	// arrive; nil until the payout is initiated.
+	PayoutEstimatedArrival *time.Time
+	// PlatformFee is the provider fee the platform absorbed instead of
+	// deducting it from the account; it is not part of Fee. FeePolicy is
+	// the policy applied to the provider fee, absorb or pass_through; empty
+	// if none was charged.
+	PlatformFee float64
+	FeePolicy   string
 	// Add audit, denormalized, or computed fields as needed
 }
 
@@ -37,6 +75,20 @@ type TransactionCreate struct {
 	ExternalTargetMasked string
 	TargetCurrency       string
 	Fee                  int64 // Total transaction fee
+	// Description is the user's note, normalized by
+	// account.NormalizeDescription.
+	Description string
+	// RefundedTransactionID links a refund to the deposit it refunds.
+	RefundedTransactionID *uuid.UUID
+	// ExternalBankAccount is the bank account a withdrawal pays out to.
+	ExternalBankAccount *BankAccount
+	// Balance is the account balance after the transaction, if known.
+	Balance int64
+	// ExternalReference identifies a transaction imported from another
+	// system, unique per account.
+	ExternalReference string
+	// CreatedAt backdates an imported transaction; zero means now.
+	CreatedAt time.Time
 	// Add more fields as needed for creation
 }
 
@@ -54,5 +106,43 @@ type TransactionUpdate struct {
 	ConversionRate   *float64
 	TargetCurrency   *string
 	// Add more fields as needed for partial updates
-	Fee *int64
+	Fee           *int64
+	PaymentMethod *PaymentMethod // Optional payment method details update
+	// PayoutEstimatedArrival is when a withdrawal's payout is estimated to
+	// arrive.
+	PayoutEstimatedArrival *time.Time
+	// PlatformFee is the total provider fee absorbed by the platform, in the
+	// smallest unit, and FeePolicy the policy applied to the provider fee.
+	PlatformFee *int64
+	FeePolicy   *string
+}
+
+// PaymentMethod describes how a deposit was funded. Brand and Funding are
+// only set for cards.
+type PaymentMethod struct {
+	Type    string // Provider method type, e.g. card, us_bank_account, link
+	Brand   string // Card brand, e.g. visa
+	Last4   string // Last four digits of the card or bank account
+	Funding string // Card funding: credit, debit, prepaid or unknown
+}
+
+// BankAccount is the external bank account a withdrawal pays out to, kept in
+// full so payouts can be exported to the bank.
+type BankAccount struct {
+	Number        string // Domestic account number or IBAN
+	RoutingNumber string // ABA routing number of a domestic account
+	BIC           string // SWIFT/BIC of the bank holding the account
+}
+
+// LedgerEntry is a transaction as seen by ledger verification. Amounts are in
+// the smallest currency unit.
+type LedgerEntry struct {
+	TransactionID uuid.UUID
+	Status        string
+	Amount        int64
+	Fee           int64
+	// Counted is what the transaction contributed to its account's verified
+	// ledger balance when it was last verified.
+	Counted   int64
+	UpdatedAt time.Time
 }
@@ -0,0 +1,29 @@
+package dto
+
+import "time"
+
+// Types of imported transactions. Deposits and incoming transfers credit the
+// account; withdrawals and outgoing transfers debit it.
+const (
+	TransactionImportDeposit     = "deposit"
+	TransactionImportWithdraw    = "withdraw"
+	TransactionImportTransferIn  = "transfer_in"
+	TransactionImportTransferOut = "transfer_out"
+)
+
+// TransactionImport is a historical transaction to import into an account.
+type TransactionImport struct {
+	Reference string    // Unique per account; identifies the row on re-import
+	Type      string    // deposit, withdraw, transfer_in or transfer_out
+	Amount    float64   // Positive amount in the main currency unit
+	Currency  string    // Must be the account currency
+	Timestamp time.Time // When the transaction happened
+	Memo      string    // Stored as the transaction description
+}
+
+// TransactionImportResult reports the outcome of an import.
+type TransactionImportResult struct {
+	Imported []*TransactionRead // Transactions created, in import order
+	Skipped  []string           // References already imported into the account
+	Balance  float64            // Account balance after the import
+}
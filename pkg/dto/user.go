@@ -22,6 +22,11 @@ type UserUpdate struct {
 	Password               *string `json:"password,omitempty" validate:"omitempty,min=6"`
 	Names                  *string `json:"names,omitempty"`
 	StripeConnectAccountID *string `json:"stripe_connect_account_id,omitempty"`
+	// StripeCustomerID is set by the Stripe provider only, never from request
+	// bodies.
+	StripeCustomerID *string `json:"-"`
+	// KycTier is set by verification flows only, never from request bodies.
+	KycTier *string `json:"-"`
 }
 
 // UserRead represents a read-optimized view of a user.
@@ -32,6 +37,9 @@ type UserRead struct {
 	Email                  string    `json:"email"`
 	Names                  string    `json:"names,omitempty"`
 	StripeConnectAccountID string    `json:"stripe_connect_account_id,omitempty"`
+	StripeCustomerID       string    `json:"-"`
+	KycTier                string    `json:"kyc_tier,omitempty"`
+	Role                   string    `json:"role,omitempty"`
 	CreatedAt              time.Time `json:"created_at"`
 	UpdatedAt              time.Time `json:"updated_at"`
 }
@@ -0,0 +1,201 @@
+// Package errcode is the registry of domain sentinel errors, mapping each to
+// the HTTP status and stable error code an API responds with.
+package errcode
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+
+	"github.com/amirasaad/fintech/pkg/commandbus"
+	"github.com/amirasaad/fintech/pkg/commands"
+	"github.com/amirasaad/fintech/pkg/currency"
+	"github.com/amirasaad/fintech/pkg/domain"
+	"github.com/amirasaad/fintech/pkg/domain/account"
+	"github.com/amirasaad/fintech/pkg/domain/user"
+	"github.com/amirasaad/fintech/pkg/eventbus"
+	"github.com/amirasaad/fintech/pkg/iso20022"
+	"github.com/amirasaad/fintech/pkg/money"
+	"github.com/amirasaad/fintech/pkg/provider/exchange"
+	"github.com/amirasaad/fintech/pkg/provider/payment"
+	"github.com/amirasaad/fintech/pkg/registry"
+	"github.com/amirasaad/fintech/pkg/service/featureflag"
+	"github.com/amirasaad/fintech/pkg/service/maintenance"
+	"github.com/amirasaad/fintech/pkg/service/payoutexport"
+	"github.com/amirasaad/fintech/pkg/service/reconciliation"
+	"github.com/amirasaad/fintech/pkg/validation"
+)
+
+// Internal is the code of errors that are not registered.
+const Internal = "internal_error"
+
+// Entry maps a sentinel error to its HTTP status and error code.
+type Entry struct {
+	Err    error
+	Status int
+	// Code identifies the error to clients. Codes are part of the API and
+	// must not change once published.
+	Code string
+}
+
+var (
+	mu sync.RWMutex
+	// entries is matched in order, so errors that wrap more general ones,
+	// such as domain.ErrNotFound, must come before them.
+	entries = []Entry{
+		{domain.ErrAlreadyExists, http.StatusUnprocessableEntity, "already_exists"},
+		{commandbus.ErrInvalidCommand, http.StatusBadRequest, "invalid_command"},
+		{commandbus.ErrUnauthenticated, http.StatusUnauthorized, "unauthenticated"},
+		{commandbus.ErrIdempotencyKeyReused, http.StatusUnprocessableEntity, "idempotency_key_reused"},
+		{commandbus.ErrIdempotencyKeyInUse, http.StatusConflict, "idempotency_key_in_use"},
+		{commands.ErrMissingAccountID, http.StatusBadRequest, "missing_account_id"},
+		{commands.ErrNonPositiveAmount, http.StatusBadRequest, "non_positive_amount"},
+
+		{account.ErrAccountNotFound, http.StatusNotFound, "account_not_found"},
+		{account.ErrInvalidReference, http.StatusBadRequest, "invalid_account_reference"},
+		{account.ErrDepositAmountExceedsMaxSafeInt, http.StatusBadRequest, "deposit_amount_too_large"},
+		{account.ErrTransactionAmountMustBePositive, http.StatusBadRequest, "non_positive_amount"},
+		{account.ErrInsufficientFunds, http.StatusUnprocessableEntity, "insufficient_funds"},
+		{account.ErrOverdraftLimitExceeded, http.StatusUnprocessableEntity, "overdraft_limit_exceeded"},
+		{account.ErrNegativeOverdraftLimit, http.StatusBadRequest, "negative_overdraft_limit"},
+		{account.ErrInvalidFeeAbsorption, http.StatusBadRequest, "invalid_fee_absorption"},
+		{account.ErrInvalidFeePolicy, http.StatusBadRequest, "invalid_fee_policy"},
+		{account.ErrFeeExceedsAmount, http.StatusUnprocessableEntity, "fee_exceeds_amount"},
+		{account.ErrKycLimitExceeded, http.StatusForbidden, "kyc_limit_exceeded"},
+		{account.ErrWithdrawalCountExceeded, http.StatusForbidden, "withdrawal_count_exceeded"},
+		{account.ErrInvalidAccountType, http.StatusBadRequest, "invalid_account_type"},
+		{account.ErrAccountNotActive, http.StatusUnprocessableEntity, "account_not_active"},
+		{account.ErrNotOwner, http.StatusForbidden, "not_account_owner"},
+		{account.ErrCannotTransferToSameAccount, http.StatusBadRequest, "same_account_transfer"},
+		{account.ErrScheduleInPast, http.StatusBadRequest, "schedule_in_past"},
+		{account.ErrScheduledTransferNotPending, http.StatusConflict, "scheduled_transfer_not_pending"},
+		{account.ErrPendingTransferNotPending, http.StatusConflict, "transfer_not_pending"},
+		{account.ErrApprovalTransferScheduled, http.StatusBadRequest, "approval_transfer_scheduled"},
+		{account.ErrPendingWithdrawalNotPending, http.StatusConflict, "withdrawal_not_pending"},
+		{account.ErrSelfApproval, http.StatusForbidden, "self_approval"},
+		{account.ErrAlreadyApproved, http.StatusConflict, "already_approved"},
+		{account.ErrCurrencyMismatch, http.StatusUnprocessableEntity, "currency_mismatch"},
+		{account.ErrCurrencyDeactivated, http.StatusUnprocessableEntity, "currency_deactivated"},
+		{account.ErrTransactionNotFound, http.StatusNotFound, "transaction_not_found"},
+		{
+			account.ErrTransactionNotRefundable,
+			http.StatusUnprocessableEntity,
+			"transaction_not_refundable",
+		},
+		{account.ErrRefundExceedsDeposit, http.StatusUnprocessableEntity, "refund_exceeds_deposit"},
+		{account.ErrDepositNotCancellable, http.StatusConflict, "deposit_not_cancellable"},
+		{account.ErrDepositNotCapturable, http.StatusConflict, "deposit_not_capturable"},
+		{
+			account.ErrCaptureExceedsAuthorized,
+			http.StatusUnprocessableEntity,
+			"capture_exceeds_authorized",
+		},
+		{account.ErrReceiptUnavailable, http.StatusConflict, "receipt_unavailable"},
+		{account.ErrReceiptNotFound, http.StatusNotFound, "receipt_not_found"},
+		{account.ErrReceiptExpired, http.StatusGone, "receipt_expired"},
+		{account.ErrReprocessNotSupported, http.StatusConflict, "reprocess_not_supported"},
+		{account.ErrAccountBusy, http.StatusConflict, "account_busy"},
+		{account.ErrInvalidDescription, http.StatusBadRequest, "invalid_description"},
+
+		{money.ErrInvalidCurrency, http.StatusBadRequest, "invalid_currency"},
+		{money.ErrAmountExceedsMaxSafeInt, http.StatusBadRequest, "amount_too_large"},
+		{money.ErrInvalidAmountPrecision, http.StatusBadRequest, "invalid_amount_precision"},
+		{money.ErrMismatchedCurrencies, http.StatusUnprocessableEntity, "currency_mismatch"},
+		{currency.ErrUnsupported, http.StatusBadRequest, "unsupported_currency"},
+		{currency.ErrCurrencyNotFound, http.StatusNotFound, "currency_not_found"},
+		{currency.ErrCurrencyExists, http.StatusConflict, "currency_exists"},
+		{validation.ErrInvalidRoutingNumber, http.StatusBadRequest, "invalid_routing_number"},
+		{validation.ErrInvalidIBAN, http.StatusBadRequest, "invalid_iban"},
+		{validation.ErrInvalidBankAccountNumber, http.StatusBadRequest, "invalid_bank_account_number"},
+		{validation.ErrInvalidBIC, http.StatusBadRequest, "invalid_bic"},
+		{validation.ErrInvalidStatementDescriptor, http.StatusBadRequest, "invalid_statement_descriptor"},
+
+		{exchange.ErrUnsupportedPair, http.StatusUnprocessableEntity, "unsupported_currency_pair"},
+		{exchange.ErrProviderUnavailable, http.StatusServiceUnavailable, "rate_provider_unavailable"},
+		{
+			exchange.ErrRateProviderQuotaExhausted,
+			http.StatusServiceUnavailable,
+			"rate_provider_quota_exhausted",
+		},
+		{exchange.ErrConversionNotAllowed, http.StatusUnprocessableEntity, "conversion_not_allowed"},
+		{payment.ErrRedirectURLNotAllowed, http.StatusBadRequest, "redirect_url_not_allowed"},
+		{payment.ErrInvalidCaptureMethod, http.StatusBadRequest, "invalid_capture_method"},
+		{payment.ErrRefundsNotSupported, http.StatusNotImplemented, "refunds_not_supported"},
+		{
+			payment.ErrCheckoutCancelNotSupported,
+			http.StatusNotImplemented,
+			"checkout_cancel_not_supported",
+		},
+		{payment.ErrCaptureNotSupported, http.StatusNotImplemented, "capture_not_supported"},
+		{payment.ErrPaymentNotCapturable, http.StatusConflict, "payment_not_capturable"},
+		{payment.ErrPayoutNotFound, http.StatusNotFound, "payout_not_found"},
+		{payment.ErrPayoutNotCancellable, http.StatusConflict, "payout_not_cancellable"},
+		{iso20022.ErrIncompleteTransfer, http.StatusUnprocessableEntity, "incomplete_transfer"},
+		{payoutexport.ErrNotConfigured, http.StatusNotImplemented, "payout_export_not_configured"},
+		{reconciliation.ErrInvalidRange, http.StatusBadRequest, "invalid_date_range"},
+		{registry.ErrNotFound, http.StatusNotFound, "not_found"},
+
+		{context.DeadlineExceeded, http.StatusGatewayTimeout, "timeout"},
+		{eventbus.ErrNoConsumer, http.StatusNotFound, "consumer_not_found"},
+		{featureflag.ErrFlagNotFound, http.StatusNotFound, "feature_flag_not_found"},
+		{featureflag.ErrInvalidFlag, http.StatusBadRequest, "invalid_feature_flag"},
+		{maintenance.ErrUnderMaintenance, http.StatusServiceUnavailable, "under_maintenance"},
+
+		{user.ErrUserNotFound, http.StatusNotFound, "user_not_found"},
+		{user.ErrUserUnauthorized, http.StatusUnauthorized, "unauthorized"},
+		{domain.ErrStripeOnboardingIncomplete, http.StatusForbidden, "stripe_onboarding_incomplete"},
+
+		{domain.ErrNotFound, http.StatusNotFound, "not_found"},
+		{domain.ErrValidation, http.StatusBadRequest, "validation_error"},
+		{domain.ErrUnauthorized, http.StatusUnauthorized, "unauthorized"},
+		{domain.ErrForbidden, http.StatusForbidden, "forbidden"},
+	}
+)
+
+// Register maps err to status and code. It is matched before the errors
+// registered so far, so it can refine an error those already cover.
+func Register(err error, status int, code string) {
+	mu.Lock()
+	defer mu.Unlock()
+	entries = append([]Entry{{err, status, code}}, entries...)
+}
+
+// Lookup returns the entry of the first registered error err matches with
+// errors.Is, or false if there is none.
+func Lookup(err error) (Entry, bool) {
+	if err == nil {
+		return Entry{}, false
+	}
+	mu.RLock()
+	defer mu.RUnlock()
+	for _, e := range entries {
+		if errors.Is(err, e.Err) {
+			return e, true
+		}
+	}
+	return Entry{}, false
+}
+
+// Status returns the HTTP status of err, or 500 if it is not registered.
+func Status(err error) int {
+	if e, ok := Lookup(err); ok {
+		return e.Status
+	}
+	return http.StatusInternalServerError
+}
+
+// Code returns the error code of err, or Internal if it is not registered.
+func Code(err error) string {
+	if e, ok := Lookup(err); ok {
+		return e.Code
+	}
+	return Internal
+}
+
+// Entries returns the registered errors in the order they are matched.
+func Entries() []Entry {
+	mu.RLock()
+	defer mu.RUnlock()
+	return append([]Entry(nil), entries...)
+}
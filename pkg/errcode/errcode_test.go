@@ -0,0 +1,175 @@
+package errcode_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"testing"
+
+	"github.com/amirasaad/fintech/pkg/commandbus"
+	"github.com/amirasaad/fintech/pkg/commands"
+	"github.com/amirasaad/fintech/pkg/currency"
+	"github.com/amirasaad/fintech/pkg/domain"
+	"github.com/amirasaad/fintech/pkg/domain/account"
+	"github.com/amirasaad/fintech/pkg/domain/user"
+	"github.com/amirasaad/fintech/pkg/errcode"
+	"github.com/amirasaad/fintech/pkg/eventbus"
+	"github.com/amirasaad/fintech/pkg/iso20022"
+	"github.com/amirasaad/fintech/pkg/money"
+	"github.com/amirasaad/fintech/pkg/provider/exchange"
+	"github.com/amirasaad/fintech/pkg/provider/payment"
+	"github.com/amirasaad/fintech/pkg/registry"
+	"github.com/amirasaad/fintech/pkg/service/featureflag"
+	"github.com/amirasaad/fintech/pkg/service/maintenance"
+	"github.com/amirasaad/fintech/pkg/service/payoutexport"
+	"github.com/amirasaad/fintech/pkg/service/reconciliation"
+	"github.com/amirasaad/fintech/pkg/validation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLookup(t *testing.T) {
+	tests := []struct {
+		err    error
+		status int
+		code   string
+	}{
+		{domain.ErrAlreadyExists, http.StatusUnprocessableEntity, "already_exists"},
+		{domain.ErrNotFound, http.StatusNotFound, "not_found"},
+		{domain.ErrValidation, http.StatusBadRequest, "validation_error"},
+		{domain.ErrUnauthorized, http.StatusUnauthorized, "unauthorized"},
+		{domain.ErrForbidden, http.StatusForbidden, "forbidden"},
+		{domain.ErrStripeOnboardingIncomplete, http.StatusForbidden, "stripe_onboarding_incomplete"},
+		{commandbus.ErrInvalidCommand, http.StatusBadRequest, "invalid_command"},
+		{commandbus.ErrUnauthenticated, http.StatusUnauthorized, "unauthenticated"},
+		{commandbus.ErrIdempotencyKeyReused, http.StatusUnprocessableEntity, "idempotency_key_reused"},
+		{commandbus.ErrIdempotencyKeyInUse, http.StatusConflict, "idempotency_key_in_use"},
+		{commands.ErrMissingAccountID, http.StatusBadRequest, "missing_account_id"},
+		{commands.ErrNonPositiveAmount, http.StatusBadRequest, "non_positive_amount"},
+		{account.ErrAccountNotFound, http.StatusNotFound, "account_not_found"},
+		{account.ErrInvalidReference, http.StatusBadRequest, "invalid_account_reference"},
+		{account.ErrDepositAmountExceedsMaxSafeInt, http.StatusBadRequest, "deposit_amount_too_large"},
+		{account.ErrTransactionAmountMustBePositive, http.StatusBadRequest, "non_positive_amount"},
+		{account.ErrInsufficientFunds, http.StatusUnprocessableEntity, "insufficient_funds"},
+		{account.ErrOverdraftLimitExceeded, http.StatusUnprocessableEntity, "overdraft_limit_exceeded"},
+		{account.ErrNegativeOverdraftLimit, http.StatusBadRequest, "negative_overdraft_limit"},
+		{account.ErrInvalidFeeAbsorption, http.StatusBadRequest, "invalid_fee_absorption"},
+		{account.ErrInvalidFeePolicy, http.StatusBadRequest, "invalid_fee_policy"},
+		{account.ErrFeeExceedsAmount, http.StatusUnprocessableEntity, "fee_exceeds_amount"},
+		{account.ErrKycLimitExceeded, http.StatusForbidden, "kyc_limit_exceeded"},
+		{account.ErrWithdrawalCountExceeded, http.StatusForbidden, "withdrawal_count_exceeded"},
+		{account.ErrInvalidAccountType, http.StatusBadRequest, "invalid_account_type"},
+		{account.ErrAccountNotActive, http.StatusUnprocessableEntity, "account_not_active"},
+		{account.ErrNotOwner, http.StatusForbidden, "not_account_owner"},
+		{account.ErrCannotTransferToSameAccount, http.StatusBadRequest, "same_account_transfer"},
+		{account.ErrScheduleInPast, http.StatusBadRequest, "schedule_in_past"},
+		{account.ErrScheduledTransferNotPending, http.StatusConflict, "scheduled_transfer_not_pending"},
+		{account.ErrPendingTransferNotPending, http.StatusConflict, "transfer_not_pending"},
+		{account.ErrApprovalTransferScheduled, http.StatusBadRequest, "approval_transfer_scheduled"},
+		{account.ErrPendingWithdrawalNotPending, http.StatusConflict, "withdrawal_not_pending"},
+		{account.ErrSelfApproval, http.StatusForbidden, "self_approval"},
+		{account.ErrAlreadyApproved, http.StatusConflict, "already_approved"},
+		{account.ErrCurrencyMismatch, http.StatusUnprocessableEntity, "currency_mismatch"},
+		{account.ErrCurrencyDeactivated, http.StatusUnprocessableEntity, "currency_deactivated"},
+		{account.ErrTransactionNotFound, http.StatusNotFound, "transaction_not_found"},
+		{account.ErrTransactionNotRefundable, http.StatusUnprocessableEntity, "transaction_not_refundable"},
+		{account.ErrRefundExceedsDeposit, http.StatusUnprocessableEntity, "refund_exceeds_deposit"},
+		{account.ErrDepositNotCancellable, http.StatusConflict, "deposit_not_cancellable"},
+		{account.ErrDepositNotCapturable, http.StatusConflict, "deposit_not_capturable"},
+		{account.ErrCaptureExceedsAuthorized, http.StatusUnprocessableEntity, "capture_exceeds_authorized"},
+		{account.ErrReceiptUnavailable, http.StatusConflict, "receipt_unavailable"},
+		{account.ErrReceiptNotFound, http.StatusNotFound, "receipt_not_found"},
+		{account.ErrReceiptExpired, http.StatusGone, "receipt_expired"},
+		{account.ErrReprocessNotSupported, http.StatusConflict, "reprocess_not_supported"},
+		{account.ErrAccountBusy, http.StatusConflict, "account_busy"},
+		{account.ErrInvalidDescription, http.StatusBadRequest, "invalid_description"},
+		{money.ErrInvalidCurrency, http.StatusBadRequest, "invalid_currency"},
+		{money.ErrAmountExceedsMaxSafeInt, http.StatusBadRequest, "amount_too_large"},
+		{money.ErrInvalidAmountPrecision, http.StatusBadRequest, "invalid_amount_precision"},
+		{money.ErrMismatchedCurrencies, http.StatusUnprocessableEntity, "currency_mismatch"},
+		{currency.ErrUnsupported, http.StatusBadRequest, "unsupported_currency"},
+		{currency.ErrCurrencyNotFound, http.StatusNotFound, "currency_not_found"},
+		{currency.ErrCurrencyExists, http.StatusConflict, "currency_exists"},
+		{validation.ErrInvalidRoutingNumber, http.StatusBadRequest, "invalid_routing_number"},
+		{validation.ErrInvalidIBAN, http.StatusBadRequest, "invalid_iban"},
+		{validation.ErrInvalidBankAccountNumber, http.StatusBadRequest, "invalid_bank_account_number"},
+		{validation.ErrInvalidBIC, http.StatusBadRequest, "invalid_bic"},
+		{validation.ErrInvalidStatementDescriptor, http.StatusBadRequest, "invalid_statement_descriptor"},
+		{exchange.ErrUnsupportedPair, http.StatusUnprocessableEntity, "unsupported_currency_pair"},
+		{exchange.ErrProviderUnavailable, http.StatusServiceUnavailable, "rate_provider_unavailable"},
+		{exchange.ErrRateProviderQuotaExhausted, http.StatusServiceUnavailable, "rate_provider_quota_exhausted"},
+		{exchange.ErrConversionNotAllowed, http.StatusUnprocessableEntity, "conversion_not_allowed"},
+		{payment.ErrRedirectURLNotAllowed, http.StatusBadRequest, "redirect_url_not_allowed"},
+		{payment.ErrInvalidCaptureMethod, http.StatusBadRequest, "invalid_capture_method"},
+		{payment.ErrRefundsNotSupported, http.StatusNotImplemented, "refunds_not_supported"},
+		{payment.ErrCheckoutCancelNotSupported, http.StatusNotImplemented, "checkout_cancel_not_supported"},
+		{payment.ErrCaptureNotSupported, http.StatusNotImplemented, "capture_not_supported"},
+		{payment.ErrPaymentNotCapturable, http.StatusConflict, "payment_not_capturable"},
+		{payment.ErrPayoutNotFound, http.StatusNotFound, "payout_not_found"},
+		{payment.ErrPayoutNotCancellable, http.StatusConflict, "payout_not_cancellable"},
+		{iso20022.ErrIncompleteTransfer, http.StatusUnprocessableEntity, "incomplete_transfer"},
+		{payoutexport.ErrNotConfigured, http.StatusNotImplemented, "payout_export_not_configured"},
+		{reconciliation.ErrInvalidRange, http.StatusBadRequest, "invalid_date_range"},
+		{registry.ErrNotFound, http.StatusNotFound, "not_found"},
+		{context.DeadlineExceeded, http.StatusGatewayTimeout, "timeout"},
+		{eventbus.ErrNoConsumer, http.StatusNotFound, "consumer_not_found"},
+		{featureflag.ErrFlagNotFound, http.StatusNotFound, "feature_flag_not_found"},
+		{featureflag.ErrInvalidFlag, http.StatusBadRequest, "invalid_feature_flag"},
+		{maintenance.ErrUnderMaintenance, http.StatusServiceUnavailable, "under_maintenance"},
+		{user.ErrUserNotFound, http.StatusNotFound, "user_not_found"},
+		{user.ErrUserUnauthorized, http.StatusUnauthorized, "unauthorized"},
+	}
+	for _, e := range errcode.Entries() {
+		if e.Err == errVaultSealed {
+			continue
+		}
+		covered := false
+		for _, tt := range tests {
+			covered = covered || tt.err == e.Err
+		}
+		assert.True(t, covered, "registered error %q is not covered", e.Err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			wrapped := fmt.Errorf("handling request: %w", tt.err)
+			e, ok := errcode.Lookup(wrapped)
+			require.True(t, ok)
+			assert.ErrorIs(t, tt.err, e.Err)
+			assert.Equal(t, tt.status, e.Status)
+			assert.Equal(t, tt.code, e.Code)
+			assert.Equal(t, tt.status, errcode.Status(wrapped))
+			assert.Equal(t, tt.code, errcode.Code(wrapped))
+		})
+	}
+}
+
+func TestEntriesHaveStableCodes(t *testing.T) {
+	snakeCase := regexp.MustCompile(`^[a-z]+(_[a-z0-9]+)*$`)
+	for _, e := range errcode.Entries() {
+		assert.Regexp(t, snakeCase, e.Code, e.Err)
+		assert.GreaterOrEqual(t, e.Status, 400, e.Err)
+		assert.Less(t, e.Status, 600, e.Err)
+	}
+}
+
+func TestUnregistered(t *testing.T) {
+	_, ok := errcode.Lookup(nil)
+	assert.False(t, ok)
+	err := errors.New("boom")
+	_, ok = errcode.Lookup(err)
+	assert.False(t, ok)
+	assert.Equal(t, http.StatusInternalServerError, errcode.Status(err))
+	assert.Equal(t, errcode.Internal, errcode.Code(err))
+}
+
+var errVaultSealed = fmt.Errorf("vault sealed: %w", domain.ErrForbidden)
+
+func TestRegister(t *testing.T) {
+	errcode.Register(errVaultSealed, http.StatusLocked, "vault_sealed")
+
+	assert.Equal(t, http.StatusLocked, errcode.Status(fmt.Errorf("open: %w", errVaultSealed)))
+	assert.Equal(t, "vault_sealed", errcode.Code(errVaultSealed))
+	assert.Equal(t, http.StatusForbidden, errcode.Status(domain.ErrForbidden))
+}
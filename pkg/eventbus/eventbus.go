@@ -2,6 +2,8 @@ package eventbus
 
 import (
 	"context"
+	"errors"
+
 	"github.com/amirasaad/fintech/pkg/domain/events"
 )
 
@@ -11,6 +13,69 @@ type Bus interface {
 	Emit(ctx context.Context, event events.Event) error
 }
 
+// ErrNoConsumer is returned when pausing or resuming an event type that has
+// no consumer, i.e. no registered handler.
+var ErrNoConsumer = errors.New("no consumer for event type")
+
+// PausableBus is a Bus whose per-event-type consumers can be paused, e.g.
+// while the downstream system they call is under maintenance. Events of a
+// paused type are kept by the bus and handled once it is resumed.
+type PausableBus interface {
+	Bus
+	PauseConsumer(eventType events.EventType) error
+	ResumeConsumer(eventType events.EventType) error
+	// PausedConsumers returns the paused event types.
+	PausedConsumers() []events.EventType
+}
+
 // HandlerFunc is a generic event handler function for registry-based event
 // buses.
 type HandlerFunc func(ctx context.Context, event events.Event) error
+
+// Phase orders the handlers of an event type. All critical handlers run, and
+// must succeed, before any best-effort handler runs.
+type Phase int
+
+const (
+	// PhaseCritical handlers do work the event cannot be considered handled
+	// without, such as updating balances. If one fails, best-effort handlers
+	// are skipped and the event is routed to the DLQ, where the bus has one.
+	PhaseCritical Phase = iota
+	// PhaseBestEffort handlers, such as notifications, run only after every
+	// critical handler succeeded. Their failures are logged and neither roll
+	// back the critical work nor route the event to the DLQ.
+	PhaseBestEffort
+)
+
+// String returns the phase name.
+func (p Phase) String() string {
+	switch p {
+	case PhaseCritical:
+		return "critical"
+	case PhaseBestEffort:
+		return "best_effort"
+	}
+	return "unknown"
+}
+
+// PhasedBus is a Bus that can register handlers in a phase. Register
+// registers critical handlers.
+type PhasedBus interface {
+	Bus
+	RegisterWithPhase(eventType events.EventType, handler HandlerFunc, phase Phase)
+}
+
+// RegisterWithPhase registers handler in phase if bus supports phases, and
+// as a critical handler otherwise.
+func RegisterWithPhase(
+	bus Bus,
+	eventType events.EventType,
+	handler HandlerFunc,
+	phase Phase,
+) {
+	if phased, ok := bus.(PhasedBus); ok {
+		phased.RegisterWithPhase(eventType, handler, phase)
+		return
+	}
+	bus.Register(eventType, handler)
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log/slog"
 
+	accountdomain "github.com/amirasaad/fintech/pkg/domain/account"
 	"github.com/amirasaad/fintech/pkg/domain/events"
 	"github.com/amirasaad/fintech/pkg/dto"
 	"github.com/amirasaad/fintech/pkg/eventbus"
@@ -76,6 +77,21 @@ func HandleRequested(
 			return fmt.Errorf("failed to get account: %w", err)
 		}
 
+		// Accounts that do not auto-convert only take their own currency.
+		if depositCurrency := dr.Amount.Currency().String(); !account.AutoConvert &&
+			depositCurrency != account.Currency {
+			err := fmt.Errorf(
+				"%w: account only accepts %s deposits, got %s",
+				accountdomain.ErrCurrencyMismatch, account.Currency, depositCurrency,
+			)
+			log.Warn("🚫 [REJECTED] Deposit currency not accepted", "error", err)
+			df := events.NewDepositFailed(dr, err.Error())
+			if err := bus.Emit(ctx, df); err != nil {
+				log.Error("❌ [ERROR] Failed to emit DepositFailed event", "error", err)
+			}
+			return nil
+		}
+
 		// Create transaction ID if not provided
 		if dr.TransactionID == uuid.Nil {
 			dr.TransactionID = uuid.New()
@@ -162,6 +178,7 @@ func persistDepositTransaction(
 			Status:      "created",
 			MoneySource: "deposit",
 			Currency:    dr.Amount.Currency().String(),
+			Description: dr.Description,
 			// PaymentID is intentionally omitted to prevent unique constraint violations
 		}
 
@@ -0,0 +1,86 @@
+package deposit_test
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/amirasaad/fintech/internal/fixtures/mocks"
+	"github.com/amirasaad/fintech/pkg/domain/events"
+	"github.com/amirasaad/fintech/pkg/dto"
+	"github.com/amirasaad/fintech/pkg/handler/account/deposit"
+	"github.com/amirasaad/fintech/pkg/money"
+	"github.com/amirasaad/fintech/pkg/repository"
+	"github.com/amirasaad/fintech/pkg/repository/account"
+	"github.com/amirasaad/fintech/pkg/repository/transaction"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleRequested_AutoConvert(t *testing.T) {
+	t.Parallel()
+	userID := uuid.New()
+	accountID := uuid.New()
+	amount, err := money.New(50, money.USD)
+	require.NoError(t, err)
+
+	setup := func(t *testing.T, autoConvert bool) (*mocks.Bus, *mocks.UnitOfWork) {
+		bus := mocks.NewBus(t)
+		uow := mocks.NewUnitOfWork(t)
+		accRepo := mocks.NewAccountRepository(t)
+		uow.EXPECT().GetRepository((*account.Repository)(nil)).Return(accRepo, nil)
+		accRepo.EXPECT().Get(mock.Anything, accountID).Return(&dto.AccountRead{
+			ID:          accountID,
+			UserID:      userID,
+			Currency:    "EUR",
+			AutoConvert: autoConvert,
+		}, nil)
+		return bus, uow
+	}
+	newRequest := func() *events.DepositRequested {
+		return events.NewDepositRequested(
+			userID, accountID, uuid.New(), events.WithDepositAmount(amount),
+		)
+	}
+
+	t.Run("converts deposits in another currency", func(t *testing.T) {
+		t.Parallel()
+		bus, uow := setup(t, true)
+		txRepo := mocks.NewTransactionRepository(t)
+		uow.EXPECT().Do(mock.Anything, mock.Anything).RunAndReturn(
+			func(_ context.Context, fn func(repository.UnitOfWork) error) error {
+				return fn(uow)
+			},
+		)
+		uow.EXPECT().GetRepository((*transaction.Repository)(nil)).Return(txRepo, nil)
+		txRepo.EXPECT().Create(mock.Anything, mock.MatchedBy(
+			func(tx dto.TransactionCreate) bool { return tx.Currency == "USD" },
+		)).Return(nil)
+		bus.EXPECT().Emit(mock.Anything, mock.MatchedBy(func(e events.Event) bool {
+			ccr, ok := e.(*events.CurrencyConversionRequested)
+			return ok && ccr.To == "EUR"
+		})).Return(nil).Once()
+
+		handler := deposit.HandleRequested(bus, uow, slog.Default())
+		require.NoError(t, handler(context.Background(), newRequest()))
+	})
+
+	t.Run("rejects deposits in another currency", func(t *testing.T) {
+		t.Parallel()
+		bus, uow := setup(t, false)
+		var failed *events.DepositFailed
+		bus.EXPECT().Emit(mock.Anything, mock.AnythingOfType("*events.DepositFailed")).
+			Run(func(_ context.Context, e events.Event) {
+				failed = e.(*events.DepositFailed)
+			}).
+			Return(nil).Once()
+
+		handler := deposit.HandleRequested(bus, uow, slog.Default())
+		require.NoError(t, handler(context.Background(), newRequest()))
+		require.NotNil(t, failed)
+		assert.Contains(t, failed.Reason, "only accepts EUR deposits")
+		uow.AssertNotCalled(t, "Do", mock.Anything, mock.Anything)
+	})
+}
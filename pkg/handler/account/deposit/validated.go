@@ -47,6 +47,12 @@ func HandleValidated(
 			pi.UserID = dv.UserID
 			pi.AccountID = dv.AccountID
 			pi.CorrelationID = dv.CorrelationID
+			if dr, ok := dv.OriginalRequest.(*events.DepositRequested); ok {
+				pi.SuccessURL = dr.SuccessURL
+				pi.CancelURL = dr.CancelURL
+				pi.StatementDescriptor = dr.StatementDescriptor
+				pi.CaptureMethod = dr.CaptureMethod
+			}
 		})
 		log.Info(
 			"📤 [EMIT] Emitting event",
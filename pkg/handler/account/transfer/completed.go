@@ -5,10 +5,12 @@ import (
 	"fmt"
 	"log/slog"
 
+	"github.com/amirasaad/fintech/pkg/domain/account"
 	"github.com/amirasaad/fintech/pkg/domain/events"
 	"github.com/amirasaad/fintech/pkg/dto"
 	"github.com/amirasaad/fintech/pkg/eventbus"
 	"github.com/amirasaad/fintech/pkg/handler/common"
+	"github.com/amirasaad/fintech/pkg/mapper"
 	"github.com/amirasaad/fintech/pkg/money"
 	"github.com/amirasaad/fintech/pkg/repository"
 	"github.com/google/uuid"
@@ -66,6 +68,7 @@ func HandleCompleted(
 		// 2. Atomic Final HandleCompleted
 		txInID := uuid.New()
 		txOutID := tr.TransactionID
+		applied := false
 
 		if err := uow.Do(ctx, func(uow repository.UnitOfWork) error {
 			txRepo, err := common.GetTransactionRepository(uow, log)
@@ -78,13 +81,28 @@ func HandleCompleted(
 				return fmt.Errorf("failed to get account repo: %w", err)
 			}
 
-			sourceAcc, err := accRepo.Get(ctx, tr.AccountID)
+			// Lock both account rows for the balance updates, then check
+			// the transfer was not applied by a concurrent delivery of the
+			// event and the source still has the funds.
+			accounts, err := common.LockAccounts(ctx, accRepo, tr.AccountID, tr.DestAccountID)
 			if err != nil {
-				return fmt.Errorf("could not find source account: %w", err)
+				return err
 			}
-			destAcc, err := accRepo.Get(ctx, tr.DestAccountID)
+			sourceAcc, destAcc := accounts[tr.AccountID], accounts[tr.DestAccountID]
+			txOut, err := txRepo.Get(ctx, txOutID)
 			if err != nil {
-				return fmt.Errorf("could not find destination account: %w", err)
+				return fmt.Errorf("could not find outgoing transaction: %w", err)
+			}
+			if txOut.Status == string(account.TransactionStatusCompleted) {
+				applied = true
+				return nil
+			}
+			source, err := mapper.MapAccountReadToDomain(sourceAcc)
+			if err != nil {
+				return fmt.Errorf("could not map source account: %w", err)
+			}
+			if source.AvailableBalance().Amount() < tr.Amount.Amount() {
+				return account.ErrInsufficientFunds
 			}
 
 			sourceBalance, err := money.New(sourceAcc.Balance, tr.Amount.Currency())
@@ -124,6 +142,20 @@ func HandleCompleted(
 			}
 
 			completedStatus := "completed"
+			// Record the incoming side so the destination ledger matches
+			// its balance.
+			if err := txRepo.Create(ctx, dto.TransactionCreate{
+				ID:          txInID,
+				UserID:      destAcc.UserID,
+				AccountID:   tr.DestAccountID,
+				Amount:      tr.Amount.Amount(),
+				Currency:    tr.Amount.Currency().String(),
+				Status:      completedStatus,
+				MoneySource: "transfer",
+				Description: tr.Description,
+			}); err != nil {
+				return fmt.Errorf("failed to create incoming transaction: %w", err)
+			}
 			if err := txRepo.Update(
 				ctx,
 				txOutID,
@@ -142,6 +174,10 @@ func HandleCompleted(
 			tf := events.NewTransferFailed(tr, "PersistenceFailed: "+err.Error())
 			return bus.Emit(ctx, tf)
 		}
+		if applied {
+			log.Info("Transfer already completed", "tx_out_id", txOutID)
+			return nil
+		}
 		log.Info(
 			"✅ [SUCCESS] Final transfer persistence complete",
 			"tx_out_id", txOutID,
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log/slog"
 
+	"github.com/amirasaad/fintech/pkg/domain/account"
 	"github.com/amirasaad/fintech/pkg/domain/events"
 	"github.com/amirasaad/fintech/pkg/eventbus"
 	"github.com/amirasaad/fintech/pkg/handler/common"
@@ -120,6 +121,19 @@ func HandleCurrencyConverted(
 			return fmt.Errorf("failed to map destination account read to domain: %w", err)
 		}
 
+		if !account.IsActiveStatus(sourceAccDto.Status) ||
+			!account.IsActiveStatus(destAccDto.Status) {
+			log.Warn(
+				"account is not active",
+				"source_status", sourceAccDto.Status,
+				"dest_status", destAccDto.Status,
+			)
+			return bus.Emit(ctx, events.NewTransferFailed(
+				tr,
+				account.ErrAccountNotActive.Error(),
+			))
+		}
+
 		// Perform domain validation
 		if err := sourceAcc.ValidateTransfer(
 			tcc.UserID,
@@ -130,3 +130,59 @@ func TestHandleCurrencyConverted_Success(t *testing.T) {
 	uow.AssertNumberOfCalls(t, "GetRepository", 1) // Called once to get the account repository
 	uow.AssertExpectations(t)                      // Ensure all UoW expectations were met
 }
+
+func TestHandleCurrencyConverted_AccountNotActive(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	sourceAccountID := uuid.New()
+	destAccountID := uuid.New()
+	transactionID := uuid.New()
+
+	amount, err := money.New(100.0, money.USD)
+	require.NoError(t, err)
+	tr := &events.TransferRequested{
+		FlowEvent: events.FlowEvent{
+			ID:        uuid.New(),
+			UserID:    userID,
+			AccountID: sourceAccountID,
+			Timestamp: time.Now(),
+		},
+		DestAccountID: destAccountID,
+		Amount:        amount,
+	}
+	tcc := events.NewTransferCurrencyConverted(&events.CurrencyConverted{
+		CurrencyConversionRequested: events.CurrencyConversionRequested{
+			FlowEvent:       tr.FlowEvent,
+			OriginalRequest: tr,
+			Amount:          amount,
+			To:              money.USD,
+			TransactionID:   transactionID,
+		},
+		TransactionID:   transactionID,
+		ConvertedAmount: amount,
+	})
+
+	bus := mocks.NewBus(t)
+	uow := mocks.NewUnitOfWork(t)
+	accRepo := mocks.NewAccountRepository(t)
+	uow.On("GetRepository", (*account.Repository)(nil)).Return(accRepo, nil).Once()
+	accRepo.On("Get", ctx, sourceAccountID).
+		Return(&dto.AccountRead{
+			ID: sourceAccountID, UserID: userID, Balance: 1000.0, Currency: "USD",
+		}, nil).
+		Once()
+	// The destination was frozen, e.g. after suspicious activity.
+	accRepo.On("Get", ctx, destAccountID).
+		Return(&dto.AccountRead{
+			ID: destAccountID, UserID: userID, Balance: 500.0, Currency: "USD", Status: "frozen",
+		}, nil).
+		Once()
+	bus.On("Emit", ctx, mock.MatchedBy(func(e *events.TransferFailed) bool {
+		return e.Reason == "account is not active"
+	})).
+		Return(nil).
+		Once()
+
+	err = HandleCurrencyConverted(bus, uow, slog.Default())(ctx, tcc)
+	require.NoError(t, err)
+}
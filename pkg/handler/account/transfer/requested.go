@@ -76,6 +76,7 @@ func HandleRequested(
 				Currency:    tr.Amount.Currency().String(),
 				Status:      "pending",
 				MoneySource: "transfer",
+				Description: tr.Description,
 			})
 		})
 
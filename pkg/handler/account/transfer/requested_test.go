@@ -48,6 +48,7 @@ func TestInitialPersistence(t *testing.T) {
 			correlationID,
 			events.WithTransferRequestedAmount(validAmount),
 			events.WithTransferDestAccountID(destAccountID),
+			events.WithTransferDescription("Rent for March"),
 		)
 		requestedEvent.ID = transactionID // Set the transaction ID
 
@@ -59,6 +60,7 @@ func TestInitialPersistence(t *testing.T) {
 				tx.Status == "pending" &&
 				tx.ID != uuid.Nil &&
 				tx.MoneySource == "transfer" &&
+				tx.Description == "Rent for March" &&
 				tx.Amount == -10000 // Negative amount in smallest currency unit (100.00 USD * 100)
 		})
 
@@ -11,6 +11,7 @@ import (
 	"github.com/amirasaad/fintech/pkg/handler/common"
 	"github.com/amirasaad/fintech/pkg/mapper"
 	"github.com/amirasaad/fintech/pkg/repository"
+	"github.com/google/uuid"
 )
 
 // HandleCurrencyConverted performs domain validation after currency conversion for withdrawals.
@@ -86,6 +87,19 @@ func HandleCurrencyConverted(
 			return account.ErrAccountNotFound
 		}
 
+		if !account.IsActiveStatus(accRead.Status) {
+			log.Warn(
+				"account is not active",
+				"transaction_id", wcc.TransactionID,
+				"account_id", wcc.AccountID,
+				"status", accRead.Status,
+			)
+			return bus.Emit(ctx, events.NewWithdrawFailed(
+				wr,
+				account.ErrAccountNotActive.Error(),
+			))
+		}
+
 		acc, err := mapper.MapAccountReadToDomain(accRead)
 		if err != nil {
 			log.Error(
@@ -95,8 +109,12 @@ func HandleCurrencyConverted(
 			return err
 		}
 
-		// Perform domain validation
-		if err := acc.ValidateWithdraw(wcc.UserID, wcc.ConvertedAmount); err != nil {
+		// Perform domain validation. The amount of a withdrawal approved
+		// after being held was checked and debited when it was held.
+		if wr.HoldTransactionID == uuid.Nil {
+			err = acc.ValidateWithdraw(wcc.UserID, wcc.ConvertedAmount)
+		}
+		if err != nil {
 			log.Error(
 				"domain validation failed",
 				"transaction_id", wcc.TransactionID,
@@ -0,0 +1,59 @@
+package withdraw
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/amirasaad/fintech/pkg/domain/events"
+	"github.com/amirasaad/fintech/pkg/eventbus"
+	"github.com/amirasaad/fintech/pkg/handler/common"
+	"github.com/amirasaad/fintech/pkg/repository"
+	"github.com/google/uuid"
+)
+
+// HandleFailed handles WithdrawFailed events of withdrawals approved after
+// being held by releasing their hold, which debited the amount. Other
+// withdrawals never debited the account, so there is nothing to undo.
+func HandleFailed(
+	uow repository.UnitOfWork,
+	logger *slog.Logger,
+) eventbus.HandlerFunc {
+	return func(ctx context.Context, e events.Event) error {
+		log := logger.With(
+			"handler", "withdraw.HandleFailed",
+			"event_type", e.Type(),
+		)
+
+		wf, ok := e.(*events.WithdrawFailed)
+		if !ok {
+			log.Error("❌ [ERROR] Unexpected event type", "event", e)
+			return fmt.Errorf("unexpected event type: %T", e)
+		}
+		if wf.HoldTransactionID == uuid.Nil {
+			return nil
+		}
+		log = log.With(
+			"user_id", wf.UserID,
+			"account_id", wf.AccountID,
+			"transaction_id", wf.HoldTransactionID,
+			"reason", wf.Reason,
+		)
+
+		var released bool
+		if err := uow.Do(ctx, func(uow repository.UnitOfWork) error {
+			var err error
+			released, err = common.ReleaseWithdrawalHold(
+				ctx, uow, wf.HoldTransactionID, nil, log,
+			)
+			return err
+		}); err != nil {
+			log.Error("❌ [ERROR] Failed to release withdrawal hold", "error", err)
+			return err
+		}
+		if released {
+			log.Info("✅ [SUCCESS] Withdrawal hold released")
+		}
+		return nil
+	}
+}
@@ -76,8 +76,11 @@ func HandleRequested(
 		// Create transaction ID
 		txID := uuid.New()
 
-		// Persist the withdraw transaction
-		if err := persistWithdrawTransaction(ctx, uow, wr, txID, log); err != nil {
+		// Persist the withdraw transaction, unless the hold transaction of
+		// an approved withdrawal already debited it
+		if wr.HoldTransactionID != uuid.Nil {
+			txID = wr.HoldTransactionID
+		} else if err := persistWithdrawTransaction(ctx, uow, wr, txID, log); err != nil {
 			log.Error(
 				"❌ [ERROR] Failed to persist withdraw transaction",
 				"error", err,
@@ -175,6 +178,19 @@ func persistWithdrawTransaction(
 			Currency:    wr.Amount.Currency().String(),
 			Status:      "created",
 			MoneySource: "withdraw",
+			Description: wr.Description,
+		}
+		if target := wr.BankAccountNumber; target != "" {
+			txCreate.ExternalTargetMasked = "****" + lastFourDigits(target)
+		} else if target := wr.ExternalWalletAddress; target != "" {
+			txCreate.ExternalTargetMasked = "****" + lastFourDigits(target)
+		}
+		if wr.BankAccountNumber != "" {
+			txCreate.ExternalBankAccount = &dto.BankAccount{
+				Number:        wr.BankAccountNumber,
+				RoutingNumber: wr.RoutingNumber,
+				BIC:           wr.BIC,
+			}
 		}
 
 		if err := txRepo.Create(ctx, txCreate); err != nil {
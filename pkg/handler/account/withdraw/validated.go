@@ -6,13 +6,17 @@ import (
 	"fmt"
 	"log/slog"
 	"strings"
+	"time"
 
+	"github.com/amirasaad/fintech/pkg/domain/account"
 	"github.com/amirasaad/fintech/pkg/domain/events"
 	"github.com/amirasaad/fintech/pkg/dto"
 	"github.com/amirasaad/fintech/pkg/eventbus"
 	"github.com/amirasaad/fintech/pkg/handler/common"
+	"github.com/amirasaad/fintech/pkg/money"
 	"github.com/amirasaad/fintech/pkg/provider/payment"
 	"github.com/amirasaad/fintech/pkg/repository"
+	"github.com/google/uuid"
 )
 
 // HandleValidated handles WithdrawValidated events by initiating a payout.
@@ -25,7 +29,7 @@ import (
 func HandleValidated(
 	bus eventbus.Bus,
 	uow repository.UnitOfWork,
-	paymentProvider payment.Payment,
+	payoutProvider payment.Payout,
 	logger *slog.Logger,
 ) eventbus.HandlerFunc {
 	return func(ctx context.Context, e events.Event) error {
@@ -52,8 +56,8 @@ func HandleValidated(
 		}
 
 		// Validate the withdrawal amount is positive
-		if wv.ConvertedAmount.Amount() <= 0 {
-			err := fmt.Errorf("invalid withdrawal amount: %d", wv.ConvertedAmount.Amount())
+		if !wv.ConvertedAmount.IsPositive() {
+			err := fmt.Errorf("invalid withdrawal amount: %s", wv.ConvertedAmount)
 			log.Error("validation failed", "error", err)
 			return err
 		}
@@ -97,6 +101,7 @@ func HandleValidated(
 			"stripe_account_id":  user.StripeConnectAccountID,
 			"bank_account_last4": lastFourDigits(req.BankAccountNumber),
 			"bank_routing":       maskSensitive(req.RoutingNumber, 4),
+			"network":            req.Network,
 			"user_id":            wv.UserID.String(),
 			"account_id":         wv.AccountID.String(),
 			"user_email":         user.Email,
@@ -115,13 +120,7 @@ func HandleValidated(
 			Currency:          strings.ToLower(wv.ConvertedAmount.Currency().String()),
 			Description:       description,
 			Metadata:          metadata,
-			Destination: payment.PayoutDestination{
-				Type: payment.PayoutDestinationBankAccount,
-				BankAccount: &payment.BankAccountDetails{
-					AccountNumber: req.BankAccountNumber,
-					RoutingNumber: req.RoutingNumber,
-				},
-			},
+			Destination:       payoutDestination(req),
 		}
 
 		// Log the payout initiation attempt
@@ -132,7 +131,7 @@ func HandleValidated(
 		)
 
 		// Initiate the payout
-		payout, err := paymentProvider.InitiatePayout(ctx, payoutParams)
+		payout, err := payoutProvider.InitiatePayout(ctx, payoutParams)
 		if err != nil {
 			log.Error("Failed to initiate payout", "error", err)
 
@@ -172,7 +171,15 @@ func HandleValidated(
 		log.Info("Payout initiated successfully",
 			"payout_id", payout.PayoutID,
 			"status", payout.Status,
+			"application_fee_amount", payout.ApplicationFeeAmount,
 		)
+		if payout.ApplicationFeeAmount > 0 || payout.EstimatedArrivalDate > 0 {
+			// The payout is already under way; a failure here only affects
+			// reporting, so it is logged rather than failing the withdrawal.
+			if err := recordPayout(ctx, uow, wv.TransactionID, payout, log); err != nil {
+				log.Error("Failed to record payout details", "error", err)
+			}
+		}
 
 		// Prepare payment processed event with all required details
 		paymentID := payout.PayoutID
@@ -211,6 +218,87 @@ func HandleValidated(
 	}
 }
 
+// recordPayout records the payout's estimated arrival on the withdrawal
+// transaction, and adds the platform fee withheld from it to the
+// transaction's fee. The balance is not touched: the fee came out of the
+// amount already withdrawn. A retried payout that returned the transfer
+// already recorded on the transaction adds no fee.
+func recordPayout(
+	ctx context.Context,
+	uow repository.UnitOfWork,
+	transactionID uuid.UUID,
+	payout *payment.InitiatePayoutResponse,
+	log *slog.Logger,
+) error {
+	txRepo, err := common.GetTransactionRepository(uow, log)
+	if err != nil {
+		return err
+	}
+	tx, err := txRepo.Get(ctx, transactionID)
+	if err != nil {
+		return fmt.Errorf("failed to get transaction: %w", err)
+	}
+	var update dto.TransactionUpdate
+	if payout.EstimatedArrivalDate > 0 {
+		arrival := time.Unix(payout.EstimatedArrivalDate, 0).UTC()
+		update.PayoutEstimatedArrival = &arrival
+	}
+	recorded := tx.PaymentID != nil && *tx.PaymentID == payout.PayoutID
+	var fee, total *money.Money
+	if payout.ApplicationFeeAmount > 0 && !recorded {
+		fee, err = money.NewFromSmallestUnit(
+			payout.ApplicationFeeAmount,
+			money.Code(strings.ToUpper(payout.Currency)),
+		)
+		if err != nil {
+			return fmt.Errorf("invalid application fee: %w", err)
+		}
+		existing, err := money.New(tx.Fee, money.Code(tx.Currency))
+		if err != nil {
+			return fmt.Errorf("invalid transaction fee: %w", err)
+		}
+		if total, err = existing.Add(fee); err != nil {
+			return fmt.Errorf("failed to add application fee: %w", err)
+		}
+		amount := int64(total.Amount())
+		update.Fee = &amount
+	}
+	if update.Fee == nil && update.PayoutEstimatedArrival == nil {
+		return nil
+	}
+	if err := txRepo.Update(ctx, tx.ID, update); err != nil {
+		return fmt.Errorf("failed to update transaction: %w", err)
+	}
+	if fee != nil {
+		log.Info("Recorded application fee",
+			"fee_type", account.FeeTypeApplication,
+			"application_fee", fee.String(),
+			"total_fee", total.String(),
+		)
+	}
+	return nil
+}
+
+// payoutDestination builds the payout destination from the original request,
+// preferring the external wallet (with its network) when one was provided.
+func payoutDestination(req *events.WithdrawRequested) payment.PayoutDestination {
+	if req.ExternalWalletAddress != "" {
+		wallet := req.ExternalWalletAddress
+		return payment.PayoutDestination{
+			Type:           payment.PayoutDestinationExternalWallet,
+			ExternalWallet: &wallet,
+			Network:        req.Network,
+		}
+	}
+	return payment.PayoutDestination{
+		Type: payment.PayoutDestinationBankAccount,
+		BankAccount: &payment.BankAccountDetails{
+			AccountNumber: req.BankAccountNumber,
+			RoutingNumber: req.RoutingNumber,
+		},
+	}
+}
+
 // lastFourDigits returns the last 4 digits of a bank account number
 func lastFourDigits(accountNumber string) string {
 	if len(accountNumber) <= 4 {
@@ -12,6 +12,7 @@ import (
 	"github.com/amirasaad/fintech/pkg/dto"
 	"github.com/amirasaad/fintech/pkg/money"
 	"github.com/amirasaad/fintech/pkg/provider/payment"
+	"github.com/amirasaad/fintech/pkg/repository/transaction"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -69,12 +70,20 @@ func TestHandleValidated(t *testing.T) {
 	t.Run("successful payout initiation", func(t *testing.T) {
 		// Create mocks
 		mockBus := mocks.NewBus(t)
-		mockPayment := mocks.NewPaymentProvider(t)
+		mockPayout := mocks.NewPayoutProvider(t)
 		uow := mocks.NewUnitOfWork(t)
 
-		// Mock user repository
+		// Mock user and transaction repositories
 		mockUserRepo := new(mocks.UserRepository)
-		uow.On("GetRepository", mock.Anything).Return(mockUserRepo, nil)
+		txRepo := mocks.NewTransactionRepository(t)
+		uow.EXPECT().GetRepository(mock.Anything).RunAndReturn(
+			func(repoType any) (any, error) {
+				if _, ok := repoType.(*transaction.Repository); ok {
+					return txRepo, nil
+				}
+				return mockUserRepo, nil
+			},
+		)
 		mockUserRepo.On("Get", mock.Anything, userID).Return(&dto.UserRead{
 			ID:                     userID,
 			Username:               "testuser",
@@ -95,8 +104,19 @@ func TestHandleValidated(t *testing.T) {
 			EstimatedArrivalDate: time.Now().Add(24 * time.Hour).Unix(),
 		}
 
+		// The estimated arrival is recorded on the transaction.
+		txRepo.EXPECT().Get(mock.Anything, transactionID).Return(&dto.TransactionRead{
+			ID: transactionID, Currency: "USD", Amount: -100,
+		}, nil)
+		txRepo.EXPECT().Update(mock.Anything, transactionID, mock.MatchedBy(
+			func(u dto.TransactionUpdate) bool {
+				return u.Fee == nil && u.PayoutEstimatedArrival != nil &&
+					u.PayoutEstimatedArrival.Unix() == expectedPayout.EstimatedArrivalDate
+			},
+		)).Return(nil)
+
 		// Set up expectations
-		mockPayment.On(
+		mockPayout.On(
 			"InitiatePayout",
 			mock.Anything,
 			mock.AnythingOfType("*payment.InitiatePayoutParams")).
@@ -143,7 +163,7 @@ func TestHandleValidated(t *testing.T) {
 			).Return(nil)
 
 		// Create handler
-		handler := HandleValidated(mockBus, uow, mockPayment, logger)
+		handler := HandleValidated(mockBus, uow, mockPayout, logger)
 
 		// Execute
 		err := handler(context.Background(), wv)
@@ -151,10 +171,93 @@ func TestHandleValidated(t *testing.T) {
 		// Assert
 		require.NoError(t, err)
 	})
+	t.Run("records withheld application fee", func(t *testing.T) {
+		mockBus := mocks.NewBus(t)
+		mockPayout := mocks.NewPayoutProvider(t)
+		uow := mocks.NewUnitOfWork(t)
+		userRepo := mocks.NewUserRepository(t)
+		txRepo := mocks.NewTransactionRepository(t)
+		uow.EXPECT().GetRepository(mock.Anything).RunAndReturn(
+			func(repoType any) (any, error) {
+				if _, ok := repoType.(*transaction.Repository); ok {
+					return txRepo, nil
+				}
+				return userRepo, nil
+			},
+		)
+		userRepo.EXPECT().Get(mock.Anything, userID).Return(&dto.UserRead{
+			ID: userID, Names: "Test User", StripeConnectAccountID: "acct_123",
+		}, nil)
+		userRepo.EXPECT().Update(mock.Anything, userID, mock.Anything).Return(nil)
+		mockPayout.EXPECT().InitiatePayout(mock.Anything, mock.Anything).Return(
+			&payment.InitiatePayoutResponse{
+				PayoutID:             "tr_123",
+				PaymentProviderID:    "acct_123",
+				Status:               payment.PaymentPending,
+				Amount:               9720,
+				Currency:             "usd",
+				ApplicationFeeAmount: 280,
+			}, nil,
+		)
+		txRepo.EXPECT().Get(mock.Anything, transactionID).Return(&dto.TransactionRead{
+			ID: transactionID, Currency: "USD", Amount: -100,
+		}, nil)
+		txRepo.EXPECT().Update(mock.Anything, transactionID, mock.MatchedBy(
+			func(u dto.TransactionUpdate) bool { return u.Fee != nil && *u.Fee == 280 },
+		)).Return(nil)
+		mockBus.EXPECT().Emit(mock.Anything, mock.AnythingOfType("*events.PaymentProcessed")).
+			Return(nil)
+
+		handler := HandleValidated(mockBus, uow, mockPayout, logger)
+		require.NoError(t, handler(context.Background(), wv))
+	})
+
+	t.Run("retried payout does not record the fee again", func(t *testing.T) {
+		mockBus := mocks.NewBus(t)
+		mockPayout := mocks.NewPayoutProvider(t)
+		uow := mocks.NewUnitOfWork(t)
+		userRepo := mocks.NewUserRepository(t)
+		txRepo := mocks.NewTransactionRepository(t)
+		uow.EXPECT().GetRepository(mock.Anything).RunAndReturn(
+			func(repoType any) (any, error) {
+				if _, ok := repoType.(*transaction.Repository); ok {
+					return txRepo, nil
+				}
+				return userRepo, nil
+			},
+		)
+		userRepo.EXPECT().Get(mock.Anything, userID).Return(&dto.UserRead{
+			ID: userID, Names: "Test User", StripeConnectAccountID: "acct_123",
+		}, nil)
+		userRepo.EXPECT().Update(mock.Anything, userID, mock.Anything).Return(nil)
+		// The provider returns the transfer of the first attempt.
+		mockPayout.EXPECT().InitiatePayout(mock.Anything, mock.Anything).Return(
+			&payment.InitiatePayoutResponse{
+				PayoutID:             "tr_123",
+				PaymentProviderID:    "acct_123",
+				Status:               payment.PaymentPending,
+				Amount:               9720,
+				Currency:             "usd",
+				ApplicationFeeAmount: 280,
+			}, nil,
+		)
+		paymentID := "tr_123"
+		txRepo.EXPECT().Get(mock.Anything, transactionID).Return(&dto.TransactionRead{
+			ID: transactionID, Currency: "USD", Amount: -100, Fee: 2.80,
+			PaymentID: &paymentID,
+		}, nil)
+		mockBus.EXPECT().Emit(mock.Anything, mock.AnythingOfType("*events.PaymentProcessed")).
+			Return(nil)
+
+		handler := HandleValidated(mockBus, uow, mockPayout, logger)
+		require.NoError(t, handler(context.Background(), wv))
+		txRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+	})
+
 	t.Run("payout initiation failure", func(t *testing.T) {
 		// Create mocks
 		mockBus := mocks.NewBus(t)
-		mockPayment := mocks.NewPaymentProvider(t)
+		mockPayout := mocks.NewPayoutProvider(t)
 		uow := mocks.NewUnitOfWork(t)
 
 		// Mock user repository
@@ -172,7 +275,7 @@ func TestHandleValidated(t *testing.T) {
 		expectedErr := errors.New("payout failed")
 
 		// Set up expectations
-		mockPayment.On("InitiatePayout", mock.Anything, mock.Anything).
+		mockPayout.On("InitiatePayout", mock.Anything, mock.Anything).
 			Return((*payment.InitiatePayoutResponse)(nil), expectedErr)
 
 		// Expect a WithdrawFailed event
@@ -181,7 +284,7 @@ func TestHandleValidated(t *testing.T) {
 		})).Return(nil)
 
 		// Create handler
-		handler := HandleValidated(mockBus, uow, mockPayment, logger)
+		handler := HandleValidated(mockBus, uow, mockPayout, logger)
 
 		// Execute
 		err := handler(context.Background(), wv)
@@ -195,10 +298,10 @@ func TestHandleValidated(t *testing.T) {
 		// Create mocks
 		mockBus := mocks.NewBus(t)
 		uow := mocks.NewUnitOfWork(t)
-		mockPayment := mocks.NewPaymentProvider(t)
+		mockPayout := mocks.NewPayoutProvider(t)
 
 		// Create handler
-		handler := HandleValidated(mockBus, uow, mockPayment, logger)
+		handler := HandleValidated(mockBus, uow, mockPayout, logger)
 
 		// Execute with wrong event type
 		err := handler(context.Background(), &events.WithdrawRequested{})
@@ -208,3 +311,23 @@ func TestHandleValidated(t *testing.T) {
 		assert.Contains(t, err.Error(), "expected WithdrawValidated event")
 	})
 }
+
+func TestPayoutDestination(t *testing.T) {
+	bank := payoutDestination(&events.WithdrawRequested{
+		BankAccountNumber: "123456789",
+		RoutingNumber:     "021000021",
+	})
+	assert.Equal(t, payment.PayoutDestinationBankAccount, bank.Type)
+	require.NotNil(t, bank.BankAccount)
+	assert.Equal(t, "021000021", bank.BankAccount.RoutingNumber)
+
+	wallet := payoutDestination(&events.WithdrawRequested{
+		ExternalWalletAddress: "TJCnKsPa7y5okkXvQAidZBzqx3QyQ6sxMW",
+		Network:               "tron",
+	})
+	assert.Equal(t, payment.PayoutDestinationExternalWallet, wallet.Type)
+	require.NotNil(t, wallet.ExternalWallet)
+	assert.Equal(t, "TJCnKsPa7y5okkXvQAidZBzqx3QyQ6sxMW", *wallet.ExternalWallet)
+	assert.Equal(t, "tron", wallet.Network)
+	assert.Nil(t, wallet.BankAccount)
+}
@@ -0,0 +1,211 @@
+// Package analytics exports selected domain events, stripped of personal
+// data, to a product analytics sink. Export is best-effort: it never blocks
+// or fails the flow that emitted the event.
+package analytics
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/amirasaad/fintech/pkg/config"
+	"github.com/amirasaad/fintech/pkg/domain/events"
+	"github.com/amirasaad/fintech/pkg/eventbus"
+	"github.com/amirasaad/fintech/pkg/provider/analytics"
+	"github.com/google/uuid"
+)
+
+// shutdownFlushTimeout bounds the delivery of the events still queued when
+// the exporter stops.
+const shutdownFlushTimeout = 10 * time.Second
+
+// Exporter queues sanitized events and sends them to the sink in batches.
+// Batches the sink keeps rejecting are written to the dead letter sink.
+type Exporter struct {
+	sink    analytics.Sink
+	dlq     analytics.Sink
+	logger  *slog.Logger
+	cfg     *config.Analytics
+	queue   chan analytics.Event
+	clean   sanitizer
+	dropped atomic.Int64
+	// sleep waits between retries; replaced in tests.
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+// NewExporter creates an exporter sending to sink and writing undeliverable
+// batches to dlq, which may be nil to drop them.
+func NewExporter(
+	sink, dlq analytics.Sink,
+	logger *slog.Logger,
+	cfg *config.Analytics,
+) *Exporter {
+	if cfg == nil {
+		cfg = &config.Analytics{}
+	}
+	return &Exporter{
+		sink:   sink,
+		dlq:    dlq,
+		logger: logger.With("component", "analytics.Exporter"),
+		cfg:    cfg,
+		queue:  make(chan analytics.Event, max(cfg.QueueSize, 1)),
+		clean:  sanitizer{key: []byte(cfg.PseudonymKey)},
+		sleep:  sleepContext,
+	}
+}
+
+// Dropped returns the number of events dropped because the queue was full
+// or they could not be sanitized.
+func (x *Exporter) Dropped() int64 {
+	return x.dropped.Load()
+}
+
+// Handle returns an event handler queueing events for export. It never
+// blocks and never returns an error: when the queue is full the event is
+// dropped. Register it in the best-effort phase.
+func (x *Exporter) Handle() eventbus.HandlerFunc {
+	return func(ctx context.Context, e events.Event) error {
+		data, err := x.clean.sanitize(e)
+		if err != nil {
+			x.dropped.Add(1)
+			x.logger.Warn("failed to sanitize analytics event", "event_type", e.Type(), "error", err)
+			return nil
+		}
+		event := analytics.Event{
+			ID:         uuid.NewString(),
+			Type:       e.Type(),
+			OccurredAt: time.Now().UTC(),
+			Data:       data,
+		}
+		select {
+		case x.queue <- event:
+		default:
+			x.dropped.Add(1)
+			x.logger.Warn("analytics queue full, event dropped", "event_type", e.Type())
+		}
+		return nil
+	}
+}
+
+// Run sends queued events until ctx is done, in batches of up to BatchSize
+// at least every FlushInterval. It then sends the events still queued.
+func (x *Exporter) Run(ctx context.Context) {
+	batchSize := max(x.cfg.BatchSize, 1)
+	interval := x.cfg.FlushInterval
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	x.logger.Info("analytics exporter started",
+		"batch_size", batchSize,
+		"flush_interval", interval,
+	)
+	batch := make([]analytics.Event, 0, batchSize)
+	for {
+		select {
+		case <-ctx.Done():
+			x.drain(ctx, batch, batchSize)
+			x.logger.Info("analytics exporter stopped", "dropped", x.Dropped())
+			return
+		case e := <-x.queue:
+			batch = append(batch, e)
+			if len(batch) >= batchSize {
+				x.flush(ctx, batch)
+				batch = nil
+			}
+		case <-ticker.C:
+			if len(batch) > 0 {
+				x.flush(ctx, batch)
+				batch = nil
+			}
+		}
+	}
+}
+
+// drain sends the pending batch and the events still queued, giving up after
+// shutdownFlushTimeout.
+func (x *Exporter) drain(ctx context.Context, batch []analytics.Event, batchSize int) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), shutdownFlushTimeout)
+	defer cancel()
+	for {
+		select {
+		case e := <-x.queue:
+			batch = append(batch, e)
+			if len(batch) < batchSize {
+				continue
+			}
+		default:
+		}
+		if len(batch) == 0 {
+			return
+		}
+		x.flush(ctx, batch)
+		batch = nil
+		if len(x.queue) == 0 {
+			return
+		}
+	}
+}
+
+// flush sends a batch, retrying with exponential backoff up to MaxAttempts
+// times, and writes it to the dead letter sink if it still fails.
+func (x *Exporter) flush(ctx context.Context, batch []analytics.Event) {
+	attempts := max(x.cfg.MaxAttempts, 1)
+	backoff := x.cfg.RetryBackoff
+	var err error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		if err = x.sink.Send(ctx, batch); err == nil {
+			x.logger.Debug("analytics batch sent", "events", len(batch), "attempt", attempt)
+			return
+		}
+		x.logger.Warn("failed to send analytics batch",
+			"events", len(batch),
+			"attempt", attempt,
+			"error", err,
+		)
+		if attempt == attempts {
+			break
+		}
+		if x.sleep(ctx, backoff) != nil {
+			break
+		}
+		backoff *= 2
+	}
+	x.deadLetter(ctx, batch, err)
+}
+
+// deadLetter writes a batch the sink did not accept to the dead letter sink.
+func (x *Exporter) deadLetter(ctx context.Context, batch []analytics.Event, cause error) {
+	if x.dlq == nil {
+		x.dropped.Add(int64(len(batch)))
+		x.logger.Error("analytics batch dropped", "events", len(batch), "error", cause)
+		return
+	}
+	if err := x.dlq.Send(context.WithoutCancel(ctx), batch); err != nil {
+		x.dropped.Add(int64(len(batch)))
+		x.logger.Error("failed to dead-letter analytics batch",
+			"events", len(batch),
+			"error", err,
+			"cause", cause,
+		)
+		return
+	}
+	x.logger.Error("analytics batch dead-lettered", "events", len(batch), "error", cause)
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -0,0 +1,136 @@
+package analytics
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/amirasaad/fintech/pkg/config"
+	"github.com/amirasaad/fintech/pkg/domain/events"
+	"github.com/amirasaad/fintech/pkg/provider/analytics"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSink records the batches it receives and fails the first failures
+// sends.
+type fakeSink struct {
+	mu       sync.Mutex
+	failures int
+	calls    int
+	batches  [][]analytics.Event
+}
+
+func (s *fakeSink) Send(_ context.Context, batch []analytics.Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls++
+	if s.failures > 0 {
+		s.failures--
+		return errors.New("sink unavailable")
+	}
+	s.batches = append(s.batches, batch)
+	return nil
+}
+
+func (s *fakeSink) events() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := 0
+	for _, b := range s.batches {
+		n += len(b)
+	}
+	return n
+}
+
+func newTestExporter(sink, dlq analytics.Sink, cfg *config.Analytics) *Exporter {
+	x := NewExporter(sink, dlq, slog.New(slog.NewTextHandler(io.Discard, nil)), cfg)
+	x.sleep = func(context.Context, time.Duration) error { return nil }
+	return x
+}
+
+func depositRequested() events.Event {
+	return events.NewDepositRequested(uuid.New(), uuid.New(), uuid.New())
+}
+
+func TestExporter_Handle_NeverBlocks(t *testing.T) {
+	x := newTestExporter(&fakeSink{}, nil, &config.Analytics{QueueSize: 2, BatchSize: 10})
+	handle := x.Handle()
+
+	for range 5 {
+		require.NoError(t, handle(context.Background(), depositRequested()))
+	}
+	assert.Len(t, x.queue, 2)
+	assert.Equal(t, int64(3), x.Dropped())
+}
+
+func TestExporter_Run_BatchesAndFlushesOnShutdown(t *testing.T) {
+	sink := &fakeSink{}
+	x := newTestExporter(sink, nil, &config.Analytics{
+		QueueSize:     100,
+		BatchSize:     2,
+		FlushInterval: time.Hour,
+		MaxAttempts:   1,
+	})
+	handle := x.Handle()
+	for range 5 {
+		require.NoError(t, handle(context.Background(), depositRequested()))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		x.Run(ctx)
+		close(done)
+	}()
+	require.Eventually(t, func() bool { return sink.events() >= 4 }, time.Second, time.Millisecond)
+	cancel()
+	<-done
+
+	assert.Equal(t, 5, sink.events())
+	for _, b := range sink.batches {
+		assert.LessOrEqual(t, len(b), 2)
+		assert.Equal(t, events.EventTypeDepositRequested.String(), b[0].Type)
+	}
+}
+
+func TestExporter_Flush(t *testing.T) {
+	batch := []analytics.Event{{ID: "1", Type: "Deposit.Requested"}}
+	cfg := &config.Analytics{MaxAttempts: 3, RetryBackoff: time.Millisecond}
+
+	t.Run("retries until the sink accepts", func(t *testing.T) {
+		sink, dlq := &fakeSink{failures: 2}, &fakeSink{}
+		x := newTestExporter(sink, dlq, cfg)
+
+		x.flush(context.Background(), batch)
+
+		assert.Equal(t, 3, sink.calls)
+		assert.Equal(t, 1, sink.events())
+		assert.Zero(t, dlq.calls)
+	})
+
+	t.Run("dead-letters after the last attempt", func(t *testing.T) {
+		sink, dlq := &fakeSink{failures: 5}, &fakeSink{}
+		x := newTestExporter(sink, dlq, cfg)
+
+		x.flush(context.Background(), batch)
+
+		assert.Equal(t, 3, sink.calls)
+		assert.Equal(t, 1, dlq.events())
+		assert.Zero(t, x.Dropped())
+	})
+
+	t.Run("drops when the dead letter sink fails", func(t *testing.T) {
+		sink, dlq := &fakeSink{failures: 5}, &fakeSink{failures: 1}
+		x := newTestExporter(sink, dlq, cfg)
+
+		x.flush(context.Background(), batch)
+
+		assert.Equal(t, int64(1), x.Dropped())
+	})
+}
@@ -0,0 +1,122 @@
+package analytics
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/amirasaad/fintech/pkg/domain/events"
+)
+
+// strippedFields are removed from exported events wherever they appear,
+// nested or not. Keys are matched ignoring case and underscores, so
+// BankAccountNumber, bankAccountNumber and bank_account_number all match.
+var strippedFields = map[string]bool{
+	// Payout destinations
+	"bankaccountnumber":     true,
+	"routingnumber":         true,
+	"bic":                   true,
+	"iban":                  true,
+	"externalwalletaddress": true,
+	"externaltargetmasked":  true,
+	"last4":                 true,
+	"stripeaccountid":       true,
+	// Contact details and names
+	"email":     true,
+	"username":  true,
+	"names":     true,
+	"firstname": true,
+	"lastname":  true,
+	// Free text users or operators typed in
+	"description": true,
+	"reason":      true,
+	// Checkout redirects, which may carry user-specific query strings
+	"successurl":  true,
+	"cancelurl":   true,
+	"checkouturl": true,
+}
+
+// pseudonymizedFields hold the IDs of users and are replaced by a keyed
+// hash of the ID, so events of the same user can still be related.
+var pseudonymizedFields = map[string]bool{
+	"userid":     true,
+	"approvedby": true,
+	"rejectedby": true,
+}
+
+// normalizeKey lowercases a field name and drops its underscores.
+func normalizeKey(key string) string {
+	return strings.ReplaceAll(strings.ToLower(key), "_", "")
+}
+
+// sanitizer turns domain events into analytics payloads.
+type sanitizer struct {
+	key []byte
+}
+
+// sanitize returns the event's fields with personal data stripped and user
+// IDs pseudonymized.
+func (s *sanitizer) sanitize(e events.Event) (map[string]any, error) {
+	raw, err := json.Marshal(e)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s event: %w", e.Type(), err)
+	}
+	var data map[string]any
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return nil, fmt.Errorf("failed to decode %s event: %w", e.Type(), err)
+	}
+	s.clean(data)
+	return data, nil
+}
+
+// clean strips and pseudonymizes the fields of m and of the objects nested
+// in it.
+func (s *sanitizer) clean(m map[string]any) {
+	for k, v := range m {
+		key := normalizeKey(k)
+		switch {
+		case strippedFields[key]:
+			delete(m, k)
+		case pseudonymizedFields[key]:
+			m[k] = s.pseudonymize(v)
+		default:
+			s.cleanValue(v)
+		}
+	}
+}
+
+func (s *sanitizer) cleanValue(v any) {
+	switch v := v.(type) {
+	case map[string]any:
+		s.clean(v)
+	case []any:
+		for _, item := range v {
+			s.cleanValue(item)
+		}
+	}
+}
+
+// pseudonymize replaces an ID, or each ID of a list, by its HMAC-SHA256
+// under the pseudonym key. Anything else is dropped.
+func (s *sanitizer) pseudonymize(v any) any {
+	switch v := v.(type) {
+	case string:
+		if v == "" {
+			return v
+		}
+		mac := hmac.New(sha256.New, s.key)
+		mac.Write([]byte(v))
+		return hex.EncodeToString(mac.Sum(nil))
+	case []any:
+		out := make([]any, 0, len(v))
+		for _, item := range v {
+			out = append(out, s.pseudonymize(item))
+		}
+		return out
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,93 @@
+package analytics
+
+import (
+	"testing"
+
+	"github.com/amirasaad/fintech/pkg/domain/account"
+	"github.com/amirasaad/fintech/pkg/domain/events"
+	"github.com/amirasaad/fintech/pkg/money"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSanitize_StripsPersonalData(t *testing.T) {
+	s := sanitizer{key: []byte("secret")}
+	userID := uuid.New()
+	amount, err := money.New(25, money.USD)
+	require.NoError(t, err)
+
+	e := events.NewWithdrawRequested(
+		userID, uuid.New(), uuid.New(),
+		events.WithWithdrawAmount(amount),
+		events.WithWithdrawBankAccountNumber("000123456789"),
+		events.WithWithdrawRoutingNumber("110000000"),
+		events.WithWithdrawBIC("DEUTDEFF"),
+		events.WithWithdrawDescription("rent for Jane Doe"),
+	)
+	data, err := s.sanitize(e)
+	require.NoError(t, err)
+
+	for _, field := range []string{
+		"BankAccountNumber", "RoutingNumber", "BIC",
+		"ExternalWalletAddress", "Description",
+	} {
+		assert.NotContains(t, data, field)
+	}
+	assert.Contains(t, data, "Amount")
+	assert.Equal(t, e.AccountID.String(), data["AccountID"])
+	require.IsType(t, "", data["UserID"])
+	assert.NotEqual(t, userID.String(), data["UserID"])
+	assert.Len(t, data["UserID"], 64)
+}
+
+func TestSanitize_Nested(t *testing.T) {
+	s := sanitizer{key: []byte("secret")}
+	e := events.NewPaymentCompleted(
+		&events.FlowEvent{UserID: uuid.New()},
+		events.WithPaymentMethod(&account.PaymentMethod{
+			Type:  "card",
+			Brand: "visa",
+			Last4: "4242",
+		}),
+	)
+	data, err := s.sanitize(e)
+	require.NoError(t, err)
+
+	pm, ok := data["PaymentMethod"].(map[string]any)
+	require.True(t, ok)
+	assert.Equal(t, "visa", pm["Brand"])
+	assert.NotContains(t, pm, "Last4")
+}
+
+func TestSanitize_PseudonymsAreStable(t *testing.T) {
+	userID := uuid.New()
+	wp := events.NewWithdrawPending(
+		userID, uuid.New(), uuid.New(), uuid.New(),
+		money.Zero(money.USD), "", 2,
+	)
+	approved := events.NewWithdrawApproved(wp, []uuid.UUID{uuid.New(), uuid.New()})
+
+	s := sanitizer{key: []byte("secret")}
+	first, err := s.sanitize(wp)
+	require.NoError(t, err)
+	second, err := s.sanitize(approved)
+	require.NoError(t, err)
+	assert.Equal(t, first["UserID"], second["UserID"])
+
+	approvers, ok := second["ApprovedBy"].([]any)
+	require.True(t, ok)
+	require.Len(t, approvers, 2)
+	assert.Len(t, approvers[0], 64)
+
+	other := sanitizer{key: []byte("rotated")}
+	third, err := other.sanitize(wp)
+	require.NoError(t, err)
+	assert.NotEqual(t, first["UserID"], third["UserID"])
+}
+
+func TestNormalizeKey(t *testing.T) {
+	for _, key := range []string{"bankAccountNumber", "BankAccountNumber", "bank_account_number"} {
+		assert.True(t, strippedFields[normalizeKey(key)], key)
+	}
+}
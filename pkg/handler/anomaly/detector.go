@@ -0,0 +1,291 @@
+// Package anomaly flags accounts whose activity departs from their usual
+// pattern, for fraud review. Detection is best-effort: it never blocks or
+// fails the flow whose events it watches.
+package anomaly
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/amirasaad/fintech/pkg/config"
+	"github.com/amirasaad/fintech/pkg/domain/account"
+	"github.com/amirasaad/fintech/pkg/domain/events"
+	"github.com/amirasaad/fintech/pkg/dto"
+	"github.com/amirasaad/fintech/pkg/eventbus"
+	"github.com/amirasaad/fintech/pkg/handler/common"
+	"github.com/amirasaad/fintech/pkg/money"
+	"github.com/amirasaad/fintech/pkg/repository"
+	"github.com/google/uuid"
+)
+
+// EventTypes are the events of the transactions the detector tracks: each
+// deposit, withdrawal and transfer is requested by exactly one of them.
+// Register Handle for each in the best-effort phase.
+var EventTypes = []events.EventType{
+	events.EventTypeDepositRequested,
+	events.EventTypeWithdrawRequested,
+	events.EventTypeTransferRequested,
+	events.EventTypeTransferPending,
+}
+
+// Detector tracks the transactions of each account over a rolling window and
+// emits SuspiciousActivityDetected when an account makes too many of them or
+// one far larger than its usual amounts, optionally freezing the account.
+// Its state is kept in memory, so each instance judges the transactions it
+// handles.
+type Detector struct {
+	bus    eventbus.Bus
+	uow    repository.UnitOfWork
+	logger *slog.Logger
+	cfg    *config.Anomaly
+	// now returns the current time; replaced in tests.
+	now func() time.Time
+
+	mu       sync.Mutex
+	accounts map[uuid.UUID]*activity
+}
+
+// activity is what the detector knows of one account.
+type activity struct {
+	// times are those of the transactions within the window, oldest first.
+	times []time.Time
+	// amounts are the last amounts of the account per currency, oldest
+	// first, in the smallest unit.
+	amounts   map[money.Code][]int64
+	lastSeen  time.Time
+	lastAlert time.Time
+}
+
+// transaction is a transaction the detector tracks.
+type transaction struct {
+	accountID uuid.UUID
+	userID    uuid.UUID
+	amount    *money.Money
+	eventType string
+}
+
+// NewDetector creates a Detector with the thresholds of cfg.
+func NewDetector(
+	bus eventbus.Bus,
+	uow repository.UnitOfWork,
+	logger *slog.Logger,
+	cfg *config.Anomaly,
+) *Detector {
+	return &Detector{
+		bus:      bus,
+		uow:      uow,
+		logger:   logger.With("component", "anomaly.Detector"),
+		cfg:      cfg,
+		now:      time.Now,
+		accounts: make(map[uuid.UUID]*activity),
+	}
+}
+
+// Handle returns an event handler tracking the transaction the event
+// requests. It never returns an error: failing to freeze an account or emit
+// the alert is logged, as is a panic.
+func (d *Detector) Handle() eventbus.HandlerFunc {
+	return func(ctx context.Context, e events.Event) error {
+		defer func() {
+			if r := recover(); r != nil {
+				d.logger.Error("anomaly detection panicked", "event_type", e.Type(), "panic", r)
+			}
+		}()
+		tx, ok := transactionOf(e)
+		if !ok {
+			return nil
+		}
+		if alert := d.observe(tx); alert != nil {
+			d.raise(ctx, alert)
+		}
+		return nil
+	}
+}
+
+// transactionOf returns the transaction the event requests.
+func transactionOf(e events.Event) (transaction, bool) {
+	switch evt := e.(type) {
+	case *events.DepositRequested:
+		return transaction{evt.AccountID, evt.UserID, evt.Amount, e.Type()}, true
+	case *events.WithdrawRequested:
+		return transaction{evt.AccountID, evt.UserID, evt.Amount, e.Type()}, true
+	case *events.TransferRequested:
+		return transaction{evt.AccountID, evt.UserID, evt.Amount, e.Type()}, true
+	case *events.TransferPending:
+		return transaction{evt.AccountID, evt.UserID, evt.Amount, e.Type()}, true
+	}
+	return transaction{}, false
+}
+
+// observe records the transaction and returns the alert it raises, if any.
+func (d *Detector) observe(tx transaction) *events.SuspiciousActivityDetected {
+	if tx.accountID == uuid.Nil {
+		return nil
+	}
+	now := d.now()
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	a, ok := d.accounts[tx.accountID]
+	if !ok {
+		d.evict()
+		a = &activity{amounts: make(map[money.Code][]int64)}
+		d.accounts[tx.accountID] = a
+	}
+	a.lastSeen = now
+
+	cutoff := now.Add(-d.cfg.Window)
+	expired := 0
+	for expired < len(a.times) && !a.times[expired].After(cutoff) {
+		expired++
+	}
+	// Only whether the count exceeds MaxTransactions matters, so no more
+	// are kept.
+	a.times = append(a.times[expired:], now)
+	if len(a.times) > d.cfg.MaxTransactions+1 {
+		a.times = a.times[len(a.times)-d.cfg.MaxTransactions-1:]
+	}
+
+	alert := &events.SuspiciousActivityDetected{
+		ID:               uuid.New(),
+		AccountID:        tx.accountID,
+		UserID:           tx.userID,
+		TransactionCount: len(a.times),
+		Window:           d.cfg.Window,
+		TriggeredBy:      tx.eventType,
+		Timestamp:        now,
+	}
+	var amount int64
+	if tx.amount != nil {
+		amount = tx.amount.Amount()
+		alert.Amount = amount
+		alert.Currency = tx.amount.CurrencyCode().String()
+	}
+	switch baseline, ok := a.baseline(money.Code(alert.Currency), d.cfg.MinBaseline); {
+	case len(a.times) > d.cfg.MaxTransactions:
+		alert.Rule = events.SuspiciousRuleVelocity
+		alert.Reason = fmt.Sprintf(
+			"more than %d transactions within %s",
+			d.cfg.MaxTransactions,
+			d.cfg.Window,
+		)
+	case ok && float64(amount) > d.cfg.SpikeFactor*float64(baseline):
+		alert.Rule = events.SuspiciousRuleAmountSpike
+		alert.Baseline = baseline
+		alert.Reason = fmt.Sprintf(
+			"amount over %g times the account's usual amount",
+			d.cfg.SpikeFactor,
+		)
+	default:
+		alert = nil
+	}
+	if tx.amount != nil && amount > 0 {
+		a.record(tx.amount.CurrencyCode(), amount, d.cfg.BaselineSize)
+	}
+
+	if alert == nil {
+		return nil
+	}
+	if !a.lastAlert.IsZero() && now.Sub(a.lastAlert) < d.cfg.Cooldown {
+		return nil
+	}
+	a.lastAlert = now
+	return alert
+}
+
+// baseline returns the mean of the account's last amounts in the currency,
+// with ok false while it has fewer than minCount of them.
+func (a *activity) baseline(currency money.Code, minCount int) (int64, bool) {
+	amounts := a.amounts[currency]
+	if len(amounts) < minCount || len(amounts) == 0 {
+		return 0, false
+	}
+	var sum float64
+	for _, amount := range amounts {
+		sum += float64(amount)
+	}
+	return int64(sum / float64(len(amounts))), true
+}
+
+// record adds an amount to the account's last size amounts in the currency.
+func (a *activity) record(currency money.Code, amount int64, size int) {
+	amounts := append(a.amounts[currency], amount)
+	if len(amounts) > size {
+		amounts = amounts[len(amounts)-size:]
+	}
+	a.amounts[currency] = amounts
+}
+
+// evict forgets the least recently active tenth of the accounts once
+// MaxAccounts are tracked. It must be called with d.mu held.
+func (d *Detector) evict() {
+	if len(d.accounts) < d.cfg.MaxAccounts {
+		return
+	}
+	ids := make([]uuid.UUID, 0, len(d.accounts))
+	for id := range d.accounts {
+		ids = append(ids, id)
+	}
+	slices.SortFunc(ids, func(x, y uuid.UUID) int {
+		return d.accounts[x].lastSeen.Compare(d.accounts[y].lastSeen)
+	})
+	for _, id := range ids[:max(len(ids)/10, 1)] {
+		delete(d.accounts, id)
+	}
+}
+
+// raise freezes the account if configured, then emits the alert.
+func (d *Detector) raise(ctx context.Context, alert *events.SuspiciousActivityDetected) {
+	log := d.logger.With(
+		"account_id", alert.AccountID,
+		"rule", alert.Rule,
+		"triggered_by", alert.TriggeredBy,
+	)
+	log.Warn(
+		"🚨 suspicious activity detected",
+		"reason", alert.Reason,
+		"transaction_count", alert.TransactionCount,
+		"amount", alert.Amount,
+		"baseline", alert.Baseline,
+		"currency", alert.Currency,
+	)
+	if d.cfg.AutoFreeze {
+		frozen, err := d.freeze(ctx, alert.AccountID)
+		if err != nil {
+			log.Error("failed to freeze account", "error", err)
+		}
+		alert.Frozen = frozen
+	}
+	if err := d.bus.Emit(ctx, alert); err != nil {
+		log.Error("failed to emit SuspiciousActivityDetected", "error", err)
+	}
+}
+
+// freeze freezes the account, reporting false if it was not active.
+func (d *Detector) freeze(ctx context.Context, accountID uuid.UUID) (bool, error) {
+	frozen := false
+	err := d.uow.Do(ctx, func(uow repository.UnitOfWork) error {
+		accRepo, err := common.GetAccountRepository(uow, d.logger)
+		if err != nil {
+			return err
+		}
+		acc, err := accRepo.Get(ctx, accountID)
+		if err != nil {
+			return err
+		}
+		if !account.IsActiveStatus(acc.Status) {
+			return nil
+		}
+		status := account.StatusFrozen
+		if err := accRepo.Update(ctx, accountID, dto.AccountUpdate{Status: &status}); err != nil {
+			return err
+		}
+		frozen = true
+		return nil
+	})
+	return frozen, err
+}
@@ -0,0 +1,221 @@
+package anomaly
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/amirasaad/fintech/pkg/config"
+	"github.com/amirasaad/fintech/pkg/domain/account"
+	"github.com/amirasaad/fintech/pkg/domain/events"
+	"github.com/amirasaad/fintech/pkg/dto"
+	"github.com/amirasaad/fintech/pkg/handler/testutils"
+	"github.com/amirasaad/fintech/pkg/money"
+	"github.com/amirasaad/fintech/pkg/repository"
+	repoaccount "github.com/amirasaad/fintech/pkg/repository/account"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func testConfig() *config.Anomaly {
+	return &config.Anomaly{
+		Enabled:         true,
+		Window:          time.Hour,
+		MaxTransactions: 5,
+		SpikeFactor:     10,
+		BaselineSize:    10,
+		MinBaseline:     3,
+		Cooldown:        time.Hour,
+		MaxAccounts:     100,
+	}
+}
+
+// newDetector returns a detector whose clock advances a minute per call.
+func newDetector(h *testutils.TestHelper, cfg *config.Anomaly) *Detector {
+	d := NewDetector(h.Bus, h.UOW, h.Logger, cfg)
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	d.now = func() time.Time {
+		now = now.Add(time.Minute)
+		return now
+	}
+	return d
+}
+
+func deposit(h *testutils.TestHelper, amount int64) *events.DepositRequested {
+	return &events.DepositRequested{
+		FlowEvent: events.FlowEvent{
+			ID:        uuid.New(),
+			FlowType:  "deposit",
+			UserID:    h.UserID,
+			AccountID: h.AccountID,
+		},
+		Amount: money.NewFromData(amount, "USD"),
+	}
+}
+
+func expectAccountRepository(h *testutils.TestHelper) {
+	h.UOW.EXPECT().Do(h.Ctx, mock.Anything).RunAndReturn(
+		func(ctx context.Context, fn func(repository.UnitOfWork) error) error {
+			return fn(h.UOW)
+		},
+	).Maybe()
+	h.UOW.EXPECT().
+		GetRepository((*repoaccount.Repository)(nil)).
+		Return(h.MockAccRepo, nil).
+		Maybe()
+}
+
+func TestDetector(t *testing.T) {
+	t.Run("flags an amount spike against the account's baseline", func(t *testing.T) {
+		h := testutils.New(t)
+		handle := newDetector(h, testConfig()).Handle()
+		for _, amount := range []int64{1_000, 2_000, 1_500} {
+			require.NoError(t, handle(h.Ctx, deposit(h, amount)))
+		}
+		h.Bus.EXPECT().
+			Emit(h.Ctx, mock.MatchedBy(func(e *events.SuspiciousActivityDetected) bool {
+				return e.AccountID == h.AccountID &&
+					e.UserID == h.UserID &&
+					e.Rule == events.SuspiciousRuleAmountSpike &&
+					e.Amount == 50_000 &&
+					e.Baseline == 1_500 &&
+					e.Currency == "USD" &&
+					e.TriggeredBy == events.EventTypeDepositRequested.String() &&
+					!e.Frozen
+			})).
+			Return(nil).
+			Once()
+
+		require.NoError(t, handle(h.Ctx, deposit(h, 50_000)))
+	})
+
+	t.Run("needs a baseline before flagging spikes", func(t *testing.T) {
+		h := testutils.New(t)
+		handle := newDetector(h, testConfig()).Handle()
+		for _, amount := range []int64{1_000, 2_000, 50_000_000} {
+			require.NoError(t, handle(h.Ctx, deposit(h, amount)))
+		}
+	})
+
+	t.Run("compares amounts in the same currency only", func(t *testing.T) {
+		h := testutils.New(t)
+		handle := newDetector(h, testConfig()).Handle()
+		for range 3 {
+			require.NoError(t, handle(h.Ctx, deposit(h, 100)))
+		}
+		jpy := deposit(h, 1_000_000)
+		jpy.Amount = money.NewFromData(1_000_000, "JPY")
+		require.NoError(t, handle(h.Ctx, jpy))
+	})
+
+	t.Run("flags too many transactions within the window", func(t *testing.T) {
+		h := testutils.New(t)
+		handle := newDetector(h, testConfig()).Handle()
+		for range 5 {
+			require.NoError(t, handle(h.Ctx, deposit(h, 1_000)))
+		}
+		h.Bus.EXPECT().
+			Emit(h.Ctx, mock.MatchedBy(func(e *events.SuspiciousActivityDetected) bool {
+				return e.Rule == events.SuspiciousRuleVelocity &&
+					e.TransactionCount == 6 &&
+					e.Window == time.Hour
+			})).
+			Return(nil).
+			Once()
+
+		require.NoError(t, handle(h.Ctx, deposit(h, 1_000)))
+		// Within the cooldown, further transactions are not flagged again.
+		for range 5 {
+			require.NoError(t, handle(h.Ctx, deposit(h, 1_000)))
+		}
+	})
+
+	t.Run("forgets transactions older than the window", func(t *testing.T) {
+		h := testutils.New(t)
+		cfg := testConfig()
+		cfg.Window = 3 * time.Minute
+		handle := newDetector(h, cfg).Handle()
+		for range 20 {
+			require.NoError(t, handle(h.Ctx, deposit(h, 1_000)))
+		}
+	})
+
+	t.Run("freezes the account when configured", func(t *testing.T) {
+		h := testutils.New(t)
+		expectAccountRepository(h)
+		cfg := testConfig()
+		cfg.AutoFreeze = true
+		handle := newDetector(h, cfg).Handle()
+		for range 3 {
+			require.NoError(t, handle(h.Ctx, deposit(h, 1_000)))
+		}
+		h.MockAccRepo.EXPECT().
+			Get(h.Ctx, h.AccountID).
+			Return(&dto.AccountRead{ID: h.AccountID, Status: account.StatusActive}, nil).
+			Once()
+		h.MockAccRepo.EXPECT().
+			Update(h.Ctx, h.AccountID, mock.MatchedBy(func(u dto.AccountUpdate) bool {
+				return u.Status != nil && *u.Status == account.StatusFrozen
+			})).
+			Return(nil).
+			Once()
+		h.Bus.EXPECT().
+			Emit(h.Ctx, mock.MatchedBy(func(e *events.SuspiciousActivityDetected) bool {
+				return e.Frozen
+			})).
+			Return(nil).
+			Once()
+
+		require.NoError(t, handle(h.Ctx, deposit(h, 100_000)))
+	})
+
+	t.Run("still alerts when the account cannot be frozen", func(t *testing.T) {
+		h := testutils.New(t)
+		expectAccountRepository(h)
+		cfg := testConfig()
+		cfg.AutoFreeze = true
+		handle := newDetector(h, cfg).Handle()
+		for range 3 {
+			require.NoError(t, handle(h.Ctx, deposit(h, 1_000)))
+		}
+		h.MockAccRepo.EXPECT().
+			Get(h.Ctx, h.AccountID).
+			Return(nil, errors.New("db down")).
+			Once()
+		h.Bus.EXPECT().
+			Emit(h.Ctx, mock.MatchedBy(func(e *events.SuspiciousActivityDetected) bool {
+				return !e.Frozen
+			})).
+			Return(errors.New("bus down")).
+			Once()
+
+		assert.NoError(t, handle(h.Ctx, deposit(h, 100_000)))
+	})
+
+	t.Run("forgets the least recently active accounts", func(t *testing.T) {
+		h := testutils.New(t)
+		cfg := testConfig()
+		cfg.MaxAccounts = 10
+		d := newDetector(h, cfg)
+		handle := d.Handle()
+		first := deposit(h, 1_000)
+		first.AccountID = uuid.New()
+		require.NoError(t, handle(h.Ctx, first))
+		for range 20 {
+			e := deposit(h, 1_000)
+			e.AccountID = uuid.New()
+			require.NoError(t, handle(h.Ctx, e))
+		}
+		assert.LessOrEqual(t, len(d.accounts), cfg.MaxAccounts)
+		assert.NotContains(t, d.accounts, first.AccountID)
+	})
+
+	t.Run("ignores other events", func(t *testing.T) {
+		h := testutils.New(t)
+		handle := newDetector(h, testConfig()).Handle()
+		require.NoError(t, handle(h.Ctx, &events.PaymentCompleted{}))
+	})
+}
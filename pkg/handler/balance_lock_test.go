@@ -0,0 +1,255 @@
+package handler_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/amirasaad/fintech/infra/eventbus"
+	"github.com/amirasaad/fintech/pkg/domain/account"
+	"github.com/amirasaad/fintech/pkg/domain/events"
+	"github.com/amirasaad/fintech/pkg/dto"
+	"github.com/amirasaad/fintech/pkg/handler/account/transfer"
+	"github.com/amirasaad/fintech/pkg/handler/payment"
+	"github.com/amirasaad/fintech/pkg/money"
+	"github.com/amirasaad/fintech/pkg/repository"
+	repoaccount "github.com/amirasaad/fintech/pkg/repository/account"
+	repotransaction "github.com/amirasaad/fintech/pkg/repository/transaction"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// TestBalanceUpdates_AsyncBusKeepBalances applies deposits and transfers
+// between two accounts concurrently on the async bus, where nothing
+// serializes the handlers but the account row locks, and delivers every
+// deposit twice.
+func TestBalanceUpdates_AsyncBusKeepBalances(t *testing.T) {
+	logger := slog.New(slog.DiscardHandler)
+	a, b := uuid.New(), uuid.New()
+	l := newLedger(map[uuid.UUID]int64{a: 100_00, b: 100_00})
+
+	bus := eventbus.NewWithMemoryAsync(logger)
+	bus.Register(events.EventTypePaymentCompleted, payment.HandleCompleted(bus, l, logger))
+	bus.Register(events.EventTypeTransferCompleted, transfer.HandleCompleted(bus, l, logger))
+
+	ctx := context.Background()
+	five, err := money.New(5, money.USD)
+	require.NoError(t, err)
+	one, err := money.New(1, money.USD)
+	require.NoError(t, err)
+	deposit := func(accountID uuid.UUID) {
+		txID := l.createPending(accountID)
+		paymentID := "pi_" + txID.String()
+		pc := events.NewPaymentCompleted(
+			&events.FlowEvent{FlowType: "deposit", UserID: accountID, AccountID: accountID},
+			events.WithPaymentID(&paymentID),
+		)
+		pc.TransactionID = txID
+		pc.Amount = five
+		require.NoError(t, bus.Emit(ctx, pc))
+		require.NoError(t, bus.Emit(ctx, pc))
+	}
+	send := func(from, to uuid.UUID) {
+		tr := events.NewTransferRequested(
+			from, from, uuid.New(),
+			events.WithTransferRequestedAmount(one),
+			events.WithTransferDestAccountID(to),
+		)
+		tr.TransactionID = l.createPending(from)
+		require.NoError(t, bus.Emit(ctx, events.NewTransferCompleted(tr)))
+	}
+
+	const n = 20
+	for range n {
+		deposit(a)
+		deposit(b)
+		send(a, b)
+		send(b, a)
+	}
+
+	require.Eventually(t, l.settled, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, int64(100_00+n*5_00), l.balance(a))
+	assert.Equal(t, int64(100_00+n*5_00), l.balance(b))
+}
+
+// ledger is an in-memory unit of work whose GetForUpdate locks the account
+// row until the unit of work ends, like SELECT ... FOR UPDATE does.
+type ledger struct {
+	mu       sync.Mutex
+	balances map[uuid.UUID]int64
+	rows     map[uuid.UUID]*sync.Mutex
+	txs      map[uuid.UUID]dto.TransactionRead
+	running  atomic.Int64
+}
+
+func newLedger(balances map[uuid.UUID]int64) *ledger {
+	l := &ledger{
+		balances: balances,
+		rows:     make(map[uuid.UUID]*sync.Mutex),
+		txs:      make(map[uuid.UUID]dto.TransactionRead),
+	}
+	for id := range balances {
+		l.rows[id] = &sync.Mutex{}
+	}
+	return l
+}
+
+func (l *ledger) Do(_ context.Context, fn func(repository.UnitOfWork) error) error {
+	l.running.Add(1)
+	defer l.running.Add(-1)
+	u := &ledgerUnit{ledger: l}
+	defer u.release()
+	return fn(u)
+}
+
+func (l *ledger) GetRepository(any) (any, error) {
+	return nil, errors.New("repositories are only available in a unit of work")
+}
+
+func (l *ledger) createPending(accountID uuid.UUID) uuid.UUID {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	id := uuid.New()
+	l.txs[id] = dto.TransactionRead{
+		ID:        id,
+		AccountID: accountID,
+		Status:    string(account.TransactionStatusPending),
+	}
+	return id
+}
+
+func (l *ledger) balance(id uuid.UUID) int64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.balances[id]
+}
+
+// settled reports whether every transaction completed and no unit of work
+// is still writing.
+func (l *ledger) settled() bool {
+	if l.running.Load() > 0 {
+		return false
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, tx := range l.txs {
+		if tx.Status != string(account.TransactionStatusCompleted) {
+			return false
+		}
+	}
+	return true
+}
+
+type ledgerUnit struct {
+	*ledger
+	held []*sync.Mutex
+}
+
+func (u *ledgerUnit) GetRepository(repoType any) (any, error) {
+	switch repoType.(type) {
+	case *repoaccount.Repository:
+		return ledgerAccounts{u: u}, nil
+	case *repotransaction.Repository:
+		return ledgerTransactions{l: u.ledger}, nil
+	}
+	return nil, fmt.Errorf("unexpected repository type %T", repoType)
+}
+
+func (u *ledgerUnit) release() {
+	for _, row := range u.held {
+		row.Unlock()
+	}
+}
+
+// ledgerAccounts implements the account methods the handlers use; calling
+// any other panics.
+type ledgerAccounts struct {
+	repoaccount.Repository
+	u *ledgerUnit
+}
+
+func (r ledgerAccounts) Get(_ context.Context, id uuid.UUID) (*dto.AccountRead, error) {
+	r.u.mu.Lock()
+	defer r.u.mu.Unlock()
+	return &dto.AccountRead{
+		ID:       id,
+		UserID:   id,
+		Balance:  float64(r.u.balances[id]) / 100,
+		Currency: "USD",
+		Status:   "active",
+	}, nil
+}
+
+func (r ledgerAccounts) GetForUpdate(ctx context.Context, id uuid.UUID) (*dto.AccountRead, error) {
+	r.u.mu.Lock()
+	row := r.u.rows[id]
+	r.u.mu.Unlock()
+	row.Lock()
+	r.u.held = append(r.u.held, row)
+	return r.Get(ctx, id)
+}
+
+func (r ledgerAccounts) Update(_ context.Context, id uuid.UUID, update dto.AccountUpdate) error {
+	// Widen the gap between reading the balance and writing it, so updates
+	// that are not serialized lose each other.
+	time.Sleep(100 * time.Microsecond)
+	r.u.mu.Lock()
+	defer r.u.mu.Unlock()
+	if update.Balance != nil {
+		r.u.balances[id] = *update.Balance
+	}
+	return nil
+}
+
+// ledgerTransactions implements the transaction methods the handlers use;
+// calling any other panics.
+type ledgerTransactions struct {
+	repotransaction.Repository
+	l *ledger
+}
+
+func (r ledgerTransactions) GetByPaymentID(context.Context, string) (*dto.TransactionRead, error) {
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r ledgerTransactions) Get(_ context.Context, id uuid.UUID) (*dto.TransactionRead, error) {
+	r.l.mu.Lock()
+	defer r.l.mu.Unlock()
+	tx, ok := r.l.txs[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &tx, nil
+}
+
+func (r ledgerTransactions) Create(_ context.Context, create dto.TransactionCreate) error {
+	r.l.mu.Lock()
+	defer r.l.mu.Unlock()
+	r.l.txs[create.ID] = dto.TransactionRead{
+		ID:        create.ID,
+		AccountID: create.AccountID,
+		Status:    create.Status,
+	}
+	return nil
+}
+
+func (r ledgerTransactions) Update(_ context.Context, id uuid.UUID, update dto.TransactionUpdate) error {
+	r.l.mu.Lock()
+	defer r.l.mu.Unlock()
+	tx := r.l.txs[id]
+	if update.Status != nil {
+		tx.Status = *update.Status
+	}
+	if update.PaymentID != nil {
+		tx.PaymentID = update.PaymentID
+	}
+	r.l.txs[id] = tx
+	return nil
+}
@@ -0,0 +1,100 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/amirasaad/fintech/pkg/domain/account"
+	"github.com/amirasaad/fintech/pkg/dto"
+	"github.com/amirasaad/fintech/pkg/money"
+	"github.com/amirasaad/fintech/pkg/repository"
+	"github.com/google/uuid"
+)
+
+// Money sources of withdrawal transactions.
+const (
+	// MoneySourceWithdraw is the money source of the transactions of
+	// withdrawals that were not held for approval.
+	MoneySourceWithdraw = "withdraw"
+	// MoneySourceWithdrawHold is the money source of the transactions
+	// holding the amount of withdrawals awaiting approval. Once a withdrawal
+	// is approved its hold stays completed and stands for the withdrawal's
+	// debit.
+	MoneySourceWithdrawHold = "withdraw_hold"
+)
+
+// WithdrawMoneySources returns the money sources of withdrawal transactions.
+// Anything counting or listing withdrawals must match all of them, or
+// approved held withdrawals are missed.
+func WithdrawMoneySources() []string {
+	return []string{MoneySourceWithdraw, MoneySourceWithdrawHold}
+}
+
+// IsWithdrawMoneySource reports whether moneySource is that of a withdrawal.
+func IsWithdrawMoneySource(moneySource string) bool {
+	return moneySource == MoneySourceWithdraw || moneySource == MoneySourceWithdrawHold
+}
+
+// ReleaseWithdrawalHold returns the amount debited by the hold transaction
+// txID of an approved withdrawal that failed to its account, and marks the
+// hold failed with paymentID, if set. It runs in the caller's unit of work
+// and reports false, changing nothing, if txID is not a completed hold, so a
+// failure reported twice releases the hold once.
+func ReleaseWithdrawalHold(
+	ctx context.Context,
+	uow repository.UnitOfWork,
+	txID uuid.UUID,
+	paymentID *string,
+	log *slog.Logger,
+) (bool, error) {
+	txRepo, err := GetTransactionRepository(uow, log)
+	if err != nil {
+		return false, err
+	}
+	accRepo, err := GetAccountRepository(uow, log)
+	if err != nil {
+		return false, err
+	}
+	hold, err := txRepo.Get(ctx, txID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get hold transaction: %w", err)
+	}
+	// Read the hold again under the account lock, so concurrent releases
+	// of it run one at a time and only the first finds it completed.
+	acc, err := accRepo.GetForUpdate(ctx, hold.AccountID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get account: %w", err)
+	}
+	if hold, err = txRepo.Get(ctx, txID); err != nil {
+		return false, fmt.Errorf("failed to get hold transaction: %w", err)
+	}
+	if hold.MoneySource != MoneySourceWithdrawHold ||
+		hold.Status != string(account.TransactionStatusCompleted) {
+		return false, nil
+	}
+
+	held, err := money.New(hold.Amount, money.Code(hold.Currency))
+	if err != nil {
+		return false, err
+	}
+	balance, err := money.New(acc.Balance, money.Code(acc.Currency))
+	if err != nil {
+		return false, err
+	}
+	if balance, err = balance.Add(held.Abs()); err != nil {
+		return false, err
+	}
+	amount := balance.Amount()
+	if err := accRepo.Update(ctx, acc.ID, dto.AccountUpdate{Balance: &amount}); err != nil {
+		return false, fmt.Errorf("failed to release withdrawal hold: %w", err)
+	}
+	failed := string(account.TransactionStatusFailed)
+	if err := txRepo.Update(ctx, txID, dto.TransactionUpdate{
+		Status:    &failed,
+		PaymentID: paymentID,
+	}); err != nil {
+		return false, fmt.Errorf("failed to update hold transaction: %w", err)
+	}
+	return true, nil
+}
@@ -0,0 +1,34 @@
+package common
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/amirasaad/fintech/pkg/dto"
+	"github.com/amirasaad/fintech/pkg/repository/account"
+	"github.com/google/uuid"
+)
+
+// LockAccounts reads the accounts with GetForUpdate, locking their rows until
+// the unit of work ends, and returns them by ID. Rows are locked in ID order
+// so that units of work locking the same accounts cannot deadlock.
+func LockAccounts(
+	ctx context.Context,
+	accRepo account.Repository,
+	ids ...uuid.UUID,
+) (map[uuid.UUID]*dto.AccountRead, error) {
+	ordered := slices.Clone(ids)
+	slices.SortFunc(ordered, func(a, b uuid.UUID) int { return bytes.Compare(a[:], b[:]) })
+	ordered = slices.Compact(ordered)
+	accounts := make(map[uuid.UUID]*dto.AccountRead, len(ordered))
+	for _, id := range ordered {
+		acc, err := accRepo.GetForUpdate(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to lock account %s: %w", id, err)
+		}
+		accounts[id] = acc
+	}
+	return accounts, nil
+}
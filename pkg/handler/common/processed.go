@@ -0,0 +1,115 @@
+package common
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/amirasaad/fintech/pkg/domain/events"
+	"github.com/amirasaad/fintech/pkg/eventbus"
+	"github.com/amirasaad/fintech/pkg/repository"
+	"github.com/amirasaad/fintech/pkg/repository/processedevent"
+)
+
+// GetProcessedEventRepository returns the processed event repository of uow.
+func GetProcessedEventRepository(
+	uow repository.UnitOfWork,
+	log *slog.Logger,
+) (
+	processedevent.Repository,
+	error,
+) {
+	repoAny, err := uow.GetRepository(
+		(*processedevent.Repository)(nil),
+	)
+	if err != nil {
+		log.Error(
+			"failed to get processed event repository",
+			"error", err,
+		)
+		return nil, err
+	}
+	if repo, ok := repoAny.(processedevent.Repository); ok {
+		return repo, nil
+	}
+	return nil, ErrInvalidRepositoryType
+}
+
+// ProcessOnce runs fn in a unit of work that also records that handlerName
+// processed the event identified by eventKey. If the pair is already
+// recorded, fn is skipped and ProcessOnce reports false. fn receives the
+// transaction's unit of work, so its writes commit or roll back together with
+// the record; when fn fails the record is rolled back and a redelivered event
+// runs fn again.
+//
+// Calls to external systems made by fn are not undone by a rollback. If the
+// commit fails after such a call, a redelivery repeats it, so those calls
+// should also pass the provider an idempotency key where it supports one.
+func ProcessOnce(
+	ctx context.Context,
+	uow repository.UnitOfWork,
+	handlerName, eventKey string,
+	log *slog.Logger,
+	fn func(uow repository.UnitOfWork) error,
+) (bool, error) {
+	processed := false
+	err := uow.Do(ctx, func(uow repository.UnitOfWork) error {
+		repo, err := GetProcessedEventRepository(uow, log)
+		if err != nil {
+			return err
+		}
+		marked, err := repo.MarkProcessed(ctx, handlerName, eventKey)
+		if err != nil || !marked {
+			return err
+		}
+		if err := fn(uow); err != nil {
+			return err
+		}
+		processed = true
+		return nil
+	})
+	return processed, err
+}
+
+// WithProcessedEvents wraps a handler so that it runs once per event key,
+// even when the event is redelivered after a restart or from the DLQ. Unlike
+// WithIdempotency, which remembers keys in memory, it records them in the
+// database through ProcessOnce. Events without a key are always handled.
+//
+// The handler runs inside the transaction holding the record, but writes it
+// makes through its own unit of work commit separately. Handlers that write
+// to the database should call ProcessOnce themselves instead.
+func WithProcessedEvents(
+	handler eventbus.HandlerFunc,
+	uow repository.UnitOfWork,
+	keyExtractor KeyExtractor,
+	handlerName string,
+	logger *slog.Logger,
+) eventbus.HandlerFunc {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return func(ctx context.Context, e events.Event) error {
+		key := keyExtractor(e)
+		if key == "" {
+			return handler(ctx, e)
+		}
+
+		log := logger.With(
+			"handler", handlerName,
+			"event_type", e.Type(),
+			"idempotency_key", key,
+		)
+		processed, err := ProcessOnce(ctx, uow, handlerName, key, log,
+			func(repository.UnitOfWork) error {
+				return handler(ctx, e)
+			},
+		)
+		if err != nil {
+			return err
+		}
+		if !processed {
+			log.Info("🔁 [SKIP] Event already processed")
+		}
+		return nil
+	}
+}
@@ -0,0 +1,171 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"maps"
+	"testing"
+
+	"github.com/amirasaad/fintech/pkg/domain/events"
+	"github.com/amirasaad/fintech/pkg/repository"
+	"github.com/amirasaad/fintech/pkg/repository/processedevent"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeProcessedUoW is a unit of work over an in-memory processed event table
+// whose Do discards the records of failed transactions.
+type fakeProcessedUoW struct {
+	records map[string]bool
+}
+
+func (u *fakeProcessedUoW) Do(
+	_ context.Context,
+	fn func(uow repository.UnitOfWork) error,
+) error {
+	tx := &fakeProcessedUoW{records: maps.Clone(u.records)}
+	if err := fn(tx); err != nil {
+		return err
+	}
+	u.records = tx.records
+	return nil
+}
+
+func (u *fakeProcessedUoW) GetRepository(repoType any) (any, error) {
+	if repoType != (*processedevent.Repository)(nil) {
+		return nil, errors.New("unsupported repository type")
+	}
+	return u, nil
+}
+
+func (u *fakeProcessedUoW) MarkProcessed(
+	_ context.Context,
+	handler, eventKey string,
+) (bool, error) {
+	key := handler + "/" + eventKey
+	if u.records[key] {
+		return false, nil
+	}
+	u.records[key] = true
+	return true, nil
+}
+
+func TestWithProcessedEvents(t *testing.T) {
+	t.Parallel()
+
+	keyOf := func(e events.Event) string {
+		return e.(*events.PaymentInitiated).TransactionID.String()
+	}
+	newEvent := func(txID uuid.UUID) events.Event {
+		return &events.PaymentInitiated{TransactionID: txID}
+	}
+
+	t.Run("redelivered event does not repeat the side effect", func(t *testing.T) {
+		t.Parallel()
+		uow := &fakeProcessedUoW{records: map[string]bool{}}
+		calls := 0
+		handler := WithProcessedEvents(
+			func(context.Context, events.Event) error {
+				calls++
+				return nil
+			},
+			uow, keyOf, "HandleInitiated", slog.Default(),
+		)
+
+		txID := uuid.New()
+		require.NoError(t, handler(context.Background(), newEvent(txID)))
+		// Redelivered, e.g. replayed from the DLQ by another instance.
+		require.NoError(t, handler(context.Background(), newEvent(txID)))
+		assert.Equal(t, 1, calls)
+
+		require.NoError(t, handler(context.Background(), newEvent(uuid.New())))
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("failed handling is retried on redelivery", func(t *testing.T) {
+		t.Parallel()
+		uow := &fakeProcessedUoW{records: map[string]bool{}}
+		calls := 0
+		handler := WithProcessedEvents(
+			func(context.Context, events.Event) error {
+				calls++
+				if calls == 1 {
+					return errors.New("provider unavailable")
+				}
+				return nil
+			},
+			uow, keyOf, "HandleInitiated", slog.Default(),
+		)
+
+		txID := uuid.New()
+		require.Error(t, handler(context.Background(), newEvent(txID)))
+		require.NoError(t, handler(context.Background(), newEvent(txID)))
+		require.NoError(t, handler(context.Background(), newEvent(txID)))
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("handlers are tracked separately", func(t *testing.T) {
+		t.Parallel()
+		uow := &fakeProcessedUoW{records: map[string]bool{}}
+		calls := 0
+		count := func(context.Context, events.Event) error {
+			calls++
+			return nil
+		}
+		first := WithProcessedEvents(count, uow, keyOf, "first", slog.Default())
+		second := WithProcessedEvents(count, uow, keyOf, "second", slog.Default())
+
+		e := newEvent(uuid.New())
+		require.NoError(t, first(context.Background(), e))
+		require.NoError(t, second(context.Background(), e))
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("events without a key are always handled", func(t *testing.T) {
+		t.Parallel()
+		uow := &fakeProcessedUoW{records: map[string]bool{}}
+		calls := 0
+		handler := WithProcessedEvents(
+			func(context.Context, events.Event) error {
+				calls++
+				return nil
+			},
+			uow, func(events.Event) string { return "" }, "HandleInitiated", nil,
+		)
+
+		e := newEvent(uuid.Nil)
+		require.NoError(t, handler(context.Background(), e))
+		require.NoError(t, handler(context.Background(), e))
+		assert.Equal(t, 2, calls)
+		assert.Empty(t, uow.records)
+	})
+}
+
+func TestProcessOnce_RollsBackWithWork(t *testing.T) {
+	t.Parallel()
+	uow := &fakeProcessedUoW{records: map[string]bool{}}
+
+	processed, err := ProcessOnce(context.Background(), uow, "h", "k", slog.Default(),
+		func(repository.UnitOfWork) error { return errors.New("boom") },
+	)
+	require.Error(t, err)
+	assert.False(t, processed)
+	assert.Empty(t, uow.records)
+
+	processed, err = ProcessOnce(context.Background(), uow, "h", "k", slog.Default(),
+		func(repository.UnitOfWork) error { return nil },
+	)
+	require.NoError(t, err)
+	assert.True(t, processed)
+
+	processed, err = ProcessOnce(context.Background(), uow, "h", "k", slog.Default(),
+		func(repository.UnitOfWork) error {
+			t.Fatal("already processed event handled again")
+			return nil
+		},
+	)
+	require.NoError(t, err)
+	assert.False(t, processed)
+}
@@ -0,0 +1,24 @@
+package common
+
+import (
+	"log/slog"
+
+	"github.com/amirasaad/fintech/pkg/domain/account"
+	"github.com/google/uuid"
+)
+
+// CanTransition reports whether the transaction txID may move from status from
+// to status to. It logs a warning if not, so that an event arriving out of
+// order is ignored instead of moving the transaction backward.
+func CanTransition(log *slog.Logger, txID uuid.UUID, from, to string) bool {
+	if account.TransactionStatus(from).CanTransitionTo(account.TransactionStatus(to)) {
+		return true
+	}
+	log.Warn(
+		"⚠️ Ignoring invalid transaction status transition",
+		"transaction_id", txID,
+		"from_status", from,
+		"to_status", to,
+	)
+	return false
+}
@@ -14,13 +14,15 @@ import (
 )
 
 // HandleRequested processes ConversionRequestedEvent and
-// delegates to a flow-specific factory to create the next event.
+// delegates to a flow-specific factory to create the next event. opts
+// configure the exchange service converting the amounts, e.g. its rounding.
 func HandleRequested(
 	bus eventbus.Bus,
 	exchangeRegistry registry.Provider,
 	exchangeRateProvider exchangeprovider.Exchange,
 	logger *slog.Logger,
 	factories map[string]EventFactory,
+	opts ...exchange.Option,
 ) func(ctx context.Context, e events.Event) error {
 	return func(ctx context.Context, e events.Event) error {
 		log := logger.With(
@@ -52,7 +54,7 @@ func HandleRequested(
 			return fmt.Errorf("unknown flow type %s", ccr.FlowType)
 		}
 
-		srv := exchange.New(exchangeRegistry, exchangeRateProvider, log)
+		srv := exchange.New(exchangeRegistry, exchangeRateProvider, log, opts...)
 
 		convertedMoney,
 			convInfo,
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log/slog"
 
+	"github.com/amirasaad/fintech/pkg/domain/account"
 	"github.com/amirasaad/fintech/pkg/domain/events"
 	"github.com/amirasaad/fintech/pkg/eventbus"
 	"github.com/amirasaad/fintech/pkg/handler/common"
@@ -13,9 +14,12 @@ import (
 
 // HandleCalculated handles FeesCalculated events.
 // It updates the transaction with the calculated fees and deducts them from the account balance.
+// Provider fees on accounts whose fee policy, or absorption when they have
+// none, is absorb are recorded as platform fees instead of being deducted.
 func HandleCalculated(
 	uow repository.UnitOfWork,
 	logger *slog.Logger,
+	absorption account.FeeAbsorption,
 ) eventbus.HandlerFunc {
 	return func(
 		ctx context.Context,
@@ -65,7 +69,7 @@ func HandleCalculated(
 			}
 
 			// Create fee calculator and apply fees
-			calculator := NewFeeCalculator(txRepo, accRepo, log)
+			calculator := NewFeeCalculator(txRepo, accRepo, log).WithFeeAbsorption(absorption)
 			if err := calculator.ApplyFees(ctx, fc.TransactionID, fc.Fee); err != nil {
 				log.Error("failed to apply fees",
 					"error", err,
@@ -85,7 +85,7 @@ func TestCalculated(t *testing.T) {
 					h.MockTxRepo.EXPECT().
 						Get(ctx, tx.ID).
 						Return(tx, nil).
-						Once()
+						Times(2)
 
 					h.MockTxRepo.EXPECT().
 						Update(ctx, tx.ID, mock.AnythingOfType("dto.TransactionUpdate")).
@@ -93,7 +93,7 @@ func TestCalculated(t *testing.T) {
 						Once()
 
 					h.MockAccRepo.EXPECT().
-						Get(ctx, acc.ID).
+						GetForUpdate(ctx, acc.ID).
 						Return(acc, nil).
 						Once()
 
@@ -124,7 +124,7 @@ func TestCalculated(t *testing.T) {
 			)
 
 			err := h.WithHandler(
-				HandleCalculated(h.UOW, h.Logger),
+				HandleCalculated(h.UOW, h.Logger, account.FeeAbsorptionPassThrough),
 			).Handler(ctx, event)
 
 			// Verify results
@@ -189,7 +189,7 @@ func TestCalculated(t *testing.T) {
 				h.MockTxRepo.EXPECT().
 					Get(ctx, transactionID).
 					Return(tx, nil).
-					Once()
+					Times(2)
 
 				// Expect transaction update
 				h.MockTxRepo.EXPECT().
@@ -202,7 +202,7 @@ func TestCalculated(t *testing.T) {
 					Once()
 
 				h.MockAccRepo.EXPECT().
-					Get(ctx, accountID).
+					GetForUpdate(ctx, accountID).
 					Return(acc, nil).
 					Once()
 
@@ -228,7 +228,9 @@ func TestCalculated(t *testing.T) {
 		err := h.WithHandler(
 			HandleCalculated(
 				h.UOW,
-				h.Logger)).Handler(ctx, event)
+				h.Logger,
+				account.FeeAbsorptionPassThrough,
+			)).Handler(ctx, event)
 		require.Error(t, err)
 		assert.Equal(t, expectedErr, err)
 	})
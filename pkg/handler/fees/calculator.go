@@ -19,6 +19,9 @@ type FeeCalculator struct {
 	txRepo  repotransaction.Repository
 	accRepo repoaccount.Repository
 	logger  *slog.Logger
+	// absorption applies to provider fees on accounts without a fee policy
+	// of their own.
+	absorption account.FeeAbsorption
 }
 
 // NewFeeCalculator creates a new FeeCalculator instance
@@ -36,13 +39,25 @@ func NewFeeCalculator(
 	}
 
 	return &FeeCalculator{
-		txRepo:  txRepo,
-		accRepo: accRepo,
-		logger:  logger,
+		txRepo:     txRepo,
+		accRepo:    accRepo,
+		logger:     logger,
+		absorption: account.FeeAbsorptionPassThrough,
 	}
 }
 
-// ApplyFees applies the calculated fees to a transaction and updates the account balance
+// WithFeeAbsorption sets the policy applied to provider fees on accounts
+// without a fee policy of their own; pass_through if never set or empty.
+func (fc *FeeCalculator) WithFeeAbsorption(policy account.FeeAbsorption) *FeeCalculator {
+	if policy != "" {
+		fc.absorption = policy
+	}
+	return fc
+}
+
+// ApplyFees applies the calculated fees to a transaction and updates the account balance.
+// A provider fee on an account whose fee policy is absorb is recorded as a
+// platform fee instead and leaves the balance untouched.
 func (fc *FeeCalculator) ApplyFees(
 	ctx context.Context,
 	transactionID uuid.UUID,
@@ -55,24 +70,106 @@ func (fc *FeeCalculator) ApplyFees(
 		return err
 	}
 
+	// Get the account, whose policy decides who bears provider fees, locking
+	// its row for the balance update. The transaction is read again under the
+	// lock since another fee may have been added to it meanwhile.
+	acc, err := fc.accRepo.GetForUpdate(ctx, tx.AccountID)
+	if err != nil {
+		fc.logger.Error("failed to get account", "error", err, "account_id", tx.AccountID)
+		return err
+	}
+	if tx, err = fc.txRepo.Get(ctx, transactionID); err != nil {
+		fc.logger.Error("failed to get transaction", "error", err, "transaction_id", transactionID)
+		return err
+	}
+
+	var policy account.FeeAbsorption
+	if fee.Type == account.FeeProvider {
+		policy = fc.absorptionFor(acc)
+	}
+	if policy == account.FeeAbsorptionAbsorb {
+		return fc.recordPlatformFee(ctx, tx, fee)
+	}
+
 	// Update transaction with new fee
-	if err := fc.updateTransactionFee(ctx, tx, fee); err != nil {
+	if err := fc.updateTransactionFee(ctx, tx, fee, policy); err != nil {
 		return err
 	}
 
 	// Update account balance with fee deduction
-	if err := fc.updateAccountBalance(ctx, tx.AccountID, fee.Amount); err != nil {
+	if err := fc.updateAccountBalance(ctx, acc, fee.Amount); err != nil {
 		return err
 	}
 
 	return nil
 }
 
-// updateTransactionFee updates a transaction with the calculated fee
+// absorptionFor returns the fee absorption policy of the account, or the
+// default if it has none or an unknown one.
+func (fc *FeeCalculator) absorptionFor(acc *dto.AccountRead) account.FeeAbsorption {
+	if acc.FeePolicy == "" {
+		return fc.absorption
+	}
+	policy, err := account.ParseFeeAbsorption(acc.FeePolicy)
+	if err != nil {
+		fc.logger.Warn("ignoring invalid account fee policy",
+			"account_id", acc.ID,
+			"fee_policy", acc.FeePolicy,
+		)
+		return fc.absorption
+	}
+	return policy
+}
+
+// recordPlatformFee records a provider fee the platform absorbs on the
+// transaction, without deducting it from the account.
+func (fc *FeeCalculator) recordPlatformFee(
+	ctx context.Context,
+	tx *dto.TransactionRead,
+	fee account.Fee,
+) error {
+	if tx.Currency == "" {
+		err := fmt.Errorf("transaction %s has no currency set", tx.ID)
+		fc.logger.Error("transaction has no currency", "error", err, "transaction_id", tx.ID)
+		return err
+	}
+	platformFee, err := money.New(tx.PlatformFee, money.Code(tx.Currency))
+	if err != nil {
+		return fmt.Errorf("invalid transaction platform fee amount: %w", err)
+	}
+	if platformFee, err = platformFee.Add(fee.Amount); err != nil {
+		return fmt.Errorf("failed to add platform fee: %w", err)
+	}
+
+	amount := platformFee.Amount()
+	policy := string(account.FeeAbsorptionAbsorb)
+	if err := fc.txRepo.Update(ctx, tx.ID, dto.TransactionUpdate{
+		PlatformFee: &amount,
+		FeePolicy:   &policy,
+	}); err != nil {
+		fc.logger.Error("failed to record platform fee",
+			"error", err,
+			"transaction_id", tx.ID,
+			"fee", fee.Amount,
+		)
+		return fmt.Errorf("failed to update transaction: %w", err)
+	}
+
+	fc.logger.Info("provider fee absorbed by the platform",
+		"transaction_id", tx.ID,
+		"account_id", tx.AccountID,
+		"platform_fee", platformFee,
+	)
+	return nil
+}
+
+// updateTransactionFee updates a transaction with the calculated fee and,
+// for provider fees, the absorption policy applied.
 func (fc *FeeCalculator) updateTransactionFee(
 	ctx context.Context,
 	tx *dto.TransactionRead,
 	fee account.Fee,
+	policy account.FeeAbsorption,
 ) error {
 	// Validate currency is set
 	if tx.Currency == "" {
@@ -111,6 +208,10 @@ func (fc *FeeCalculator) updateTransactionFee(
 	// Update the transaction
 	totalFeeAmount := totalFee.Amount()
 	updateTx := dto.TransactionUpdate{Fee: &totalFeeAmount}
+	if policy != "" {
+		applied := string(policy)
+		updateTx.FeePolicy = &applied
+	}
 
 	if err := fc.txRepo.Update(ctx, tx.ID, updateTx); err != nil {
 		fc.logger.Error("failed to update transaction with fees",
@@ -131,15 +232,10 @@ func (fc *FeeCalculator) updateTransactionFee(
 // updateAccountBalance updates an account balance by deducting the fee
 func (fc *FeeCalculator) updateAccountBalance(
 	ctx context.Context,
-	accountID uuid.UUID,
+	acc *dto.AccountRead,
 	feeAmount *money.Money,
 ) error {
-	// Get the account
-	acc, err := fc.accRepo.Get(ctx, accountID)
-	if err != nil {
-		fc.logger.Error("failed to get account", "error", err, "account_id", accountID)
-		return err
-	}
+	accountID := acc.ID
 
 	// Convert to domain model to use money operations
 	domainAcc, err := mapper.MapAccountReadToDomain(acc)
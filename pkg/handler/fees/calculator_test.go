@@ -14,7 +14,6 @@ import (
 	repotransaction "github.com/amirasaad/fintech/pkg/repository/transaction"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
-	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
 
@@ -47,12 +46,14 @@ func TestFeeCalculator_ApplyFees(t *testing.T) {
 				h.MockTxRepo.EXPECT().
 					Get(h.Ctx, tx.ID).
 					Return(tx, nil).
-					Once()
+					Times(2)
 
 				// Use the fee amount from the test data (100.00 in cents)
 				feeAmount := int64(10000) // $100.00 in cents
+				policy := string(account.FeeAbsorptionPassThrough)
 				updateTx := dto.TransactionUpdate{
-					Fee: &feeAmount,
+					Fee:       &feeAmount,
+					FeePolicy: &policy,
 				}
 				h.MockTxRepo.EXPECT().
 					Update(h.Ctx, tx.ID, updateTx).
@@ -67,7 +68,7 @@ func TestFeeCalculator_ApplyFees(t *testing.T) {
 					Status:  nil,
 				}
 				h.MockAccRepo.EXPECT().
-					Get(h.Ctx, acc.ID).
+					GetForUpdate(h.Ctx, acc.ID).
 					Return(acc, nil).
 					Once()
 
@@ -124,15 +125,10 @@ func TestFeeCalculator_ApplyFees(t *testing.T) {
 					Return(tx, nil).
 					Once()
 
-				// The implementation will try to update the transaction with the fee
-				// even if the account is not found, so we need to expect this call
-				h.MockTxRepo.EXPECT().
-					Update(h.Ctx, tx.ID, mock.AnythingOfType("dto.TransactionUpdate")).
-					Return(nil).
-					Once()
-
+				// The account is read before the transaction is updated, so
+				// nothing is written when it is not found
 				h.MockAccRepo.EXPECT().
-					Get(h.Ctx, tx.AccountID).
+					GetForUpdate(h.Ctx, tx.AccountID).
 					Return(nil, account.ErrAccountNotFound).
 					Once()
 			},
@@ -253,3 +249,89 @@ func TestNewFeeCalculator(t *testing.T) {
 		})
 	}
 }
+
+func TestFeeCalculator_ApplyFees_Absorption(t *testing.T) {
+	tests := []struct {
+		name          string
+		defaultPolicy account.FeeAbsorption
+		accountPolicy string
+		feeType       account.FeeType
+		wantAbsorbed  bool
+	}{
+		{
+			name:          "global absorb",
+			defaultPolicy: account.FeeAbsorptionAbsorb,
+			feeType:       account.FeeProvider,
+			wantAbsorbed:  true,
+		},
+		{
+			name:          "account absorb overrides global pass-through",
+			defaultPolicy: account.FeeAbsorptionPassThrough,
+			accountPolicy: "absorb",
+			feeType:       account.FeeProvider,
+			wantAbsorbed:  true,
+		},
+		{
+			name:          "account pass-through overrides global absorb",
+			defaultPolicy: account.FeeAbsorptionAbsorb,
+			accountPolicy: "pass_through",
+			feeType:       account.FeeProvider,
+		},
+		{
+			name:          "service fees are never absorbed",
+			defaultPolicy: account.FeeAbsorptionAbsorb,
+			feeType:       account.FeeTypeService,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			txRepo := mocks.NewTransactionRepository(t)
+			accRepo := mocks.NewAccountRepository(t)
+			tx := &dto.TransactionRead{
+				ID:          uuid.New(),
+				AccountID:   uuid.New(),
+				Amount:      100,
+				PlatformFee: 1,
+				Currency:    "USD",
+			}
+			acc := &dto.AccountRead{
+				ID:        tx.AccountID,
+				UserID:    uuid.New(),
+				Balance:   100,
+				Currency:  "USD",
+				FeePolicy: tt.accountPolicy,
+			}
+			fee := account.Fee{Amount: money.Must(3.2, money.USD.ToCurrency()), Type: tt.feeType}
+
+			txRepo.EXPECT().Get(ctx, tx.ID).Return(tx, nil).Times(2)
+			accRepo.EXPECT().GetForUpdate(ctx, acc.ID).Return(acc, nil).Once()
+			if tt.wantAbsorbed {
+				platformFee := int64(420) // Previously absorbed $1.00 plus $3.20
+				policy := string(account.FeeAbsorptionAbsorb)
+				txRepo.EXPECT().Update(ctx, tx.ID, dto.TransactionUpdate{
+					PlatformFee: &platformFee,
+					FeePolicy:   &policy,
+				}).Return(nil).Once()
+			} else {
+				feeAmount := int64(320)
+				update := dto.TransactionUpdate{Fee: &feeAmount}
+				if tt.feeType == account.FeeProvider {
+					policy := string(account.FeeAbsorptionPassThrough)
+					update.FeePolicy = &policy
+				}
+				balance := int64(10000 - 320)
+				txRepo.EXPECT().Update(ctx, tx.ID, update).Return(nil).Once()
+				accRepo.EXPECT().
+					Update(ctx, acc.ID, dto.AccountUpdate{Balance: &balance}).
+					Return(nil).
+					Once()
+			}
+
+			calculator := NewFeeCalculator(txRepo, accRepo, slog.Default()).
+				WithFeeAbsorption(tt.defaultPolicy)
+			require.NoError(t, calculator.ApplyFees(ctx, tx.ID, fee))
+		})
+	}
+}
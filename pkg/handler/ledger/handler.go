@@ -0,0 +1,145 @@
+package ledger
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/amirasaad/fintech/pkg/domain/events"
+	"github.com/amirasaad/fintech/pkg/eventbus"
+	"github.com/amirasaad/fintech/pkg/handler/common"
+	"github.com/amirasaad/fintech/pkg/repository"
+	"github.com/google/uuid"
+)
+
+// HandleVerify verifies the ledger of the accounts a completed money-moving
+// flow touched: PaymentCompleted, FeesCalculated, RefundCompleted,
+// RefundFailed, TransferCompleted, the TransferPending, TransferApproved
+// and TransferRejected steps of two-phase transfers and the WithdrawPending,
+// WithdrawApproved and WithdrawRejected steps of held withdrawals. When an
+// account's balance and ledger diverge it logs an error and emits
+// LedgerDiscrepancyDetected. Register it in the best-effort phase, after the
+// handlers that move the money.
+func HandleVerify(
+	bus eventbus.Bus,
+	uow repository.UnitOfWork,
+	logger *slog.Logger,
+) eventbus.HandlerFunc {
+	return func(ctx context.Context, e events.Event) error {
+		log := logger.With(
+			"handler", "ledger.HandleVerify",
+			"event_type", e.Type(),
+		)
+
+		var discrepancies []*events.LedgerDiscrepancyDetected
+		if err := uow.Do(ctx, func(uow repository.UnitOfWork) error {
+			accountIDs, err := affectedAccounts(ctx, uow, e, log)
+			if err != nil {
+				return err
+			}
+			for _, accountID := range accountIDs {
+				result, err := Verify(ctx, uow, accountID, time.Now(), log)
+				if err != nil {
+					log.Error("failed to verify ledger", "account_id", accountID, "error", err)
+					return err
+				}
+				if result.Balanced() {
+					log.Debug("ledger verified", "account_id", accountID, "checked", result.Checked)
+					continue
+				}
+				log.Error(
+					"🚨 ledger discrepancy detected",
+					"account_id", accountID,
+					"currency", result.Currency,
+					"balance", result.Balance,
+					"ledger_balance", result.LedgerBalance,
+				)
+				discrepancies = append(discrepancies, events.NewLedgerDiscrepancyDetected(
+					accountID,
+					result.Currency,
+					result.Balance,
+					result.LedgerBalance,
+					e.Type(),
+				))
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+
+		// Emit after the unit of work commits so the checkpoints are saved
+		// even if a discrepancy handler fails.
+		for _, d := range discrepancies {
+			if err := bus.Emit(ctx, d); err != nil {
+				log.Error(
+					"failed to emit LedgerDiscrepancyDetected",
+					"account_id", d.AccountID,
+					"error", err,
+				)
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// affectedAccounts returns the IDs of the accounts whose balance the event's
+// flow changed. Payment and refund events are resolved through their
+// transaction, since provider webhooks do not always carry the account.
+func affectedAccounts(
+	ctx context.Context,
+	uow repository.UnitOfWork,
+	e events.Event,
+	log *slog.Logger,
+) ([]uuid.UUID, error) {
+	var (
+		paymentID     *string
+		transactionID uuid.UUID
+	)
+	switch evt := e.(type) {
+	case *events.TransferCompleted:
+		tr, ok := evt.OriginalRequest.(*events.TransferRequested)
+		if !ok {
+			return nil, fmt.Errorf("unexpected original request type: %T", evt.OriginalRequest)
+		}
+		return []uuid.UUID{tr.AccountID, tr.DestAccountID}, nil
+	case *events.TransferPending:
+		return []uuid.UUID{evt.AccountID}, nil
+	case *events.TransferApproved:
+		return []uuid.UUID{evt.DestAccountID}, nil
+	case *events.TransferRejected:
+		return []uuid.UUID{evt.AccountID}, nil
+	case *events.WithdrawPending:
+		return []uuid.UUID{evt.AccountID}, nil
+	case *events.WithdrawApproved:
+		return []uuid.UUID{evt.AccountID}, nil
+	case *events.WithdrawRejected:
+		return []uuid.UUID{evt.AccountID}, nil
+	case *events.DepositReversed:
+		return []uuid.UUID{evt.AccountID}, nil
+	case *events.PaymentCompleted:
+		paymentID, transactionID = evt.PaymentID, evt.TransactionID
+	case *events.FeesCalculated:
+		transactionID = evt.TransactionID
+	case *events.RefundCompleted:
+		paymentID, transactionID = &evt.RefundID, evt.TransactionID
+	case *events.RefundFailed:
+		paymentID, transactionID = &evt.RefundID, evt.TransactionID
+	default:
+		return nil, fmt.Errorf("unexpected event type: %s", e.Type())
+	}
+
+	txRepo, err := common.GetTransactionRepository(uow, log)
+	if err != nil {
+		return nil, err
+	}
+	lookup := common.LookupTransactionByPaymentOrID(ctx, txRepo, paymentID, transactionID, log)
+	if lookup.Error != nil {
+		return nil, lookup.Error
+	}
+	if !lookup.Found {
+		return nil, nil
+	}
+	return []uuid.UUID{lookup.Transaction.AccountID}, nil
+}
@@ -0,0 +1,153 @@
+package ledger
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/amirasaad/fintech/pkg/domain/events"
+	"github.com/amirasaad/fintech/pkg/dto"
+	"github.com/amirasaad/fintech/pkg/handler/testutils"
+	"github.com/amirasaad/fintech/pkg/repository"
+	repoaccount "github.com/amirasaad/fintech/pkg/repository/account"
+	repotransaction "github.com/amirasaad/fintech/pkg/repository/transaction"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func expectRepositories(h *testutils.TestHelper) {
+	h.UOW.EXPECT().Do(h.Ctx, mock.Anything).RunAndReturn(
+		func(ctx context.Context, fn func(repository.UnitOfWork) error) error {
+			return fn(h.UOW)
+		},
+	).Maybe()
+	h.UOW.EXPECT().
+		GetRepository((*repotransaction.Repository)(nil)).
+		Return(h.MockTxRepo, nil).
+		Maybe()
+	h.UOW.EXPECT().
+		GetRepository((*repoaccount.Repository)(nil)).
+		Return(h.MockAccRepo, nil).
+		Maybe()
+}
+
+func TestVerify(t *testing.T) {
+	verifiedAt := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	now := verifiedAt.Add(time.Hour)
+
+	t.Run("adjusts the ledger by the change since the last verification", func(t *testing.T) {
+		h := testutils.New(t)
+		expectRepositories(h)
+		deposit, withdrawal, pending := uuid.New(), uuid.New(), uuid.New()
+		h.MockAccRepo.EXPECT().
+			GetLedgerCheckpoint(h.Ctx, h.AccountID).
+			Return(&dto.LedgerCheckpoint{
+				AccountID:     h.AccountID,
+				Currency:      "USD",
+				Balance:       14_800,
+				LedgerBalance: 10_000,
+				VerifiedAt:    verifiedAt,
+			}, nil).
+			Once()
+		h.MockTxRepo.EXPECT().
+			ListLedgerEntries(h.Ctx, h.AccountID, verifiedAt).
+			Return([]*dto.LedgerEntry{
+				// Counted when it completed; its fee was applied since.
+				{TransactionID: deposit, Status: "completed", Amount: 5_000, Fee: 200, Counted: 5_000},
+				// Already counted in full; only re-read.
+				{TransactionID: withdrawal, Status: "completed", Amount: -1_000, Counted: -1_000},
+				{TransactionID: pending, Status: "pending", Amount: 3_000},
+			}, nil).
+			Once()
+		h.MockTxRepo.EXPECT().SetLedgerCounted(h.Ctx, deposit, int64(4_800)).Return(nil).Once()
+		h.MockAccRepo.EXPECT().
+			SaveLedgerCheckpoint(h.Ctx, h.AccountID, int64(9_800), now.Add(-watermarkLag)).
+			Return(nil).
+			Once()
+
+		result, err := Verify(h.Ctx, h.UOW, h.AccountID, now, h.Logger)
+		require.NoError(t, err)
+		assert.Equal(t, int64(9_800), result.LedgerBalance)
+		assert.Equal(t, 3, result.Checked)
+		assert.False(t, result.Balanced())
+	})
+
+	t.Run("uncounts transactions that are no longer completed", func(t *testing.T) {
+		h := testutils.New(t)
+		expectRepositories(h)
+		refund := uuid.New()
+		h.MockAccRepo.EXPECT().
+			GetLedgerCheckpoint(h.Ctx, h.AccountID).
+			Return(&dto.LedgerCheckpoint{
+				AccountID:     h.AccountID,
+				Currency:      "USD",
+				Balance:       10_000,
+				LedgerBalance: 7_000,
+			}, nil).
+			Once()
+		h.MockTxRepo.EXPECT().
+			ListLedgerEntries(h.Ctx, h.AccountID, time.Time{}).
+			Return([]*dto.LedgerEntry{
+				{TransactionID: refund, Status: "failed", Amount: -3_000, Counted: -3_000},
+			}, nil).
+			Once()
+		h.MockTxRepo.EXPECT().SetLedgerCounted(h.Ctx, refund, int64(0)).Return(nil).Once()
+		h.MockAccRepo.EXPECT().
+			SaveLedgerCheckpoint(h.Ctx, h.AccountID, int64(10_000), now.Add(-watermarkLag)).
+			Return(nil).
+			Once()
+
+		result, err := Verify(h.Ctx, h.UOW, h.AccountID, now, h.Logger)
+		require.NoError(t, err)
+		assert.True(t, result.Balanced())
+	})
+}
+
+func TestHandleVerify(t *testing.T) {
+	setup := func(t *testing.T, balance int64) (*testutils.TestHelper, *events.FeesCalculated) {
+		h := testutils.New(t)
+		expectRepositories(h)
+		h.MockTxRepo.EXPECT().
+			Get(h.Ctx, h.TransactionID).
+			Return(&dto.TransactionRead{ID: h.TransactionID, AccountID: h.AccountID}, nil).
+			Once()
+		h.MockAccRepo.EXPECT().
+			GetLedgerCheckpoint(h.Ctx, h.AccountID).
+			Return(&dto.LedgerCheckpoint{
+				AccountID:     h.AccountID,
+				Currency:      "USD",
+				Balance:       balance,
+				LedgerBalance: 5_000,
+			}, nil).
+			Once()
+		h.MockTxRepo.EXPECT().
+			ListLedgerEntries(h.Ctx, h.AccountID, time.Time{}).
+			Return(nil, nil).
+			Once()
+		h.MockAccRepo.EXPECT().
+			SaveLedgerCheckpoint(h.Ctx, h.AccountID, int64(5_000), mock.Anything).
+			Return(nil).
+			Once()
+		return h, &events.FeesCalculated{TransactionID: h.TransactionID}
+	}
+
+	t.Run("emits nothing when the ledger balances", func(t *testing.T) {
+		h, event := setup(t, 5_000)
+		require.NoError(t, HandleVerify(h.Bus, h.UOW, h.Logger)(h.Ctx, event))
+	})
+
+	t.Run("emits LedgerDiscrepancyDetected on drift", func(t *testing.T) {
+		h, event := setup(t, 5_100)
+		h.Bus.EXPECT().
+			Emit(h.Ctx, mock.MatchedBy(func(d *events.LedgerDiscrepancyDetected) bool {
+				return d.AccountID == h.AccountID &&
+					d.Difference == 100 &&
+					d.TriggeredBy == events.EventTypeFeesCalculated.String()
+			})).
+			Return(nil).
+			Once()
+		require.NoError(t, HandleVerify(h.Bus, h.UOW, h.Logger)(h.Ctx, event))
+	})
+}
@@ -111,6 +111,32 @@ func HandleCompleted(
 			}
 
 			tx := lookupResult.Transaction
+			if tx.MoneySource == common.MoneySourceWithdrawHold {
+				log.Info("Held withdrawal paid out; its hold already debited the account")
+				return nil
+			}
+			status := string(account.TransactionStatusCompleted)
+			if !common.CanTransition(log, tx.ID, tx.Status, status) {
+				return nil
+			}
+
+			// Lock the account row for the balance update, then check the
+			// transaction again: a concurrent completion of it may have
+			// committed since it was read.
+			acc, err := accRepo.GetForUpdate(ctx, tx.AccountID)
+			if err != nil {
+				log.Error(
+					"failed to get account",
+					"error", err,
+				)
+				return err
+			}
+			if tx, err = txRepo.Get(ctx, tx.ID); err != nil {
+				return fmt.Errorf("failed to get transaction: %w", err)
+			}
+			if !common.CanTransition(log, tx.ID, tx.Status, status) {
+				return nil
+			}
 
 			// Update the transaction with the payment ID if it wasn't set
 			if tx.PaymentID == nil || (tx.PaymentID != nil && *tx.PaymentID != *pc.PaymentID) {
@@ -133,14 +159,6 @@ func HandleCompleted(
 				"transaction_id", tx.ID,
 				"user_id", tx.UserID,
 			)
-			acc, err := accRepo.Get(ctx, tx.AccountID)
-			if err != nil {
-				log.Error(
-					"failed to get account",
-					"error", err,
-				)
-				return err
-			}
 			domainAcc, err := mapper.MapAccountReadToDomain(acc)
 			if err != nil {
 				log.Error(
@@ -160,7 +178,6 @@ func HandleCompleted(
 				return err
 			}
 			oldStatus := tx.Status
-			status := string(account.TransactionStatusCompleted)
 			tx.Status = status
 
 			// Store the gross amount in the transaction
@@ -174,6 +191,14 @@ func HandleCompleted(
 				Currency: &currency,
 				Balance:  &balance,
 			}
+			if pm := pc.PaymentMethod; pm != nil {
+				update.PaymentMethod = &dto.PaymentMethod{
+					Type:    pm.Type,
+					Brand:   pm.Brand,
+					Last4:   pm.Last4,
+					Funding: pm.Funding,
+				}
+			}
 
 			if err = txRepo.Update(ctx, tx.ID, update); err != nil {
 				log.Error(
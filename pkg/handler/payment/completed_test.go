@@ -142,7 +142,7 @@ func TestCompletedHandler(t *testing.T) {
 
 			h.UOW.EXPECT().GetRepository(
 				(*repoaccount.Repository)(nil)).Return(h.MockAccRepo, nil).Once()
-			h.MockAccRepo.EXPECT().Get(h.Ctx, h.AccountID).Return(nil, expectedErr).Once()
+			h.MockAccRepo.EXPECT().GetForUpdate(h.Ctx, h.AccountID).Return(nil, expectedErr).Once()
 
 			err := fn(h.UOW)
 			require.ErrorIs(t, err, expectedErr)
@@ -287,9 +287,13 @@ func TestCompletedHandler(t *testing.T) {
 					Once()
 
 				h.MockAccRepo.EXPECT().
-					Get(h.Ctx, h.AccountID).
+					GetForUpdate(h.Ctx, h.AccountID).
 					Return(testAccount, nil).
 					Once()
+				h.MockTxRepo.EXPECT().
+					Get(h.Ctx, h.TransactionID).
+					Return(tx, nil).
+					Once()
 
 				h.MockTxRepo.EXPECT().
 					Update(
@@ -315,6 +319,85 @@ func TestCompletedHandler(t *testing.T) {
 		require.NoError(t, err)
 	})
 
+	t.Run("persists payment method details", func(t *testing.T) {
+		t.Parallel()
+		h := newTestHelper(t)
+		handler := HandleCompleted(h.Bus, h.UOW, h.Logger)
+
+		event := createValidPaymentCompletedEvent(h)
+		event.PaymentMethod = &account.PaymentMethod{
+			Type: "card", Brand: "visa", Last4: "4242", Funding: "credit",
+		}
+		paymentID := "test-payment-id"
+
+		tx := &dto.TransactionRead{
+			ID:        h.TransactionID,
+			UserID:    h.UserID,
+			AccountID: h.AccountID,
+			PaymentID: &paymentID,
+			Status:    string(account.TransactionStatusPending),
+			Currency:  "USD",
+			Amount:    h.Amount.AmountFloat(),
+		}
+
+		testAccount := &dto.AccountRead{
+			ID:       h.AccountID,
+			UserID:   h.UserID,
+			Currency: "USD",
+		}
+
+		h.UOW.EXPECT().
+			Do(h.Ctx, mock.Anything).
+			RunAndReturn(func(ctx context.Context, fn func(uow repository.UnitOfWork) error) error {
+				h.UOW.EXPECT().
+					GetRepository((*repoaccount.Repository)(nil)).
+					Return(h.MockAccRepo, nil).
+					Once()
+				h.UOW.EXPECT().
+					GetRepository((*repotransaction.Repository)(nil)).
+					Return(h.MockTxRepo, nil).
+					Once()
+
+				h.MockTxRepo.EXPECT().
+					GetByPaymentID(h.Ctx, paymentID).
+					Return(tx, nil).
+					Once()
+
+				h.MockAccRepo.EXPECT().
+					GetForUpdate(h.Ctx, h.AccountID).
+					Return(testAccount, nil).
+					Once()
+				h.MockTxRepo.EXPECT().
+					Get(h.Ctx, h.TransactionID).
+					Return(tx, nil).
+					Once()
+
+				h.MockTxRepo.EXPECT().
+					Update(
+						h.Ctx,
+						h.TransactionID,
+						mock.MatchedBy(func(update dto.TransactionUpdate) bool {
+							return update.PaymentMethod != nil &&
+								*update.PaymentMethod == dto.PaymentMethod{
+									Type: "card", Brand: "visa", Last4: "4242", Funding: "credit",
+								}
+						})).
+					Return(nil).
+					Once()
+
+				h.MockAccRepo.EXPECT().
+					Update(h.Ctx, h.AccountID, mock.Anything).
+					Return(nil).
+					Once()
+
+				return fn(h.UOW)
+			}).
+			Once()
+
+		err := handler(h.Ctx, event)
+		require.NoError(t, err)
+	})
+
 	t.Run("handles account mapping error", func(t *testing.T) {
 		t.Parallel()
 		h := newTestHelper(t)
@@ -358,9 +441,13 @@ func TestCompletedHandler(t *testing.T) {
 					Once()
 
 				h.MockAccRepo.EXPECT().
-					Get(h.Ctx, h.AccountID).
+					GetForUpdate(h.Ctx, h.AccountID).
 					Return(testAccount, nil).
 					Once()
+				h.MockTxRepo.EXPECT().
+					Get(h.Ctx, h.TransactionID).
+					Return(tx, nil).
+					Once()
 
 				return fn(h.UOW)
 			}).
@@ -416,9 +503,13 @@ func TestCompletedHandler(t *testing.T) {
 					Once()
 
 				h.MockAccRepo.EXPECT().
-					Get(h.Ctx, h.AccountID).
+					GetForUpdate(h.Ctx, h.AccountID).
 					Return(testAccount, nil).
 					Once()
+				h.MockTxRepo.EXPECT().
+					Get(h.Ctx, h.TransactionID).
+					Return(tx, nil).
+					Once()
 
 				h.MockTxRepo.EXPECT().
 					Update(h.Ctx, h.TransactionID, mock.Anything).
@@ -439,4 +530,40 @@ func TestCompletedHandler(t *testing.T) {
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "failed to update account balance")
 	})
+
+	t.Run("ignores completion of a completed transaction", func(t *testing.T) {
+		t.Parallel()
+		h := newTestHelper(t)
+		handler := HandleCompleted(h.Bus, h.UOW, h.Logger)
+
+		event := createValidPaymentCompletedEvent(h)
+		tx := h.CreateValidTransaction()
+		tx.Status = string(account.TransactionStatusCompleted)
+
+		h.UOW.EXPECT().
+			Do(h.Ctx, mock.Anything).
+			RunAndReturn(func(ctx context.Context, fn func(uow repository.UnitOfWork) error) error {
+				h.UOW.EXPECT().
+					GetRepository((*repoaccount.Repository)(nil)).
+					Return(h.MockAccRepo, nil).
+					Once()
+				h.UOW.EXPECT().
+					GetRepository((*repotransaction.Repository)(nil)).
+					Return(h.MockTxRepo, nil).
+					Once()
+				h.MockTxRepo.EXPECT().
+					GetByPaymentID(h.Ctx, "test-payment-id").
+					Return(tx, nil).
+					Once()
+
+				return fn(h.UOW)
+			}).
+			Once()
+
+		err := handler(h.Ctx, event)
+		require.NoError(t, err)
+		// The balance is not credited a second time.
+		h.MockAccRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+		h.MockTxRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+	})
 }
@@ -13,6 +13,18 @@ import (
 	"github.com/amirasaad/fintech/pkg/repository"
 )
 
+// ExtractPaymentFailedKey extracts idempotency key from PaymentFailed event
+func ExtractPaymentFailedKey(e events.Event) string {
+	pf, ok := e.(*events.PaymentFailed)
+	if !ok {
+		return ""
+	}
+	if pf.PaymentID != nil && *pf.PaymentID != "" {
+		return *pf.PaymentID
+	}
+	return pf.TransactionID.String()
+}
+
 // HandleFailed handles the PaymentFailedEvent by updating the transaction status to "failed"
 func HandleFailed(
 	bus eventbus.Bus,
@@ -49,8 +61,33 @@ func HandleFailed(
 			return err
 		}
 
+		tx, err := txRepo.Get(ctx, txID)
+		if err != nil {
+			err = fmt.Errorf("failed to get transaction: %w", err)
+			log.Error("repository error", "error", err)
+			return err
+		}
+		// A held withdrawal's payout failed: its hold, which debited the
+		// amount, is released and marked failed.
+		if tx.MoneySource == common.MoneySourceWithdrawHold {
+			return uow.Do(ctx, func(uow repository.UnitOfWork) error {
+				released, err := common.ReleaseWithdrawalHold(ctx, uow, txID, pf.PaymentID, log)
+				if err != nil {
+					log.Error("failed to release withdrawal hold", "error", err)
+					return err
+				}
+				if released {
+					log.Info("released hold of failed withdrawal")
+				}
+				return nil
+			})
+		}
+
 		// Update the transaction status to failed
 		status := string(account.TransactionStatusFailed)
+		if !common.CanTransition(log, txID, tx.Status, status) {
+			return nil
+		}
 		updateErr := txRepo.Update(ctx, txID, dto.TransactionUpdate{
 			PaymentID: pf.PaymentID, // Update to handle PaymentID as a pointer
 			Status:    &status,
@@ -1,10 +1,15 @@
 package payment
 
 import (
+	"context"
 	"testing"
 
+	"github.com/amirasaad/fintech/pkg/domain/account"
 	"github.com/amirasaad/fintech/pkg/dto"
+	"github.com/amirasaad/fintech/pkg/handler/common"
 	"github.com/amirasaad/fintech/pkg/handler/testutils"
+	"github.com/amirasaad/fintech/pkg/repository"
+	repoaccount "github.com/amirasaad/fintech/pkg/repository/account"
 	repotransaction "github.com/amirasaad/fintech/pkg/repository/transaction"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -35,6 +40,11 @@ func TestFailedHandler(t *testing.T) {
 			Return(h.MockTxRepo, nil).
 			Once()
 
+		h.MockTxRepo.EXPECT().
+			Get(h.Ctx, h.TransactionID).
+			Return(h.CreateValidTransaction(), nil).
+			Once()
+
 		// Setup the mock for the Update method
 		status := "failed"
 		h.MockTxRepo.EXPECT().
@@ -68,6 +78,11 @@ func TestFailedHandler(t *testing.T) {
 			Return(h.MockTxRepo, nil).
 			Once()
 
+		h.MockTxRepo.EXPECT().
+			Get(h.Ctx, h.TransactionID).
+			Return(h.CreateValidTransaction(), nil).
+			Once()
+
 		// Setup the mock for the Update method
 		status := "failed"
 		h.MockTxRepo.EXPECT().
@@ -87,4 +102,75 @@ func TestFailedHandler(t *testing.T) {
 		err := handler(h.Ctx, createValidPaymentFailedEvent(h))
 		assert.NoError(t, err)
 	})
+
+	t.Run("ignores failure of a completed transaction", func(t *testing.T) {
+		t.Parallel()
+		h := testutils.New(t)
+
+		h.UOW.EXPECT().
+			GetRepository((*repotransaction.Repository)(nil)).
+			Return(h.MockTxRepo, nil).
+			Once()
+
+		// The payment completed before its failed attempt was reported.
+		tx := h.CreateValidTransaction()
+		tx.Status = string(account.TransactionStatusCompleted)
+		h.MockTxRepo.EXPECT().
+			Get(h.Ctx, h.TransactionID).
+			Return(tx, nil).
+			Once()
+
+		err := HandleFailed(h.Bus, h.UOW, h.Logger)(h.Ctx, createValidPaymentFailedEvent(h))
+		require.NoError(t, err)
+		h.MockTxRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+	})
+	t.Run("releases the hold of a held withdrawal", func(t *testing.T) {
+		t.Parallel()
+		h := testutils.New(t)
+
+		h.UOW.EXPECT().
+			GetRepository((*repotransaction.Repository)(nil)).
+			Return(h.MockTxRepo, nil).
+			Times(2)
+		h.UOW.EXPECT().
+			GetRepository((*repoaccount.Repository)(nil)).
+			Return(h.MockAccRepo, nil).
+			Once()
+		h.UOW.EXPECT().Do(h.Ctx, mock.Anything).RunAndReturn(
+			func(ctx context.Context, fn func(repository.UnitOfWork) error) error {
+				return fn(h.UOW)
+			},
+		).Once()
+
+		// The hold debited the withdrawal when it was held for approval.
+		hold := &dto.TransactionRead{
+			ID:          h.TransactionID,
+			AccountID:   h.AccountID,
+			Amount:      -250,
+			Currency:    "USD",
+			Status:      string(account.TransactionStatusCompleted),
+			MoneySource: common.MoneySourceWithdrawHold,
+		}
+		h.MockTxRepo.EXPECT().Get(h.Ctx, h.TransactionID).Return(hold, nil).Times(3)
+		h.MockAccRepo.EXPECT().
+			GetForUpdate(h.Ctx, h.AccountID).
+			Return(&dto.AccountRead{ID: h.AccountID, Balance: 750, Currency: "USD"}, nil).
+			Once()
+		var balance int64 = 100000
+		h.MockAccRepo.EXPECT().
+			Update(h.Ctx, h.AccountID, dto.AccountUpdate{Balance: &balance}).
+			Return(nil).
+			Once()
+		status := string(account.TransactionStatusFailed)
+		h.MockTxRepo.EXPECT().
+			Update(h.Ctx, h.TransactionID, dto.TransactionUpdate{
+				PaymentID: h.PaymentID,
+				Status:    &status,
+			}).
+			Return(nil).
+			Once()
+
+		err := HandleFailed(h.Bus, h.UOW, h.Logger)(h.Ctx, createValidPaymentFailedEvent(h))
+		require.NoError(t, err)
+	})
 }
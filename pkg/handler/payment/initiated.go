@@ -63,11 +63,15 @@ func HandleInitiated(
 		payment, err := paymentProvider.InitiatePayment(
 			ctx,
 			&payment.InitiatePaymentParams{
-				UserID:        pi.UserID,
-				AccountID:     pi.AccountID,
-				Amount:        amount,
-				Currency:      currency,
-				TransactionID: transactionID,
+				UserID:              pi.UserID,
+				AccountID:           pi.AccountID,
+				Amount:              amount,
+				Currency:            currency,
+				TransactionID:       transactionID,
+				SuccessURL:          pi.SuccessURL,
+				CancelURL:           pi.CancelURL,
+				StatementDescriptor: pi.StatementDescriptor,
+				CaptureMethod:       payment.CaptureMethod(pi.CaptureMethod),
 			},
 		)
 		if err != nil {
@@ -9,6 +9,7 @@ import (
 	"github.com/amirasaad/fintech/pkg/eventbus"
 	"github.com/amirasaad/fintech/pkg/handler/common"
 
+	"github.com/amirasaad/fintech/pkg/domain/account"
 	"github.com/amirasaad/fintech/pkg/domain/events"
 
 	"github.com/amirasaad/fintech/pkg/dto"
@@ -97,10 +98,13 @@ func HandleProcessed(
 
 			tx := lookupResult.Transaction
 			transactionID := lookupResult.TransactionID
-			status := "processed"
+			status := string(account.TransactionStatusProcessed)
 
 			// If transaction exists, update it with payment ID
 			if tx != nil {
+				if !common.CanTransition(log, transactionID, tx.Status, status) {
+					return nil
+				}
 				update := dto.TransactionUpdate{
 					PaymentID: pp.PaymentID,
 					Status:    &status,
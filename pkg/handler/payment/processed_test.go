@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/amirasaad/fintech/pkg/domain/account"
 	"github.com/amirasaad/fintech/pkg/domain/events"
 	"github.com/amirasaad/fintech/pkg/dto"
 	"github.com/amirasaad/fintech/pkg/handler/testutils"
@@ -438,4 +439,54 @@ func TestHandleProcessed(t *testing.T) {
 		require.Error(t, err)
 		assert.Equal(t, lookupErr, err)
 	})
+
+	t.Run("ignores processed event arriving after completion", func(t *testing.T) {
+		t.Parallel()
+		h := testutils.New(t)
+		handler := HandleProcessed(h.UOW, h.Logger)
+
+		paymentID := "test-payment-id"
+		testTx := &dto.TransactionRead{
+			ID:        h.TransactionID,
+			AccountID: h.AccountID,
+			UserID:    h.UserID,
+			Status:    string(account.TransactionStatusCompleted),
+			Amount:    100.0,
+			Currency:  "USD",
+			PaymentID: &paymentID,
+		}
+
+		h.UOW.EXPECT().
+			Do(h.Ctx, mock.AnythingOfType("func(repository.UnitOfWork) error")).
+			RunAndReturn(func(ctx context.Context, fn func(uow repository.UnitOfWork) error) error {
+				h.UOW.EXPECT().
+					GetRepository((*repotransaction.Repository)(nil)).
+					Return(h.MockTxRepo, nil).
+					Once()
+				h.MockTxRepo.EXPECT().
+					GetByPaymentID(h.Ctx, paymentID).
+					Return(testTx, nil).
+					Once()
+
+				return fn(h.UOW)
+			}).
+			Once()
+
+		event := events.NewPaymentProcessed(
+			&events.FlowEvent{
+				ID:            h.EventID,
+				CorrelationID: h.CorrelationID,
+				FlowType:      "payment",
+			},
+			func(pp *events.PaymentProcessed) {
+				pp.TransactionID = h.TransactionID
+				pp.PaymentID = &paymentID
+			},
+		)
+
+		err := handler(h.Ctx, event)
+		require.NoError(t, err)
+		// The transaction is not moved back from completed to processed.
+		h.MockTxRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+	})
 }
@@ -0,0 +1,127 @@
+package payment
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/amirasaad/fintech/pkg/domain/account"
+	"github.com/amirasaad/fintech/pkg/domain/events"
+	"github.com/amirasaad/fintech/pkg/dto"
+	"github.com/amirasaad/fintech/pkg/eventbus"
+	"github.com/amirasaad/fintech/pkg/handler/common"
+	"github.com/amirasaad/fintech/pkg/mapper"
+	"github.com/amirasaad/fintech/pkg/repository"
+)
+
+// ExtractRefundCompletedKey extracts idempotency key from RefundCompleted event
+func ExtractRefundCompletedKey(e events.Event) string {
+	rc, ok := e.(*events.RefundCompleted)
+	if !ok {
+		return ""
+	}
+	if rc.RefundID != "" {
+		return rc.RefundID
+	}
+	return rc.TransactionID.String()
+}
+
+// HandleRefundCompleted handles RefundCompleted by marking the pending refund
+// transaction completed and debiting the refunded amount from the account.
+// Refunds that are no longer pending have already been applied and are
+// skipped, since the provider may report the same refund more than once.
+func HandleRefundCompleted(
+	uow repository.UnitOfWork,
+	logger *slog.Logger,
+) eventbus.HandlerFunc {
+	return func(ctx context.Context, e events.Event) error {
+		log := logger.With(
+			"handler", "payment.HandleRefundCompleted",
+			"event_type", e.Type(),
+		)
+		rc, ok := e.(*events.RefundCompleted)
+		if !ok {
+			log.Error("Skipping unexpected event type", "event", e)
+			return nil
+		}
+		log = log.With(
+			"transaction_id", rc.TransactionID,
+			"refund_id", rc.RefundID,
+			"account_id", rc.AccountID,
+		)
+
+		return uow.Do(ctx, func(uow repository.UnitOfWork) error {
+			txRepo, err := common.GetTransactionRepository(uow, log)
+			if err != nil {
+				return err
+			}
+			accRepo, err := common.GetAccountRepository(uow, log)
+			if err != nil {
+				return err
+			}
+
+			lookup := common.LookupTransactionByPaymentOrID(
+				ctx, txRepo, &rc.RefundID, rc.TransactionID, log,
+			)
+			if lookup.Error != nil {
+				return lookup.Error
+			}
+			if !lookup.Found {
+				return nil
+			}
+			tx := lookup.Transaction
+			if tx.Status != string(account.TransactionStatusPending) {
+				log.Info("Refund already applied", "status", tx.Status)
+				return nil
+			}
+
+			// Lock the account row for the balance update, then check the
+			// refund again: a concurrent delivery may have applied it since
+			// it was read.
+			acc, err := accRepo.GetForUpdate(ctx, tx.AccountID)
+			if err != nil {
+				log.Error("failed to get account", "error", err)
+				return err
+			}
+			if tx, err = txRepo.Get(ctx, tx.ID); err != nil {
+				log.Error("failed to get refund transaction", "error", err)
+				return err
+			}
+			if tx.Status != string(account.TransactionStatusPending) {
+				log.Info("Refund already applied", "status", tx.Status)
+				return nil
+			}
+			domainAcc, err := mapper.MapAccountReadToDomain(acc)
+			if err != nil {
+				log.Error("failed to map account to domain", "error", err)
+				return err
+			}
+			newBalance, err := domainAcc.Balance.Subtract(rc.Amount)
+			if err != nil {
+				log.Error("failed to subtract refund from balance", "error", err)
+				return err
+			}
+
+			status := string(account.TransactionStatusCompleted)
+			balance := newBalance.Amount()
+			if err := txRepo.Update(ctx, tx.ID, dto.TransactionUpdate{
+				Status:    &status,
+				PaymentID: &rc.RefundID,
+				Balance:   &balance,
+			}); err != nil {
+				log.Error("failed to update refund transaction", "error", err)
+				return err
+			}
+			if err := accRepo.Update(
+				ctx,
+				tx.AccountID,
+				dto.AccountUpdate{Balance: &balance},
+			); err != nil {
+				log.Error("failed to update account balance", "error", err)
+				return err
+			}
+
+			log.Info("✅ [SUCCESS] refund applied", "new_balance", newBalance)
+			return nil
+		})
+	}
+}
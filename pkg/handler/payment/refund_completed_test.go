@@ -0,0 +1,118 @@
+package payment
+
+import (
+	"context"
+	"testing"
+
+	"github.com/amirasaad/fintech/pkg/domain/account"
+	"github.com/amirasaad/fintech/pkg/domain/events"
+	"github.com/amirasaad/fintech/pkg/dto"
+	"github.com/amirasaad/fintech/pkg/handler/testutils"
+	"github.com/amirasaad/fintech/pkg/money"
+	"github.com/amirasaad/fintech/pkg/repository"
+	repoaccount "github.com/amirasaad/fintech/pkg/repository/account"
+	repotransaction "github.com/amirasaad/fintech/pkg/repository/transaction"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRefundCompletedHandler(t *testing.T) {
+	setup := func(t *testing.T, status account.TransactionStatus) (
+		*testutils.TestHelper,
+		*events.RefundCompleted,
+	) {
+		h := testutils.New(t)
+		amount, err := money.New(30, money.USD)
+		require.NoError(t, err)
+		event := events.NewRefundCompleted(
+			h.UserID, h.AccountID, h.TransactionID, "re_1", amount,
+		)
+
+		h.UOW.EXPECT().Do(h.Ctx, mock.Anything).RunAndReturn(
+			func(ctx context.Context, fn func(repository.UnitOfWork) error) error {
+				return fn(h.UOW)
+			},
+		).Once()
+		h.UOW.EXPECT().
+			GetRepository((*repotransaction.Repository)(nil)).
+			Return(h.MockTxRepo, nil).
+			Once()
+		h.UOW.EXPECT().
+			GetRepository((*repoaccount.Repository)(nil)).
+			Return(h.MockAccRepo, nil).
+			Once()
+		h.MockTxRepo.EXPECT().
+			GetByPaymentID(h.Ctx, "re_1").
+			Return(&dto.TransactionRead{
+				ID:        h.TransactionID,
+				AccountID: h.AccountID,
+				Status:    string(status),
+			}, nil).
+			Once()
+		return h, event
+	}
+
+	t.Run("debits the account and completes the refund", func(t *testing.T) {
+		h, event := setup(t, account.TransactionStatusPending)
+		h.MockAccRepo.EXPECT().
+			GetForUpdate(h.Ctx, h.AccountID).
+			Return(&dto.AccountRead{
+				ID: h.AccountID, UserID: h.UserID, Balance: 100, Currency: "USD",
+			}, nil).
+			Once()
+		h.MockTxRepo.EXPECT().
+			Get(h.Ctx, h.TransactionID).
+			Return(&dto.TransactionRead{
+				ID:        h.TransactionID,
+				AccountID: h.AccountID,
+				Status:    string(account.TransactionStatusPending),
+			}, nil).
+			Once()
+		var balance int64 = 7000
+		status := string(account.TransactionStatusCompleted)
+		refundID := "re_1"
+		h.MockTxRepo.EXPECT().
+			Update(h.Ctx, h.TransactionID, dto.TransactionUpdate{
+				Status:    &status,
+				PaymentID: &refundID,
+				Balance:   &balance,
+			}).
+			Return(nil).
+			Once()
+		h.MockAccRepo.EXPECT().
+			Update(h.Ctx, h.AccountID, dto.AccountUpdate{Balance: &balance}).
+			Return(nil).
+			Once()
+
+		err := HandleRefundCompleted(h.UOW, h.Logger)(h.Ctx, event)
+		assert.NoError(t, err)
+	})
+
+	t.Run("skips refunds that were already applied", func(t *testing.T) {
+		h, event := setup(t, account.TransactionStatusCompleted)
+		err := HandleRefundCompleted(h.UOW, h.Logger)(h.Ctx, event)
+		assert.NoError(t, err)
+	})
+
+	t.Run("skips a refund applied while waiting for the lock", func(t *testing.T) {
+		h, event := setup(t, account.TransactionStatusPending)
+		h.MockAccRepo.EXPECT().
+			GetForUpdate(h.Ctx, h.AccountID).
+			Return(&dto.AccountRead{
+				ID: h.AccountID, UserID: h.UserID, Balance: 70, Currency: "USD",
+			}, nil).
+			Once()
+		h.MockTxRepo.EXPECT().
+			Get(h.Ctx, h.TransactionID).
+			Return(&dto.TransactionRead{
+				ID:        h.TransactionID,
+				AccountID: h.AccountID,
+				Status:    string(account.TransactionStatusCompleted),
+			}, nil).
+			Once()
+
+		err := HandleRefundCompleted(h.UOW, h.Logger)(h.Ctx, event)
+		assert.NoError(t, err)
+	})
+}
@@ -0,0 +1,126 @@
+package payment
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/amirasaad/fintech/pkg/domain/account"
+	"github.com/amirasaad/fintech/pkg/domain/events"
+	"github.com/amirasaad/fintech/pkg/dto"
+	"github.com/amirasaad/fintech/pkg/eventbus"
+	"github.com/amirasaad/fintech/pkg/handler/common"
+	"github.com/amirasaad/fintech/pkg/mapper"
+	"github.com/amirasaad/fintech/pkg/repository"
+)
+
+// ExtractRefundFailedKey extracts idempotency key from RefundFailed event
+func ExtractRefundFailedKey(e events.Event) string {
+	rf, ok := e.(*events.RefundFailed)
+	if !ok {
+		return ""
+	}
+	if rf.RefundID != "" {
+		return rf.RefundID
+	}
+	return rf.TransactionID.String()
+}
+
+// HandleRefundFailed handles RefundFailed by marking the refund transaction
+// failed. A pending refund never touched the balance; a refund that had
+// already completed is reversed, since the provider returns the money to the
+// platform, so it is credited back to the account. Refunds that already
+// failed are skipped, since the provider may report a failure more than once.
+func HandleRefundFailed(
+	uow repository.UnitOfWork,
+	logger *slog.Logger,
+) eventbus.HandlerFunc {
+	return func(ctx context.Context, e events.Event) error {
+		log := logger.With(
+			"handler", "payment.HandleRefundFailed",
+			"event_type", e.Type(),
+		)
+		rf, ok := e.(*events.RefundFailed)
+		if !ok {
+			log.Error("Skipping unexpected event type", "event", e)
+			return nil
+		}
+		log = log.With(
+			"transaction_id", rf.TransactionID,
+			"refund_id", rf.RefundID,
+			"account_id", rf.AccountID,
+			"reason", rf.Reason,
+		)
+
+		return uow.Do(ctx, func(uow repository.UnitOfWork) error {
+			txRepo, err := common.GetTransactionRepository(uow, log)
+			if err != nil {
+				return err
+			}
+			lookup := common.LookupTransactionByPaymentOrID(
+				ctx, txRepo, &rf.RefundID, rf.TransactionID, log,
+			)
+			if lookup.Error != nil {
+				return lookup.Error
+			}
+			if !lookup.Found {
+				return nil
+			}
+			tx := lookup.Transaction
+
+			// Lock the account row, which a completing refund locks too,
+			// and read the refund again so its status cannot change under
+			// the update below.
+			accRepo, err := common.GetAccountRepository(uow, log)
+			if err != nil {
+				return err
+			}
+			acc, err := accRepo.GetForUpdate(ctx, tx.AccountID)
+			if err != nil {
+				log.Error("failed to get account", "error", err)
+				return err
+			}
+			if tx, err = txRepo.Get(ctx, tx.ID); err != nil {
+				log.Error("failed to get refund transaction", "error", err)
+				return err
+			}
+
+			status := string(account.TransactionStatusFailed)
+			update := dto.TransactionUpdate{Status: &status, PaymentID: &rf.RefundID}
+			switch tx.Status {
+			case string(account.TransactionStatusPending):
+			case string(account.TransactionStatusCompleted):
+				domainAcc, err := mapper.MapAccountReadToDomain(acc)
+				if err != nil {
+					log.Error("failed to map account to domain", "error", err)
+					return err
+				}
+				newBalance, err := domainAcc.Balance.Add(rf.Amount)
+				if err != nil {
+					log.Error("failed to credit reversed refund", "error", err)
+					return err
+				}
+				balance := newBalance.Amount()
+				if err := accRepo.Update(
+					ctx,
+					tx.AccountID,
+					dto.AccountUpdate{Balance: &balance},
+				); err != nil {
+					log.Error("failed to update account balance", "error", err)
+					return err
+				}
+				update.Balance = &balance
+				log.Info("Reversed completed refund", "new_balance", newBalance)
+			default:
+				log.Info("Refund already failed", "status", tx.Status)
+				return nil
+			}
+
+			if err := txRepo.Update(ctx, tx.ID, update); err != nil {
+				log.Error("failed to update refund transaction", "error", err)
+				return err
+			}
+			log.Info("✅ [SUCCESS] refund marked failed")
+			return nil
+		})
+	}
+}
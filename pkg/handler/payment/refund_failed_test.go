@@ -0,0 +1,102 @@
+package payment
+
+import (
+	"context"
+	"testing"
+
+	"github.com/amirasaad/fintech/pkg/domain/account"
+	"github.com/amirasaad/fintech/pkg/domain/events"
+	"github.com/amirasaad/fintech/pkg/dto"
+	"github.com/amirasaad/fintech/pkg/handler/testutils"
+	"github.com/amirasaad/fintech/pkg/money"
+	"github.com/amirasaad/fintech/pkg/repository"
+	repoaccount "github.com/amirasaad/fintech/pkg/repository/account"
+	repotransaction "github.com/amirasaad/fintech/pkg/repository/transaction"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRefundFailedHandler(t *testing.T) {
+	setup := func(t *testing.T, status account.TransactionStatus) (
+		*testutils.TestHelper,
+		*events.RefundFailed,
+	) {
+		h := testutils.New(t)
+		amount, err := money.New(30, money.USD)
+		require.NoError(t, err)
+		event := events.NewRefundFailed(
+			h.UserID, h.AccountID, h.TransactionID, "re_1", amount, "unknown",
+		)
+
+		h.UOW.EXPECT().Do(h.Ctx, mock.Anything).RunAndReturn(
+			func(ctx context.Context, fn func(repository.UnitOfWork) error) error {
+				return fn(h.UOW)
+			},
+		).Once()
+		h.UOW.EXPECT().
+			GetRepository((*repotransaction.Repository)(nil)).
+			Return(h.MockTxRepo, nil).
+			Once()
+		h.UOW.EXPECT().
+			GetRepository((*repoaccount.Repository)(nil)).
+			Return(h.MockAccRepo, nil).
+			Once()
+		tx := &dto.TransactionRead{
+			ID:        h.TransactionID,
+			AccountID: h.AccountID,
+			Status:    string(status),
+		}
+		h.MockTxRepo.EXPECT().GetByPaymentID(h.Ctx, "re_1").Return(tx, nil).Once()
+		h.MockAccRepo.EXPECT().
+			GetForUpdate(h.Ctx, h.AccountID).
+			Return(&dto.AccountRead{
+				ID: h.AccountID, UserID: h.UserID, Balance: 70, Currency: "USD",
+			}, nil).
+			Once()
+		h.MockTxRepo.EXPECT().Get(h.Ctx, h.TransactionID).Return(tx, nil).Once()
+		return h, event
+	}
+	failed := string(account.TransactionStatusFailed)
+	refundID := "re_1"
+
+	t.Run("fails a pending refund without touching the balance", func(t *testing.T) {
+		h, event := setup(t, account.TransactionStatusPending)
+		h.MockTxRepo.EXPECT().
+			Update(h.Ctx, h.TransactionID, dto.TransactionUpdate{
+				Status:    &failed,
+				PaymentID: &refundID,
+			}).
+			Return(nil).
+			Once()
+
+		err := HandleRefundFailed(h.UOW, h.Logger)(h.Ctx, event)
+		assert.NoError(t, err)
+	})
+
+	t.Run("credits back a refund that had completed", func(t *testing.T) {
+		h, event := setup(t, account.TransactionStatusCompleted)
+		var balance int64 = 10000
+		h.MockAccRepo.EXPECT().
+			Update(h.Ctx, h.AccountID, dto.AccountUpdate{Balance: &balance}).
+			Return(nil).
+			Once()
+		h.MockTxRepo.EXPECT().
+			Update(h.Ctx, h.TransactionID, dto.TransactionUpdate{
+				Status:    &failed,
+				PaymentID: &refundID,
+				Balance:   &balance,
+			}).
+			Return(nil).
+			Once()
+
+		err := HandleRefundFailed(h.UOW, h.Logger)(h.Ctx, event)
+		assert.NoError(t, err)
+	})
+
+	t.Run("skips refunds that already failed", func(t *testing.T) {
+		h, event := setup(t, account.TransactionStatusFailed)
+		err := HandleRefundFailed(h.UOW, h.Logger)(h.Ctx, event)
+		assert.NoError(t, err)
+	})
+}
@@ -0,0 +1,191 @@
+package payment
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/amirasaad/fintech/pkg/domain/account"
+	"github.com/amirasaad/fintech/pkg/domain/events"
+	"github.com/amirasaad/fintech/pkg/dto"
+	"github.com/amirasaad/fintech/pkg/eventbus"
+	"github.com/amirasaad/fintech/pkg/handler/common"
+	"github.com/amirasaad/fintech/pkg/mapper"
+	"github.com/amirasaad/fintech/pkg/money"
+	"github.com/amirasaad/fintech/pkg/repository"
+	"github.com/google/uuid"
+)
+
+// moneySourceReversal is the money source of deposit reversal transactions.
+const moneySourceReversal = "reversal"
+
+// ExtractPaymentReversedKey extracts idempotency key from PaymentReversed event
+func ExtractPaymentReversedKey(e events.Event) string {
+	pr, ok := e.(*events.PaymentReversed)
+	if !ok {
+		return ""
+	}
+	return pr.ReversalID
+}
+
+// HandleReversed handles PaymentReversed by debiting the reversed amount
+// from the account the deposit credited and emitting DepositReversed.
+//
+// The debit is recorded as a completed reversal transaction linked to the
+// deposit like a refund, so it counts against what can still be refunded,
+// and carrying the provider's reversal ID as its payment ID. A reversal the
+// provider reports again is found among the deposit's refunds and skipped;
+// concurrent duplicates are stopped by the unique payment ID.
+//
+// A reversal cannot be refused: if the balance and overdraft do not cover
+// it, the account is left owing the shortfall, which DepositReversed
+// reports, and withdrawals and transfers are refused until deposits repay it.
+func HandleReversed(
+	bus eventbus.Bus,
+	uow repository.UnitOfWork,
+	logger *slog.Logger,
+) eventbus.HandlerFunc {
+	return func(ctx context.Context, e events.Event) error {
+		log := logger.With(
+			"handler", "payment.HandleReversed",
+			"event_type", e.Type(),
+		)
+		pr, ok := e.(*events.PaymentReversed)
+		if !ok {
+			log.Error("Skipping unexpected event type", "event", e)
+			return nil
+		}
+		log = log.With(
+			"payment_id", pr.PaymentID,
+			"reversal_id", pr.ReversalID,
+		)
+
+		var reversed *events.DepositReversed
+		err := uow.Do(ctx, func(uow repository.UnitOfWork) error {
+			txRepo, err := common.GetTransactionRepository(uow, log)
+			if err != nil {
+				return err
+			}
+			accRepo, err := common.GetAccountRepository(uow, log)
+			if err != nil {
+				return err
+			}
+
+			lookup := common.LookupTransactionByPaymentOrID(
+				ctx, txRepo, &pr.PaymentID, uuid.Nil, log,
+			)
+			if lookup.Error != nil {
+				return lookup.Error
+			}
+			if !lookup.Found {
+				return nil
+			}
+			deposit := lookup.Transaction
+			log = log.With(
+				"transaction_id", deposit.ID,
+				"account_id", deposit.AccountID,
+			)
+			if deposit.Status != string(account.TransactionStatusCompleted) {
+				log.Warn("Deposit was never credited, nothing to reverse",
+					"status", deposit.Status,
+				)
+				return nil
+			}
+
+			// Lock the account row before checking for an earlier delivery
+			// of the reversal, so the check and the debit run as one.
+			acc, err := accRepo.GetForUpdate(ctx, deposit.AccountID)
+			if err != nil {
+				log.Error("failed to get account", "error", err)
+				return err
+			}
+			refunds, err := txRepo.ListRefunds(ctx, deposit.ID)
+			if err != nil {
+				log.Error("failed to list refunds", "error", err)
+				return err
+			}
+			for _, r := range refunds {
+				if r.PaymentID != nil && *r.PaymentID == pr.ReversalID {
+					log.Info("Reversal already applied")
+					return nil
+				}
+			}
+
+			domainAcc, err := mapper.MapAccountReadToDomain(acc)
+			if err != nil {
+				log.Error("failed to map account to domain", "error", err)
+				return err
+			}
+			shortfall, err := domainAcc.Reverse(pr.Amount)
+			if err != nil {
+				log.Error("failed to reverse deposit", "error", err)
+				return err
+			}
+
+			reversal := dto.TransactionCreate{
+				ID:                    uuid.New(),
+				UserID:                deposit.UserID,
+				AccountID:             deposit.AccountID,
+				PaymentID:             &pr.ReversalID,
+				Amount:                -int64(pr.Amount.Amount()),
+				Status:                string(account.TransactionStatusCompleted),
+				Currency:              pr.Amount.CurrencyCode().String(),
+				MoneySource:           moneySourceReversal,
+				RefundedTransactionID: &deposit.ID,
+			}
+			if err := txRepo.Create(ctx, reversal); err != nil {
+				log.Error("failed to create reversal transaction", "error", err)
+				return err
+			}
+			balance := domainAcc.Balance.Amount()
+			if err := txRepo.Update(ctx, reversal.ID, dto.TransactionUpdate{
+				Balance: &balance,
+			}); err != nil {
+				log.Error("failed to update reversal transaction", "error", err)
+				return err
+			}
+			if err := accRepo.Update(
+				ctx,
+				deposit.AccountID,
+				dto.AccountUpdate{Balance: &balance},
+			); err != nil {
+				log.Error("failed to update account balance", "error", err)
+				return err
+			}
+
+			reversed = events.NewDepositReversed(
+				deposit.UserID,
+				deposit.AccountID,
+				reversal.ID,
+				deposit.ID,
+				pr.ReversalID,
+				pr.Amount,
+				shortfall,
+				pr.Reason,
+			)
+			logReversal(log, domainAcc.Balance, shortfall)
+			return nil
+		})
+		if err != nil || reversed == nil {
+			return err
+		}
+
+		if err := bus.Emit(ctx, reversed); err != nil {
+			log.Error("failed to emit DepositReversed event", "error", err)
+			return err
+		}
+		return nil
+	}
+}
+
+// logReversal logs an applied reversal, as a warning if it left the account
+// owing money that has to be recovered.
+func logReversal(log *slog.Logger, balance, shortfall *money.Money) {
+	if shortfall.IsPositive() {
+		log.Warn("⚠️ Deposit reversed beyond available funds, pending recovery",
+			"new_balance", balance,
+			"shortfall", shortfall,
+		)
+		return
+	}
+	log.Info("✅ [SUCCESS] deposit reversed", "new_balance", balance)
+}
@@ -0,0 +1,131 @@
+package payment
+
+import (
+	"context"
+	"testing"
+
+	"github.com/amirasaad/fintech/pkg/domain/account"
+	"github.com/amirasaad/fintech/pkg/domain/events"
+	"github.com/amirasaad/fintech/pkg/dto"
+	"github.com/amirasaad/fintech/pkg/handler/testutils"
+	"github.com/amirasaad/fintech/pkg/money"
+	"github.com/amirasaad/fintech/pkg/repository"
+	repoaccount "github.com/amirasaad/fintech/pkg/repository/account"
+	repotransaction "github.com/amirasaad/fintech/pkg/repository/transaction"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReversedHandler(t *testing.T) {
+	setup := func(t *testing.T, refunds []*dto.TransactionRead) (
+		*testutils.TestHelper,
+		*events.PaymentReversed,
+	) {
+		h := testutils.New(t)
+		amount, err := money.New(80, money.USD)
+		require.NoError(t, err)
+		event := events.NewPaymentReversed("pi_1", "dp_1", amount, "fraudulent")
+
+		h.UOW.EXPECT().Do(h.Ctx, mock.Anything).RunAndReturn(
+			func(ctx context.Context, fn func(repository.UnitOfWork) error) error {
+				return fn(h.UOW)
+			},
+		).Once()
+		h.UOW.EXPECT().
+			GetRepository((*repotransaction.Repository)(nil)).
+			Return(h.MockTxRepo, nil).
+			Once()
+		h.UOW.EXPECT().
+			GetRepository((*repoaccount.Repository)(nil)).
+			Return(h.MockAccRepo, nil).
+			Once()
+		h.MockTxRepo.EXPECT().
+			GetByPaymentID(h.Ctx, "pi_1").
+			Return(&dto.TransactionRead{
+				ID:        h.TransactionID,
+				UserID:    h.UserID,
+				AccountID: h.AccountID,
+				Status:    string(account.TransactionStatusCompleted),
+			}, nil).
+			Once()
+		h.MockTxRepo.EXPECT().
+			ListRefunds(h.Ctx, h.TransactionID).
+			Return(refunds, nil).
+			Once()
+		return h, event
+	}
+
+	// expectReversal expects the account, holding balance with a 20.00
+	// overdraft, to be debited down to newBalance.
+	expectReversal := func(h *testutils.TestHelper, balance float64, newBalance int64) {
+		h.MockAccRepo.EXPECT().
+			GetForUpdate(h.Ctx, h.AccountID).
+			Return(&dto.AccountRead{
+				ID:             h.AccountID,
+				UserID:         h.UserID,
+				Balance:        balance,
+				OverdraftLimit: 20,
+				Currency:       "USD",
+			}, nil).
+			Once()
+		h.MockTxRepo.EXPECT().
+			Create(h.Ctx, mock.MatchedBy(func(tx dto.TransactionCreate) bool {
+				return tx.Amount == -8000 &&
+					*tx.PaymentID == "dp_1" &&
+					*tx.RefundedTransactionID == h.TransactionID &&
+					tx.Status == string(account.TransactionStatusCompleted)
+			})).
+			Return(nil).
+			Once()
+		h.MockTxRepo.EXPECT().
+			Update(h.Ctx, mock.Anything, dto.TransactionUpdate{Balance: &newBalance}).
+			Return(nil).
+			Once()
+		h.MockAccRepo.EXPECT().
+			Update(h.Ctx, h.AccountID, dto.AccountUpdate{Balance: &newBalance}).
+			Return(nil).
+			Once()
+	}
+
+	t.Run("debits a covered reversal", func(t *testing.T) {
+		h, event := setup(t, nil)
+		expectReversal(h, 100, 2000)
+		h.Bus.EXPECT().
+			Emit(h.Ctx, mock.MatchedBy(func(dr *events.DepositReversed) bool {
+				return dr.DepositTransactionID == h.TransactionID &&
+					dr.AccountID == h.AccountID &&
+					!dr.PendingRecovery()
+			})).
+			Return(nil).
+			Once()
+
+		err := HandleReversed(h.Bus, h.UOW, h.Logger)(h.Ctx, event)
+		assert.NoError(t, err)
+	})
+
+	t.Run("records the shortfall beyond the overdraft", func(t *testing.T) {
+		h, event := setup(t, nil)
+		expectReversal(h, 50, -3000)
+		h.Bus.EXPECT().
+			Emit(h.Ctx, mock.MatchedBy(func(dr *events.DepositReversed) bool {
+				return dr.PendingRecovery() && dr.Shortfall.Amount() == 1000
+			})).
+			Return(nil).
+			Once()
+
+		err := HandleReversed(h.Bus, h.UOW, h.Logger)(h.Ctx, event)
+		assert.NoError(t, err)
+	})
+
+	t.Run("skips reversals that were already applied", func(t *testing.T) {
+		reversalID := "dp_1"
+		h, event := setup(t, []*dto.TransactionRead{{PaymentID: &reversalID}})
+		h.MockAccRepo.EXPECT().
+			GetForUpdate(h.Ctx, h.AccountID).
+			Return(&dto.AccountRead{ID: h.AccountID, Currency: "USD"}, nil).
+			Once()
+		err := HandleReversed(h.Bus, h.UOW, h.Logger)(h.Ctx, event)
+		assert.NoError(t, err)
+	})
+}
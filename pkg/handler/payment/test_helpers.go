@@ -109,9 +109,13 @@ func setupSuccessfulTest(h *testutils.TestHelper) {
 			).Once()
 		h.MockAccRepo.
 			EXPECT().
-			Get(ctx, h.AccountID).
+			GetForUpdate(ctx, h.AccountID).
 			Return(testAccount, nil).
 			Once()
+		h.MockTxRepo.EXPECT().
+			Get(ctx, h.TransactionID).
+			Return(tx, nil).
+			Once()
 
 		// Setup mock expectations for account update
 		h.MockAccRepo.EXPECT().
@@ -0,0 +1,343 @@
+// Package iso20022 builds ISO 20022 payment messages for banks and
+// enterprise finance systems.
+package iso20022
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/amirasaad/fintech/pkg/money"
+	"github.com/amirasaad/fintech/pkg/validation"
+)
+
+// Pain001Namespace is the XML namespace of the generated documents,
+// Customer Credit Transfer Initiation version 3.
+const Pain001Namespace = "urn:iso:std:iso:20022:tech:xsd:pain.001.001.03"
+
+// Field length limits of the pain.001.001.03 schema.
+const (
+	maxIDLength   = 35  // Max35Text: MsgId, PmtInfId, EndToEndId
+	maxNameLength = 70  // Max70Text: party names
+	maxRemittance = 140 // Max140Text: unstructured remittance information
+)
+
+// ErrIncompleteTransfer is returned when the debtor or a credit transfer is
+// missing details required by pain.001, such as an IBAN or BIC.
+var ErrIncompleteTransfer = errors.New("incomplete credit transfer")
+
+// Party is the debtor or creditor of a credit transfer and its bank account.
+type Party struct {
+	Name string
+	IBAN string
+	BIC  string
+}
+
+// CreditTransfer is one payment to a creditor.
+type CreditTransfer struct {
+	// EndToEndID identifies the payment end to end, e.g. the transaction ID.
+	EndToEndID     string
+	Amount         *money.Money
+	Creditor       Party
+	RemittanceInfo string
+}
+
+// PaymentInitiation is a batch of credit transfers from one debtor account.
+type PaymentInitiation struct {
+	MessageID     string
+	CreatedAt     time.Time
+	ExecutionDate time.Time
+	Debtor        Party
+	Transfers     []CreditTransfer
+}
+
+// Problem describes why one party cannot be paid. EndToEndID is empty for
+// problems with the debtor.
+type Problem struct {
+	EndToEndID string `json:"end_to_end_id,omitempty"`
+	Field      string `json:"field"`
+	Message    string `json:"message"`
+}
+
+// ValidationError lists every problem found by PaymentInitiation.Validate.
+// It unwraps to ErrIncompleteTransfer.
+type ValidationError struct {
+	Problems []Problem
+}
+
+// Error implements the error interface.
+func (e *ValidationError) Error() string {
+	msgs := make([]string, 0, len(e.Problems))
+	for _, p := range e.Problems {
+		msg := p.Field + ": " + p.Message
+		if p.EndToEndID != "" {
+			msg = p.EndToEndID + " " + msg
+		}
+		msgs = append(msgs, msg)
+	}
+	return ErrIncompleteTransfer.Error() + ": " + strings.Join(msgs, "; ")
+}
+
+// Unwrap returns ErrIncompleteTransfer.
+func (e *ValidationError) Unwrap() error {
+	return ErrIncompleteTransfer
+}
+
+// Validate checks that the message has an ID and transfers, and that the
+// debtor and every creditor have a name, a valid IBAN and a valid BIC.
+// Every problem is reported, not just the first.
+func (p *PaymentInitiation) Validate() error {
+	var problems []Problem
+	if p.MessageID == "" || len(p.MessageID) > maxIDLength {
+		problems = append(problems, Problem{
+			Field:   "message_id",
+			Message: fmt.Sprintf("must be 1 to %d characters", maxIDLength),
+		})
+	}
+	if len(p.Transfers) == 0 {
+		problems = append(problems, Problem{
+			Field:   "transfers",
+			Message: "at least one credit transfer is required",
+		})
+	}
+	problems = append(problems, partyProblems("", "debtor", p.Debtor)...)
+	for _, t := range p.Transfers {
+		if t.EndToEndID == "" || len(t.EndToEndID) > maxIDLength {
+			problems = append(problems, Problem{
+				EndToEndID: t.EndToEndID,
+				Field:      "end_to_end_id",
+				Message:    fmt.Sprintf("must be 1 to %d characters", maxIDLength),
+			})
+		}
+		if t.Amount == nil || !t.Amount.IsPositive() {
+			problems = append(problems, Problem{
+				EndToEndID: t.EndToEndID,
+				Field:      "amount",
+				Message:    "must be positive",
+			})
+		}
+		problems = append(problems, partyProblems(t.EndToEndID, "creditor", t.Creditor)...)
+	}
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
+
+func partyProblems(endToEndID, role string, party Party) []Problem {
+	var problems []Problem
+	add := func(field, message string) {
+		problems = append(problems, Problem{
+			EndToEndID: endToEndID,
+			Field:      role + "_" + field,
+			Message:    message,
+		})
+	}
+	if strings.TrimSpace(party.Name) == "" {
+		add("name", "is required")
+	}
+	switch {
+	case party.IBAN == "":
+		add("iban", "is required")
+	case validation.ValidateIBAN(party.IBAN) != nil:
+		add("iban", validation.ErrInvalidIBAN.Error())
+	}
+	switch {
+	case party.BIC == "":
+		add("bic", "is required")
+	case validation.ValidateBIC(party.BIC) != nil:
+		add("bic", validation.ErrInvalidBIC.Error())
+	}
+	return problems
+}
+
+// MarshalPain001 validates the message and renders it as a pain.001.001.03
+// document. Transfers are grouped into one payment information block per
+// currency, in order of first appearance; EUR blocks use the SEPA service
+// level.
+func (p *PaymentInitiation) MarshalPain001() ([]byte, error) {
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+
+	var (
+		blocks   []*paymentInformation
+		byCcy    = map[string]*paymentInformation{}
+		sums     = map[string]*big.Rat{}
+		decimals = map[string]int{}
+		total    = new(big.Rat)
+		maxDec   = 0
+	)
+	for _, t := range p.Transfers {
+		ccy := t.Amount.Currency().String()
+		block, ok := byCcy[ccy]
+		if !ok {
+			block = newPaymentInformation(p, ccy)
+			byCcy[ccy] = block
+			sums[ccy] = new(big.Rat)
+			decimals[ccy] = t.Amount.Currency().Decimals
+			maxDec = max(maxDec, decimals[ccy])
+			blocks = append(blocks, block)
+		}
+		amount := t.Amount.AmountString()
+		r, _ := new(big.Rat).SetString(amount)
+		sums[ccy].Add(sums[ccy], r)
+		total.Add(total, r)
+
+		block.Transactions = append(block.Transactions, creditTransferTransaction{
+			EndToEndID: t.EndToEndID,
+			Amount:     instructedAmount{Currency: ccy, Value: amount},
+			CreditorAgent: &agent{
+				FinancialInstitution: financialInstitution{BIC: normalize(t.Creditor.BIC)},
+			},
+			Creditor:        party{Name: truncate(t.Creditor.Name, maxNameLength)},
+			CreditorAccount: account{IBAN: normalize(t.Creditor.IBAN)},
+			RemittanceInfo:  remittance(t.RemittanceInfo),
+		})
+	}
+	for _, block := range blocks {
+		block.NumberOfTransactions = len(block.Transactions)
+		ccy := block.Transactions[0].Amount.Currency
+		block.ControlSum = sums[ccy].FloatString(decimals[ccy])
+	}
+
+	doc := document{
+		Namespace: Pain001Namespace,
+		Initiation: customerCreditTransferInitiation{
+			GroupHeader: groupHeader{
+				MessageID:            p.MessageID,
+				CreatedAt:            p.CreatedAt.UTC().Format("2006-01-02T15:04:05"),
+				NumberOfTransactions: len(p.Transfers),
+				ControlSum:           total.FloatString(maxDec),
+				InitiatingParty:      party{Name: truncate(p.Debtor.Name, maxNameLength)},
+			},
+			PaymentInformation: blocks,
+		},
+	}
+	out, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal pain.001 document: %w", err)
+	}
+	return append([]byte(xml.Header), out...), nil
+}
+
+func newPaymentInformation(p *PaymentInitiation, ccy string) *paymentInformation {
+	block := &paymentInformation{
+		ID:            truncate(p.MessageID+"-"+ccy, maxIDLength),
+		Method:        "TRF",
+		BatchBooking:  true,
+		ExecutionDate: p.ExecutionDate.UTC().Format("2006-01-02"),
+		Debtor:        party{Name: truncate(p.Debtor.Name, maxNameLength)},
+		DebtorAccount: account{IBAN: normalize(p.Debtor.IBAN)},
+		DebtorAgent: agent{
+			FinancialInstitution: financialInstitution{BIC: normalize(p.Debtor.BIC)},
+		},
+		ChargeBearer: "SHAR",
+	}
+	if ccy == "EUR" {
+		block.PaymentType = &paymentType{ServiceLevel: serviceLevel{Code: "SEPA"}}
+		block.ChargeBearer = "SLEV"
+	}
+	return block
+}
+
+// normalize strips spaces from and upper-cases an IBAN or BIC.
+func normalize(s string) string {
+	return strings.ToUpper(strings.ReplaceAll(s, " ", ""))
+}
+
+func truncate(s string, n int) string {
+	s = strings.TrimSpace(s)
+	if r := []rune(s); len(r) > n {
+		return string(r[:n])
+	}
+	return s
+}
+
+func remittance(s string) *remittanceInformation {
+	if s = truncate(s, maxRemittance); s == "" {
+		return nil
+	}
+	return &remittanceInformation{Unstructured: s}
+}
+
+// XML schema elements, in schema order.
+
+type document struct {
+	XMLName    xml.Name                         `xml:"Document"`
+	Namespace  string                           `xml:"xmlns,attr"`
+	Initiation customerCreditTransferInitiation `xml:"CstmrCdtTrfInitn"`
+}
+
+type customerCreditTransferInitiation struct {
+	GroupHeader        groupHeader           `xml:"GrpHdr"`
+	PaymentInformation []*paymentInformation `xml:"PmtInf"`
+}
+
+type groupHeader struct {
+	MessageID            string `xml:"MsgId"`
+	CreatedAt            string `xml:"CreDtTm"`
+	NumberOfTransactions int    `xml:"NbOfTxs"`
+	ControlSum           string `xml:"CtrlSum"`
+	InitiatingParty      party  `xml:"InitgPty"`
+}
+
+type paymentInformation struct {
+	ID                   string                      `xml:"PmtInfId"`
+	Method               string                      `xml:"PmtMtd"`
+	BatchBooking         bool                        `xml:"BtchBookg"`
+	NumberOfTransactions int                         `xml:"NbOfTxs"`
+	ControlSum           string                      `xml:"CtrlSum"`
+	PaymentType          *paymentType                `xml:"PmtTpInf,omitempty"`
+	ExecutionDate        string                      `xml:"ReqdExctnDt"`
+	Debtor               party                       `xml:"Dbtr"`
+	DebtorAccount        account                     `xml:"DbtrAcct"`
+	DebtorAgent          agent                       `xml:"DbtrAgt"`
+	ChargeBearer         string                      `xml:"ChrgBr"`
+	Transactions         []creditTransferTransaction `xml:"CdtTrfTxInf"`
+}
+
+type paymentType struct {
+	ServiceLevel serviceLevel `xml:"SvcLvl"`
+}
+
+type serviceLevel struct {
+	Code string `xml:"Cd"`
+}
+
+type creditTransferTransaction struct {
+	EndToEndID      string                 `xml:"PmtId>EndToEndId"`
+	Amount          instructedAmount       `xml:"Amt>InstdAmt"`
+	CreditorAgent   *agent                 `xml:"CdtrAgt,omitempty"`
+	Creditor        party                  `xml:"Cdtr"`
+	CreditorAccount account                `xml:"CdtrAcct"`
+	RemittanceInfo  *remittanceInformation `xml:"RmtInf,omitempty"`
+}
+
+type instructedAmount struct {
+	Currency string `xml:"Ccy,attr"`
+	Value    string `xml:",chardata"`
+}
+
+type party struct {
+	Name string `xml:"Nm"`
+}
+
+type account struct {
+	IBAN string `xml:"Id>IBAN"`
+}
+
+type agent struct {
+	FinancialInstitution financialInstitution `xml:"FinInstnId"`
+}
+
+type financialInstitution struct {
+	BIC string `xml:"BIC"`
+}
+
+type remittanceInformation struct {
+	Unstructured string `xml:"Ustrd"`
+}
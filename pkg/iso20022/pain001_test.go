@@ -0,0 +1,146 @@
+package iso20022_test
+
+import (
+	"encoding/xml"
+	"testing"
+	"time"
+
+	"github.com/amirasaad/fintech/pkg/iso20022"
+	"github.com/amirasaad/fintech/pkg/money"
+	"github.com/amirasaad/fintech/pkg/validation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func mustMoney(t *testing.T, amount float64, code money.Code) *money.Money {
+	t.Helper()
+	m, err := money.New(amount, code)
+	require.NoError(t, err)
+	return m
+}
+
+func creditor(name string) iso20022.Party {
+	return iso20022.Party{Name: name, IBAN: "GB82WEST12345698765432", BIC: "NWBKGB2L"}
+}
+
+func newInitiation(t *testing.T) *iso20022.PaymentInitiation {
+	return &iso20022.PaymentInitiation{
+		MessageID:     "MSG-1",
+		CreatedAt:     time.Date(2026, 3, 2, 9, 30, 0, 0, time.UTC),
+		ExecutionDate: time.Date(2026, 3, 3, 0, 0, 0, 0, time.UTC),
+		Debtor: iso20022.Party{
+			Name: "Fintech Ltd",
+			IBAN: "DE89 3704 0044 0532 0130 00",
+			BIC:  "COBADEFFXXX",
+		},
+		Transfers: []iso20022.CreditTransfer{
+			{
+				EndToEndID:     "E2E-1",
+				Amount:         mustMoney(t, 10.5, "EUR"),
+				Creditor:       creditor("Alice"),
+				RemittanceInfo: "Withdrawal 1",
+			},
+			{
+				EndToEndID: "E2E-2",
+				Amount:     mustMoney(t, 20, "USD"),
+				Creditor:   creditor("Bob"),
+			},
+			{
+				EndToEndID: "E2E-3",
+				Amount:     mustMoney(t, 4.25, "EUR"),
+				Creditor:   creditor("Carol"),
+			},
+		},
+	}
+}
+
+// pain001 mirrors the parts of the schema the tests inspect.
+type pain001 struct {
+	XMLName xml.Name `xml:"urn:iso:std:iso:20022:tech:xsd:pain.001.001.03 Document"`
+	GrpHdr  struct {
+		MsgID   string `xml:"MsgId"`
+		CreDtTm string `xml:"CreDtTm"`
+		NbOfTxs int    `xml:"NbOfTxs"`
+		CtrlSum string `xml:"CtrlSum"`
+	} `xml:"CstmrCdtTrfInitn>GrpHdr"`
+	PmtInf []struct {
+		NbOfTxs     int    `xml:"NbOfTxs"`
+		CtrlSum     string `xml:"CtrlSum"`
+		SvcLvl      string `xml:"PmtTpInf>SvcLvl>Cd"`
+		ReqdExctnDt string `xml:"ReqdExctnDt"`
+		DbtrIBAN    string `xml:"DbtrAcct>Id>IBAN"`
+		ChrgBr      string `xml:"ChrgBr"`
+		CdtTrfTxInf []struct {
+			EndToEndID string `xml:"PmtId>EndToEndId"`
+			Amt        struct {
+				Ccy   string `xml:"Ccy,attr"`
+				Value string `xml:",chardata"`
+			} `xml:"Amt>InstdAmt"`
+			BIC   string `xml:"CdtrAgt>FinInstnId>BIC"`
+			Name  string `xml:"Cdtr>Nm"`
+			IBAN  string `xml:"CdtrAcct>Id>IBAN"`
+			Ustrd string `xml:"RmtInf>Ustrd"`
+		} `xml:"CdtTrfTxInf"`
+	} `xml:"CstmrCdtTrfInitn>PmtInf"`
+}
+
+func TestMarshalPain001(t *testing.T) {
+	out, err := newInitiation(t).MarshalPain001()
+	require.NoError(t, err)
+
+	var doc pain001
+	require.NoError(t, xml.Unmarshal(out, &doc))
+	assert.Equal(t, "MSG-1", doc.GrpHdr.MsgID)
+	assert.Equal(t, "2026-03-02T09:30:00", doc.GrpHdr.CreDtTm)
+	assert.Equal(t, 3, doc.GrpHdr.NbOfTxs)
+	assert.Equal(t, "34.75", doc.GrpHdr.CtrlSum)
+
+	require.Len(t, doc.PmtInf, 2, "one payment information block per currency")
+	eur, usd := doc.PmtInf[0], doc.PmtInf[1]
+	assert.Equal(t, 2, eur.NbOfTxs)
+	assert.Equal(t, "14.75", eur.CtrlSum)
+	assert.Equal(t, "SEPA", eur.SvcLvl)
+	assert.Equal(t, "SLEV", eur.ChrgBr)
+	assert.Equal(t, "2026-03-03", eur.ReqdExctnDt)
+	assert.Equal(t, "DE89370400440532013000", eur.DbtrIBAN)
+	assert.Equal(t, "E2E-1", eur.CdtTrfTxInf[0].EndToEndID)
+	assert.Equal(t, "EUR", eur.CdtTrfTxInf[0].Amt.Ccy)
+	assert.Equal(t, "10.50", eur.CdtTrfTxInf[0].Amt.Value)
+	assert.Equal(t, "NWBKGB2L", eur.CdtTrfTxInf[0].BIC)
+	assert.Equal(t, "Alice", eur.CdtTrfTxInf[0].Name)
+	assert.Equal(t, "Withdrawal 1", eur.CdtTrfTxInf[0].Ustrd)
+	assert.Equal(t, "E2E-3", eur.CdtTrfTxInf[1].EndToEndID)
+
+	assert.Equal(t, 1, usd.NbOfTxs)
+	assert.Equal(t, "20.00", usd.CtrlSum)
+	assert.Empty(t, usd.SvcLvl)
+	assert.Equal(t, "SHAR", usd.ChrgBr)
+}
+
+func TestValidateReportsEveryProblem(t *testing.T) {
+	p := newInitiation(t)
+	p.Debtor.BIC = ""
+	p.Transfers[0].Creditor.IBAN = "GB82WEST12345698765433"
+	p.Transfers[1].Creditor.BIC = "NWBK1B2L"
+	p.Transfers[2].Creditor.Name = " "
+
+	_, err := p.MarshalPain001()
+	require.ErrorIs(t, err, iso20022.ErrIncompleteTransfer)
+	var verr *iso20022.ValidationError
+	require.ErrorAs(t, err, &verr)
+	assert.Equal(t, []iso20022.Problem{
+		{Field: "debtor_bic", Message: "is required"},
+		{EndToEndID: "E2E-1", Field: "creditor_iban", Message: validation.ErrInvalidIBAN.Error()},
+		{EndToEndID: "E2E-2", Field: "creditor_bic", Message: validation.ErrInvalidBIC.Error()},
+		{EndToEndID: "E2E-3", Field: "creditor_name", Message: "is required"},
+	}, verr.Problems)
+}
+
+func TestValidateEmptyBatch(t *testing.T) {
+	p := newInitiation(t)
+	p.Transfers = nil
+	var verr *iso20022.ValidationError
+	require.ErrorAs(t, p.Validate(), &verr)
+	require.Len(t, verr.Problems, 1)
+	assert.Equal(t, "transfers", verr.Problems[0].Field)
+}
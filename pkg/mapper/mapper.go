@@ -14,10 +14,15 @@ func MapAccountReadToDomain(dto *dto.AccountRead) (*account.Account, error) {
 	if err != nil {
 		return nil, fmt.Errorf("error creating money from dto: %w", err)
 	}
+	overdraftLimit, err := money.New(dto.OverdraftLimit, money.Code(dto.Currency))
+	if err != nil {
+		return nil, fmt.Errorf("error creating overdraft limit from dto: %w", err)
+	}
 	acc, err := account.New().
 		WithID(dto.ID).
 		WithUserID(dto.UserID).
 		WithBalance(balance.Amount()).
+		WithOverdraftLimit(overdraftLimit.Amount()).
 		WithCurrency(money.Code(balance.Currency().String())).
 		WithCreatedAt(dto.CreatedAt).
 		WithUpdatedAt(dto.UpdatedAt).
@@ -4,16 +4,67 @@ import (
 	"github.com/amirasaad/fintech/pkg/config"
 	jwtware "github.com/gofiber/contrib/jwt"
 	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
 )
 
-// JwtProtected protect routes
+// JwtProtected protect routes. Tokens must also carry the configured issuer
+// and audience, if any.
 func JwtProtected(cfg *config.Jwt) fiber.Handler {
+	var claimOpts []jwt.ParserOption
+	if cfg.Issuer != "" {
+		claimOpts = append(claimOpts, jwt.WithIssuer(cfg.Issuer))
+	}
+	if cfg.Audience != "" {
+		claimOpts = append(claimOpts, jwt.WithAudience(cfg.Audience))
+	}
+	var success fiber.Handler
+	if len(claimOpts) > 0 {
+		success = validateClaims(jwt.NewValidator(claimOpts...))
+	}
 	return jwtware.New(jwtware.Config{
-		SigningKey:   jwtware.SigningKey{Key: []byte(cfg.Secret)},
-		ErrorHandler: jwtError,
+		SigningKey:     jwtware.SigningKey{Key: []byte(cfg.Secret)},
+		ErrorHandler:   jwtError,
+		SuccessHandler: success,
 	})
 }
 
+// validateClaims rejects verified tokens whose claims fail validator, e.g.
+// tokens minted by or for another service.
+func validateClaims(validator *jwt.Validator) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		token, ok := c.Locals("user").(*jwt.Token)
+		if !ok {
+			return jwtError(c, jwt.ErrTokenInvalidClaims)
+		}
+		if err := validator.Validate(token.Claims); err != nil {
+			return jwtError(c, err)
+		}
+		return c.Next()
+	}
+}
+
+// RequireRole allows the request only if the JWT's role claim is one of roles.
+// It must run after JwtProtected.
+func RequireRole(roles ...string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		token, ok := c.Locals("user").(*jwt.Token)
+		if !ok {
+			return c.Status(fiber.StatusUnauthorized).
+				JSON(fiber.Map{"status": "error", "message": "Missing user context", "data": nil})
+		}
+		if claims, ok := token.Claims.(jwt.MapClaims); ok {
+			role, _ := claims["role"].(string)
+			for _, r := range roles {
+				if role == r {
+					return c.Next()
+				}
+			}
+		}
+		return c.Status(fiber.StatusForbidden).
+			JSON(fiber.Map{"status": "error", "message": "Insufficient role", "data": nil})
+	}
+}
+
 func jwtError(c *fiber.Ctx, err error) error {
 	if err.Error() == "Missing or malformed JWT" {
 		return c.Status(fiber.StatusBadRequest).
@@ -4,12 +4,14 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/amirasaad/fintech/pkg/config"
 
 	"errors"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
 )
 
 func TestProtected_Unauthorized(t *testing.T) {
@@ -47,3 +49,83 @@ func TestJwtError_Invalid(t *testing.T) {
 		t.Errorf("expected %d, got %d", fiber.StatusUnauthorized, resp.StatusCode)
 	}
 }
+
+func TestRequireRole(t *testing.T) {
+	tests := []struct {
+		name   string
+		claims jwt.MapClaims
+		want   int
+	}{
+		{"admin", jwt.MapClaims{"role": "admin"}, fiber.StatusOK},
+		{"user", jwt.MapClaims{"role": "user"}, fiber.StatusForbidden},
+		{"no role", jwt.MapClaims{}, fiber.StatusForbidden},
+		{"no token", nil, fiber.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New()
+			app.Use(func(c *fiber.Ctx) error {
+				if tt.claims != nil {
+					c.Locals("user", jwt.NewWithClaims(jwt.SigningMethodHS256, tt.claims))
+				}
+				return c.Next()
+			})
+			app.Use(RequireRole("admin"))
+			app.Get("/", func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusOK) })
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			resp, _ := app.Test(req)
+			if resp.StatusCode != tt.want {
+				t.Errorf("expected %d, got %d", tt.want, resp.StatusCode)
+			}
+		})
+	}
+}
+
+func TestJwtProtected_IssuerAudience(t *testing.T) {
+	cfg := &config.Jwt{Secret: "secret", Issuer: "fintech", Audience: "fintech-api"}
+	sign := func(claims jwt.MapClaims) string {
+		claims["exp"] = time.Now().Add(time.Hour).Unix()
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(cfg.Secret))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return token
+	}
+	tests := []struct {
+		name   string
+		claims jwt.MapClaims
+		want   int
+	}{
+		{"valid", jwt.MapClaims{"iss": "fintech", "aud": "fintech-api"}, fiber.StatusOK},
+		{"audience list", jwt.MapClaims{"iss": "fintech", "aud": []string{"other", "fintech-api"}}, fiber.StatusOK},
+		{"wrong audience", jwt.MapClaims{"iss": "fintech", "aud": "other-api"}, fiber.StatusUnauthorized},
+		{"wrong issuer", jwt.MapClaims{"iss": "other", "aud": "fintech-api"}, fiber.StatusUnauthorized},
+		{"missing claims", jwt.MapClaims{}, fiber.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New()
+			app.Use(JwtProtected(cfg))
+			app.Get("/", func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusOK) })
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("Authorization", "Bearer "+sign(tt.claims))
+			resp, _ := app.Test(req)
+			if resp.StatusCode != tt.want {
+				t.Errorf("expected %d, got %d", tt.want, resp.StatusCode)
+			}
+		})
+	}
+
+	// Without an expected issuer and audience, any are accepted.
+	app := fiber.New()
+	app.Use(JwtProtected(&config.Jwt{Secret: cfg.Secret}))
+	app.Get("/", func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusOK) })
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer "+sign(jwt.MapClaims{"iss": "other"}))
+	resp, _ := app.Test(req)
+	if resp.StatusCode != fiber.StatusOK {
+		t.Errorf("expected %d, got %d", fiber.StatusOK, resp.StatusCode)
+	}
+}
@@ -10,4 +10,11 @@ var (
 
 	// ErrNegativeAmount is returned when an operation would result in a negative amount
 	ErrNegativeAmount = errors.New("resulting amount cannot be negative")
+
+	// ErrInvalidSplit is returned when splitting into a non-positive number of parts
+	ErrInvalidSplit = errors.New("split count must be positive")
+
+	// ErrInvalidAmountPrecision is returned when an amount has more decimal
+	// places than its currency allows
+	ErrInvalidAmountPrecision = errors.New("amount has too many decimal places")
 )
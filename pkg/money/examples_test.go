@@ -1,6 +1,7 @@
 package money_test
 
 import (
+	"errors"
 	"fmt"
 	"log"
 
@@ -117,6 +118,19 @@ func ExampleMoney_comparison() {
 	// Less than: false
 }
 
+// ExampleMoney_Equal demonstrates that comparisons across currencies fail
+func ExampleMoney_Equal() {
+	usd, _ := money.New(100, money.USD)
+	eur, _ := money.New(100, money.EUR)
+
+	_, err := usd.Equal(eur)
+	fmt.Printf("Mismatched: %t\n", errors.Is(err, money.ErrMismatchedCurrencies))
+	fmt.Printf("Equals: %t\n", usd.Equals(eur))
+	// Output:
+	// Mismatched: true
+	// Equals: false
+}
+
 // ExampleMoney_IsPositive demonstrates checking if money is positive
 func ExampleMoney_IsPositive() {
 	// Create positive and negative amounts
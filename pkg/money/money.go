@@ -12,6 +12,8 @@ import (
 	"fmt"
 	"math"
 	"math/big"
+	"strconv"
+	"strings"
 )
 
 var (
@@ -21,8 +23,7 @@ var (
 	// ErrAmountExceedsMaxSafeInt is returned when an amount exceeds the maximum safe integer value.
 	ErrAmountExceedsMaxSafeInt = fmt.Errorf("amount exceeds maximum safe integer value")
 
-	// ErrMismatchedCurrencies is returned when performing operations
-	// on money with different currencies.
+	// ErrInvalidCurrency is returned when an invalid currency code is provided.
 	ErrInvalidCurrency = fmt.Errorf("invalid currency code")
 )
 
@@ -352,8 +353,8 @@ func (m *Money) Negate() *Money {
 }
 
 // Equals checks if the current Money object is equal to another Money object.
-// Invariants enforced:
-//   - Currencies must match.
+// Unlike Equal, it reports false rather than an error when either is nil or
+// the currencies differ.
 func (m *Money) Equals(other *Money) bool {
 	if m == nil || other == nil {
 		return false
@@ -362,33 +363,50 @@ func (m *Money) Equals(other *Money) bool {
 	return m.currency == other.currency && m.amount == other.amount
 }
 
+// Equal reports whether the current Money object has the same amount as
+// another Money object.
+//
+// Equal, GreaterThan and LessThan never panic on a currency mismatch; they
+// return an error wrapping ErrMismatchedCurrencies.
+func (m *Money) Equal(other *Money) (bool, error) {
+	if err := m.checkComparable(other); err != nil {
+		return false, err
+	}
+	return m.amount == other.amount, nil
+}
+
 // GreaterThan checks if the current Money object is greater than another Money object.
-// Invariants enforced:
-//   - Currencies must match.
+// Returns an error wrapping ErrMismatchedCurrencies if currencies do not match.
 func (m *Money) GreaterThan(other *Money) (bool, error) {
-	if m.currency != other.currency {
-		return false, fmt.Errorf(
-			"cannot compare different currencies: %s and %s",
-			m.currency.Code,
-			other.currency.Code,
-		)
+	if err := m.checkComparable(other); err != nil {
+		return false, err
 	}
-
 	return m.amount > other.amount, nil
 }
 
 // LessThan checks if the current Money object is less than another Money object.
-// Invariants enforced:
-//   - Currencies must match.
-//
-// Returns an error if currencies do not match.
+// Returns an error wrapping ErrMismatchedCurrencies if currencies do not match.
 func (m *Money) LessThan(other *Money) (bool, error) {
-	if !m.IsSameCurrency(other) {
-		return false, ErrInvalidCurrency
+	if err := m.checkComparable(other); err != nil {
+		return false, err
 	}
 	return m.amount < other.amount, nil
 }
 
+// checkComparable returns an error wrapping ErrMismatchedCurrencies if the
+// currencies of m and other differ.
+func (m *Money) checkComparable(other *Money) error {
+	if !m.IsSameCurrency(other) {
+		return fmt.Errorf(
+			"%w: cannot compare %s and %s",
+			ErrMismatchedCurrencies,
+			m.currency.Code,
+			other.currency.Code,
+		)
+	}
+	return nil
+}
+
 // IsSameCurrency checks if the current Money object has the same currency as another Money object.
 func (m *Money) IsSameCurrency(other *Money) bool {
 	return m.currency == other.currency
@@ -485,11 +503,82 @@ func (m *Money) Divide(divisor float64) (*Money, error) {
 	}, nil
 }
 
+// Split divides the Money amount into n parts as evenly as possible in the
+// smallest currency unit. The remainder is distributed one unit at a time to
+// the first parts, so the parts always sum exactly to the original amount.
+// For example, 100.00 USD split 3 ways is 33.34, 33.33 and 33.33.
+//
+// Returns ErrInvalidSplit if n is not positive.
+func (m *Money) Split(n int) ([]Money, error) {
+	if n <= 0 {
+		return nil, fmt.Errorf("%w: %d", ErrInvalidSplit, n)
+	}
+
+	// Go's integer division truncates toward zero, so the remainder has the
+	// sign of the amount and the extra unit goes in the same direction.
+	share := m.amount / Amount(n)
+	remainder := m.amount % Amount(n)
+	unit := Amount(1)
+	if remainder < 0 {
+		unit, remainder = -1, -remainder
+	}
+
+	parts := make([]Money, n)
+	for i := range parts {
+		parts[i] = Money{amount: share, currency: m.currency}
+		if Amount(i) < remainder {
+			parts[i].amount += unit
+		}
+	}
+	return parts, nil
+}
+
 // String returns a string representation of the Money object.
 func (m *Money) String() string {
 	return fmt.Sprintf("%.*f %s", m.currency.Decimals, m.AmountFloat(), m.currency.Code)
 }
 
+// AmountString returns the amount in the main currency unit with exactly the
+// currency's number of decimal places, e.g. "1234.50" for USD or "1235" for
+// JPY. Unlike String it is computed from the smallest unit without going
+// through float64, so it is exact for any amount.
+func (m *Money) AmountString() string {
+	amount := new(big.Int).SetInt64(int64(m.amount))
+	sign := ""
+	if amount.Sign() < 0 {
+		sign = "-"
+		amount.Neg(amount)
+	}
+	digits := amount.String()
+	decimals := m.currency.Decimals
+	if decimals == 0 {
+		return sign + digits
+	}
+	if len(digits) <= decimals {
+		digits = strings.Repeat("0", decimals-len(digits)+1) + digits
+	}
+	split := len(digits) - decimals
+	return sign + digits[:split] + "." + digits[split:]
+}
+
+// ValidatePrecision returns an error wrapping ErrInvalidAmountPrecision if
+// amount has more decimal places than currency allows, e.g. 10.999 USD.
+// New would otherwise round such amounts silently.
+func ValidatePrecision(amount float64, currency Currency) error {
+	// The shortest representation that parses back to amount has the
+	// decimals the amount was written with, e.g. in a JSON request.
+	s := strconv.FormatFloat(amount, 'f', -1, 64)
+	if _, fraction, found := strings.Cut(s, "."); found && len(fraction) > currency.Decimals {
+		return fmt.Errorf(
+			"%w: %s allows at most %d decimal places",
+			ErrInvalidAmountPrecision,
+			currency.Code,
+			currency.Decimals,
+		)
+	}
+	return nil
+}
+
 // convertToSmallestUnit converts a float64 amount to the smallest currency unit.
 // This ensures precision by avoiding floating-point arithmetic issues.
 // Returns an error if the amount is non-finite or would overflow int64.
@@ -174,8 +174,80 @@ func TestMoney_Comparison(t *testing.T) {
 
 	t.Run("GreaterThan different currency", func(t *testing.T) {
 		_, err := usd100.GreaterThan(eur100)
-		require.Error(t, err)
-		assert.EqualError(t, err, "cannot compare different currencies: USD and EUR")
+		require.ErrorIs(t, err, money.ErrMismatchedCurrencies)
+		assert.EqualError(t, err, "mismatched currencies: cannot compare USD and EUR")
+	})
+}
+
+func TestMoney_ComparisonMatrix(t *testing.T) {
+	usd100 := mustNew(t, 100.0, money.USD)
+	usd50 := mustNew(t, 50.0, money.USD)
+	usdNeg50 := mustNew(t, -50.0, money.USD)
+	eur100 := mustNew(t, 100.0, money.EUR)
+	jpy100 := mustNew(t, 100, money.JPY)
+
+	type compare func(a, b *money.Money) (bool, error)
+	ops := map[string]compare{
+		"Equal":       (*money.Money).Equal,
+		"GreaterThan": (*money.Money).GreaterThan,
+		"LessThan":    (*money.Money).LessThan,
+	}
+
+	tests := []struct {
+		name string
+		a, b *money.Money
+		want map[string]bool // nil when the currencies differ
+	}{
+		{"equal amounts", usd100, mustNew(t, 100.0, money.USD),
+			map[string]bool{"Equal": true, "GreaterThan": false, "LessThan": false}},
+		{"greater", usd100, usd50,
+			map[string]bool{"Equal": false, "GreaterThan": true, "LessThan": false}},
+		{"less", usd50, usd100,
+			map[string]bool{"Equal": false, "GreaterThan": false, "LessThan": true}},
+		{"negative against positive", usdNeg50, usd50,
+			map[string]bool{"Equal": false, "GreaterThan": false, "LessThan": true}},
+		{"zero against negative", money.Zero(money.USD), usdNeg50,
+			map[string]bool{"Equal": false, "GreaterThan": true, "LessThan": false}},
+		{"same amount, different currency", usd100, eur100, nil},
+		{"different amount, different currency", usd50, eur100, nil},
+		{"different decimals", eur100, jpy100, nil},
+	}
+	for _, tt := range tests {
+		for name, op := range ops {
+			t.Run(tt.name+"/"+name, func(t *testing.T) {
+				got, err := op(tt.a, tt.b)
+				if tt.want == nil {
+					require.ErrorIs(t, err, money.ErrMismatchedCurrencies)
+					assert.False(t, got)
+					return
+				}
+				require.NoError(t, err)
+				assert.Equal(t, tt.want[name], got)
+			})
+		}
+	}
+
+	t.Run("Equals reports false instead of an error", func(t *testing.T) {
+		assert.False(t, usd100.Equals(eur100))
+		assert.False(t, usd100.Equals(nil))
+	})
+
+	t.Run("sign helpers", func(t *testing.T) {
+		var nilMoney *money.Money
+		for _, tt := range []struct {
+			name                     string
+			m                        *money.Money
+			zero, negative, positive bool
+		}{
+			{"positive", usd50, false, false, true},
+			{"zero", money.Zero(money.EUR), true, false, false},
+			{"negative", usdNeg50, false, true, false},
+			{"nil", nilMoney, true, false, false},
+		} {
+			assert.Equal(t, tt.zero, tt.m.IsZero(), tt.name)
+			assert.Equal(t, tt.negative, tt.m.IsNegative(), tt.name)
+			assert.Equal(t, tt.positive, tt.m.IsPositive(), tt.name)
+		}
 	})
 }
 
@@ -408,6 +480,74 @@ func TestMoney_Divide(t *testing.T) {
 	})
 }
 
+func TestMoney_Split(t *testing.T) {
+	tests := []struct {
+		name     string
+		amount   int64
+		currency money.Code
+		n        int
+		want     []int64
+	}{
+		{"even", 10000, money.USD, 4, []int64{2500, 2500, 2500, 2500}},
+		{"100 / 3", 10000, money.USD, 3, []int64{3334, 3333, 3333}},
+		{"remainder of 2", 1001, money.USD, 3, []int64{334, 334, 333}},
+		{"fewer units than parts", 2, money.USD, 4, []int64{1, 1, 0, 0}},
+		{"negative", -10000, money.USD, 3, []int64{-3334, -3333, -3333}},
+		{"single part", 999, money.USD, 1, []int64{999}},
+		{"JPY", 1000, money.JPY, 3, []int64{334, 333, 333}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mustNewFromSmallestUnit(t, tt.amount, tt.currency)
+			parts, err := m.Split(tt.n)
+			require.NoError(t, err)
+			require.Len(t, parts, tt.n)
+
+			var sum int64
+			got := make([]int64, len(parts))
+			for i := range parts {
+				got[i] = parts[i].Amount()
+				sum += parts[i].Amount()
+				assert.Equal(t, tt.currency, parts[i].CurrencyCode())
+			}
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.amount, sum)
+		})
+	}
+
+	t.Run("invalid n", func(t *testing.T) {
+		m := mustNew(t, 100.0, money.USD)
+		for _, n := range []int{0, -1} {
+			_, err := m.Split(n)
+			require.ErrorIs(t, err, money.ErrInvalidSplit)
+		}
+	})
+}
+
+func TestMoney_AmountString(t *testing.T) {
+	tests := []struct {
+		amount   int64
+		currency money.Currency
+		want     string
+	}{
+		{123450, money.USDCurrency, "1234.50"},
+		{5, money.USDCurrency, "0.05"},
+		{-5, money.USDCurrency, "-0.05"},
+		{0, money.USDCurrency, "0.00"},
+		{1235, money.JPYCurrency, "1235"},
+		{-1235, money.JPYCurrency, "-1235"},
+		{1234567, money.Currency{Code: "KWD", Decimals: 3}, "1234.567"},
+		{math.MinInt64, money.USDCurrency, "-92233720368547758.08"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			m, err := money.NewFromSmallestUnit(tt.amount, tt.currency)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, m.AmountString())
+		})
+	}
+}
+
 func TestMoney_JPY(t *testing.T) {
 	t.Run("JPY whole number valid", func(t *testing.T) {
 		m := mustNew(t, 1000, money.JPY)
@@ -546,3 +686,35 @@ func TestConvertToSmallestUnit(t *testing.T) {
 // TestMoney_Divide is covered by the comprehensive test above
 // TestMoney_Compare is covered by TestMoney_Comparison
 // TestMoney_IsZero is covered by TestMoney_State
+
+func TestValidatePrecision(t *testing.T) {
+	kwd := money.Currency{Code: "KWD", Decimals: 3}
+	tests := []struct {
+		amount   float64
+		currency money.Currency
+		wantErr  bool
+	}{
+		{10, money.USDCurrency, false},
+		{10.5, money.USDCurrency, false},
+		{10.99, money.USDCurrency, false},
+		{0.01, money.USDCurrency, false},
+		{10.999, money.USDCurrency, true},
+		{0.001, money.USDCurrency, true},
+		{100, money.JPYCurrency, false},
+		{100.5, money.JPYCurrency, true},
+		{10.999, kwd, false},
+		{10.9999, kwd, true},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%v %s", tt.amount, tt.currency), func(t *testing.T) {
+			err := money.ValidatePrecision(tt.amount, tt.currency)
+			if !tt.wantErr {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, money.ErrInvalidAmountPrecision)
+			assert.Contains(t, err.Error(),
+				fmt.Sprintf("%s allows at most %d decimal places", tt.currency, tt.currency.Decimals))
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package money
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// RoundingMode is how an amount that falls between two smallest currency
+// units is rounded.
+type RoundingMode string
+
+const (
+	// RoundHalfEven rounds halves to the nearest even unit (banker's
+	// rounding), so rounding does not bias totals up or down.
+	RoundHalfEven RoundingMode = "half_even"
+	// RoundHalfUp rounds halves away from zero.
+	RoundHalfUp RoundingMode = "half_up"
+	// RoundFloor rounds toward negative infinity.
+	RoundFloor RoundingMode = "floor"
+)
+
+// DefaultRoundingMode is the rounding mode used when none is configured.
+const DefaultRoundingMode = RoundHalfEven
+
+// ParseRoundingMode parses a rounding mode such as "half_even". Hyphens are
+// accepted in place of underscores and case is ignored.
+func ParseRoundingMode(s string) (RoundingMode, error) {
+	mode := RoundingMode(strings.ReplaceAll(strings.ToLower(strings.TrimSpace(s)), "-", "_"))
+	switch mode {
+	case RoundHalfEven, RoundHalfUp, RoundFloor:
+		return mode, nil
+	}
+	return "", fmt.Errorf(
+		"invalid rounding mode %q: must be %s, %s or %s",
+		s, RoundHalfEven, RoundHalfUp, RoundFloor,
+	)
+}
+
+// round rounds r to an integer with mode.
+func (mode RoundingMode) round(r *big.Rat) (*big.Int, error) {
+	// Floor division: q = floor(num / denom), rem = r - q in [0, 1)
+	q, m := new(big.Int).DivMod(r.Num(), r.Denom(), new(big.Int))
+	if m.Sign() == 0 {
+		return q, nil
+	}
+	rem := new(big.Rat).SetFrac(m, r.Denom())
+	half := big.NewRat(1, 2)
+	switch mode {
+	case RoundFloor:
+		return q, nil
+	case RoundHalfUp:
+		switch c := rem.Cmp(half); {
+		case c > 0, c == 0 && r.Sign() > 0:
+			q.Add(q, big.NewInt(1))
+		}
+		return q, nil
+	case RoundHalfEven:
+		switch c := rem.Cmp(half); {
+		case c > 0, c == 0 && q.Bit(0) == 1:
+			q.Add(q, big.NewInt(1))
+		}
+		return q, nil
+	}
+	return nil, fmt.Errorf("invalid rounding mode %q", string(mode))
+}
+
+// Convert converts m to currency to at rate, the number of units of to per
+// unit of m's currency, and rounds the result to the smallest unit of to
+// with mode. The rate is taken as the shortest decimal that represents it,
+// so a rate of 0.85 converts at exactly 0.85.
+func (m *Money) Convert(rate float64, to Currency, mode RoundingMode) (*Money, error) {
+	if math.IsNaN(rate) || math.IsInf(rate, 0) || rate <= 0 {
+		return nil, fmt.Errorf("invalid conversion rate %v", rate)
+	}
+	mode, err := ParseRoundingMode(string(mode))
+	if err != nil {
+		return nil, err
+	}
+	if !to.IsValid() {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCurrency, to)
+	}
+	r, ok := new(big.Rat).SetString(strconv.FormatFloat(rate, 'g', -1, 64))
+	if !ok {
+		return nil, fmt.Errorf("invalid conversion rate %v", rate)
+	}
+
+	// amount / 10^from.Decimals * rate * 10^to.Decimals
+	result := new(big.Rat).SetInt64(int64(m.amount))
+	result.Mul(result, r)
+	scale := to.Decimals - m.currency.Decimals
+	pow := new(big.Rat).SetInt(
+		new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(max(scale, -scale))), nil),
+	)
+	if scale >= 0 {
+		result.Mul(result, pow)
+	} else {
+		result.Quo(result, pow)
+	}
+
+	rounded, err := mode.round(result)
+	if err != nil {
+		return nil, err
+	}
+	if !rounded.IsInt64() {
+		return nil, fmt.Errorf("%w: converted amount overflows", ErrAmountExceedsMaxSafeInt)
+	}
+	return &Money{amount: Amount(rounded.Int64()), currency: to}, nil
+}
@@ -0,0 +1,64 @@
+package money_test
+
+import (
+	"testing"
+
+	"github.com/amirasaad/fintech/pkg/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRoundingMode(t *testing.T) {
+	for in, want := range map[string]money.RoundingMode{
+		"half_even": money.RoundHalfEven,
+		"HALF-UP":   money.RoundHalfUp,
+		" floor ":   money.RoundFloor,
+	} {
+		got, err := money.ParseRoundingMode(in)
+		require.NoError(t, err, in)
+		assert.Equal(t, want, got, in)
+	}
+	for _, in := range []string{"", "ceil", "half"} {
+		_, err := money.ParseRoundingMode(in)
+		require.Error(t, err, in)
+	}
+}
+
+func TestMoney_Convert(t *testing.T) {
+	tests := []struct {
+		name     string
+		amount   int64
+		from     money.Code
+		rate     float64
+		to       money.Code
+		mode     money.RoundingMode
+		expected money.Amount
+	}{
+		// 0.85 is not exact in binary; the decimal rate is used.
+		{"decimal rate", 10000, money.USD, 0.85, money.EUR, money.RoundFloor, 8500},
+		{"to fewer decimals", 1005, money.USD, 150.5, money.JPY, money.RoundHalfEven, 1513},
+		{"from fewer decimals", 1000, money.JPY, 0.0067, money.USD, money.RoundHalfEven, 670},
+		{"half even to fewer decimals", 250, money.USD, 1, money.JPY, money.RoundHalfEven, 2},
+		{"half up", 5, money.USD, 0.5, money.EUR, money.RoundHalfUp, 3},
+		{"half even down", 5, money.USD, 0.5, money.EUR, money.RoundHalfEven, 2},
+		{"half even up", 7, money.USD, 0.5, money.EUR, money.RoundHalfEven, 4},
+		{"floor", 7, money.USD, 0.5, money.EUR, money.RoundFloor, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := money.NewFromSmallestUnit(tt.amount, tt.from)
+			require.NoError(t, err)
+			got, err := m.Convert(tt.rate, tt.to.ToCurrency(), tt.mode)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, got.Amount())
+			assert.Equal(t, tt.to, got.CurrencyCode())
+		})
+	}
+
+	m, err := money.NewFromSmallestUnit(100, money.USD)
+	require.NoError(t, err)
+	_, err = m.Convert(0, money.EUR.ToCurrency(), money.RoundHalfEven)
+	require.Error(t, err)
+	_, err = m.Convert(1, money.EUR.ToCurrency(), "ceil")
+	require.Error(t, err)
+}
@@ -175,8 +175,7 @@ func TestMoney_Comparison(t *testing.T) {
 
 	t.Run("LessThan different currency", func(t *testing.T) {
 		_, err := usd100.LessThan(eur100)
-		require.Error(t, err)
-		assert.Contains(t, err.Error(), "invalid currency code")
+		require.ErrorIs(t, err, money.ErrMismatchedCurrencies)
 	})
 }
 
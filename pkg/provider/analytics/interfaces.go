@@ -0,0 +1,25 @@
+// Package analytics defines the sinks that product analytics events are
+// exported to.
+package analytics
+
+import (
+	"context"
+	"time"
+)
+
+// Event is a domain event prepared for analytics: its payload has been
+// stripped of personal data and its user IDs pseudonymized.
+type Event struct {
+	ID         string         `json:"id"`
+	Type       string         `json:"type"`
+	OccurredAt time.Time      `json:"occurred_at"`
+	Data       map[string]any `json:"data"`
+}
+
+// Sink receives batches of analytics events, e.g. an HTTP collector or a
+// file shipped to object storage.
+type Sink interface {
+	// Send delivers a batch of events. A non-nil error means the batch may
+	// not have been delivered and may be sent again.
+	Send(ctx context.Context, events []Event) error
+}
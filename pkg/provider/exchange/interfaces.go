@@ -10,6 +10,12 @@ import (
 var (
 	ErrProviderUnavailable = errors.New("provider unavailable")
 	ErrUnsupportedPair     = errors.New("unsupported currency pair")
+	// ErrRateProviderQuotaExhausted reports that no more calls may be made to
+	// the provider until its quota resets; only cached rates are available.
+	ErrRateProviderQuotaExhausted = errors.New("exchange rate provider quota exhausted")
+	// ErrConversionNotAllowed reports that the currency pair is not on the
+	// configured conversion allowlist, whether or not the provider serves it.
+	ErrConversionNotAllowed = errors.New("currency conversion not allowed")
 )
 
 // RateInfo contains information about an exchange rate
@@ -23,6 +29,9 @@ type RateInfo struct {
 	IsDerived    bool      `json:"is_derived"`
 	BaseCurrency string    `json:"base_currency,omitempty"`
 	OriginalRate float64   `json:"original_rate,omitempty"`
+	// RoundingMode is how a conversion at this rate was rounded to the
+	// smallest unit of ToCurrency; empty if no conversion was rounded.
+	RoundingMode string `json:"rounding_mode,omitempty"`
 }
 
 // RateFetcher defines the interface for fetching exchange rates
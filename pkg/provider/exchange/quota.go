@@ -0,0 +1,200 @@
+package exchange
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// QuotaUsage reports the calls made to a provider in the current quota
+// window.
+type QuotaUsage struct {
+	Provider string `json:"provider"`
+	// Calls is the number of calls made since WindowStart.
+	Calls int `json:"calls"`
+	// Limit is the number of calls the provider allows per window.
+	Limit int `json:"limit"`
+	// SoftLimit is the number of calls after which only cached rates are
+	// served.
+	SoftLimit   int       `json:"soft_limit"`
+	Remaining   int       `json:"remaining"`
+	WindowStart time.Time `json:"window_start"`
+	ResetsAt    time.Time `json:"resets_at"`
+	// CacheOnly is true once the soft limit is reached or the provider has
+	// reported its quota exhausted, until the window resets.
+	CacheOnly bool `json:"cache_only"`
+}
+
+// QuotaProvider wraps an Exchange and counts the calls made to it in a
+// monthly window. Once the calls reach the soft limit, or the provider
+// reports its quota exhausted, further calls fail with
+// ErrRateProviderQuotaExhausted without reaching the provider, so callers
+// fall back to cached rates until the window resets.
+//
+// Calls are counted in memory, per process; instances sharing an API key
+// each count only their own calls.
+type QuotaProvider struct {
+	Exchange
+	limit     int
+	softLimit int
+	resetDay  int
+	now       func() time.Time
+
+	mu          sync.Mutex
+	calls       int
+	exhausted   bool
+	windowStart time.Time
+}
+
+// NewQuotaProvider wraps provider with a quota of limit calls per window.
+// softLimit, a fraction of limit in (0, 1], sets when calls stop; windows
+// start at midnight UTC on resetDay (1-28) of each month.
+func NewQuotaProvider(
+	provider Exchange,
+	limit int,
+	softLimit float64,
+	resetDay int,
+) *QuotaProvider {
+	if resetDay < 1 || resetDay > 28 {
+		resetDay = 1
+	}
+	soft := int(float64(limit) * softLimit)
+	if soft < 1 || soft > limit {
+		soft = limit
+	}
+	return &QuotaProvider{
+		Exchange:  provider,
+		limit:     limit,
+		softLimit: soft,
+		resetDay:  resetDay,
+		now:       time.Now,
+	}
+}
+
+// FetchRate fetches a rate from the wrapped provider unless the quota is
+// exhausted.
+func (q *QuotaProvider) FetchRate(ctx context.Context, from, to string) (*RateInfo, error) {
+	if err := q.acquire(); err != nil {
+		return nil, err
+	}
+	rate, err := q.Exchange.FetchRate(ctx, from, to)
+	q.observe(err)
+	return rate, err
+}
+
+// FetchRates fetches rates from the wrapped provider unless the quota is
+// exhausted.
+func (q *QuotaProvider) FetchRates(ctx context.Context, from string) (map[string]*RateInfo, error) {
+	if err := q.acquire(); err != nil {
+		return nil, err
+	}
+	rates, err := q.Exchange.FetchRates(ctx, from)
+	q.observe(err)
+	return rates, err
+}
+
+// Usage returns the usage of the current window.
+func (q *QuotaProvider) Usage() QuotaUsage {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.rollWindow()
+	return QuotaUsage{
+		Provider:    q.Metadata().Name,
+		Calls:       q.calls,
+		Limit:       q.limit,
+		SoftLimit:   q.softLimit,
+		Remaining:   max(q.limit-q.calls, 0),
+		WindowStart: q.windowStart,
+		ResetsAt:    q.windowStart.AddDate(0, 1, 0),
+		CacheOnly:   q.cacheOnly(),
+	}
+}
+
+// WritePrometheus writes the quota usage in the Prometheus text format.
+func (q *QuotaProvider) WritePrometheus(w io.Writer) {
+	u := q.Usage()
+	cacheOnly := 0
+	if u.CacheOnly {
+		cacheOnly = 1
+	}
+	metrics := []struct {
+		name, help string
+		value      int
+	}{
+		{"fintech_exchange_provider_calls",
+			"Calls made to the exchange rate provider in the current quota window.",
+			u.Calls},
+		{"fintech_exchange_provider_quota_limit",
+			"Calls the exchange rate provider allows per quota window.",
+			u.Limit},
+		{"fintech_exchange_provider_quota_soft_limit",
+			"Calls after which only cached exchange rates are served.",
+			u.SoftLimit},
+		{"fintech_exchange_provider_cache_only",
+			"Whether only cached exchange rates are served (1) or not (0).",
+			cacheOnly},
+	}
+	for _, m := range metrics {
+		fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s gauge\n%s{provider=%q} %d\n",
+			m.name, m.help, m.name, m.name, u.Provider, m.value)
+	}
+}
+
+// acquire counts a call, or fails if the quota allows no more calls.
+func (q *QuotaProvider) acquire() error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.rollWindow()
+	if q.cacheOnly() {
+		return fmt.Errorf(
+			"%w: %d of %d calls made, resets at %s",
+			ErrRateProviderQuotaExhausted,
+			q.calls,
+			q.limit,
+			q.windowStart.AddDate(0, 1, 0).Format(time.RFC3339),
+		)
+	}
+	q.calls++
+	return nil
+}
+
+// observe switches to cache-only mode when the provider reports its quota
+// exhausted, e.g. because other clients share the API key.
+func (q *QuotaProvider) observe(err error) {
+	if !errors.Is(err, ErrRateProviderQuotaExhausted) {
+		return
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.exhausted = true
+}
+
+func (q *QuotaProvider) cacheOnly() bool {
+	return q.exhausted || q.calls >= q.softLimit
+}
+
+// rollWindow starts a new window, resetting the counter, once the current
+// one has ended. q.mu must be held.
+func (q *QuotaProvider) rollWindow() {
+	start := windowStart(q.now().UTC(), q.resetDay)
+	if start.Equal(q.windowStart) {
+		return
+	}
+	q.windowStart = start
+	q.calls = 0
+	q.exhausted = false
+}
+
+// windowStart returns the start of the monthly window containing now.
+func windowStart(now time.Time, resetDay int) time.Time {
+	start := time.Date(now.Year(), now.Month(), resetDay, 0, 0, 0, 0, time.UTC)
+	if now.Before(start) {
+		start = start.AddDate(0, -1, 0)
+	}
+	return start
+}
+
+var _ Exchange = (*QuotaProvider)(nil)
@@ -0,0 +1,66 @@
+package exchange
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingProvider is an Exchange that counts the rates fetched from it.
+type countingProvider struct {
+	Exchange
+	fetched int
+}
+
+func (p *countingProvider) FetchRate(_ context.Context, from, to string) (*RateInfo, error) {
+	p.fetched++
+	return &RateInfo{FromCurrency: from, ToCurrency: to, Rate: 1.1}, nil
+}
+
+func (p *countingProvider) Metadata() ProviderMetadata {
+	return ProviderMetadata{Name: "counting"}
+}
+
+func TestQuotaProvider(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, time.March, 14, 12, 0, 0, 0, time.UTC)
+	provider := &countingProvider{}
+	q := NewQuotaProvider(provider, 10, 0.8, 15)
+	q.now = func() time.Time { return now }
+
+	for range 8 {
+		_, err := q.FetchRate(ctx, "USD", "EUR")
+		require.NoError(t, err)
+	}
+	_, err := q.FetchRate(ctx, "USD", "EUR")
+	require.ErrorIs(t, err, ErrRateProviderQuotaExhausted)
+	assert.Equal(t, 8, provider.fetched)
+
+	usage := q.Usage()
+	assert.Equal(t, QuotaUsage{
+		Provider:    "counting",
+		Calls:       8,
+		Limit:       10,
+		SoftLimit:   8,
+		Remaining:   2,
+		WindowStart: time.Date(2026, time.February, 15, 0, 0, 0, 0, time.UTC),
+		ResetsAt:    time.Date(2026, time.March, 15, 0, 0, 0, 0, time.UTC),
+		CacheOnly:   true,
+	}, usage)
+
+	var b strings.Builder
+	q.WritePrometheus(&b)
+	assert.Contains(t, b.String(), `fintech_exchange_provider_calls{provider="counting"} 8`)
+	assert.Contains(t, b.String(), `fintech_exchange_provider_cache_only{provider="counting"} 1`)
+
+	// The counter resets with the window.
+	now = now.Add(24 * time.Hour)
+	_, err = q.FetchRate(ctx, "USD", "EUR")
+	require.NoError(t, err)
+	assert.Equal(t, 1, q.Usage().Calls)
+	assert.False(t, q.Usage().CacheOnly)
+}
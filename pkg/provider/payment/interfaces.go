@@ -2,6 +2,45 @@ package payment
 
 import (
 	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrRefundsNotSupported is returned when a refund is requested but the
+// payment provider does not implement Refunder.
+var ErrRefundsNotSupported = errors.New("payment provider does not support refunds")
+
+var (
+	// ErrCheckoutCancelNotSupported is returned when a deposit is canceled
+	// but the payment provider does not implement CheckoutCanceler.
+	ErrCheckoutCancelNotSupported = errors.New(
+		"payment provider does not support canceling checkouts",
+	)
+	// ErrCheckoutNotCancellable is returned when the checkout of a deposit
+	// has already been paid.
+	ErrCheckoutNotCancellable = errors.New("checkout cannot be canceled")
+)
+
+var (
+	// ErrCaptureNotSupported is returned when a payment capture is requested
+	// but the payment provider does not implement Capturer.
+	ErrCaptureNotSupported = errors.New(
+		"payment provider does not support capturing payments",
+	)
+	// ErrPaymentNotCapturable is returned when a payment is not awaiting
+	// capture, e.g. it was captured automatically or its authorization expired.
+	ErrPaymentNotCapturable = errors.New("payment cannot be captured")
+)
+
+var (
+	// ErrPayoutNotFound is returned when a payout provider does not know the
+	// requested payout.
+	ErrPayoutNotFound = errors.New("payout not found")
+	// ErrPayoutNotCancellable is returned when a payout has already been paid
+	// out, failed or been cancelled.
+	ErrPayoutNotCancellable = errors.New("payout cannot be cancelled")
 )
 
 // Payment is a interface for payment provider
@@ -16,10 +55,57 @@ type Payment interface {
 		payload []byte,
 		signature string,
 	) (*PaymentEvent, error)
+}
 
-	// InitiatePayout initiates a payout to a connected account
+// Payout is implemented by providers that pay withdrawals out to users. It
+// keeps withdrawals independent of any one provider, so regions without
+// Stripe Connect can use another.
+type Payout interface {
+	// InitiatePayout starts paying out a withdrawal.
 	InitiatePayout(
 		ctx context.Context,
 		params *InitiatePayoutParams,
 	) (*InitiatePayoutResponse, error)
+
+	// GetPayoutStatus returns the current status of the payout with the ID
+	// returned by InitiatePayout, or ErrPayoutNotFound.
+	GetPayoutStatus(ctx context.Context, payoutID string) (PaymentStatus, error)
+
+	// CancelPayout cancels a payout, or returns ErrPayoutNotCancellable if it
+	// can no longer be cancelled.
+	CancelPayout(ctx context.Context, payoutID string) error
+}
+
+// PayoutLister is implemented by providers that can list the payouts they
+// executed, e.g. for reconciliation against internal transactions.
+type PayoutLister interface {
+	// ListPayouts returns all payouts created in [from, to), following the
+	// provider's pagination until exhausted.
+	ListPayouts(ctx context.Context, from, to time.Time) ([]*PayoutRecord, error)
+}
+
+// Refunder is implemented by providers that can refund captured payments.
+type Refunder interface {
+	// RefundPayment refunds part or all of a captured payment. The outcome
+	// is reported asynchronously through HandleWebhook.
+	RefundPayment(ctx context.Context, params *RefundPaymentParams) (*RefundPaymentResponse, error)
+}
+
+// Capturer is implemented by providers that can capture payments authorized
+// with CaptureManual.
+type Capturer interface {
+	// CapturePayment captures amount, in the smallest currency unit, of an
+	// authorized payment; the rest of the authorization is released. It
+	// returns ErrPaymentNotCapturable if the payment is not awaiting capture.
+	// The outcome is reported asynchronously through HandleWebhook.
+	CapturePayment(ctx context.Context, paymentID string, amount int64) error
+}
+
+// CheckoutCanceler is implemented by providers that can cancel the checkout
+// of a deposit before it is paid.
+type CheckoutCanceler interface {
+	// CancelCheckout expires the checkout of the deposit transaction, so it
+	// can no longer be paid, or returns ErrCheckoutNotCancellable if it has
+	// already been paid. A deposit without an open checkout is not an error.
+	CancelCheckout(ctx context.Context, transactionID uuid.UUID) error
 }
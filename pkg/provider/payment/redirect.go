@@ -0,0 +1,32 @@
+package payment
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// ErrRedirectURLNotAllowed is returned when a per-request success or cancel URL
+// is malformed or points to a host outside the configured allowlist.
+var ErrRedirectURLNotAllowed = errors.New("redirect URL not allowed")
+
+// ValidateRedirectURL checks that raw is an absolute http(s) URL whose host is
+// in allowedHosts. Hosts match case-insensitively and exactly, including any
+// port. An empty allowlist rejects every URL, so per-request redirects stay
+// disabled until hosts are configured.
+func ValidateRedirectURL(raw string, allowedHosts []string) error {
+	u, err := url.Parse(raw)
+	if err != nil || !u.IsAbs() || u.Host == "" {
+		return fmt.Errorf("%w: %q is not an absolute URL", ErrRedirectURLNotAllowed, raw)
+	}
+	if u.Scheme != "https" && u.Scheme != "http" {
+		return fmt.Errorf("%w: unsupported scheme %q", ErrRedirectURLNotAllowed, u.Scheme)
+	}
+	for _, h := range allowedHosts {
+		if strings.EqualFold(strings.TrimSpace(h), u.Host) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: host %q is not allowed", ErrRedirectURLNotAllowed, u.Host)
+}
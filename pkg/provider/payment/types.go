@@ -1,6 +1,10 @@
 package payment
 
 import (
+	"errors"
+	"fmt"
+	"time"
+
 	"github.com/google/uuid"
 )
 
@@ -14,8 +18,37 @@ const (
 	PaymentCompleted PaymentStatus = "completed"
 	// PaymentFailed indicates the payment has failed.
 	PaymentFailed PaymentStatus = "failed"
+	// PaymentCancelled indicates the payment was cancelled before completing.
+	PaymentCancelled PaymentStatus = "cancelled"
+)
+
+// CaptureMethod is when the funds of an authorized payment are captured.
+type CaptureMethod string
+
+const (
+	// CaptureAutomatic captures the funds as soon as the payment is
+	// authorized. It is the default.
+	CaptureAutomatic CaptureMethod = "automatic"
+	// CaptureManual only authorizes the payment and holds the funds until
+	// they are captured with Capturer.CapturePayment. Authorizations not
+	// captured within the provider's window, seven days for Stripe cards,
+	// expire and the payment fails.
+	CaptureManual CaptureMethod = "manual"
 )
 
+// ErrInvalidCaptureMethod is returned for a capture method other than
+// CaptureAutomatic or CaptureManual.
+var ErrInvalidCaptureMethod = errors.New("invalid capture method")
+
+// Validate checks that m is empty or a known capture method.
+func (m CaptureMethod) Validate() error {
+	switch m {
+	case "", CaptureAutomatic, CaptureManual:
+		return nil
+	}
+	return fmt.Errorf("%w: %q", ErrInvalidCaptureMethod, m)
+}
+
 // PaymentEventType represents the type of payment event.
 type PaymentEventType string
 
@@ -49,6 +82,16 @@ type InitiatePaymentParams struct {
 	TransactionID uuid.UUID
 	Amount        int64
 	Currency      string
+	// SuccessURL and CancelURL override the provider's configured redirect
+	// URLs for this payment. They must pass ValidateRedirectURL.
+	SuccessURL string
+	CancelURL  string
+	// StatementDescriptor overrides the provider's default text on the
+	// user's card statement for this payment.
+	StatementDescriptor string
+	// CaptureMethod is when the funds are captured; empty is
+	// CaptureAutomatic.
+	CaptureMethod CaptureMethod
 }
 
 type InitiatePaymentResponse struct {
@@ -85,6 +128,7 @@ type PayoutDestination struct {
 	Type           PayoutDestinationType
 	BankAccount    *BankAccountDetails `json:"bank_account,omitempty"`
 	ExternalWallet *string             `json:"external_wallet,omitempty"`
+	Network        string              `json:"network,omitempty"` // crypto network of ExternalWallet
 }
 
 // BankAccountDetails contains bank account information for payouts
@@ -117,4 +161,39 @@ type InitiatePayoutResponse struct {
 	FeeAmount            int64
 	FeeCurrency          string
 	EstimatedArrivalDate int64 // Unix timestamp
+	// ApplicationFeeAmount is the platform fee withheld from the requested
+	// amount, in the smallest unit of Currency. Amount is net of it.
+	ApplicationFeeAmount int64
+}
+
+// PayoutRecord is a payout as reported by the payment provider.
+type PayoutRecord struct {
+	ID string
+	// TransactionID is the internal transaction ID from the payout metadata,
+	// or uuid.Nil if the payout carries none.
+	TransactionID  uuid.UUID
+	Amount         int64 // Amount in the smallest currency unit
+	AmountReversed int64 // Amount reversed so far, in the smallest currency unit
+	Currency       string
+	Reversed       bool // Fully reversed
+	CreatedAt      time.Time
+}
+
+// RefundPaymentParams holds the parameters for refunding a payment.
+type RefundPaymentParams struct {
+	UserID    uuid.UUID
+	AccountID uuid.UUID
+	// TransactionID is the internal refund transaction, echoed back by the
+	// provider when the refund completes.
+	TransactionID uuid.UUID
+	// PaymentID is the provider ID of the payment being refunded.
+	PaymentID string
+	Amount    int64 // Amount to refund, in the smallest currency unit
+	Currency  string
+}
+
+// RefundPaymentResponse represents the response from refunding a payment.
+type RefundPaymentResponse struct {
+	RefundID string
+	Status   PaymentStatus
 }
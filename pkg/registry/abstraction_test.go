@@ -2,6 +2,10 @@ package registry
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync"
 	"testing"
 	"time"
 )
@@ -507,6 +511,72 @@ func TestFilePersistence(t *testing.T) {
 	}
 }
 
+func TestEnhancedRegistry_Close(t *testing.T) {
+	ctx := context.Background()
+	persistence := NewFilePersistence(filepath.Join(t.TempDir(), "registry.json"))
+	registry := NewEnhanced(Config{Name: "test"}).WithPersistence(persistence)
+
+	for _, id := range []string{"keep", "drop"} {
+		if err := registry.Register(ctx, NewBaseEntity(id, id)); err != nil {
+			t.Fatalf("Failed to register %s: %v", id, err)
+		}
+	}
+	// Unregistering does not save, so only the final save drops the entity.
+	if err := registry.Unregister(ctx, "drop"); err != nil {
+		t.Fatalf("Failed to unregister: %v", err)
+	}
+
+	// Registrations racing with Close are either saved or rejected.
+	results := make([]error, 20)
+	var wg sync.WaitGroup
+	for i := range results {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			id := fmt.Sprintf("racing-%d", i)
+			results[i] = registry.Register(ctx, NewBaseEntity(id, id))
+		}()
+	}
+	if err := registry.Close(ctx); err != nil {
+		t.Fatalf("Failed to close registry: %v", err)
+	}
+	wg.Wait()
+
+	loaded, err := persistence.Load(ctx)
+	if err != nil {
+		t.Fatalf("Failed to load entities: %v", err)
+	}
+	saved := map[string]bool{}
+	for _, entity := range loaded {
+		saved[entity.ID()] = true
+	}
+	if !saved["keep"] || saved["drop"] {
+		t.Errorf("Final save should contain keep but not drop, got %v", saved)
+	}
+	for i, err := range results {
+		id := fmt.Sprintf("racing-%d", i)
+		switch {
+		case err == nil && !saved[id]:
+			t.Errorf("Registered %s is missing from the final save", id)
+		case err != nil && !errors.Is(err, ErrClosed):
+			t.Errorf("Registering %s failed with %v, want ErrClosed", id, err)
+		}
+	}
+
+	if err := registry.Register(ctx, NewBaseEntity("late", "late")); !errors.Is(err, ErrClosed) {
+		t.Errorf("Register after Close returned %v, want ErrClosed", err)
+	}
+	if err := registry.Deactivate(ctx, "keep"); !errors.Is(err, ErrClosed) {
+		t.Errorf("Deactivate after Close returned %v, want ErrClosed", err)
+	}
+	if _, err := registry.Get(ctx, "keep"); err != nil {
+		t.Errorf("Get after Close failed: %v", err)
+	}
+	if err := registry.Close(ctx); err != nil {
+		t.Errorf("Closing twice failed: %v", err)
+	}
+}
+
 // testObserver implements RegistryObserver for testing
 type testObserver struct {
 	events *[]string
@@ -22,6 +22,7 @@ type Enhanced struct {
 	metrics     Metrics
 	health      Health
 	eventBus    EventBus
+	closed      bool
 }
 
 // NewEnhanced creates a new enhanced registry
@@ -103,6 +104,9 @@ func (r *Enhanced) Register(ctx context.Context, entity Entity) error {
 
 	r.mu.Lock()
 	defer r.mu.Unlock()
+	if r.closed {
+		return ErrClosed
+	}
 
 	// Check if this is an update
 	_, exists := r.entities[entity.ID()]
@@ -224,6 +228,9 @@ func (r *Enhanced) Unregister(ctx context.Context, id string) error {
 
 	r.mu.Lock()
 	defer r.mu.Unlock()
+	if r.closed {
+		return ErrClosed
+	}
 
 	if _, exists := r.entities[id]; !exists {
 		if r.metrics != nil {
@@ -425,6 +432,9 @@ func (r *Enhanced) RemoveMetadata(ctx context.Context, id, key string) error {
 func (r *Enhanced) Activate(ctx context.Context, id string) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
+	if r.closed {
+		return ErrClosed
+	}
 
 	entity, exists := r.entities[id]
 	if !exists {
@@ -478,6 +488,9 @@ func (r *Enhanced) Activate(ctx context.Context, id string) error {
 func (r *Enhanced) Deactivate(ctx context.Context, id string) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
+	if r.closed {
+		return ErrClosed
+	}
 
 	entity, exists := r.entities[id]
 	if !exists {
@@ -529,6 +542,29 @@ func (r *Enhanced) Deactivate(ctx context.Context, id string) error {
 
 // ...
 
+// Close stops the registry accepting changes and, if it has persistence,
+// saves every entity one final time. Saving again covers changes whose
+// earlier save failed and unregistrations, which are not saved as they
+// happen. Changes that complete before Close are in the final save; later
+// ones fail with ErrClosed, while reads keep working. Closing a closed
+// registry does nothing.
+func (r *Enhanced) Close(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return nil
+	}
+	r.closed = true
+
+	if r.persistence == nil {
+		return nil
+	}
+	if err := r.persistence.Save(ctx, r.getAllEntitiesLocked()); err != nil {
+		return fmt.Errorf("failed to persist registry: %w", err)
+	}
+	return nil
+}
+
 // Search performs a simple search on entity names
 func (r *Enhanced) Search(ctx context.Context, query string) ([]Entity, error) {
 	r.mu.RLock()
@@ -149,6 +149,12 @@ type Provider interface {
 	SearchByMetadata(ctx context.Context, metadata map[string]string) ([]Entity, error)
 }
 
+// Closer is implemented by providers that must be closed on shutdown, such
+// as Enhanced, which saves its entities one final time.
+type Closer interface {
+	Close(ctx context.Context) error
+}
+
 // Observer defines the interface for registry event observers
 type Observer interface {
 	OnEntityRegistered(ctx context.Context, entity Entity)
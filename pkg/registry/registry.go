@@ -9,6 +9,9 @@ import (
 
 var ErrNotFound = errors.New("entity not found")
 
+// ErrClosed is returned when changing a registry that has been closed.
+var ErrClosed = errors.New("registry is closed")
+
 // Registry is a thread-safe registry for managing entities that implement the Entity interface
 type Registry struct {
 	entities map[string]Entity
@@ -2,6 +2,7 @@ package account
 
 import (
 	"context"
+	"time"
 
 	"github.com/amirasaad/fintech/pkg/dto"
 	"github.com/google/uuid"
@@ -19,6 +20,55 @@ type Repository interface {
 	// Get retrieves an account by its ID as a read-optimized DTO.
 	Get(ctx context.Context, id uuid.UUID) (*dto.AccountRead, error)
 
+	// GetForUpdate retrieves an account like Get, locking its row until the
+	// unit of work ends so concurrent balance updates of the account run one
+	// at a time. Accounts locked together must be locked in ID order.
+	GetForUpdate(ctx context.Context, id uuid.UUID) (*dto.AccountRead, error)
+
+	// GetByReference retrieves an account by its canonical human-friendly
+	// reference.
+	GetByReference(ctx context.Context, reference string) (*dto.AccountRead, error)
+
 	// ListByUser lists all accounts for a given user as read-optimized DTOs.
 	ListByUser(ctx context.Context, userID uuid.UUID) ([]*dto.AccountRead, error)
+
+	// ListByCurrency lists one page of the accounts in the given currency across
+	// all users, oldest first. Closed accounts are included only if requested.
+	ListByCurrency(
+		ctx context.Context,
+		currency string,
+		includeClosed bool,
+		page, pageSize int,
+	) ([]*dto.AccountRead, error)
+
+	// ListInterestDue lists up to limit open accounts of the given type that
+	// have not accrued interest for day or later, oldest first.
+	ListInterestDue(
+		ctx context.Context,
+		accountType string,
+		day time.Time,
+		limit int,
+	) ([]*dto.AccountRead, error)
+
+	// TotalsByCurrency counts the accounts in the given currency and sums
+	// their balances, with the same closed-account rule as ListByCurrency.
+	TotalsByCurrency(
+		ctx context.Context,
+		currency string,
+		includeClosed bool,
+	) (*dto.AccountTotals, error)
+
+	// GetLedgerCheckpoint returns the account's balance and verified ledger
+	// balance, locking the account row until the unit of work ends so
+	// concurrent verifications of the account run one at a time.
+	GetLedgerCheckpoint(ctx context.Context, id uuid.UUID) (*dto.LedgerCheckpoint, error)
+
+	// SaveLedgerCheckpoint records the account's verified ledger balance and
+	// watermark, without changing its updated_at.
+	SaveLedgerCheckpoint(
+		ctx context.Context,
+		id uuid.UUID,
+		ledgerBalance int64,
+		verifiedAt time.Time,
+	) error
 }
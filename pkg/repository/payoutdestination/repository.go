@@ -0,0 +1,23 @@
+package payoutdestination
+
+import (
+	"context"
+
+	"github.com/amirasaad/fintech/pkg/dto"
+	"github.com/google/uuid"
+)
+
+// Repository defines the interface for saved payout destination data access.
+type Repository interface {
+	// Create inserts a new payout destination.
+	Create(ctx context.Context, create dto.PayoutDestinationCreate) error
+
+	// Get retrieves a payout destination by its ID.
+	Get(ctx context.Context, id uuid.UUID) (*dto.PayoutDestinationRead, error)
+
+	// ListByUser lists a user's payout destinations, oldest first.
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]*dto.PayoutDestinationRead, error)
+
+	// Delete removes a payout destination.
+	Delete(ctx context.Context, id uuid.UUID) error
+}
@@ -0,0 +1,33 @@
+package pendingtransfer
+
+import (
+	"context"
+
+	"github.com/amirasaad/fintech/pkg/dto"
+	"github.com/google/uuid"
+)
+
+// Repository defines the interface for data access of transfers awaiting
+// approval.
+type Repository interface {
+	// Create inserts a new pending transfer.
+	Create(ctx context.Context, create dto.PendingTransferCreate) error
+
+	// Get retrieves a pending transfer by its ID.
+	Get(ctx context.Context, id uuid.UUID) (*dto.PendingTransferRead, error)
+
+	// List lists pending transfers, oldest first, optionally filtered by
+	// status. An empty status returns all of them.
+	List(ctx context.Context, status string) ([]*dto.PendingTransferRead, error)
+
+	// Decide moves a pending transfer from dto.PendingTransferPending to the
+	// given status, recording who decided and why. It reports false if the
+	// transfer was no longer pending.
+	Decide(
+		ctx context.Context,
+		id uuid.UUID,
+		status string,
+		decidedBy uuid.UUID,
+		reason string,
+	) (bool, error)
+}
@@ -0,0 +1,51 @@
+package pendingwithdrawal
+
+import (
+	"context"
+
+	"github.com/amirasaad/fintech/pkg/dto"
+	"github.com/google/uuid"
+)
+
+// Repository defines the interface for data access of withdrawals held for
+// approval.
+type Repository interface {
+	// Create inserts a new pending withdrawal.
+	Create(ctx context.Context, create dto.PendingWithdrawalCreate) error
+
+	// Get retrieves a pending withdrawal, with its approvals, by its ID.
+	Get(ctx context.Context, id uuid.UUID) (*dto.PendingWithdrawalRead, error)
+
+	// GetByTransactionID retrieves the pending withdrawal, with its
+	// approvals, whose amount is held by the given hold transaction.
+	GetByTransactionID(
+		ctx context.Context,
+		transactionID uuid.UUID,
+	) (*dto.PendingWithdrawalRead, error)
+
+	// List lists pending withdrawals, oldest first, optionally filtered by
+	// status. An empty status returns all of them.
+	List(ctx context.Context, status string) ([]*dto.PendingWithdrawalRead, error)
+
+	// Approve records approverID's approval of a withdrawal that is still
+	// dto.PendingWithdrawalPending and returns all its approvers so far,
+	// counting an approver once. The withdrawal stays locked until the unit
+	// of work ends, so concurrent approvals are counted one at a time. It
+	// reports false if the withdrawal was no longer pending.
+	Approve(
+		ctx context.Context,
+		id uuid.UUID,
+		approverID uuid.UUID,
+	) ([]uuid.UUID, bool, error)
+
+	// Decide moves a pending withdrawal from dto.PendingWithdrawalPending to
+	// the given status, recording who decided and why. It reports false if
+	// the withdrawal was no longer pending.
+	Decide(
+		ctx context.Context,
+		id uuid.UUID,
+		status string,
+		decidedBy uuid.UUID,
+		reason string,
+	) (bool, error)
+}
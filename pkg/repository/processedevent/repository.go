@@ -0,0 +1,12 @@
+package processedevent
+
+import "context"
+
+// Repository records which events each handler has already processed, so
+// handlers can skip side effects for redelivered events.
+type Repository interface {
+	// MarkProcessed records that handler processed the event with the given
+	// key. It reports false if the pair was already recorded. Inside a unit
+	// of work the record is rolled back with the rest of the transaction.
+	MarkProcessed(ctx context.Context, handler, eventKey string) (bool, error)
+}
@@ -0,0 +1,30 @@
+package repository
+
+import "context"
+
+type replicaReadsKey struct{}
+
+// WithReplicaReads returns a copy of ctx whose reads may be served by a read
+// replica when they run outside Do. Replicas can lag behind the primary, so
+// use it only for reads shown to the caller as they are, such as balances and
+// listings, never for reads that guard a write or read back a row just
+// written.
+func WithReplicaReads(ctx context.Context) context.Context {
+	return context.WithValue(ctx, replicaReadsKey{}, true)
+}
+
+// WithoutReplicaReads returns a copy of ctx whose reads use the primary, even
+// if ctx was returned by WithReplicaReads.
+func WithoutReplicaReads(ctx context.Context) context.Context {
+	return context.WithValue(ctx, replicaReadsKey{}, false)
+}
+
+// ReplicaReadsAllowed reports whether reads made with ctx may be served by a
+// read replica.
+func ReplicaReadsAllowed(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+	allowed, _ := ctx.Value(replicaReadsKey{}).(bool)
+	return allowed
+}
@@ -0,0 +1,44 @@
+package scheduledtransfer
+
+import (
+	"context"
+	"time"
+
+	"github.com/amirasaad/fintech/pkg/dto"
+	"github.com/google/uuid"
+)
+
+// Repository defines the interface for scheduled transfer data access.
+type Repository interface {
+	// Create inserts a new pending scheduled transfer.
+	Create(ctx context.Context, create dto.ScheduledTransferCreate) error
+
+	// Get retrieves a scheduled transfer by its ID.
+	Get(ctx context.Context, id uuid.UUID) (*dto.ScheduledTransferRead, error)
+
+	// ListByUser lists a user's scheduled transfers, optionally filtered by status.
+	// An empty status returns all scheduled transfers.
+	ListByUser(
+		ctx context.Context,
+		userID uuid.UUID,
+		status string,
+	) ([]*dto.ScheduledTransferRead, error)
+
+	// ListDue lists up to limit pending transfers due at or before the given time.
+	// When called inside a unit of work the returned rows are locked, so
+	// concurrent schedulers never pick up the same transfer.
+	ListDue(
+		ctx context.Context,
+		before time.Time,
+		limit int,
+	) ([]*dto.ScheduledTransferRead, error)
+
+	// UpdateStatus moves a scheduled transfer from one status to another.
+	// It reports false if the transfer was not in the expected status.
+	UpdateStatus(
+		ctx context.Context,
+		id uuid.UUID,
+		from, to string,
+		reason string,
+	) (bool, error)
+}
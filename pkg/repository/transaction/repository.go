@@ -2,6 +2,7 @@ package transaction
 
 import (
 	"context"
+	"time"
 
 	"github.com/amirasaad/fintech/pkg/dto"
 	"github.com/google/uuid"
@@ -33,4 +34,51 @@ type Repository interface {
 
 	// ListByAccount lists all transactions for a given account as read-optimized DTOs.
 	ListByAccount(ctx context.Context, accountID uuid.UUID) ([]*dto.TransactionRead, error)
+
+	// ListByMoneySource lists transactions with one of the given money sources
+	// created in the half-open interval [from, to), e.g. withdrawals for
+	// reconciliation.
+	ListByMoneySource(
+		ctx context.Context,
+		moneySources []string,
+		from, to time.Time,
+	) ([]*dto.TransactionRead, error)
+
+	// SumByUserMoneySource sums the absolute amounts of the user's transactions
+	// with one of the given money sources that were neither failed nor
+	// canceled, created at or after since. Sums are per currency code, in the
+	// smallest unit.
+	SumByUserMoneySource(
+		ctx context.Context,
+		userID uuid.UUID,
+		moneySources []string,
+		since time.Time,
+	) (map[string]int64, error)
+
+	// ListPayouts lists one page of the user's withdrawal transactions, those
+	// with one of the given money sources, newest first. A non-empty statuses
+	// keeps only transactions in one of them. Pages start at 1.
+	ListPayouts(
+		ctx context.Context,
+		userID uuid.UUID,
+		moneySources []string,
+		statuses []string,
+		page, pageSize int,
+	) ([]*dto.TransactionRead, error)
+
+	// ListRefunds lists the refund transactions recorded against the
+	// transaction with the given ID, in any status.
+	ListRefunds(ctx context.Context, transactionID uuid.UUID) ([]*dto.TransactionRead, error)
+
+	// ListLedgerEntries lists the ledger entries of the account's transactions
+	// last updated at or after since, for incremental ledger verification.
+	ListLedgerEntries(
+		ctx context.Context,
+		accountID uuid.UUID,
+		since time.Time,
+	) ([]*dto.LedgerEntry, error)
+
+	// SetLedgerCounted records what a transaction contributes to its account's
+	// verified ledger balance, without changing its updated_at.
+	SetLedgerCounted(ctx context.Context, id uuid.UUID, counted int64) error
 }
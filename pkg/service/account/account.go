@@ -16,19 +16,23 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"time"
 
 	"github.com/amirasaad/fintech/pkg/eventbus"
 
 	"github.com/amirasaad/fintech/pkg/commands"
+	"github.com/amirasaad/fintech/pkg/config"
 	"github.com/amirasaad/fintech/pkg/domain/events"
 
 	"github.com/amirasaad/fintech/pkg/domain"
 	"github.com/amirasaad/fintech/pkg/domain/account"
 	"github.com/amirasaad/fintech/pkg/dto"
 	"github.com/amirasaad/fintech/pkg/money"
+	"github.com/amirasaad/fintech/pkg/provider/payment"
 	"github.com/amirasaad/fintech/pkg/repository"
 	repoaccount "github.com/amirasaad/fintech/pkg/repository/account"
 	stripeconnect "github.com/amirasaad/fintech/pkg/service/stripeconnect"
+	"github.com/amirasaad/fintech/pkg/validation"
 	"github.com/google/uuid"
 )
 
@@ -39,6 +43,22 @@ type Service struct {
 	uow              repository.UnitOfWork
 	logger           *slog.Logger
 	stripeConnectSvc stripeconnect.Service
+	kyc              *config.Kyc
+	converter        CurrencyConverter
+	redirectHosts    []string
+	currencies       CurrencyRegistry
+	refunder         payment.Refunder
+	checkoutCanceler payment.CheckoutCanceler
+	capturer         payment.Capturer
+	referencePrefix  string
+	typeRules        *config.Account
+	interest         *config.Interest
+	withdrawApproval *config.Withdraw
+	receiptKey       []byte
+	receiptTTL       time.Duration
+	txCache          TransactionCache
+	locker           AccountLocker
+	lockTimeout      time.Duration
 }
 
 // New creates a new Service with the provided dependencies.
@@ -47,13 +67,26 @@ func New(
 	uow repository.UnitOfWork,
 	logger *slog.Logger,
 	stripeConnectSvc stripeconnect.Service,
+	opts ...Option,
 ) *Service {
-	return &Service{
+	s := &Service{
 		bus:              bus,
 		uow:              uow,
 		logger:           logger,
 		stripeConnectSvc: stripeConnectSvc,
+		locker:           NewLocalAccountLocker(),
+		lockTimeout:      DefaultAccountLockTimeout,
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// WithRedirectAllowedHosts sets the hosts a deposit may name in its
+// per-request checkout success and cancel URLs.
+func WithRedirectAllowedHosts(hosts []string) Option {
+	return func(s *Service) { s.redirectHosts = hosts }
 }
 
 func (s *Service) CreateAccount(
@@ -70,14 +103,25 @@ func (s *Service) CreateAccount(
 		}
 		acctRepo := repoAny.(repoaccount.Repository)
 
-		// Check if user already has an account with the same currency
+		accountType, err := account.ParseType(create.Type)
+		if err != nil {
+			return err
+		}
+		// Check if user already has an account of the type with the same currency
 		existingAccounts, err := acctRepo.ListByUser(ctx, create.UserID)
 		if err != nil {
 			return fmt.Errorf("failed to check existing accounts: %w", err)
 		}
 		for _, acc := range existingAccounts {
-			if acc.Currency == create.Currency {
-				return fmt.Errorf("user already has an account with currency %s", create.Currency)
+			existingType, err := account.ParseType(acc.Type)
+			if err != nil {
+				existingType = account.TypeChecking
+			}
+			if acc.Currency == create.Currency && existingType == accountType {
+				return fmt.Errorf(
+					"%w: user already has a %s account with currency %s",
+					domain.ErrAlreadyExists, accountType, create.Currency,
+				)
 			}
 		}
 
@@ -86,17 +130,26 @@ func (s *Service) CreateAccount(
 		if curr == "" {
 			curr = money.DefaultCode
 		}
+		if err := s.ensureCurrencyActive(ctx, curr.String()); err != nil {
+			return err
+		}
 		domainAcc, err := account.New().WithUserID(create.UserID).WithCurrency(curr).Build()
 		if err != nil {
 			return err
 		}
+		reference, err := account.NewReference(s.referencePrefix)
+		if err != nil {
+			return err
+		}
 
 		// Map to DTO for persistence
 		createDTO := dto.AccountCreate{
-			ID:       domainAcc.ID,
-			UserID:   domainAcc.UserID,
-			Balance:  int64(domainAcc.Balance.Amount()), // or 0 if always zero at creation
-			Currency: curr.String(),
+			ID:        domainAcc.ID,
+			Reference: reference,
+			UserID:    domainAcc.UserID,
+			Balance:   int64(domainAcc.Balance.Amount()), // or 0 if always zero at creation
+			Currency:  curr.String(),
+			Type:      accountType.String(),
 		}
 		if err = acctRepo.Create(ctx, createDTO); err != nil {
 			return fmt.Errorf("failed to create account: %w", err)
@@ -122,16 +175,56 @@ func (s *Service) Deposit(
 	ctx context.Context,
 	cmd commands.Deposit,
 ) error {
+	unlock, err := s.lockAccounts(ctx, cmd.AccountID)
+	if err != nil {
+		return err
+	}
+	defer unlock()
 	// Always use the source currency for the initial deposit event
 	amount, err := money.New(cmd.Amount, money.Code(cmd.Currency))
 	if err != nil {
 		return err
 	}
+	for _, u := range []string{cmd.SuccessURL, cmd.CancelURL} {
+		if u == "" {
+			continue
+		}
+		if err := payment.ValidateRedirectURL(u, s.redirectHosts); err != nil {
+			return err
+		}
+	}
+	description, err := account.NormalizeDescription(cmd.Description)
+	if err != nil {
+		return err
+	}
+	if cmd.StatementDescriptor != "" {
+		if err := validation.ValidateStatementDescriptor(cmd.StatementDescriptor); err != nil {
+			return err
+		}
+	}
+	if err := payment.CaptureMethod(cmd.CaptureMethod).Validate(); err != nil {
+		return err
+	}
+	if err := s.ensureCurrencyActive(ctx, cmd.Currency); err != nil {
+		return err
+	}
+	if err := s.ensureAccountAcceptsFunds(ctx, cmd.AccountID); err != nil {
+		return err
+	}
+	if err := s.enforceKycLimits(
+		ctx, cmd.UserID, cmd.AccountID, moneySourceDeposit, amount,
+	); err != nil {
+		return err
+	}
 	dr := events.NewDepositRequested(
 		cmd.UserID,
 		cmd.AccountID,
 		uuid.New(),
 		events.WithDepositAmount(amount),
+		events.WithDepositRedirectURLs(cmd.SuccessURL, cmd.CancelURL),
+		events.WithDepositDescription(description),
+		events.WithDepositStatementDescriptor(cmd.StatementDescriptor),
+		events.WithDepositCaptureMethod(cmd.CaptureMethod),
 	)
 	return s.bus.Emit(ctx, dr)
 }
@@ -139,42 +232,108 @@ func (s *Service) Deposit(
 // Withdraw removes funds from the specified account
 // to an external target and creates a transaction record.
 // It returns an error if the user has not completed Stripe Connect onboarding.
+// A withdrawal above the approval threshold is held for approval instead
+// (see RequestWithdrawalApproval).
 func (s *Service) Withdraw(
 	ctx context.Context,
 	cmd commands.Withdraw,
 ) error {
+	unlock, err := s.lockAccounts(ctx, cmd.AccountID)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	amount, description, err := s.checkWithdrawal(ctx, cmd)
+	if err != nil {
+		return err
+	}
+	held, err := s.requiresWithdrawalApproval(ctx, amount)
+	if err != nil {
+		return err
+	}
+	if held {
+		_, err := s.holdWithdrawal(ctx, cmd, amount, description)
+		return err
+	}
+	return s.emitWithdrawRequested(
+		ctx, cmd.UserID, cmd.AccountID, amount, description, cmd.ExternalTarget,
+	)
+}
+
+// checkWithdrawal checks that the user may make the withdrawal and returns
+// its amount and normalized description.
+func (s *Service) checkWithdrawal(
+	ctx context.Context,
+	cmd commands.Withdraw,
+) (*money.Money, string, error) {
 	// Check if user has completed Stripe Connect onboarding
 	onboarded, err := s.stripeConnectSvc.IsOnboardingComplete(ctx, cmd.UserID)
 	if err != nil && !errors.Is(err, domain.ErrNotFound) {
-		return fmt.Errorf("failed to check Stripe Connect status: %w", err)
+		return nil, "", fmt.Errorf("failed to check Stripe Connect status: %w", err)
 	}
 
 	if !onboarded {
-		return domain.ErrStripeOnboardingIncomplete
+		return nil, "", domain.ErrStripeOnboardingIncomplete
 	}
 
 	amount, err := money.New(cmd.Amount, money.Code(cmd.Currency))
 	if err != nil {
-		return fmt.Errorf("invalid amount: %w", err)
+		return nil, "", fmt.Errorf("invalid amount: %w", err)
+	}
+	description, err := account.NormalizeDescription(cmd.Description)
+	if err != nil {
+		return nil, "", err
+	}
+	if err := s.enforceKycLimits(
+		ctx, cmd.UserID, cmd.AccountID, moneySourceWithdraw, amount,
+	); err != nil {
+		return nil, "", err
+	}
+	if err := s.enforceWithdrawalCount(ctx, cmd.AccountID); err != nil {
+		return nil, "", err
 	}
+	return amount, description, nil
+}
 
+// emitWithdrawRequested starts the withdrawal flow for amount to target.
+func (s *Service) emitWithdrawRequested(
+	ctx context.Context,
+	userID, accountID uuid.UUID,
+	amount *money.Money,
+	description string,
+	target *commands.ExternalTarget,
+	extra ...events.WithdrawRequestedOpt,
+) error {
 	// Create event with amount and bank account number if provided
 	opts := []events.WithdrawRequestedOpt{
 		events.WithWithdrawAmount(amount),
+		events.WithWithdrawDescription(description),
 	}
+	opts = append(opts, extra...)
 
-	if cmd.ExternalTarget != nil && cmd.ExternalTarget.BankAccountNumber != "" {
-		opts = append(
-			opts,
-			events.WithWithdrawBankAccountNumber(
-				cmd.ExternalTarget.BankAccountNumber,
-			),
-		)
+	if target != nil {
+		if target.BankAccountNumber != "" {
+			opts = append(
+				opts,
+				events.WithWithdrawBankAccountNumber(target.BankAccountNumber),
+				events.WithWithdrawRoutingNumber(target.RoutingNumber),
+				events.WithWithdrawBIC(target.BIC),
+			)
+		}
+		if target.ExternalWalletAddress != "" {
+			opts = append(
+				opts,
+				events.WithWithdrawExternalWallet(
+					target.ExternalWalletAddress,
+					target.Network,
+				),
+			)
+		}
 	}
 
 	wr := events.NewWithdrawRequested(
-		cmd.UserID,
-		cmd.AccountID,
+		userID,
+		accountID,
 		uuid.New(),
 		opts...,
 	)
@@ -182,52 +341,71 @@ func (s *Service) Withdraw(
 	return s.bus.Emit(ctx, wr)
 }
 
-// ListUserAccounts returns all accounts for a specific user.
+// ListUserAccounts returns all accounts for a specific user. It reads outside
+// a unit of work, so a ctx marked with repository.WithReplicaReads lets a read
+// replica serve it.
 func (s *Service) ListUserAccounts(
 	ctx context.Context,
 	userID uuid.UUID,
 ) ([]*dto.AccountRead, error) {
-	uow := s.uow
-	var accounts []*dto.AccountRead
-
-	err := uow.Do(ctx, func(uow repository.UnitOfWork) error {
-		repoAny, err := uow.GetRepository((*repoaccount.Repository)(nil))
-		if err != nil {
-			return fmt.Errorf("failed to get account repository: %w", err)
-		}
-		acctRepo := repoAny.(repoaccount.Repository)
-
-		accounts, err = acctRepo.ListByUser(ctx, userID)
-		if err != nil {
-			return fmt.Errorf("failed to list user accounts: %w", err)
-		}
-
-		return nil
-	})
-
+	acctRepo, err := getAccountRepository(s.uow)
 	if err != nil {
-		s.logger.Error("Failed to list user accounts", "error", err, "userID", userID)
 		return nil, err
 	}
+	accounts, err := acctRepo.ListByUser(ctx, userID)
+	if err != nil {
+		s.logger.Error("Failed to list user accounts", "error", err, "userID", userID)
+		return nil, fmt.Errorf("failed to list user accounts: %w", err)
+	}
+	for _, acc := range accounts {
+		acc.InterestAPY = s.interestAPY(acc.Type)
+	}
 
 	return accounts, nil
 }
 
 // Transfer moves funds from one account to another account.
+// When cmd.ExecuteAt is set the transfer is scheduled instead (see
+// ScheduleTransfer), and when cmd.RequireApproval is set it waits for approval
+// (see RequestTransferApproval).
 func (s *Service) Transfer(
 	ctx context.Context,
 	cmd commands.Transfer,
 ) error {
+	if cmd.RequireApproval {
+		_, err := s.RequestTransferApproval(ctx, cmd)
+		return err
+	}
+	if !cmd.ExecuteAt.IsZero() {
+		_, err := s.ScheduleTransfer(ctx, cmd)
+		return err
+	}
+	unlock, err := s.lockAccounts(ctx, cmd.AccountID, cmd.ToAccountID)
+	if err != nil {
+		return err
+	}
+	defer unlock()
 	amount, err := money.New(cmd.Amount, money.Code(cmd.Currency))
 	if err != nil {
 		return err
 	}
+	description, err := account.NormalizeDescription(cmd.Description)
+	if err != nil {
+		return err
+	}
+	if err := s.ensureAccountAcceptsFunds(ctx, cmd.ToAccountID); err != nil {
+		return err
+	}
+	if err := s.enforceWithdrawalCount(ctx, cmd.AccountID); err != nil {
+		return err
+	}
 	tr := events.NewTransferRequested(
 		cmd.UserID,
 		cmd.AccountID,
 		uuid.New(),
 		events.WithTransferDestAccountID(cmd.ToAccountID),
 		events.WithTransferRequestedAmount(amount),
+		events.WithTransferDescription(description),
 	)
 	return s.bus.Emit(ctx, tr)
 }
@@ -0,0 +1,144 @@
+package account
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/amirasaad/fintech/pkg/domain/account"
+	"github.com/google/uuid"
+)
+
+// DefaultAccountLockTimeout is how long an operation waits for an account
+// held by another operation unless configured otherwise.
+const DefaultAccountLockTimeout = 5 * time.Second
+
+// AccountLocker serializes the operations that change an account's balance.
+// Deposit, Withdraw, Transfer and the approval requests lock the accounts
+// they affect for as long as they run; with a synchronous event bus that
+// includes the handlers that update the balance, with the asynchronous buses
+// only the checks and the request itself.
+type AccountLocker interface {
+	// Lock blocks until the account is locked or ctx is done, and returns
+	// the function that releases the lock. Calling it more than once has no
+	// effect.
+	Lock(ctx context.Context, accountID uuid.UUID) (unlock func(), err error)
+}
+
+// WithAccountLocker locks accounts with locker, waiting at most timeout, or
+// DefaultAccountLockTimeout if timeout is not positive, for an account held
+// by another operation. Without it accounts are locked within the process.
+func WithAccountLocker(locker AccountLocker, timeout time.Duration) Option {
+	return func(s *Service) {
+		if locker != nil {
+			s.locker = locker
+		}
+		if timeout > 0 {
+			s.lockTimeout = timeout
+		}
+	}
+}
+
+// lockAccounts locks the accounts, in ID order so that operations locking
+// the same accounts cannot deadlock, and returns the function that releases
+// them. It returns account.ErrAccountBusy if an account is not locked within
+// the lock timeout; accounts already locked are then released.
+func (s *Service) lockAccounts(ctx context.Context, accountIDs ...uuid.UUID) (func(), error) {
+	ids := make([]uuid.UUID, 0, len(accountIDs))
+	for _, id := range accountIDs {
+		if id != uuid.Nil && !slices.Contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+	slices.SortFunc(ids, func(a, b uuid.UUID) int { return bytes.Compare(a[:], b[:]) })
+
+	timeout := s.lockTimeout
+	if timeout <= 0 {
+		timeout = DefaultAccountLockTimeout
+	}
+	lockCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	unlocks := make([]func(), 0, len(ids))
+	unlockAll := func() {
+		for i := len(unlocks) - 1; i >= 0; i-- {
+			unlocks[i]()
+		}
+	}
+	for _, id := range ids {
+		unlock, err := s.locker.Lock(lockCtx, id)
+		if err != nil {
+			unlockAll()
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			s.logger.Warn("failed to lock account", "account_id", id, "error", err)
+			if errors.Is(err, context.DeadlineExceeded) {
+				return nil, account.ErrAccountBusy
+			}
+			return nil, fmt.Errorf("failed to lock account: %w", err)
+		}
+		unlocks = append(unlocks, unlock)
+	}
+	return unlockAll, nil
+}
+
+// LocalAccountLocker locks accounts within the process. It serializes
+// operations handled by one instance only; deployments running several use a
+// distributed AccountLocker.
+type LocalAccountLocker struct {
+	mu    sync.Mutex
+	locks map[uuid.UUID]*localAccountLock
+}
+
+// localAccountLock is the lock of one account. refs counts the operations
+// holding or waiting for it, so that it is dropped once unused.
+type localAccountLock struct {
+	held chan struct{}
+	refs int
+}
+
+// NewLocalAccountLocker creates a LocalAccountLocker.
+func NewLocalAccountLocker() *LocalAccountLocker {
+	return &LocalAccountLocker{locks: make(map[uuid.UUID]*localAccountLock)}
+}
+
+// Lock locks the account.
+func (l *LocalAccountLocker) Lock(ctx context.Context, accountID uuid.UUID) (func(), error) {
+	l.mu.Lock()
+	lock, ok := l.locks[accountID]
+	if !ok {
+		lock = &localAccountLock{held: make(chan struct{}, 1)}
+		l.locks[accountID] = lock
+	}
+	lock.refs++
+	l.mu.Unlock()
+
+	select {
+	case lock.held <- struct{}{}:
+	case <-ctx.Done():
+		l.release(accountID, lock)
+		return nil, ctx.Err()
+	}
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			<-lock.held
+			l.release(accountID, lock)
+		})
+	}, nil
+}
+
+// release drops a reference to the account's lock.
+func (l *LocalAccountLocker) release(accountID uuid.UUID, lock *localAccountLock) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	lock.refs--
+	if lock.refs == 0 {
+		delete(l.locks, accountID)
+	}
+}
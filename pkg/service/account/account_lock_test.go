@@ -0,0 +1,159 @@
+package account_test
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/amirasaad/fintech/infra/eventbus"
+	"github.com/amirasaad/fintech/pkg/commands"
+	accountdomain "github.com/amirasaad/fintech/pkg/domain/account"
+	"github.com/amirasaad/fintech/pkg/domain/events"
+	"github.com/amirasaad/fintech/pkg/money"
+	accountsvc "github.com/amirasaad/fintech/pkg/service/account"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccountLock_ParallelOperationsKeepBalances(t *testing.T) {
+	memBus := eventbus.NewWithMemory(slog.New(slog.DiscardHandler))
+	svc := accountsvc.New(memBus, nil, slog.New(slog.DiscardHandler), nil)
+	a, b := uuid.New(), uuid.New()
+
+	// The handlers read and write balances without a transaction, with a
+	// delay in between, like the handlers applying the events do: operations
+	// on an account that overlap lose updates unless the lock serializes them.
+	var (
+		mu       sync.Mutex
+		balances = map[uuid.UUID]money.Amount{a: 100_00, b: 0}
+		rejected atomic.Int64
+	)
+	read := func(id uuid.UUID) money.Amount {
+		mu.Lock()
+		defer mu.Unlock()
+		return balances[id]
+	}
+	write := func(id uuid.UUID, amount money.Amount) {
+		mu.Lock()
+		defer mu.Unlock()
+		balances[id] = amount
+	}
+	memBus.Register(events.EventTypeDepositRequested, func(_ context.Context, e events.Event) error {
+		dr := e.(*events.DepositRequested)
+		balance := read(dr.AccountID)
+		time.Sleep(100 * time.Microsecond)
+		write(dr.AccountID, balance+dr.Amount.Amount())
+		return nil
+	})
+	memBus.Register(events.EventTypeTransferRequested, func(_ context.Context, e events.Event) error {
+		tr := e.(*events.TransferRequested)
+		from, to := read(tr.AccountID), read(tr.DestAccountID)
+		if from < tr.Amount.Amount() {
+			rejected.Add(1)
+			return accountdomain.ErrInsufficientFunds
+		}
+		time.Sleep(100 * time.Microsecond)
+		write(tr.AccountID, from-tr.Amount.Amount())
+		write(tr.DestAccountID, to+tr.Amount.Amount())
+		return nil
+	})
+
+	ctx := context.Background()
+	var wg sync.WaitGroup
+	run := func(fn func() error) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, fn())
+		}()
+	}
+	const n = 20
+	for range n {
+		// Transfers both ways lock the same two accounts; ordered locking
+		// keeps them from deadlocking.
+		run(func() error {
+			return svc.Transfer(ctx, commands.Transfer{
+				AccountID: a, ToAccountID: b, Amount: 10, Currency: "USD",
+			})
+		})
+		run(func() error {
+			return svc.Transfer(ctx, commands.Transfer{
+				AccountID: b, ToAccountID: a, Amount: 10, Currency: "USD",
+			})
+		})
+		run(func() error {
+			return svc.Deposit(ctx, commands.Deposit{
+				AccountID: a, Amount: 5, Currency: "USD",
+			})
+		})
+	}
+	wg.Wait()
+
+	assert.GreaterOrEqual(t, read(a), money.Amount(0))
+	assert.GreaterOrEqual(t, read(b), money.Amount(0))
+	assert.Equal(t, money.Amount(100_00+n*5_00), read(a)+read(b), "money created or lost")
+	assert.Zero(t, read(b)%10_00)
+	t.Logf("rejected transfers: %d", rejected.Load())
+}
+
+func TestAccountLock_Timeout(t *testing.T) {
+	memBus := eventbus.NewWithMemory(slog.New(slog.DiscardHandler))
+	locker := accountsvc.NewLocalAccountLocker()
+	svc := accountsvc.New(
+		memBus,
+		nil,
+		slog.New(slog.DiscardHandler),
+		nil,
+		accountsvc.WithAccountLocker(locker, 20*time.Millisecond),
+	)
+	a, b := uuid.New(), uuid.New()
+	ctx := context.Background()
+
+	unlock, err := locker.Lock(ctx, b)
+	require.NoError(t, err)
+	err = svc.Transfer(ctx, commands.Transfer{
+		AccountID: a, ToAccountID: b, Amount: 10, Currency: "USD",
+	})
+	require.ErrorIs(t, err, accountdomain.ErrAccountBusy)
+	assert.Empty(t, memBus.Published())
+
+	// The source account, locked before the busy one, was released.
+	lockCtx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	unlockA, err := locker.Lock(lockCtx, a)
+	require.NoError(t, err)
+	unlockA()
+
+	unlock()
+	unlock() // releasing twice is a no-op
+	require.NoError(t, svc.Transfer(ctx, commands.Transfer{
+		AccountID: a, ToAccountID: b, Amount: 10, Currency: "USD",
+	}))
+}
+
+func TestAccountLock_ReleasedOnPanic(t *testing.T) {
+	memBus := eventbus.NewWithMemory(slog.New(slog.DiscardHandler))
+	locker := accountsvc.NewLocalAccountLocker()
+	svc := accountsvc.New(
+		memBus,
+		nil,
+		slog.New(slog.DiscardHandler),
+		nil,
+		accountsvc.WithAccountLocker(locker, 20*time.Millisecond),
+	)
+	a := uuid.New()
+	memBus.Register(events.EventTypeDepositRequested, func(context.Context, events.Event) error {
+		panic("handler failed")
+	})
+	ctx := context.Background()
+	deposit := commands.Deposit{AccountID: a, Amount: 5, Currency: "USD"}
+
+	assert.Panics(t, func() { _ = svc.Deposit(ctx, deposit) })
+	unlock, err := locker.Lock(ctx, a)
+	require.NoError(t, err)
+	unlock()
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"log/slog"
+	"strings"
 	"testing"
 
 	"github.com/amirasaad/fintech/infra/eventbus"
@@ -14,12 +15,16 @@ import (
 	"github.com/amirasaad/fintech/pkg/domain/events"
 	"github.com/amirasaad/fintech/pkg/domain/user"
 	"github.com/amirasaad/fintech/pkg/dto"
+	"github.com/amirasaad/fintech/pkg/money"
+	"github.com/amirasaad/fintech/pkg/provider/exchange"
+	"github.com/amirasaad/fintech/pkg/provider/payment"
 	"github.com/amirasaad/fintech/pkg/repository"
 	repoaccount "github.com/amirasaad/fintech/pkg/repository/account"
 	"github.com/amirasaad/fintech/pkg/repository/transaction"
 	userrepo "github.com/amirasaad/fintech/pkg/repository/user"
 	accountsvc "github.com/amirasaad/fintech/pkg/service/account"
 	"github.com/amirasaad/fintech/pkg/service/stripeconnect"
+	"github.com/amirasaad/fintech/pkg/validation"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -132,6 +137,105 @@ func TestDeposit_PublishesEvent(t *testing.T) {
 	assert.True(t, called, "Handler should have been called")
 }
 
+func TestDeposit_RedirectURLs(t *testing.T) {
+	memBus := eventbus.NewWithMemory(slog.Default())
+	svc := accountsvc.New(
+		memBus, nil, slog.Default(), nil,
+		accountsvc.WithRedirectAllowedHosts([]string{"shop.example.com"}),
+	)
+
+	var got *events.DepositRequested
+	memBus.Register(
+		events.EventTypeDepositRequested,
+		func(c context.Context, e events.Event) error {
+			got, _ = e.(*events.DepositRequested)
+			return nil
+		})
+
+	cmd := commands.Deposit{
+		UserID:     uuid.New(),
+		AccountID:  uuid.New(),
+		Amount:     10,
+		Currency:   "USD",
+		SuccessURL: "https://shop.example.com/paid",
+		CancelURL:  "https://shop.example.com/cart",
+	}
+	require.NoError(t, svc.Deposit(context.Background(), cmd))
+	require.NotNil(t, got)
+	assert.Equal(t, cmd.SuccessURL, got.SuccessURL)
+	assert.Equal(t, cmd.CancelURL, got.CancelURL)
+
+	for _, u := range []string{
+		"https://evil.example.com/paid",
+		"/relative/path",
+		"javascript://shop.example.com/x",
+	} {
+		cmd.SuccessURL = u
+		err := svc.Deposit(context.Background(), cmd)
+		require.ErrorIs(t, err, payment.ErrRedirectURLNotAllowed, u)
+	}
+}
+
+func TestDeposit_StatementDescriptor(t *testing.T) {
+	memBus := eventbus.NewWithMemory(slog.Default())
+	svc := accountsvc.New(memBus, nil, slog.Default(), nil)
+
+	var got *events.DepositRequested
+	memBus.Register(
+		events.EventTypeDepositRequested,
+		func(c context.Context, e events.Event) error {
+			got, _ = e.(*events.DepositRequested)
+			return nil
+		})
+
+	cmd := commands.Deposit{
+		UserID:              uuid.New(),
+		AccountID:           uuid.New(),
+		Amount:              10,
+		Currency:            "USD",
+		StatementDescriptor: "ORDER 1042",
+	}
+	require.NoError(t, svc.Deposit(context.Background(), cmd))
+	require.NotNil(t, got)
+	assert.Equal(t, "ORDER 1042", got.StatementDescriptor)
+
+	got = nil
+	cmd.StatementDescriptor = "ORDER*1042"
+	err := svc.Deposit(context.Background(), cmd)
+	require.ErrorIs(t, err, validation.ErrInvalidStatementDescriptor)
+	assert.Nil(t, got)
+}
+
+func TestDeposit_CaptureMethod(t *testing.T) {
+	memBus := eventbus.NewWithMemory(slog.Default())
+	svc := accountsvc.New(memBus, nil, slog.Default(), nil)
+
+	var got *events.DepositRequested
+	memBus.Register(
+		events.EventTypeDepositRequested,
+		func(c context.Context, e events.Event) error {
+			got, _ = e.(*events.DepositRequested)
+			return nil
+		})
+
+	cmd := commands.Deposit{
+		UserID:        uuid.New(),
+		AccountID:     uuid.New(),
+		Amount:        10,
+		Currency:      "USD",
+		CaptureMethod: "manual",
+	}
+	require.NoError(t, svc.Deposit(context.Background(), cmd))
+	require.NotNil(t, got)
+	assert.Equal(t, "manual", got.CaptureMethod)
+
+	got = nil
+	cmd.CaptureMethod = "later"
+	err := svc.Deposit(context.Background(), cmd)
+	require.ErrorIs(t, err, payment.ErrInvalidCaptureMethod)
+	assert.Nil(t, got)
+}
+
 func TestWithdraw_PublishesEvent(t *testing.T) {
 	memBus := eventbus.NewWithMemory(slog.Default())
 	uow := mocks.NewUnitOfWork(t)
@@ -198,6 +302,7 @@ func TestTransfer_PublishesEvent(t *testing.T) {
 		ToAccountID: destAccountID,
 		Amount:      amount,
 		Currency:    currency,
+		Description: "  Rent\nfor March ",
 	}
 	err := svc.Transfer(context.TODO(), cmd)
 	require.NoError(t, err)
@@ -209,6 +314,12 @@ func TestTransfer_PublishesEvent(t *testing.T) {
 	assert.Equal(t, destAccountID, evt.DestAccountID)
 	// assert.InEpsilon(t, amount, evt.Amount, 0.01)
 	assert.Equal(t, currency, evt.Amount.Currency().String())
+	assert.Equal(t, "Rent for March", evt.Description)
+
+	cmd.Description = strings.Repeat("x", accountdomain.MaxDescriptionLength+1)
+	err = svc.Transfer(context.TODO(), cmd)
+	require.ErrorIs(t, err, accountdomain.ErrInvalidDescription)
+	assert.Len(t, publishedEvents, 1)
 }
 
 func TestGetAccount_Success(t *testing.T) {
@@ -331,16 +442,21 @@ func TestGetBalance_Success(t *testing.T) {
 
 	userID := uuid.New()
 	acc := dto.AccountRead{
-		ID:       uuid.New(),
-		UserID:   userID,
-		Balance:  100,
-		Currency: "USD",
+		ID:           uuid.New(),
+		UserID:       userID,
+		Balance:      100,
+		Currency:     "USD",
+		BalanceMoney: money.NewFromData(10000, "USD"),
 	}
 	accountRepo.EXPECT().Get(context.Background(), acc.ID).Return(&acc, nil)
 	svc := accountsvc.New(nil, uow, slog.Default(), nil)
 	got, err := svc.GetBalance(context.Background(), userID, acc.ID)
 	require.NoError(t, err)
-	assert.InDelta(t, acc.Balance, got, 0.01)
+	assert.Equal(t, money.Amount(10000), got.Amount())
+	assert.Equal(t, "100.00", got.AmountString())
+
+	_, err = svc.GetBalance(context.Background(), uuid.New(), acc.ID)
+	require.ErrorIs(t, err, accountdomain.ErrAccountNotFound)
 
 }
 
@@ -366,5 +482,187 @@ func TestGetBalance_NotFound(t *testing.T) {
 		nil,
 	).GetBalance(context.Background(), uuid.New(), uuid.New())
 	require.Error(err)
-	assert.InDelta(0, balance, 0.01)
+	assert.Nil(balance)
+}
+
+func TestListAccountsByCurrency(t *testing.T) {
+	uow := mocks.NewUnitOfWork(t)
+	repo := mocks.NewAccountRepository(t)
+	uow.EXPECT().GetRepository(mock.Anything).Return(repo, nil)
+	accounts := []*dto.AccountRead{{ID: uuid.New(), Currency: "EUR", Balance: 10}}
+	repo.EXPECT().
+		ListByCurrency(mock.Anything, "EUR", false, 1, accountsvc.MaxAccountPageSize).
+		Return(accounts, nil)
+	repo.EXPECT().TotalsByCurrency(mock.Anything, "EUR", false).
+		Return(&dto.AccountTotals{Currency: "EUR", Count: 3, Balance: 42.5}, nil)
+
+	svc := accountsvc.New(nil, uow, slog.Default(), nil)
+	got, totals, err := svc.ListAccountsByCurrency(context.Background(), "EUR", false, 0, 10_000)
+	require.NoError(t, err)
+	assert.Equal(t, accounts, got)
+	assert.Equal(t, int64(3), totals.Count)
+}
+
+func TestSetOverdraftLimit(t *testing.T) {
+	uow := mocks.NewUnitOfWork(t)
+	repo := mocks.NewAccountRepository(t)
+	uow.EXPECT().GetRepository(mock.Anything).Return(repo, nil)
+	accountID := uuid.New()
+	repo.EXPECT().Get(mock.Anything, accountID).Return(
+		&dto.AccountRead{ID: accountID, Currency: "JPY", Balance: -500}, nil,
+	)
+	limit := int64(1000)
+	repo.EXPECT().Update(mock.Anything, accountID, dto.AccountUpdate{OverdraftLimit: &limit}).
+		Return(nil)
+
+	svc := accountsvc.New(nil, uow, slog.Default(), nil)
+	acc, err := svc.SetOverdraftLimit(context.Background(), accountID, 1000)
+	require.NoError(t, err)
+	assert.InDelta(t, 1000.0, acc.OverdraftLimit, 0.001)
+	assert.InDelta(t, -500.0, acc.Balance, 0.001)
+
+	_, err = svc.SetOverdraftLimit(context.Background(), accountID, -1)
+	require.ErrorIs(t, err, accountdomain.ErrNegativeOverdraftLimit)
+}
+
+func TestSetFeePolicy(t *testing.T) {
+	uow := mocks.NewUnitOfWork(t)
+	repo := mocks.NewAccountRepository(t)
+	uow.EXPECT().GetRepository(mock.Anything).Return(repo, nil)
+	accountID := uuid.New()
+	repo.EXPECT().Get(mock.Anything, accountID).Return(
+		&dto.AccountRead{ID: accountID, Currency: "USD"}, nil,
+	)
+	absorb, inherit := "absorb", ""
+	repo.EXPECT().Update(mock.Anything, accountID, dto.AccountUpdate{FeePolicy: &absorb}).
+		Return(nil).Once()
+	repo.EXPECT().Update(mock.Anything, accountID, dto.AccountUpdate{FeePolicy: &inherit}).
+		Return(nil).Once()
+
+	svc := accountsvc.New(nil, uow, slog.Default(), nil)
+	acc, err := svc.SetFeePolicy(context.Background(), accountID, "absorb")
+	require.NoError(t, err)
+	assert.Equal(t, "absorb", acc.FeePolicy)
+
+	acc, err = svc.SetFeePolicy(context.Background(), accountID, "")
+	require.NoError(t, err)
+	assert.Empty(t, acc.FeePolicy)
+
+	_, err = svc.SetFeePolicy(context.Background(), accountID, "platform")
+	require.ErrorIs(t, err, accountdomain.ErrInvalidFeeAbsorption)
+}
+
+// deactivatedCurrencies is a CurrencyRegistry with a fixed set of inactive codes.
+type deactivatedCurrencies map[string]bool
+
+func (d deactivatedCurrencies) IsDeactivated(_ context.Context, code string) bool {
+	return d[code]
+}
+
+func TestCurrencyWindDown(t *testing.T) {
+	memBus := eventbus.NewWithMemory(slog.Default())
+	uow := mocks.NewUnitOfWork(t)
+	accountRepo := mocks.NewAccountRepository(t)
+	userID := uuid.New()
+	eurAccountID, usdAccountID := uuid.New(), uuid.New()
+	uow.EXPECT().GetRepository(mock.Anything).Return(accountRepo, nil).Maybe()
+	accountRepo.EXPECT().Get(mock.Anything, eurAccountID).Return(&dto.AccountRead{
+		ID: eurAccountID, UserID: userID, Currency: "EUR",
+	}, nil).Maybe()
+	accountRepo.EXPECT().Get(mock.Anything, usdAccountID).Return(&dto.AccountRead{
+		ID: usdAccountID, UserID: userID, Currency: "USD",
+	}, nil).Maybe()
+	svc := accountsvc.New(
+		memBus, uow, slog.Default(), nil,
+		accountsvc.WithCurrencyRegistry(deactivatedCurrencies{"EUR": true}),
+	)
+	var transfers int
+	memBus.Register(
+		events.EventTypeTransferRequested,
+		func(c context.Context, e events.Event) error {
+			transfers++
+			return nil
+		},
+	)
+	ctx := context.Background()
+
+	t.Run("blocks deposits into the currency", func(t *testing.T) {
+		err := svc.Deposit(ctx, commands.Deposit{
+			UserID: userID, AccountID: eurAccountID, Amount: 10, Currency: "USD",
+		})
+		require.ErrorIs(t, err, accountdomain.ErrCurrencyDeactivated)
+		err = svc.Deposit(ctx, commands.Deposit{
+			UserID: userID, AccountID: usdAccountID, Amount: 10, Currency: "EUR",
+		})
+		require.ErrorIs(t, err, accountdomain.ErrCurrencyDeactivated)
+	})
+
+	t.Run("blocks transfers into the currency", func(t *testing.T) {
+		err := svc.Transfer(ctx, commands.Transfer{
+			UserID: userID, AccountID: usdAccountID, ToAccountID: eurAccountID,
+			Amount: 10, Currency: "USD",
+		})
+		require.ErrorIs(t, err, accountdomain.ErrCurrencyDeactivated)
+	})
+
+	t.Run("allows transfers out of the currency", func(t *testing.T) {
+		err := svc.Transfer(ctx, commands.Transfer{
+			UserID: userID, AccountID: eurAccountID, ToAccountID: usdAccountID,
+			Amount: 10, Currency: "EUR",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 1, transfers)
+	})
+
+	t.Run("blocks new accounts in the currency", func(t *testing.T) {
+		uow.EXPECT().Do(mock.Anything, mock.Anything).RunAndReturn(
+			func(ctx context.Context, fn func(repository.UnitOfWork) error) error {
+				return fn(uow)
+			},
+		).Once()
+		accountRepo.EXPECT().ListByUser(mock.Anything, userID).
+			Return([]*dto.AccountRead{}, nil).Once()
+		_, err := svc.CreateAccount(ctx, dto.AccountCreate{UserID: userID, Currency: "EUR"})
+		require.ErrorIs(t, err, accountdomain.ErrCurrencyDeactivated)
+	})
+}
+
+// fixedRateConverter converts at a fixed rate.
+type fixedRateConverter struct{ rate float64 }
+
+func (f fixedRateConverter) Convert(
+	_ context.Context,
+	amount *money.Money,
+	to money.Code,
+) (*money.Money, *exchange.RateInfo, error) {
+	converted, err := amount.Convert(f.rate, to.ToCurrency(), money.RoundHalfEven)
+	if err != nil {
+		return nil, nil, err
+	}
+	return converted, &exchange.RateInfo{
+		FromCurrency: amount.Currency().String(),
+		ToCurrency:   to.String(),
+		Rate:         f.rate,
+	}, nil
+}
+
+func TestConvertBalance(t *testing.T) {
+	ctx := context.Background()
+	balance, err := money.New(100, money.USD)
+	require.NoError(t, err)
+
+	t.Run("converts with the configured converter", func(t *testing.T) {
+		svc := accountsvc.New(nil, nil, slog.Default(), nil,
+			accountsvc.WithCurrencyConverter(fixedRateConverter{rate: 0.9}))
+		converted, rate, err := svc.ConvertBalance(ctx, balance, money.EUR)
+		require.NoError(t, err)
+		assert.Equal(t, "90.00 EUR", converted.String())
+		assert.InDelta(t, 0.9, rate.Rate, 1e-9)
+	})
+
+	t.Run("unsupported without a converter", func(t *testing.T) {
+		svc := accountsvc.New(nil, nil, slog.Default(), nil)
+		_, _, err := svc.ConvertBalance(ctx, balance, money.EUR)
+		require.ErrorIs(t, err, exchange.ErrUnsupportedPair)
+	})
 }
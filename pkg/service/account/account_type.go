@@ -0,0 +1,87 @@
+package account
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/amirasaad/fintech/pkg/config"
+	"github.com/amirasaad/fintech/pkg/domain/account"
+	"github.com/amirasaad/fintech/pkg/dto"
+	"github.com/amirasaad/fintech/pkg/handler/common"
+	"github.com/google/uuid"
+)
+
+// WithAccountTypeRules enforces the per-type rules in cfg, such as how many
+// withdrawals savings accounts allow per month. Without it no type limits
+// withdrawals or transfers.
+func WithAccountTypeRules(cfg *config.Account) Option {
+	return func(s *Service) { s.typeRules = cfg }
+}
+
+// enforceWithdrawalCount returns a *account.WithdrawalCountError if the
+// account has already been debited as many times this UTC month as its type
+// allows. Withdrawals, withdrawals held for approval and outgoing transfers
+// all count; failed and canceled ones do not.
+func (s *Service) enforceWithdrawalCount(ctx context.Context, accountID uuid.UUID) error {
+	if s.typeRules == nil {
+		return nil
+	}
+	acctRepo, err := getAccountRepository(s.uow)
+	if err != nil {
+		return err
+	}
+	acc, err := acctRepo.Get(ctx, accountID)
+	if err != nil {
+		return fmt.Errorf("failed to get account: %w", err)
+	}
+	accountType, err := account.ParseType(acc.Type)
+	if err != nil {
+		accountType = account.TypeChecking
+	}
+	limit := s.typeRules.MonthlyWithdrawals(accountType.String())
+	if limit <= 0 {
+		return nil
+	}
+
+	txRepo, err := common.GetTransactionRepository(s.uow, s.logger)
+	if err != nil {
+		return err
+	}
+	txs, err := txRepo.ListByAccount(ctx, accountID)
+	if err != nil {
+		return fmt.Errorf("failed to list transactions: %w", err)
+	}
+	now := time.Now().UTC()
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	count := 0
+	for _, tx := range txs {
+		if !countsAsWithdrawal(tx) || tx.CreatedAt.Before(monthStart) {
+			continue
+		}
+		switch account.TransactionStatus(tx.Status) {
+		case account.TransactionStatusFailed, account.TransactionStatusCanceled:
+			continue
+		}
+		count++
+	}
+	if count < limit {
+		return nil
+	}
+	s.logger.Warn("monthly withdrawal count exceeded",
+		"account_id", accountID,
+		"type", accountType,
+		"limit", limit,
+	)
+	return &account.WithdrawalCountError{Type: accountType, Limit: limit}
+}
+
+// countsAsWithdrawal reports whether tx takes money out of its account
+// towards the monthly withdrawal count.
+func countsAsWithdrawal(tx *dto.TransactionRead) bool {
+	switch tx.MoneySource {
+	case moneySourceWithdraw, moneySourceWithdrawHold, moneySourceTransfer:
+		return tx.Amount < 0
+	}
+	return false
+}
@@ -0,0 +1,221 @@
+package account_test
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/amirasaad/fintech/infra/eventbus"
+	"github.com/amirasaad/fintech/internal/fixtures/mocks"
+	"github.com/amirasaad/fintech/pkg/commands"
+	"github.com/amirasaad/fintech/pkg/config"
+	"github.com/amirasaad/fintech/pkg/domain"
+	accountdomain "github.com/amirasaad/fintech/pkg/domain/account"
+	"github.com/amirasaad/fintech/pkg/domain/events"
+	"github.com/amirasaad/fintech/pkg/dto"
+	"github.com/amirasaad/fintech/pkg/repository"
+	repoaccount "github.com/amirasaad/fintech/pkg/repository/account"
+	"github.com/amirasaad/fintech/pkg/repository/transaction"
+	repouser "github.com/amirasaad/fintech/pkg/repository/user"
+	accountsvc "github.com/amirasaad/fintech/pkg/service/account"
+	"github.com/amirasaad/fintech/pkg/service/stripeconnect"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithdraw_SavingsWithdrawalCount(t *testing.T) {
+	userID, accountID := uuid.New(), uuid.New()
+	rules := &config.Account{SavingsMonthlyWithdrawals: 2}
+	now := time.Now().UTC()
+	lastMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC).Add(-time.Hour)
+
+	movement := func(moneySource string, amount float64) *dto.TransactionRead {
+		return &dto.TransactionRead{
+			ID:          uuid.New(),
+			AccountID:   accountID,
+			Amount:      amount,
+			Currency:    "USD",
+			Status:      "completed",
+			MoneySource: moneySource,
+			CreatedAt:   now,
+		}
+	}
+	withdrawal := func(status string, createdAt time.Time) *dto.TransactionRead {
+		tx := movement("withdraw", -10)
+		tx.Status = status
+		tx.CreatedAt = createdAt
+		return tx
+	}
+
+	// setup returns a service for a user's account of accountType with txs.
+	setup := func(
+		t *testing.T,
+		accountType string,
+		txs []*dto.TransactionRead,
+	) (*accountsvc.Service, *int) {
+		uow := mocks.NewUnitOfWork(t)
+		userRepo := mocks.NewUserRepository(t)
+		accRepo := mocks.NewAccountRepository(t)
+		txRepo := mocks.NewTransactionRepository(t)
+		uow.EXPECT().GetRepository(mock.Anything).RunAndReturn(
+			func(repoType any) (any, error) {
+				switch repoType.(type) {
+				case *repouser.Repository:
+					return userRepo, nil
+				case *repoaccount.Repository:
+					return accRepo, nil
+				case *transaction.Repository:
+					return txRepo, nil
+				}
+				return nil, nil
+			},
+		)
+		userRepo.EXPECT().GetStripeOnboardingStatus(mock.Anything, userID).Return(true, nil)
+		accRepo.EXPECT().Get(mock.Anything, accountID).Return(&dto.AccountRead{
+			ID: accountID, UserID: userID, Currency: "USD", Type: accountType,
+		}, nil)
+		txRepo.EXPECT().ListByAccount(mock.Anything, accountID).Return(txs, nil).Maybe()
+
+		bus := eventbus.NewWithMemory(slog.Default())
+		requested := 0
+		bus.Register(
+			events.EventTypeWithdrawRequested,
+			func(context.Context, events.Event) error {
+				requested++
+				return nil
+			},
+		)
+		svc := accountsvc.New(
+			bus, uow, slog.Default(),
+			stripeconnect.New(uow, slog.Default(), &config.Stripe{}),
+			accountsvc.WithAccountTypeRules(rules),
+		)
+		return svc, &requested
+	}
+	withdraw := func(svc *accountsvc.Service) error {
+		return svc.Withdraw(context.Background(), commands.Withdraw{
+			UserID:    userID,
+			AccountID: accountID,
+			Amount:    10,
+			Currency:  "USD",
+			ExternalTarget: &commands.ExternalTarget{
+				BankAccountNumber: "1234567890",
+			},
+		})
+	}
+
+	t.Run("allows withdrawals up to the monthly count", func(t *testing.T) {
+		svc, requested := setup(t, "savings", []*dto.TransactionRead{
+			withdrawal("completed", now),
+			// Failed withdrawals and last month's do not count.
+			withdrawal("failed", now),
+			withdrawal("completed", lastMonth),
+			withdrawal("completed", lastMonth),
+		})
+		require.NoError(t, withdraw(svc))
+		assert.Equal(t, 1, *requested)
+	})
+
+	t.Run("rejects withdrawals over the monthly count", func(t *testing.T) {
+		svc, requested := setup(t, "savings", []*dto.TransactionRead{
+			withdrawal("completed", now),
+			withdrawal("pending", now),
+		})
+		err := withdraw(svc)
+		require.ErrorIs(t, err, accountdomain.ErrWithdrawalCountExceeded)
+		var countErr *accountdomain.WithdrawalCountError
+		require.ErrorAs(t, err, &countErr)
+		assert.Equal(t, accountdomain.TypeSavings, countErr.Type)
+		assert.Equal(t, 2, countErr.Limit)
+		assert.Zero(t, *requested)
+	})
+
+	t.Run("counts outgoing transfers and held withdrawals", func(t *testing.T) {
+		svc, requested := setup(t, "savings", []*dto.TransactionRead{
+			movement("transfer", -10),
+			movement("withdraw_hold", -10),
+		})
+		require.ErrorIs(t, withdraw(svc), accountdomain.ErrWithdrawalCountExceeded)
+		assert.Zero(t, *requested)
+	})
+
+	t.Run("does not count money coming in", func(t *testing.T) {
+		svc, requested := setup(t, "savings", []*dto.TransactionRead{
+			movement("transfer", 10),
+			movement("deposit", 10),
+			movement("withdraw", -10),
+		})
+		require.NoError(t, withdraw(svc))
+		assert.Equal(t, 1, *requested)
+	})
+
+	t.Run("does not limit checking accounts", func(t *testing.T) {
+		svc, requested := setup(t, "checking", []*dto.TransactionRead{
+			withdrawal("completed", now),
+			withdrawal("completed", now),
+			withdrawal("completed", now),
+		})
+		require.NoError(t, withdraw(svc))
+		assert.Equal(t, 1, *requested)
+	})
+}
+
+func TestCreateAccount_Type(t *testing.T) {
+	userID := uuid.New()
+
+	create := func(
+		t *testing.T,
+		accountType string,
+		existing ...*dto.AccountRead,
+	) (*dto.AccountCreate, error) {
+		uow, accountRepo, _ := setupTestMocks(t)
+		uow.EXPECT().Do(mock.Anything, mock.Anything).RunAndReturn(
+			func(ctx context.Context, fn func(repository.UnitOfWork) error) error {
+				return fn(uow)
+			},
+		)
+		uow.EXPECT().GetRepository(mock.Anything).Return(accountRepo, nil)
+		accountRepo.EXPECT().ListByUser(mock.Anything, userID).
+			Return(existing, nil).
+			Maybe()
+		var created *dto.AccountCreate
+		accountRepo.EXPECT().Create(mock.Anything, mock.Anything).RunAndReturn(
+			func(_ context.Context, c dto.AccountCreate) error {
+				created = &c
+				return nil
+			},
+		).Maybe()
+		accountRepo.EXPECT().Get(mock.Anything, mock.Anything).
+			Return(&dto.AccountRead{}, nil).Maybe()
+		svc := accountsvc.New(nil, uow, slog.Default(), nil)
+		_, err := svc.CreateAccount(
+			context.Background(),
+			dto.AccountCreate{UserID: userID, Currency: "USD", Type: accountType},
+		)
+		return created, err
+	}
+
+	created, err := create(t, "")
+	require.NoError(t, err)
+	assert.Equal(t, "checking", created.Type)
+
+	created, err = create(t, "savings")
+	require.NoError(t, err)
+	assert.Equal(t, "savings", created.Type)
+
+	_, err = create(t, "brokerage")
+	require.ErrorIs(t, err, accountdomain.ErrInvalidAccountType)
+
+	// One account per currency and type.
+	checkingUSD := &dto.AccountRead{UserID: userID, Currency: "USD", Type: "checking"}
+	created, err = create(t, "savings", checkingUSD)
+	require.NoError(t, err)
+	assert.Equal(t, "savings", created.Type)
+
+	_, err = create(t, "checking", checkingUSD)
+	require.ErrorIs(t, err, domain.ErrAlreadyExists)
+	assert.Contains(t, err.Error(), "already has a checking account with currency USD")
+}
@@ -0,0 +1,124 @@
+package account
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/amirasaad/fintech/pkg/domain/account"
+	"github.com/amirasaad/fintech/pkg/dto"
+	"github.com/amirasaad/fintech/pkg/money"
+	"github.com/google/uuid"
+)
+
+const (
+	// DefaultAccountPageSize is the page size used when none is given.
+	DefaultAccountPageSize = 50
+	// MaxAccountPageSize caps the page size of cross-user account listings.
+	MaxAccountPageSize = 500
+)
+
+// ListAccountsByCurrency returns one page of the accounts in the given
+// currency across all users, together with the count and balance totals of
+// every matching account (not just the page). It is intended for operators
+// and performs no ownership checks; callers must restrict it to admins.
+// Closed accounts are excluded unless includeClosed is set.
+func (s *Service) ListAccountsByCurrency(
+	ctx context.Context,
+	currency string,
+	includeClosed bool,
+	page, pageSize int,
+) ([]*dto.AccountRead, *dto.AccountTotals, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = DefaultAccountPageSize
+	}
+	pageSize = min(pageSize, MaxAccountPageSize)
+
+	repo, err := getAccountRepository(s.uow)
+	if err != nil {
+		return nil, nil, err
+	}
+	accounts, err := repo.ListByCurrency(ctx, currency, includeClosed, page, pageSize)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list %s accounts: %w", currency, err)
+	}
+	totals, err := repo.TotalsByCurrency(ctx, currency, includeClosed)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to total %s accounts: %w", currency, err)
+	}
+	return accounts, totals, nil
+}
+
+// SetOverdraftLimit lets the account's balance go down to -limit, in the
+// account currency. A limit of zero removes the overdraft. Lowering the limit
+// does not affect a balance that is already below it; it only blocks further
+// withdrawals and transfers. Like ListAccountsByCurrency it performs no
+// ownership checks; callers must restrict it to admins.
+func (s *Service) SetOverdraftLimit(
+	ctx context.Context,
+	accountID uuid.UUID,
+	limit float64,
+) (*dto.AccountRead, error) {
+	if limit < 0 {
+		return nil, account.ErrNegativeOverdraftLimit
+	}
+	repo, err := getAccountRepository(s.uow)
+	if err != nil {
+		return nil, err
+	}
+	acc, err := repo.Get(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	overdraftLimit, err := money.New(limit, money.Code(acc.Currency))
+	if err != nil {
+		return nil, fmt.Errorf("invalid overdraft limit: %w", err)
+	}
+	amount := overdraftLimit.Amount()
+	if err := repo.Update(ctx, accountID, dto.AccountUpdate{OverdraftLimit: &amount}); err != nil {
+		return nil, fmt.Errorf("failed to update overdraft limit: %w", err)
+	}
+	acc.OverdraftLimit = overdraftLimit.AmountFloat()
+	s.logger.Info("Overdraft limit updated",
+		"account_id", accountID,
+		"overdraft_limit", overdraftLimit.String(),
+	)
+	return acc, nil
+}
+
+// SetFeePolicy sets who bears the provider fee on deposits to the account:
+// absorb credits the full amount and records the fee as a platform cost,
+// pass_through deducts it from the credited amount. An empty policy makes
+// the account follow the global policy again. It applies to fees charged
+// from now on. Like SetOverdraftLimit it performs no ownership checks;
+// callers must restrict it to admins.
+func (s *Service) SetFeePolicy(
+	ctx context.Context,
+	accountID uuid.UUID,
+	policy string,
+) (*dto.AccountRead, error) {
+	if policy != "" {
+		if _, err := account.ParseFeeAbsorption(policy); err != nil {
+			return nil, err
+		}
+	}
+	repo, err := getAccountRepository(s.uow)
+	if err != nil {
+		return nil, err
+	}
+	acc, err := repo.Get(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	if err := repo.Update(ctx, accountID, dto.AccountUpdate{FeePolicy: &policy}); err != nil {
+		return nil, fmt.Errorf("failed to update fee policy: %w", err)
+	}
+	acc.FeePolicy = policy
+	s.logger.Info("Fee policy updated",
+		"account_id", accountID,
+		"fee_policy", policy,
+	)
+	return acc, nil
+}
@@ -0,0 +1,11 @@
+package account
+
+import "github.com/amirasaad/fintech/pkg/commandbus"
+
+// RegisterCommands registers Deposit, Withdraw and Transfer as the handlers of
+// the matching commands on bus.
+func (s *Service) RegisterCommands(bus commandbus.Bus) {
+	commandbus.Handle(bus, s.Deposit)
+	commandbus.Handle(bus, s.Withdraw)
+	commandbus.Handle(bus, s.Transfer)
+}
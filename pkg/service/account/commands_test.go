@@ -0,0 +1,73 @@
+package account_test
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/amirasaad/fintech/infra/eventbus"
+	"github.com/amirasaad/fintech/internal/fixtures/mocks"
+	"github.com/amirasaad/fintech/pkg/commandbus"
+	"github.com/amirasaad/fintech/pkg/commands"
+	"github.com/amirasaad/fintech/pkg/config"
+	"github.com/amirasaad/fintech/pkg/domain/events"
+	userrepo "github.com/amirasaad/fintech/pkg/repository/user"
+	accountsvc "github.com/amirasaad/fintech/pkg/service/account"
+	"github.com/amirasaad/fintech/pkg/service/stripeconnect"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegisterCommands(t *testing.T) {
+	memBus := eventbus.NewWithMemory(slog.Default())
+	uow := mocks.NewUnitOfWork(t)
+	userRepo := mocks.NewUserRepository(t)
+	uow.EXPECT().GetRepository((*userrepo.Repository)(nil)).Return(userRepo, nil).Once()
+	userRepo.EXPECT().
+		GetStripeOnboardingStatus(mock.Anything, mock.Anything).
+		Return(true, nil).
+		Once()
+	stripeConnectSvc := stripeconnect.New(uow, slog.Default(), &config.Stripe{})
+	svc := accountsvc.New(memBus, nil, slog.Default(), stripeConnectSvc)
+
+	var emitted []events.EventType
+	for _, et := range []events.EventType{
+		events.EventTypeDepositRequested,
+		events.EventTypeWithdrawRequested,
+		events.EventTypeTransferRequested,
+	} {
+		memBus.Register(et, func(_ context.Context, e events.Event) error {
+			emitted = append(emitted, events.EventType(e.Type()))
+			return nil
+		})
+	}
+
+	cmdBus := commandbus.New(commandbus.Authenticated(), commandbus.Validation())
+	svc.RegisterCommands(cmdBus)
+
+	userID, accountID := uuid.New(), uuid.New()
+	ctx := context.Background()
+	require.NoError(t, cmdBus.Dispatch(ctx, commands.Deposit{
+		UserID: userID, AccountID: accountID, Amount: 10, Currency: "USD",
+	}))
+	require.NoError(t, cmdBus.Dispatch(ctx, commands.Withdraw{
+		UserID: userID, AccountID: accountID, Amount: 5, Currency: "USD",
+		ExternalTarget: &commands.ExternalTarget{BankAccountNumber: "1234567890"},
+	}))
+	require.NoError(t, cmdBus.Dispatch(ctx, commands.Transfer{
+		UserID: userID, AccountID: accountID, ToAccountID: uuid.New(),
+		Amount: 1, Currency: "USD",
+	}))
+	assert.Equal(t, []events.EventType{
+		events.EventTypeDepositRequested,
+		events.EventTypeWithdrawRequested,
+		events.EventTypeTransferRequested,
+	}, emitted)
+
+	err := cmdBus.Dispatch(ctx, commands.Transfer{
+		UserID: userID, AccountID: accountID, Amount: 1, Currency: "USD",
+	})
+	require.ErrorIs(t, err, commandbus.ErrInvalidCommand)
+}
@@ -0,0 +1,52 @@
+package account
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/amirasaad/fintech/pkg/domain/account"
+	"github.com/google/uuid"
+)
+
+// CurrencyRegistry reports whether a registered currency has been deactivated.
+// The currency service satisfies it.
+type CurrencyRegistry interface {
+	IsDeactivated(ctx context.Context, code string) bool
+}
+
+// WithCurrencyRegistry puts deactivated currencies into wind-down mode: no new
+// accounts, deposits or incoming transfers in them, while withdrawals and
+// transfers out keep working. The registry is consulted on every operation,
+// so deactivation applies to existing accounts immediately.
+func WithCurrencyRegistry(currencies CurrencyRegistry) Option {
+	return func(s *Service) { s.currencies = currencies }
+}
+
+// ensureCurrencyActive returns account.ErrCurrencyDeactivated if money may no
+// longer move into the given currency.
+func (s *Service) ensureCurrencyActive(ctx context.Context, code string) error {
+	if s.currencies != nil && s.currencies.IsDeactivated(ctx, code) {
+		return fmt.Errorf("%w: %s", account.ErrCurrencyDeactivated, code)
+	}
+	return nil
+}
+
+// ensureAccountAcceptsFunds checks the currency of the account that money
+// would move into.
+func (s *Service) ensureAccountAcceptsFunds(
+	ctx context.Context,
+	accountID uuid.UUID,
+) error {
+	if s.currencies == nil {
+		return nil
+	}
+	acctRepo, err := getAccountRepository(s.uow)
+	if err != nil {
+		return err
+	}
+	acc, err := acctRepo.Get(ctx, accountID)
+	if err != nil {
+		return fmt.Errorf("failed to get account: %w", err)
+	}
+	return s.ensureCurrencyActive(ctx, acc.Currency)
+}
@@ -0,0 +1,161 @@
+package account
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/amirasaad/fintech/pkg/domain/account"
+	"github.com/amirasaad/fintech/pkg/domain/events"
+	"github.com/amirasaad/fintech/pkg/dto"
+	"github.com/amirasaad/fintech/pkg/handler/common"
+	"github.com/amirasaad/fintech/pkg/money"
+	"github.com/amirasaad/fintech/pkg/provider/payment"
+	"github.com/amirasaad/fintech/pkg/repository"
+	"github.com/google/uuid"
+)
+
+// WithCheckoutCanceler enables canceling unpaid deposits through the given
+// payment provider.
+func WithCheckoutCanceler(canceler payment.CheckoutCanceler) Option {
+	return func(s *Service) { s.checkoutCanceler = canceler }
+}
+
+// ListPendingDeposits returns the deposits of the user's account that have
+// been requested but not paid yet.
+func (s *Service) ListPendingDeposits(
+	ctx context.Context,
+	userID, accountID uuid.UUID,
+) ([]*dto.TransactionRead, error) {
+	var pending []*dto.TransactionRead
+	err := s.uow.Do(ctx, func(uow repository.UnitOfWork) error {
+		accRepo, err := common.GetAccountRepository(uow, s.logger)
+		if err != nil {
+			return err
+		}
+		acc, err := accRepo.Get(ctx, accountID)
+		if err != nil {
+			return err
+		}
+		if acc.UserID != userID {
+			return account.ErrAccountNotFound
+		}
+
+		txRepo, err := common.GetTransactionRepository(uow, s.logger)
+		if err != nil {
+			return err
+		}
+		txs, err := txRepo.ListByAccount(ctx, accountID)
+		if err != nil {
+			return err
+		}
+		for _, tx := range txs {
+			if isCancellableDeposit(tx) {
+				pending = append(pending, tx)
+			}
+		}
+		return nil
+	})
+	return pending, err
+}
+
+// CancelDeposit cancels a deposit the user has not paid yet. The payment
+// provider's checkout is expired first, so the deposit cannot be paid after
+// it is marked canceled; a deposit that has already been paid, failed or
+// been canceled returns ErrDepositNotCancellable.
+func (s *Service) CancelDeposit(
+	ctx context.Context,
+	userID, transactionID uuid.UUID,
+) (*dto.TransactionRead, error) {
+	if s.checkoutCanceler == nil {
+		return nil, payment.ErrCheckoutCancelNotSupported
+	}
+
+	deposit, err := s.getCancellableDeposit(ctx, s.uow, userID, transactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkoutCanceler.CancelCheckout(ctx, transactionID); err != nil {
+		if errors.Is(err, payment.ErrCheckoutNotCancellable) {
+			return nil, fmt.Errorf("%w: %w", account.ErrDepositNotCancellable, err)
+		}
+		s.logger.Error("failed to cancel deposit checkout",
+			"transaction_id", transactionID,
+			"error", err,
+		)
+		return nil, fmt.Errorf("failed to cancel checkout: %w", err)
+	}
+
+	err = s.uow.Do(ctx, func(uow repository.UnitOfWork) error {
+		// The payment may have been reported while the checkout was being
+		// canceled.
+		if _, err := s.getCancellableDeposit(ctx, uow, userID, transactionID); err != nil {
+			return err
+		}
+		txRepo, err := common.GetTransactionRepository(uow, s.logger)
+		if err != nil {
+			return err
+		}
+		canceled := string(account.TransactionStatusCanceled)
+		return txRepo.Update(ctx, transactionID, dto.TransactionUpdate{Status: &canceled})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	amount, err := money.New(deposit.Amount, money.Code(deposit.Currency))
+	if err != nil {
+		s.logger.Error("invalid amount of canceled deposit",
+			"transaction_id", transactionID,
+			"error", err,
+		)
+	} else if err := s.bus.Emit(ctx, events.NewDepositCanceled(
+		userID,
+		deposit.AccountID,
+		transactionID,
+		amount,
+	)); err != nil {
+		s.logger.Error("failed to emit DepositCanceled event", "error", err)
+	}
+
+	txRepo, err := common.GetTransactionRepository(s.uow, s.logger)
+	if err != nil {
+		return nil, err
+	}
+	return txRepo.Get(ctx, transactionID)
+}
+
+// getCancellableDeposit returns the user's deposit transactionID, or
+// ErrDepositNotCancellable if it can no longer be canceled.
+func (s *Service) getCancellableDeposit(
+	ctx context.Context,
+	uow repository.UnitOfWork,
+	userID, transactionID uuid.UUID,
+) (*dto.TransactionRead, error) {
+	txRepo, err := common.GetTransactionRepository(uow, s.logger)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := txRepo.Get(ctx, transactionID)
+	if err != nil {
+		return nil, err
+	}
+	if tx.UserID != userID || tx.MoneySource != moneySourceDeposit {
+		return nil, account.ErrTransactionNotFound
+	}
+	if !isCancellableDeposit(tx) {
+		return nil, fmt.Errorf("%w: deposit is %s", account.ErrDepositNotCancellable, tx.Status)
+	}
+	return tx, nil
+}
+
+// isCancellableDeposit reports whether tx is a deposit that has been
+// requested but not paid yet.
+func isCancellableDeposit(tx *dto.TransactionRead) bool {
+	switch account.TransactionStatus(tx.Status) {
+	case account.TransactionStatusCreated, account.TransactionStatusPending:
+		return tx.MoneySource == moneySourceDeposit
+	}
+	return false
+}
@@ -0,0 +1,164 @@
+package account_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"testing"
+
+	"github.com/amirasaad/fintech/infra/eventbus"
+	"github.com/amirasaad/fintech/internal/fixtures/mocks"
+	accountdomain "github.com/amirasaad/fintech/pkg/domain/account"
+	"github.com/amirasaad/fintech/pkg/domain/events"
+	"github.com/amirasaad/fintech/pkg/dto"
+	"github.com/amirasaad/fintech/pkg/provider/payment"
+	"github.com/amirasaad/fintech/pkg/repository"
+	repoaccount "github.com/amirasaad/fintech/pkg/repository/account"
+	"github.com/amirasaad/fintech/pkg/repository/transaction"
+	accountsvc "github.com/amirasaad/fintech/pkg/service/account"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeCheckoutCanceler struct {
+	calls []uuid.UUID
+	err   error
+}
+
+func (f *fakeCheckoutCanceler) CancelCheckout(_ context.Context, transactionID uuid.UUID) error {
+	f.calls = append(f.calls, transactionID)
+	return f.err
+}
+
+func TestCancelDeposit(t *testing.T) {
+	userID, accountID := uuid.New(), uuid.New()
+	ctx := context.Background()
+
+	// setup returns a service whose repository holds one deposit in status.
+	setup := func(t *testing.T, status string, canceler payment.CheckoutCanceler) (
+		*accountsvc.Service,
+		*dto.TransactionRead,
+		*[]events.Event,
+	) {
+		deposit := &dto.TransactionRead{
+			ID:          uuid.New(),
+			UserID:      userID,
+			AccountID:   accountID,
+			Amount:      25,
+			Currency:    "USD",
+			Status:      status,
+			MoneySource: "deposit",
+		}
+		uow := mocks.NewUnitOfWork(t)
+		txRepo := mocks.NewTransactionRepository(t)
+		accRepo := mocks.NewAccountRepository(t)
+		uow.EXPECT().Do(mock.Anything, mock.Anything).RunAndReturn(
+			func(ctx context.Context, fn func(repository.UnitOfWork) error) error {
+				return fn(uow)
+			},
+		).Maybe()
+		uow.EXPECT().GetRepository(mock.Anything).RunAndReturn(
+			func(repoType any) (any, error) {
+				switch repoType.(type) {
+				case *transaction.Repository:
+					return txRepo, nil
+				case *repoaccount.Repository:
+					return accRepo, nil
+				}
+				return nil, errors.New("unexpected repository type")
+			},
+		).Maybe()
+		txRepo.EXPECT().Get(mock.Anything, deposit.ID).RunAndReturn(
+			func(context.Context, uuid.UUID) (*dto.TransactionRead, error) {
+				tx := *deposit
+				return &tx, nil
+			},
+		).Maybe()
+		txRepo.EXPECT().Update(mock.Anything, deposit.ID, mock.Anything).RunAndReturn(
+			func(_ context.Context, _ uuid.UUID, u dto.TransactionUpdate) error {
+				deposit.Status = *u.Status
+				return nil
+			},
+		).Maybe()
+		txRepo.EXPECT().ListByAccount(mock.Anything, accountID).RunAndReturn(
+			func(context.Context, uuid.UUID) ([]*dto.TransactionRead, error) {
+				return []*dto.TransactionRead{deposit}, nil
+			},
+		).Maybe()
+		accRepo.EXPECT().Get(mock.Anything, accountID).Return(&dto.AccountRead{
+			ID: accountID, UserID: userID, Currency: "USD",
+		}, nil).Maybe()
+
+		bus := eventbus.NewWithMemory(slog.Default())
+		var emitted []events.Event
+		bus.Register(events.EventTypeDepositCanceled, func(_ context.Context, e events.Event) error {
+			emitted = append(emitted, e)
+			return nil
+		})
+		var opts []accountsvc.Option
+		if canceler != nil {
+			opts = append(opts, accountsvc.WithCheckoutCanceler(canceler))
+		}
+		return accountsvc.New(bus, uow, slog.Default(), nil, opts...), deposit, &emitted
+	}
+
+	t.Run("cancels an unpaid deposit", func(t *testing.T) {
+		canceler := &fakeCheckoutCanceler{}
+		svc, deposit, emitted := setup(t, "created", canceler)
+
+		pending, err := svc.ListPendingDeposits(ctx, userID, accountID)
+		require.NoError(t, err)
+		require.Len(t, pending, 1)
+
+		tx, err := svc.CancelDeposit(ctx, userID, deposit.ID)
+		require.NoError(t, err)
+		assert.Equal(t, string(accountdomain.TransactionStatusCanceled), tx.Status)
+		assert.Equal(t, []uuid.UUID{deposit.ID}, canceler.calls)
+		require.Len(t, *emitted, 1)
+		dc := (*emitted)[0].(*events.DepositCanceled)
+		assert.Equal(t, deposit.ID, dc.TransactionID)
+		assert.Equal(t, "25.00 USD", dc.Amount.String())
+
+		pending, err = svc.ListPendingDeposits(ctx, userID, accountID)
+		require.NoError(t, err)
+		assert.Empty(t, pending)
+	})
+
+	for _, status := range []string{"processed", "completed", "failed", "canceled"} {
+		t.Run(fmt.Sprintf("rejects a %s deposit", status), func(t *testing.T) {
+			canceler := &fakeCheckoutCanceler{}
+			svc, deposit, emitted := setup(t, status, canceler)
+			_, err := svc.CancelDeposit(ctx, userID, deposit.ID)
+			require.ErrorIs(t, err, accountdomain.ErrDepositNotCancellable)
+			assert.Empty(t, canceler.calls)
+			assert.Equal(t, status, deposit.Status)
+			assert.Empty(t, *emitted)
+		})
+	}
+
+	t.Run("rejects a deposit paid before its checkout expired", func(t *testing.T) {
+		canceler := &fakeCheckoutCanceler{err: payment.ErrCheckoutNotCancellable}
+		svc, deposit, emitted := setup(t, "created", canceler)
+		_, err := svc.CancelDeposit(ctx, userID, deposit.ID)
+		require.ErrorIs(t, err, accountdomain.ErrDepositNotCancellable)
+		assert.Equal(t, "created", deposit.Status)
+		assert.Empty(t, *emitted)
+	})
+
+	t.Run("hides other users' deposits", func(t *testing.T) {
+		svc, deposit, _ := setup(t, "created", &fakeCheckoutCanceler{})
+		_, err := svc.CancelDeposit(ctx, uuid.New(), deposit.ID)
+		require.ErrorIs(t, err, accountdomain.ErrTransactionNotFound)
+		_, err = svc.ListPendingDeposits(ctx, uuid.New(), accountID)
+		require.ErrorIs(t, err, accountdomain.ErrAccountNotFound)
+	})
+
+	t.Run("requires a provider that supports canceling", func(t *testing.T) {
+		svc, deposit, _ := setup(t, "created", nil)
+		_, err := svc.CancelDeposit(ctx, userID, deposit.ID)
+		require.ErrorIs(t, err, payment.ErrCheckoutCancelNotSupported)
+	})
+}
@@ -0,0 +1,111 @@
+package account
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/amirasaad/fintech/pkg/domain/account"
+	"github.com/amirasaad/fintech/pkg/dto"
+	"github.com/amirasaad/fintech/pkg/handler/common"
+	"github.com/amirasaad/fintech/pkg/money"
+	"github.com/amirasaad/fintech/pkg/provider/payment"
+	"github.com/amirasaad/fintech/pkg/repository"
+	"github.com/google/uuid"
+)
+
+// WithCapturer enables capturing deposits authorized with manual capture
+// through the given payment provider.
+func WithCapturer(capturer payment.Capturer) Option {
+	return func(s *Service) { s.capturer = capturer }
+}
+
+// CaptureDeposit captures amount, in the deposit's currency, of a deposit
+// authorized with payment.CaptureManual; zero captures the full authorized
+// amount. The rest of the authorization is released.
+//
+// The deposit stays processed until the provider reports the capture, then it
+// is completed and the account credited with the captured amount.
+func (s *Service) CaptureDeposit(
+	ctx context.Context,
+	transactionID uuid.UUID,
+	amount float64,
+) (*dto.TransactionRead, error) {
+	if s.capturer == nil {
+		return nil, payment.ErrCaptureNotSupported
+	}
+
+	var (
+		deposit *dto.TransactionRead
+		capture *money.Money
+	)
+	err := s.uow.Do(ctx, func(uow repository.UnitOfWork) error {
+		txRepo, err := common.GetTransactionRepository(uow, s.logger)
+		if err != nil {
+			return err
+		}
+		deposit, err = txRepo.Get(ctx, transactionID)
+		if err != nil {
+			return err
+		}
+		if deposit.MoneySource != moneySourceDeposit {
+			return account.ErrTransactionNotFound
+		}
+		if deposit.Status != string(account.TransactionStatusProcessed) ||
+			deposit.PaymentID == nil || *deposit.PaymentID == "" {
+			return fmt.Errorf("%w: deposit is %s", account.ErrDepositNotCapturable, deposit.Status)
+		}
+
+		authorized, err := money.New(deposit.Amount, money.Code(deposit.Currency))
+		if err != nil {
+			return err
+		}
+		if amount == 0 {
+			capture = authorized
+			return nil
+		}
+		capture, err = money.New(amount, money.Code(deposit.Currency))
+		if err != nil {
+			return fmt.Errorf("invalid amount: %w", err)
+		}
+		if !capture.IsPositive() {
+			return account.ErrTransactionAmountMustBePositive
+		}
+		if exceeds, err := capture.GreaterThan(authorized); err != nil {
+			return err
+		} else if exceeds {
+			return fmt.Errorf(
+				"%w: %s requested, %s authorized",
+				account.ErrCaptureExceedsAuthorized,
+				capture,
+				authorized,
+			)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.capturer.CapturePayment(
+		ctx,
+		*deposit.PaymentID,
+		int64(capture.Amount()),
+	); err != nil {
+		if errors.Is(err, payment.ErrPaymentNotCapturable) {
+			return nil, fmt.Errorf("%w: %w", account.ErrDepositNotCapturable, err)
+		}
+		s.logger.Error("failed to capture deposit",
+			"transaction_id", transactionID,
+			"payment_id", *deposit.PaymentID,
+			"error", err,
+		)
+		return nil, fmt.Errorf("failed to capture deposit: %w", err)
+	}
+	s.logger.Info("Captured deposit",
+		"transaction_id", transactionID,
+		"payment_id", *deposit.PaymentID,
+		"amount", capture.String(),
+	)
+	return deposit, nil
+}
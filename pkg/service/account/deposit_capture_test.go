@@ -0,0 +1,133 @@
+package account_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"testing"
+
+	"github.com/amirasaad/fintech/infra/eventbus"
+	"github.com/amirasaad/fintech/internal/fixtures/mocks"
+	accountdomain "github.com/amirasaad/fintech/pkg/domain/account"
+	"github.com/amirasaad/fintech/pkg/dto"
+	"github.com/amirasaad/fintech/pkg/provider/payment"
+	"github.com/amirasaad/fintech/pkg/repository"
+	"github.com/amirasaad/fintech/pkg/repository/transaction"
+	accountsvc "github.com/amirasaad/fintech/pkg/service/account"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type capture struct {
+	paymentID string
+	amount    int64
+}
+
+type fakeCapturer struct {
+	calls []capture
+	err   error
+}
+
+func (f *fakeCapturer) CapturePayment(_ context.Context, paymentID string, amount int64) error {
+	f.calls = append(f.calls, capture{paymentID, amount})
+	return f.err
+}
+
+func TestCaptureDeposit(t *testing.T) {
+	ctx := context.Background()
+
+	// setup returns a service whose repository holds one 25.00 USD deposit
+	// in status.
+	setup := func(t *testing.T, status string, capturer payment.Capturer) (
+		*accountsvc.Service,
+		*dto.TransactionRead,
+	) {
+		paymentID := "pi_1"
+		deposit := &dto.TransactionRead{
+			ID:          uuid.New(),
+			UserID:      uuid.New(),
+			AccountID:   uuid.New(),
+			Amount:      25,
+			Currency:    "USD",
+			Status:      status,
+			MoneySource: "deposit",
+			PaymentID:   &paymentID,
+		}
+		uow := mocks.NewUnitOfWork(t)
+		txRepo := mocks.NewTransactionRepository(t)
+		uow.EXPECT().Do(mock.Anything, mock.Anything).RunAndReturn(
+			func(ctx context.Context, fn func(repository.UnitOfWork) error) error {
+				return fn(uow)
+			},
+		).Maybe()
+		uow.EXPECT().GetRepository(mock.Anything).RunAndReturn(
+			func(repoType any) (any, error) {
+				if _, ok := repoType.(*transaction.Repository); ok {
+					return txRepo, nil
+				}
+				return nil, errors.New("unexpected repository type")
+			},
+		).Maybe()
+		txRepo.EXPECT().Get(mock.Anything, deposit.ID).Return(deposit, nil).Maybe()
+
+		var opts []accountsvc.Option
+		if capturer != nil {
+			opts = append(opts, accountsvc.WithCapturer(capturer))
+		}
+		bus := eventbus.NewWithMemory(slog.Default())
+		return accountsvc.New(bus, uow, slog.Default(), nil, opts...), deposit
+	}
+
+	t.Run("captures the full authorization by default", func(t *testing.T) {
+		capturer := &fakeCapturer{}
+		svc, deposit := setup(t, "processed", capturer)
+		tx, err := svc.CaptureDeposit(ctx, deposit.ID, 0)
+		require.NoError(t, err)
+		assert.Equal(t, deposit.ID, tx.ID)
+		assert.Equal(t, []capture{{"pi_1", 2500}}, capturer.calls)
+	})
+
+	t.Run("captures part of the authorization", func(t *testing.T) {
+		capturer := &fakeCapturer{}
+		svc, deposit := setup(t, "processed", capturer)
+		_, err := svc.CaptureDeposit(ctx, deposit.ID, 10.5)
+		require.NoError(t, err)
+		assert.Equal(t, []capture{{"pi_1", 1050}}, capturer.calls)
+	})
+
+	t.Run("rejects more than authorized", func(t *testing.T) {
+		capturer := &fakeCapturer{}
+		svc, deposit := setup(t, "processed", capturer)
+		_, err := svc.CaptureDeposit(ctx, deposit.ID, 25.01)
+		require.ErrorIs(t, err, accountdomain.ErrCaptureExceedsAuthorized)
+		_, err = svc.CaptureDeposit(ctx, deposit.ID, -1)
+		require.ErrorIs(t, err, accountdomain.ErrTransactionAmountMustBePositive)
+		assert.Empty(t, capturer.calls)
+	})
+
+	for _, status := range []string{"created", "pending", "completed", "failed", "canceled"} {
+		t.Run(fmt.Sprintf("rejects a %s deposit", status), func(t *testing.T) {
+			capturer := &fakeCapturer{}
+			svc, deposit := setup(t, status, capturer)
+			_, err := svc.CaptureDeposit(ctx, deposit.ID, 0)
+			require.ErrorIs(t, err, accountdomain.ErrDepositNotCapturable)
+			assert.Empty(t, capturer.calls)
+		})
+	}
+
+	t.Run("rejects a payment not awaiting capture", func(t *testing.T) {
+		capturer := &fakeCapturer{err: payment.ErrPaymentNotCapturable}
+		svc, deposit := setup(t, "processed", capturer)
+		_, err := svc.CaptureDeposit(ctx, deposit.ID, 0)
+		require.ErrorIs(t, err, accountdomain.ErrDepositNotCapturable)
+	})
+
+	t.Run("requires a provider that supports capturing", func(t *testing.T) {
+		svc, deposit := setup(t, "processed", nil)
+		_, err := svc.CaptureDeposit(ctx, deposit.ID, 0)
+		require.ErrorIs(t, err, payment.ErrCaptureNotSupported)
+	})
+}
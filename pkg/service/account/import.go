@@ -0,0 +1,213 @@
+package account
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/amirasaad/fintech/pkg/domain"
+	"github.com/amirasaad/fintech/pkg/domain/account"
+	"github.com/amirasaad/fintech/pkg/dto"
+	"github.com/amirasaad/fintech/pkg/handler/common"
+	"github.com/amirasaad/fintech/pkg/money"
+	"github.com/amirasaad/fintech/pkg/repository"
+	"github.com/google/uuid"
+)
+
+// moneySourceImport is the money source of imported transactions. It keeps
+// them out of KYC limits, payout reconciliation and refunds, which only look
+// at deposits and withdrawals.
+const moneySourceImport = "import"
+
+// MaxTransactionImportSize caps the number of transactions in one import.
+const MaxTransactionImportSize = 1000
+
+// ImportTransactions seeds an account with historical transactions from
+// another system, e.g. when onboarding a customer. The transactions are
+// written as completed directly through the repositories and the balance is
+// recomputed; no events are emitted and no payment provider is called.
+//
+// Rows must be in the account currency and in chronological order, and each
+// needs a reference that is unique within the account. Rows whose reference
+// has already been imported are skipped, so a failed or repeated import can be
+// retried with the same batch. The import is all or nothing: if any row is
+// invalid, or would take the balance below the overdraft limit, nothing is
+// imported. Like SetOverdraftLimit it performs no ownership checks; callers
+// must restrict it to admins.
+func (s *Service) ImportTransactions(
+	ctx context.Context,
+	accountID uuid.UUID,
+	rows []dto.TransactionImport,
+) (*dto.TransactionImportResult, error) {
+	if err := validateImportRows(rows, time.Now()); err != nil {
+		return nil, err
+	}
+
+	result := &dto.TransactionImportResult{Imported: []*dto.TransactionRead{}}
+	err := s.uow.Do(ctx, func(uow repository.UnitOfWork) error {
+		accRepo, err := common.GetAccountRepository(uow, s.logger)
+		if err != nil {
+			return err
+		}
+		txRepo, err := common.GetTransactionRepository(uow, s.logger)
+		if err != nil {
+			return err
+		}
+
+		acc, err := accRepo.GetForUpdate(ctx, accountID)
+		if err != nil {
+			return err
+		}
+		if !isActive(acc) {
+			return account.ErrAccountNotActive
+		}
+		balance, err := money.New(acc.Balance, money.Code(acc.Currency))
+		if err != nil {
+			return err
+		}
+		overdraft, err := money.New(acc.OverdraftLimit, money.Code(acc.Currency))
+		if err != nil {
+			return err
+		}
+
+		existing, err := txRepo.ListByAccount(ctx, accountID)
+		if err != nil {
+			return fmt.Errorf("failed to list account transactions: %w", err)
+		}
+		imported := make(map[string]bool)
+		for _, tx := range existing {
+			if tx.ExternalReference != "" {
+				imported[tx.ExternalReference] = true
+			}
+		}
+
+		for i, row := range rows {
+			if imported[row.Reference] {
+				result.Skipped = append(result.Skipped, row.Reference)
+				continue
+			}
+			amount, memo, err := importAmount(row, acc.Currency)
+			if err != nil {
+				return fmt.Errorf("row %d (%s): %w", i+1, row.Reference, err)
+			}
+			if balance, err = balance.Add(amount); err != nil {
+				return err
+			}
+			if balance.Amount() < -overdraft.Amount() {
+				return fmt.Errorf(
+					"row %d (%s): %w: balance would be %s",
+					i+1, row.Reference, account.ErrInsufficientFunds, balance,
+				)
+			}
+
+			id := uuid.New()
+			if err := txRepo.Create(ctx, dto.TransactionCreate{
+				ID:                id,
+				UserID:            acc.UserID,
+				AccountID:         acc.ID,
+				Amount:            amount.Amount(),
+				Balance:           balance.Amount(),
+				Status:            string(account.TransactionStatusCompleted),
+				Currency:          acc.Currency,
+				MoneySource:       moneySourceImport,
+				Description:       memo,
+				ExternalReference: row.Reference,
+				CreatedAt:         row.Timestamp.UTC(),
+			}); err != nil {
+				return fmt.Errorf("failed to import row %d (%s): %w", i+1, row.Reference, err)
+			}
+			tx, err := txRepo.Get(ctx, id)
+			if err != nil {
+				return err
+			}
+			result.Imported = append(result.Imported, tx)
+		}
+
+		if len(result.Imported) > 0 {
+			if err := updateBalance(ctx, accRepo, acc.ID, balance); err != nil {
+				return fmt.Errorf("failed to update account balance: %w", err)
+			}
+		}
+		result.Balance = balance.AmountFloat()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Imported) > 0 {
+		s.invalidateTransactions(ctx, accountID)
+	}
+
+	s.logger.Info("Transactions imported",
+		"account_id", accountID,
+		"imported", len(result.Imported),
+		"skipped", len(result.Skipped),
+		"balance", result.Balance,
+	)
+	return result, nil
+}
+
+// validateImportRows checks what can be checked without the account: the
+// batch size, that references are present and unique, and that timestamps
+// are set, not after now and in chronological order.
+func validateImportRows(rows []dto.TransactionImport, now time.Time) error {
+	if len(rows) == 0 || len(rows) > MaxTransactionImportSize {
+		return fmt.Errorf(
+			"%w: an import takes 1 to %d transactions, got %d",
+			domain.ErrValidation, MaxTransactionImportSize, len(rows),
+		)
+	}
+	seen := make(map[string]bool, len(rows))
+	for i, row := range rows {
+		switch {
+		case row.Reference == "":
+			return fmt.Errorf("%w: row %d: reference is required", domain.ErrValidation, i+1)
+		case seen[row.Reference]:
+			return fmt.Errorf(
+				"%w: row %d: duplicate reference %s", domain.ErrValidation, i+1, row.Reference,
+			)
+		case row.Timestamp.IsZero():
+			return fmt.Errorf("%w: row %d: timestamp is required", domain.ErrValidation, i+1)
+		case row.Timestamp.After(now):
+			return fmt.Errorf(
+				"%w: row %d: timestamp is in the future", domain.ErrValidation, i+1,
+			)
+		case i > 0 && row.Timestamp.Before(rows[i-1].Timestamp):
+			return fmt.Errorf(
+				"%w: row %d: transactions must be in chronological order",
+				domain.ErrValidation, i+1,
+			)
+		}
+		seen[row.Reference] = true
+	}
+	return nil
+}
+
+// importAmount returns the signed amount of an imported row, negative for
+// debits, and its normalized memo.
+func importAmount(row dto.TransactionImport, currency string) (*money.Money, string, error) {
+	if row.Currency != currency {
+		return nil, "", fmt.Errorf(
+			"%w: %s transaction in a %s account", account.ErrCurrencyMismatch, row.Currency, currency,
+		)
+	}
+	amount, err := money.New(row.Amount, money.Code(currency))
+	if err != nil {
+		return nil, "", err
+	}
+	if !amount.IsPositive() {
+		return nil, "", account.ErrTransactionAmountMustBePositive
+	}
+	memo, err := account.NormalizeDescription(row.Memo)
+	if err != nil {
+		return nil, "", err
+	}
+	switch row.Type {
+	case dto.TransactionImportDeposit, dto.TransactionImportTransferIn:
+		return amount, memo, nil
+	case dto.TransactionImportWithdraw, dto.TransactionImportTransferOut:
+		return amount.Negate(), memo, nil
+	default:
+		return nil, "", fmt.Errorf("%w: unknown transaction type %q", domain.ErrValidation, row.Type)
+	}
+}
@@ -0,0 +1,209 @@
+package account_test
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/amirasaad/fintech/internal/fixtures/mocks"
+	"github.com/amirasaad/fintech/pkg/domain"
+	accountdomain "github.com/amirasaad/fintech/pkg/domain/account"
+	"github.com/amirasaad/fintech/pkg/dto"
+	"github.com/amirasaad/fintech/pkg/repository"
+	"github.com/amirasaad/fintech/pkg/repository/transaction"
+	accountsvc "github.com/amirasaad/fintech/pkg/service/account"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// importFixture holds a USD account whose balance, in cents, and
+// transactions are kept up to date by the repository mocks.
+type importFixture struct {
+	svc       *accountsvc.Service
+	accountID uuid.UUID
+	balance   int64
+	txs       []dto.TransactionCreate
+}
+
+// newImportFixture builds the fixture. The bus has no expectations, so any
+// emitted event fails the test.
+func newImportFixture(t *testing.T) *importFixture {
+	f := &importFixture{accountID: uuid.New(), balance: 1000}
+
+	accountRepo := mocks.NewAccountRepository(t)
+	accountRepo.EXPECT().GetForUpdate(mock.Anything, f.accountID).RunAndReturn(
+		func(_ context.Context, id uuid.UUID) (*dto.AccountRead, error) {
+			return &dto.AccountRead{
+				ID:       id,
+				UserID:   uuid.New(),
+				Balance:  float64(f.balance) / 100,
+				Currency: "USD",
+				Status:   "active",
+			}, nil
+		},
+	).Maybe()
+	accountRepo.EXPECT().Update(mock.Anything, f.accountID, mock.Anything).RunAndReturn(
+		func(_ context.Context, _ uuid.UUID, update dto.AccountUpdate) error {
+			f.balance = *update.Balance
+			return nil
+		},
+	).Maybe()
+
+	txRepo := mocks.NewTransactionRepository(t)
+	txRepo.EXPECT().ListByAccount(mock.Anything, f.accountID).RunAndReturn(
+		func(_ context.Context, _ uuid.UUID) ([]*dto.TransactionRead, error) {
+			out := make([]*dto.TransactionRead, 0, len(f.txs))
+			for _, tx := range f.txs {
+				out = append(out, &dto.TransactionRead{
+					ID:                tx.ID,
+					ExternalReference: tx.ExternalReference,
+				})
+			}
+			return out, nil
+		},
+	).Maybe()
+	txRepo.EXPECT().Create(mock.Anything, mock.Anything).RunAndReturn(
+		func(_ context.Context, tx dto.TransactionCreate) error {
+			f.txs = append(f.txs, tx)
+			return nil
+		},
+	).Maybe()
+	txRepo.EXPECT().Get(mock.Anything, mock.Anything).RunAndReturn(
+		func(_ context.Context, id uuid.UUID) (*dto.TransactionRead, error) {
+			for _, tx := range f.txs {
+				if tx.ID == id {
+					return &dto.TransactionRead{
+						ID:                tx.ID,
+						AccountID:         tx.AccountID,
+						Amount:            float64(tx.Amount) / 100,
+						Balance:           float64(tx.Balance) / 100,
+						CreatedAt:         tx.CreatedAt,
+						ExternalReference: tx.ExternalReference,
+					}, nil
+				}
+			}
+			return nil, domain.ErrNotFound
+		},
+	).Maybe()
+
+	uow := mocks.NewUnitOfWork(t)
+	uow.EXPECT().Do(mock.Anything, mock.Anything).RunAndReturn(
+		func(ctx context.Context, fn func(repository.UnitOfWork) error) error {
+			return fn(uow)
+		},
+	).Maybe()
+	uow.EXPECT().GetRepository(mock.Anything).RunAndReturn(
+		func(repoType any) (any, error) {
+			if _, ok := repoType.(*transaction.Repository); ok {
+				return txRepo, nil
+			}
+			return accountRepo, nil
+		},
+	).Maybe()
+
+	f.svc = accountsvc.New(mocks.NewBus(t), uow, slog.Default(), nil)
+	return f
+}
+
+func importRows() []dto.TransactionImport {
+	start := time.Date(2025, time.January, 10, 9, 0, 0, 0, time.UTC)
+	return []dto.TransactionImport{
+		{Reference: "old-1", Type: dto.TransactionImportDeposit, Amount: 100,
+			Currency: "USD", Timestamp: start, Memo: "Opening deposit"},
+		{Reference: "old-2", Type: dto.TransactionImportWithdraw, Amount: 30,
+			Currency: "USD", Timestamp: start.Add(time.Hour)},
+		{Reference: "old-3", Type: dto.TransactionImportTransferIn, Amount: 5.5,
+			Currency: "USD", Timestamp: start.Add(time.Hour)},
+	}
+}
+
+func TestImportTransactions(t *testing.T) {
+	f := newImportFixture(t)
+	rows := importRows()
+
+	result, err := f.svc.ImportTransactions(context.Background(), f.accountID, rows)
+	require.NoError(t, err)
+	require.Len(t, result.Imported, 3)
+	assert.Empty(t, result.Skipped)
+	// 10.00 + 100.00 - 30.00 + 5.50
+	assert.Equal(t, int64(8550), f.balance)
+	assert.InDelta(t, 85.5, result.Balance, 0.001)
+
+	assert.Equal(t, int64(-3000), f.txs[1].Amount)
+	assert.Equal(t, int64(8000), f.txs[1].Balance)
+	assert.Equal(t, "old-2", result.Imported[1].ExternalReference)
+	assert.Equal(t, rows[1].Timestamp, result.Imported[1].CreatedAt)
+	assert.Equal(t, "Opening deposit", f.txs[0].Description)
+	for _, tx := range f.txs {
+		assert.Equal(t, string(accountdomain.TransactionStatusCompleted), tx.Status)
+	}
+
+	// Re-importing skips the rows already imported and adds the new one.
+	rows = append(rows, dto.TransactionImport{
+		Reference: "old-4", Type: dto.TransactionImportTransferOut, Amount: 0.5,
+		Currency: "USD", Timestamp: rows[2].Timestamp.Add(time.Minute),
+	})
+	result, err = f.svc.ImportTransactions(context.Background(), f.accountID, rows)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"old-1", "old-2", "old-3"}, result.Skipped)
+	require.Len(t, result.Imported, 1)
+	assert.Equal(t, int64(8500), f.balance)
+	assert.Len(t, f.txs, 4)
+}
+
+func TestImportTransactions_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(rows []dto.TransactionImport)
+		wantErr error
+	}{
+		{
+			name: "out of order",
+			modify: func(rows []dto.TransactionImport) {
+				rows[2].Timestamp = rows[0].Timestamp.Add(-time.Hour)
+			},
+			wantErr: domain.ErrValidation,
+		},
+		{
+			name: "in the future",
+			modify: func(rows []dto.TransactionImport) {
+				rows[2].Timestamp = time.Now().Add(time.Hour)
+			},
+			wantErr: domain.ErrValidation,
+		},
+		{
+			name:    "duplicate reference",
+			modify:  func(rows []dto.TransactionImport) { rows[2].Reference = rows[0].Reference },
+			wantErr: domain.ErrValidation,
+		},
+		{
+			name:    "unknown type",
+			modify:  func(rows []dto.TransactionImport) { rows[2].Type = "fee" },
+			wantErr: domain.ErrValidation,
+		},
+		{
+			name:    "currency mismatch",
+			modify:  func(rows []dto.TransactionImport) { rows[2].Currency = "EUR" },
+			wantErr: accountdomain.ErrCurrencyMismatch,
+		},
+		{
+			name:    "overdrawn",
+			modify:  func(rows []dto.TransactionImport) { rows[1].Amount = 200 },
+			wantErr: accountdomain.ErrInsufficientFunds,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newImportFixture(t)
+			rows := importRows()
+			tt.modify(rows)
+			_, err := f.svc.ImportTransactions(context.Background(), f.accountID, rows)
+			require.ErrorIs(t, err, tt.wantErr)
+			// The balance is only updated once every row has been imported.
+			assert.Equal(t, int64(1000), f.balance)
+		})
+	}
+}
@@ -0,0 +1,294 @@
+package account
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/amirasaad/fintech/pkg/config"
+	"github.com/amirasaad/fintech/pkg/domain/account"
+	"github.com/amirasaad/fintech/pkg/dto"
+	"github.com/amirasaad/fintech/pkg/handler/common"
+	"github.com/amirasaad/fintech/pkg/money"
+	"github.com/amirasaad/fintech/pkg/repository"
+	"github.com/google/uuid"
+)
+
+const (
+	moneySourceInterest = "interest"
+
+	defaultInterestInterval  = time.Hour
+	defaultInterestBatchSize = 100
+)
+
+// WithInterest reports the APY in cfg on savings accounts. Without it no
+// account reports an APY.
+func WithInterest(cfg *config.Interest) Option {
+	return func(s *Service) { s.interest = cfg }
+}
+
+// interestAPY returns the APY an account of the given type earns.
+func (s *Service) interestAPY(accountType string) float64 {
+	if s.interest == nil || !s.interest.Enabled ||
+		accountType != account.TypeSavings.String() {
+		return 0
+	}
+	return s.interest.SavingsAPY
+}
+
+// InterestAccruer credits savings accounts with a day's interest once per
+// UTC day.
+//
+// Interest compounds daily at the rate that yields the configured APY over
+// 365 days, and is rounded down to the smallest currency unit. Each credit
+// is a completed "interest" transaction whose external reference names the
+// day and whose description records the APY used; the account's
+// interest_accrued_on is set in the same unit of work, so a restart never
+// accrues a day twice. Accounts with a zero or negative balance earn nothing
+// but are still marked for the day. Days the accruer did not run are not
+// backfilled.
+type InterestAccruer struct {
+	uow         repository.UnitOfWork
+	logger      *slog.Logger
+	apy         float64
+	interval    time.Duration
+	batchSize   int
+	now         func() time.Time
+	txCache     TransactionCache
+	maintenance MaintenanceChecker
+}
+
+// NewInterestAccruer creates a new InterestAccruer. A nil config accrues no
+// interest.
+func NewInterestAccruer(
+	uow repository.UnitOfWork,
+	logger *slog.Logger,
+	cfg *config.Interest,
+) *InterestAccruer {
+	a := &InterestAccruer{
+		uow:       uow,
+		logger:    logger,
+		interval:  defaultInterestInterval,
+		batchSize: defaultInterestBatchSize,
+		now:       time.Now,
+	}
+	if cfg != nil {
+		a.apy = cfg.SavingsAPY
+		if cfg.Interval > 0 {
+			a.interval = cfg.Interval
+		}
+		if cfg.BatchSize > 0 {
+			a.batchSize = cfg.BatchSize
+		}
+	}
+	return a
+}
+
+// WithTransactionCache invalidates the cached transactions of the accounts
+// the accruer credits.
+func (a *InterestAccruer) WithTransactionCache(cache TransactionCache) *InterestAccruer {
+	a.txCache = cache
+	return a
+}
+
+// WithMaintenance pauses accrual while maintenance mode is on. The day's
+// interest is credited on the first run after it; like any day the accruer
+// does not run, a day spent entirely in maintenance is not backfilled.
+func (a *InterestAccruer) WithMaintenance(checker MaintenanceChecker) *InterestAccruer {
+	a.maintenance = checker
+	return a
+}
+
+// Run accrues the day's interest every interval until ctx is canceled.
+func (a *InterestAccruer) Run(ctx context.Context) {
+	ticker := time.NewTicker(a.interval)
+	defer ticker.Stop()
+	a.logger.Info("Interest accruer started", "interval", a.interval, "savings_apy", a.apy)
+	for {
+		if n, err := a.AccrueDue(ctx); err != nil {
+			a.logger.Error("Failed to accrue interest", "error", err)
+		} else if n > 0 {
+			a.logger.Info("Accrued interest", "count", n)
+		}
+		select {
+		case <-ctx.Done():
+			a.logger.Info("Interest accruer stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// AccrueDue accrues today's interest on every savings account that has not
+// accrued it yet and returns how many accounts were credited. An account
+// that fails is logged and retried on the next run. While maintenance mode is
+// on it credits none.
+func (a *InterestAccruer) AccrueDue(ctx context.Context) (int, error) {
+	if a.apy <= 0 {
+		return 0, nil
+	}
+	if a.maintenance != nil {
+		if err := a.maintenance.CheckWrites(ctx); err != nil {
+			a.logger.Info("Skipping interest accrual during maintenance", "reason", err)
+			return 0, nil
+		}
+	}
+	now := a.now().UTC()
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	credited := 0
+	for {
+		var due []*dto.AccountRead
+		if err := a.uow.Do(ctx, func(uow repository.UnitOfWork) error {
+			acctRepo, err := getAccountRepository(uow)
+			if err != nil {
+				return err
+			}
+			due, err = acctRepo.ListInterestDue(
+				ctx, account.TypeSavings.String(), day, a.batchSize,
+			)
+			return err
+		}); err != nil {
+			return credited, fmt.Errorf("failed to list accounts due interest: %w", err)
+		}
+
+		failed := false
+		for _, acc := range due {
+			ok, err := a.accrue(ctx, acc.ID, day)
+			if err != nil {
+				a.logger.Error("Failed to accrue interest",
+					"account_id", acc.ID,
+					"day", day.Format(time.DateOnly),
+					"error", err,
+				)
+				failed = true
+				continue
+			}
+			if ok {
+				credited++
+			}
+		}
+		// Stop at the last batch, or if an account failed, which would be
+		// listed again.
+		if len(due) < a.batchSize || failed {
+			return credited, nil
+		}
+	}
+}
+
+// accrue accrues the account's interest for day and reports whether any was
+// credited.
+func (a *InterestAccruer) accrue(
+	ctx context.Context,
+	accountID uuid.UUID,
+	day time.Time,
+) (bool, error) {
+	credited := false
+	err := a.uow.Do(ctx, func(uow repository.UnitOfWork) error {
+		credited = false
+		acctRepo, err := getAccountRepository(uow)
+		if err != nil {
+			return err
+		}
+		acc, err := acctRepo.GetForUpdate(ctx, accountID)
+		if err != nil {
+			return fmt.Errorf("failed to get account: %w", err)
+		}
+		if acc.InterestAccruedOn != nil && !acc.InterestAccruedOn.Before(day) {
+			return nil
+		}
+
+		update := dto.AccountUpdate{InterestAccruedOn: &day}
+		interest, err := a.dailyInterest(acc)
+		if err != nil {
+			return err
+		}
+		if interest != nil {
+			balance, err := acc.BalanceMoney.Add(interest)
+			if err != nil {
+				return err
+			}
+			txRepo, err := common.GetTransactionRepository(uow, a.logger)
+			if err != nil {
+				return err
+			}
+			if err := txRepo.Create(ctx, dto.TransactionCreate{
+				ID:          uuid.New(),
+				UserID:      acc.UserID,
+				AccountID:   acc.ID,
+				Amount:      interest.Amount(),
+				Balance:     balance.Amount(),
+				Status:      string(account.TransactionStatusCompleted),
+				Currency:    acc.Currency,
+				MoneySource: moneySourceInterest,
+				Description: fmt.Sprintf(
+					"Interest for %s at %s%% APY",
+					day.Format(time.DateOnly), formatAPY(a.apy),
+				),
+				ExternalReference: "interest-" + day.Format(time.DateOnly),
+				CreatedAt:         a.now().UTC(),
+			}); err != nil {
+				return fmt.Errorf("failed to record interest: %w", err)
+			}
+			newBalance := balance.Amount()
+			accrued := interest.Amount()
+			if acc.InterestAccrued != nil {
+				accrued += acc.InterestAccrued.Amount()
+			}
+			update.Balance = &newBalance
+			update.InterestAccrued = &accrued
+			credited = true
+		}
+		if err := acctRepo.Update(ctx, acc.ID, update); err != nil {
+			return fmt.Errorf("failed to update account: %w", err)
+		}
+		return nil
+	})
+	if credited && a.txCache != nil {
+		if err := a.txCache.Invalidate(ctx, accountID); err != nil {
+			a.logger.Warn(
+				"failed to invalidate cached transactions",
+				"account_id", accountID,
+				"error", err,
+			)
+		}
+	}
+	return credited, err
+}
+
+// dailyInterest returns the interest the account earns for one day, or nil
+// if it earns none.
+func (a *InterestAccruer) dailyInterest(acc *dto.AccountRead) (*money.Money, error) {
+	balance := acc.BalanceMoney
+	if balance == nil {
+		var err error
+		if balance, err = money.New(acc.Balance, money.Code(acc.Currency)); err != nil {
+			return nil, err
+		}
+		acc.BalanceMoney = balance
+	}
+	if !balance.IsPositive() {
+		return nil, nil
+	}
+	interest, err := balance.Convert(dailyRate(a.apy), balance.Currency(), money.RoundFloor)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute interest: %w", err)
+	}
+	if !interest.IsPositive() {
+		return nil, nil
+	}
+	return interest, nil
+}
+
+// dailyRate returns the daily rate that compounds to apy over 365 days.
+func dailyRate(apy float64) float64 {
+	return math.Expm1(math.Log1p(apy) / 365)
+}
+
+// formatAPY formats apy as a percentage to at most four decimal places,
+// without trailing zeros, e.g. 2.5 for 0.025.
+func formatAPY(apy float64) string {
+	return strconv.FormatFloat(math.Round(apy*1e6)/1e4, 'f', -1, 64)
+}
@@ -0,0 +1,182 @@
+package account_test
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/amirasaad/fintech/internal/fixtures/mocks"
+	"github.com/amirasaad/fintech/pkg/config"
+	"github.com/amirasaad/fintech/pkg/dto"
+	"github.com/amirasaad/fintech/pkg/money"
+	"github.com/amirasaad/fintech/pkg/repository"
+	repoaccount "github.com/amirasaad/fintech/pkg/repository/account"
+	"github.com/amirasaad/fintech/pkg/repository/transaction"
+	accountsvc "github.com/amirasaad/fintech/pkg/service/account"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// interestFixture holds savings accounts, with balances and accrued interest
+// in cents, that the repository mocks keep up to date.
+type interestFixture struct {
+	accounts map[uuid.UUID]*dto.AccountRead
+	txs      []dto.TransactionCreate
+}
+
+func newInterestFixture(t *testing.T, balances ...int64) (*interestFixture, repository.UnitOfWork) {
+	f := &interestFixture{accounts: map[uuid.UUID]*dto.AccountRead{}}
+	for _, cents := range balances {
+		balance, err := money.NewFromSmallestUnit(cents, money.USD)
+		require.NoError(t, err)
+		id := uuid.New()
+		f.accounts[id] = &dto.AccountRead{
+			ID:           id,
+			UserID:       uuid.New(),
+			Balance:      balance.AmountFloat(),
+			BalanceMoney: balance,
+			Currency:     "USD",
+			Type:         "savings",
+		}
+	}
+
+	accountRepo := mocks.NewAccountRepository(t)
+	accountRepo.EXPECT().ListInterestDue(mock.Anything, "savings", mock.Anything, mock.Anything).
+		RunAndReturn(func(
+			_ context.Context, _ string, day time.Time, _ int,
+		) ([]*dto.AccountRead, error) {
+			var due []*dto.AccountRead
+			for _, acc := range f.accounts {
+				if acc.InterestAccruedOn == nil || acc.InterestAccruedOn.Before(day) {
+					due = append(due, acc)
+				}
+			}
+			return due, nil
+		}).Maybe()
+	accountRepo.EXPECT().GetForUpdate(mock.Anything, mock.Anything).RunAndReturn(
+		func(_ context.Context, id uuid.UUID) (*dto.AccountRead, error) {
+			acc := *f.accounts[id]
+			return &acc, nil
+		},
+	).Maybe()
+	accountRepo.EXPECT().Update(mock.Anything, mock.Anything, mock.Anything).RunAndReturn(
+		func(_ context.Context, id uuid.UUID, update dto.AccountUpdate) error {
+			acc := f.accounts[id]
+			if update.Balance != nil {
+				acc.BalanceMoney, _ = money.NewFromSmallestUnit(*update.Balance, money.USD)
+				acc.Balance = acc.BalanceMoney.AmountFloat()
+			}
+			if update.InterestAccrued != nil {
+				acc.InterestAccrued, _ = money.NewFromSmallestUnit(*update.InterestAccrued, money.USD)
+			}
+			if update.InterestAccruedOn != nil {
+				acc.InterestAccruedOn = update.InterestAccruedOn
+			}
+			return nil
+		},
+	).Maybe()
+
+	txRepo := mocks.NewTransactionRepository(t)
+	txRepo.EXPECT().Create(mock.Anything, mock.Anything).RunAndReturn(
+		func(_ context.Context, tx dto.TransactionCreate) error {
+			f.txs = append(f.txs, tx)
+			return nil
+		},
+	).Maybe()
+
+	uow := mocks.NewUnitOfWork(t)
+	uow.EXPECT().Do(mock.Anything, mock.Anything).RunAndReturn(
+		func(ctx context.Context, fn func(repository.UnitOfWork) error) error {
+			return fn(uow)
+		},
+	).Maybe()
+	uow.EXPECT().GetRepository(mock.Anything).RunAndReturn(
+		func(repoType any) (any, error) {
+			switch repoType.(type) {
+			case *repoaccount.Repository:
+				return accountRepo, nil
+			case *transaction.Repository:
+				return txRepo, nil
+			}
+			return nil, nil
+		},
+	).Maybe()
+	return f, uow
+}
+
+func TestInterestAccruer_AccrueDue(t *testing.T) {
+	cfg := &config.Interest{Enabled: true, SavingsAPY: 0.05}
+
+	t.Run("credits a day's interest once per day", func(t *testing.T) {
+		f, uow := newInterestFixture(t, 1_000_000) // $10,000.00
+		accruer := accountsvc.NewInterestAccruer(uow, slog.Default(), cfg)
+
+		n, err := accruer.AccrueDue(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, 1, n)
+
+		// 1.05^(1/365) - 1 = 0.000133681 per day, rounded down to the cent.
+		require.Len(t, f.txs, 1)
+		tx := f.txs[0]
+		today := time.Now().UTC().Format(time.DateOnly)
+		assert.Equal(t, int64(133), tx.Amount)
+		assert.Equal(t, int64(1_000_133), tx.Balance)
+		assert.Equal(t, "interest", tx.MoneySource)
+		assert.Equal(t, "completed", tx.Status)
+		assert.Equal(t, "interest-"+today, tx.ExternalReference)
+		assert.Equal(t, "Interest for "+today+" at 5% APY", tx.Description)
+
+		for _, acc := range f.accounts {
+			assert.Equal(t, int64(1_000_133), acc.BalanceMoney.Amount())
+			assert.Equal(t, int64(133), acc.InterestAccrued.Amount())
+			require.NotNil(t, acc.InterestAccruedOn)
+			assert.Equal(t, today, acc.InterestAccruedOn.Format(time.DateOnly))
+		}
+
+		// A second run the same day, e.g. after a restart, accrues nothing.
+		n, err = accruer.AccrueDue(context.Background())
+		require.NoError(t, err)
+		assert.Zero(t, n)
+		assert.Len(t, f.txs, 1)
+	})
+
+	t.Run("credits nothing to zero or negative balances", func(t *testing.T) {
+		f, uow := newInterestFixture(t, 0, -5_000)
+		accruer := accountsvc.NewInterestAccruer(uow, slog.Default(), cfg)
+
+		n, err := accruer.AccrueDue(context.Background())
+		require.NoError(t, err)
+		assert.Zero(t, n)
+		assert.Empty(t, f.txs)
+		for _, acc := range f.accounts {
+			assert.NotNil(t, acc.InterestAccruedOn, "the day is still marked")
+		}
+	})
+
+	t.Run("accrues nothing without an APY", func(t *testing.T) {
+		f, uow := newInterestFixture(t, 1_000_000)
+		accruer := accountsvc.NewInterestAccruer(uow, slog.Default(), nil)
+
+		n, err := accruer.AccrueDue(context.Background())
+		require.NoError(t, err)
+		assert.Zero(t, n)
+		assert.Empty(t, f.txs)
+	})
+
+	t.Run("accrues nothing during maintenance", func(t *testing.T) {
+		f, uow := newInterestFixture(t, 1_000_000)
+		accruer := accountsvc.NewInterestAccruer(uow, slog.Default(), cfg).
+			WithMaintenance(&fakeMaintenance{on: true})
+
+		n, err := accruer.AccrueDue(context.Background())
+		require.NoError(t, err)
+		assert.Zero(t, n)
+		assert.Empty(t, f.txs)
+		for _, acc := range f.accounts {
+			assert.Nil(t, acc.InterestAccruedOn, "the day is left for a later run")
+		}
+	})
+}
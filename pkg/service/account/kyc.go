@@ -0,0 +1,217 @@
+package account
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/amirasaad/fintech/pkg/config"
+	"github.com/amirasaad/fintech/pkg/domain/account"
+	"github.com/amirasaad/fintech/pkg/domain/events"
+	"github.com/amirasaad/fintech/pkg/domain/user"
+	"github.com/amirasaad/fintech/pkg/handler/common"
+	"github.com/amirasaad/fintech/pkg/money"
+	"github.com/amirasaad/fintech/pkg/provider/exchange"
+	"github.com/google/uuid"
+)
+
+// Money sources of the transactions counted towards KYC limits.
+const (
+	moneySourceDeposit  = "deposit"
+	moneySourceWithdraw = common.MoneySourceWithdraw
+)
+
+// CurrencyConverter converts amounts into the KYC limit currency.
+// The exchange service satisfies it.
+type CurrencyConverter interface {
+	Convert(
+		ctx context.Context,
+		amount *money.Money,
+		to money.Code,
+	) (*money.Money, *exchange.RateInfo, error)
+}
+
+// Option configures a Service.
+type Option func(*Service)
+
+// WithKycLimits enforces the per-tier deposit and withdrawal limits in cfg.
+// converter translates amounts in other currencies into cfg.Currency.
+// A nil or disabled config leaves limits unenforced.
+func WithKycLimits(cfg *config.Kyc, converter CurrencyConverter) Option {
+	return func(s *Service) {
+		if cfg == nil || !cfg.Enabled {
+			return
+		}
+		s.kyc = cfg
+		s.converter = converter
+	}
+}
+
+// enforceKycLimits returns a *account.KycLimitError if amount would take the
+// user over the per-transaction, daily or monthly limit of their KYC tier for
+// the given money source, and emits KycLimitReached so the user can be
+// prompted to upgrade. Failed transactions do not count towards the limits.
+func (s *Service) enforceKycLimits(
+	ctx context.Context,
+	userID, accountID uuid.UUID,
+	moneySource string,
+	amount *money.Money,
+) error {
+	if s.kyc == nil {
+		return nil
+	}
+	limitCurrency := money.Code(s.kyc.Currency)
+
+	userRepo, err := common.GetUserRepository(s.uow, s.logger)
+	if err != nil {
+		return err
+	}
+	u, err := userRepo.Get(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+	tier := u.KycTier
+	if !user.IsValidKycTier(tier) {
+		tier = user.KycTierUnverified
+	}
+	limits := s.kyc.Limits(tier)
+
+	attempted, err := s.toLimitCurrency(ctx, amount, limitCurrency)
+	if err != nil {
+		return err
+	}
+
+	var daily, monthly int64
+	if limits.Daily > 0 || limits.Monthly > 0 {
+		daily, monthly, err = s.usedKycAllowance(ctx, userID, moneySource, limitCurrency)
+		if err != nil {
+			return err
+		}
+	}
+
+	checks := []struct {
+		window string
+		limit  float64
+		used   int64
+	}{
+		{account.KycWindowPerTransaction, limits.PerTransaction, 0},
+		{account.KycWindowDaily, limits.Daily, daily},
+		{account.KycWindowMonthly, limits.Monthly, monthly},
+	}
+	for _, c := range checks {
+		if c.limit <= 0 {
+			continue
+		}
+		limit, err := money.New(c.limit, limitCurrency)
+		if err != nil {
+			return fmt.Errorf("invalid %s KYC limit: %w", c.window, err)
+		}
+		if c.used+attempted.Amount() <= limit.Amount() {
+			continue
+		}
+
+		s.logger.Warn("KYC limit exceeded",
+			"user_id", userID,
+			"tier", tier,
+			"window", c.window,
+			"limit", limit.String(),
+			"attempted", attempted.String(),
+			"money_source", moneySource,
+		)
+		evt := events.NewKycLimitReached(
+			userID, accountID, moneySource, tier, c.window, limit, amount,
+		)
+		if err := s.bus.Emit(ctx, evt); err != nil {
+			s.logger.Error("failed to emit KycLimitReached event", "error", err)
+		}
+		return &account.KycLimitError{Tier: tier, Window: c.window, Limit: limit}
+	}
+	return nil
+}
+
+// usedKycAllowance sums the user's transactions with the given money source
+// that were neither failed nor canceled for the current UTC day and month,
+// in the limit currency's smallest unit. Withdrawals count held ones too,
+// whether awaiting approval or approved, since their holds debited the
+// account.
+func (s *Service) usedKycAllowance(
+	ctx context.Context,
+	userID uuid.UUID,
+	moneySource string,
+	limitCurrency money.Code,
+) (daily, monthly int64, err error) {
+	txRepo, err := common.GetTransactionRepository(s.uow, s.logger)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	now := time.Now().UTC()
+	dayStart := now.Truncate(24 * time.Hour)
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	moneySources := []string{moneySource}
+	if moneySource == moneySourceWithdraw {
+		moneySources = common.WithdrawMoneySources()
+	}
+
+	// The repository sums per currency so each currency is converted once.
+	dailyByCurrency, err := txRepo.SumByUserMoneySource(ctx, userID, moneySources, dayStart)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to sum transactions: %w", err)
+	}
+	monthlyByCurrency, err := txRepo.SumByUserMoneySource(ctx, userID, moneySources, monthStart)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to sum transactions: %w", err)
+	}
+
+	if daily, err = s.sumInLimitCurrency(ctx, dailyByCurrency, limitCurrency); err != nil {
+		return 0, 0, err
+	}
+	if monthly, err = s.sumInLimitCurrency(ctx, monthlyByCurrency, limitCurrency); err != nil {
+		return 0, 0, err
+	}
+	return daily, monthly, nil
+}
+
+// sumInLimitCurrency converts sums in the smallest unit of their currency to
+// the limit currency and adds them up.
+func (s *Service) sumInLimitCurrency(
+	ctx context.Context,
+	byCurrency map[string]int64,
+	limitCurrency money.Code,
+) (int64, error) {
+	var total int64
+	for currency, sum := range byCurrency {
+		m, err := money.NewFromSmallestUnit(sum, money.Code(currency))
+		if err != nil {
+			return 0, err
+		}
+		converted, err := s.toLimitCurrency(ctx, m, limitCurrency)
+		if err != nil {
+			return 0, err
+		}
+		total += converted.Amount()
+	}
+	return total, nil
+}
+
+func (s *Service) toLimitCurrency(
+	ctx context.Context,
+	amount *money.Money,
+	limitCurrency money.Code,
+) (*money.Money, error) {
+	if amount.CurrencyCode() == limitCurrency {
+		return amount, nil
+	}
+	if s.converter == nil {
+		return nil, fmt.Errorf(
+			"cannot check KYC limits: no converter from %s to %s",
+			amount.CurrencyCode(), limitCurrency,
+		)
+	}
+	converted, _, err := s.converter.Convert(ctx, amount, limitCurrency)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert amount for KYC limits: %w", err)
+	}
+	return converted, nil
+}
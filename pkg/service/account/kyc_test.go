@@ -0,0 +1,213 @@
+package account_test
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/amirasaad/fintech/internal/fixtures/mocks"
+	"github.com/amirasaad/fintech/pkg/commands"
+	"github.com/amirasaad/fintech/pkg/config"
+	accountdomain "github.com/amirasaad/fintech/pkg/domain/account"
+	"github.com/amirasaad/fintech/pkg/domain/events"
+	"github.com/amirasaad/fintech/pkg/domain/user"
+	"github.com/amirasaad/fintech/pkg/dto"
+	"github.com/amirasaad/fintech/pkg/money"
+	"github.com/amirasaad/fintech/pkg/repository/transaction"
+	repouser "github.com/amirasaad/fintech/pkg/repository/user"
+	accountsvc "github.com/amirasaad/fintech/pkg/service/account"
+	"github.com/amirasaad/fintech/pkg/service/stripeconnect"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var testKycConfig = &config.Kyc{
+	Enabled:                  true,
+	Currency:                 "USD",
+	UnverifiedPerTransaction: 100,
+	UnverifiedDaily:          150,
+	UnverifiedMonthly:        500,
+}
+
+// setupKycUOW wires a UnitOfWork mock that resolves the user and transaction
+// repositories by type.
+func setupKycUOW(
+	t *testing.T,
+	userID uuid.UUID,
+	tier string,
+	txs []*dto.TransactionRead,
+) *mocks.UnitOfWork {
+	userRepo := mocks.NewUserRepository(t)
+	userRepo.EXPECT().Get(mock.Anything, userID).Return(
+		&dto.UserRead{ID: userID, KycTier: tier}, nil,
+	)
+	userRepo.EXPECT().GetStripeOnboardingStatus(mock.Anything, userID).
+		Return(true, nil).Maybe()
+	txRepo := mocks.NewTransactionRepository(t)
+	txRepo.EXPECT().
+		SumByUserMoneySource(mock.Anything, userID, mock.Anything, mock.Anything).
+		RunAndReturn(func(
+			_ context.Context,
+			_ uuid.UUID,
+			moneySources []string,
+			since time.Time,
+		) (map[string]int64, error) {
+			return sumTransactions(t, txs, moneySources, since), nil
+		}).
+		Maybe()
+
+	uow := mocks.NewUnitOfWork(t)
+	uow.EXPECT().GetRepository(mock.Anything).RunAndReturn(
+		func(repoType any) (any, error) {
+			if _, ok := repoType.(*repouser.Repository); ok {
+				return userRepo, nil
+			}
+			if _, ok := repoType.(*transaction.Repository); ok {
+				return txRepo, nil
+			}
+			return nil, nil
+		},
+	)
+	return uow
+}
+
+// sumTransactions sums txs the way SumByUserMoneySource does.
+func sumTransactions(
+	t *testing.T,
+	txs []*dto.TransactionRead,
+	moneySources []string,
+	since time.Time,
+) map[string]int64 {
+	sums := map[string]int64{}
+	for _, tx := range txs {
+		if !slices.Contains(moneySources, tx.MoneySource) ||
+			tx.Status == "failed" || tx.Status == "canceled" ||
+			tx.CreatedAt.Before(since) {
+			continue
+		}
+		amount, err := money.New(math.Abs(tx.Amount), money.Code(tx.Currency))
+		require.NoError(t, err)
+		sums[tx.Currency] += amount.Amount()
+	}
+	return sums
+}
+
+func expectKycLimitReached(bus *mocks.Bus, window string) {
+	bus.EXPECT().Emit(mock.Anything, mock.MatchedBy(func(e events.Event) bool {
+		evt, ok := e.(*events.KycLimitReached)
+		return ok && evt.Window == window && evt.Tier == user.KycTierUnverified
+	})).Return(nil).Once()
+}
+
+func TestDeposit_KycPerTransactionLimit(t *testing.T) {
+	userID := uuid.New()
+	bus := mocks.NewBus(t)
+	expectKycLimitReached(bus, accountdomain.KycWindowPerTransaction)
+	uow := setupKycUOW(t, userID, user.KycTierUnverified, nil)
+	svc := accountsvc.New(
+		bus, uow, slog.Default(), nil, accountsvc.WithKycLimits(testKycConfig, nil),
+	)
+
+	err := svc.Deposit(context.Background(), commands.Deposit{
+		UserID:    userID,
+		AccountID: uuid.New(),
+		Amount:    100.01,
+		Currency:  "USD",
+	})
+	require.ErrorIs(t, err, accountdomain.ErrKycLimitExceeded)
+	assert.Contains(t, err.Error(), user.KycTierUnverified)
+	assert.Contains(t, err.Error(), "100.00")
+}
+
+func TestWithdraw_KycDailyLimit(t *testing.T) {
+	userID := uuid.New()
+	now := time.Now().UTC()
+	txs := []*dto.TransactionRead{
+		{Amount: -100, Currency: "USD", MoneySource: "withdraw", Status: "completed",
+			CreatedAt: now},
+		// Failed withdrawals and deposits do not count towards the limit.
+		{Amount: -100, Currency: "USD", MoneySource: "withdraw", Status: "failed",
+			CreatedAt: now},
+		{Amount: 100, Currency: "USD", MoneySource: "deposit", Status: "completed",
+			CreatedAt: now},
+	}
+	bus := mocks.NewBus(t)
+	expectKycLimitReached(bus, accountdomain.KycWindowDaily)
+	uow := setupKycUOW(t, userID, user.KycTierUnverified, txs)
+	stripeConnectSvc := stripeconnect.New(uow, slog.Default(), &config.Stripe{})
+	svc := accountsvc.New(
+		bus, uow, slog.Default(), stripeConnectSvc,
+		accountsvc.WithKycLimits(testKycConfig, nil),
+	)
+
+	err := svc.Withdraw(context.Background(), commands.Withdraw{
+		UserID:    userID,
+		AccountID: uuid.New(),
+		Amount:    60,
+		Currency:  "USD",
+		ExternalTarget: &commands.ExternalTarget{
+			BankAccountNumber: "1234567890",
+		},
+	})
+	var limitErr *accountdomain.KycLimitError
+	require.ErrorAs(t, err, &limitErr)
+	assert.Equal(t, accountdomain.KycWindowDaily, limitErr.Window)
+}
+
+func TestWithdraw_KycDailyLimitCountsHeldWithdrawals(t *testing.T) {
+	userID := uuid.New()
+	now := time.Now().UTC()
+	txs := []*dto.TransactionRead{
+		// The hold of an approved withdrawal is its debit.
+		{Amount: -100, Currency: "USD", MoneySource: "withdraw_hold", Status: "completed",
+			CreatedAt: now},
+		// A rejected withdrawal's hold is canceled and does not count.
+		{Amount: -100, Currency: "USD", MoneySource: "withdraw_hold", Status: "canceled",
+			CreatedAt: now},
+	}
+	bus := mocks.NewBus(t)
+	expectKycLimitReached(bus, accountdomain.KycWindowDaily)
+	uow := setupKycUOW(t, userID, user.KycTierUnverified, txs)
+	stripeConnectSvc := stripeconnect.New(uow, slog.Default(), &config.Stripe{})
+	svc := accountsvc.New(
+		bus, uow, slog.Default(), stripeConnectSvc,
+		accountsvc.WithKycLimits(testKycConfig, nil),
+	)
+
+	err := svc.Withdraw(context.Background(), commands.Withdraw{
+		UserID:    userID,
+		AccountID: uuid.New(),
+		Amount:    60,
+		Currency:  "USD",
+		ExternalTarget: &commands.ExternalTarget{
+			BankAccountNumber: "1234567890",
+		},
+	})
+	var limitErr *accountdomain.KycLimitError
+	require.ErrorAs(t, err, &limitErr)
+	assert.Equal(t, accountdomain.KycWindowDaily, limitErr.Window)
+}
+
+func TestDeposit_KycVerifiedUnlimited(t *testing.T) {
+	userID := uuid.New()
+	bus := mocks.NewBus(t)
+	bus.EXPECT().Emit(mock.Anything, mock.AnythingOfType("*events.DepositRequested")).
+		Return(nil).Once()
+	uow := setupKycUOW(t, userID, user.KycTierVerified, nil)
+	svc := accountsvc.New(
+		bus, uow, slog.Default(), nil, accountsvc.WithKycLimits(testKycConfig, nil),
+	)
+
+	err := svc.Deposit(context.Background(), commands.Deposit{
+		UserID:    userID,
+		AccountID: uuid.New(),
+		Amount:    1_000_000,
+		Currency:  "USD",
+	})
+	require.NoError(t, err)
+}
@@ -0,0 +1,144 @@
+package account
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/amirasaad/fintech/pkg/commands"
+	"github.com/amirasaad/fintech/pkg/domain"
+	"github.com/amirasaad/fintech/pkg/dto"
+	"github.com/amirasaad/fintech/pkg/repository"
+	"github.com/amirasaad/fintech/pkg/repository/payoutdestination"
+	"github.com/google/uuid"
+)
+
+// AddPayoutDestination saves an external withdrawal target for the user. The
+// target should already be validated; it is stored as given so later
+// withdrawals can reuse it by ID.
+func (s *Service) AddPayoutDestination(
+	ctx context.Context,
+	userID uuid.UUID,
+	label string,
+	target commands.ExternalTarget,
+) (*dto.PayoutDestinationRead, error) {
+	var result *dto.PayoutDestinationRead
+	err := s.uow.Do(ctx, func(uow repository.UnitOfWork) error {
+		repo, err := getPayoutDestinationRepository(uow)
+		if err != nil {
+			return err
+		}
+		id := uuid.New()
+		if err := repo.Create(ctx, dto.PayoutDestinationCreate{
+			ID:                    id,
+			UserID:                userID,
+			Label:                 label,
+			BankAccountNumber:     target.BankAccountNumber,
+			RoutingNumber:         target.RoutingNumber,
+			BIC:                   target.BIC,
+			ExternalWalletAddress: target.ExternalWalletAddress,
+			Network:               target.Network,
+		}); err != nil {
+			return fmt.Errorf("failed to create payout destination: %w", err)
+		}
+		result, err = repo.Get(ctx, id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ListPayoutDestinations returns the user's saved payout destinations.
+func (s *Service) ListPayoutDestinations(
+	ctx context.Context,
+	userID uuid.UUID,
+) ([]*dto.PayoutDestinationRead, error) {
+	repo, err := getPayoutDestinationRepository(s.uow)
+	if err != nil {
+		return nil, err
+	}
+	return repo.ListByUser(ctx, userID)
+}
+
+// RemovePayoutDestination deletes a payout destination owned by the user.
+func (s *Service) RemovePayoutDestination(
+	ctx context.Context,
+	userID, id uuid.UUID,
+) error {
+	return s.uow.Do(ctx, func(uow repository.UnitOfWork) error {
+		repo, err := getPayoutDestinationRepository(uow)
+		if err != nil {
+			return err
+		}
+		if _, err := getOwnedPayoutDestination(ctx, repo, userID, id); err != nil {
+			return err
+		}
+		if err := repo.Delete(ctx, id); err != nil {
+			return fmt.Errorf("failed to delete payout destination: %w", err)
+		}
+		return nil
+	})
+}
+
+// PayoutDestinationTarget resolves a saved payout destination owned by the user
+// into the external target of a withdrawal.
+func (s *Service) PayoutDestinationTarget(
+	ctx context.Context,
+	userID, id uuid.UUID,
+) (*commands.ExternalTarget, error) {
+	var target *commands.ExternalTarget
+	err := s.uow.Do(ctx, func(uow repository.UnitOfWork) error {
+		repo, err := getPayoutDestinationRepository(uow)
+		if err != nil {
+			return err
+		}
+		pd, err := getOwnedPayoutDestination(ctx, repo, userID, id)
+		if err != nil {
+			return err
+		}
+		target = &commands.ExternalTarget{
+			BankAccountNumber:     pd.BankAccountNumber,
+			RoutingNumber:         pd.RoutingNumber,
+			BIC:                   pd.BIC,
+			ExternalWalletAddress: pd.ExternalWalletAddress,
+			Network:               pd.Network,
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return target, nil
+}
+
+// getOwnedPayoutDestination loads a payout destination and reports
+// domain.ErrNotFound if it belongs to another user.
+func getOwnedPayoutDestination(
+	ctx context.Context,
+	repo payoutdestination.Repository,
+	userID, id uuid.UUID,
+) (*dto.PayoutDestinationRead, error) {
+	pd, err := repo.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if pd.UserID != userID {
+		return nil, domain.ErrNotFound
+	}
+	return pd, nil
+}
+
+func getPayoutDestinationRepository(
+	uow repository.UnitOfWork,
+) (payoutdestination.Repository, error) {
+	repoAny, err := uow.GetRepository((*payoutdestination.Repository)(nil))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get payout destination repository: %w", err)
+	}
+	repo, ok := repoAny.(payoutdestination.Repository)
+	if !ok {
+		return nil, fmt.Errorf("unexpected payout destination repository type %T", repoAny)
+	}
+	return repo, nil
+}
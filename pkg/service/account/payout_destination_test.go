@@ -0,0 +1,127 @@
+package account_test
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/amirasaad/fintech/internal/fixtures/mocks"
+	"github.com/amirasaad/fintech/pkg/commands"
+	"github.com/amirasaad/fintech/pkg/domain"
+	"github.com/amirasaad/fintech/pkg/dto"
+	"github.com/amirasaad/fintech/pkg/repository"
+	"github.com/amirasaad/fintech/pkg/repository/payoutdestination"
+	accountsvc "github.com/amirasaad/fintech/pkg/service/account"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// fakePayoutDestinationRepo is an in-memory payoutdestination.Repository.
+type fakePayoutDestinationRepo struct {
+	items map[uuid.UUID]*dto.PayoutDestinationRead
+}
+
+func (r *fakePayoutDestinationRepo) Create(
+	_ context.Context,
+	create dto.PayoutDestinationCreate,
+) error {
+	r.items[create.ID] = &dto.PayoutDestinationRead{
+		ID:                    create.ID,
+		UserID:                create.UserID,
+		Label:                 create.Label,
+		BankAccountNumber:     create.BankAccountNumber,
+		RoutingNumber:         create.RoutingNumber,
+		ExternalWalletAddress: create.ExternalWalletAddress,
+		Network:               create.Network,
+		CreatedAt:             time.Now(),
+	}
+	return nil
+}
+
+func (r *fakePayoutDestinationRepo) Get(
+	_ context.Context,
+	id uuid.UUID,
+) (*dto.PayoutDestinationRead, error) {
+	pd, ok := r.items[id]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	return pd, nil
+}
+
+func (r *fakePayoutDestinationRepo) ListByUser(
+	_ context.Context,
+	userID uuid.UUID,
+) ([]*dto.PayoutDestinationRead, error) {
+	var out []*dto.PayoutDestinationRead
+	for _, pd := range r.items {
+		if pd.UserID == userID {
+			out = append(out, pd)
+		}
+	}
+	return out, nil
+}
+
+func (r *fakePayoutDestinationRepo) Delete(_ context.Context, id uuid.UUID) error {
+	delete(r.items, id)
+	return nil
+}
+
+var _ payoutdestination.Repository = (*fakePayoutDestinationRepo)(nil)
+
+func TestPayoutDestinations(t *testing.T) {
+	userID := uuid.New()
+	pdRepo := &fakePayoutDestinationRepo{items: map[uuid.UUID]*dto.PayoutDestinationRead{}}
+	uow := mocks.NewUnitOfWork(t)
+	uow.EXPECT().Do(mock.Anything, mock.Anything).RunAndReturn(
+		func(ctx context.Context, fn func(repository.UnitOfWork) error) error {
+			return fn(uow)
+		},
+	).Maybe()
+	uow.EXPECT().GetRepository(mock.Anything).Return(pdRepo, nil)
+	svc := accountsvc.New(nil, uow, slog.Default(), nil)
+	ctx := context.Background()
+
+	target := commands.ExternalTarget{
+		BankAccountNumber: "000123456789",
+		RoutingNumber:     "110000000",
+	}
+	pd, err := svc.AddPayoutDestination(ctx, userID, "Checking", target)
+	require.NoError(t, err)
+	assert.Equal(t, dto.PayoutDestinationBankAccount, pd.Type())
+	assert.Equal(t, "6789", pd.Last4())
+
+	list, err := svc.ListPayoutDestinations(ctx, userID)
+	require.NoError(t, err)
+	assert.Len(t, list, 1)
+
+	resolved, err := svc.PayoutDestinationTarget(ctx, userID, pd.ID)
+	require.NoError(t, err)
+	assert.Equal(t, target, *resolved)
+
+	// Other users can neither use nor remove the destination.
+	_, err = svc.PayoutDestinationTarget(ctx, uuid.New(), pd.ID)
+	require.ErrorIs(t, err, domain.ErrNotFound)
+	require.ErrorIs(t,
+		svc.RemovePayoutDestination(ctx, uuid.New(), pd.ID),
+		domain.ErrNotFound,
+	)
+
+	require.NoError(t, svc.RemovePayoutDestination(ctx, userID, pd.ID))
+	list, err = svc.ListPayoutDestinations(ctx, userID)
+	require.NoError(t, err)
+	assert.Empty(t, list)
+}
+
+func TestPayoutDestinationRead_Last4(t *testing.T) {
+	wallet := &dto.PayoutDestinationRead{
+		ExternalWalletAddress: "0x52908400098527886E0F7030069857D2E4169EE7",
+		Network:               "ethereum",
+	}
+	assert.Equal(t, dto.PayoutDestinationExternalWallet, wallet.Type())
+	assert.Equal(t, "9EE7", wallet.Last4())
+	assert.Equal(t, "12", (&dto.PayoutDestinationRead{BankAccountNumber: "12"}).Last4())
+}
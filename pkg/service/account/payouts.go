@@ -0,0 +1,103 @@
+package account
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/amirasaad/fintech/pkg/domain/account"
+	"github.com/amirasaad/fintech/pkg/dto"
+	"github.com/amirasaad/fintech/pkg/handler/common"
+	"github.com/google/uuid"
+)
+
+// Payout statuses, as reported in a user's payout history.
+const (
+	PayoutStatusPending   = "pending"
+	PayoutStatusCompleted = "completed"
+	PayoutStatusFailed    = "failed"
+)
+
+const (
+	// DefaultPayoutPageSize is the page size used when none is given.
+	DefaultPayoutPageSize = 20
+	// MaxPayoutPageSize caps the page size of payout listings.
+	MaxPayoutPageSize = 100
+)
+
+// PayoutStatus returns the payout status of a withdrawal transaction in the
+// given status: pending until it completes, failed if it failed or was
+// canceled.
+func PayoutStatus(txStatus string) string {
+	switch account.TransactionStatus(txStatus) {
+	case account.TransactionStatusCompleted:
+		return PayoutStatusCompleted
+	case account.TransactionStatusFailed, account.TransactionStatusCanceled:
+		return PayoutStatusFailed
+	default:
+		return PayoutStatusPending
+	}
+}
+
+// payoutTransactionStatuses returns the transaction statuses of withdrawals
+// in the given payout status; nil for all of them.
+func payoutTransactionStatuses(status string) []string {
+	var statuses []account.TransactionStatus
+	switch status {
+	case PayoutStatusPending:
+		statuses = []account.TransactionStatus{
+			account.TransactionStatusCreated,
+			account.TransactionStatusPending,
+			account.TransactionStatusProcessed,
+		}
+	case PayoutStatusCompleted:
+		statuses = []account.TransactionStatus{account.TransactionStatusCompleted}
+	case PayoutStatusFailed:
+		statuses = []account.TransactionStatus{
+			account.TransactionStatusFailed,
+			account.TransactionStatusCanceled,
+		}
+	default:
+		return nil
+	}
+	out := make([]string, 0, len(statuses))
+	for _, st := range statuses {
+		out = append(out, string(st))
+	}
+	return out
+}
+
+// ListPayouts returns one page of the user's withdrawals, newest first,
+// including those held for approval. A non-empty status
+// (PayoutStatusPending, PayoutStatusCompleted or PayoutStatusFailed) keeps
+// only the withdrawals in that payout status.
+func (s *Service) ListPayouts(
+	ctx context.Context,
+	userID uuid.UUID,
+	status string,
+	page, pageSize int,
+) ([]*dto.TransactionRead, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = DefaultPayoutPageSize
+	}
+	pageSize = min(pageSize, MaxPayoutPageSize)
+
+	txRepo, err := common.GetTransactionRepository(s.uow, s.logger)
+	if err != nil {
+		return nil, err
+	}
+	payouts, err := txRepo.ListPayouts(
+		ctx,
+		userID,
+		common.WithdrawMoneySources(),
+		payoutTransactionStatuses(status),
+		page,
+		pageSize,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list payouts: %w", err)
+	}
+	return payouts, nil
+}
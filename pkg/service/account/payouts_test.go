@@ -0,0 +1,70 @@
+package account_test
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/amirasaad/fintech/internal/fixtures/mocks"
+	"github.com/amirasaad/fintech/pkg/dto"
+	accountsvc "github.com/amirasaad/fintech/pkg/service/account"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListPayouts(t *testing.T) {
+	userID := uuid.New()
+	tests := []struct {
+		name         string
+		status       string
+		page         int
+		pageSize     int
+		wantStatuses []string
+		wantPage     int
+		wantPageSize int
+	}{
+		{"all", "", 2, 10, nil, 2, 10},
+		{"pending", "pending", 1, 0, []string{"created", "pending", "processed"}, 1, 20},
+		{"completed", "completed", 0, 20, []string{"completed"}, 1, 20},
+		{"failed", "failed", 1, 1000, []string{"failed", "canceled"}, 1, 100},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			txRepo := mocks.NewTransactionRepository(t)
+			txRepo.EXPECT().
+				ListPayouts(
+					mock.Anything,
+					userID,
+					[]string{"withdraw", "withdraw_hold"},
+					tt.wantStatuses,
+					tt.wantPage,
+					tt.wantPageSize,
+				).
+				Return([]*dto.TransactionRead{{ID: uuid.New()}}, nil)
+			uow := mocks.NewUnitOfWork(t)
+			uow.EXPECT().GetRepository(mock.Anything).Return(txRepo, nil)
+			svc := accountsvc.New(nil, uow, slog.Default(), nil)
+
+			payouts, err := svc.ListPayouts(
+				context.Background(), userID, tt.status, tt.page, tt.pageSize,
+			)
+			require.NoError(t, err)
+			assert.Len(t, payouts, 1)
+		})
+	}
+}
+
+func TestPayoutStatus(t *testing.T) {
+	for txStatus, want := range map[string]string{
+		"created":   accountsvc.PayoutStatusPending,
+		"pending":   accountsvc.PayoutStatusPending,
+		"processed": accountsvc.PayoutStatusPending,
+		"completed": accountsvc.PayoutStatusCompleted,
+		"failed":    accountsvc.PayoutStatusFailed,
+		"canceled":  accountsvc.PayoutStatusFailed,
+	} {
+		assert.Equal(t, want, accountsvc.PayoutStatus(txStatus), txStatus)
+	}
+}
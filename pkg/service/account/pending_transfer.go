@@ -0,0 +1,358 @@
+package account
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/amirasaad/fintech/pkg/commands"
+	"github.com/amirasaad/fintech/pkg/domain/account"
+	"github.com/amirasaad/fintech/pkg/domain/events"
+	"github.com/amirasaad/fintech/pkg/dto"
+	"github.com/amirasaad/fintech/pkg/handler/common"
+	"github.com/amirasaad/fintech/pkg/mapper"
+	"github.com/amirasaad/fintech/pkg/money"
+	"github.com/amirasaad/fintech/pkg/repository"
+	repoaccount "github.com/amirasaad/fintech/pkg/repository/account"
+	"github.com/amirasaad/fintech/pkg/repository/pendingtransfer"
+	"github.com/google/uuid"
+)
+
+// moneySourceTransfer is the money source of transfer transactions.
+const moneySourceTransfer = "transfer"
+
+// RequestTransferApproval starts a two-phase transfer: the amount is debited
+// from the source account right away, recorded as a completed outgoing
+// transaction, and held until an admin approves the transfer
+// (ApproveTransfer) or rejects it (RejectTransfer). Both accounts must be in
+// the transfer currency. It emits TransferPending.
+func (s *Service) RequestTransferApproval(
+	ctx context.Context,
+	cmd commands.Transfer,
+) (*dto.PendingTransferRead, error) {
+	if !cmd.ExecuteAt.IsZero() {
+		return nil, account.ErrApprovalTransferScheduled
+	}
+	unlock, err := s.lockAccounts(ctx, cmd.AccountID, cmd.ToAccountID)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	amount, err := money.New(cmd.Amount, money.Code(cmd.Currency))
+	if err != nil {
+		return nil, err
+	}
+	description, err := account.NormalizeDescription(cmd.Description)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.ensureAccountAcceptsFunds(ctx, cmd.ToAccountID); err != nil {
+		return nil, err
+	}
+	if err := s.enforceWithdrawalCount(ctx, cmd.AccountID); err != nil {
+		return nil, err
+	}
+
+	var result *dto.PendingTransferRead
+	err = s.uow.Do(ctx, func(uow repository.UnitOfWork) error {
+		accRepo, err := common.GetAccountRepository(uow, s.logger)
+		if err != nil {
+			return err
+		}
+		txRepo, err := common.GetTransactionRepository(uow, s.logger)
+		if err != nil {
+			return err
+		}
+		ptRepo, err := getPendingTransferRepository(uow)
+		if err != nil {
+			return err
+		}
+
+		src, err := accRepo.GetForUpdate(ctx, cmd.AccountID)
+		if err != nil {
+			return fmt.Errorf("source account: %w", err)
+		}
+		dest, err := accRepo.Get(ctx, cmd.ToAccountID)
+		if err != nil {
+			return fmt.Errorf("destination account: %w", err)
+		}
+		if !isActive(src) || !isActive(dest) {
+			return account.ErrAccountNotActive
+		}
+		srcAcc, err := mapper.MapAccountReadToDomain(src)
+		if err != nil {
+			return err
+		}
+		destAcc, err := mapper.MapAccountReadToDomain(dest)
+		if err != nil {
+			return err
+		}
+		if err := srcAcc.ValidateTransfer(
+			cmd.UserID, destAcc.UserID, destAcc, amount,
+		); err != nil {
+			return err
+		}
+
+		newBalance, err := srcAcc.Balance.Subtract(amount)
+		if err != nil {
+			return err
+		}
+		if err := updateBalance(ctx, accRepo, src.ID, newBalance); err != nil {
+			return fmt.Errorf("failed to debit source account: %w", err)
+		}
+		txID := uuid.New()
+		if err := txRepo.Create(ctx, dto.TransactionCreate{
+			ID:          txID,
+			UserID:      cmd.UserID,
+			AccountID:   src.ID,
+			Amount:      amount.Negate().Amount(),
+			Currency:    amount.Currency().String(),
+			Status:      string(account.TransactionStatusCompleted),
+			MoneySource: moneySourceTransfer,
+			Description: description,
+		}); err != nil {
+			return fmt.Errorf("failed to create outgoing transaction: %w", err)
+		}
+
+		id := uuid.New()
+		if err := ptRepo.Create(ctx, dto.PendingTransferCreate{
+			ID:            id,
+			UserID:        cmd.UserID,
+			FromAccountID: src.ID,
+			ToAccountID:   dest.ID,
+			TransactionID: txID,
+			Amount:        amount.Amount(),
+			Currency:      amount.Currency().String(),
+			Description:   description,
+		}); err != nil {
+			return fmt.Errorf("failed to create pending transfer: %w", err)
+		}
+		result, err = ptRepo.Get(ctx, id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.emitTransferEvent(ctx, events.NewTransferPending(
+		result.UserID,
+		result.FromAccountID,
+		result.ToAccountID,
+		result.ID,
+		result.TransactionID,
+		amount,
+		result.Description,
+	))
+	return result, nil
+}
+
+// ListPendingTransfers returns the transfers awaiting approval, or those in
+// the given status (e.g. dto.PendingTransferRejected). It performs no
+// ownership checks; callers must restrict it to admins.
+func (s *Service) ListPendingTransfers(
+	ctx context.Context,
+	status string,
+) ([]*dto.PendingTransferRead, error) {
+	repo, err := getPendingTransferRepository(s.uow)
+	if err != nil {
+		return nil, err
+	}
+	return repo.List(ctx, status)
+}
+
+// ApproveTransfer credits the destination account of a pending transfer,
+// recording the incoming transaction, and emits TransferApproved. The
+// destination is credited even if it has been closed since the transfer was
+// requested: the money has already left the source. Like
+// ListPendingTransfers it performs no ownership checks; callers must restrict
+// it to admins.
+func (s *Service) ApproveTransfer(
+	ctx context.Context,
+	approverID, id uuid.UUID,
+) (*dto.PendingTransferRead, error) {
+	pt, err := s.decideTransfer(ctx, approverID, id, dto.PendingTransferApproved, "")
+	if err != nil {
+		return nil, err
+	}
+	s.emitTransferEvent(ctx, events.NewTransferApproved(transferPendingOf(pt), approverID))
+	return pt, nil
+}
+
+// RejectTransfer returns the amount of a pending transfer to the source
+// account and emits TransferRejected. The outgoing transaction is marked
+// failed, so the source balance and ledger are as if the transfer had never
+// been requested. Callers must restrict it to admins.
+func (s *Service) RejectTransfer(
+	ctx context.Context,
+	approverID, id uuid.UUID,
+	reason string,
+) (*dto.PendingTransferRead, error) {
+	pt, err := s.decideTransfer(ctx, approverID, id, dto.PendingTransferRejected, reason)
+	if err != nil {
+		return nil, err
+	}
+	s.emitTransferEvent(ctx, events.NewTransferRejected(transferPendingOf(pt), approverID, reason))
+	return pt, nil
+}
+
+// decideTransfer moves a pending transfer to status, approved or rejected,
+// and settles its money in the same unit of work.
+func (s *Service) decideTransfer(
+	ctx context.Context,
+	approverID, id uuid.UUID,
+	status, reason string,
+) (*dto.PendingTransferRead, error) {
+	var result *dto.PendingTransferRead
+	err := s.uow.Do(ctx, func(uow repository.UnitOfWork) error {
+		accRepo, err := common.GetAccountRepository(uow, s.logger)
+		if err != nil {
+			return err
+		}
+		txRepo, err := common.GetTransactionRepository(uow, s.logger)
+		if err != nil {
+			return err
+		}
+		ptRepo, err := getPendingTransferRepository(uow)
+		if err != nil {
+			return err
+		}
+
+		pt, err := ptRepo.Get(ctx, id)
+		if err != nil {
+			return err
+		}
+		// Deciding first makes concurrent decisions on the same transfer wait
+		// for each other; only one of them finds it still pending.
+		ok, err := ptRepo.Decide(ctx, id, status, approverID, reason)
+		if err != nil {
+			return fmt.Errorf("failed to update pending transfer: %w", err)
+		}
+		if !ok {
+			return account.ErrPendingTransferNotPending
+		}
+		amount, err := money.New(pt.Amount, money.Code(pt.Currency))
+		if err != nil {
+			return err
+		}
+
+		switch status {
+		case dto.PendingTransferApproved:
+			dest, err := accRepo.GetForUpdate(ctx, pt.ToAccountID)
+			if err != nil {
+				return fmt.Errorf("destination account: %w", err)
+			}
+			if err := credit(ctx, accRepo, dest, amount); err != nil {
+				return fmt.Errorf("failed to credit destination account: %w", err)
+			}
+			if err := txRepo.Create(ctx, dto.TransactionCreate{
+				ID:          uuid.New(),
+				UserID:      dest.UserID,
+				AccountID:   dest.ID,
+				Amount:      amount.Amount(),
+				Currency:    amount.Currency().String(),
+				Status:      string(account.TransactionStatusCompleted),
+				MoneySource: moneySourceTransfer,
+				Description: pt.Description,
+			}); err != nil {
+				return fmt.Errorf("failed to create incoming transaction: %w", err)
+			}
+		case dto.PendingTransferRejected:
+			src, err := accRepo.GetForUpdate(ctx, pt.FromAccountID)
+			if err != nil {
+				return fmt.Errorf("source account: %w", err)
+			}
+			if err := credit(ctx, accRepo, src, amount); err != nil {
+				return fmt.Errorf("failed to release transfer to source account: %w", err)
+			}
+			failed := string(account.TransactionStatusFailed)
+			if err := txRepo.Update(
+				ctx, pt.TransactionID, dto.TransactionUpdate{Status: &failed},
+			); err != nil {
+				return fmt.Errorf("failed to update outgoing transaction: %w", err)
+			}
+		}
+		result, err = ptRepo.Get(ctx, id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.logger.Info("Pending transfer decided",
+		"pending_transfer_id", id,
+		"status", status,
+		"decided_by", approverID,
+	)
+	return result, nil
+}
+
+// credit adds amount, in the account currency, to the account's balance.
+func credit(
+	ctx context.Context,
+	accRepo repoaccount.Repository,
+	acc *dto.AccountRead,
+	amount *money.Money,
+) error {
+	balance, err := money.New(acc.Balance, money.Code(acc.Currency))
+	if err != nil {
+		return err
+	}
+	newBalance, err := balance.Add(amount)
+	if err != nil {
+		return err
+	}
+	return updateBalance(ctx, accRepo, acc.ID, newBalance)
+}
+
+func updateBalance(
+	ctx context.Context,
+	accRepo repoaccount.Repository,
+	accountID uuid.UUID,
+	balance *money.Money,
+) error {
+	amount := balance.Amount()
+	return accRepo.Update(ctx, accountID, dto.AccountUpdate{Balance: &amount})
+}
+
+// emitTransferEvent emits an event of a two-phase transfer. The money has
+// already moved by then, so a failure is only logged.
+func (s *Service) emitTransferEvent(ctx context.Context, e events.Event) {
+	if s.bus == nil {
+		return
+	}
+	if err := s.bus.Emit(ctx, e); err != nil {
+		s.logger.Error("failed to emit transfer event",
+			"event_type", e.Type(),
+			"error", err,
+		)
+	}
+}
+
+// transferPendingOf rebuilds the TransferPending event of a pending transfer.
+func transferPendingOf(pt *dto.PendingTransferRead) *events.TransferPending {
+	amount, err := money.New(pt.Amount, money.Code(pt.Currency))
+	if err != nil {
+		amount = money.Zero(money.Code(pt.Currency))
+	}
+	return events.NewTransferPending(
+		pt.UserID,
+		pt.FromAccountID,
+		pt.ToAccountID,
+		pt.ID,
+		pt.TransactionID,
+		amount,
+		pt.Description,
+	)
+}
+
+func getPendingTransferRepository(
+	uow repository.UnitOfWork,
+) (pendingtransfer.Repository, error) {
+	repoAny, err := uow.GetRepository((*pendingtransfer.Repository)(nil))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pending transfer repository: %w", err)
+	}
+	repo, ok := repoAny.(pendingtransfer.Repository)
+	if !ok {
+		return nil, fmt.Errorf("unexpected pending transfer repository type %T", repoAny)
+	}
+	return repo, nil
+}
@@ -0,0 +1,302 @@
+package account_test
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/amirasaad/fintech/internal/fixtures/mocks"
+	"github.com/amirasaad/fintech/pkg/commands"
+	"github.com/amirasaad/fintech/pkg/domain"
+	accountdomain "github.com/amirasaad/fintech/pkg/domain/account"
+	"github.com/amirasaad/fintech/pkg/domain/events"
+	"github.com/amirasaad/fintech/pkg/dto"
+	"github.com/amirasaad/fintech/pkg/repository"
+	"github.com/amirasaad/fintech/pkg/repository/pendingtransfer"
+	"github.com/amirasaad/fintech/pkg/repository/transaction"
+	accountsvc "github.com/amirasaad/fintech/pkg/service/account"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// fakePendingTransferRepo is an in-memory pendingtransfer.Repository.
+type fakePendingTransferRepo struct {
+	items map[uuid.UUID]*dto.PendingTransferRead
+}
+
+func (r *fakePendingTransferRepo) Create(
+	_ context.Context,
+	create dto.PendingTransferCreate,
+) error {
+	r.items[create.ID] = &dto.PendingTransferRead{
+		ID:            create.ID,
+		UserID:        create.UserID,
+		FromAccountID: create.FromAccountID,
+		ToAccountID:   create.ToAccountID,
+		TransactionID: create.TransactionID,
+		Amount:        float64(create.Amount) / 100,
+		Currency:      create.Currency,
+		Description:   create.Description,
+		Status:        dto.PendingTransferPending,
+		CreatedAt:     time.Now(),
+	}
+	return nil
+}
+
+func (r *fakePendingTransferRepo) Get(
+	_ context.Context,
+	id uuid.UUID,
+) (*dto.PendingTransferRead, error) {
+	pt, ok := r.items[id]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	return pt, nil
+}
+
+func (r *fakePendingTransferRepo) List(
+	_ context.Context,
+	status string,
+) ([]*dto.PendingTransferRead, error) {
+	var out []*dto.PendingTransferRead
+	for _, pt := range r.items {
+		if status == "" || pt.Status == status {
+			out = append(out, pt)
+		}
+	}
+	return out, nil
+}
+
+func (r *fakePendingTransferRepo) Decide(
+	_ context.Context,
+	id uuid.UUID,
+	status string,
+	decidedBy uuid.UUID,
+	reason string,
+) (bool, error) {
+	pt, ok := r.items[id]
+	if !ok || pt.Status != dto.PendingTransferPending {
+		return false, nil
+	}
+	pt.Status = status
+	pt.DecidedBy = &decidedBy
+	pt.Reason = reason
+	return true, nil
+}
+
+var _ pendingtransfer.Repository = (*fakePendingTransferRepo)(nil)
+
+// pendingTransferFixture holds two USD accounts whose balances, in cents,
+// are kept up to date by the account repository mock.
+type pendingTransferFixture struct {
+	svc      *accountsvc.Service
+	userID   uuid.UUID
+	fromID   uuid.UUID
+	toID     uuid.UUID
+	balances map[uuid.UUID]int64
+	txs      map[uuid.UUID]dto.TransactionCreate
+	ptRepo   *fakePendingTransferRepo
+}
+
+// newPendingTransferFixture builds the fixture. A nil bus accepts any event.
+func newPendingTransferFixture(t *testing.T, bus *mocks.Bus) *pendingTransferFixture {
+	f := &pendingTransferFixture{
+		userID: uuid.New(),
+		fromID: uuid.New(),
+		toID:   uuid.New(),
+		txs:    map[uuid.UUID]dto.TransactionCreate{},
+		ptRepo: &fakePendingTransferRepo{items: map[uuid.UUID]*dto.PendingTransferRead{}},
+	}
+	f.balances = map[uuid.UUID]int64{f.fromID: 10000, f.toID: 500}
+	owners := map[uuid.UUID]uuid.UUID{f.fromID: f.userID, f.toID: uuid.New()}
+
+	accountRepo := mocks.NewAccountRepository(t)
+	getAccount := func(_ context.Context, id uuid.UUID) (*dto.AccountRead, error) {
+		return &dto.AccountRead{
+			ID:       id,
+			UserID:   owners[id],
+			Balance:  float64(f.balances[id]) / 100,
+			Currency: "USD",
+			Status:   "active",
+		}, nil
+	}
+	accountRepo.EXPECT().Get(mock.Anything, mock.Anything).RunAndReturn(getAccount).Maybe()
+	accountRepo.EXPECT().GetForUpdate(mock.Anything, mock.Anything).RunAndReturn(getAccount).Maybe()
+	accountRepo.EXPECT().Update(mock.Anything, mock.Anything, mock.Anything).RunAndReturn(
+		func(_ context.Context, id uuid.UUID, update dto.AccountUpdate) error {
+			f.balances[id] = *update.Balance
+			return nil
+		},
+	).Maybe()
+
+	txRepo := mocks.NewTransactionRepository(t)
+	txRepo.EXPECT().Create(mock.Anything, mock.Anything).RunAndReturn(
+		func(_ context.Context, tx dto.TransactionCreate) error {
+			f.txs[tx.ID] = tx
+			return nil
+		},
+	).Maybe()
+	txRepo.EXPECT().Update(mock.Anything, mock.Anything, mock.Anything).RunAndReturn(
+		func(_ context.Context, id uuid.UUID, update dto.TransactionUpdate) error {
+			tx := f.txs[id]
+			tx.Status = *update.Status
+			f.txs[id] = tx
+			return nil
+		},
+	).Maybe()
+
+	uow := mocks.NewUnitOfWork(t)
+	uow.EXPECT().Do(mock.Anything, mock.Anything).RunAndReturn(
+		func(ctx context.Context, fn func(repository.UnitOfWork) error) error {
+			return fn(uow)
+		},
+	).Maybe()
+	uow.EXPECT().GetRepository(mock.Anything).RunAndReturn(
+		func(repoType any) (any, error) {
+			switch repoType.(type) {
+			case *pendingtransfer.Repository:
+				return f.ptRepo, nil
+			case *transaction.Repository:
+				return txRepo, nil
+			}
+			return accountRepo, nil
+		},
+	).Maybe()
+
+	if bus == nil {
+		bus = mocks.NewBus(t)
+		bus.EXPECT().Emit(mock.Anything, mock.Anything).Return(nil).Maybe()
+	}
+	f.svc = accountsvc.New(bus, uow, slog.Default(), nil)
+	return f
+}
+
+func (f *pendingTransferFixture) request(t *testing.T) *dto.PendingTransferRead {
+	t.Helper()
+	pt, err := f.svc.RequestTransferApproval(context.Background(), commands.Transfer{
+		UserID:          f.userID,
+		AccountID:       f.fromID,
+		ToAccountID:     f.toID,
+		Amount:          25,
+		Currency:        "USD",
+		RequireApproval: true,
+	})
+	require.NoError(t, err)
+	return pt
+}
+
+func TestRequestTransferApproval_Approve(t *testing.T) {
+	f := newPendingTransferFixture(t, nil)
+	pt := f.request(t)
+	assert.Equal(t, dto.PendingTransferPending, pt.Status)
+	// The source is debited right away; the destination waits for approval.
+	assert.Equal(t, int64(7500), f.balances[f.fromID])
+	assert.Equal(t, int64(500), f.balances[f.toID])
+	assert.Equal(t, int64(-2500), f.txs[pt.TransactionID].Amount)
+
+	adminID := uuid.New()
+	approved, err := f.svc.ApproveTransfer(context.Background(), adminID, pt.ID)
+	require.NoError(t, err)
+	assert.Equal(t, dto.PendingTransferApproved, approved.Status)
+	assert.Equal(t, adminID, *approved.DecidedBy)
+	assert.Equal(t, int64(7500), f.balances[f.fromID])
+	assert.Equal(t, int64(3000), f.balances[f.toID])
+	assert.Len(t, f.txs, 2)
+
+	_, err = f.svc.ApproveTransfer(context.Background(), adminID, pt.ID)
+	require.ErrorIs(t, err, accountdomain.ErrPendingTransferNotPending)
+	_, err = f.svc.RejectTransfer(context.Background(), adminID, pt.ID, "")
+	require.ErrorIs(t, err, accountdomain.ErrPendingTransferNotPending)
+	assert.Equal(t, int64(3000), f.balances[f.toID])
+}
+
+func TestRequestTransferApproval_Reject(t *testing.T) {
+	f := newPendingTransferFixture(t, nil)
+	pt := f.request(t)
+
+	rejected, err := f.svc.RejectTransfer(
+		context.Background(), uuid.New(), pt.ID, "beneficiary not verified",
+	)
+	require.NoError(t, err)
+	assert.Equal(t, dto.PendingTransferRejected, rejected.Status)
+	assert.Equal(t, "beneficiary not verified", rejected.Reason)
+	// The source balance is fully restored and the debit no longer counts.
+	assert.Equal(t, int64(10000), f.balances[f.fromID])
+	assert.Equal(t, int64(500), f.balances[f.toID])
+	assert.Equal(t,
+		string(accountdomain.TransactionStatusFailed),
+		f.txs[pt.TransactionID].Status,
+	)
+}
+
+func TestRequestTransferApproval_Invalid(t *testing.T) {
+	f := newPendingTransferFixture(t, nil)
+	tests := []struct {
+		name    string
+		cmd     commands.Transfer
+		wantErr error
+	}{
+		{
+			name: "insufficient funds",
+			cmd: commands.Transfer{
+				UserID: f.userID, AccountID: f.fromID, ToAccountID: f.toID,
+				Amount: 1000, Currency: "USD",
+			},
+			wantErr: accountdomain.ErrInsufficientFunds,
+		},
+		{
+			name: "not owner",
+			cmd: commands.Transfer{
+				UserID: uuid.New(), AccountID: f.fromID, ToAccountID: f.toID,
+				Amount: 10, Currency: "USD",
+			},
+			wantErr: accountdomain.ErrNotOwner,
+		},
+		{
+			name: "currency mismatch",
+			cmd: commands.Transfer{
+				UserID: f.userID, AccountID: f.fromID, ToAccountID: f.toID,
+				Amount: 10, Currency: "EUR",
+			},
+			wantErr: accountdomain.ErrCurrencyMismatch,
+		},
+		{
+			name: "scheduled",
+			cmd: commands.Transfer{
+				UserID: f.userID, AccountID: f.fromID, ToAccountID: f.toID,
+				Amount: 10, Currency: "USD", ExecuteAt: time.Now().Add(time.Hour),
+			},
+			wantErr: accountdomain.ErrApprovalTransferScheduled,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := f.svc.RequestTransferApproval(context.Background(), tt.cmd)
+			require.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, int64(10000), f.balances[f.fromID])
+			assert.Empty(t, f.ptRepo.items)
+		})
+	}
+}
+
+func TestTransfer_RequireApprovalEmitsTransferPending(t *testing.T) {
+	bus := mocks.NewBus(t)
+	f := newPendingTransferFixture(t, bus)
+	bus.EXPECT().Emit(mock.Anything, mock.MatchedBy(func(e events.Event) bool {
+		tp, ok := e.(*events.TransferPending)
+		return ok && tp.DestAccountID == f.toID && tp.Amount.Amount() == 2500
+	})).Return(nil).Once()
+
+	require.NoError(t, f.svc.Transfer(context.Background(), commands.Transfer{
+		UserID:          f.userID,
+		AccountID:       f.fromID,
+		ToAccountID:     f.toID,
+		Amount:          25,
+		Currency:        "USD",
+		RequireApproval: true,
+	}))
+	assert.Len(t, f.ptRepo.items, 1)
+}
@@ -0,0 +1,484 @@
+package account
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/amirasaad/fintech/pkg/commands"
+	"github.com/amirasaad/fintech/pkg/config"
+	"github.com/amirasaad/fintech/pkg/domain/account"
+	"github.com/amirasaad/fintech/pkg/domain/events"
+	"github.com/amirasaad/fintech/pkg/dto"
+	"github.com/amirasaad/fintech/pkg/handler/common"
+	"github.com/amirasaad/fintech/pkg/mapper"
+	"github.com/amirasaad/fintech/pkg/money"
+	"github.com/amirasaad/fintech/pkg/repository"
+	"github.com/amirasaad/fintech/pkg/repository/pendingwithdrawal"
+	"github.com/google/uuid"
+)
+
+// moneySourceWithdrawHold is the money source of the transactions holding
+// the amount of withdrawals awaiting approval.
+const moneySourceWithdrawHold = common.MoneySourceWithdrawHold
+
+// WithWithdrawalApproval holds withdrawals above cfg.ApprovalThreshold until
+// cfg.RequiredApprovals approvers approve them. Without it, or with a zero
+// threshold, no withdrawal needs approval.
+func WithWithdrawalApproval(cfg *config.Withdraw) Option {
+	return func(s *Service) {
+		if cfg == nil || cfg.ApprovalThreshold <= 0 {
+			return
+		}
+		s.withdrawApproval = cfg
+	}
+}
+
+// WithdrawalRequiresApproval reports whether the withdrawal is above the
+// approval threshold, and so is held by Withdraw until approved.
+func (s *Service) WithdrawalRequiresApproval(
+	ctx context.Context,
+	cmd commands.Withdraw,
+) (bool, error) {
+	amount, err := money.New(cmd.Amount, money.Code(cmd.Currency))
+	if err != nil {
+		return false, fmt.Errorf("invalid amount: %w", err)
+	}
+	return s.requiresWithdrawalApproval(ctx, amount)
+}
+
+// requiresWithdrawalApproval reports whether amount is above the approval
+// threshold, converting it to the threshold currency if needed.
+func (s *Service) requiresWithdrawalApproval(
+	ctx context.Context,
+	amount *money.Money,
+) (bool, error) {
+	if s.withdrawApproval == nil {
+		return false, nil
+	}
+	thresholdCurrency := money.Code(s.withdrawApproval.ApprovalCurrency)
+	threshold, err := money.New(s.withdrawApproval.ApprovalThreshold, thresholdCurrency)
+	if err != nil {
+		return false, fmt.Errorf("invalid withdrawal approval threshold: %w", err)
+	}
+	attempted := amount
+	if amount.CurrencyCode() != thresholdCurrency {
+		attempted, _, err = s.ConvertBalance(ctx, amount, thresholdCurrency)
+		if err != nil {
+			return false, fmt.Errorf("failed to convert amount for approval threshold: %w", err)
+		}
+	}
+	return attempted.Amount() > threshold.Amount(), nil
+}
+
+// RequestWithdrawalApproval holds a withdrawal for approval, whatever its
+// amount: the amount, in the account currency, is debited from the account
+// right away, recorded as a completed hold transaction, and held until the
+// required number of approvers other than the user approve the withdrawal
+// (ApproveWithdrawal), which starts it, or one rejects it
+// (RejectWithdrawal). It emits WithdrawPending to notify approvers.
+func (s *Service) RequestWithdrawalApproval(
+	ctx context.Context,
+	cmd commands.Withdraw,
+) (*dto.PendingWithdrawalRead, error) {
+	unlock, err := s.lockAccounts(ctx, cmd.AccountID)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	amount, description, err := s.checkWithdrawal(ctx, cmd)
+	if err != nil {
+		return nil, err
+	}
+	return s.holdWithdrawal(ctx, cmd, amount, description)
+}
+
+// holdWithdrawal holds a checked withdrawal for approval.
+func (s *Service) holdWithdrawal(
+	ctx context.Context,
+	cmd commands.Withdraw,
+	amount *money.Money,
+	description string,
+) (*dto.PendingWithdrawalRead, error) {
+	requiredApprovals := 1
+	if s.withdrawApproval != nil && s.withdrawApproval.RequiredApprovals > 1 {
+		requiredApprovals = s.withdrawApproval.RequiredApprovals
+	}
+
+	var (
+		result *dto.PendingWithdrawalRead
+		hold   *money.Money
+	)
+	err := s.uow.Do(ctx, func(uow repository.UnitOfWork) error {
+		accRepo, err := common.GetAccountRepository(uow, s.logger)
+		if err != nil {
+			return err
+		}
+		txRepo, err := common.GetTransactionRepository(uow, s.logger)
+		if err != nil {
+			return err
+		}
+		pwRepo, err := getPendingWithdrawalRepository(uow)
+		if err != nil {
+			return err
+		}
+
+		acc, err := accRepo.GetForUpdate(ctx, cmd.AccountID)
+		if err != nil {
+			return fmt.Errorf("account: %w", err)
+		}
+		if !isActive(acc) {
+			return account.ErrAccountNotActive
+		}
+		domainAcc, err := mapper.MapAccountReadToDomain(acc)
+		if err != nil {
+			return err
+		}
+		hold = amount
+		if amount.CurrencyCode() != money.Code(acc.Currency) {
+			if hold, _, err = s.ConvertBalance(ctx, amount, money.Code(acc.Currency)); err != nil {
+				return fmt.Errorf("failed to convert withdrawal to account currency: %w", err)
+			}
+		}
+		if err := domainAcc.ValidateWithdraw(cmd.UserID, hold); err != nil {
+			return err
+		}
+
+		newBalance, err := domainAcc.Balance.Subtract(hold)
+		if err != nil {
+			return err
+		}
+		if err := updateBalance(ctx, accRepo, acc.ID, newBalance); err != nil {
+			return fmt.Errorf("failed to hold withdrawal: %w", err)
+		}
+		// Once approved, the hold is the withdrawal's transaction, so it
+		// carries the destination like any other withdrawal's.
+		txID := uuid.New()
+		txCreate := dto.TransactionCreate{
+			ID:          txID,
+			UserID:      cmd.UserID,
+			AccountID:   acc.ID,
+			Amount:      hold.Negate().Amount(),
+			Currency:    hold.Currency().String(),
+			Status:      string(account.TransactionStatusCompleted),
+			MoneySource: moneySourceWithdrawHold,
+			Description: description,
+		}
+		if target := cmd.ExternalTarget; target != nil {
+			if target.BankAccountNumber != "" {
+				txCreate.ExternalTargetMasked = maskTail(target.BankAccountNumber)
+				txCreate.ExternalBankAccount = &dto.BankAccount{
+					Number:        target.BankAccountNumber,
+					RoutingNumber: target.RoutingNumber,
+					BIC:           target.BIC,
+				}
+			} else if target.ExternalWalletAddress != "" {
+				txCreate.ExternalTargetMasked = maskTail(target.ExternalWalletAddress)
+			}
+		}
+		if err := txRepo.Create(ctx, txCreate); err != nil {
+			return fmt.Errorf("failed to create hold transaction: %w", err)
+		}
+
+		create := dto.PendingWithdrawalCreate{
+			ID:                uuid.New(),
+			UserID:            cmd.UserID,
+			AccountID:         acc.ID,
+			TransactionID:     txID,
+			Amount:            amount.Amount(),
+			Currency:          amount.Currency().String(),
+			HoldAmount:        hold.Amount(),
+			HoldCurrency:      hold.Currency().String(),
+			Description:       description,
+			RequiredApprovals: requiredApprovals,
+		}
+		if target := cmd.ExternalTarget; target != nil {
+			create.BankAccountNumber = target.BankAccountNumber
+			create.RoutingNumber = target.RoutingNumber
+			create.BIC = target.BIC
+			create.ExternalWalletAddress = target.ExternalWalletAddress
+			create.Network = target.Network
+		}
+		if err := pwRepo.Create(ctx, create); err != nil {
+			return fmt.Errorf("failed to create pending withdrawal: %w", err)
+		}
+		result, err = pwRepo.Get(ctx, create.ID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Withdrawal held for approval",
+		"pending_withdrawal_id", result.ID,
+		"user_id", result.UserID,
+		"account_id", result.AccountID,
+		"amount", amount.String(),
+		"required_approvals", result.RequiredApprovals,
+	)
+	s.emitWithdrawEvent(ctx, withdrawPendingOf(result))
+	return result, nil
+}
+
+// ListPendingWithdrawals returns the withdrawals awaiting approval, or those
+// in the given status (e.g. dto.PendingWithdrawalRejected). It performs no
+// ownership checks; callers must restrict it to approvers.
+func (s *Service) ListPendingWithdrawals(
+	ctx context.Context,
+	status string,
+) ([]*dto.PendingWithdrawalRead, error) {
+	repo, err := getPendingWithdrawalRepository(s.uow)
+	if err != nil {
+		return nil, err
+	}
+	return repo.List(ctx, status)
+}
+
+// ApproveWithdrawal records approverID's approval of a held withdrawal. Once
+// the withdrawal has its required number of approvals it is started, with
+// its hold transaction as its debit so the held funds never return to the
+// account in between, and WithdrawApproved is emitted. If the withdrawal
+// fails, including failing to start, the hold is released. The user who
+// requested the withdrawal cannot approve it (account.ErrSelfApproval), and
+// an approver counts once (account.ErrAlreadyApproved). Callers must
+// restrict it to approvers.
+func (s *Service) ApproveWithdrawal(
+	ctx context.Context,
+	approverID, id uuid.UUID,
+) (*dto.PendingWithdrawalRead, error) {
+	var result *dto.PendingWithdrawalRead
+	err := s.uow.Do(ctx, func(uow repository.UnitOfWork) error {
+		pwRepo, err := getPendingWithdrawalRepository(uow)
+		if err != nil {
+			return err
+		}
+		pw, err := pwRepo.Get(ctx, id)
+		if err != nil {
+			return err
+		}
+		if pw.UserID == approverID {
+			return account.ErrSelfApproval
+		}
+		if pw.Status != dto.PendingWithdrawalPending {
+			return account.ErrPendingWithdrawalNotPending
+		}
+		if slices.Contains(pw.Approvals, approverID) {
+			return account.ErrAlreadyApproved
+		}
+		approvals, ok, err := pwRepo.Approve(ctx, id, approverID)
+		if err != nil {
+			return fmt.Errorf("failed to approve pending withdrawal: %w", err)
+		}
+		if !ok {
+			return account.ErrPendingWithdrawalNotPending
+		}
+		if len(approvals) >= pw.RequiredApprovals {
+			if err := s.settleWithdrawal(
+				ctx, uow, pw, dto.PendingWithdrawalApproved, approverID, "",
+			); err != nil {
+				return err
+			}
+		}
+		result, err = pwRepo.Get(ctx, id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.logger.Info("Pending withdrawal approved",
+		"pending_withdrawal_id", id,
+		"approved_by", approverID,
+		"approvals", len(result.Approvals),
+		"required_approvals", result.RequiredApprovals,
+		"status", result.Status,
+	)
+	if result.Status != dto.PendingWithdrawalApproved {
+		return result, nil
+	}
+
+	// The withdrawal pays out the held amount, already in the account
+	// currency.
+	hold, err := money.New(result.HoldAmount, money.Code(result.HoldCurrency))
+	if err != nil {
+		return nil, err
+	}
+	if err := s.emitWithdrawRequested(
+		ctx,
+		result.UserID,
+		result.AccountID,
+		hold,
+		result.Description,
+		&commands.ExternalTarget{
+			BankAccountNumber:     result.BankAccountNumber,
+			RoutingNumber:         result.RoutingNumber,
+			BIC:                   result.BIC,
+			ExternalWalletAddress: result.ExternalWalletAddress,
+			Network:               result.Network,
+		},
+		events.WithWithdrawHold(result.TransactionID),
+	); err != nil {
+		s.releaseWithdrawalHold(ctx, result)
+		return nil, fmt.Errorf("failed to start approved withdrawal: %w", err)
+	}
+	s.emitWithdrawEvent(ctx, events.NewWithdrawApproved(
+		withdrawPendingOf(result), result.Approvals,
+	))
+	return result, nil
+}
+
+// RejectWithdrawal returns the held amount of a withdrawal to the account
+// and emits WithdrawRejected. The hold transaction is marked canceled, so the
+// balance and ledger are as if the withdrawal had never been requested.
+// Callers must restrict it to approvers.
+func (s *Service) RejectWithdrawal(
+	ctx context.Context,
+	approverID, id uuid.UUID,
+	reason string,
+) (*dto.PendingWithdrawalRead, error) {
+	var result *dto.PendingWithdrawalRead
+	err := s.uow.Do(ctx, func(uow repository.UnitOfWork) error {
+		pwRepo, err := getPendingWithdrawalRepository(uow)
+		if err != nil {
+			return err
+		}
+		pw, err := pwRepo.Get(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := s.settleWithdrawal(
+			ctx, uow, pw, dto.PendingWithdrawalRejected, approverID, reason,
+		); err != nil {
+			return err
+		}
+		result, err = pwRepo.Get(ctx, id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.logger.Info("Pending withdrawal rejected",
+		"pending_withdrawal_id", id,
+		"rejected_by", approverID,
+	)
+	s.emitWithdrawEvent(ctx, events.NewWithdrawRejected(
+		withdrawPendingOf(result), approverID, reason,
+	))
+	return result, nil
+}
+
+// settleWithdrawal moves a pending withdrawal to status, approved or
+// rejected. A rejected withdrawal's hold is released in the same unit of
+// work; an approved one's stays as the withdrawal's debit.
+func (s *Service) settleWithdrawal(
+	ctx context.Context,
+	uow repository.UnitOfWork,
+	pw *dto.PendingWithdrawalRead,
+	status string,
+	decidedBy uuid.UUID,
+	reason string,
+) error {
+	accRepo, err := common.GetAccountRepository(uow, s.logger)
+	if err != nil {
+		return err
+	}
+	txRepo, err := common.GetTransactionRepository(uow, s.logger)
+	if err != nil {
+		return err
+	}
+	pwRepo, err := getPendingWithdrawalRepository(uow)
+	if err != nil {
+		return err
+	}
+
+	// Deciding first makes concurrent decisions on the same withdrawal wait
+	// for each other; only one of them finds it still pending.
+	ok, err := pwRepo.Decide(ctx, pw.ID, status, decidedBy, reason)
+	if err != nil {
+		return fmt.Errorf("failed to update pending withdrawal: %w", err)
+	}
+	if !ok {
+		return account.ErrPendingWithdrawalNotPending
+	}
+	if status != dto.PendingWithdrawalRejected {
+		return nil
+	}
+	hold, err := money.New(pw.HoldAmount, money.Code(pw.HoldCurrency))
+	if err != nil {
+		return err
+	}
+	acc, err := accRepo.GetForUpdate(ctx, pw.AccountID)
+	if err != nil {
+		return fmt.Errorf("account: %w", err)
+	}
+	if err := credit(ctx, accRepo, acc, hold); err != nil {
+		return fmt.Errorf("failed to release withdrawal hold: %w", err)
+	}
+	canceled := string(account.TransactionStatusCanceled)
+	if err := txRepo.Update(
+		ctx, pw.TransactionID, dto.TransactionUpdate{Status: &canceled},
+	); err != nil {
+		return fmt.Errorf("failed to update hold transaction: %w", err)
+	}
+	return nil
+}
+
+// releaseWithdrawalHold releases the hold of an approved withdrawal that
+// could not be started. A failure is only logged: the hold then stays, and
+// the withdrawal's hold transaction shows it.
+func (s *Service) releaseWithdrawalHold(ctx context.Context, pw *dto.PendingWithdrawalRead) {
+	if err := s.uow.Do(ctx, func(uow repository.UnitOfWork) error {
+		_, err := common.ReleaseWithdrawalHold(ctx, uow, pw.TransactionID, nil, s.logger)
+		return err
+	}); err != nil {
+		s.logger.Error("failed to release withdrawal hold",
+			"pending_withdrawal_id", pw.ID,
+			"transaction_id", pw.TransactionID,
+			"error", err,
+		)
+	}
+}
+
+// emitWithdrawEvent emits an event of a held withdrawal. The money has
+// already moved by then, so a failure is only logged.
+func (s *Service) emitWithdrawEvent(ctx context.Context, e events.Event) {
+	if s.bus == nil {
+		return
+	}
+	if err := s.bus.Emit(ctx, e); err != nil {
+		s.logger.Error("failed to emit withdrawal event",
+			"event_type", e.Type(),
+			"error", err,
+		)
+	}
+}
+
+// withdrawPendingOf rebuilds the WithdrawPending event of a held withdrawal.
+func withdrawPendingOf(pw *dto.PendingWithdrawalRead) *events.WithdrawPending {
+	amount, err := money.New(pw.Amount, money.Code(pw.Currency))
+	if err != nil {
+		amount = money.Zero(money.Code(pw.Currency))
+	}
+	return events.NewWithdrawPending(
+		pw.UserID,
+		pw.AccountID,
+		pw.ID,
+		pw.TransactionID,
+		amount,
+		pw.Description,
+		pw.RequiredApprovals,
+	)
+}
+
+func getPendingWithdrawalRepository(
+	uow repository.UnitOfWork,
+) (pendingwithdrawal.Repository, error) {
+	repoAny, err := uow.GetRepository((*pendingwithdrawal.Repository)(nil))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pending withdrawal repository: %w", err)
+	}
+	repo, ok := repoAny.(pendingwithdrawal.Repository)
+	if !ok {
+		return nil, fmt.Errorf("unexpected pending withdrawal repository type %T", repoAny)
+	}
+	return repo, nil
+}
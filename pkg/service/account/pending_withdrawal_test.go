@@ -0,0 +1,393 @@
+package account_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/amirasaad/fintech/infra/eventbus"
+	"github.com/amirasaad/fintech/internal/fixtures/mocks"
+	"github.com/amirasaad/fintech/pkg/commands"
+	"github.com/amirasaad/fintech/pkg/config"
+	"github.com/amirasaad/fintech/pkg/domain"
+	accountdomain "github.com/amirasaad/fintech/pkg/domain/account"
+	"github.com/amirasaad/fintech/pkg/domain/events"
+	"github.com/amirasaad/fintech/pkg/dto"
+	"github.com/amirasaad/fintech/pkg/repository"
+	"github.com/amirasaad/fintech/pkg/repository/pendingwithdrawal"
+	"github.com/amirasaad/fintech/pkg/repository/transaction"
+	repouser "github.com/amirasaad/fintech/pkg/repository/user"
+	accountsvc "github.com/amirasaad/fintech/pkg/service/account"
+	"github.com/amirasaad/fintech/pkg/service/stripeconnect"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// fakePendingWithdrawalRepo is an in-memory pendingwithdrawal.Repository.
+type fakePendingWithdrawalRepo struct {
+	items map[uuid.UUID]*dto.PendingWithdrawalRead
+}
+
+func (r *fakePendingWithdrawalRepo) Create(
+	_ context.Context,
+	create dto.PendingWithdrawalCreate,
+) error {
+	r.items[create.ID] = &dto.PendingWithdrawalRead{
+		ID:                create.ID,
+		UserID:            create.UserID,
+		AccountID:         create.AccountID,
+		TransactionID:     create.TransactionID,
+		Amount:            float64(create.Amount) / 100,
+		Currency:          create.Currency,
+		HoldAmount:        float64(create.HoldAmount) / 100,
+		HoldCurrency:      create.HoldCurrency,
+		Description:       create.Description,
+		BankAccountNumber: create.BankAccountNumber,
+		RequiredApprovals: create.RequiredApprovals,
+		Status:            dto.PendingWithdrawalPending,
+		CreatedAt:         time.Now(),
+	}
+	return nil
+}
+
+func (r *fakePendingWithdrawalRepo) Get(
+	_ context.Context,
+	id uuid.UUID,
+) (*dto.PendingWithdrawalRead, error) {
+	pw, ok := r.items[id]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	out := *pw
+	out.Approvals = slices.Clone(pw.Approvals)
+	return &out, nil
+}
+
+func (r *fakePendingWithdrawalRepo) GetByTransactionID(
+	ctx context.Context,
+	transactionID uuid.UUID,
+) (*dto.PendingWithdrawalRead, error) {
+	for id, pw := range r.items {
+		if pw.TransactionID == transactionID {
+			return r.Get(ctx, id)
+		}
+	}
+	return nil, domain.ErrNotFound
+}
+
+func (r *fakePendingWithdrawalRepo) List(
+	_ context.Context,
+	status string,
+) ([]*dto.PendingWithdrawalRead, error) {
+	var out []*dto.PendingWithdrawalRead
+	for _, pw := range r.items {
+		if status == "" || pw.Status == status {
+			out = append(out, pw)
+		}
+	}
+	return out, nil
+}
+
+func (r *fakePendingWithdrawalRepo) Approve(
+	_ context.Context,
+	id uuid.UUID,
+	approverID uuid.UUID,
+) ([]uuid.UUID, bool, error) {
+	pw, ok := r.items[id]
+	if !ok || pw.Status != dto.PendingWithdrawalPending {
+		return nil, false, nil
+	}
+	if !slices.Contains(pw.Approvals, approverID) {
+		pw.Approvals = append(pw.Approvals, approverID)
+	}
+	return slices.Clone(pw.Approvals), true, nil
+}
+
+func (r *fakePendingWithdrawalRepo) Decide(
+	_ context.Context,
+	id uuid.UUID,
+	status string,
+	decidedBy uuid.UUID,
+	reason string,
+) (bool, error) {
+	pw, ok := r.items[id]
+	if !ok || pw.Status != dto.PendingWithdrawalPending {
+		return false, nil
+	}
+	pw.Status = status
+	pw.DecidedBy = &decidedBy
+	pw.Reason = reason
+	return true, nil
+}
+
+var _ pendingwithdrawal.Repository = (*fakePendingWithdrawalRepo)(nil)
+
+// pendingWithdrawalFixture holds a USD account whose balance, in cents, is
+// kept up to date by the account repository mock, and counts the withdrawal
+// events emitted.
+type pendingWithdrawalFixture struct {
+	svc       *accountsvc.Service
+	userID    uuid.UUID
+	accountID uuid.UUID
+	balance   int64
+	txs       map[uuid.UUID]dto.TransactionCreate
+	pwRepo    *fakePendingWithdrawalRepo
+	emitted   map[events.EventType]int
+	// started is the last WithdrawRequested emitted, and startErr the
+	// error emitting it fails with, if any.
+	started  *events.WithdrawRequested
+	startErr error
+}
+
+// withdrawalBus is a memory bus on which emitting WithdrawRequested fails
+// with the fixture's startErr.
+type withdrawalBus struct {
+	*eventbus.MemoryEventBus
+	f *pendingWithdrawalFixture
+}
+
+func (b withdrawalBus) Emit(ctx context.Context, e events.Event) error {
+	if wr, ok := e.(*events.WithdrawRequested); ok {
+		if b.f.startErr != nil {
+			return b.f.startErr
+		}
+		b.f.started = wr
+	}
+	return b.MemoryEventBus.Emit(ctx, e)
+}
+
+// newPendingWithdrawalFixture builds the fixture with withdrawals above
+// $100 requiring the given number of approvals.
+func newPendingWithdrawalFixture(t *testing.T, requiredApprovals int) *pendingWithdrawalFixture {
+	f := &pendingWithdrawalFixture{
+		userID:    uuid.New(),
+		accountID: uuid.New(),
+		balance:   100000,
+		txs:       map[uuid.UUID]dto.TransactionCreate{},
+		pwRepo:    &fakePendingWithdrawalRepo{items: map[uuid.UUID]*dto.PendingWithdrawalRead{}},
+		emitted:   map[events.EventType]int{},
+	}
+
+	userRepo := mocks.NewUserRepository(t)
+	userRepo.EXPECT().GetStripeOnboardingStatus(mock.Anything, f.userID).
+		Return(true, nil).Maybe()
+
+	accountRepo := mocks.NewAccountRepository(t)
+	getAccount := func(_ context.Context, id uuid.UUID) (*dto.AccountRead, error) {
+		return &dto.AccountRead{
+			ID:       id,
+			UserID:   f.userID,
+			Balance:  float64(f.balance) / 100,
+			Currency: "USD",
+			Status:   "active",
+		}, nil
+	}
+	accountRepo.EXPECT().Get(mock.Anything, f.accountID).RunAndReturn(getAccount).Maybe()
+	accountRepo.EXPECT().GetForUpdate(mock.Anything, f.accountID).RunAndReturn(getAccount).Maybe()
+	accountRepo.EXPECT().Update(mock.Anything, f.accountID, mock.Anything).RunAndReturn(
+		func(_ context.Context, _ uuid.UUID, update dto.AccountUpdate) error {
+			f.balance = *update.Balance
+			return nil
+		},
+	).Maybe()
+
+	txRepo := mocks.NewTransactionRepository(t)
+	txRepo.EXPECT().Create(mock.Anything, mock.Anything).RunAndReturn(
+		func(_ context.Context, tx dto.TransactionCreate) error {
+			f.txs[tx.ID] = tx
+			return nil
+		},
+	).Maybe()
+	txRepo.EXPECT().Get(mock.Anything, mock.Anything).RunAndReturn(
+		func(_ context.Context, id uuid.UUID) (*dto.TransactionRead, error) {
+			tx := f.txs[id]
+			return &dto.TransactionRead{
+				ID:          tx.ID,
+				AccountID:   tx.AccountID,
+				Amount:      float64(tx.Amount) / 100,
+				Currency:    tx.Currency,
+				Status:      tx.Status,
+				MoneySource: tx.MoneySource,
+			}, nil
+		},
+	).Maybe()
+	txRepo.EXPECT().Update(mock.Anything, mock.Anything, mock.Anything).RunAndReturn(
+		func(_ context.Context, id uuid.UUID, update dto.TransactionUpdate) error {
+			tx := f.txs[id]
+			tx.Status = *update.Status
+			f.txs[id] = tx
+			return nil
+		},
+	).Maybe()
+
+	uow := mocks.NewUnitOfWork(t)
+	uow.EXPECT().Do(mock.Anything, mock.Anything).RunAndReturn(
+		func(ctx context.Context, fn func(repository.UnitOfWork) error) error {
+			return fn(uow)
+		},
+	).Maybe()
+	uow.EXPECT().GetRepository(mock.Anything).RunAndReturn(
+		func(repoType any) (any, error) {
+			switch repoType.(type) {
+			case *pendingwithdrawal.Repository:
+				return f.pwRepo, nil
+			case *transaction.Repository:
+				return txRepo, nil
+			case *repouser.Repository:
+				return userRepo, nil
+			}
+			return accountRepo, nil
+		},
+	).Maybe()
+
+	bus := withdrawalBus{MemoryEventBus: eventbus.NewWithMemory(slog.Default()), f: f}
+	for _, et := range []events.EventType{
+		events.EventTypeWithdrawRequested,
+		events.EventTypeWithdrawPending,
+		events.EventTypeWithdrawApproved,
+		events.EventTypeWithdrawRejected,
+	} {
+		bus.Register(et, func(_ context.Context, e events.Event) error {
+			f.emitted[events.EventType(e.Type())]++
+			return nil
+		})
+	}
+	f.svc = accountsvc.New(
+		bus, uow, slog.Default(),
+		stripeconnect.New(uow, slog.Default(), &config.Stripe{}),
+		accountsvc.WithWithdrawalApproval(&config.Withdraw{
+			ApprovalThreshold: 100,
+			ApprovalCurrency:  "USD",
+			RequiredApprovals: requiredApprovals,
+		}),
+	)
+	return f
+}
+
+func (f *pendingWithdrawalFixture) withdraw(t *testing.T, amount float64) {
+	t.Helper()
+	require.NoError(t, f.svc.Withdraw(context.Background(), commands.Withdraw{
+		UserID:    f.userID,
+		AccountID: f.accountID,
+		Amount:    amount,
+		Currency:  "USD",
+		ExternalTarget: &commands.ExternalTarget{
+			BankAccountNumber: "1234567890",
+		},
+	}))
+}
+
+// held returns the only pending withdrawal.
+func (f *pendingWithdrawalFixture) held(t *testing.T) *dto.PendingWithdrawalRead {
+	t.Helper()
+	require.Len(t, f.pwRepo.items, 1)
+	for _, pw := range f.pwRepo.items {
+		return pw
+	}
+	return nil
+}
+
+func TestWithdraw_ApprovalThreshold(t *testing.T) {
+	f := newPendingWithdrawalFixture(t, 1)
+
+	f.withdraw(t, 100)
+	assert.Empty(t, f.pwRepo.items, "a withdrawal at the threshold is not held")
+	assert.Equal(t, 1, f.emitted[events.EventTypeWithdrawRequested])
+
+	f.withdraw(t, 250)
+	pw := f.held(t)
+	assert.Equal(t, dto.PendingWithdrawalPending, pw.Status)
+	assert.Equal(t, "7890", pw.Last4())
+	// The funds are held right away; the withdrawal waits for approval.
+	assert.Equal(t, int64(75000), f.balance)
+	assert.Equal(t, int64(-25000), f.txs[pw.TransactionID].Amount)
+	assert.Equal(t, 1, f.emitted[events.EventTypeWithdrawRequested])
+	assert.Equal(t, 1, f.emitted[events.EventTypeWithdrawPending])
+}
+
+func TestApproveWithdrawal(t *testing.T) {
+	f := newPendingWithdrawalFixture(t, 2)
+	f.withdraw(t, 250)
+	pw := f.held(t)
+	ctx := context.Background()
+
+	_, err := f.svc.ApproveWithdrawal(ctx, f.userID, pw.ID)
+	require.ErrorIs(t, err, accountdomain.ErrSelfApproval)
+
+	first := uuid.New()
+	got, err := f.svc.ApproveWithdrawal(ctx, first, pw.ID)
+	require.NoError(t, err)
+	assert.Equal(t, dto.PendingWithdrawalPending, got.Status)
+	assert.Equal(t, []uuid.UUID{first}, got.Approvals)
+
+	_, err = f.svc.ApproveWithdrawal(ctx, first, pw.ID)
+	require.ErrorIs(t, err, accountdomain.ErrAlreadyApproved)
+	assert.Equal(t, 0, f.emitted[events.EventTypeWithdrawRequested])
+	assert.Equal(t, int64(75000), f.balance)
+
+	second := uuid.New()
+	got, err = f.svc.ApproveWithdrawal(ctx, second, pw.ID)
+	require.NoError(t, err)
+	assert.Equal(t, dto.PendingWithdrawalApproved, got.Status)
+	assert.Equal(t, second, *got.DecidedBy)
+	// The hold stays as the withdrawal's debit, so the held funds cannot be
+	// spent before the withdrawal starts from it.
+	assert.Equal(t, int64(75000), f.balance)
+	assert.Equal(t,
+		string(accountdomain.TransactionStatusCompleted),
+		f.txs[pw.TransactionID].Status,
+	)
+	assert.Equal(t, 1, f.emitted[events.EventTypeWithdrawRequested])
+	require.NotNil(t, f.started)
+	assert.Equal(t, pw.TransactionID, f.started.HoldTransactionID)
+	assert.Equal(t, "250.00 USD", f.started.Amount.String())
+	assert.Equal(t, 1, f.emitted[events.EventTypeWithdrawApproved])
+
+	_, err = f.svc.ApproveWithdrawal(ctx, uuid.New(), pw.ID)
+	require.ErrorIs(t, err, accountdomain.ErrPendingWithdrawalNotPending)
+	_, err = f.svc.RejectWithdrawal(ctx, uuid.New(), pw.ID, "")
+	require.ErrorIs(t, err, accountdomain.ErrPendingWithdrawalNotPending)
+}
+
+func TestApproveWithdrawal_ReleasesHoldIfNotStarted(t *testing.T) {
+	f := newPendingWithdrawalFixture(t, 1)
+	f.withdraw(t, 250)
+	pw := f.held(t)
+	f.startErr = errors.New("bus unavailable")
+
+	_, err := f.svc.ApproveWithdrawal(context.Background(), uuid.New(), pw.ID)
+	require.ErrorIs(t, err, f.startErr)
+	assert.Equal(t, int64(100000), f.balance)
+	assert.Equal(t,
+		string(accountdomain.TransactionStatusFailed),
+		f.txs[pw.TransactionID].Status,
+	)
+	assert.Equal(t, 0, f.emitted[events.EventTypeWithdrawApproved])
+}
+
+func TestRejectWithdrawal(t *testing.T) {
+	f := newPendingWithdrawalFixture(t, 2)
+	f.withdraw(t, 250)
+	pw := f.held(t)
+	ctx := context.Background()
+
+	_, err := f.svc.ApproveWithdrawal(ctx, uuid.New(), pw.ID)
+	require.NoError(t, err)
+	got, err := f.svc.RejectWithdrawal(ctx, uuid.New(), pw.ID, "unusual destination")
+	require.NoError(t, err)
+	assert.Equal(t, dto.PendingWithdrawalRejected, got.Status)
+	assert.Equal(t, "unusual destination", got.Reason)
+	// The hold is released and the withdrawal never starts.
+	assert.Equal(t, int64(100000), f.balance)
+	assert.Equal(t,
+		string(accountdomain.TransactionStatusCanceled),
+		f.txs[pw.TransactionID].Status,
+	)
+	assert.Equal(t, 0, f.emitted[events.EventTypeWithdrawRequested])
+	assert.Equal(t, 1, f.emitted[events.EventTypeWithdrawRejected])
+}
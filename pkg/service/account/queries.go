@@ -2,8 +2,13 @@ package account
 
 import (
 	"context"
+	"fmt"
 
+	accountdomain "github.com/amirasaad/fintech/pkg/domain/account"
 	"github.com/amirasaad/fintech/pkg/dto"
+	"github.com/amirasaad/fintech/pkg/money"
+	"github.com/amirasaad/fintech/pkg/provider/exchange"
+	"github.com/amirasaad/fintech/pkg/repository"
 	repoaccount "github.com/amirasaad/fintech/pkg/repository/account"
 	transactionrepo "github.com/amirasaad/fintech/pkg/repository/transaction"
 	"github.com/google/uuid"
@@ -26,10 +31,15 @@ func (s *Service) GetAccount(
 		return
 	}
 	account, err = repo.Get(ctx, accountID)
+	if err == nil && account != nil {
+		account.InterestAPY = s.interestAPY(account.Type)
+	}
 	return
 }
 
-// GetTransactions retrieves all transactions for a specific account.
+// GetTransactions retrieves all transactions for a specific account. With a
+// transaction cache it reads them through the cache, falling back to the
+// database when the cache fails.
 func (s *Service) GetTransactions(
 	ctx context.Context,
 	userID, accountID uuid.UUID,
@@ -60,16 +70,49 @@ func (s *Service) GetTransactions(
 	if !ok {
 		return
 	}
-	transactions, err = transactionRepo.ListByAccount(ctx, accountID)
+	if s.txCache == nil {
+		transactions, err = transactionRepo.ListByAccount(ctx, accountID)
+		return
+	}
+
+	cached, version, ok, cacheErr := s.txCache.Get(ctx, accountID)
+	if cacheErr != nil {
+		s.logger.Warn(
+			"failed to read cached transactions",
+			"account_id", accountID,
+			"error", cacheErr,
+		)
+	}
+	if ok {
+		return cached, nil
+	}
+	// Fill the cache from the primary: a lagging replica would cache stale
+	// transactions under the current version.
+	transactions, err = transactionRepo.ListByAccount(
+		repository.WithoutReplicaReads(ctx),
+		accountID,
+	)
+	if err != nil || cacheErr != nil {
+		return
+	}
+	if cacheErr = s.txCache.Set(ctx, accountID, version, transactions); cacheErr != nil {
+		s.logger.Warn(
+			"failed to cache transactions",
+			"account_id", accountID,
+			"error", cacheErr,
+		)
+	}
 	return
 }
 
-// GetBalance retrieves the current balance of an account for the specified user.
+// GetBalance retrieves the exact current balance of an account for the
+// specified user. It returns account.ErrAccountNotFound if the account
+// belongs to another user.
 func (s *Service) GetBalance(
 	ctx context.Context,
 	userID, accountID uuid.UUID,
 ) (
-	balance float64,
+	balance *money.Money,
 	err error,
 ) {
 	repoAny, err := s.uow.GetRepository((*repoaccount.Repository)(nil))
@@ -86,9 +129,32 @@ func (s *Service) GetBalance(
 	}
 
 	if acc.UserID != userID {
+		err = accountdomain.ErrAccountNotFound
 		return
 	}
 
-	balance = acc.Balance
+	balance = acc.BalanceMoney
 	return
 }
+
+// WithCurrencyConverter sets the converter ConvertBalance displays balances
+// in other currencies with.
+func WithCurrencyConverter(converter CurrencyConverter) Option {
+	return func(s *Service) { s.converter = converter }
+}
+
+// ConvertBalance converts balance into the display currency to. It returns
+// exchange.ErrUnsupportedPair if the service has no converter.
+func (s *Service) ConvertBalance(
+	ctx context.Context,
+	balance *money.Money,
+	to money.Code,
+) (*money.Money, *exchange.RateInfo, error) {
+	if s.converter == nil {
+		return nil, nil, fmt.Errorf(
+			"%w: no converter for %s to %s",
+			exchange.ErrUnsupportedPair, balance.Currency(), to,
+		)
+	}
+	return s.converter.Convert(ctx, balance, to)
+}
@@ -0,0 +1,216 @@
+package account
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/amirasaad/fintech/pkg/domain/account"
+	"github.com/amirasaad/fintech/pkg/dto"
+	"github.com/amirasaad/fintech/pkg/handler/common"
+	"github.com/amirasaad/fintech/pkg/money"
+	"github.com/google/uuid"
+)
+
+const (
+	defaultReceiptTTL = 7 * 24 * time.Hour
+	// receiptTokenPurpose is signed with every receipt token so the key
+	// signs nothing else that could be passed off as one.
+	receiptTokenPurpose = "fintech/receipt/v1"
+	// receiptPayloadSize is a transaction ID and a Unix expiry time.
+	receiptPayloadSize = 16 + 8
+)
+
+// Receipt is a shareable summary of a completed transaction. Account and
+// Counterparty are masked.
+type Receipt struct {
+	TransactionID uuid.UUID
+	// Type is the transaction's money source, e.g. deposit or transfer.
+	Type   string
+	Status string
+	// Amount is the absolute amount; Direction is credit if it was added
+	// to the account and debit if it was taken from it.
+	Amount    *money.Money
+	Direction string
+	// Fee is the fee deducted from the account; nil if none.
+	Fee *money.Money
+	// ConvertedAmount is Amount in TargetCurrency for a transaction that
+	// was converted; nil otherwise.
+	ConvertedAmount *money.Money
+	Description     string
+	Account         string
+	// Counterparty is the card, bank account or wallet money came from or
+	// went to; empty if the transaction does not record one, as for
+	// transfers.
+	Counterparty string
+	CreatedAt    time.Time
+	// ExpiresAt is when the link the receipt was fetched with expires.
+	ExpiresAt time.Time
+}
+
+// WithReceipts signs receipt links with key, each working for ttl, or seven
+// days if ttl is not positive. Without it no receipt link can be created.
+func WithReceipts(key []byte, ttl time.Duration) Option {
+	return func(s *Service) {
+		if ttl <= 0 {
+			ttl = defaultReceiptTTL
+		}
+		s.receiptKey, s.receiptTTL = key, ttl
+	}
+}
+
+// ReceiptLink returns a token that shows the receipt of the user's completed
+// transaction to anyone who has it, until it expires. Tokens are not stored;
+// each call signs a new one.
+func (s *Service) ReceiptLink(
+	ctx context.Context,
+	userID, transactionID uuid.UUID,
+) (token string, expiresAt time.Time, err error) {
+	if len(s.receiptKey) == 0 {
+		return "", time.Time{}, fmt.Errorf("receipt links are not configured")
+	}
+	tx, err := s.receiptTransaction(ctx, transactionID)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	if tx.UserID != userID {
+		return "", time.Time{}, account.ErrTransactionNotFound
+	}
+	expiresAt = time.Now().UTC().Add(s.receiptTTL).Truncate(time.Second)
+	return s.signReceipt(transactionID, expiresAt), expiresAt, nil
+}
+
+// Receipt returns the receipt a token from ReceiptLink shows. It returns
+// account.ErrReceiptNotFound for a token that was not signed by the service
+// and account.ErrReceiptExpired for one that has expired.
+func (s *Service) Receipt(ctx context.Context, token string) (*Receipt, error) {
+	transactionID, expiresAt, err := s.verifyReceipt(token)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := s.receiptTransaction(ctx, transactionID)
+	if err != nil {
+		return nil, err
+	}
+	return newReceipt(tx, expiresAt), nil
+}
+
+// receiptTransaction returns the transaction, which must be completed.
+func (s *Service) receiptTransaction(
+	ctx context.Context,
+	transactionID uuid.UUID,
+) (*dto.TransactionRead, error) {
+	txRepo, err := common.GetTransactionRepository(s.uow, s.logger)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := txRepo.Get(ctx, transactionID)
+	if err != nil {
+		return nil, err
+	}
+	if tx == nil {
+		return nil, account.ErrTransactionNotFound
+	}
+	if tx.Status != string(account.TransactionStatusCompleted) {
+		return nil, account.ErrReceiptUnavailable
+	}
+	return tx, nil
+}
+
+// signReceipt returns a token for the receipt of transactionID that expires
+// at expiresAt: the transaction ID and expiry time, and their HMAC.
+func (s *Service) signReceipt(transactionID uuid.UUID, expiresAt time.Time) string {
+	payload := make([]byte, receiptPayloadSize)
+	copy(payload, transactionID[:])
+	binary.BigEndian.PutUint64(payload[16:], uint64(expiresAt.Unix()))
+	return base64.RawURLEncoding.EncodeToString(payload) + "." +
+		base64.RawURLEncoding.EncodeToString(s.receiptMAC(payload))
+}
+
+// verifyReceipt returns the transaction ID and expiry time of a token
+// signed by signReceipt.
+func (s *Service) verifyReceipt(token string) (uuid.UUID, time.Time, error) {
+	if len(s.receiptKey) == 0 {
+		return uuid.Nil, time.Time{}, account.ErrReceiptNotFound
+	}
+	encPayload, encMAC, ok := strings.Cut(token, ".")
+	if !ok {
+		return uuid.Nil, time.Time{}, account.ErrReceiptNotFound
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encPayload)
+	if err != nil || len(payload) != receiptPayloadSize {
+		return uuid.Nil, time.Time{}, account.ErrReceiptNotFound
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(encMAC)
+	if err != nil || !hmac.Equal(mac, s.receiptMAC(payload)) {
+		return uuid.Nil, time.Time{}, account.ErrReceiptNotFound
+	}
+	transactionID, _ := uuid.FromBytes(payload[:16])
+	expiresAt := time.Unix(int64(binary.BigEndian.Uint64(payload[16:])), 0).UTC()
+	if !time.Now().Before(expiresAt) {
+		return uuid.Nil, time.Time{}, account.ErrReceiptExpired
+	}
+	return transactionID, expiresAt, nil
+}
+
+func (s *Service) receiptMAC(payload []byte) []byte {
+	mac := hmac.New(sha256.New, s.receiptKey)
+	mac.Write([]byte(receiptTokenPurpose))
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
+
+// newReceipt summarizes tx for a receipt link that expires at expiresAt.
+func newReceipt(tx *dto.TransactionRead, expiresAt time.Time) *Receipt {
+	amount := tx.AmountMoney
+	if amount == nil {
+		amount, _ = money.New(tx.Amount, money.Code(tx.Currency))
+	}
+	r := &Receipt{
+		TransactionID: tx.ID,
+		Type:          tx.MoneySource,
+		Status:        tx.Status,
+		Amount:        amount,
+		Direction:     "credit",
+		Description:   tx.Description,
+		Account:       maskTail(tx.AccountID.String()),
+		CreatedAt:     tx.CreatedAt,
+		ExpiresAt:     expiresAt,
+	}
+	if amount != nil && amount.IsNegative() {
+		r.Amount, r.Direction = amount.Abs(), "debit"
+	}
+	if tx.Fee > 0 {
+		r.Fee, _ = money.New(tx.Fee, money.Code(tx.Currency))
+	}
+	if tx.TargetCurrency != "" && tx.TargetCurrency != tx.Currency && tx.ConvertedAmount != 0 {
+		r.ConvertedAmount, _ = money.New(tx.ConvertedAmount, money.Code(tx.TargetCurrency))
+		if r.ConvertedAmount != nil && r.ConvertedAmount.IsNegative() {
+			r.ConvertedAmount = r.ConvertedAmount.Abs()
+		}
+	}
+	switch {
+	case tx.PaymentMethod != nil && tx.PaymentMethod.Last4 != "":
+		name := tx.PaymentMethod.Brand
+		if name == "" {
+			name = tx.PaymentMethod.Type
+		}
+		r.Counterparty = strings.TrimSpace(name + " ****" + tx.PaymentMethod.Last4)
+	case tx.ExternalTargetMasked != "":
+		r.Counterparty = tx.ExternalTargetMasked
+	}
+	return r
+}
+
+// maskTail masks all but the last four characters of s, e.g. ****89ab.
+func maskTail(s string) string {
+	if len(s) <= 4 {
+		return "****"
+	}
+	return "****" + s[len(s)-4:]
+}
@@ -0,0 +1,157 @@
+package account_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/amirasaad/fintech/infra/eventbus"
+	"github.com/amirasaad/fintech/internal/fixtures/mocks"
+	accountdomain "github.com/amirasaad/fintech/pkg/domain/account"
+	"github.com/amirasaad/fintech/pkg/dto"
+	"github.com/amirasaad/fintech/pkg/repository"
+	"github.com/amirasaad/fintech/pkg/repository/transaction"
+	accountsvc "github.com/amirasaad/fintech/pkg/service/account"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReceipts(t *testing.T) {
+	userID, accountID := uuid.New(), uuid.New()
+	deposit := &dto.TransactionRead{
+		ID:              uuid.New(),
+		UserID:          userID,
+		AccountID:       accountID,
+		Amount:          100,
+		Currency:        "USD",
+		Fee:             2.5,
+		ConvertedAmount: 92,
+		TargetCurrency:  "EUR",
+		Status:          string(accountdomain.TransactionStatusCompleted),
+		MoneySource:     "deposit",
+		PaymentMethod:   &dto.PaymentMethod{Type: "card", Brand: "visa", Last4: "4242"},
+		CreatedAt:       time.Now().Add(-time.Hour),
+	}
+	withdrawal := &dto.TransactionRead{
+		ID:                   uuid.New(),
+		UserID:               userID,
+		AccountID:            accountID,
+		Amount:               -40,
+		Currency:             "USD",
+		Status:               string(accountdomain.TransactionStatusCompleted),
+		MoneySource:          "withdraw",
+		ExternalTargetMasked: "****6789",
+	}
+	pending := &dto.TransactionRead{
+		ID:       uuid.New(),
+		UserID:   userID,
+		Amount:   10,
+		Currency: "USD",
+		Status:   string(accountdomain.TransactionStatusPending),
+	}
+
+	newService := func(t *testing.T, key string, ttl time.Duration) *accountsvc.Service {
+		uow := mocks.NewUnitOfWork(t)
+		txRepo := mocks.NewTransactionRepository(t)
+		uow.EXPECT().Do(mock.Anything, mock.Anything).RunAndReturn(
+			func(ctx context.Context, fn func(repository.UnitOfWork) error) error {
+				return fn(uow)
+			},
+		).Maybe()
+		uow.EXPECT().GetRepository(mock.Anything).RunAndReturn(
+			func(repoType any) (any, error) {
+				if _, ok := repoType.(*transaction.Repository); ok {
+					return txRepo, nil
+				}
+				return nil, errors.New("unexpected repository type")
+			},
+		).Maybe()
+		for _, tx := range []*dto.TransactionRead{deposit, withdrawal, pending} {
+			txRepo.EXPECT().Get(mock.Anything, tx.ID).Return(tx, nil).Maybe()
+		}
+		txRepo.EXPECT().Get(mock.Anything, mock.Anything).
+			Return(nil, accountdomain.ErrTransactionNotFound).Maybe()
+		return accountsvc.New(
+			eventbus.NewWithMemory(slog.Default()),
+			uow,
+			slog.Default(),
+			nil,
+			accountsvc.WithReceipts([]byte(key), ttl),
+		)
+	}
+	ctx := context.Background()
+
+	t.Run("link shows the masked receipt", func(t *testing.T) {
+		svc := newService(t, "secret", time.Hour)
+		token, expiresAt, err := svc.ReceiptLink(ctx, userID, deposit.ID)
+		require.NoError(t, err)
+		assert.WithinDuration(t, time.Now().Add(time.Hour), expiresAt, 2*time.Second)
+
+		r, err := svc.Receipt(ctx, token)
+		require.NoError(t, err)
+		assert.Equal(t, deposit.ID, r.TransactionID)
+		assert.Equal(t, "credit", r.Direction)
+		assert.Equal(t, "100.00", r.Amount.AmountString())
+		assert.Equal(t, "2.50", r.Fee.AmountString())
+		assert.Equal(t, "EUR", r.ConvertedAmount.CurrencyCode().String())
+		assert.Equal(t, "visa ****4242", r.Counterparty)
+		accountStr := accountID.String()
+		assert.Equal(t, "****"+accountStr[len(accountStr)-4:], r.Account)
+		assert.Equal(t, expiresAt, r.ExpiresAt)
+	})
+
+	t.Run("withdrawal is a debit to its payout target", func(t *testing.T) {
+		svc := newService(t, "secret", time.Hour)
+		token, _, err := svc.ReceiptLink(ctx, userID, withdrawal.ID)
+		require.NoError(t, err)
+		r, err := svc.Receipt(ctx, token)
+		require.NoError(t, err)
+		assert.Equal(t, "debit", r.Direction)
+		assert.Equal(t, "40.00", r.Amount.AmountString())
+		assert.Nil(t, r.Fee)
+		assert.Equal(t, "****6789", r.Counterparty)
+	})
+
+	t.Run("other users' transactions are not found", func(t *testing.T) {
+		svc := newService(t, "secret", time.Hour)
+		_, _, err := svc.ReceiptLink(ctx, uuid.New(), deposit.ID)
+		require.ErrorIs(t, err, accountdomain.ErrTransactionNotFound)
+	})
+
+	t.Run("incomplete transactions have no receipt", func(t *testing.T) {
+		svc := newService(t, "secret", time.Hour)
+		_, _, err := svc.ReceiptLink(ctx, userID, pending.ID)
+		require.ErrorIs(t, err, accountdomain.ErrReceiptUnavailable)
+	})
+
+	t.Run("tampered and foreign tokens are not found", func(t *testing.T) {
+		svc := newService(t, "secret", time.Hour)
+		token, _, err := svc.ReceiptLink(ctx, userID, deposit.ID)
+		require.NoError(t, err)
+
+		payload, mac, _ := strings.Cut(token, ".")
+		other, _, err := svc.ReceiptLink(ctx, userID, withdrawal.ID)
+		require.NoError(t, err)
+		otherPayload, _, _ := strings.Cut(other, ".")
+		for _, bad := range []string{"", "garbage", payload, otherPayload + "." + mac, token + "x"} {
+			_, err := svc.Receipt(ctx, bad)
+			require.ErrorIs(t, err, accountdomain.ErrReceiptNotFound, bad)
+		}
+
+		_, err = newService(t, "another secret", time.Hour).Receipt(ctx, token)
+		require.ErrorIs(t, err, accountdomain.ErrReceiptNotFound)
+	})
+
+	t.Run("expired links are gone", func(t *testing.T) {
+		svc := newService(t, "secret", time.Nanosecond)
+		token, _, err := svc.ReceiptLink(ctx, userID, deposit.ID)
+		require.NoError(t, err)
+		_, err = svc.Receipt(ctx, token)
+		require.ErrorIs(t, err, accountdomain.ErrReceiptExpired)
+	})
+}
@@ -0,0 +1,51 @@
+package account
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/amirasaad/fintech/pkg/domain"
+	"github.com/amirasaad/fintech/pkg/domain/account"
+	"github.com/amirasaad/fintech/pkg/repository"
+	"github.com/google/uuid"
+)
+
+// WithReferencePrefix sets the prefix of the human-friendly references given
+// to new accounts, such as FT in FT-7K3M-9QXD-2HAB. Existing references keep
+// the prefix they were created with.
+func WithReferencePrefix(prefix string) Option {
+	return func(s *Service) { s.referencePrefix = prefix }
+}
+
+// ResolveAccountID returns the ID of the account identified by ref, which is
+// either the account UUID or its human-friendly reference. It returns
+// account.ErrInvalidReference if ref is neither, and account.ErrAccountNotFound
+// if no account has the reference. It does not check who owns the account.
+func (s *Service) ResolveAccountID(ctx context.Context, ref string) (uuid.UUID, error) {
+	if id, err := uuid.Parse(ref); err == nil {
+		return id, nil
+	}
+	reference, err := account.ParseReference(ref)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	var id uuid.UUID
+	err = s.uow.Do(ctx, func(uow repository.UnitOfWork) error {
+		acctRepo, err := getAccountRepository(uow)
+		if err != nil {
+			return err
+		}
+		acc, err := acctRepo.GetByReference(ctx, reference)
+		if err != nil {
+			return err
+		}
+		id = acc.ID
+		return nil
+	})
+	if errors.Is(err, domain.ErrNotFound) {
+		return uuid.Nil, fmt.Errorf("%w: %s", account.ErrAccountNotFound, reference)
+	}
+	return id, err
+}
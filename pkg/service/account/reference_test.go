@@ -0,0 +1,100 @@
+package account_test
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/amirasaad/fintech/internal/fixtures/mocks"
+	"github.com/amirasaad/fintech/pkg/domain"
+	accountdomain "github.com/amirasaad/fintech/pkg/domain/account"
+	"github.com/amirasaad/fintech/pkg/dto"
+	"github.com/amirasaad/fintech/pkg/repository"
+	accountsvc "github.com/amirasaad/fintech/pkg/service/account"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateAccount_AssignsReference(t *testing.T) {
+	uow := mocks.NewUnitOfWork(t)
+	accountRepo := mocks.NewAccountRepository(t)
+	userID := uuid.New()
+	uow.EXPECT().Do(mock.Anything, mock.Anything).RunAndReturn(
+		func(ctx context.Context, fn func(repository.UnitOfWork) error) error {
+			return fn(uow)
+		},
+	).Once()
+	uow.EXPECT().GetRepository(mock.Anything).Return(accountRepo, nil).Once()
+	accountRepo.EXPECT().ListByUser(mock.Anything, userID).Return(nil, nil).Once()
+
+	var reference string
+	accountRepo.EXPECT().Create(mock.Anything, mock.Anything).RunAndReturn(
+		func(ctx context.Context, create dto.AccountCreate) error {
+			reference = create.Reference
+			return nil
+		},
+	).Once()
+	accountRepo.EXPECT().Get(mock.Anything, mock.Anything).Return(&dto.AccountRead{}, nil).Once()
+
+	svc := accountsvc.New(nil, uow, slog.Default(), nil, accountsvc.WithReferencePrefix("FT"))
+	_, err := svc.CreateAccount(context.Background(), dto.AccountCreate{UserID: userID})
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(reference, "FT-"), reference)
+	parsed, err := accountdomain.ParseReference(reference)
+	require.NoError(t, err)
+	assert.Equal(t, reference, parsed)
+}
+
+func TestResolveAccountID(t *testing.T) {
+	const reference = "FT-7K3M-9QXD-2HAB"
+	accountID := uuid.New()
+
+	setup := func(t *testing.T) (*accountsvc.Service, *mocks.AccountRepository) {
+		uow := mocks.NewUnitOfWork(t)
+		accountRepo := mocks.NewAccountRepository(t)
+		uow.EXPECT().Do(mock.Anything, mock.Anything).RunAndReturn(
+			func(ctx context.Context, fn func(repository.UnitOfWork) error) error {
+				return fn(uow)
+			},
+		).Maybe()
+		uow.EXPECT().GetRepository(mock.Anything).Return(accountRepo, nil).Maybe()
+		return accountsvc.New(nil, uow, slog.Default(), nil), accountRepo
+	}
+
+	t.Run("passes UUIDs through", func(t *testing.T) {
+		svc, _ := setup(t)
+		got, err := svc.ResolveAccountID(context.Background(), accountID.String())
+		require.NoError(t, err)
+		assert.Equal(t, accountID, got)
+	})
+
+	t.Run("looks up references in canonical form", func(t *testing.T) {
+		svc, accountRepo := setup(t)
+		accountRepo.EXPECT().
+			GetByReference(mock.Anything, reference).
+			Return(&dto.AccountRead{ID: accountID}, nil).
+			Once()
+		got, err := svc.ResolveAccountID(context.Background(), "ft7k3m9qxd2hab")
+		require.NoError(t, err)
+		assert.Equal(t, accountID, got)
+	})
+
+	t.Run("reports unknown references as not found", func(t *testing.T) {
+		svc, accountRepo := setup(t)
+		accountRepo.EXPECT().
+			GetByReference(mock.Anything, reference).
+			Return(nil, domain.ErrNotFound).
+			Once()
+		_, err := svc.ResolveAccountID(context.Background(), reference)
+		require.ErrorIs(t, err, accountdomain.ErrAccountNotFound)
+	})
+
+	t.Run("rejects malformed references", func(t *testing.T) {
+		svc, _ := setup(t)
+		_, err := svc.ResolveAccountID(context.Background(), "FT-7K3M-9QXD-2HAC")
+		require.ErrorIs(t, err, accountdomain.ErrInvalidReference)
+	})
+}
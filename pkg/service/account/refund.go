@@ -0,0 +1,215 @@
+package account
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/amirasaad/fintech/pkg/domain/account"
+	"github.com/amirasaad/fintech/pkg/domain/events"
+	"github.com/amirasaad/fintech/pkg/dto"
+	"github.com/amirasaad/fintech/pkg/handler/common"
+	"github.com/amirasaad/fintech/pkg/mapper"
+	"github.com/amirasaad/fintech/pkg/money"
+	"github.com/amirasaad/fintech/pkg/provider/payment"
+	"github.com/amirasaad/fintech/pkg/repository"
+	"github.com/amirasaad/fintech/pkg/repository/transaction"
+	"github.com/google/uuid"
+)
+
+// moneySourceRefund is the money source of refund transactions.
+const moneySourceRefund = "refund"
+
+// WithRefunder enables deposit refunds through the given payment provider.
+func WithRefunder(refunder payment.Refunder) Option {
+	return func(s *Service) { s.refunder = refunder }
+}
+
+// RefundDeposit refunds amount, in the deposit's currency, of a completed
+// deposit back to the payment method that funded it. Partial refunds are
+// allowed as long as the total refunded, including pending refunds, does not
+// exceed the deposit.
+//
+// The refund is recorded as a pending transaction and submitted to the payment
+// provider; the account balance is debited once the provider reports the
+// refund as completed.
+func (s *Service) RefundDeposit(
+	ctx context.Context,
+	userID, transactionID uuid.UUID,
+	amount float64,
+) (*dto.TransactionRead, error) {
+	if s.refunder == nil {
+		return nil, payment.ErrRefundsNotSupported
+	}
+
+	var (
+		deposit *dto.TransactionRead
+		refund  dto.TransactionCreate
+		refAmt  *money.Money
+	)
+	err := s.uow.Do(ctx, func(uow repository.UnitOfWork) error {
+		txRepo, err := common.GetTransactionRepository(uow, s.logger)
+		if err != nil {
+			return err
+		}
+		accRepo, err := common.GetAccountRepository(uow, s.logger)
+		if err != nil {
+			return err
+		}
+
+		deposit, err = txRepo.Get(ctx, transactionID)
+		if err != nil {
+			return err
+		}
+		if deposit.UserID != userID {
+			return account.ErrTransactionNotFound
+		}
+		if deposit.MoneySource != moneySourceDeposit ||
+			deposit.Status != string(account.TransactionStatusCompleted) ||
+			deposit.PaymentID == nil || *deposit.PaymentID == "" {
+			return account.ErrTransactionNotRefundable
+		}
+
+		refAmt, err = money.New(amount, money.Code(deposit.Currency))
+		if err != nil {
+			return fmt.Errorf("invalid amount: %w", err)
+		}
+		if !refAmt.IsPositive() {
+			return account.ErrTransactionAmountMustBePositive
+		}
+		// Refunds of the deposit lock its account before adding up what is
+		// left to refund, so concurrent refunds see each other's.
+		acc, err := accRepo.GetForUpdate(ctx, deposit.AccountID)
+		if err != nil {
+			return err
+		}
+		remaining, err := refundableAmount(ctx, txRepo, deposit)
+		if err != nil {
+			return err
+		}
+		if exceeds, err := refAmt.GreaterThan(remaining); err != nil {
+			return err
+		} else if exceeds {
+			return fmt.Errorf(
+				"%w: %s requested, %s refundable",
+				account.ErrRefundExceedsDeposit,
+				refAmt,
+				remaining,
+			)
+		}
+
+		// The refunded money leaves the account, so it must still be there.
+		domainAcc, err := mapper.MapAccountReadToDomain(acc)
+		if err != nil {
+			return err
+		}
+		if err := domainAcc.ValidateWithdraw(userID, refAmt); err != nil {
+			return err
+		}
+
+		refund = dto.TransactionCreate{
+			ID:                    uuid.New(),
+			UserID:                userID,
+			AccountID:             deposit.AccountID,
+			Amount:                -int64(refAmt.Amount()),
+			Status:                string(account.TransactionStatusPending),
+			Currency:              deposit.Currency,
+			MoneySource:           moneySourceRefund,
+			RefundedTransactionID: &deposit.ID,
+		}
+		return txRepo.Create(ctx, refund)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.refunder.RefundPayment(ctx, &payment.RefundPaymentParams{
+		UserID:        userID,
+		AccountID:     deposit.AccountID,
+		TransactionID: refund.ID,
+		PaymentID:     *deposit.PaymentID,
+		Amount:        int64(refAmt.Amount()),
+		Currency:      deposit.Currency,
+	})
+	if err == nil && resp.Status == payment.PaymentFailed {
+		err = fmt.Errorf("refund %s was rejected by the payment provider", resp.RefundID)
+	}
+	if err != nil {
+		s.logger.Error("failed to refund deposit",
+			"transaction_id", deposit.ID,
+			"refund_transaction_id", refund.ID,
+			"error", err,
+		)
+		failed := string(account.TransactionStatusFailed)
+		s.updateRefund(ctx, refund.ID, dto.TransactionUpdate{Status: &failed})
+		return nil, fmt.Errorf("failed to refund deposit: %w", err)
+	}
+
+	s.updateRefund(ctx, refund.ID, dto.TransactionUpdate{PaymentID: &resp.RefundID})
+
+	if err := s.bus.Emit(ctx, events.NewRefundInitiated(
+		userID,
+		deposit.AccountID,
+		refund.ID,
+		deposit.ID,
+		resp.RefundID,
+		refAmt,
+	)); err != nil {
+		s.logger.Error("failed to emit RefundInitiated event", "error", err)
+	}
+
+	txRepo, err := common.GetTransactionRepository(s.uow, s.logger)
+	if err != nil {
+		return nil, err
+	}
+	return txRepo.Get(ctx, refund.ID)
+}
+
+// refundableAmount returns how much of deposit has not been refunded yet.
+// Pending refunds count as refunded, so with the account locked concurrent
+// refunds cannot overshoot.
+func refundableAmount(
+	ctx context.Context,
+	txRepo transaction.Repository,
+	deposit *dto.TransactionRead,
+) (*money.Money, error) {
+	remaining, err := money.New(deposit.Amount, money.Code(deposit.Currency))
+	if err != nil {
+		return nil, err
+	}
+	refunds, err := txRepo.ListRefunds(ctx, deposit.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list refunds: %w", err)
+	}
+	for _, r := range refunds {
+		if r.Status == string(account.TransactionStatusFailed) {
+			continue
+		}
+		refunded, err := money.New(r.Amount, money.Code(r.Currency))
+		if err != nil {
+			return nil, err
+		}
+		if remaining, err = remaining.Subtract(refunded.Abs()); err != nil {
+			return nil, err
+		}
+	}
+	return remaining, nil
+}
+
+// updateRefund records the outcome of submitting a refund. Failures are only
+// logged: the refund has already been submitted or rejected by then.
+func (s *Service) updateRefund(
+	ctx context.Context,
+	id uuid.UUID,
+	update dto.TransactionUpdate,
+) {
+	txRepo, err := common.GetTransactionRepository(s.uow, s.logger)
+	if err == nil {
+		err = txRepo.Update(ctx, id, update)
+	}
+	if err != nil {
+		s.logger.Error("failed to update refund transaction",
+			"transaction_id", id,
+			"error", err,
+		)
+	}
+}
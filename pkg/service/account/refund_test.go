@@ -0,0 +1,324 @@
+package account_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/amirasaad/fintech/infra/eventbus"
+	"github.com/amirasaad/fintech/internal/fixtures/mocks"
+	accountdomain "github.com/amirasaad/fintech/pkg/domain/account"
+	"github.com/amirasaad/fintech/pkg/domain/events"
+	"github.com/amirasaad/fintech/pkg/dto"
+	"github.com/amirasaad/fintech/pkg/provider/payment"
+	"github.com/amirasaad/fintech/pkg/repository"
+	repoaccount "github.com/amirasaad/fintech/pkg/repository/account"
+	"github.com/amirasaad/fintech/pkg/repository/transaction"
+	accountsvc "github.com/amirasaad/fintech/pkg/service/account"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeRefunder struct {
+	mu    sync.Mutex
+	calls []*payment.RefundPaymentParams
+	err   error
+}
+
+func (f *fakeRefunder) RefundPayment(
+	_ context.Context,
+	params *payment.RefundPaymentParams,
+) (*payment.RefundPaymentResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, params)
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &payment.RefundPaymentResponse{RefundID: "re_1", Status: payment.PaymentPending}, nil
+}
+
+func TestRefundDeposit(t *testing.T) {
+	userID, accountID := uuid.New(), uuid.New()
+	paymentID := "pi_1"
+	deposit := &dto.TransactionRead{
+		ID:          uuid.New(),
+		UserID:      userID,
+		AccountID:   accountID,
+		Amount:      100,
+		Currency:    "USD",
+		Status:      string(accountdomain.TransactionStatusCompleted),
+		MoneySource: "deposit",
+		PaymentID:   &paymentID,
+	}
+	withdrawal := &dto.TransactionRead{
+		ID:          uuid.New(),
+		UserID:      userID,
+		AccountID:   accountID,
+		Amount:      -20,
+		Currency:    "USD",
+		Status:      string(accountdomain.TransactionStatusCompleted),
+		MoneySource: "withdraw",
+	}
+	priorRefunds := []*dto.TransactionRead{
+		{Amount: -30, Currency: "USD", Status: string(accountdomain.TransactionStatusCompleted)},
+		{Amount: -50, Currency: "USD", Status: string(accountdomain.TransactionStatusFailed)},
+	}
+
+	setup := func(t *testing.T, refunder payment.Refunder) (
+		*accountsvc.Service,
+		*mocks.TransactionRepository,
+		*[]events.Event,
+	) {
+		uow := mocks.NewUnitOfWork(t)
+		txRepo := mocks.NewTransactionRepository(t)
+		accRepo := mocks.NewAccountRepository(t)
+		uow.EXPECT().Do(mock.Anything, mock.Anything).RunAndReturn(
+			func(ctx context.Context, fn func(repository.UnitOfWork) error) error {
+				return fn(uow)
+			},
+		).Maybe()
+		uow.EXPECT().GetRepository(mock.Anything).RunAndReturn(
+			func(repoType any) (any, error) {
+				switch repoType.(type) {
+				case *transaction.Repository:
+					return txRepo, nil
+				case *repoaccount.Repository:
+					return accRepo, nil
+				}
+				return nil, errors.New("unexpected repository type")
+			},
+		).Maybe()
+		txRepo.EXPECT().Get(mock.Anything, deposit.ID).Return(deposit, nil).Maybe()
+		txRepo.EXPECT().Get(mock.Anything, withdrawal.ID).Return(withdrawal, nil).Maybe()
+		txRepo.EXPECT().ListRefunds(mock.Anything, deposit.ID).Return(priorRefunds, nil).Maybe()
+		accRepo.EXPECT().GetForUpdate(mock.Anything, accountID).Return(&dto.AccountRead{
+			ID: accountID, UserID: userID, Balance: 100, Currency: "USD",
+		}, nil).Maybe()
+
+		bus := eventbus.NewWithMemory(slog.Default())
+		var emitted []events.Event
+		bus.Register(events.EventTypeRefundInitiated, func(_ context.Context, e events.Event) error {
+			emitted = append(emitted, e)
+			return nil
+		})
+		var opts []accountsvc.Option
+		if refunder != nil {
+			opts = append(opts, accountsvc.WithRefunder(refunder))
+		}
+		return accountsvc.New(bus, uow, slog.Default(), nil, opts...), txRepo, &emitted
+	}
+	ctx := context.Background()
+
+	t.Run("refunds part of the deposit", func(t *testing.T) {
+		refunder := &fakeRefunder{}
+		svc, txRepo, emitted := setup(t, refunder)
+		var refundID uuid.UUID
+		txRepo.EXPECT().Create(mock.Anything, mock.MatchedBy(func(c dto.TransactionCreate) bool {
+			refundID = c.ID
+			return c.Amount == -5000 &&
+				c.MoneySource == "refund" &&
+				c.Status == string(accountdomain.TransactionStatusPending) &&
+				*c.RefundedTransactionID == deposit.ID
+		})).Return(nil).Once()
+		txRepo.EXPECT().Update(mock.Anything, mock.Anything, mock.MatchedBy(
+			func(u dto.TransactionUpdate) bool {
+				return u.PaymentID != nil && *u.PaymentID == "re_1"
+			},
+		)).Return(nil).Once()
+		txRepo.EXPECT().Get(mock.Anything, mock.Anything).RunAndReturn(
+			func(_ context.Context, id uuid.UUID) (*dto.TransactionRead, error) {
+				return &dto.TransactionRead{ID: id, Amount: -50}, nil
+			},
+		).Once()
+
+		tx, err := svc.RefundDeposit(ctx, userID, deposit.ID, 50)
+		require.NoError(t, err)
+		assert.Equal(t, refundID, tx.ID)
+		require.Len(t, refunder.calls, 1)
+		assert.Equal(t, int64(5000), refunder.calls[0].Amount)
+		assert.Equal(t, paymentID, refunder.calls[0].PaymentID)
+		assert.Equal(t, refundID, refunder.calls[0].TransactionID)
+		require.Len(t, *emitted, 1)
+		ri := (*emitted)[0].(*events.RefundInitiated)
+		assert.Equal(t, deposit.ID, ri.RefundedTransactionID)
+		assert.Equal(t, "re_1", ri.RefundID)
+	})
+
+	t.Run("rejects refunds above the unrefunded amount", func(t *testing.T) {
+		refunder := &fakeRefunder{}
+		svc, _, _ := setup(t, refunder)
+		_, err := svc.RefundDeposit(ctx, userID, deposit.ID, 70.01)
+		require.ErrorIs(t, err, accountdomain.ErrRefundExceedsDeposit)
+		assert.Empty(t, refunder.calls)
+	})
+
+	t.Run("only refunds completed deposits", func(t *testing.T) {
+		svc, _, _ := setup(t, &fakeRefunder{})
+		_, err := svc.RefundDeposit(ctx, userID, withdrawal.ID, 10)
+		require.ErrorIs(t, err, accountdomain.ErrTransactionNotRefundable)
+	})
+
+	t.Run("hides other users' deposits", func(t *testing.T) {
+		svc, _, _ := setup(t, &fakeRefunder{})
+		_, err := svc.RefundDeposit(ctx, uuid.New(), deposit.ID, 10)
+		require.ErrorIs(t, err, accountdomain.ErrTransactionNotFound)
+	})
+
+	t.Run("marks the refund failed when the provider rejects it", func(t *testing.T) {
+		svc, txRepo, emitted := setup(t, &fakeRefunder{err: errors.New("declined")})
+		txRepo.EXPECT().Create(mock.Anything, mock.Anything).Return(nil).Once()
+		txRepo.EXPECT().Update(mock.Anything, mock.Anything, mock.MatchedBy(
+			func(u dto.TransactionUpdate) bool {
+				return u.Status != nil &&
+					*u.Status == string(accountdomain.TransactionStatusFailed)
+			},
+		)).Return(nil).Once()
+		_, err := svc.RefundDeposit(ctx, userID, deposit.ID, 10)
+		require.Error(t, err)
+		assert.Empty(t, *emitted)
+	})
+
+	t.Run("requires a provider that supports refunds", func(t *testing.T) {
+		svc, _, _ := setup(t, nil)
+		_, err := svc.RefundDeposit(ctx, userID, deposit.ID, 10)
+		require.ErrorIs(t, err, payment.ErrRefundsNotSupported)
+	})
+}
+
+func TestRefundDeposit_ConcurrentRefundsCannotOvershoot(t *testing.T) {
+	userID, accountID := uuid.New(), uuid.New()
+	paymentID := "pi_1"
+	deposit := &dto.TransactionRead{
+		ID:          uuid.New(),
+		UserID:      userID,
+		AccountID:   accountID,
+		Amount:      100,
+		Currency:    "USD",
+		Status:      string(accountdomain.TransactionStatusCompleted),
+		MoneySource: "deposit",
+		PaymentID:   &paymentID,
+	}
+
+	var (
+		mu      sync.Mutex
+		refunds []*dto.TransactionRead
+		row     sync.Mutex
+	)
+	txRepo := mocks.NewTransactionRepository(t)
+	txRepo.EXPECT().Get(mock.Anything, deposit.ID).Return(deposit, nil)
+	txRepo.EXPECT().ListRefunds(mock.Anything, deposit.ID).RunAndReturn(
+		func(context.Context, uuid.UUID) ([]*dto.TransactionRead, error) {
+			mu.Lock()
+			listed := slices.Clone(refunds)
+			mu.Unlock()
+			// Give a concurrent refund the time to slip in before this one
+			// is created, as it would without the lock.
+			time.Sleep(time.Millisecond)
+			return listed, nil
+		},
+	)
+	txRepo.EXPECT().Create(mock.Anything, mock.Anything).RunAndReturn(
+		func(_ context.Context, c dto.TransactionCreate) error {
+			mu.Lock()
+			defer mu.Unlock()
+			refunds = append(refunds, &dto.TransactionRead{
+				ID:       c.ID,
+				Amount:   float64(c.Amount) / 100,
+				Currency: c.Currency,
+				Status:   c.Status,
+			})
+			return nil
+		},
+	)
+	txRepo.EXPECT().Update(mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	txRepo.EXPECT().Get(mock.Anything, mock.Anything).RunAndReturn(
+		func(_ context.Context, id uuid.UUID) (*dto.TransactionRead, error) {
+			return &dto.TransactionRead{ID: id}, nil
+		},
+	)
+	accRepo := mocks.NewAccountRepository(t)
+	accRepo.EXPECT().Get(mock.Anything, accountID).Return(&dto.AccountRead{
+		ID: accountID, UserID: userID, Balance: 1000, Currency: "USD",
+	}, nil)
+
+	uow := mocks.NewUnitOfWork(t)
+	uow.EXPECT().Do(mock.Anything, mock.Anything).RunAndReturn(
+		func(ctx context.Context, fn func(repository.UnitOfWork) error) error {
+			unit := &rowLockingUnit{UnitOfWork: uow, row: &row, accRepo: accRepo, txRepo: txRepo}
+			defer unit.release()
+			return fn(unit)
+		},
+	)
+	uow.EXPECT().GetRepository((*transaction.Repository)(nil)).Return(txRepo, nil)
+
+	refunder := &fakeRefunder{}
+	svc := accountsvc.New(
+		eventbus.NewWithMemory(slog.Default()),
+		uow,
+		slog.Default(),
+		nil,
+		accountsvc.WithRefunder(refunder),
+	)
+
+	ctx := context.Background()
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := svc.RefundDeposit(ctx, userID, deposit.ID, 30)
+			if err != nil {
+				assert.ErrorIs(t, err, accountdomain.ErrRefundExceedsDeposit)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Three refunds of 30.00 fit in the 100.00 deposit; the rest are rejected.
+	assert.Len(t, refunds, 3)
+	assert.Len(t, refunder.calls, 3)
+}
+
+// rowLockingUnit is a unit of work whose account repository's GetForUpdate
+// holds row until the unit of work ends, like SELECT ... FOR UPDATE does.
+type rowLockingUnit struct {
+	repository.UnitOfWork
+	row     *sync.Mutex
+	locked  bool
+	accRepo repoaccount.Repository
+	txRepo  transaction.Repository
+}
+
+func (u *rowLockingUnit) GetRepository(repoType any) (any, error) {
+	switch repoType.(type) {
+	case *transaction.Repository:
+		return u.txRepo, nil
+	case *repoaccount.Repository:
+		return rowLockingAccounts{Repository: u.accRepo, unit: u}, nil
+	}
+	return nil, errors.New("unexpected repository type")
+}
+
+func (u *rowLockingUnit) release() {
+	if u.locked {
+		u.row.Unlock()
+	}
+}
+
+type rowLockingAccounts struct {
+	repoaccount.Repository
+	unit *rowLockingUnit
+}
+
+func (r rowLockingAccounts) GetForUpdate(ctx context.Context, id uuid.UUID) (*dto.AccountRead, error) {
+	r.unit.row.Lock()
+	r.unit.locked = true
+	return r.Get(ctx, id)
+}
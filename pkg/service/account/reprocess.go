@@ -0,0 +1,141 @@
+package account
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/amirasaad/fintech/pkg/domain/account"
+	"github.com/amirasaad/fintech/pkg/domain/events"
+	"github.com/amirasaad/fintech/pkg/dto"
+	"github.com/amirasaad/fintech/pkg/handler/common"
+	"github.com/amirasaad/fintech/pkg/money"
+	"github.com/amirasaad/fintech/pkg/repository"
+	"github.com/google/uuid"
+)
+
+// ReprocessTransaction re-emits the domain event that settled a transaction,
+// rebuilt from its stored state, so that the handlers of the event run again.
+// It repairs transactions whose event was handled but whose side effects
+// failed: completed deposits and withdrawals re-emit Payment.Completed,
+// completed and failed refunds Refund.Completed and Refund.Failed. Other
+// transactions return account.ErrReprocessNotSupported.
+//
+// Handlers that already applied the event must skip it, so reprocessing
+// relies on their idempotency: payment and refund handlers skip transactions
+// no longer in the status the event moves them from. With dryRun set the
+// event is returned without being emitted. Like CaptureDeposit it performs
+// no ownership checks; callers must restrict it to admins. adminID and
+// reason are logged as an audit record of every call.
+func (s *Service) ReprocessTransaction(
+	ctx context.Context,
+	adminID, transactionID uuid.UUID,
+	reason string,
+	dryRun bool,
+) (events.Event, error) {
+	var tx *dto.TransactionRead
+	err := s.uow.Do(ctx, func(uow repository.UnitOfWork) error {
+		txRepo, err := common.GetTransactionRepository(uow, s.logger)
+		if err != nil {
+			return err
+		}
+		tx, err = txRepo.Get(ctx, transactionID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	evt, err := reprocessEvent(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	log := s.logger.With(
+		"audit", true,
+		"admin_id", adminID,
+		"reason", reason,
+		"transaction_id", tx.ID,
+		"account_id", tx.AccountID,
+		"event_type", evt.Type(),
+		"dry_run", dryRun,
+	)
+	if dryRun {
+		log.Info("Transaction events reprocess dry run")
+		return evt, nil
+	}
+	// Re-running handlers of settled money movements is only safe if they
+	// are idempotent, so every reprocess is flagged.
+	log.Warn("Reprocessing transaction events; handlers will run again")
+	if err := s.bus.Emit(ctx, evt); err != nil {
+		log.Error("failed to re-emit transaction event", "error", err)
+		return nil, fmt.Errorf("failed to re-emit %s: %w", evt.Type(), err)
+	}
+	return evt, nil
+}
+
+// reprocessEvent rebuilds the event that settled tx.
+func reprocessEvent(tx *dto.TransactionRead) (events.Event, error) {
+	if tx.PaymentID == nil || *tx.PaymentID == "" {
+		return nil, fmt.Errorf(
+			"%w: %s transaction has no payment ID",
+			account.ErrReprocessNotSupported,
+			tx.MoneySource,
+		)
+	}
+	amount := tx.AmountMoney
+	if amount == nil {
+		var err error
+		if amount, err = money.New(tx.Amount, money.Code(tx.Currency)); err != nil {
+			return nil, err
+		}
+	}
+	amount = amount.Abs()
+
+	status := account.TransactionStatus(tx.Status)
+	switch {
+	case (tx.MoneySource == moneySourceDeposit || common.IsWithdrawMoneySource(tx.MoneySource)) &&
+		status == account.TransactionStatusCompleted:
+		// The provider webhook emits deposits' completions as payment flows.
+		flowType := "payment"
+		if common.IsWithdrawMoneySource(tx.MoneySource) {
+			flowType = moneySourceWithdraw
+		}
+		return events.NewPaymentCompleted(
+			&events.FlowEvent{
+				FlowType:      flowType,
+				UserID:        tx.UserID,
+				AccountID:     tx.AccountID,
+				CorrelationID: tx.ID,
+				Timestamp:     time.Now(),
+			},
+			func(pc *events.PaymentCompleted) {
+				pc.TransactionID = tx.ID
+				pc.PaymentID = tx.PaymentID
+				pc.Amount = amount
+				pc.Status = tx.Status
+				if pm := tx.PaymentMethod; pm != nil {
+					pc.PaymentMethod = &account.PaymentMethod{
+						Type:    pm.Type,
+						Brand:   pm.Brand,
+						Last4:   pm.Last4,
+						Funding: pm.Funding,
+					}
+				}
+			},
+		), nil
+	case tx.MoneySource == moneySourceRefund && status == account.TransactionStatusCompleted:
+		return events.NewRefundCompleted(
+			tx.UserID, tx.AccountID, tx.ID, *tx.PaymentID, amount,
+		), nil
+	case tx.MoneySource == moneySourceRefund && status == account.TransactionStatusFailed:
+		return events.NewRefundFailed(
+			tx.UserID, tx.AccountID, tx.ID, *tx.PaymentID, amount, "reprocessed",
+		), nil
+	}
+	return nil, fmt.Errorf(
+		"%w: %s transaction is %s",
+		account.ErrReprocessNotSupported,
+		tx.MoneySource,
+		tx.Status,
+	)
+}
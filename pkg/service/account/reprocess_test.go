@@ -0,0 +1,128 @@
+package account_test
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/amirasaad/fintech/internal/fixtures/mocks"
+	accountdomain "github.com/amirasaad/fintech/pkg/domain/account"
+	"github.com/amirasaad/fintech/pkg/domain/events"
+	"github.com/amirasaad/fintech/pkg/dto"
+	"github.com/amirasaad/fintech/pkg/repository"
+	"github.com/amirasaad/fintech/pkg/repository/transaction"
+	accountsvc "github.com/amirasaad/fintech/pkg/service/account"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReprocessTransaction(t *testing.T) {
+	adminID, userID, accountID := uuid.New(), uuid.New(), uuid.New()
+	paymentID, refundID := "pi_1", "re_1"
+	deposit := &dto.TransactionRead{
+		ID:            uuid.New(),
+		UserID:        userID,
+		AccountID:     accountID,
+		Amount:        100,
+		Currency:      "USD",
+		Status:        string(accountdomain.TransactionStatusCompleted),
+		MoneySource:   "deposit",
+		PaymentID:     &paymentID,
+		PaymentMethod: &dto.PaymentMethod{Type: "card", Brand: "visa", Last4: "4242"},
+	}
+	refund := &dto.TransactionRead{
+		ID:          uuid.New(),
+		UserID:      userID,
+		AccountID:   accountID,
+		Amount:      -40,
+		Currency:    "USD",
+		Status:      string(accountdomain.TransactionStatusFailed),
+		MoneySource: "refund",
+		PaymentID:   &refundID,
+	}
+	payoutID := "tr_1"
+	heldWithdrawal := &dto.TransactionRead{
+		ID:          uuid.New(),
+		UserID:      userID,
+		AccountID:   accountID,
+		Amount:      -250,
+		Currency:    "USD",
+		Status:      string(accountdomain.TransactionStatusCompleted),
+		MoneySource: "withdraw_hold",
+		PaymentID:   &payoutID,
+	}
+	pendingDeposit := *deposit
+	pendingDeposit.ID = uuid.New()
+	pendingDeposit.Status = string(accountdomain.TransactionStatusPending)
+	transfer := &dto.TransactionRead{
+		ID:          uuid.New(),
+		Amount:      -10,
+		Currency:    "USD",
+		Status:      string(accountdomain.TransactionStatusCompleted),
+		MoneySource: "transfer",
+	}
+
+	newService := func(t *testing.T) (*accountsvc.Service, *mocks.Bus) {
+		uow := mocks.NewUnitOfWork(t)
+		txRepo := mocks.NewTransactionRepository(t)
+		bus := mocks.NewBus(t)
+		uow.EXPECT().Do(mock.Anything, mock.Anything).RunAndReturn(
+			func(ctx context.Context, fn func(repository.UnitOfWork) error) error {
+				return fn(uow)
+			},
+		)
+		uow.EXPECT().GetRepository((*transaction.Repository)(nil)).Return(txRepo, nil)
+		for _, tx := range []*dto.TransactionRead{
+			deposit, refund, &pendingDeposit, transfer, heldWithdrawal,
+		} {
+			txRepo.EXPECT().Get(mock.Anything, tx.ID).Return(tx, nil).Maybe()
+		}
+		return accountsvc.New(bus, uow, slog.Default(), nil), bus
+	}
+	ctx := context.Background()
+
+	t.Run("completed deposit re-emits payment completed", func(t *testing.T) {
+		svc, bus := newService(t)
+		bus.EXPECT().Emit(mock.Anything, mock.AnythingOfType("*events.PaymentCompleted")).
+			Return(nil).Once()
+		evt, err := svc.ReprocessTransaction(ctx, adminID, deposit.ID, "ledger repair", false)
+		require.NoError(t, err)
+		pc, ok := evt.(*events.PaymentCompleted)
+		require.True(t, ok)
+		assert.Equal(t, deposit.ID, pc.TransactionID)
+		assert.Equal(t, accountID, pc.AccountID)
+		assert.Equal(t, paymentID, *pc.PaymentID)
+		assert.Equal(t, "100.00", pc.Amount.AmountString())
+		assert.Equal(t, "visa", pc.PaymentMethod.Brand)
+	})
+
+	t.Run("approved held withdrawal re-emits a withdraw flow", func(t *testing.T) {
+		svc, _ := newService(t)
+		evt, err := svc.ReprocessTransaction(ctx, adminID, heldWithdrawal.ID, "check", true)
+		require.NoError(t, err)
+		pc, ok := evt.(*events.PaymentCompleted)
+		require.True(t, ok)
+		assert.Equal(t, "withdraw", pc.FlowType)
+		assert.Equal(t, heldWithdrawal.ID, pc.TransactionID)
+	})
+
+	t.Run("dry run does not emit", func(t *testing.T) {
+		svc, _ := newService(t)
+		evt, err := svc.ReprocessTransaction(ctx, adminID, refund.ID, "check", true)
+		require.NoError(t, err)
+		rf, ok := evt.(*events.RefundFailed)
+		require.True(t, ok)
+		assert.Equal(t, refundID, rf.RefundID)
+		assert.Equal(t, "40.00", rf.Amount.AmountString())
+	})
+
+	t.Run("unsettled and unsupported transactions are rejected", func(t *testing.T) {
+		svc, _ := newService(t)
+		for _, id := range []uuid.UUID{pendingDeposit.ID, transfer.ID} {
+			_, err := svc.ReprocessTransaction(ctx, adminID, id, "repair", false)
+			require.ErrorIs(t, err, accountdomain.ErrReprocessNotSupported)
+		}
+	})
+}
@@ -0,0 +1,204 @@
+package account
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/amirasaad/fintech/pkg/commands"
+	"github.com/amirasaad/fintech/pkg/domain"
+	"github.com/amirasaad/fintech/pkg/domain/account"
+	"github.com/amirasaad/fintech/pkg/dto"
+	"github.com/amirasaad/fintech/pkg/money"
+	"github.com/amirasaad/fintech/pkg/repository"
+	repoaccount "github.com/amirasaad/fintech/pkg/repository/account"
+	"github.com/amirasaad/fintech/pkg/repository/scheduledtransfer"
+	"github.com/google/uuid"
+)
+
+// ScheduleTransfer persists a transfer to be executed at cmd.ExecuteAt, which
+// must be in the future. The TransferScheduler later executes it through the
+// normal transfer flow, re-validating the accounts at execution time.
+func (s *Service) ScheduleTransfer(
+	ctx context.Context,
+	cmd commands.Transfer,
+) (*dto.ScheduledTransferRead, error) {
+	if !cmd.ExecuteAt.After(time.Now()) {
+		return nil, account.ErrScheduleInPast
+	}
+	amount, err := money.New(cmd.Amount, money.Code(cmd.Currency))
+	if err != nil {
+		return nil, err
+	}
+	description, err := account.NormalizeDescription(cmd.Description)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.ensureAccountAcceptsFunds(ctx, cmd.ToAccountID); err != nil {
+		return nil, err
+	}
+
+	var result *dto.ScheduledTransferRead
+	err = s.uow.Do(ctx, func(uow repository.UnitOfWork) error {
+		acctRepo, err := getAccountRepository(uow)
+		if err != nil {
+			return err
+		}
+		if err := validateTransferAccounts(
+			ctx, acctRepo, cmd.UserID, cmd.AccountID, cmd.ToAccountID, nil,
+		); err != nil {
+			return err
+		}
+
+		repo, err := getScheduledTransferRepository(uow)
+		if err != nil {
+			return err
+		}
+		id := uuid.New()
+		if err := repo.Create(ctx, dto.ScheduledTransferCreate{
+			ID:            id,
+			UserID:        cmd.UserID,
+			FromAccountID: cmd.AccountID,
+			ToAccountID:   cmd.ToAccountID,
+			Amount:        amount.Amount(),
+			Currency:      amount.Currency().String(),
+			Description:   description,
+			ExecuteAt:     cmd.ExecuteAt,
+		}); err != nil {
+			return fmt.Errorf("failed to create scheduled transfer: %w", err)
+		}
+		result, err = repo.Get(ctx, id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ListScheduledTransfers returns the user's scheduled transfers, optionally
+// filtered by status (e.g. dto.ScheduledTransferPending).
+func (s *Service) ListScheduledTransfers(
+	ctx context.Context,
+	userID uuid.UUID,
+	status string,
+) ([]*dto.ScheduledTransferRead, error) {
+	repo, err := getScheduledTransferRepository(s.uow)
+	if err != nil {
+		return nil, err
+	}
+	return repo.ListByUser(ctx, userID, status)
+}
+
+// CancelScheduledTransfer cancels a pending scheduled transfer owned by the user.
+func (s *Service) CancelScheduledTransfer(
+	ctx context.Context,
+	userID, id uuid.UUID,
+) error {
+	return s.uow.Do(ctx, func(uow repository.UnitOfWork) error {
+		repo, err := getScheduledTransferRepository(uow)
+		if err != nil {
+			return err
+		}
+		st, err := repo.Get(ctx, id)
+		if err != nil {
+			return err
+		}
+		if st.UserID != userID {
+			return domain.ErrNotFound
+		}
+		ok, err := repo.UpdateStatus(
+			ctx, id, dto.ScheduledTransferPending, dto.ScheduledTransferCanceled, "",
+		)
+		if err != nil {
+			return fmt.Errorf("failed to cancel scheduled transfer: %w", err)
+		}
+		if !ok {
+			return account.ErrScheduledTransferNotPending
+		}
+		return nil
+	})
+}
+
+// validateTransferAccounts checks that the source account belongs to the user,
+// both accounts are active and, when amount is given in the source account's
+// currency, that the source has sufficient funds.
+func validateTransferAccounts(
+	ctx context.Context,
+	acctRepo repoaccount.Repository,
+	userID, fromID, toID uuid.UUID,
+	amount *money.Money,
+) error {
+	if fromID == toID {
+		return account.ErrCannotTransferToSameAccount
+	}
+	src, err := acctRepo.Get(ctx, fromID)
+	if err != nil {
+		return fmt.Errorf("source account: %w", err)
+	}
+	if src.UserID != userID {
+		return account.ErrNotOwner
+	}
+	dest, err := acctRepo.Get(ctx, toID)
+	if err != nil {
+		return fmt.Errorf("destination account: %w", err)
+	}
+	if !isActive(src) || !isActive(dest) {
+		return account.ErrAccountNotActive
+	}
+	if amount == nil || src.Currency != amount.Currency().String() {
+		// Cross-currency balances are checked by the transfer flow after conversion.
+		return nil
+	}
+	balance, err := money.New(src.Balance, money.Code(src.Currency))
+	if err != nil {
+		return err
+	}
+	overdraftLimit, err := money.New(src.OverdraftLimit, money.Code(src.Currency))
+	if err != nil {
+		return err
+	}
+	acc, err := account.New().
+		WithID(src.ID).
+		WithUserID(src.UserID).
+		WithCurrency(src.Currency).
+		WithBalance(balance.Amount()).
+		WithOverdraftLimit(overdraftLimit.Amount()).
+		Build()
+	if err != nil {
+		return err
+	}
+	return acc.ValidateWithdraw(userID, amount)
+}
+
+// isActive reports whether the account can take part in money movements.
+// Accounts without a status are treated as active.
+func isActive(a *dto.AccountRead) bool {
+	return account.IsActiveStatus(a.Status)
+}
+
+func getAccountRepository(uow repository.UnitOfWork) (repoaccount.Repository, error) {
+	repoAny, err := uow.GetRepository((*repoaccount.Repository)(nil))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get account repository: %w", err)
+	}
+	repo, ok := repoAny.(repoaccount.Repository)
+	if !ok {
+		return nil, fmt.Errorf("unexpected account repository type %T", repoAny)
+	}
+	return repo, nil
+}
+
+func getScheduledTransferRepository(
+	uow repository.UnitOfWork,
+) (scheduledtransfer.Repository, error) {
+	repoAny, err := uow.GetRepository((*scheduledtransfer.Repository)(nil))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get scheduled transfer repository: %w", err)
+	}
+	repo, ok := repoAny.(scheduledtransfer.Repository)
+	if !ok {
+		return nil, fmt.Errorf("unexpected scheduled transfer repository type %T", repoAny)
+	}
+	return repo, nil
+}
@@ -0,0 +1,375 @@
+package account_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/amirasaad/fintech/internal/fixtures/mocks"
+	"github.com/amirasaad/fintech/pkg/commands"
+	"github.com/amirasaad/fintech/pkg/domain"
+	accountdomain "github.com/amirasaad/fintech/pkg/domain/account"
+	"github.com/amirasaad/fintech/pkg/domain/events"
+	"github.com/amirasaad/fintech/pkg/dto"
+	"github.com/amirasaad/fintech/pkg/repository"
+	repoaccount "github.com/amirasaad/fintech/pkg/repository/account"
+	"github.com/amirasaad/fintech/pkg/repository/scheduledtransfer"
+	accountsvc "github.com/amirasaad/fintech/pkg/service/account"
+	"github.com/amirasaad/fintech/pkg/service/maintenance"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeScheduledTransferRepo is an in-memory scheduledtransfer.Repository.
+type fakeScheduledTransferRepo struct {
+	items map[uuid.UUID]*dto.ScheduledTransferRead
+}
+
+func newFakeScheduledTransferRepo() *fakeScheduledTransferRepo {
+	return &fakeScheduledTransferRepo{items: map[uuid.UUID]*dto.ScheduledTransferRead{}}
+}
+
+func (r *fakeScheduledTransferRepo) Create(
+	_ context.Context,
+	create dto.ScheduledTransferCreate,
+) error {
+	r.items[create.ID] = &dto.ScheduledTransferRead{
+		ID:            create.ID,
+		UserID:        create.UserID,
+		FromAccountID: create.FromAccountID,
+		ToAccountID:   create.ToAccountID,
+		Amount:        float64(create.Amount) / 100,
+		Currency:      create.Currency,
+		Status:        dto.ScheduledTransferPending,
+		ExecuteAt:     create.ExecuteAt,
+		CreatedAt:     time.Now(),
+	}
+	return nil
+}
+
+func (r *fakeScheduledTransferRepo) Get(
+	_ context.Context,
+	id uuid.UUID,
+) (*dto.ScheduledTransferRead, error) {
+	st, ok := r.items[id]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	return st, nil
+}
+
+func (r *fakeScheduledTransferRepo) ListByUser(
+	_ context.Context,
+	userID uuid.UUID,
+	status string,
+) ([]*dto.ScheduledTransferRead, error) {
+	var out []*dto.ScheduledTransferRead
+	for _, st := range r.items {
+		if st.UserID == userID && (status == "" || st.Status == status) {
+			out = append(out, st)
+		}
+	}
+	return out, nil
+}
+
+func (r *fakeScheduledTransferRepo) ListDue(
+	_ context.Context,
+	before time.Time,
+	limit int,
+) ([]*dto.ScheduledTransferRead, error) {
+	var out []*dto.ScheduledTransferRead
+	for _, st := range r.items {
+		if st.Status == dto.ScheduledTransferPending && !st.ExecuteAt.After(before) {
+			out = append(out, st)
+		}
+		if len(out) == limit {
+			break
+		}
+	}
+	return out, nil
+}
+
+func (r *fakeScheduledTransferRepo) UpdateStatus(
+	_ context.Context,
+	id uuid.UUID,
+	from, to string,
+	reason string,
+) (bool, error) {
+	st, ok := r.items[id]
+	if !ok || st.Status != from {
+		return false, nil
+	}
+	st.Status = to
+	st.FailureReason = reason
+	return true, nil
+}
+
+// snapshot copies the items so that a failed unit of work can restore them.
+func (r *fakeScheduledTransferRepo) snapshot() map[uuid.UUID]dto.ScheduledTransferRead {
+	out := make(map[uuid.UUID]dto.ScheduledTransferRead, len(r.items))
+	for id, st := range r.items {
+		out[id] = *st
+	}
+	return out
+}
+
+// restore rolls the items back to a snapshot.
+func (r *fakeScheduledTransferRepo) restore(snapshot map[uuid.UUID]dto.ScheduledTransferRead) {
+	for id, st := range r.items {
+		saved, ok := snapshot[id]
+		if !ok {
+			delete(r.items, id)
+			continue
+		}
+		*st = saved
+	}
+}
+
+var _ scheduledtransfer.Repository = (*fakeScheduledTransferRepo)(nil)
+
+// setupScheduledTransferUOW wires a UnitOfWork mock that runs Do inline,
+// rolling the fake scheduled transfer repository back when it fails, and
+// resolves the account and scheduled transfer repositories by type.
+func setupScheduledTransferUOW(
+	t *testing.T,
+	accountRepo repoaccount.Repository,
+	stRepo scheduledtransfer.Repository,
+) *mocks.UnitOfWork {
+	uow := mocks.NewUnitOfWork(t)
+	uow.EXPECT().Do(mock.Anything, mock.Anything).RunAndReturn(
+		func(ctx context.Context, fn func(repository.UnitOfWork) error) error {
+			fake, ok := stRepo.(*fakeScheduledTransferRepo)
+			if !ok {
+				return fn(uow)
+			}
+			snapshot := fake.snapshot()
+			err := fn(uow)
+			if err != nil {
+				fake.restore(snapshot)
+			}
+			return err
+		},
+	).Maybe()
+	uow.EXPECT().GetRepository(mock.Anything).RunAndReturn(
+		func(repoType any) (any, error) {
+			if _, ok := repoType.(*scheduledtransfer.Repository); ok {
+				return stRepo, nil
+			}
+			return accountRepo, nil
+		},
+	).Maybe()
+	return uow
+}
+
+func TestScheduleTransfer(t *testing.T) {
+	userID := uuid.New()
+	fromID, toID := uuid.New(), uuid.New()
+	accountRepo := mocks.NewAccountRepository(t)
+	accountRepo.EXPECT().Get(mock.Anything, fromID).Return(&dto.AccountRead{
+		ID: fromID, UserID: userID, Currency: "USD", Status: "active",
+	}, nil)
+	accountRepo.EXPECT().Get(mock.Anything, toID).Return(&dto.AccountRead{
+		ID: toID, UserID: uuid.New(), Currency: "USD", Status: "active",
+	}, nil)
+	stRepo := newFakeScheduledTransferRepo()
+	uow := setupScheduledTransferUOW(t, accountRepo, stRepo)
+	svc := accountsvc.New(nil, uow, slog.Default(), nil)
+
+	cmd := commands.Transfer{
+		UserID:      userID,
+		AccountID:   fromID,
+		ToAccountID: toID,
+		Amount:      25,
+		Currency:    "USD",
+		ExecuteAt:   time.Now().Add(time.Hour),
+	}
+	st, err := svc.ScheduleTransfer(context.Background(), cmd)
+	require.NoError(t, err)
+	assert.Equal(t, dto.ScheduledTransferPending, st.Status)
+	assert.InDelta(t, 25.0, st.Amount, 0.001)
+
+	pending, err := svc.ListScheduledTransfers(
+		context.Background(), userID, dto.ScheduledTransferPending,
+	)
+	require.NoError(t, err)
+	assert.Len(t, pending, 1)
+
+	require.NoError(t, svc.CancelScheduledTransfer(context.Background(), userID, st.ID))
+	require.ErrorIs(t,
+		svc.CancelScheduledTransfer(context.Background(), userID, st.ID),
+		accountdomain.ErrScheduledTransferNotPending,
+	)
+	require.ErrorIs(t,
+		svc.CancelScheduledTransfer(context.Background(), uuid.New(), st.ID),
+		domain.ErrNotFound,
+	)
+}
+
+func TestScheduleTransfer_InPast(t *testing.T) {
+	svc := accountsvc.New(nil, mocks.NewUnitOfWork(t), slog.Default(), nil)
+	_, err := svc.ScheduleTransfer(context.Background(), commands.Transfer{
+		UserID:      uuid.New(),
+		AccountID:   uuid.New(),
+		ToAccountID: uuid.New(),
+		Amount:      10,
+		Currency:    "USD",
+		ExecuteAt:   time.Now().Add(-time.Minute),
+	})
+	require.ErrorIs(t, err, accountdomain.ErrScheduleInPast)
+}
+
+func TestTransferScheduler_ExecuteDue(t *testing.T) {
+	userID := uuid.New()
+	fromID, toID := uuid.New(), uuid.New()
+	accountRepo := mocks.NewAccountRepository(t)
+	accountRepo.EXPECT().Get(mock.Anything, fromID).Return(&dto.AccountRead{
+		ID: fromID, UserID: userID, Currency: "USD", Balance: 50, Status: "active",
+	}, nil)
+	accountRepo.EXPECT().Get(mock.Anything, toID).Return(&dto.AccountRead{
+		ID: toID, UserID: uuid.New(), Currency: "USD", Status: "active",
+	}, nil)
+
+	stRepo := newFakeScheduledTransferRepo()
+	due := dto.ScheduledTransferCreate{
+		ID: uuid.New(), UserID: userID, FromAccountID: fromID, ToAccountID: toID,
+		Amount: 2000, Currency: "USD", ExecuteAt: time.Now().Add(-time.Minute),
+	}
+	tooLarge := dto.ScheduledTransferCreate{
+		ID: uuid.New(), UserID: userID, FromAccountID: fromID, ToAccountID: toID,
+		Amount: 10000, Currency: "USD", ExecuteAt: time.Now().Add(-time.Minute),
+	}
+	later := dto.ScheduledTransferCreate{
+		ID: uuid.New(), UserID: userID, FromAccountID: fromID, ToAccountID: toID,
+		Amount: 1000, Currency: "USD", ExecuteAt: time.Now().Add(time.Hour),
+	}
+	for _, c := range []dto.ScheduledTransferCreate{due, tooLarge, later} {
+		require.NoError(t, stRepo.Create(context.Background(), c))
+	}
+
+	bus := mocks.NewBus(t)
+	bus.EXPECT().Emit(mock.Anything, mock.MatchedBy(func(e events.Event) bool {
+		tr, ok := e.(*events.TransferRequested)
+		return ok && tr.ID == due.ID && tr.DestAccountID == toID
+	})).Return(nil).Once()
+
+	uow := setupScheduledTransferUOW(t, accountRepo, stRepo)
+	scheduler := accountsvc.NewTransferScheduler(bus, uow, slog.Default(), nil)
+
+	n, err := scheduler.ExecuteDue(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.Equal(t, dto.ScheduledTransferExecuted, stRepo.items[due.ID].Status)
+	assert.Equal(t, dto.ScheduledTransferFailed, stRepo.items[tooLarge.ID].Status)
+	assert.NotEmpty(t, stRepo.items[tooLarge.ID].FailureReason)
+	assert.Equal(t, dto.ScheduledTransferPending, stRepo.items[later.ID].Status)
+
+	// A second run must not execute anything again.
+	n, err = scheduler.ExecuteDue(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 0, n)
+}
+
+func TestTransferScheduler_ExecuteDueEmitFailure(t *testing.T) {
+	userID := uuid.New()
+	fromID, toID := uuid.New(), uuid.New()
+	accountRepo := mocks.NewAccountRepository(t)
+	accountRepo.EXPECT().Get(mock.Anything, fromID).Return(&dto.AccountRead{
+		ID: fromID, UserID: userID, Currency: "USD", Balance: 50, Status: "active",
+	}, nil)
+	accountRepo.EXPECT().Get(mock.Anything, toID).Return(&dto.AccountRead{
+		ID: toID, UserID: uuid.New(), Currency: "USD", Status: "active",
+	}, nil)
+
+	stRepo := newFakeScheduledTransferRepo()
+	for range 2 {
+		require.NoError(t, stRepo.Create(context.Background(), dto.ScheduledTransferCreate{
+			ID: uuid.New(), UserID: userID, FromAccountID: fromID, ToAccountID: toID,
+			Amount: 1000, Currency: "USD", ExecuteAt: time.Now().Add(-time.Minute),
+		}))
+	}
+
+	// The first emit succeeds and the second fails.
+	var emitted []uuid.UUID
+	bus := mocks.NewBus(t)
+	bus.EXPECT().Emit(mock.Anything, mock.Anything).RunAndReturn(
+		func(_ context.Context, e events.Event) error {
+			emitted = append(emitted, e.(*events.TransferRequested).ID)
+			if len(emitted) == 2 {
+				return errors.New("bus down")
+			}
+			return nil
+		},
+	).Twice()
+
+	uow := setupScheduledTransferUOW(t, accountRepo, stRepo)
+	scheduler := accountsvc.NewTransferScheduler(bus, uow, slog.Default(), nil)
+
+	n, err := scheduler.ExecuteDue(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	require.Len(t, emitted, 2)
+	assert.Equal(t, dto.ScheduledTransferExecuted, stRepo.items[emitted[0]].Status)
+	assert.Equal(t, dto.ScheduledTransferFailed, stRepo.items[emitted[1]].Status)
+	assert.Contains(t, stRepo.items[emitted[1]].FailureReason, "bus down")
+
+	// Neither transfer is emitted again.
+	n, err = scheduler.ExecuteDue(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 0, n)
+	assert.Len(t, emitted, 2)
+}
+
+// fakeMaintenance is an accountsvc.MaintenanceChecker toggled by the test.
+type fakeMaintenance struct {
+	on bool
+}
+
+func (f *fakeMaintenance) CheckWrites(context.Context) error {
+	if f.on {
+		return maintenance.ErrUnderMaintenance
+	}
+	return nil
+}
+
+func TestTransferScheduler_ExecuteDuePausedDuringMaintenance(t *testing.T) {
+	userID := uuid.New()
+	fromID, toID := uuid.New(), uuid.New()
+	accountRepo := mocks.NewAccountRepository(t)
+	accountRepo.EXPECT().Get(mock.Anything, fromID).Return(&dto.AccountRead{
+		ID: fromID, UserID: userID, Currency: "USD", Balance: 50, Status: "active",
+	}, nil)
+	accountRepo.EXPECT().Get(mock.Anything, toID).Return(&dto.AccountRead{
+		ID: toID, UserID: uuid.New(), Currency: "USD", Status: "active",
+	}, nil)
+
+	stRepo := newFakeScheduledTransferRepo()
+	due := dto.ScheduledTransferCreate{
+		ID: uuid.New(), UserID: userID, FromAccountID: fromID, ToAccountID: toID,
+		Amount: 2000, Currency: "USD", ExecuteAt: time.Now().Add(-time.Minute),
+	}
+	require.NoError(t, stRepo.Create(context.Background(), due))
+
+	bus := mocks.NewBus(t)
+	uow := setupScheduledTransferUOW(t, accountRepo, stRepo)
+	checker := &fakeMaintenance{on: true}
+	scheduler := accountsvc.NewTransferScheduler(bus, uow, slog.Default(), nil).
+		WithMaintenance(checker)
+
+	n, err := scheduler.ExecuteDue(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 0, n)
+	assert.Equal(t, dto.ScheduledTransferPending, stRepo.items[due.ID].Status)
+	bus.AssertNotCalled(t, "Emit", mock.Anything, mock.Anything)
+
+	// The transfer runs once maintenance is over.
+	checker.on = false
+	bus.EXPECT().Emit(mock.Anything, mock.Anything).Return(nil).Once()
+	n, err = scheduler.ExecuteDue(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.Equal(t, dto.ScheduledTransferExecuted, stRepo.items[due.ID].Status)
+}
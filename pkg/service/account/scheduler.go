@@ -0,0 +1,231 @@
+package account
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/amirasaad/fintech/pkg/config"
+	"github.com/amirasaad/fintech/pkg/domain/events"
+	"github.com/amirasaad/fintech/pkg/dto"
+	"github.com/amirasaad/fintech/pkg/eventbus"
+	"github.com/amirasaad/fintech/pkg/money"
+	"github.com/amirasaad/fintech/pkg/repository"
+	"github.com/google/uuid"
+)
+
+const (
+	defaultSchedulerInterval  = 30 * time.Second
+	defaultSchedulerBatchSize = 50
+)
+
+// TransferScheduler executes due scheduled transfers through the normal
+// transfer flow.
+//
+// Scheduled transfers live in the database, so nothing is lost across
+// restarts. Each due transfer is validated and claimed in its own unit of
+// work, which commits before its TransferRequested event is emitted, so one
+// transfer failing never undoes the claims of the others. A transfer whose
+// event cannot be emitted is marked failed rather than retried, so a transfer
+// is executed at most once. The event ID is the scheduled transfer ID, and the
+// transfer flow uses it as the transaction ID.
+type TransferScheduler struct {
+	bus         eventbus.Bus
+	uow         repository.UnitOfWork
+	logger      *slog.Logger
+	interval    time.Duration
+	batchSize   int
+	now         func() time.Time
+	maintenance MaintenanceChecker
+}
+
+// MaintenanceChecker reports whether money movements are allowed.
+// *maintenance.Service satisfies it.
+type MaintenanceChecker interface {
+	CheckWrites(ctx context.Context) error
+}
+
+// NewTransferScheduler creates a new TransferScheduler. A nil config uses the defaults.
+func NewTransferScheduler(
+	bus eventbus.Bus,
+	uow repository.UnitOfWork,
+	logger *slog.Logger,
+	cfg *config.TransferScheduler,
+) *TransferScheduler {
+	s := &TransferScheduler{
+		bus:       bus,
+		uow:       uow,
+		logger:    logger,
+		interval:  defaultSchedulerInterval,
+		batchSize: defaultSchedulerBatchSize,
+		now:       time.Now,
+	}
+	if cfg != nil {
+		if cfg.Interval > 0 {
+			s.interval = cfg.Interval
+		}
+		if cfg.BatchSize > 0 {
+			s.batchSize = cfg.BatchSize
+		}
+	}
+	return s
+}
+
+// WithMaintenance pauses the scheduler while maintenance mode is on. Transfers
+// that fall due meanwhile are executed on the first run after it.
+func (s *TransferScheduler) WithMaintenance(checker MaintenanceChecker) *TransferScheduler {
+	s.maintenance = checker
+	return s
+}
+
+// Run executes due transfers every interval until ctx is canceled.
+func (s *TransferScheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	s.logger.Info("Transfer scheduler started", "interval", s.interval)
+	for {
+		if n, err := s.ExecuteDue(ctx); err != nil {
+			s.logger.Error("Failed to execute scheduled transfers", "error", err)
+		} else if n > 0 {
+			s.logger.Info("Executed scheduled transfers", "count", n)
+		}
+		select {
+		case <-ctx.Done():
+			s.logger.Info("Transfer scheduler stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ExecuteDue executes up to one batch of due transfers and returns how many
+// were handed to the transfer flow. Transfers that no longer pass validation
+// (ownership, account status, balance) are marked failed with the reason.
+// While maintenance mode is on it executes none.
+func (s *TransferScheduler) ExecuteDue(ctx context.Context) (int, error) {
+	if s.maintenance != nil {
+		if err := s.maintenance.CheckWrites(ctx); err != nil {
+			s.logger.Info("Skipping scheduled transfers during maintenance", "reason", err)
+			return 0, nil
+		}
+	}
+	repo, err := getScheduledTransferRepository(s.uow)
+	if err != nil {
+		return 0, err
+	}
+	due, err := repo.ListDue(ctx, s.now(), s.batchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list due transfers: %w", err)
+	}
+
+	executed := 0
+	for _, st := range due {
+		log := s.logger.With(
+			"scheduled_transfer_id", st.ID,
+			"user_id", st.UserID,
+			"from_account_id", st.FromAccountID,
+			"to_account_id", st.ToAccountID,
+		)
+		tr, err := s.claim(ctx, st, log)
+		if err != nil {
+			log.Error("Failed to claim scheduled transfer", "error", err)
+			continue
+		}
+		if tr == nil {
+			continue
+		}
+		if err := s.bus.Emit(ctx, tr); err != nil {
+			log.Error("Failed to emit scheduled transfer", "error", err)
+			s.markEmitFailed(ctx, st.ID, err, log)
+			continue
+		}
+		log.Info("📤 [EMITTED] Scheduled transfer", "event_type", tr.Type())
+		executed++
+	}
+	return executed, nil
+}
+
+// claim validates st and, in one unit of work, either marks it failed or
+// claims it by moving it from pending to executed. It returns the transfer to
+// emit, or nil if st failed validation or was claimed by another run.
+func (s *TransferScheduler) claim(
+	ctx context.Context,
+	st *dto.ScheduledTransferRead,
+	log *slog.Logger,
+) (*events.TransferRequested, error) {
+	var tr *events.TransferRequested
+	err := s.uow.Do(ctx, func(uow repository.UnitOfWork) error {
+		tr = nil
+		repo, err := getScheduledTransferRepository(uow)
+		if err != nil {
+			return err
+		}
+		acctRepo, err := getAccountRepository(uow)
+		if err != nil {
+			return err
+		}
+		amount, err := money.New(st.Amount, money.Code(st.Currency))
+		if err == nil {
+			err = validateTransferAccounts(
+				ctx, acctRepo, st.UserID, st.FromAccountID, st.ToAccountID, amount,
+			)
+		}
+		if err != nil {
+			log.Warn("Scheduled transfer failed validation", "error", err)
+			if _, uerr := repo.UpdateStatus(
+				ctx, st.ID, dto.ScheduledTransferPending, dto.ScheduledTransferFailed,
+				err.Error(),
+			); uerr != nil {
+				return fmt.Errorf("failed to mark scheduled transfer failed: %w", uerr)
+			}
+			return nil
+		}
+
+		claimed, err := repo.UpdateStatus(
+			ctx, st.ID, dto.ScheduledTransferPending, dto.ScheduledTransferExecuted, "",
+		)
+		if err != nil || !claimed {
+			return err
+		}
+		tr = events.NewTransferRequested(
+			st.UserID,
+			st.FromAccountID,
+			uuid.New(),
+			events.WithTransferDestAccountID(st.ToAccountID),
+			events.WithTransferRequestedAmount(amount),
+			events.WithTransferDescription(st.Description),
+		)
+		tr.ID = st.ID
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tr, nil
+}
+
+// markEmitFailed moves a claimed transfer whose event could not be emitted
+// to failed. It is not retried: the event may still have reached the transfer
+// flow, and retrying could move the money twice.
+func (s *TransferScheduler) markEmitFailed(
+	ctx context.Context,
+	id uuid.UUID,
+	emitErr error,
+	log *slog.Logger,
+) {
+	err := s.uow.Do(ctx, func(uow repository.UnitOfWork) error {
+		repo, err := getScheduledTransferRepository(uow)
+		if err != nil {
+			return err
+		}
+		_, err = repo.UpdateStatus(
+			ctx, id, dto.ScheduledTransferExecuted, dto.ScheduledTransferFailed,
+			fmt.Sprintf("failed to start transfer: %v", emitErr),
+		)
+		return err
+	})
+	if err != nil {
+		log.Error("Failed to mark scheduled transfer failed", "error", err)
+	}
+}
@@ -0,0 +1,41 @@
+package account
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/amirasaad/fintech/pkg/domain/account"
+	"github.com/amirasaad/fintech/pkg/dto"
+	"github.com/google/uuid"
+)
+
+// SetAutoConvert sets whether deposits into the user's account in another
+// currency are converted to the account currency. When disabled, such
+// deposits fail instead. It returns account.ErrAccountNotFound if the account
+// belongs to another user.
+func (s *Service) SetAutoConvert(
+	ctx context.Context,
+	userID, accountID uuid.UUID,
+	enabled bool,
+) (*dto.AccountRead, error) {
+	repo, err := getAccountRepository(s.uow)
+	if err != nil {
+		return nil, err
+	}
+	acc, err := repo.Get(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	if acc.UserID != userID {
+		return nil, account.ErrAccountNotFound
+	}
+	if err := repo.Update(ctx, accountID, dto.AccountUpdate{AutoConvert: &enabled}); err != nil {
+		return nil, fmt.Errorf("failed to update auto-conversion: %w", err)
+	}
+	acc.AutoConvert = enabled
+	s.logger.Info("Auto-conversion updated",
+		"account_id", accountID,
+		"auto_convert", enabled,
+	)
+	return acc, nil
+}
@@ -0,0 +1,37 @@
+package account_test
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	accountdomain "github.com/amirasaad/fintech/pkg/domain/account"
+	"github.com/amirasaad/fintech/pkg/dto"
+	repoaccount "github.com/amirasaad/fintech/pkg/repository/account"
+	accountsvc "github.com/amirasaad/fintech/pkg/service/account"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetAutoConvert(t *testing.T) {
+	t.Parallel()
+	uow, accountRepo, _ := setupTestMocks(t)
+	uow.EXPECT().GetRepository((*repoaccount.Repository)(nil)).Return(accountRepo, nil)
+
+	userID := uuid.New()
+	acc := &dto.AccountRead{ID: uuid.New(), UserID: userID, Currency: "EUR", AutoConvert: true}
+	accountRepo.EXPECT().Get(mock.Anything, acc.ID).Return(acc, nil)
+	accountRepo.EXPECT().Update(mock.Anything, acc.ID, mock.MatchedBy(
+		func(u dto.AccountUpdate) bool { return u.AutoConvert != nil && !*u.AutoConvert },
+	)).Return(nil).Once()
+	svc := accountsvc.New(nil, uow, slog.Default(), nil)
+
+	got, err := svc.SetAutoConvert(context.Background(), userID, acc.ID, false)
+	require.NoError(t, err)
+	assert.False(t, got.AutoConvert)
+
+	_, err = svc.SetAutoConvert(context.Background(), uuid.New(), acc.ID, true)
+	require.ErrorIs(t, err, accountdomain.ErrAccountNotFound)
+}
@@ -0,0 +1,200 @@
+package account
+
+import (
+	"context"
+
+	"github.com/amirasaad/fintech/pkg/domain/events"
+	"github.com/amirasaad/fintech/pkg/dto"
+	"github.com/amirasaad/fintech/pkg/eventbus"
+	"github.com/amirasaad/fintech/pkg/handler/common"
+	"github.com/amirasaad/fintech/pkg/repository"
+	"github.com/google/uuid"
+)
+
+// TransactionCache caches the transaction lists of accounts that
+// GetTransactions returns.
+//
+// Entries are versioned so that a list read from the database while the
+// account's transactions changed is never served: Get returns the account's
+// current version, Set stores the list under it, and Invalidate moves the
+// account to a new version.
+type TransactionCache interface {
+	// Get returns the cached transactions of the account, with ok false on
+	// a miss, and the account's current version to pass to Set.
+	Get(
+		ctx context.Context,
+		accountID uuid.UUID,
+	) (txs []*dto.TransactionRead, version int64, ok bool, err error)
+	// Set caches the transactions of the account read at version. It is
+	// not served if the account has been invalidated since.
+	Set(ctx context.Context, accountID uuid.UUID, version int64, txs []*dto.TransactionRead) error
+	// Invalidate drops the cached transactions of the accounts.
+	Invalidate(ctx context.Context, accountIDs ...uuid.UUID) error
+}
+
+// TransactionCacheEventTypes are the event types emitted after transactions
+// are created or updated. HandleTransactionsChanged must be registered for
+// each, after the handlers that write the transactions.
+var TransactionCacheEventTypes = []events.EventType{
+	events.EventTypeDepositRequested,
+	events.EventTypeDepositCanceled,
+	events.EventTypeDepositReversed,
+	events.EventTypeWithdrawRequested,
+	events.EventTypeWithdrawValidated,
+	events.EventTypeWithdrawPending,
+	events.EventTypeWithdrawApproved,
+	events.EventTypeWithdrawRejected,
+	events.EventTypeTransferRequested,
+	events.EventTypeTransferCompleted,
+	events.EventTypeTransferPending,
+	events.EventTypeTransferApproved,
+	events.EventTypeTransferRejected,
+	events.EventTypePaymentProcessed,
+	events.EventTypePaymentCompleted,
+	events.EventTypePaymentFailed,
+	events.EventTypeFeesCalculated,
+	events.EventTypeRefundInitiated,
+	events.EventTypeRefundCompleted,
+	events.EventTypeRefundFailed,
+}
+
+// WithTransactionCache reads GetTransactions through cache. Without it every
+// call reads the database.
+func WithTransactionCache(cache TransactionCache) Option {
+	return func(s *Service) {
+		s.txCache = cache
+	}
+}
+
+// HasTransactionCache reports whether GetTransactions reads through a cache,
+// which HandleTransactionsChanged must then keep fresh.
+func (s *Service) HasTransactionCache() bool {
+	return s.txCache != nil
+}
+
+// HandleTransactionsChanged returns an event handler that invalidates the
+// cached transactions of the accounts whose transactions the event's flow
+// created or updated. Register it for TransactionCacheEventTypes in the
+// best-effort phase. Failing to invalidate is logged, not returned: the
+// cache's TTL bounds how long a stale list is served.
+func (s *Service) HandleTransactionsChanged() eventbus.HandlerFunc {
+	return func(ctx context.Context, e events.Event) error {
+		if s.txCache == nil {
+			return nil
+		}
+		log := s.logger.With(
+			"handler", "account.HandleTransactionsChanged",
+			"event_type", e.Type(),
+		)
+		accountIDs, err := s.changedAccounts(ctx, e)
+		if err != nil {
+			log.Warn("failed to resolve accounts to invalidate", "error", err)
+			return nil
+		}
+		s.invalidateTransactions(ctx, accountIDs...)
+		return nil
+	}
+}
+
+// changedAccounts returns the IDs of the accounts whose transactions the
+// event's flow changed. Payment, fee and refund events are resolved through
+// their transaction when they do not carry the account, as provider webhooks
+// may not.
+func (s *Service) changedAccounts(ctx context.Context, e events.Event) ([]uuid.UUID, error) {
+	var (
+		accountID     uuid.UUID
+		paymentID     *string
+		transactionID uuid.UUID
+	)
+	switch evt := e.(type) {
+	case *events.DepositRequested:
+		return []uuid.UUID{evt.AccountID}, nil
+	case *events.DepositCanceled:
+		return []uuid.UUID{evt.AccountID}, nil
+	case *events.DepositReversed:
+		return []uuid.UUID{evt.AccountID}, nil
+	case *events.WithdrawRequested:
+		return []uuid.UUID{evt.AccountID}, nil
+	case *events.WithdrawValidated:
+		return []uuid.UUID{evt.AccountID}, nil
+	case *events.WithdrawPending:
+		return []uuid.UUID{evt.AccountID}, nil
+	case *events.WithdrawApproved:
+		return []uuid.UUID{evt.AccountID}, nil
+	case *events.WithdrawRejected:
+		return []uuid.UUID{evt.AccountID}, nil
+	case *events.TransferRequested:
+		return []uuid.UUID{evt.AccountID, evt.DestAccountID}, nil
+	case *events.TransferCompleted:
+		if tr, ok := evt.OriginalRequest.(*events.TransferRequested); ok {
+			return []uuid.UUID{tr.AccountID, tr.DestAccountID}, nil
+		}
+		return []uuid.UUID{evt.AccountID}, nil
+	case *events.TransferPending:
+		return []uuid.UUID{evt.AccountID, evt.DestAccountID}, nil
+	case *events.TransferApproved:
+		return []uuid.UUID{evt.AccountID, evt.DestAccountID}, nil
+	case *events.TransferRejected:
+		return []uuid.UUID{evt.AccountID, evt.DestAccountID}, nil
+	case *events.RefundInitiated:
+		return []uuid.UUID{evt.AccountID}, nil
+	case *events.PaymentProcessed:
+		accountID, paymentID, transactionID = evt.AccountID, evt.PaymentID, evt.TransactionID
+	case *events.PaymentCompleted:
+		accountID, paymentID, transactionID = evt.AccountID, evt.PaymentID, evt.TransactionID
+	case *events.FeesCalculated:
+		accountID, transactionID = evt.AccountID, evt.TransactionID
+	case *events.RefundCompleted:
+		accountID, paymentID, transactionID = evt.AccountID, &evt.RefundID, evt.TransactionID
+	case *events.RefundFailed:
+		accountID, paymentID, transactionID = evt.AccountID, &evt.RefundID, evt.TransactionID
+	default:
+		return nil, nil
+	}
+	if accountID != uuid.Nil {
+		return []uuid.UUID{accountID}, nil
+	}
+
+	var accountIDs []uuid.UUID
+	err := s.uow.Do(ctx, func(uow repository.UnitOfWork) error {
+		txRepo, err := common.GetTransactionRepository(uow, s.logger)
+		if err != nil {
+			return err
+		}
+		lookup := common.LookupTransactionByPaymentOrID(
+			ctx, txRepo, paymentID, transactionID, s.logger,
+		)
+		if lookup.Error != nil {
+			return lookup.Error
+		}
+		if lookup.Found {
+			accountIDs = []uuid.UUID{lookup.Transaction.AccountID}
+		}
+		return nil
+	})
+	return accountIDs, err
+}
+
+// invalidateTransactions drops the cached transactions of the accounts,
+// logging failures.
+func (s *Service) invalidateTransactions(ctx context.Context, accountIDs ...uuid.UUID) {
+	if s.txCache == nil {
+		return
+	}
+	ids := make([]uuid.UUID, 0, len(accountIDs))
+	for _, id := range accountIDs {
+		if id != uuid.Nil {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return
+	}
+	if err := s.txCache.Invalidate(ctx, ids...); err != nil {
+		s.logger.Warn(
+			"failed to invalidate cached transactions",
+			"account_ids", ids,
+			"error", err,
+		)
+	}
+}
@@ -0,0 +1,233 @@
+package account_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/amirasaad/fintech/internal/fixtures/mocks"
+	"github.com/amirasaad/fintech/pkg/domain/events"
+	"github.com/amirasaad/fintech/pkg/dto"
+	"github.com/amirasaad/fintech/pkg/repository"
+	repoaccount "github.com/amirasaad/fintech/pkg/repository/account"
+	"github.com/amirasaad/fintech/pkg/repository/transaction"
+	accountsvc "github.com/amirasaad/fintech/pkg/service/account"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeTransactionCache is an in-memory accountsvc.TransactionCache.
+type fakeTransactionCache struct {
+	mu       sync.Mutex
+	versions map[uuid.UUID]int64
+	lists    map[uuid.UUID]fakeCacheEntry
+	getErr   error
+}
+
+type fakeCacheEntry struct {
+	version int64
+	txs     []*dto.TransactionRead
+}
+
+func newFakeTransactionCache() *fakeTransactionCache {
+	return &fakeTransactionCache{
+		versions: map[uuid.UUID]int64{},
+		lists:    map[uuid.UUID]fakeCacheEntry{},
+	}
+}
+
+func (c *fakeTransactionCache) Get(
+	_ context.Context,
+	accountID uuid.UUID,
+) ([]*dto.TransactionRead, int64, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.getErr != nil {
+		return nil, 0, false, c.getErr
+	}
+	version := c.versions[accountID]
+	entry, ok := c.lists[accountID]
+	if !ok || entry.version != version {
+		return nil, version, false, nil
+	}
+	return entry.txs, version, true, nil
+}
+
+func (c *fakeTransactionCache) Set(
+	_ context.Context,
+	accountID uuid.UUID,
+	version int64,
+	txs []*dto.TransactionRead,
+) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.lists[accountID] = fakeCacheEntry{version: version, txs: txs}
+	return nil
+}
+
+func (c *fakeTransactionCache) Invalidate(_ context.Context, accountIDs ...uuid.UUID) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, id := range accountIDs {
+		c.versions[id]++
+		delete(c.lists, id)
+	}
+	return nil
+}
+
+func (c *fakeTransactionCache) cached(accountID uuid.UUID) bool {
+	_, _, ok, _ := c.Get(context.Background(), accountID)
+	return ok
+}
+
+func TestGetTransactions_Cache(t *testing.T) {
+	userID, accountID := uuid.New(), uuid.New()
+	txs := []*dto.TransactionRead{
+		{ID: uuid.New(), UserID: userID, AccountID: accountID, Amount: 100, Currency: "USD"},
+	}
+
+	setup := func(t *testing.T, cache accountsvc.TransactionCache) (
+		*accountsvc.Service,
+		*mocks.TransactionRepository,
+	) {
+		uow := mocks.NewUnitOfWork(t)
+		accountRepo := mocks.NewAccountRepository(t)
+		txRepo := mocks.NewTransactionRepository(t)
+		uow.EXPECT().GetRepository((*repoaccount.Repository)(nil)).Return(accountRepo, nil)
+		uow.EXPECT().GetRepository((*transaction.Repository)(nil)).Return(txRepo, nil)
+		accountRepo.EXPECT().Get(mock.Anything, accountID).Return(&dto.AccountRead{
+			ID: accountID, UserID: userID, Currency: "USD",
+		}, nil)
+		return accountsvc.New(
+			nil, uow, slog.Default(), nil, accountsvc.WithTransactionCache(cache),
+		), txRepo
+	}
+	ctx := context.Background()
+
+	t.Run("reads the database once until invalidated", func(t *testing.T) {
+		cache := newFakeTransactionCache()
+		svc, txRepo := setup(t, cache)
+		txRepo.EXPECT().ListByAccount(mock.Anything, accountID).Return(txs, nil).Twice()
+
+		for range 3 {
+			got, err := svc.GetTransactions(ctx, userID, accountID)
+			require.NoError(t, err)
+			assert.Equal(t, txs, got)
+		}
+		require.NoError(t, cache.Invalidate(ctx, accountID))
+		got, err := svc.GetTransactions(ctx, userID, accountID)
+		require.NoError(t, err)
+		assert.Equal(t, txs, got)
+	})
+
+	t.Run("list read across an invalidation is not served", func(t *testing.T) {
+		cache := newFakeTransactionCache()
+		svc, txRepo := setup(t, cache)
+		txRepo.EXPECT().ListByAccount(mock.Anything, accountID).RunAndReturn(
+			func(ctx context.Context, _ uuid.UUID) ([]*dto.TransactionRead, error) {
+				// A deposit completes while the list is being read.
+				require.NoError(t, cache.Invalidate(ctx, accountID))
+				return txs, nil
+			},
+		).Once()
+		_, err := svc.GetTransactions(ctx, userID, accountID)
+		require.NoError(t, err)
+		assert.False(t, cache.cached(accountID))
+	})
+
+	t.Run("fills the cache from the primary", func(t *testing.T) {
+		svc, txRepo := setup(t, newFakeTransactionCache())
+		txRepo.EXPECT().ListByAccount(
+			mock.MatchedBy(func(ctx context.Context) bool {
+				return !repository.ReplicaReadsAllowed(ctx)
+			}),
+			accountID,
+		).Return(txs, nil).Once()
+		_, err := svc.GetTransactions(repository.WithReplicaReads(ctx), userID, accountID)
+		require.NoError(t, err)
+	})
+
+	t.Run("falls back to the database when the cache fails", func(t *testing.T) {
+		cache := newFakeTransactionCache()
+		cache.getErr = errors.New("redis down")
+		svc, txRepo := setup(t, cache)
+		txRepo.EXPECT().ListByAccount(mock.Anything, accountID).Return(txs, nil).Once()
+		got, err := svc.GetTransactions(ctx, userID, accountID)
+		require.NoError(t, err)
+		assert.Equal(t, txs, got)
+		assert.Empty(t, cache.lists)
+	})
+}
+
+func TestHandleTransactionsChanged(t *testing.T) {
+	source, dest := uuid.New(), uuid.New()
+	paymentID := "pi_1"
+
+	uow := mocks.NewUnitOfWork(t)
+	txRepo := mocks.NewTransactionRepository(t)
+	uow.EXPECT().Do(mock.Anything, mock.Anything).RunAndReturn(
+		func(ctx context.Context, fn func(repository.UnitOfWork) error) error {
+			return fn(uow)
+		},
+	).Maybe()
+	uow.EXPECT().GetRepository((*transaction.Repository)(nil)).Return(txRepo, nil).Maybe()
+	txRepo.EXPECT().GetByPaymentID(mock.Anything, paymentID).Return(&dto.TransactionRead{
+		ID: uuid.New(), AccountID: source,
+	}, nil).Maybe()
+
+	cache := newFakeTransactionCache()
+	svc := accountsvc.New(nil, uow, slog.Default(), nil, accountsvc.WithTransactionCache(cache))
+	handle := svc.HandleTransactionsChanged()
+	ctx := context.Background()
+	fill := func() {
+		for _, id := range []uuid.UUID{source, dest} {
+			_, version, _, _ := cache.Get(ctx, id)
+			require.NoError(t, cache.Set(ctx, id, version, nil))
+		}
+	}
+
+	tests := []struct {
+		name        string
+		event       events.Event
+		invalidated []uuid.UUID
+	}{
+		{
+			name:        "deposit requested",
+			event:       events.NewDepositRequested(uuid.New(), source, uuid.New()),
+			invalidated: []uuid.UUID{source},
+		},
+		{
+			name: "transfer approved",
+			event: &events.TransferApproved{TransferPending: events.TransferPending{
+				FlowEvent:     events.FlowEvent{AccountID: source},
+				DestAccountID: dest,
+			}},
+			invalidated: []uuid.UUID{source, dest},
+		},
+		{
+			name: "webhook payment completed without account",
+			event: &events.PaymentCompleted{PaymentInitiated: events.PaymentInitiated{
+				PaymentID: &paymentID,
+			}},
+			invalidated: []uuid.UUID{source},
+		},
+		{
+			name:  "unrelated event",
+			event: &events.DLQThresholdExceeded{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fill()
+			require.NoError(t, handle(ctx, tt.event))
+			for _, id := range []uuid.UUID{source, dest} {
+				assert.Equal(t, !slices.Contains(tt.invalidated, id), cache.cached(id), id)
+			}
+		})
+	}
+}
@@ -147,7 +147,14 @@ func (s *JWTStrategy) GenerateToken(
 	claims["username"] = u.Username
 	claims["email"] = u.Email
 	claims["user_id"] = u.ID.String()
+	claims["role"] = u.Role
 	claims["exp"] = time.Now().Add(s.cfg.Expiry).Unix()
+	if s.cfg.Issuer != "" {
+		claims["iss"] = s.cfg.Issuer
+	}
+	if s.cfg.Audience != "" {
+		claims["aud"] = s.cfg.Audience
+	}
 	tokenString, err := token.SignedString([]byte(s.cfg.Secret))
 	if err != nil {
 		log.Error("GenerateToken failed", "userID", u.ID, "error", err)
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/amirasaad/fintech/pkg/config"
 
@@ -187,6 +188,20 @@ func TestGetCurrentUserId_InvalidToken(t *testing.T) {
 	require.Error(t, err)
 }
 
+func TestGenerateToken_IssuerAudience(t *testing.T) {
+	t.Parallel()
+	cfg := &config.Jwt{Secret: "secret", Expiry: time.Hour, Issuer: "fintech", Audience: "fintech-api"}
+	s := authsvc.NewJWTStrategy(nil, cfg, slog.Default())
+	signed, err := s.GenerateToken(context.Background(), &dto.UserRead{ID: uuid.New()})
+	require.NoError(t, err)
+
+	token, err := jwt.Parse(signed, func(*jwt.Token) (any, error) {
+		return []byte(cfg.Secret), nil
+	}, jwt.WithIssuer("fintech"), jwt.WithAudience("fintech-api"))
+	require.NoError(t, err)
+	assert.True(t, token.Valid)
+}
+
 func TestGetCurrentUserId_MissingClaim(t *testing.T) {
 	t.Parallel()
 	uow := mocks.NewUnitOfWork(t)
@@ -17,6 +17,7 @@ import (
 type Session struct {
 	ID            string    `json:"id"`
 	TransactionID uuid.UUID `json:"transaction_id"`
+	PaymentID     string    `json:"payment_id,omitempty"`
 	UserID        uuid.UUID `json:"user_id"`
 	AccountID     uuid.UUID `json:"account_id"`
 	Amount        int64     `json:"amount"`
@@ -41,7 +42,10 @@ func New(reg registry.Provider, logger *slog.Logger) *Service {
 	}
 }
 
-// CreateSession creates a new checkout session
+// CreateSession creates a new checkout session. It is idempotent per
+// transaction: if an active session already exists for txID, that session is
+// returned and nothing is saved, so a retried payment does not leave two
+// records for one transaction.
 func (s *Service) CreateSession(
 	ctx context.Context,
 	sessionID string,
@@ -58,6 +62,7 @@ func (s *Service) CreateSession(
 	session := &Session{
 		ID:            sessionID,
 		TransactionID: txID,
+		PaymentID:     id,
 		UserID:        userID,
 		AccountID:     accountID,
 		Amount:        amount,
@@ -73,6 +78,18 @@ func (s *Service) CreateSession(
 		return nil, fmt.Errorf("invalid session: %w", err)
 	}
 
+	existing, err := s.GetActiveSessionByTransactionID(ctx, txID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		s.logger.Info("Reusing active checkout session",
+			"transaction_id", txID,
+			"session_id", existing.ID,
+		)
+		return existing, nil
+	}
+
 	// Save to registry
 	if err := s.saveSession(session); err != nil {
 		return nil, fmt.Errorf("failed to save session: %w", err)
@@ -121,6 +138,36 @@ func (s *Service) GetSessionByTransactionID(
 	return s.entityToSession(entities[0])
 }
 
+// GetActiveSessionByTransactionID returns the most recently created session
+// for txID that has neither expired nor ended, or nil if there is none.
+func (s *Service) GetActiveSessionByTransactionID(
+	ctx context.Context,
+	txID uuid.UUID,
+) (*Session, error) {
+	entities, err := s.registry.ListByMetadata(ctx, "transaction_id", txID.String())
+	if err != nil {
+		return nil, fmt.Errorf("error searching for session: %w", err)
+	}
+
+	now := time.Now().UTC()
+	var active *Session
+	for _, entity := range entities {
+		session, err := s.entityToSession(entity)
+		if err != nil {
+			s.logger.Warn("skipping invalid checkout session",
+				"id", entity.ID(), "error", err)
+			continue
+		}
+		if !session.IsActive(now) {
+			continue
+		}
+		if active == nil || session.CreatedAt.After(active.CreatedAt) {
+			active = session
+		}
+	}
+	return active, nil
+}
+
 // GetSessionsByUserID retrieves all checkout sessions for a given user ID
 func (s *Service) GetSessionsByUserID(ctx context.Context, userID uuid.UUID) ([]*Session, error) {
 	entities, err := s.registry.ListByMetadata(ctx, "user_id", userID.String())
@@ -178,6 +225,16 @@ func (s *Service) UpdateStatus(
 	return nil
 }
 
+// IsActive reports whether the session can still be paid at the given time:
+// it has not expired and has not been completed, canceled or failed.
+func (s *Session) IsActive(now time.Time) bool {
+	switch s.Status {
+	case "expired", "canceled", "failed", "completed":
+		return false
+	}
+	return s.ExpiresAt.IsZero() || now.Before(s.ExpiresAt)
+}
+
 // Validate checks if the session is valid
 func (s *Session) Validate() error {
 	if s.ID == "" {
@@ -230,6 +287,7 @@ func (s *Service) saveSession(session *Session) error {
 
 	// Add all fields as metadata for searchability
 	entity.SetMetadata("transaction_id", session.TransactionID.String())
+	entity.SetMetadata("payment_id", session.PaymentID)
 	entity.SetMetadata("user_id", session.UserID.String())
 	entity.SetMetadata("account_id", session.AccountID.String())
 	entity.SetMetadata("amount", fmt.Sprintf("%d", session.Amount))
@@ -305,6 +363,7 @@ func (s *Service) entityToSession(entity registry.Entity) (*Session, error) {
 	}
 
 	// Set other fields
+	session.PaymentID = metadata["payment_id"]
 	session.Currency = metadata["currency"]
 	session.Status = metadata["status"]
 	session.CheckoutURL = metadata["checkout_url"]
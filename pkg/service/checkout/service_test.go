@@ -39,6 +39,12 @@ func TestService_CreateSession(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			mr := mocks.NewRegistryProvider(t)
 			if !tt.wantErr || tt.registryErr != nil {
+				mr.On(
+					"ListByMetadata",
+					mock.Anything,
+					"transaction_id",
+					mock.Anything,
+				).Return([]registry.Entity{}, nil)
 				mr.On(
 					"Register",
 					mock.Anything,
@@ -70,6 +76,37 @@ func TestService_CreateSession(t *testing.T) {
 	}
 }
 
+func TestService_CreateSession_ReusesActiveSession(t *testing.T) {
+	ctx := context.Background()
+	txID, userID, accountID := uuid.New(), uuid.New(), uuid.New()
+	reg := registry.NewEnhanced(registry.Config{Name: "checkout-test"})
+	svc := New(reg, slog.Default())
+
+	first, err := svc.CreateSession(ctx, "cs_1", "pi_1", txID, userID, accountID,
+		1000, "USD", "https://checkout.example.com/1", time.Hour)
+	require.NoError(t, err)
+
+	retry, err := svc.CreateSession(ctx, "cs_2", "pi_2", txID, userID, accountID,
+		1000, "USD", "https://checkout.example.com/2", time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, first.ID, retry.ID)
+	assert.Equal(t, "pi_1", retry.PaymentID)
+	_, err = svc.GetSession(ctx, "cs_2")
+	require.Error(t, err, "a retry must not save a second session")
+
+	// Once the session has expired a new one is created.
+	require.NoError(t, svc.UpdateStatus(ctx, first.ID, "expired"))
+	next, err := svc.CreateSession(ctx, "cs_3", "pi_3", txID, userID, accountID,
+		1000, "USD", "https://checkout.example.com/3", time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, "cs_3", next.ID)
+
+	active, err := svc.GetActiveSessionByTransactionID(ctx, txID)
+	require.NoError(t, err)
+	require.NotNil(t, active)
+	assert.Equal(t, "cs_3", active.ID)
+}
+
 func TestService_GetSession(t *testing.T) {
 	transactionID := uuid.New()
 	userID := uuid.New()
@@ -59,6 +59,16 @@ func (s *Service) Get(ctx context.Context, code string) (*money.Currency, error)
 	return meta, nil
 }
 
+// GetEntity retrieves a currency by code with its full metadata, including
+// the display symbol.
+func (s *Service) GetEntity(ctx context.Context, code string) (*Entity, error) {
+	entity, err := s.registry.Get(ctx, code)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get currency: %w", err)
+	}
+	return toEntity(entity)
+}
+
 // ListSupported returns all supported currency codes
 func (s *Service) ListSupported(ctx context.Context) ([]string, error) {
 	entities, err := s.registry.ListActive(ctx)
@@ -137,6 +147,16 @@ func (s *Service) IsSupported(ctx context.Context, code string) bool {
 	return entity.Active()
 }
 
+// IsDeactivated reports whether a currency is registered but inactive.
+// Unregistered currencies are not considered deactivated.
+func (s *Service) IsDeactivated(ctx context.Context, code string) bool {
+	entity, err := s.registry.Get(ctx, code)
+	if err != nil {
+		return false
+	}
+	return !entity.Active()
+}
+
 // Search searches for currencies by name
 func (s *Service) Search(
 	ctx context.Context,
@@ -261,3 +281,22 @@ func toCurrency(entity registry.Entity) (*money.Currency, error) {
 		Decimals: decimals,
 	}, nil
 }
+
+// toEntity converts a registry.Entity to an Entity with its metadata parsed.
+func toEntity(entity registry.Entity) (*Entity, error) {
+	c, err := toCurrency(entity)
+	if err != nil {
+		return nil, err
+	}
+	metadata := entity.Metadata()
+	return &Entity{
+		Entity:   entity,
+		Code:     c.Code,
+		Name:     entity.Name(),
+		Symbol:   metadata["symbol"],
+		Decimals: c.Decimals,
+		Country:  metadata["country"],
+		Region:   metadata["region"],
+		Active:   entity.Active(),
+	}, nil
+}
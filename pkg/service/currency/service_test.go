@@ -94,6 +94,20 @@ func TestCurrencyService(t *testing.T) {
 		require.Error(err)
 	})
 
+	t.Run("get currency entity", func(t *testing.T) {
+		service := setupTestService()
+
+		eur, err := service.GetEntity(ctx, "EUR")
+		require.NoError(err)
+		assert.Equal(money.Code("EUR"), eur.Code)
+		assert.Equal("€", eur.Symbol)
+		assert.Equal(2, eur.Decimals)
+		assert.True(eur.Active)
+
+		_, err = service.GetEntity(ctx, "INVALID")
+		require.Error(err)
+	})
+
 	t.Run("list supported currencies", func(t *testing.T) {
 		service := setupTestService()
 
@@ -212,6 +226,7 @@ func TestCurrencyService(t *testing.T) {
 		// Now should be supported
 		isSupported = service.IsSupported(ctx, "TSV")
 		assert.True(isSupported, "Active currency should be supported")
+		assert.False(service.IsDeactivated(ctx, "TSV"))
 
 		// Deactivate
 		err = service.Deactivate(ctx, "TSV")
@@ -220,6 +235,8 @@ func TestCurrencyService(t *testing.T) {
 		// Should no longer be supported
 		isSupported = service.IsSupported(ctx, "TSV")
 		assert.False(isSupported, "Deactivated currency should not be supported")
+		assert.True(service.IsDeactivated(ctx, "TSV"))
+		assert.False(service.IsDeactivated(ctx, "XXX"), "Unregistered currency is not deactivated")
 
 		// Clean up
 		err = service.Unregister(ctx, "TSV")
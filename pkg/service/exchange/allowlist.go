@@ -0,0 +1,81 @@
+package exchange
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/amirasaad/fintech/pkg/provider/exchange"
+)
+
+// PairAllowlist restricts the conversions the service makes. A pair is
+// allowed if it is listed, or if both of its currencies are allowed
+// currencies. An empty allowlist allows every pair.
+type PairAllowlist struct {
+	pairs      map[string]struct{}
+	currencies map[string]struct{}
+}
+
+// NewPairAllowlist creates an allowlist of pairs, given as FROM/TO currency
+// codes, and of currencies converted between freely. Pairs are directional:
+// list USD/EUR and EUR/USD to allow both.
+func NewPairAllowlist(pairs, currencies []string) (*PairAllowlist, error) {
+	a := &PairAllowlist{
+		pairs:      make(map[string]struct{}, len(pairs)),
+		currencies: make(map[string]struct{}, len(currencies)),
+	}
+	for _, pair := range pairs {
+		from, to, ok := strings.Cut(strings.ToUpper(strings.TrimSpace(pair)), "/")
+		if !ok || from == "" || to == "" {
+			return nil, fmt.Errorf("invalid currency pair %q", pair)
+		}
+		a.pairs[pairKey(from, to)] = struct{}{}
+	}
+	for _, code := range currencies {
+		code = strings.ToUpper(strings.TrimSpace(code))
+		if code == "" {
+			continue
+		}
+		a.currencies[code] = struct{}{}
+	}
+	return a, nil
+}
+
+// Allows reports whether converting from one currency to another is
+// allowed. Converting a currency to itself always is.
+func (a *PairAllowlist) Allows(from, to string) bool {
+	if a == nil || (len(a.pairs) == 0 && len(a.currencies) == 0) {
+		return true
+	}
+	from, to = strings.ToUpper(from), strings.ToUpper(to)
+	if from == to {
+		return true
+	}
+	if _, ok := a.pairs[pairKey(from, to)]; ok {
+		return true
+	}
+	_, fromOK := a.currencies[from]
+	_, toOK := a.currencies[to]
+	return fromOK && toOK
+}
+
+// WithPairAllowlist restricts conversions to the pairs allowlist allows.
+// Disallowed pairs fail with exchange.ErrConversionNotAllowed before the
+// cache or provider is consulted. Without it every pair is allowed.
+func WithPairAllowlist(allowlist *PairAllowlist) Option {
+	return func(s *Service) {
+		s.allowlist = allowlist
+	}
+}
+
+// checkAllowed returns exchange.ErrConversionNotAllowed if the pair is not
+// allowed.
+func (s *Service) checkAllowed(from, to string) error {
+	if s.allowlist.Allows(from, to) {
+		return nil
+	}
+	return fmt.Errorf("%w: %s/%s", exchange.ErrConversionNotAllowed, from, to)
+}
+
+func pairKey(from, to string) string {
+	return from + "/" + to
+}
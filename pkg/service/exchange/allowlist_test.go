@@ -0,0 +1,102 @@
+package exchange
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/amirasaad/fintech/internal/fixtures/mocks"
+	"github.com/amirasaad/fintech/pkg/money"
+	"github.com/amirasaad/fintech/pkg/provider/exchange"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPairAllowlist_Allows(t *testing.T) {
+	allowlist, err := NewPairAllowlist(
+		[]string{"USD/EUR", " usd/jpy "},
+		[]string{"GBP", "chf"},
+	)
+	require.NoError(t, err)
+
+	tests := []struct {
+		from, to string
+		allowed  bool
+	}{
+		{"USD", "EUR", true},
+		{"usd", "JPY", true},
+		{"EUR", "USD", false}, // pairs are directional
+		{"GBP", "CHF", true},
+		{"CHF", "GBP", true},
+		{"USD", "GBP", false}, // only one currency is allowed
+		{"JPY", "JPY", true},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.allowed, allowlist.Allows(tt.from, tt.to), tt.from+"/"+tt.to)
+	}
+
+	empty, err := NewPairAllowlist(nil, nil)
+	require.NoError(t, err)
+	assert.True(t, empty.Allows("USD", "EUR"))
+	var unset *PairAllowlist
+	assert.True(t, unset.Allows("USD", "EUR"))
+
+	_, err = NewPairAllowlist([]string{"USDEUR"}, nil)
+	require.Error(t, err)
+}
+
+func TestService_PairAllowlist(t *testing.T) {
+	ctx := context.Background()
+	allowlist, err := NewPairAllowlist([]string{"USD/EUR"}, nil)
+	require.NoError(t, err)
+
+	newService := func(t *testing.T) (*Service, *mocks.ExchangeProvider, *mocks.RegistryProvider) {
+		provider := mocks.NewExchangeProvider(t)
+		registry := mocks.NewRegistryProvider(t)
+		return New(
+			registry,
+			provider,
+			slog.New(slog.NewTextHandler(io.Discard, nil)),
+			WithPairAllowlist(allowlist),
+		), provider, registry
+	}
+
+	t.Run("allowed pair is converted", func(t *testing.T) {
+		svc, provider, registry := newService(t)
+		registry.On("Get", ctx, "USD:EUR").Return(nil, nil).Once()
+		registry.On("Register", ctx, mock.Anything).Return(nil)
+		provider.On("FetchRate", ctx, "USD", "EUR").Return(&exchange.RateInfo{
+			FromCurrency: "USD",
+			ToCurrency:   "EUR",
+			Rate:         0.5,
+			Provider:     "test",
+		}, nil).Once()
+		provider.On("Metadata").Return(exchange.ProviderMetadata{Name: "test"}).Maybe()
+
+		amount, err := money.New(10, "USD")
+		require.NoError(t, err)
+		result, _, err := svc.Convert(ctx, amount, "EUR")
+		require.NoError(t, err)
+		assert.InDelta(t, 5.0, result.AmountFloat(), 0.0001)
+	})
+
+	t.Run("disallowed pair fails without reaching cache or provider", func(t *testing.T) {
+		svc, _, _ := newService(t)
+		amount, err := money.New(10, "EUR")
+		require.NoError(t, err)
+		_, _, err = svc.Convert(ctx, amount, "USD")
+		require.ErrorIs(t, err, exchange.ErrConversionNotAllowed)
+		_, err = svc.GetRate(ctx, "USD", "JPY")
+		require.ErrorIs(t, err, exchange.ErrConversionNotAllowed)
+	})
+
+	t.Run("IsSupported reflects the allowlist", func(t *testing.T) {
+		svc, provider, _ := newService(t)
+		provider.On("IsSupported", "USD", "EUR").Return(true).Once()
+		assert.True(t, svc.IsSupported("USD", "EUR"))
+		assert.False(t, svc.IsSupported("USD", "JPY"))
+		assert.True(t, svc.IsSupported("JPY", "JPY"))
+	})
+}
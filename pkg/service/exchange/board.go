@@ -0,0 +1,81 @@
+package exchange
+
+import (
+	"context"
+	"time"
+
+	"github.com/amirasaad/fintech/pkg/provider/exchange"
+)
+
+const (
+	// MaxRateBoardPairs caps the currencies a rate board quotes.
+	MaxRateBoardPairs = 200
+	// RateBoardMaxFetches is how many uncached rates one rate board fetches
+	// from the provider; the rest are reported unavailable until a later
+	// board or conversion caches them.
+	RateBoardMaxFetches = 5
+)
+
+// BoardRate is the rate from a rate board's base to one currency.
+type BoardRate struct {
+	Currency string
+	// Rate is nil if no rate is cached and none could be fetched.
+	Rate *exchange.RateInfo
+	// Stale reports a rate older than the cache's rate max age.
+	Stale bool
+}
+
+// WithRateMaxAge sets how old a rate may be before a rate board reports it
+// stale. The default is DefaultCacheTTL.
+func WithRateMaxAge(maxAge time.Duration) Option {
+	return func(s *Service) {
+		if maxAge > 0 {
+			s.rateMaxAge = maxAge
+		}
+	}
+}
+
+// RateBoard returns the rates from base to each of quotes, in order, for at
+// most MaxRateBoardPairs quotes. Rates are served from the cache; at most
+// RateBoardMaxFetches uncached rates are fetched from the provider, so a
+// board never spends much of the provider's quota.
+func (s *Service) RateBoard(ctx context.Context, base string, quotes []string) []BoardRate {
+	if len(quotes) > MaxRateBoardPairs {
+		quotes = quotes[:MaxRateBoardPairs]
+	}
+	maxAge := s.rateMaxAge
+	if maxAge <= 0 {
+		maxAge = DefaultCacheTTL
+	}
+
+	board := make([]BoardRate, 0, len(quotes))
+	fetches := 0
+	for _, quote := range quotes {
+		br := BoardRate{Currency: quote}
+		switch {
+		case s.checkAllowed(base, quote) != nil:
+		case base == quote:
+			br.Rate, _ = s.GetRate(ctx, base, quote)
+		default:
+			rate, ok := s.getRateFromCache(ctx, base, quote)
+			if !ok && fetches < RateBoardMaxFetches && s.provider != nil {
+				fetches++
+				var err error
+				if rate, err = s.GetRate(ctx, base, quote); err != nil {
+					s.logger.Warn("Failed to fetch rate board rate",
+						"from", base,
+						"to", quote,
+						"error", err,
+					)
+					rate = nil
+				}
+			}
+			br.Rate = rate
+		}
+		if br.Rate != nil && !br.Rate.Timestamp.IsZero() {
+			br.Stale = time.Since(br.Rate.Timestamp) > maxAge
+		}
+		board = append(board, br)
+	}
+	return board
+}
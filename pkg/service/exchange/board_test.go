@@ -0,0 +1,92 @@
+package exchange
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/amirasaad/fintech/internal/fixtures/mocks"
+	"github.com/amirasaad/fintech/pkg/provider/exchange"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_RateBoard(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	provider := mocks.NewExchangeProvider(t)
+	registry := mocks.NewRegistryProvider(t)
+
+	cached := func(to string, rate float64, at time.Time) {
+		registry.On("Get", ctx, "USD:"+to).Return(&ExchangeRateInfo{
+			From: "USD", To: to, Rate: rate, Source: "test", Timestamp: at,
+		}, nil)
+	}
+	cached("EUR", 0.9, now.Add(-time.Minute))
+	cached("GBP", 0.8, now.Add(-2*time.Hour))
+	registry.On("Get", ctx, mock.Anything).Return(nil, nil)
+	registry.On("Register", ctx, mock.Anything).Return(nil).Maybe()
+	provider.On("Metadata").Return(exchange.ProviderMetadata{Name: "test"}).Maybe()
+	provider.On("FetchRate", ctx, "USD", "JPY").Return(&exchange.RateInfo{
+		FromCurrency: "USD", ToCurrency: "JPY", Rate: 150, Timestamp: now,
+	}, nil).Once()
+	provider.On("FetchRate", ctx, "USD", "CHF").
+		Return(nil, exchange.ErrProviderUnavailable).Once()
+	// Fetches beyond RateBoardMaxFetches are not made.
+	for i := range RateBoardMaxFetches - 2 {
+		code := fmt.Sprintf("X%02d", i)
+		provider.On("FetchRate", ctx, "USD", code).Return(nil, errors.New("no rate")).Once()
+	}
+
+	allowlist, err := NewPairAllowlist(nil, []string{
+		"USD", "EUR", "GBP", "JPY", "CHF", "X00", "X01", "X02", "AUD",
+	})
+	require.NoError(t, err)
+	svc := New(
+		registry,
+		provider,
+		slog.New(slog.NewTextHandler(io.Discard, nil)),
+		WithRateMaxAge(time.Hour),
+		WithPairAllowlist(allowlist),
+	)
+
+	board := svc.RateBoard(ctx, "USD", []string{
+		"EUR", "GBP", "USD", "JPY", "CHF", "X00", "X01", "X02", "AUD", "CAD",
+	})
+	require.Len(t, board, 10)
+	byCode := map[string]BoardRate{}
+	for _, br := range board {
+		byCode[br.Currency] = br
+	}
+
+	assert.InDelta(t, 0.9, byCode["EUR"].Rate.Rate, 1e-9)
+	assert.False(t, byCode["EUR"].Stale)
+	assert.InDelta(t, 0.8, byCode["GBP"].Rate.Rate, 1e-9)
+	assert.True(t, byCode["GBP"].Stale)
+	assert.InDelta(t, 1.0, byCode["USD"].Rate.Rate, 1e-9)
+	assert.InDelta(t, 150.0, byCode["JPY"].Rate.Rate, 1e-9)
+	for _, code := range []string{"CHF", "X00", "X01", "X02"} {
+		assert.Nil(t, byCode[code].Rate, code)
+	}
+	// Past the fetch limit, and not on the allowlist.
+	assert.Nil(t, byCode["AUD"].Rate)
+	assert.Nil(t, byCode["CAD"].Rate)
+}
+
+func TestService_RateBoard_CapsPairs(t *testing.T) {
+	ctx := context.Background()
+	registry := mocks.NewRegistryProvider(t)
+	registry.On("Get", ctx, mock.Anything).Return(&ExchangeRateInfo{Rate: 1}, nil)
+	svc := New(registry, nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	quotes := make([]string, MaxRateBoardPairs+10)
+	for i := range quotes {
+		quotes[i] = fmt.Sprintf("C%02d", i)
+	}
+	assert.Len(t, svc.RateBoard(ctx, "USD", quotes), MaxRateBoardPairs)
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/amirasaad/fintech/internal/fixtures/mocks"
 	"github.com/amirasaad/fintech/pkg/money"
+	"github.com/amirasaad/fintech/pkg/provider/exchange"
 	"github.com/amirasaad/fintech/pkg/registry"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -91,3 +92,79 @@ func TestService_Convert(t *testing.T) {
 		})
 	}
 }
+
+func TestService_Convert_QuotaExhausted(t *testing.T) {
+	ctx := context.Background()
+	amount, _ := money.New(100, "USD")
+	mockProvider := mocks.NewExchangeProvider(t)
+	mockRegistry := mocks.NewRegistryProvider(t)
+
+	// The provider reports its quota exhausted on the first call; no further
+	// calls reach it.
+	mockProvider.On("FetchRate", ctx, "USD", "JPY").
+		Return(nil, exchange.ErrRateProviderQuotaExhausted).
+		Once()
+	mockRegistry.On("Get", ctx, "USD:JPY").Return(nil, nil).Twice()
+	mockRegistry.On("Get", ctx, "USD:EUR").Return(&ExchangeRateInfo{
+		From: "USD",
+		To:   "EUR",
+		Rate: 0.85,
+	}, nil).Once()
+
+	svc := &Service{
+		provider: exchange.NewQuotaProvider(mockProvider, 1000, 0.9, 1),
+		registry: mockRegistry,
+		logger:   slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}
+
+	for range 2 {
+		_, _, err := svc.Convert(ctx, amount, "JPY")
+		require.ErrorIs(t, err, exchange.ErrRateProviderQuotaExhausted)
+	}
+
+	// Cached rates are still served.
+	result, _, err := svc.Convert(ctx, amount, "EUR")
+	require.NoError(t, err)
+	assert.InDelta(t, 85.0, result.AmountFloat(), 0.0001)
+}
+
+func TestService_Convert_RoundingMode(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		mode     money.RoundingMode
+		amount   float64
+		expected money.Amount
+	}{
+		// 1.01 USD at 0.5 is 50.5 cents
+		{money.RoundHalfEven, 1.01, 50},
+		{money.RoundHalfUp, 1.01, 51},
+		{money.RoundFloor, 1.01, 50},
+		// 1.03 USD at 0.5 is 51.5 cents
+		{money.RoundHalfEven, 1.03, 52},
+		{money.RoundHalfUp, 1.03, 52},
+		{money.RoundFloor, 1.03, 51},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s %.2f", tt.mode, tt.amount), func(t *testing.T) {
+			mockRegistry := mocks.NewRegistryProvider(t)
+			mockRegistry.On("Get", ctx, "USD:EUR").Return(&ExchangeRateInfo{
+				From: "USD",
+				To:   "EUR",
+				Rate: 0.5,
+			}, nil).Once()
+			svc := New(
+				mockRegistry,
+				mocks.NewExchangeProvider(t),
+				slog.New(slog.NewTextHandler(io.Discard, nil)),
+				WithRoundingMode(tt.mode),
+			)
+
+			amount, err := money.New(tt.amount, "USD")
+			require.NoError(t, err)
+			result, info, err := svc.Convert(ctx, amount, "EUR")
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result.Amount())
+			assert.Equal(t, string(tt.mode), info.RoundingMode)
+		})
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"log/slog"
 	"math"
+	"strings"
 	"time"
 
 	"github.com/amirasaad/fintech/pkg/money"
@@ -80,6 +81,24 @@ type Service struct {
 	provider exchange.Exchange
 	registry registry.Provider // Registry for cached exchange rates
 	logger   *slog.Logger
+	rounding money.RoundingMode
+	// allowlist restricts the pairs converted; nil allows all.
+	allowlist *PairAllowlist
+	// rateMaxAge is the age after which rate boards report a rate stale.
+	rateMaxAge time.Duration
+}
+
+// Option configures a Service.
+type Option func(*Service)
+
+// WithRoundingMode sets how converted amounts are rounded to the smallest
+// unit of the target currency. The default is money.DefaultRoundingMode.
+func WithRoundingMode(mode money.RoundingMode) Option {
+	return func(s *Service) {
+		if mode != "" {
+			s.rounding = mode
+		}
+	}
 }
 
 // New creates a new exchange service with the given registry and provider
@@ -87,16 +106,23 @@ func New(
 	registry registry.Provider,
 	provider exchange.Exchange,
 	log *slog.Logger,
+	opts ...Option,
 ) *Service {
 	if log == nil {
 		log = slog.Default()
 	}
 
-	return &Service{
-		provider: provider,
-		logger:   log,
-		registry: registry,
+	s := &Service{
+		provider:   provider,
+		logger:     log,
+		registry:   registry,
+		rounding:   money.DefaultRoundingMode,
+		rateMaxAge: DefaultCacheTTL,
+	}
+	for _, opt := range opts {
+		opt(s)
 	}
+	return s
 }
 
 // processAndCacheRate validates, logs, and caches a rate with TTL support.
@@ -195,17 +221,20 @@ func (s *Service) Convert(
 	}
 
 	// Convert the amount
-	converted, err := amount.Multiply(rate.Rate)
-	if err != nil {
-		return nil, nil, fmt.Errorf("failed to convert amount: %w", err)
+	rounding := s.rounding
+	if rounding == "" {
+		rounding = money.DefaultRoundingMode
 	}
-
-	result, err := money.New(converted.AmountFloat(), to)
+	result, err := amount.Convert(rate.Rate, to.ToCurrency(), rounding)
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to create money: %w", err)
+		return nil, nil, fmt.Errorf("failed to convert amount: %w", err)
 	}
 
-	return result, rate, nil
+	// Copy the rate, which may be shared with the cache, to record the
+	// rounding mode.
+	info := *rate
+	info.RoundingMode = string(rounding)
+	return result, &info, nil
 }
 
 // GetRate gets the exchange rate between two currencies with cache-first approach
@@ -230,6 +259,11 @@ func (s *Service) GetRate(
 		}, nil
 	}
 
+	// Disallowed pairs fail even if cached or servable by the provider.
+	if err := s.checkAllowed(from, to); err != nil {
+		return nil, err
+	}
+
 	// Try to get from cache first
 	if rate, ok := s.getRateFromCache(ctx, from, to); ok {
 		return rate, nil
@@ -249,10 +283,61 @@ func (s *Service) GetRate(
 	return rate, nil
 }
 
+// Warmup loads the rates of pairs, given as FROM/TO currency codes, into the
+// cache so the first conversions after startup do not wait for the provider.
+// Pairs are fetched one at a time until timeout elapses; a pair that fails is
+// logged and skipped. It returns the number of pairs whose rate is cached.
+func (s *Service) Warmup(
+	ctx context.Context,
+	pairs []string,
+	timeout time.Duration,
+) int {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	warmed := 0
+	for _, pair := range pairs {
+		from, to, ok := strings.Cut(strings.ToUpper(strings.TrimSpace(pair)), "/")
+		if !ok {
+			s.logger.Warn("Skipping invalid exchange rate warmup pair", "pair", pair)
+			continue
+		}
+		rate, err := s.GetRate(ctx, from, to)
+		if err != nil {
+			s.logger.Warn("Failed to warm up exchange rate",
+				"from", from,
+				"to", to,
+				"error", err,
+			)
+			if ctx.Err() != nil {
+				break
+			}
+			continue
+		}
+		s.logger.Info("Warmed up exchange rate",
+			"from", from,
+			"to", to,
+			"rate", rate.Rate,
+			"provider", rate.Provider,
+		)
+		warmed++
+	}
+	s.logger.Info("Exchange rate warmup finished",
+		"warmed", warmed,
+		"pairs", len(pairs),
+	)
+	return warmed
+}
+
+// IsSupported reports whether the pair is allowed and the provider supports
+// it.
 func (s *Service) IsSupported(from, to string) bool {
 	if from == to {
 		return true
 	}
+	if !s.allowlist.Allows(from, to) {
+		return false
+	}
 	return s.provider.IsSupported(from, to)
 }
 
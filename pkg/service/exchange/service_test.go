@@ -497,3 +497,56 @@ func TestService_GetRate(t *testing.T) {
 		})
 	}
 }
+
+func TestService_Warmup(t *testing.T) {
+	mockProvider := mocks.NewExchangeProvider(t)
+	mockRegistry := mocks.NewRegistryProvider(t)
+	mockProvider.On("Metadata").
+		Return(exchange.ProviderMetadata{Name: "test-provider"}).
+		Maybe()
+	mockRegistry.On("Get", mock.Anything, mock.Anything).
+		Return(nil, registry.ErrNotFound)
+	mockRegistry.On("Register", mock.Anything, mock.Anything).Return(nil)
+	mockProvider.On("FetchRate", mock.Anything, "USD", "EUR").
+		Return(&exchange.RateInfo{
+			FromCurrency: "USD",
+			ToCurrency:   "EUR",
+			Rate:         0.9,
+			Provider:     "test-provider",
+		}, nil).Once()
+	mockProvider.On("FetchRate", mock.Anything, "EUR", "GBP").
+		Return(nil, errors.New("provider unavailable")).Once()
+
+	svc := New(mockRegistry, mockProvider, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	warmed := svc.Warmup(
+		context.Background(),
+		[]string{"usd/eur", "EUR/GBP", "USD"},
+		time.Second,
+	)
+
+	assert.Equal(t, 1, warmed)
+	// The rate and its inverse are cached.
+	mockRegistry.AssertNumberOfCalls(t, "Register", 2)
+}
+
+func TestService_Warmup_StopsAtTimeout(t *testing.T) {
+	mockProvider := mocks.NewExchangeProvider(t)
+	mockRegistry := mocks.NewRegistryProvider(t)
+	mockRegistry.On("Get", mock.Anything, mock.Anything).
+		Return(nil, registry.ErrNotFound)
+	mockProvider.On("FetchRate", mock.Anything, "USD", "EUR").
+		Return(func(ctx context.Context, _, _ string) (*exchange.RateInfo, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}, nil).Once()
+
+	svc := New(mockRegistry, mockProvider, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	warmed := svc.Warmup(
+		context.Background(),
+		[]string{"USD/EUR", "EUR/GBP"},
+		10*time.Millisecond,
+	)
+
+	assert.Zero(t, warmed)
+	mockProvider.AssertNotCalled(t, "FetchRate", mock.Anything, "EUR", "GBP")
+}
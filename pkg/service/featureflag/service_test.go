@@ -0,0 +1,95 @@
+package featureflag
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/amirasaad/fintech/pkg/registry"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlagEnabledFor(t *testing.T) {
+	allowed, denied := uuid.New(), uuid.New()
+	flag := &Flag{
+		Name:       "saved_card_deposits",
+		Enabled:    true,
+		Percentage: 0,
+		Allow:      []uuid.UUID{allowed, denied},
+		Deny:       []uuid.UUID{denied},
+	}
+	assert.True(t, flag.EnabledFor(allowed))
+	assert.False(t, flag.EnabledFor(denied), "deny wins over allow")
+	assert.False(t, flag.EnabledFor(uuid.New()))
+
+	flag.Enabled = false
+	assert.False(t, flag.EnabledFor(allowed), "disabled flags are off for everyone")
+
+	flag.Enabled, flag.Percentage = true, 100
+	assert.True(t, flag.EnabledFor(uuid.New()))
+	assert.False(t, flag.EnabledFor(denied))
+}
+
+func TestFlagPercentageRollout(t *testing.T) {
+	users := make([]uuid.UUID, 2000)
+	for i := range users {
+		users[i] = uuid.New()
+	}
+	enabledAt := func(percentage int) map[uuid.UUID]bool {
+		flag := &Flag{Name: "rollout", Enabled: true, Percentage: percentage}
+		enabled := map[uuid.UUID]bool{}
+		for _, u := range users {
+			if flag.EnabledFor(u) {
+				enabled[u] = true
+			}
+		}
+		return enabled
+	}
+
+	quarter := enabledAt(25)
+	assert.InDelta(t, 500, len(quarter), 100)
+	half := enabledAt(50)
+	for u := range quarter {
+		assert.True(t, half[u], "users keep the feature as the rollout grows")
+	}
+}
+
+func TestService(t *testing.T) {
+	ctx := context.Background()
+	svc := New(registry.NewBasicRegistry(), slog.Default())
+	userID := uuid.New()
+
+	assert.False(t, svc.IsEnabled(ctx, "saved_card_deposits", userID))
+	_, err := svc.Get(ctx, "saved_card_deposits")
+	require.ErrorIs(t, err, ErrFlagNotFound)
+
+	_, err = svc.Set(ctx, Flag{Name: "saved_card_deposits", Percentage: 101})
+	require.ErrorIs(t, err, ErrInvalidFlag)
+
+	saved, err := svc.Set(ctx, Flag{
+		Name:    "saved_card_deposits",
+		Enabled: true,
+		Allow:   []uuid.UUID{userID},
+	})
+	require.NoError(t, err)
+	assert.False(t, saved.UpdatedAt.IsZero())
+	assert.True(t, svc.IsEnabled(ctx, "saved_card_deposits", userID))
+	assert.False(t, svc.IsEnabled(ctx, "saved_card_deposits", uuid.New()))
+
+	got, err := svc.Get(ctx, "saved_card_deposits")
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{userID}, got.Allow)
+	assert.Empty(t, got.Deny)
+
+	_, err = svc.Set(ctx, Flag{Name: "instant_payouts", Enabled: false})
+	require.NoError(t, err)
+	flags, err := svc.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, flags, 2)
+	assert.Equal(t, "instant_payouts", flags[0].Name)
+
+	var unset *Service
+	assert.False(t, unset.IsEnabled(ctx, "saved_card_deposits", userID))
+}
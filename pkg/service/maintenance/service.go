@@ -0,0 +1,147 @@
+// Package maintenance switches the API into maintenance mode, in which
+// deposits, withdrawals and transfers are rejected while reads keep working.
+// The switch is kept in a Store; a Redis store shares it with every instance.
+package maintenance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrUnderMaintenance is returned for money movements while maintenance mode
+// is on, or while its state cannot be read.
+var ErrUnderMaintenance = errors.New("service under maintenance")
+
+// State is the maintenance mode switch.
+type State struct {
+	Enabled bool `json:"enabled"`
+	// Forced is true when maintenance mode is switched on by configuration
+	// (MAINTENANCE_ENABLED). It cannot be switched off through the API.
+	Forced    bool      `json:"forced"`
+	Reason    string    `json:"reason,omitempty"`
+	UpdatedBy uuid.UUID `json:"updated_by"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Store keeps the maintenance state. Load reads it from the backing store on
+// every call, returning nil if it was never saved and an error if the store
+// cannot be read.
+type Store interface {
+	Load(ctx context.Context) (*State, error)
+	Save(ctx context.Context, state State) error
+}
+
+// MemoryStore is a Store local to the process.
+type MemoryStore struct {
+	mu    sync.Mutex
+	state *State
+}
+
+// NewMemoryStore creates an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{}
+}
+
+// Load returns the saved state, or nil if none was saved.
+func (m *MemoryStore) Load(context.Context) (*State, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.state == nil {
+		return nil, nil
+	}
+	state := *m.state
+	return &state, nil
+}
+
+// Save replaces the saved state.
+func (m *MemoryStore) Save(_ context.Context, state State) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.state = &state
+	return nil
+}
+
+// Service reads and toggles maintenance mode.
+type Service struct {
+	store  Store
+	forced bool
+	logger *slog.Logger
+}
+
+// New creates a maintenance service backed by store. When forced is true,
+// maintenance mode is on regardless of the stored state.
+func New(store Store, forced bool, logger *slog.Logger) *Service {
+	return &Service{
+		store:  store,
+		forced: forced,
+		logger: logger,
+	}
+}
+
+// Status returns the current maintenance state. Maintenance mode is off until
+// it is first switched on.
+func (s *Service) Status(ctx context.Context) (*State, error) {
+	state, err := s.store.Load(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error getting maintenance state: %w", err)
+	}
+	if state == nil {
+		state = &State{}
+	}
+	if s.forced {
+		state.Enabled, state.Forced = true, true
+	}
+	return state, nil
+}
+
+// Set switches maintenance mode on or off on behalf of updatedBy. Requests
+// already accepted keep running, so deposits, withdrawals and transfers in
+// flight complete.
+func (s *Service) Set(
+	ctx context.Context,
+	enabled bool,
+	reason string,
+	updatedBy uuid.UUID,
+) (*State, error) {
+	state := State{
+		Enabled:   enabled,
+		Reason:    reason,
+		UpdatedBy: updatedBy,
+		UpdatedAt: time.Now().UTC(),
+	}
+	if err := s.store.Save(ctx, state); err != nil {
+		return nil, fmt.Errorf("failed to save maintenance state: %w", err)
+	}
+	s.logger.Info("Maintenance mode updated",
+		"enabled", state.Enabled,
+		"reason", state.Reason,
+		"updated_by", state.UpdatedBy,
+		"forced", s.forced,
+	)
+	if s.forced {
+		state.Enabled, state.Forced = true, true
+	}
+	return &state, nil
+}
+
+// CheckWrites returns ErrUnderMaintenance while maintenance mode is on. It
+// fails closed: when the state cannot be read, money movements are rejected
+// too.
+func (s *Service) CheckWrites(ctx context.Context) error {
+	state, err := s.Status(ctx)
+	if err != nil {
+		s.logger.Error("failed to read maintenance state, rejecting writes",
+			"error", err)
+		return fmt.Errorf("%w: %w", ErrUnderMaintenance, err)
+	}
+	if state.Enabled {
+		return ErrUnderMaintenance
+	}
+	return nil
+}
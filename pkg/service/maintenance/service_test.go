@@ -0,0 +1,69 @@
+package maintenance_test
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/amirasaad/fintech/infra/caching"
+	"github.com/amirasaad/fintech/pkg/service/maintenance"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService(t *testing.T) {
+	ctx := context.Background()
+	svc := maintenance.New(maintenance.NewMemoryStore(), false, slog.Default())
+	admin := uuid.New()
+
+	state, err := svc.Status(ctx)
+	require.NoError(t, err)
+	assert.False(t, state.Enabled, "off until switched on")
+	require.NoError(t, svc.CheckWrites(ctx))
+
+	state, err = svc.Set(ctx, true, "database upgrade", admin)
+	require.NoError(t, err)
+	assert.True(t, state.Enabled)
+	assert.False(t, state.UpdatedAt.IsZero())
+	require.ErrorIs(t, svc.CheckWrites(ctx), maintenance.ErrUnderMaintenance)
+
+	state, err = svc.Status(ctx)
+	require.NoError(t, err)
+	assert.True(t, state.Enabled)
+	assert.Equal(t, "database upgrade", state.Reason)
+	assert.Equal(t, admin, state.UpdatedBy)
+
+	_, err = svc.Set(ctx, false, "", admin)
+	require.NoError(t, err)
+	require.NoError(t, svc.CheckWrites(ctx))
+}
+
+func TestServiceForcedByConfig(t *testing.T) {
+	ctx := context.Background()
+	svc := maintenance.New(maintenance.NewMemoryStore(), true, slog.Default())
+
+	state, err := svc.Set(ctx, false, "", uuid.New())
+	require.NoError(t, err)
+	assert.True(t, state.Enabled, "configuration wins over the API")
+	assert.True(t, state.Forced)
+	require.ErrorIs(t, svc.CheckWrites(ctx), maintenance.ErrUnderMaintenance)
+}
+
+func TestServiceFailsClosed(t *testing.T) {
+	ctx := context.Background()
+	// Nothing listens on port 1, so every read of the switch fails.
+	client := redis.NewClient(&redis.Options{
+		Addr:        "127.0.0.1:1",
+		DialTimeout: 100 * time.Millisecond,
+		MaxRetries:  -1,
+	})
+	t.Cleanup(func() { _ = client.Close() })
+	svc := maintenance.New(caching.NewMaintenanceStore(client, "test:"), false, slog.Default())
+
+	_, err := svc.Status(ctx)
+	require.Error(t, err)
+	require.ErrorIs(t, svc.CheckWrites(ctx), maintenance.ErrUnderMaintenance)
+}
@@ -0,0 +1,277 @@
+// Package payoutexport exports withdrawals as ISO 20022 pain.001 credit
+// transfer initiations, for banks and enterprise finance systems.
+package payoutexport
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/amirasaad/fintech/pkg/config"
+	"github.com/amirasaad/fintech/pkg/domain"
+	"github.com/amirasaad/fintech/pkg/domain/account"
+	"github.com/amirasaad/fintech/pkg/dto"
+	"github.com/amirasaad/fintech/pkg/handler/common"
+	"github.com/amirasaad/fintech/pkg/iso20022"
+	"github.com/amirasaad/fintech/pkg/money"
+	"github.com/amirasaad/fintech/pkg/repository"
+	"github.com/amirasaad/fintech/pkg/repository/pendingwithdrawal"
+	"github.com/google/uuid"
+)
+
+// MaxTransactions is the most withdrawals a single export may contain.
+const MaxTransactions = 1000
+
+// ErrNotConfigured is returned when the platform's debtor account is not
+// configured.
+var ErrNotConfigured = errors.New("payout export is not configured")
+
+// Export is a generated pain.001 document.
+type Export struct {
+	MessageID string
+	Count     int
+	Document  []byte
+}
+
+// Service builds pain.001 exports of withdrawals.
+type Service struct {
+	uow    repository.UnitOfWork
+	debtor iso20022.Party
+	logger *slog.Logger
+	now    func() time.Time
+}
+
+// New creates a payout export Service paying from the debtor account in cfg.
+func New(
+	uow repository.UnitOfWork,
+	cfg *config.PayoutExport,
+	logger *slog.Logger,
+) *Service {
+	s := &Service{uow: uow, logger: logger, now: time.Now}
+	if cfg != nil {
+		s.debtor = iso20022.Party{
+			Name: cfg.DebtorName,
+			IBAN: cfg.DebtorIBAN,
+			BIC:  cfg.DebtorBIC,
+		}
+	}
+	return s
+}
+
+// ExportPain001 renders the given withdrawals as one pain.001 document to be
+// executed on executionDate. Each withdrawal is paid net of its fee to the
+// bank account it was requested to, with the transaction ID as end-to-end ID
+// and the user's description, if any, as remittance information.
+//
+// Nothing is generated unless every withdrawal can be paid: the error is an
+// *iso20022.ValidationError listing each transaction that is missing, is not
+// a withdrawal, has failed or was canceled, is held for an approval it has
+// not been given, or pays out to a destination without a valid IBAN and BIC.
+func (s *Service) ExportPain001(
+	ctx context.Context,
+	ids []uuid.UUID,
+	executionDate time.Time,
+) (*Export, error) {
+	if s.debtor == (iso20022.Party{}) {
+		return nil, ErrNotConfigured
+	}
+	ids = unique(ids)
+	if len(ids) == 0 || len(ids) > MaxTransactions {
+		return nil, fmt.Errorf(
+			"%w: between 1 and %d transactions can be exported",
+			domain.ErrValidation, MaxTransactions,
+		)
+	}
+
+	now := s.now().UTC()
+	if executionDate.IsZero() {
+		executionDate = now
+	}
+	initiation := &iso20022.PaymentInitiation{
+		MessageID:     "FT" + strings.ReplaceAll(uuid.NewString(), "-", "")[:30],
+		CreatedAt:     now,
+		ExecutionDate: executionDate,
+		Debtor:        s.debtor,
+	}
+
+	userRepo, err := common.GetUserRepository(s.uow, s.logger)
+	if err != nil {
+		return nil, err
+	}
+	var problems []iso20022.Problem
+	names := map[uuid.UUID]string{}
+	for _, id := range ids {
+		tx, err := s.getTransaction(ctx, id)
+		if errors.Is(err, domain.ErrNotFound) {
+			problems = append(problems, problem(id, "transaction", "not found"))
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get transaction %s: %w", id, err)
+		}
+		if p := exportProblem(tx); p != nil {
+			problems = append(problems, *p)
+			continue
+		}
+		if tx.MoneySource == common.MoneySourceWithdrawHold {
+			approved, err := s.holdApproved(ctx, tx.ID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get pending withdrawal of %s: %w", id, err)
+			}
+			if !approved {
+				problems = append(problems, problem(id, "transaction", "withdrawal is not approved"))
+				continue
+			}
+		}
+		name, ok := names[tx.UserID]
+		if !ok {
+			u, err := userRepo.Get(ctx, tx.UserID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get user %s: %w", tx.UserID, err)
+			}
+			name = u.Names
+			if strings.TrimSpace(name) == "" {
+				name = u.Username
+			}
+			names[tx.UserID] = name
+		}
+		amount, err := money.New(math.Abs(tx.Amount)-tx.Fee, money.Code(tx.Currency))
+		if err != nil {
+			problems = append(problems, problem(id, "amount", err.Error()))
+			continue
+		}
+		initiation.Transfers = append(initiation.Transfers, iso20022.CreditTransfer{
+			EndToEndID: endToEndID(id),
+			Amount:     amount,
+			Creditor: iso20022.Party{
+				Name: name,
+				IBAN: tx.ExternalBankAccount.Number,
+				BIC:  tx.ExternalBankAccount.BIC,
+			},
+			RemittanceInfo: remittanceInfo(tx),
+		})
+	}
+
+	doc, err := initiation.MarshalPain001()
+	var verr *iso20022.ValidationError
+	if errors.As(err, &verr) {
+		// An empty batch is only a problem if nothing else was reported.
+		for _, p := range verr.Problems {
+			if p.Field != "transfers" || len(problems) == 0 {
+				problems = append(problems, p)
+			}
+		}
+	} else if err != nil {
+		return nil, err
+	}
+	if len(problems) > 0 {
+		return nil, &iso20022.ValidationError{Problems: problems}
+	}
+
+	s.logger.Info("Payouts exported as pain.001",
+		"message_id", initiation.MessageID,
+		"count", len(initiation.Transfers),
+	)
+	return &Export{
+		MessageID: initiation.MessageID,
+		Count:     len(initiation.Transfers),
+		Document:  doc,
+	}, nil
+}
+
+// getTransaction looks up a single transaction. It runs inside a unit of work
+// so a missing row surfaces as domain.ErrNotFound.
+func (s *Service) getTransaction(
+	ctx context.Context,
+	id uuid.UUID,
+) (*dto.TransactionRead, error) {
+	var tx *dto.TransactionRead
+	err := s.uow.Do(ctx, func(uow repository.UnitOfWork) error {
+		txRepo, err := common.GetTransactionRepository(uow, s.logger)
+		if err != nil {
+			return err
+		}
+		tx, err = txRepo.Get(ctx, id)
+		return err
+	})
+	return tx, err
+}
+
+// holdApproved reports whether the withdrawal held by the hold transaction
+// txID has been approved.
+func (s *Service) holdApproved(ctx context.Context, txID uuid.UUID) (bool, error) {
+	var pw *dto.PendingWithdrawalRead
+	err := s.uow.Do(ctx, func(uow repository.UnitOfWork) error {
+		repoAny, err := uow.GetRepository((*pendingwithdrawal.Repository)(nil))
+		if err != nil {
+			return fmt.Errorf("failed to get pending withdrawal repository: %w", err)
+		}
+		pwRepo, ok := repoAny.(pendingwithdrawal.Repository)
+		if !ok {
+			return fmt.Errorf("unexpected pending withdrawal repository type %T", repoAny)
+		}
+		pw, err = pwRepo.GetByTransactionID(ctx, txID)
+		return err
+	})
+	if errors.Is(err, domain.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return pw.Status == dto.PendingWithdrawalApproved, nil
+}
+
+// exportProblem reports why a transaction cannot be exported, or nil.
+func exportProblem(tx *dto.TransactionRead) *iso20022.Problem {
+	var p iso20022.Problem
+	switch {
+	case !common.IsWithdrawMoneySource(tx.MoneySource):
+		p = problem(tx.ID, "transaction", "is not a withdrawal")
+	case tx.Status == "failed":
+		p = problem(tx.ID, "transaction", "has failed")
+	case tx.Status == "canceled":
+		p = problem(tx.ID, "transaction", "was canceled")
+	case tx.ExternalBankAccount == nil:
+		p = problem(tx.ID, "creditor_iban", "withdrawal has no bank account destination")
+	default:
+		return nil
+	}
+	return &p
+}
+
+func problem(id uuid.UUID, field, message string) iso20022.Problem {
+	return iso20022.Problem{EndToEndID: endToEndID(id), Field: field, Message: message}
+}
+
+// endToEndID is the transaction ID without dashes, to fit the 35 character
+// limit.
+func endToEndID(id uuid.UUID) string {
+	return strings.ReplaceAll(id.String(), "-", "")
+}
+
+func unique(ids []uuid.UUID) []uuid.UUID {
+	seen := make(map[uuid.UUID]bool, len(ids))
+	out := ids[:0:0]
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			out = append(out, id)
+		}
+	}
+	return out
+}
+
+// remittanceInfo returns the remittance information shown to the creditor:
+// the withdrawal's description, escaped for banks that export statements to
+// spreadsheets, or its transaction ID if it has none.
+func remittanceInfo(tx *dto.TransactionRead) string {
+	if tx.Description != "" {
+		return account.ExportDescription(tx.Description)
+	}
+	return "Withdrawal " + tx.ID.String()
+}
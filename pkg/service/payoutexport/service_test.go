@@ -0,0 +1,219 @@
+package payoutexport_test
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/amirasaad/fintech/internal/fixtures/mocks"
+	"github.com/amirasaad/fintech/pkg/config"
+	"github.com/amirasaad/fintech/pkg/domain"
+	"github.com/amirasaad/fintech/pkg/dto"
+	"github.com/amirasaad/fintech/pkg/iso20022"
+	"github.com/amirasaad/fintech/pkg/repository"
+	"github.com/amirasaad/fintech/pkg/repository/pendingwithdrawal"
+	"github.com/amirasaad/fintech/pkg/repository/transaction"
+	"github.com/amirasaad/fintech/pkg/repository/user"
+	"github.com/amirasaad/fintech/pkg/service/payoutexport"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// fakePendingWithdrawals looks pending withdrawals up by hold transaction.
+type fakePendingWithdrawals struct {
+	pendingwithdrawal.Repository
+	byTransaction map[uuid.UUID]*dto.PendingWithdrawalRead
+}
+
+func (f *fakePendingWithdrawals) GetByTransactionID(
+	_ context.Context,
+	transactionID uuid.UUID,
+) (*dto.PendingWithdrawalRead, error) {
+	pw, ok := f.byTransaction[transactionID]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	return pw, nil
+}
+
+var debtor = &config.PayoutExport{
+	DebtorName: "Fintech Ltd",
+	DebtorIBAN: "DE89370400440532013000",
+	DebtorBIC:  "COBADEFFXXX",
+}
+
+func TestExportPain001(t *testing.T) {
+	userID := uuid.New()
+	bank := &dto.BankAccount{Number: "GB82WEST12345698765432", BIC: "NWBKGB2L"}
+	withdrawal := &dto.TransactionRead{
+		ID:                  uuid.New(),
+		UserID:              userID,
+		Amount:              -100,
+		Fee:                 1.5,
+		Currency:            "EUR",
+		Status:              "pending",
+		MoneySource:         "withdraw",
+		Description:         "=Rent for March",
+		ExternalBankAccount: bank,
+	}
+	noBIC := &dto.TransactionRead{
+		ID:                  uuid.New(),
+		UserID:              userID,
+		Amount:              -10,
+		Currency:            "EUR",
+		Status:              "pending",
+		MoneySource:         "withdraw",
+		ExternalBankAccount: &dto.BankAccount{Number: bank.Number},
+	}
+	deposit := &dto.TransactionRead{
+		ID:          uuid.New(),
+		UserID:      userID,
+		Amount:      50,
+		Currency:    "EUR",
+		Status:      "completed",
+		MoneySource: "deposit",
+	}
+	// Held withdrawals are exported by their hold transaction once approved.
+	hold := func(status string) *dto.TransactionRead {
+		return &dto.TransactionRead{
+			ID:                  uuid.New(),
+			UserID:              userID,
+			Amount:              -200,
+			Currency:            "EUR",
+			Status:              status,
+			MoneySource:         "withdraw_hold",
+			ExternalBankAccount: bank,
+		}
+	}
+	approved := hold("completed")
+	awaiting := hold("completed")
+	rejected := hold("canceled")
+	pendingWithdrawals := &fakePendingWithdrawals{
+		byTransaction: map[uuid.UUID]*dto.PendingWithdrawalRead{
+			approved.ID: {TransactionID: approved.ID, Status: dto.PendingWithdrawalApproved},
+			awaiting.ID: {TransactionID: awaiting.ID, Status: dto.PendingWithdrawalPending},
+			rejected.ID: {TransactionID: rejected.ID, Status: dto.PendingWithdrawalRejected},
+		},
+	}
+	missing := uuid.New()
+
+	setup := func(t *testing.T, cfg *config.PayoutExport) *payoutexport.Service {
+		uow := mocks.NewUnitOfWork(t)
+		txRepo := mocks.NewTransactionRepository(t)
+		userRepo := mocks.NewUserRepository(t)
+		uow.EXPECT().Do(mock.Anything, mock.Anything).RunAndReturn(
+			func(ctx context.Context, fn func(repository.UnitOfWork) error) error {
+				return fn(uow)
+			},
+		).Maybe()
+		uow.EXPECT().GetRepository(mock.Anything).RunAndReturn(
+			func(repoType any) (any, error) {
+				switch repoType.(type) {
+				case *transaction.Repository:
+					return txRepo, nil
+				case *user.Repository:
+					return userRepo, nil
+				case *pendingwithdrawal.Repository:
+					return pendingWithdrawals, nil
+				}
+				return nil, errors.New("unexpected repository type")
+			},
+		).Maybe()
+		for _, tx := range []*dto.TransactionRead{
+			withdrawal, noBIC, deposit, approved, awaiting, rejected,
+		} {
+			txRepo.EXPECT().Get(mock.Anything, tx.ID).Return(tx, nil).Maybe()
+		}
+		txRepo.EXPECT().Get(mock.Anything, missing).Return(nil, domain.ErrNotFound).Maybe()
+		userRepo.EXPECT().Get(mock.Anything, userID).Return(&dto.UserRead{
+			ID: userID, Username: "alice", Names: "Alice Smith",
+		}, nil).Maybe()
+		return payoutexport.New(uow, cfg, slog.Default())
+	}
+
+	t.Run("renders withdrawals net of fees", func(t *testing.T) {
+		svc := setup(t, debtor)
+		date := time.Date(2026, 3, 3, 0, 0, 0, 0, time.UTC)
+		export, err := svc.ExportPain001(
+			context.Background(),
+			[]uuid.UUID{withdrawal.ID, withdrawal.ID},
+			date,
+		)
+		require.NoError(t, err)
+		assert.Equal(t, 1, export.Count, "duplicate IDs are exported once")
+		assert.LessOrEqual(t, len(export.MessageID), 35)
+
+		var doc struct {
+			MsgID      string `xml:"CstmrCdtTrfInitn>GrpHdr>MsgId"`
+			ExecDate   string `xml:"CstmrCdtTrfInitn>PmtInf>ReqdExctnDt"`
+			EndToEndID string `xml:"CstmrCdtTrfInitn>PmtInf>CdtTrfTxInf>PmtId>EndToEndId"`
+			Amount     string `xml:"CstmrCdtTrfInitn>PmtInf>CdtTrfTxInf>Amt>InstdAmt"`
+			Creditor   string `xml:"CstmrCdtTrfInitn>PmtInf>CdtTrfTxInf>Cdtr>Nm"`
+			IBAN       string `xml:"CstmrCdtTrfInitn>PmtInf>CdtTrfTxInf>CdtrAcct>Id>IBAN"`
+			Remittance string `xml:"CstmrCdtTrfInitn>PmtInf>CdtTrfTxInf>RmtInf>Ustrd"`
+		}
+		require.NoError(t, xml.Unmarshal(export.Document, &doc))
+		assert.Equal(t, export.MessageID, doc.MsgID)
+		assert.Equal(t, "2026-03-03", doc.ExecDate)
+		assert.Equal(t, strings.ReplaceAll(withdrawal.ID.String(), "-", ""), doc.EndToEndID)
+		assert.Equal(t, "98.50", doc.Amount)
+		assert.Equal(t, "Alice Smith", doc.Creditor)
+		assert.Equal(t, bank.Number, doc.IBAN)
+		assert.Equal(t, "'=Rent for March", doc.Remittance, "formula-like descriptions are escaped")
+	})
+
+	t.Run("renders approved held withdrawals", func(t *testing.T) {
+		svc := setup(t, debtor)
+		export, err := svc.ExportPain001(
+			context.Background(),
+			[]uuid.UUID{withdrawal.ID, approved.ID},
+			time.Time{},
+		)
+		require.NoError(t, err)
+		assert.Equal(t, 2, export.Count)
+	})
+
+	t.Run("lists every problem", func(t *testing.T) {
+		svc := setup(t, debtor)
+		_, err := svc.ExportPain001(
+			context.Background(),
+			[]uuid.UUID{
+				withdrawal.ID, noBIC.ID, deposit.ID, missing, awaiting.ID, rejected.ID,
+			},
+			time.Time{},
+		)
+		require.ErrorIs(t, err, iso20022.ErrIncompleteTransfer)
+		var verr *iso20022.ValidationError
+		require.ErrorAs(t, err, &verr)
+		fields := map[string]string{}
+		for _, p := range verr.Problems {
+			fields[p.EndToEndID] = p.Field
+		}
+		e2e := func(id uuid.UUID) string { return strings.ReplaceAll(id.String(), "-", "") }
+		assert.Equal(t, map[string]string{
+			e2e(noBIC.ID):    "creditor_bic",
+			e2e(deposit.ID):  "transaction",
+			e2e(missing):     "transaction",
+			e2e(awaiting.ID): "transaction",
+			e2e(rejected.ID): "transaction",
+		}, fields)
+	})
+
+	t.Run("requires a debtor account", func(t *testing.T) {
+		svc := setup(t, nil)
+		_, err := svc.ExportPain001(context.Background(), []uuid.UUID{withdrawal.ID}, time.Time{})
+		require.ErrorIs(t, err, payoutexport.ErrNotConfigured)
+	})
+
+	t.Run("rejects an empty batch", func(t *testing.T) {
+		svc := setup(t, debtor)
+		_, err := svc.ExportPain001(context.Background(), nil, time.Time{})
+		require.ErrorIs(t, err, domain.ErrValidation)
+	})
+}
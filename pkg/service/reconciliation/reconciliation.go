@@ -0,0 +1,287 @@
+// Package reconciliation matches payouts reported by the payment provider
+// against internal withdrawal transactions.
+package reconciliation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/amirasaad/fintech/pkg/domain"
+	"github.com/amirasaad/fintech/pkg/dto"
+	"github.com/amirasaad/fintech/pkg/handler/common"
+	"github.com/amirasaad/fintech/pkg/money"
+	"github.com/amirasaad/fintech/pkg/provider/payment"
+	"github.com/amirasaad/fintech/pkg/repository"
+	"github.com/google/uuid"
+)
+
+// MaxRange is the longest period a single report may cover.
+const MaxRange = 31 * 24 * time.Hour
+
+// DefaultGracePeriod is how long after the end of the period payouts are
+// still attributed to it. A withdrawal requested just before midnight is
+// often paid out just after.
+const DefaultGracePeriod = 24 * time.Hour
+
+// ErrInvalidRange is returned when the report period is empty or too long.
+var ErrInvalidRange = errors.New("invalid reconciliation range")
+
+// Match statuses for entries in Report.Matched.
+const (
+	// StatusMatched means the payout and the transaction agree.
+	StatusMatched = "matched"
+	// StatusAmountMismatch means the payout amount differs from the transaction.
+	StatusAmountMismatch = "amount_mismatch"
+	// StatusConverted means the payout was made in a different currency than
+	// the transaction, so the amounts cannot be compared directly.
+	StatusConverted = "converted"
+	// StatusPartiallyReversed means part of the payout was reversed.
+	StatusPartiallyReversed = "partially_reversed"
+	// StatusReversed means the payout was fully reversed.
+	StatusReversed = "reversed"
+	// StatusDuplicate means more than one payout references the transaction.
+	StatusDuplicate = "duplicate"
+)
+
+// Entry is one line of a reconciliation report. Internal fields are empty for
+// payouts without a transaction and payout fields are empty for transactions
+// without a payout.
+type Entry struct {
+	Status           string    `json:"status"`
+	TransactionID    uuid.UUID `json:"transaction_id,omitempty"`
+	InternalAmount   float64   `json:"internal_amount,omitempty"`
+	InternalCurrency string    `json:"internal_currency,omitempty"`
+	InternalStatus   string    `json:"internal_status,omitempty"`
+	PayoutID         string    `json:"payout_id,omitempty"`
+	PayoutAmount     float64   `json:"payout_amount,omitempty"`
+	PayoutReversed   float64   `json:"payout_reversed,omitempty"`
+	PayoutCurrency   string    `json:"payout_currency,omitempty"`
+	Reason           string    `json:"reason,omitempty"`
+}
+
+// Report is the result of reconciling payouts against withdrawals for [From, To).
+type Report struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+	// Matched pairs payouts with their withdrawal, including discrepancies.
+	Matched []Entry `json:"matched"`
+	// UnmatchedInProvider lists withdrawals with no payout at the provider.
+	UnmatchedInProvider []Entry `json:"unmatched_in_provider"`
+	// UnmatchedInternally lists payouts with no internal withdrawal.
+	UnmatchedInternally []Entry `json:"unmatched_internally"`
+}
+
+// Service reconciles provider payouts against internal withdrawals.
+type Service struct {
+	uow     repository.UnitOfWork
+	payouts payment.PayoutLister
+	logger  *slog.Logger
+	grace   time.Duration
+}
+
+// Option configures a Service.
+type Option func(*Service)
+
+// WithGracePeriod overrides DefaultGracePeriod.
+func WithGracePeriod(d time.Duration) Option {
+	return func(s *Service) { s.grace = d }
+}
+
+// New creates a new reconciliation Service.
+func New(
+	uow repository.UnitOfWork,
+	payouts payment.PayoutLister,
+	logger *slog.Logger,
+	opts ...Option,
+) *Service {
+	s := &Service{
+		uow:     uow,
+		payouts: payouts,
+		logger:  logger,
+		grace:   DefaultGracePeriod,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Reconcile builds the report for withdrawals created in [from, to).
+//
+// Payouts are fetched up to the grace period past to, so a withdrawal paid
+// out shortly after the period is still matched; payouts in that extra window
+// that match nothing belong to the next report and are left out. A payout in
+// the period whose transaction predates it is looked up individually.
+func (s *Service) Reconcile(ctx context.Context, from, to time.Time) (*Report, error) {
+	if !to.After(from) || to.Sub(from) > MaxRange {
+		return nil, fmt.Errorf("%w: from %s to %s", ErrInvalidRange, from, to)
+	}
+	log := s.logger.With("from", from, "to", to)
+
+	txRepo, err := common.GetTransactionRepository(s.uow, log)
+	if err != nil {
+		return nil, err
+	}
+	txs, err := txRepo.ListByMoneySource(ctx, common.WithdrawMoneySources(), from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list withdrawals: %w", err)
+	}
+	payouts, err := s.payouts.ListPayouts(ctx, from, to.Add(s.grace))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list payouts: %w", err)
+	}
+
+	report := &Report{
+		From:                from,
+		To:                  to,
+		Matched:             []Entry{},
+		UnmatchedInProvider: []Entry{},
+		UnmatchedInternally: []Entry{},
+	}
+	byID := make(map[uuid.UUID]*dto.TransactionRead, len(txs))
+	for _, tx := range txs {
+		byID[tx.ID] = tx
+	}
+	seen := make(map[uuid.UUID]bool, len(payouts))
+
+	sort.SliceStable(payouts, func(i, j int) bool {
+		return payouts[i].CreatedAt.Before(payouts[j].CreatedAt)
+	})
+	for _, p := range payouts {
+		inPeriod := p.CreatedAt.Before(to)
+		tx, ok := byID[p.TransactionID]
+		if !ok && inPeriod && p.TransactionID != uuid.Nil {
+			// The withdrawal may predate the period.
+			tx, err = s.getTransaction(ctx, p.TransactionID)
+			if err != nil && !errors.Is(err, domain.ErrNotFound) {
+				return nil, fmt.Errorf("failed to get transaction %s: %w", p.TransactionID, err)
+			}
+			ok = err == nil && tx != nil
+		}
+		switch {
+		case ok:
+			entry := matchEntry(tx, p)
+			if seen[tx.ID] {
+				entry.Status = StatusDuplicate
+				entry.Reason = "more than one payout references this transaction"
+			}
+			seen[tx.ID] = true
+			report.Matched = append(report.Matched, entry)
+		case inPeriod:
+			entry := payoutEntry(p)
+			entry.Reason = "no internal withdrawal with this transaction_id"
+			if p.TransactionID == uuid.Nil {
+				entry.Reason = "payout has no transaction_id metadata"
+			}
+			report.UnmatchedInternally = append(report.UnmatchedInternally, entry)
+		}
+	}
+
+	for _, tx := range txs {
+		// A canceled withdrawal, e.g. a held one that was rejected, was never
+		// meant to be paid out.
+		if seen[tx.ID] || tx.Status == "canceled" {
+			continue
+		}
+		entry := transactionEntry(tx)
+		entry.Reason = "no payout references this transaction"
+		report.UnmatchedInProvider = append(report.UnmatchedInProvider, entry)
+	}
+
+	log.Info("Reconciliation completed",
+		"matched", len(report.Matched),
+		"unmatched_in_provider", len(report.UnmatchedInProvider),
+		"unmatched_internally", len(report.UnmatchedInternally),
+	)
+	return report, nil
+}
+
+// getTransaction looks up a single transaction. It runs inside a unit of work
+// so a missing row surfaces as domain.ErrNotFound.
+func (s *Service) getTransaction(
+	ctx context.Context,
+	id uuid.UUID,
+) (*dto.TransactionRead, error) {
+	var tx *dto.TransactionRead
+	err := s.uow.Do(ctx, func(uow repository.UnitOfWork) error {
+		txRepo, err := common.GetTransactionRepository(uow, s.logger)
+		if err != nil {
+			return err
+		}
+		tx, err = txRepo.Get(ctx, id)
+		return err
+	})
+	return tx, err
+}
+
+// matchEntry compares a payout with its withdrawal. Reversals take precedence
+// over amount checks, since a reversed payout is expected to differ.
+func matchEntry(tx *dto.TransactionRead, p *payment.PayoutRecord) Entry {
+	entry := transactionEntry(tx)
+	pe := payoutEntry(p)
+	entry.PayoutID = pe.PayoutID
+	entry.PayoutAmount = pe.PayoutAmount
+	entry.PayoutReversed = pe.PayoutReversed
+	entry.PayoutCurrency = pe.PayoutCurrency
+	entry.Status = StatusMatched
+
+	switch {
+	case p.Reversed || (p.Amount > 0 && p.AmountReversed >= p.Amount):
+		entry.Status = StatusReversed
+		if tx.Status != "failed" {
+			entry.Reason = fmt.Sprintf(
+				"payout was reversed but the withdrawal is %q", tx.Status,
+			)
+		}
+	case p.AmountReversed > 0:
+		entry.Status = StatusPartiallyReversed
+		entry.Reason = fmt.Sprintf(
+			"%.2f of %.2f %s reversed",
+			pe.PayoutReversed, pe.PayoutAmount, pe.PayoutCurrency,
+		)
+	case !strings.EqualFold(tx.Currency, p.Currency):
+		entry.Status = StatusConverted
+	default:
+		// The platform's application fee is withheld from the payout.
+		expected := math.Abs(tx.Amount) - tx.Fee
+		internal, err := money.New(expected, money.Code(tx.Currency))
+		if err != nil || int64(internal.Amount()) != p.Amount {
+			entry.Status = StatusAmountMismatch
+			entry.Reason = fmt.Sprintf(
+				"withdrawal net of fees is %.2f but payout is %.2f %s",
+				expected, pe.PayoutAmount, pe.PayoutCurrency,
+			)
+		}
+	}
+	return entry
+}
+
+func transactionEntry(tx *dto.TransactionRead) Entry {
+	return Entry{
+		TransactionID:    tx.ID,
+		InternalAmount:   math.Abs(tx.Amount),
+		InternalCurrency: tx.Currency,
+		InternalStatus:   tx.Status,
+	}
+}
+
+func payoutEntry(p *payment.PayoutRecord) Entry {
+	entry := Entry{
+		TransactionID:  p.TransactionID,
+		PayoutID:       p.ID,
+		PayoutCurrency: p.Currency,
+	}
+	if m, err := money.NewFromSmallestUnit(p.Amount, money.Code(p.Currency)); err == nil {
+		entry.PayoutAmount = m.AmountFloat()
+	}
+	if m, err := money.NewFromSmallestUnit(p.AmountReversed, money.Code(p.Currency)); err == nil {
+		entry.PayoutReversed = m.AmountFloat()
+	}
+	return entry
+}
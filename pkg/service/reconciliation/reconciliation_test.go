@@ -0,0 +1,152 @@
+package reconciliation_test
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/amirasaad/fintech/internal/fixtures/mocks"
+	"github.com/amirasaad/fintech/pkg/domain"
+	"github.com/amirasaad/fintech/pkg/dto"
+	"github.com/amirasaad/fintech/pkg/provider/payment"
+	"github.com/amirasaad/fintech/pkg/repository"
+	"github.com/amirasaad/fintech/pkg/service/reconciliation"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type fakePayouts struct {
+	records  []*payment.PayoutRecord
+	from, to time.Time
+}
+
+func (f *fakePayouts) ListPayouts(
+	_ context.Context,
+	from, to time.Time,
+) ([]*payment.PayoutRecord, error) {
+	f.from, f.to = from, to
+	return f.records, nil
+}
+
+func TestReconcile(t *testing.T) {
+	from := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 1)
+	at := from.Add(12 * time.Hour)
+
+	withdrawal := func(amount float64, currency, status string) *dto.TransactionRead {
+		return &dto.TransactionRead{
+			ID:        uuid.New(),
+			Amount:    -amount,
+			Currency:  currency,
+			Status:    status,
+			CreatedAt: at,
+		}
+	}
+	matched := withdrawal(100, "USD", "completed")
+	mismatched := withdrawal(50, "USD", "completed")
+	converted := withdrawal(20, "EUR", "completed")
+	partial := withdrawal(30, "USD", "completed")
+	reversed := withdrawal(40, "USD", "completed")
+	duplicated := withdrawal(10, "USD", "completed")
+	missing := withdrawal(5, "USD", "completed")
+	withFee := withdrawal(60, "USD", "completed")
+	withFee.Fee = 1.8
+	// The hold of an approved withdrawal is its transaction; a rejected
+	// one's is canceled and never paid out.
+	held := withdrawal(70, "USD", "completed")
+	held.MoneySource = "withdraw_hold"
+	rejected := withdrawal(80, "USD", "canceled")
+	rejected.MoneySource = "withdraw_hold"
+	earlier := withdrawal(15, "USD", "completed")
+	earlier.CreatedAt = from.Add(-time.Hour)
+	unknownID := uuid.New()
+
+	payouts := &fakePayouts{records: []*payment.PayoutRecord{
+		{ID: "tr_1", TransactionID: matched.ID, Amount: 10000, Currency: "USD", CreatedAt: at},
+		{ID: "tr_2", TransactionID: mismatched.ID, Amount: 4900, Currency: "USD", CreatedAt: at},
+		{ID: "tr_3", TransactionID: converted.ID, Amount: 2150, Currency: "USD", CreatedAt: at},
+		{
+			ID: "tr_4", TransactionID: partial.ID, Amount: 3000, AmountReversed: 1000,
+			Currency: "USD", CreatedAt: at,
+		},
+		{
+			ID: "tr_5", TransactionID: reversed.ID, Amount: 4000, AmountReversed: 4000,
+			Reversed: true, Currency: "USD", CreatedAt: at,
+		},
+		{ID: "tr_6", TransactionID: duplicated.ID, Amount: 1000, Currency: "USD", CreatedAt: at},
+		{
+			ID: "tr_7", TransactionID: duplicated.ID, Amount: 1000, Currency: "USD",
+			CreatedAt: at.Add(time.Minute),
+		},
+		{ID: "tr_8", TransactionID: earlier.ID, Amount: 1500, Currency: "USD", CreatedAt: at},
+		{ID: "tr_9", TransactionID: unknownID, Amount: 700, Currency: "USD", CreatedAt: at},
+		{ID: "tr_10", Amount: 800, Currency: "USD", CreatedAt: at},
+		{ID: "tr_12", TransactionID: withFee.ID, Amount: 5820, Currency: "USD", CreatedAt: at},
+		{ID: "tr_13", TransactionID: held.ID, Amount: 7000, Currency: "USD", CreatedAt: at},
+		// Created after the period and matching nothing: belongs to the next report.
+		{ID: "tr_11", TransactionID: uuid.New(), Amount: 900, Currency: "USD",
+			CreatedAt: to.Add(time.Hour)},
+	}}
+
+	uow := mocks.NewUnitOfWork(t)
+	txRepo := mocks.NewTransactionRepository(t)
+	uow.EXPECT().GetRepository(mock.Anything).Return(txRepo, nil)
+	uow.EXPECT().Do(mock.Anything, mock.Anything).RunAndReturn(
+		func(ctx context.Context, fn func(repository.UnitOfWork) error) error {
+			return fn(uow)
+		},
+	)
+	txRepo.EXPECT().ListByMoneySource(
+		mock.Anything, []string{"withdraw", "withdraw_hold"}, from, to,
+	).Return(
+		[]*dto.TransactionRead{
+			matched, mismatched, converted, partial, reversed, duplicated, missing,
+			withFee, held, rejected,
+		}, nil,
+	)
+	txRepo.EXPECT().Get(mock.Anything, earlier.ID).Return(earlier, nil)
+	txRepo.EXPECT().Get(mock.Anything, unknownID).Return(nil, domain.ErrNotFound)
+
+	svc := reconciliation.New(uow, payouts, slog.Default())
+	report, err := svc.Reconcile(context.Background(), from, to)
+	require.NoError(t, err)
+	assert.Equal(t, to.Add(reconciliation.DefaultGracePeriod), payouts.to)
+
+	statuses := map[string]string{}
+	for _, e := range report.Matched {
+		statuses[e.PayoutID] = e.Status
+	}
+	assert.Equal(t, map[string]string{
+		"tr_1":  reconciliation.StatusMatched,
+		"tr_2":  reconciliation.StatusAmountMismatch,
+		"tr_3":  reconciliation.StatusConverted,
+		"tr_4":  reconciliation.StatusPartiallyReversed,
+		"tr_5":  reconciliation.StatusReversed,
+		"tr_6":  reconciliation.StatusMatched,
+		"tr_7":  reconciliation.StatusDuplicate,
+		"tr_8":  reconciliation.StatusMatched,
+		"tr_12": reconciliation.StatusMatched,
+		"tr_13": reconciliation.StatusMatched,
+	}, statuses)
+
+	require.Len(t, report.UnmatchedInProvider, 1)
+	assert.Equal(t, missing.ID, report.UnmatchedInProvider[0].TransactionID)
+
+	require.Len(t, report.UnmatchedInternally, 2)
+	assert.Equal(t, "tr_9", report.UnmatchedInternally[0].PayoutID)
+	assert.Equal(t, "tr_10", report.UnmatchedInternally[1].PayoutID)
+}
+
+func TestReconcile_InvalidRange(t *testing.T) {
+	svc := reconciliation.New(mocks.NewUnitOfWork(t), &fakePayouts{}, slog.Default())
+	now := time.Now()
+
+	_, err := svc.Reconcile(context.Background(), now, now)
+	require.ErrorIs(t, err, reconciliation.ErrInvalidRange)
+
+	_, err = svc.Reconcile(context.Background(), now, now.AddDate(0, 0, 40))
+	require.ErrorIs(t, err, reconciliation.ErrInvalidRange)
+}
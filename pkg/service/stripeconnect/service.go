@@ -10,6 +10,7 @@ import (
 	"github.com/amirasaad/fintech/pkg/domain"
 	"github.com/amirasaad/fintech/pkg/handler/common"
 	"github.com/amirasaad/fintech/pkg/repository"
+	"github.com/amirasaad/fintech/pkg/utils"
 	"github.com/google/uuid"
 	"github.com/stripe/stripe-go/v82"
 )
@@ -53,6 +54,14 @@ func New(
 	}
 }
 
+// callContext bounds an outbound Stripe call by the configured call timeout
+// when ctx has no deadline of its own.
+func (s *stripeConnectService) callContext(
+	ctx context.Context,
+) (context.Context, context.CancelFunc) {
+	return utils.BoundedContext(ctx, s.cfg.CallTimeout)
+}
+
 func (s *stripeConnectService) CreateAccount(
 	ctx context.Context,
 	userID uuid.UUID,
@@ -70,7 +79,9 @@ func (s *stripeConnectService) CreateAccount(
 
 	if existingAccountID != "" {
 		// Account already exists, return it
-		acct, err := s.client.V1Accounts.GetByID(ctx, existingAccountID, nil)
+		callCtx, cancel := s.callContext(ctx)
+		defer cancel()
+		acct, err := s.client.V1Accounts.GetByID(callCtx, existingAccountID, nil)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get existing Stripe account: %w", err)
 		}
@@ -93,7 +104,9 @@ func (s *stripeConnectService) CreateAccount(
 		},
 	}
 
-	acct, err := s.client.V1Accounts.Create(ctx, params)
+	createCtx, cancelCreate := s.callContext(ctx)
+	defer cancelCreate()
+	acct, err := s.client.V1Accounts.Create(createCtx, params)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create Stripe account: %w", err)
 	}
@@ -102,7 +115,9 @@ func (s *stripeConnectService) CreateAccount(
 	err = userRepo.UpdateStripeAccount(ctx, userID, acct.ID, false)
 	if err != nil {
 		// Try to clean up the Stripe account if we can't save the reference
-		_, _ = s.client.V1Accounts.Delete(ctx, acct.ID, nil) // nolint:errcheck
+		deleteCtx, cancelDelete := s.callContext(ctx)
+		defer cancelDelete()
+		_, _ = s.client.V1Accounts.Delete(deleteCtx, acct.ID, nil) // nolint:errcheck
 		return nil, fmt.Errorf("failed to save Stripe account ID: %w", err)
 	}
 
@@ -127,7 +142,9 @@ func (s *stripeConnectService) GenerateOnboardingURL(
 		Type:       stripe.String("account_onboarding"),
 	}
 
-	result, err := s.client.V1AccountLinks.Create(ctx, params)
+	callCtx, cancel := s.callContext(ctx)
+	defer cancel()
+	result, err := s.client.V1AccountLinks.Create(callCtx, params)
 	if err != nil {
 		return "", fmt.Errorf("failed to create account link: %w", err)
 	}
@@ -152,7 +169,9 @@ func (s *stripeConnectService) GetAccount(
 		return nil, domain.ErrNotFound
 	}
 
-	acct, err := s.client.V1Accounts.GetByID(ctx, stripeAccountID, nil)
+	callCtx, cancel := s.callContext(ctx)
+	defer cancel()
+	acct, err := s.client.V1Accounts.GetByID(callCtx, stripeAccountID, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get Stripe account: %w", err)
 	}
@@ -1,7 +1,9 @@
 package utils
 
 import (
+	"context"
 	"net/mail"
+	"time"
 
 	"golang.org/x/crypto/bcrypt"
 )
@@ -26,3 +28,14 @@ func IsEmail(email string) bool {
 	_, err := mail.ParseAddress(email)
 	return err == nil
 }
+
+// BoundedContext returns ctx with a timeout of d if ctx has no deadline yet,
+// so outbound calls cannot hang indefinitely. A ctx that already has a
+// deadline, or a non-positive d, is returned unchanged. The caller must call
+// the returned cancel function.
+func BoundedContext(ctx context.Context, d time.Duration) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok || d <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, d)
+}
@@ -1,7 +1,9 @@
 package utils
 
 import (
+	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -37,3 +39,23 @@ func TestIsEmail(t *testing.T) {
 	assert.False(t, IsEmail("@example.com"))
 	// assert.False(t, IsEmail("test@example"))
 }
+
+func TestBoundedContext(t *testing.T) {
+	ctx, cancel := BoundedContext(context.Background(), time.Minute)
+	defer cancel()
+	deadline, ok := ctx.Deadline()
+	require.True(t, ok)
+	assert.WithinDuration(t, time.Now().Add(time.Minute), deadline, time.Second)
+
+	parent, parentCancel := context.WithTimeout(context.Background(), time.Hour)
+	defer parentCancel()
+	ctx, cancel = BoundedContext(parent, time.Minute)
+	defer cancel()
+	deadline, _ = ctx.Deadline()
+	assert.WithinDuration(t, time.Now().Add(time.Hour), deadline, time.Second)
+
+	ctx, cancel = BoundedContext(context.Background(), 0)
+	defer cancel()
+	_, ok = ctx.Deadline()
+	assert.False(t, ok)
+}
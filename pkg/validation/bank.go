@@ -0,0 +1,130 @@
+package validation
+
+import (
+	"errors"
+	"strings"
+)
+
+var (
+	// ErrInvalidRoutingNumber is returned when a routing number is not a
+	// valid 9-digit ABA routing transit number.
+	ErrInvalidRoutingNumber = errors.New("routing number must be 9 digits with a valid ABA checksum")
+	// ErrInvalidIBAN is returned when an IBAN has an invalid format or checksum.
+	ErrInvalidIBAN = errors.New("invalid IBAN format or checksum")
+	// ErrInvalidBankAccountNumber is returned when a domestic bank account
+	// number is not 4 to 17 digits.
+	ErrInvalidBankAccountNumber = errors.New("bank account number must be 4 to 17 digits")
+	// ErrInvalidBIC is returned when a BIC is not 8 or 11 characters in the
+	// ISO 9362 format.
+	ErrInvalidBIC = errors.New("BIC must be 8 or 11 characters in ISO 9362 format")
+)
+
+// ValidateRoutingNumber checks a US ABA routing number using the
+// 3-7-1 weighted checksum.
+func ValidateRoutingNumber(routing string) error {
+	if len(routing) != 9 || !isDigits(routing) {
+		return ErrInvalidRoutingNumber
+	}
+	weights := [3]int{3, 7, 1}
+	sum := 0
+	for i, r := range routing {
+		sum += int(r-'0') * weights[i%3]
+	}
+	if sum == 0 || sum%10 != 0 {
+		return ErrInvalidRoutingNumber
+	}
+	return nil
+}
+
+// ValidateBankAccountNumber checks a domestic (non-IBAN) bank account number.
+func ValidateBankAccountNumber(number string) error {
+	if len(number) < 4 || len(number) > 17 || !isDigits(number) {
+		return ErrInvalidBankAccountNumber
+	}
+	return nil
+}
+
+// ValidateIBAN checks the IBAN structure and its ISO 7064 mod-97 checksum.
+// Spaces are ignored and letters are case-insensitive.
+func ValidateIBAN(iban string) error {
+	iban = normalizeIBAN(iban)
+	if len(iban) < 15 || len(iban) > 34 {
+		return ErrInvalidIBAN
+	}
+	if !isUpperAlpha(iban[:2]) || !isDigits(iban[2:4]) {
+		return ErrInvalidIBAN
+	}
+
+	// Move the country code and check digits to the end, convert letters to
+	// numbers (A=10 ... Z=35) and compute the remainder digit by digit.
+	rem := 0
+	for _, r := range iban[4:] + iban[:4] {
+		switch {
+		case r >= '0' && r <= '9':
+			rem = (rem*10 + int(r-'0')) % 97
+		case r >= 'A' && r <= 'Z':
+			rem = (rem*100 + int(r-'A') + 10) % 97
+		default:
+			return ErrInvalidIBAN
+		}
+	}
+	if rem != 1 {
+		return ErrInvalidIBAN
+	}
+	return nil
+}
+
+// ValidateBIC checks the ISO 9362 structure of a BIC: a 4-letter institution
+// code, a 2-letter country code, a 2-character location code and an optional
+// 3-character branch code. Letters are case-insensitive.
+func ValidateBIC(bic string) error {
+	bic = strings.ToUpper(strings.TrimSpace(bic))
+	if len(bic) != 8 && len(bic) != 11 {
+		return ErrInvalidBIC
+	}
+	if !isUpperAlpha(bic[:6]) || !isUpperAlphanumeric(bic[6:]) {
+		return ErrInvalidBIC
+	}
+	return nil
+}
+
+// looksLikeIBAN reports whether the value starts with a country code, which
+// distinguishes IBANs from domestic, digits-only account numbers.
+func looksLikeIBAN(number string) bool {
+	n := normalizeIBAN(number)
+	return len(n) >= 2 && isUpperAlpha(n[:2])
+}
+
+func normalizeIBAN(iban string) string {
+	return strings.ToUpper(strings.ReplaceAll(iban, " ", ""))
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func isUpperAlphanumeric(s string) bool {
+	for _, r := range s {
+		if (r < 'A' || r > 'Z') && (r < '0' || r > '9') {
+			return false
+		}
+	}
+	return s != ""
+}
+
+func isUpperAlpha(s string) bool {
+	for _, r := range s {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return s != ""
+}
@@ -0,0 +1,45 @@
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// MaxStatementDescriptorLength is the most characters Stripe allows in a
+// statement descriptor.
+const MaxStatementDescriptorLength = 22
+
+// ErrInvalidStatementDescriptor is returned when a statement descriptor would
+// be rejected by the card networks.
+var ErrInvalidStatementDescriptor = errors.New("invalid statement descriptor")
+
+// ValidateStatementDescriptor checks s against Stripe's statement descriptor
+// rules: at most MaxStatementDescriptorLength printable Latin characters,
+// at least one of them a letter, and none of < > \ ' " *.
+func ValidateStatementDescriptor(s string) error {
+	if s == "" || len(s) > MaxStatementDescriptorLength {
+		return fmt.Errorf(
+			"%w: must be 1 to %d characters",
+			ErrInvalidStatementDescriptor, MaxStatementDescriptorLength,
+		)
+	}
+	hasLetter := false
+	for _, r := range s {
+		if r > unicode.MaxASCII || !unicode.IsPrint(r) {
+			return fmt.Errorf(
+				"%w: %q is not a printable Latin character",
+				ErrInvalidStatementDescriptor, r,
+			)
+		}
+		if strings.ContainsRune(`<>\'"*`, r) {
+			return fmt.Errorf("%w: %q is not allowed", ErrInvalidStatementDescriptor, r)
+		}
+		hasLetter = hasLetter || unicode.IsLetter(r)
+	}
+	if !hasLetter {
+		return fmt.Errorf("%w: must contain a letter", ErrInvalidStatementDescriptor)
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+package validation_test
+
+import (
+	"testing"
+
+	"github.com/amirasaad/fintech/pkg/validation"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateStatementDescriptor(t *testing.T) {
+	valid := []string{"FINTECH", "FINTECH TEST", "Deposit 42", "A", "ACME-TOPUP.COM #7"}
+	for _, s := range valid {
+		assert.NoError(t, validation.ValidateStatementDescriptor(s), s)
+	}
+
+	invalid := map[string]string{
+		"empty":         "",
+		"too long":      "FINTECH DEPOSIT TOPUP 23",
+		"no letter":     "12345",
+		"angle bracket": "<FINTECH>",
+		"quote":         `FINTECH "TOPUP"`,
+		"apostrophe":    "JOE'S",
+		"backslash":     `FIN\TECH`,
+		"asterisk":      "FINTECH*TOPUP",
+		"non-latin":     "FINTÉCH",
+		"control":       "FIN\tTECH",
+	}
+	for name, s := range invalid {
+		assert.ErrorIs(t, validation.ValidateStatementDescriptor(s),
+			validation.ErrInvalidStatementDescriptor, name)
+	}
+}
@@ -0,0 +1,276 @@
+// Package validation provides reusable business validators shared by the
+// web API and the CLI.
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/amirasaad/fintech/pkg/commands"
+	"github.com/amirasaad/fintech/pkg/config"
+	"github.com/amirasaad/fintech/pkg/domain"
+)
+
+// Field names reported in FieldError. They match the JSON field names used by
+// the web API so problem details can point at the offending request field.
+const (
+	FieldExternalTarget        = "external_target"
+	FieldBankAccountNumber     = "bank_account_number"
+	FieldRoutingNumber         = "routing_number"
+	FieldBIC                   = "bic"
+	FieldExternalWalletAddress = "external_wallet_address"
+	FieldNetwork               = "network"
+)
+
+// FieldError describes a single invalid field.
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+// Error implements the error interface.
+func (e FieldError) Error() string {
+	return e.Field + ": " + e.Message
+}
+
+// Errors is a list of field validation errors. It unwraps to
+// domain.ErrValidation so callers can use errors.Is.
+type Errors []FieldError
+
+// Error implements the error interface.
+func (e Errors) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, fe := range e {
+		msgs = append(msgs, fe.Error())
+	}
+	return "validation failed: " + strings.Join(msgs, "; ")
+}
+
+// Unwrap returns domain.ErrValidation.
+func (e Errors) Unwrap() error {
+	return domain.ErrValidation
+}
+
+// Fields returns the errors as a field -> message map, suitable for
+// the errors member of a problem details response.
+func (e Errors) Fields() map[string]string {
+	fields := make(map[string]string, len(e))
+	for _, fe := range e {
+		fields[fe.Field] = fe.Message
+	}
+	return fields
+}
+
+// ExternalTargetValidator validates withdrawal destinations.
+type ExternalTargetValidator struct {
+	requireRoutingNumber bool
+	allowIBAN            bool
+	networks             []Network
+}
+
+// ExternalTargetOption configures an ExternalTargetValidator.
+type ExternalTargetOption func(*ExternalTargetValidator)
+
+// WithRequireRoutingNumber requires a routing number for domestic
+// (non-IBAN) bank account numbers.
+func WithRequireRoutingNumber(require bool) ExternalTargetOption {
+	return func(v *ExternalTargetValidator) { v.requireRoutingNumber = require }
+}
+
+// WithIBAN enables or disables accepting IBANs as bank account numbers.
+func WithIBAN(allow bool) ExternalTargetOption {
+	return func(v *ExternalTargetValidator) { v.allowIBAN = allow }
+}
+
+// WithNetworks restricts the crypto networks accepted for wallet addresses.
+func WithNetworks(networks ...Network) ExternalTargetOption {
+	return func(v *ExternalTargetValidator) { v.networks = networks }
+}
+
+// NewExternalTargetValidator creates a validator with sensible defaults:
+// IBANs are accepted, routing numbers are optional and all known networks
+// are allowed.
+func NewExternalTargetValidator(
+	opts ...ExternalTargetOption,
+) *ExternalTargetValidator {
+	v := &ExternalTargetValidator{
+		allowIBAN: true,
+		networks:  SupportedNetworks(),
+	}
+	for _, opt := range opts {
+		opt(v)
+	}
+	if len(v.networks) == 0 {
+		v.networks = SupportedNetworks()
+	}
+	return v
+}
+
+// Validate checks the external target for a withdrawal in the given currency
+// and returns Errors describing every invalid field, or nil if the target is
+// valid. When the wallet network is omitted, Validate fills in target.Network
+// from the asset's native network or from the address format if unambiguous.
+func (v *ExternalTargetValidator) Validate(
+	target *commands.ExternalTarget,
+	currency string,
+) error {
+	if target == nil ||
+		(target.BankAccountNumber == "" &&
+			target.RoutingNumber == "" &&
+			target.BIC == "" &&
+			target.ExternalWalletAddress == "") {
+		return Errors{{
+			Field:   FieldExternalTarget,
+			Message: "at least one external target field must be provided",
+		}}
+	}
+
+	var errs Errors
+	isIBAN := false
+	if target.BankAccountNumber != "" {
+		isIBAN = looksLikeIBAN(target.BankAccountNumber)
+		switch {
+		case isIBAN && !v.allowIBAN:
+			errs = append(errs, FieldError{
+				Field:   FieldBankAccountNumber,
+				Message: "IBAN accounts are not supported",
+			})
+		case isIBAN:
+			if err := ValidateIBAN(target.BankAccountNumber); err != nil {
+				errs = append(errs, FieldError{
+					Field:   FieldBankAccountNumber,
+					Message: err.Error(),
+				})
+			}
+		default:
+			if err := ValidateBankAccountNumber(target.BankAccountNumber); err != nil {
+				errs = append(errs, FieldError{
+					Field:   FieldBankAccountNumber,
+					Message: err.Error(),
+				})
+			}
+		}
+	}
+
+	switch {
+	case target.RoutingNumber != "":
+		if target.BankAccountNumber == "" {
+			errs = append(errs, FieldError{
+				Field:   FieldBankAccountNumber,
+				Message: "bank account number is required with a routing number",
+			})
+		}
+		if err := ValidateRoutingNumber(target.RoutingNumber); err != nil {
+			errs = append(errs, FieldError{
+				Field:   FieldRoutingNumber,
+				Message: err.Error(),
+			})
+		}
+	case v.requireRoutingNumber && target.BankAccountNumber != "" && !isIBAN:
+		errs = append(errs, FieldError{
+			Field:   FieldRoutingNumber,
+			Message: "routing number is required for domestic bank accounts",
+		})
+	}
+
+	if target.BIC != "" {
+		if target.BankAccountNumber == "" {
+			errs = append(errs, FieldError{
+				Field:   FieldBankAccountNumber,
+				Message: "bank account number is required with a BIC",
+			})
+		}
+		if err := ValidateBIC(target.BIC); err != nil {
+			errs = append(errs, FieldError{
+				Field:   FieldBIC,
+				Message: err.Error(),
+			})
+		}
+	}
+
+	switch {
+	case target.ExternalWalletAddress != "":
+		if fe := v.validateWallet(target, currency); fe != nil {
+			errs = append(errs, *fe)
+		}
+	case target.Network != "":
+		errs = append(errs, FieldError{
+			Field:   FieldNetwork,
+			Message: "network requires an external wallet address",
+		})
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// validateWallet checks the wallet address against the selected network,
+// resolving the network first when it was not provided. A single-network
+// asset can only be withdrawn on its native network.
+func (v *ExternalTargetValidator) validateWallet(
+	target *commands.ExternalTarget,
+	currency string,
+) *FieldError {
+	network := Network(strings.ToLower(strings.TrimSpace(target.Network)))
+	if native, ok := DefaultNetwork(currency); ok {
+		if network != "" && network != native {
+			return &FieldError{
+				Field: FieldNetwork,
+				Message: fmt.Sprintf(
+					"%s is only supported on network %q", strings.ToUpper(currency), native,
+				),
+			}
+		}
+		network = native
+	}
+
+	if network == "" {
+		detected, err := DetectNetwork(target.ExternalWalletAddress, v.networks...)
+		if errors.Is(err, ErrAmbiguousNetwork) {
+			return &FieldError{Field: FieldNetwork, Message: err.Error()}
+		}
+		if err != nil {
+			return &FieldError{Field: FieldExternalWalletAddress, Message: err.Error()}
+		}
+		target.Network = string(detected)
+		return nil
+	}
+
+	if !slices.Contains(v.networks, network) {
+		return &FieldError{
+			Field:   FieldNetwork,
+			Message: fmt.Sprintf("network %q is not supported", network),
+		}
+	}
+	if err := ValidateAddress(network, target.ExternalWalletAddress); err != nil {
+		return &FieldError{Field: FieldExternalWalletAddress, Message: err.Error()}
+	}
+	target.Network = string(network)
+	return nil
+}
+
+// NewExternalTargetValidatorFromConfig creates a validator from the withdraw
+// configuration. A nil config yields the defaults.
+func NewExternalTargetValidatorFromConfig(
+	cfg *config.Withdraw,
+) *ExternalTargetValidator {
+	if cfg == nil {
+		return NewExternalTargetValidator()
+	}
+	opts := []ExternalTargetOption{
+		WithRequireRoutingNumber(cfg.RequireRoutingNumber),
+		WithIBAN(cfg.AllowIBAN),
+	}
+	if len(cfg.CryptoNetworks) > 0 {
+		networks := make([]Network, 0, len(cfg.CryptoNetworks))
+		for _, n := range cfg.CryptoNetworks {
+			networks = append(networks, Network(strings.ToLower(strings.TrimSpace(n))))
+		}
+		opts = append(opts, WithNetworks(networks...))
+	}
+	return NewExternalTargetValidator(opts...)
+}
@@ -0,0 +1,302 @@
+package validation_test
+
+import (
+	"testing"
+
+	"github.com/amirasaad/fintech/pkg/commands"
+	"github.com/amirasaad/fintech/pkg/config"
+	"github.com/amirasaad/fintech/pkg/domain"
+	"github.com/amirasaad/fintech/pkg/validation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateRoutingNumber(t *testing.T) {
+	require.NoError(t, validation.ValidateRoutingNumber("021000021"))
+	require.NoError(t, validation.ValidateRoutingNumber("011000015"))
+	require.ErrorIs(t, validation.ValidateRoutingNumber("987654321"),
+		validation.ErrInvalidRoutingNumber)
+	require.ErrorIs(t, validation.ValidateRoutingNumber("000000000"),
+		validation.ErrInvalidRoutingNumber)
+	require.ErrorIs(t, validation.ValidateRoutingNumber("02100002"),
+		validation.ErrInvalidRoutingNumber)
+	require.ErrorIs(t, validation.ValidateRoutingNumber("02100002a"),
+		validation.ErrInvalidRoutingNumber)
+}
+
+func TestValidateIBAN(t *testing.T) {
+	require.NoError(t, validation.ValidateIBAN("GB82WEST12345698765432"))
+	require.NoError(t, validation.ValidateIBAN("de89 3704 0044 0532 0130 00"))
+	require.ErrorIs(t, validation.ValidateIBAN("GB82WEST12345698765433"),
+		validation.ErrInvalidIBAN)
+	require.ErrorIs(t, validation.ValidateIBAN("GB82"), validation.ErrInvalidIBAN)
+	require.ErrorIs(t, validation.ValidateIBAN("GBXXWEST12345698765432"),
+		validation.ErrInvalidIBAN)
+}
+
+func TestValidateBIC(t *testing.T) {
+	require.NoError(t, validation.ValidateBIC("NWBKGB2L"))
+	require.NoError(t, validation.ValidateBIC("deutdeff500"))
+	require.ErrorIs(t, validation.ValidateBIC("NWBKGB2"), validation.ErrInvalidBIC)
+	require.ErrorIs(t, validation.ValidateBIC("NWBK1B2L"), validation.ErrInvalidBIC)
+	require.ErrorIs(t, validation.ValidateBIC("NWBKGB2L-01"), validation.ErrInvalidBIC)
+}
+
+func TestDetectNetwork(t *testing.T) {
+	tests := []struct {
+		address string
+		network validation.Network
+	}{
+		{"1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2", validation.NetworkBitcoin},
+		{"bc1qar0srrr7xfkvy5l643lydnw9re59gtzzwf5mdq", validation.NetworkBitcoin},
+		{"TJCnKsPa7y5okkXvQAidZBzqx3QyQ6sxMW", validation.NetworkTron},
+	}
+	for _, tt := range tests {
+		t.Run(tt.address, func(t *testing.T) {
+			n, err := validation.DetectNetwork(tt.address)
+			require.NoError(t, err)
+			assert.Equal(t, tt.network, n)
+		})
+	}
+
+	_, err := validation.DetectNetwork("0x123")
+	require.ErrorIs(t, err, validation.ErrInvalidWalletAddress)
+
+	_, err = validation.DetectNetwork("0x52908400098527886E0F7030069857D2E4169EE7")
+	require.ErrorIs(t, err, validation.ErrAmbiguousNetwork)
+
+	n, err := validation.DetectNetwork(
+		"0x52908400098527886E0F7030069857D2E4169EE7",
+		validation.NetworkEthereum,
+		validation.NetworkBitcoin,
+	)
+	require.NoError(t, err)
+	assert.Equal(t, validation.NetworkEthereum, n)
+
+	_, err = validation.DetectNetwork(
+		"0x52908400098527886E0F7030069857D2E4169EE7",
+		validation.NetworkBitcoin,
+	)
+	require.ErrorIs(t, err, validation.ErrInvalidWalletAddress)
+}
+
+func TestExternalTargetValidator_Validate(t *testing.T) {
+	v := validation.NewExternalTargetValidator()
+
+	tests := []struct {
+		name     string
+		target   *commands.ExternalTarget
+		currency string
+		fields   []string
+	}{
+		{
+			name:   "nil target",
+			target: nil,
+			fields: []string{validation.FieldExternalTarget},
+		},
+		{
+			name:   "empty target",
+			target: &commands.ExternalTarget{},
+			fields: []string{validation.FieldExternalTarget},
+		},
+		{
+			name: "valid domestic account",
+			target: &commands.ExternalTarget{
+				BankAccountNumber: "1234567890",
+				RoutingNumber:     "021000021",
+			},
+		},
+		{
+			name:   "valid IBAN",
+			target: &commands.ExternalTarget{BankAccountNumber: "GB82WEST12345698765432"},
+		},
+		{
+			name: "valid IBAN with BIC",
+			target: &commands.ExternalTarget{
+				BankAccountNumber: "GB82WEST12345698765432",
+				BIC:               "NWBKGB2L",
+			},
+		},
+		{
+			name: "valid wallet with network",
+			target: &commands.ExternalTarget{
+				ExternalWalletAddress: "0x52908400098527886E0F7030069857D2E4169EE7",
+				Network:               "bsc",
+			},
+		},
+		{
+			name: "ambiguous wallet without network",
+			target: &commands.ExternalTarget{
+				ExternalWalletAddress: "0x52908400098527886E0F7030069857D2E4169EE7",
+			},
+			fields: []string{validation.FieldNetwork},
+		},
+		{
+			name: "network and address mismatch",
+			target: &commands.ExternalTarget{
+				ExternalWalletAddress: "1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2",
+				Network:               "ethereum",
+			},
+			fields: []string{validation.FieldExternalWalletAddress},
+		},
+		{
+			name: "network contradicting a single-network asset",
+			target: &commands.ExternalTarget{
+				ExternalWalletAddress: "0x52908400098527886E0F7030069857D2E4169EE7",
+				Network:               "ethereum",
+			},
+			currency: "BTC",
+			fields:   []string{validation.FieldNetwork},
+		},
+		{
+			name: "native network of a single-network asset",
+			target: &commands.ExternalTarget{
+				ExternalWalletAddress: "1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2",
+				Network:               "Bitcoin",
+			},
+			currency: "BTC",
+		},
+		{
+			name: "unsupported network",
+			target: &commands.ExternalTarget{
+				ExternalWalletAddress: "1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2",
+				Network:               "dogecoin",
+			},
+			fields: []string{validation.FieldNetwork},
+		},
+		{
+			name: "network without wallet",
+			target: &commands.ExternalTarget{
+				BankAccountNumber: "1234567890",
+				Network:           "bitcoin",
+			},
+			fields: []string{validation.FieldNetwork},
+		},
+		{
+			name: "bad routing checksum",
+			target: &commands.ExternalTarget{
+				BankAccountNumber: "1234567890",
+				RoutingNumber:     "987654321",
+			},
+			fields: []string{validation.FieldRoutingNumber},
+		},
+		{
+			name:   "routing without account",
+			target: &commands.ExternalTarget{RoutingNumber: "021000021"},
+			fields: []string{validation.FieldBankAccountNumber},
+		},
+		{
+			name: "bad BIC",
+			target: &commands.ExternalTarget{
+				BankAccountNumber: "GB82WEST12345698765432",
+				BIC:               "NWBK2L",
+			},
+			fields: []string{validation.FieldBIC},
+		},
+		{
+			name:   "BIC without account",
+			target: &commands.ExternalTarget{BIC: "NWBKGB2L"},
+			fields: []string{validation.FieldBankAccountNumber},
+		},
+		{
+			name:   "bad IBAN",
+			target: &commands.ExternalTarget{BankAccountNumber: "GB00WEST12345698765432"},
+			fields: []string{validation.FieldBankAccountNumber},
+		},
+		{
+			name: "bad account and wallet",
+			target: &commands.ExternalTarget{
+				BankAccountNumber:     "12ab",
+				ExternalWalletAddress: "not-a-wallet",
+			},
+			fields: []string{
+				validation.FieldBankAccountNumber,
+				validation.FieldExternalWalletAddress,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			currency := tt.currency
+			if currency == "" {
+				currency = "USD"
+			}
+			err := v.Validate(tt.target, currency)
+			if len(tt.fields) == 0 {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, domain.ErrValidation)
+			var errs validation.Errors
+			require.ErrorAs(t, err, &errs)
+			fields := errs.Fields()
+			assert.Len(t, fields, len(tt.fields))
+			for _, f := range tt.fields {
+				assert.Contains(t, fields, f)
+			}
+		})
+	}
+}
+
+func TestExternalTargetValidator_Options(t *testing.T) {
+	target := &commands.ExternalTarget{BankAccountNumber: "1234567890"}
+	require.NoError(t, validation.NewExternalTargetValidator().Validate(target, "USD"))
+
+	strict := validation.NewExternalTargetValidator(validation.WithRequireRoutingNumber(true))
+	var errs validation.Errors
+	require.ErrorAs(t, strict.Validate(target, "USD"), &errs)
+	assert.Contains(t, errs.Fields(), validation.FieldRoutingNumber)
+
+	noIBAN := validation.NewExternalTargetValidator(validation.WithIBAN(false))
+	require.Error(t, noIBAN.Validate(
+		&commands.ExternalTarget{BankAccountNumber: "GB82WEST12345698765432"},
+		"EUR",
+	))
+
+	btcOnly := validation.NewExternalTargetValidator(
+		validation.WithNetworks(validation.NetworkBitcoin),
+	)
+	require.Error(t, btcOnly.Validate(&commands.ExternalTarget{
+		ExternalWalletAddress: "0x52908400098527886E0F7030069857D2E4169EE7",
+	}, "USD"))
+}
+
+func TestNewExternalTargetValidatorFromConfig(t *testing.T) {
+	wallet := &commands.ExternalTarget{
+		ExternalWalletAddress: "TJCnKsPa7y5okkXvQAidZBzqx3QyQ6sxMW",
+	}
+	require.NoError(t, validation.NewExternalTargetValidatorFromConfig(nil).Validate(wallet, "USD"))
+
+	v := validation.NewExternalTargetValidatorFromConfig(&config.Withdraw{
+		AllowIBAN:      true,
+		CryptoNetworks: []string{" Ethereum "},
+	})
+	require.Error(t, v.Validate(wallet, "USD"))
+	require.NoError(t, v.Validate(&commands.ExternalTarget{
+		ExternalWalletAddress: "0x52908400098527886E0F7030069857D2E4169EE7",
+	}, "USD"))
+}
+
+func TestExternalTargetValidator_ResolvesNetwork(t *testing.T) {
+	v := validation.NewExternalTargetValidator()
+
+	// Single-network assets default to their native network.
+	target := &commands.ExternalTarget{
+		ExternalWalletAddress: "0x52908400098527886E0F7030069857D2E4169EE7",
+	}
+	require.NoError(t, v.Validate(target, "ETH"))
+	assert.Equal(t, string(validation.NetworkEthereum), target.Network)
+
+	// Unambiguous addresses resolve from their format.
+	target = &commands.ExternalTarget{
+		ExternalWalletAddress: "TJCnKsPa7y5okkXvQAidZBzqx3QyQ6sxMW",
+	}
+	require.NoError(t, v.Validate(target, "USDT"))
+	assert.Equal(t, string(validation.NetworkTron), target.Network)
+
+	// The asset's default network rejects addresses for other networks.
+	require.Error(t, v.Validate(&commands.ExternalTarget{
+		ExternalWalletAddress: "TJCnKsPa7y5okkXvQAidZBzqx3QyQ6sxMW",
+	}, "BTC"))
+}
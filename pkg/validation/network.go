@@ -0,0 +1,130 @@
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Network identifies a crypto network used for wallet withdrawals.
+type Network string
+
+const (
+	// NetworkBitcoin is the Bitcoin main network.
+	NetworkBitcoin Network = "bitcoin"
+	// NetworkEthereum is the Ethereum main network.
+	NetworkEthereum Network = "ethereum"
+	// NetworkTron is the Tron main network.
+	NetworkTron Network = "tron"
+	// NetworkBSC is the BNB Smart Chain (BEP-20) network.
+	NetworkBSC Network = "bsc"
+)
+
+var (
+	// ErrInvalidWalletAddress is returned when a wallet address does not match
+	// the format of any accepted network.
+	ErrInvalidWalletAddress = errors.New("invalid wallet address")
+	// ErrAmbiguousNetwork is returned when a wallet address is valid on more
+	// than one accepted network and no network was selected.
+	ErrAmbiguousNetwork = errors.New("address is valid on multiple networks; network is required")
+)
+
+const (
+	base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+	bech32Alphabet = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+	hexAlphabet    = "0123456789abcdefABCDEF"
+)
+
+// addressValidators maps each network to its address format check.
+var addressValidators = map[Network]func(string) bool{
+	NetworkBitcoin:  isBitcoinAddress,
+	NetworkEthereum: isEthereumAddress,
+	NetworkTron:     isTronAddress,
+	NetworkBSC:      isEthereumAddress,
+}
+
+// assetNetworks maps single-network assets to their native network so the
+// network can be omitted when withdrawing them. Multi-network assets such as
+// stablecoins are intentionally absent and require an explicit network.
+var assetNetworks = map[string]Network{
+	"BTC": NetworkBitcoin,
+	"ETH": NetworkEthereum,
+	"TRX": NetworkTron,
+	"BNB": NetworkBSC,
+}
+
+// SupportedNetworks returns all networks with a known address format.
+func SupportedNetworks() []Network {
+	return []Network{NetworkBitcoin, NetworkEthereum, NetworkTron, NetworkBSC}
+}
+
+// DefaultNetwork returns the native network of a single-network asset.
+func DefaultNetwork(asset string) (Network, bool) {
+	n, ok := assetNetworks[strings.ToUpper(asset)]
+	return n, ok
+}
+
+// ValidateAddress checks that address is well formed for the given network.
+func ValidateAddress(network Network, address string) error {
+	valid, ok := addressValidators[network]
+	if !ok {
+		return fmt.Errorf("unsupported network %q", network)
+	}
+	if !valid(address) {
+		return fmt.Errorf("%w for network %s", ErrInvalidWalletAddress, network)
+	}
+	return nil
+}
+
+// DetectNetwork returns the network among the given ones whose address format
+// matches address. When no networks are given, all supported networks are
+// tried. It returns ErrAmbiguousNetwork when more than one network matches.
+func DetectNetwork(address string, networks ...Network) (Network, error) {
+	if len(networks) == 0 {
+		networks = SupportedNetworks()
+	}
+	var found Network
+	for _, n := range networks {
+		if ValidateAddress(n, address) != nil {
+			continue
+		}
+		if found != "" {
+			return "", ErrAmbiguousNetwork
+		}
+		found = n
+	}
+	if found == "" {
+		return "", ErrInvalidWalletAddress
+	}
+	return found, nil
+}
+
+// isBitcoinAddress accepts legacy base58 (P2PKH/P2SH) and bech32 addresses.
+func isBitcoinAddress(addr string) bool {
+	if strings.HasPrefix(addr, "bc1") {
+		return len(addr) >= 42 && len(addr) <= 62 && inAlphabet(addr[3:], bech32Alphabet)
+	}
+	if addr == "" || (addr[0] != '1' && addr[0] != '3') {
+		return false
+	}
+	return len(addr) >= 26 && len(addr) <= 35 && inAlphabet(addr, base58Alphabet)
+}
+
+// isEthereumAddress accepts 0x-prefixed 20-byte hex addresses.
+func isEthereumAddress(addr string) bool {
+	return len(addr) == 42 && strings.HasPrefix(addr, "0x") && inAlphabet(addr[2:], hexAlphabet)
+}
+
+// isTronAddress accepts base58 addresses starting with T.
+func isTronAddress(addr string) bool {
+	return len(addr) == 34 && addr[0] == 'T' && inAlphabet(addr, base58Alphabet)
+}
+
+func inAlphabet(s, alphabet string) bool {
+	for _, r := range s {
+		if !strings.ContainsRune(alphabet, r) {
+			return false
+		}
+	}
+	return s != ""
+}
@@ -24,9 +24,15 @@ func RunSmokeTest() error {
 		groupID = "fintech"
 	}
 
+	// Use the bus's topic prefix so environments sharing a cluster stay apart
+	prefix := strings.TrimSpace(os.Getenv("TOPIC_PREFIX"))
+	if prefix == "" {
+		prefix = "fintech.events"
+	}
+
 	topics := []string{
-		"fintech.events.test.event",
-		"fintech.events.other.event",
+		prefix + ".test.event",
+		prefix + ".other.event",
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
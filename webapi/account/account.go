@@ -2,17 +2,26 @@ package account
 
 import (
 	"errors"
+	"strconv"
 	"strings"
 
+	"github.com/amirasaad/fintech/pkg/commandbus"
 	"github.com/amirasaad/fintech/pkg/commands"
 	"github.com/amirasaad/fintech/pkg/config"
 	"github.com/amirasaad/fintech/pkg/domain"
+	accountdomain "github.com/amirasaad/fintech/pkg/domain/account"
+	"github.com/amirasaad/fintech/pkg/domain/user"
 	"github.com/amirasaad/fintech/pkg/dto"
 	"github.com/amirasaad/fintech/pkg/middleware"
 	"github.com/amirasaad/fintech/pkg/money"
+	"github.com/amirasaad/fintech/pkg/repository"
 	accountsvc "github.com/amirasaad/fintech/pkg/service/account"
 	authsvc "github.com/amirasaad/fintech/pkg/service/auth"
+	"github.com/amirasaad/fintech/pkg/service/checkout"
+	currencysvc "github.com/amirasaad/fintech/pkg/service/currency"
+	maintenancesvc "github.com/amirasaad/fintech/pkg/service/maintenance"
 	stripeconnectsvc "github.com/amirasaad/fintech/pkg/service/stripeconnect"
+	"github.com/amirasaad/fintech/pkg/validation"
 	"github.com/amirasaad/fintech/webapi/common"
 
 	"github.com/gofiber/fiber/v2"
@@ -26,19 +35,50 @@ import (
 // depositing and withdrawing funds, retrieving account balances,
 // and listing account transactions.
 // All routes are protected by authentication middleware and require a valid user context.
+// Account routes take the account UUID or its human-friendly reference as :ref.
+// Register them in a group using UseVersion to serve them in another version's
+// response shape; without it they serve V1. Deposits, withdrawals and
+// transfers are rejected with 503 while maintenance mode is on.
 //
 // Routes:
 //   - POST   /account                   : Create a new account for the authenticated user.
-//   - POST   /account/:id/deposit       : Deposit funds into the specified account.
-//   - POST   /account/:id/withdraw      : Withdraw funds from the specified account.
-//   - GET    /account/:id/balance       : Retrieve the balance of the specified account.
+//   - POST   /account/:ref/deposit      : Deposit funds into the specified account.
+//   - POST   /account/:ref/withdraw     : Withdraw funds from the specified account.
+//   - POST   /account/:ref/transfer     : Transfer funds from the specified account.
+//   - GET    /account/:ref/balance      : Retrieve the balance of the specified account.
 //   - GET    /accounts/balance/aggregate: Retrieve aggregated balances across all user accounts.
-//   - GET    /account/:id/transactions  : List transactions for the specified account.
+//   - GET    /account/:ref/transactions : List transactions for the specified account.
+//   - POST   /transactions/:id/refund   : Refund part or all of a completed deposit.
+//   - GET    /account/:ref/deposits/pending : List deposits not paid yet, with checkout URLs.
+//   - POST   /deposits/:txId/cancel     : Cancel a deposit that has not been paid yet.
+//   - GET    /transfers/scheduled       : List the user's scheduled transfers.
+//   - DELETE /transfers/scheduled/:id   : Cancel a pending scheduled transfer.
+//   - GET    /payout-destinations       : List the user's saved payout destinations.
+//   - POST   /payout-destinations       : Save a payout destination.
+//   - DELETE /payout-destinations/:id   : Remove a saved payout destination.
+//   - GET    /stripe/destinations       : Alias of GET /payout-destinations.
+//   - GET    /payouts                   : List the user's payouts.
+//   - GET    /admin/accounts?currency= : List accounts in one currency (admin only).
+//   - PUT    /admin/accounts/:id/overdraft : Set an account's overdraft limit (admin only).
+//   - PUT    /admin/accounts/:id/fee-policy : Set who bears an account's provider fees (admin only).
+//   - POST   /admin/accounts/:id/transactions/import : Import historical transactions (admin only).
+//   - GET    /admin/transfers/pending   : List transfers awaiting approval (admin only).
+//   - POST   /admin/transfers/:id/approve : Approve a pending transfer (admin only).
+//   - POST   /admin/transfers/:id/reject  : Reject a pending transfer (admin only).
+//   - GET    /admin/withdrawals/pending : List withdrawals awaiting approval (admins and approvers).
+//   - POST   /admin/withdrawals/:id/approve : Approve a pending withdrawal (admins and approvers).
+//   - POST   /admin/withdrawals/:id/reject  : Reject a pending withdrawal (admins and approvers).
+//   - POST   /admin/deposits/:id/capture    : Capture a deposit authorized with manual capture (admin only).
+//   - POST   /admin/transactions/:id/reprocess : Re-emit the event that settled a transaction (admin only).
 func Routes(
-	app *fiber.App,
+	app fiber.Router,
 	accountSvc *accountsvc.Service,
+	commandBus commandbus.Bus,
 	authSvc *authsvc.Service,
 	stripeConnectSvc stripeconnectsvc.Service,
+	currencySvc *currencysvc.Service,
+	checkoutSvc *checkout.Service,
+	maintenanceSvc *maintenancesvc.Service,
 	cfg *config.App,
 ) {
 	// List all accounts for the authenticated user
@@ -53,6 +93,93 @@ func Routes(
 		GetAggregatedUserBalance(accountSvc, authSvc),
 	)
 
+	// Accounts of one currency across all users (admin only)
+	app.Get(
+		"/admin/accounts",
+		middleware.JwtProtected(cfg.Auth.Jwt),
+		middleware.RequireRole(user.RoleAdmin),
+		ListAccountsByCurrency(accountSvc),
+	)
+	app.Put(
+		"/admin/accounts/:id/overdraft",
+		middleware.JwtProtected(cfg.Auth.Jwt),
+		middleware.RequireRole(user.RoleAdmin),
+		SetOverdraftLimit(accountSvc),
+	)
+	app.Put(
+		"/admin/accounts/:id/fee-policy",
+		middleware.JwtProtected(cfg.Auth.Jwt),
+		middleware.RequireRole(user.RoleAdmin),
+		SetFeePolicy(accountSvc),
+	)
+	app.Post(
+		"/admin/accounts/:id/transactions/import",
+		middleware.JwtProtected(cfg.Auth.Jwt),
+		middleware.RequireRole(user.RoleAdmin),
+		common.RejectDuringMaintenance(maintenanceSvc),
+		ImportTransactions(accountSvc),
+	)
+
+	// Transfers awaiting approval (admin only)
+	app.Get(
+		"/admin/transfers/pending",
+		middleware.JwtProtected(cfg.Auth.Jwt),
+		middleware.RequireRole(user.RoleAdmin),
+		ListPendingTransfers(accountSvc),
+	)
+	app.Post(
+		"/admin/transfers/:id/approve",
+		middleware.JwtProtected(cfg.Auth.Jwt),
+		middleware.RequireRole(user.RoleAdmin),
+		common.RejectDuringMaintenance(maintenanceSvc),
+		ApproveTransfer(accountSvc, authSvc),
+	)
+	app.Post(
+		"/admin/transfers/:id/reject",
+		middleware.JwtProtected(cfg.Auth.Jwt),
+		middleware.RequireRole(user.RoleAdmin),
+		RejectTransfer(accountSvc, authSvc),
+	)
+
+	// Withdrawals awaiting approval (admins and approvers)
+	app.Get(
+		"/admin/withdrawals/pending",
+		middleware.JwtProtected(cfg.Auth.Jwt),
+		middleware.RequireRole(user.RoleAdmin, user.RoleApprover),
+		ListPendingWithdrawals(accountSvc),
+	)
+	app.Post(
+		"/admin/withdrawals/:id/approve",
+		middleware.JwtProtected(cfg.Auth.Jwt),
+		middleware.RequireRole(user.RoleAdmin, user.RoleApprover),
+		common.RejectDuringMaintenance(maintenanceSvc),
+		ApproveWithdrawal(accountSvc, authSvc),
+	)
+	app.Post(
+		"/admin/withdrawals/:id/reject",
+		middleware.JwtProtected(cfg.Auth.Jwt),
+		middleware.RequireRole(user.RoleAdmin, user.RoleApprover),
+		RejectWithdrawal(accountSvc, authSvc),
+	)
+
+	// Deposits authorized with manual capture (admin only)
+	app.Post(
+		"/admin/deposits/:id/capture",
+		middleware.JwtProtected(cfg.Auth.Jwt),
+		middleware.RequireRole(user.RoleAdmin),
+		common.RejectDuringMaintenance(maintenanceSvc),
+		CaptureDeposit(accountSvc, authSvc),
+	)
+
+	// Repair of transactions whose side effects failed (admin only)
+	app.Post(
+		"/admin/transactions/:id/reprocess",
+		middleware.JwtProtected(cfg.Auth.Jwt),
+		middleware.RequireRole(user.RoleAdmin),
+		common.RejectDuringMaintenance(maintenanceSvc),
+		ReprocessTransaction(accountSvc, authSvc),
+	)
+
 	// Create a new account
 	app.Post(
 		"/account",
@@ -60,25 +187,39 @@ func Routes(
 		CreateAccount(accountSvc, authSvc),
 	)
 	app.Post(
-		"/account/:id/deposit",
+		"/account/:ref/deposit",
 		middleware.JwtProtected(cfg.Auth.Jwt),
-		Deposit(accountSvc, authSvc),
+		common.RejectDuringMaintenance(maintenanceSvc),
+		Deposit(accountSvc, commandBus, authSvc, currencySvc),
 	)
 	app.Post(
-		"/account/:id/withdraw",
+		"/account/:ref/withdraw",
 		middleware.JwtProtected(cfg.Auth.Jwt),
-		Withdraw(accountSvc, authSvc),
+		common.RejectDuringMaintenance(maintenanceSvc),
+		Withdraw(
+			accountSvc,
+			commandBus,
+			authSvc,
+			currencySvc,
+			validation.NewExternalTargetValidatorFromConfig(cfg.Withdraw),
+		),
 	)
 	app.Post(
-		"/account/:id/transfer",
+		"/account/:ref/transfer",
 		middleware.JwtProtected(cfg.Auth.Jwt),
-		Transfer(accountSvc, authSvc),
+		common.RejectDuringMaintenance(maintenanceSvc),
+		Transfer(accountSvc, commandBus, authSvc),
+	)
+	app.Patch(
+		"/account/:ref",
+		middleware.JwtProtected(cfg.Auth.Jwt),
+		UpdateAccount(accountSvc, authSvc),
 	)
 	// Get account balance
 	app.Get(
-		"/account/:id/balance",
+		"/account/:ref/balance",
 		middleware.JwtProtected(cfg.Auth.Jwt),
-		GetBalance(accountSvc, authSvc),
+		GetBalance(accountSvc, authSvc, currencySvc),
 	)
 
 	// Stripe Connect routes
@@ -90,10 +231,69 @@ func Routes(
 		stripeHandlers.MapRoutes(stripeGroup, jwtMiddleware)
 	}
 	app.Get(
-		"/account/:id/transactions",
+		"/account/:ref/transactions",
 		middleware.JwtProtected(cfg.Auth.Jwt),
 		GetTransactions(accountSvc, authSvc),
 	)
+	app.Post(
+		"/transactions/:id/refund",
+		middleware.JwtProtected(cfg.Auth.Jwt),
+		common.RejectDuringMaintenance(maintenanceSvc),
+		RefundDeposit(accountSvc, authSvc),
+	)
+	app.Post(
+		"/transactions/:id/receipt",
+		middleware.JwtProtected(cfg.Auth.Jwt),
+		CreateReceiptLink(accountSvc, authSvc),
+	)
+	// Shared receipts; the token authorizes.
+	app.Get("/receipts/:token", GetReceipt(accountSvc))
+	app.Get(
+		"/account/:ref/deposits/pending",
+		middleware.JwtProtected(cfg.Auth.Jwt),
+		ListPendingDeposits(accountSvc, checkoutSvc, authSvc),
+	)
+	app.Post(
+		"/deposits/:txId/cancel",
+		middleware.JwtProtected(cfg.Auth.Jwt),
+		CancelDeposit(accountSvc, authSvc),
+	)
+	app.Get(
+		"/transfers/scheduled",
+		middleware.JwtProtected(cfg.Auth.Jwt),
+		ListScheduledTransfers(accountSvc, authSvc),
+	)
+	app.Delete(
+		"/transfers/scheduled/:id",
+		middleware.JwtProtected(cfg.Auth.Jwt),
+		CancelScheduledTransfer(accountSvc, authSvc),
+	)
+	for _, path := range []string{"/payout-destinations", "/stripe/destinations"} {
+		app.Get(
+			path,
+			middleware.JwtProtected(cfg.Auth.Jwt),
+			ListPayoutDestinations(accountSvc, authSvc),
+		)
+	}
+	app.Post(
+		"/payout-destinations",
+		middleware.JwtProtected(cfg.Auth.Jwt),
+		CreatePayoutDestination(
+			accountSvc,
+			authSvc,
+			validation.NewExternalTargetValidatorFromConfig(cfg.Withdraw),
+		),
+	)
+	app.Delete(
+		"/payout-destinations/:id",
+		middleware.JwtProtected(cfg.Auth.Jwt),
+		DeletePayoutDestination(accountSvc, authSvc),
+	)
+	app.Get(
+		"/payouts",
+		middleware.JwtProtected(cfg.Auth.Jwt),
+		ListPayouts(accountSvc, authSvc),
+	)
 }
 
 // ListUserAccounts returns a Fiber handler that retrieves all accounts for the authenticated user.
@@ -123,7 +323,10 @@ func ListUserAccounts(
 			return common.ProblemDetailsJSON(c, "Invalid user ID", err)
 		}
 
-		accounts, err := accountSvc.ListUserAccounts(c.Context(), userID)
+		accounts, err := accountSvc.ListUserAccounts(
+			repository.WithReplicaReads(c.UserContext()),
+			userID,
+		)
 		if err != nil {
 			log.Error("failed to list user accounts", "error", err, "user_id", userID)
 			return common.ProblemDetailsJSON(c, "Failed to list accounts", err)
@@ -138,7 +341,7 @@ func ListUserAccounts(
 			c,
 			fiber.StatusOK,
 			"Accounts retrieved successfully",
-			accounts,
+			accountsResponse(c, accounts),
 		)
 	}
 }
@@ -171,29 +374,26 @@ func GetAggregatedUserBalance(
 			return common.ProblemDetailsJSON(c, "Invalid user ID", err)
 		}
 
-		accounts, err := accountSvc.ListUserAccounts(c.Context(), userID)
+		accounts, err := accountSvc.ListUserAccounts(
+			repository.WithReplicaReads(c.UserContext()),
+			userID,
+		)
 		if err != nil {
 			log.Error("failed to list user accounts", "error", err, "user_id", userID)
 			return common.ProblemDetailsJSON(c, "Failed to list accounts", err)
 		}
 
-		totals := make(map[string]float64)
-		for _, acc := range accounts {
-			if acc == nil {
-				continue
-			}
-			curr := strings.ToUpper(strings.TrimSpace(acc.Currency))
-			if curr == "" {
-				curr = "UNKNOWN"
-			}
-			totals[curr] += acc.Balance
+		totals, err := aggregatedBalanceResponse(c, accounts)
+		if err != nil {
+			log.Error("failed to aggregate balances", "error", err, "user_id", userID)
+			return common.ProblemDetailsJSON(c, "Failed to aggregate balances", err)
 		}
 
 		return common.SuccessResponseJSON(
 			c,
 			fiber.StatusOK,
 			"Aggregated balance retrieved successfully",
-			AggregatedBalanceResponse{Totals: totals},
+			totals,
 		)
 	}
 }
@@ -207,8 +407,9 @@ func GetAggregatedUserBalance(
 // @Summary Create a new account
 // @Description Creates a new account for the authenticated user.
 //
-//	You can specify the currency for the account.
-//	Returns the created account details.
+//	You can specify the currency and the type of the account: checking (the
+//	default), savings or wallet. Savings accounts allow a limited number of
+//	withdrawals per month. Returns the created account details.
 //
 // @Tags accounts
 // @Accept json
@@ -240,20 +441,21 @@ func CreateAccount(
 			return err // error response already written
 		}
 		a, err := accountSvc.CreateAccount(
-			c.Context(),
+			c.UserContext(),
 			dto.AccountCreate{
 				UserID:   userID,
 				Currency: input.Currency,
+				Type:     input.Type,
 			},
 		)
 		if err != nil {
 			log.Error("failed to create account", "error", err)
-			if strings.Contains(err.Error(), "user already has an account with currency") {
+			if errors.Is(err, domain.ErrAlreadyExists) {
 				return common.ProblemDetailsJSON(
 					c,
 					"Account creation failed",
 					err,
-					"You already have an account with this currency.",
+					"You already have an account of this type with this currency.",
 					fiber.StatusConflict, // 409 Conflict
 				)
 			}
@@ -264,7 +466,7 @@ func CreateAccount(
 			c,
 			fiber.StatusCreated,
 			"Account created",
-			a,
+			accountResponse(c, a),
 		)
 	}
 }
@@ -274,8 +476,8 @@ func CreateAccount(
 // The handler parses the current user ID from the request context,
 // validates the account ID from the URL,
 // and parses the deposit amount from the request body.
-// If successful, it performs the deposit operation using
-// the AccountService and returns the transaction as JSON.
+// If successful, it dispatches a Deposit command, handled by
+// the AccountService, and returns the transaction as JSON.
 // On error, it logs the issue and returns an appropriate JSON error response.
 // @Summary Deposit funds into an account
 // @Description Adds funds to the specified account. Specify the amount, currency,
@@ -283,21 +485,24 @@ func CreateAccount(
 // @Tags accounts
 // @Accept json
 // @Produce json
-// @Param id path string true "Account ID"
+// @Param ref path string true "Account ID or reference"
 // @Param request body DepositRequest true "Deposit details"
+// @Param Idempotency-Key header string false "Makes a retried request take effect only once"
 // @Success 200 {object} common.Response "Deposit successful"
 // @Failure 400 {object} common.ProblemDetails "Invalid request"
 // @Failure 401 {object} common.ProblemDetails "Unauthorized"
 // @Failure 429 {object} common.ProblemDetails "Too many requests"
 // @Failure 500 {object} common.ProblemDetails "Internal server error"
-// @Router /account/{id}/deposit [post]
+// @Router /account/{ref}/deposit [post]
 // @Security Bearer
 func Deposit(
 	accountSvc *accountsvc.Service,
+	commandBus commandbus.Bus,
 	authSvc *authsvc.Service,
+	currencySvc *currencysvc.Service,
 ) fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		log.Info("deposit handler called", "account_id", c.Params("id"))
+		log.Info("deposit handler called", "account_ref", c.Params("ref"))
 		token, ok := c.Locals("user").(*jwt.Token)
 		if !ok {
 			return common.ProblemDetailsJSON(c, "Unauthorized", nil, "missing user context")
@@ -307,16 +512,9 @@ func Deposit(
 			log.Error("failed to get user ID from token", "error", err)
 			return common.ProblemDetailsJSON(c, "Invalid user ID", err)
 		}
-		accountID, err := uuid.Parse(c.Params("id"))
-		if err != nil {
-			log.Error("invalid account ID for deposit", "error", err)
-			return common.ProblemDetailsJSON(
-				c,
-				"Invalid account ID",
-				err,
-				"Account ID must be a valid UUID",
-				fiber.StatusBadRequest,
-			)
+		accountID, err := resolveAccountID(c, accountSvc)
+		if accountID == uuid.Nil {
+			return err // error response already written
 		}
 		input, err := common.BindAndValidate[DepositRequest](c)
 		if input == nil {
@@ -326,14 +524,24 @@ func Deposit(
 		if input.Currency != "" {
 			currencyCode = money.Code(input.Currency)
 		}
+		cur, _ := registryCurrency(c, currencySvc, string(currencyCode))
+		if err := money.ValidatePrecision(input.Amount, cur); err != nil {
+			return common.ProblemDetailsJSON(c, "Invalid amount", err)
+		}
 		depositCmd := commands.Deposit{
 			UserID:    userID,
 			AccountID: accountID,
 			Amount:    input.Amount,
 			Currency:  string(currencyCode),
 			// Add MoneySource, TargetCurrency, etc. if needed
+			SuccessURL:          input.SuccessURL,
+			CancelURL:           input.CancelURL,
+			Description:         input.Description,
+			IdempotencyKey:      c.Get(common.HeaderIdempotencyKey),
+			StatementDescriptor: input.StatementDescriptor,
+			CaptureMethod:       input.CaptureMethod,
 		}
-		err = accountSvc.Deposit(c.Context(), depositCmd)
+		err = commandBus.Dispatch(common.DetachedContext(c), depositCmd)
 		if err != nil {
 			log.Error(
 				"failed to process deposit",
@@ -364,8 +572,11 @@ func Deposit(
 //  1. Retrieves the current user ID from the request context.
 //  2. Parses the account ID from the route parameters.
 //  3. Parses the withdrawal amount from the request body.
-//  4. Calls the AccountService.Withdraw method to process the withdrawal.
-//  5. Returns the transaction details as a JSON response on success.
+//  4. Validates the external target (routing number, IBAN, wallet address).
+//  5. Holds the funds for approval if the amount is above the configured
+//     threshold, otherwise dispatches a Withdraw command, handled by
+//     AccountService.Withdraw.
+//  6. Returns the transaction details as a JSON response on success.
 //
 // Error responses are returned in JSON format with appropriate status codes
 // if any step fails (e.g., invalid user ID, invalid account ID,
@@ -374,23 +585,32 @@ func Deposit(
 //
 // @Summary Withdraw funds from an account
 // @Description Withdraws a specified amount from the user's account.
-// Specify the amount and currency. Returns the transaction details.
+// Specify the amount and currency, and either an external target or the ID of a
+// saved payout destination. Returns the transaction details.
+// Withdrawals above the configured approval threshold are held instead and
+// return the withdrawal awaiting approval; they proceed once enough approvers
+// have approved them.
 //
 // @Tags accounts
 // @Accept json
 // @Produce json
-// @Param id path string true "Account ID"
+// @Param ref path string true "Account ID or reference"
 // @Param request body WithdrawRequest true "Withdrawal details"
+// @Param Idempotency-Key header string false "Makes a retried request take effect only once"
 // @Success 200 {object} common.Response "Withdrawal successful"
+// @Success 202 {object} common.Response{data=PendingWithdrawalDTO} "Withdrawal awaiting approval"
 // @Failure 400 {object} common.ProblemDetails "Invalid request"
 // @Failure 401 {object} common.ProblemDetails "Unauthorized"
 // @Failure 429 {object} common.ProblemDetails "Too many requests"
 // @Failure 500 {object} common.ProblemDetails "Internal server error"
-// @Router /account/{id}/withdraw [post]
+// @Router /account/{ref}/withdraw [post]
 // @Security Bearer
 func Withdraw(
 	accountSvc *accountsvc.Service,
+	commandBus commandbus.Bus,
 	authSvc *authsvc.Service,
+	currencySvc *currencysvc.Service,
+	targetValidator *validation.ExternalTargetValidator,
 ) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		token, ok := c.Locals("user").(*jwt.Token)
@@ -402,33 +622,14 @@ func Withdraw(
 			log.Error("failed to get user ID from token", "error", err)
 			return common.ProblemDetailsJSON(c, "Invalid user ID", err)
 		}
-		accountID, err := uuid.Parse(c.Params("id"))
-		if err != nil {
-			log.Error("invalid account ID for withdrawal", "error", err)
-			return common.ProblemDetailsJSON(
-				c,
-				"Invalid account ID",
-				err,
-				"Account ID must be a valid UUID",
-				fiber.StatusBadRequest,
-			)
+		accountID, err := resolveAccountID(c, accountSvc)
+		if accountID == uuid.Nil {
+			return err // error response already written
 		}
 		input, err := common.BindAndValidate[WithdrawRequest](c)
 		if input == nil {
 			return err // error response already written
 		}
-		// Validate that at least one field in ExternalTarget is present
-		if input.ExternalTarget.BankAccountNumber == "" &&
-			input.ExternalTarget.RoutingNumber == "" &&
-			input.ExternalTarget.ExternalWalletAddress == "" {
-			return common.ProblemDetailsJSON(
-				c,
-				"Invalid external target",
-				nil,
-				"At least one external target field must be provided",
-				fiber.StatusBadRequest,
-			)
-		}
 		// Validate and parse currency code
 		currencyCode := money.Code(input.Currency)
 		if currencyCode == "" {
@@ -440,23 +641,78 @@ func Withdraw(
 				fiber.StatusBadRequest,
 			)
 		}
+		cur, _ := registryCurrency(c, currencySvc, string(currencyCode))
+		if err := money.ValidatePrecision(input.Amount, cur); err != nil {
+			return common.ProblemDetailsJSON(c, "Invalid amount", err)
+		}
 
 		withdrawCmd := commands.Withdraw{
-			UserID:    userID,
-			AccountID: accountID,
-			Amount:    input.Amount,
-			Currency:  string(currencyCode),
+			UserID:         userID,
+			AccountID:      accountID,
+			Amount:         input.Amount,
+			Currency:       string(currencyCode),
+			Description:    input.Description,
+			IdempotencyKey: c.Get(common.HeaderIdempotencyKey),
 		}
 
-		if input.ExternalTarget != nil {
+		if input.DestinationID != "" {
+			// A saved destination replaces external_target; it is re-validated
+			// below against this withdrawal's currency.
+			destinationID, _ := uuid.Parse(input.DestinationID) // validated by binding
+			withdrawCmd.ExternalTarget, err = accountSvc.PayoutDestinationTarget(
+				c.UserContext(),
+				userID,
+				destinationID,
+			)
+			if err != nil {
+				log.Error("failed to resolve payout destination", "error", err)
+				return common.ProblemDetailsJSON(c, "Invalid payout destination", err)
+			}
+		} else if input.ExternalTarget != nil {
 			withdrawCmd.ExternalTarget = &commands.ExternalTarget{
 				BankAccountNumber:     input.ExternalTarget.BankAccountNumber,
 				RoutingNumber:         input.ExternalTarget.RoutingNumber,
+				BIC:                   input.ExternalTarget.BIC,
 				ExternalWalletAddress: input.ExternalTarget.ExternalWalletAddress,
+				Network:               input.ExternalTarget.Network,
+			}
+		}
+
+		// Validate the external target fields (ABA checksum, IBAN, wallet network)
+		if err = targetValidator.Validate(
+			withdrawCmd.ExternalTarget,
+			withdrawCmd.Currency,
+		); err != nil {
+			var fieldErrs validation.Errors
+			if errors.As(err, &fieldErrs) {
+				return common.ProblemDetailsJSON(
+					c,
+					"Invalid external target",
+					nil,
+					"One or more external target fields are invalid",
+					fieldErrs.Fields(),
+					fiber.StatusBadRequest,
+				)
 			}
+			return common.ProblemDetailsJSON(c, "Invalid external target", err)
 		}
 
-		if err = accountSvc.Withdraw(c.Context(), withdrawCmd); err != nil {
+		requiresApproval, err := accountSvc.WithdrawalRequiresApproval(
+			c.UserContext(), withdrawCmd,
+		)
+		if err != nil {
+			log.Error("failed to check withdrawal approval", "error", err)
+			return common.ProblemDetailsJSON(c, "Failed to process withdrawal", err)
+		}
+		var pending *dto.PendingWithdrawalRead
+		if requiresApproval {
+			pending, err = accountSvc.RequestWithdrawalApproval(
+				common.DetachedContext(c), withdrawCmd,
+			)
+		} else {
+			err = commandBus.Dispatch(common.DetachedContext(c), withdrawCmd)
+		}
+		if err != nil {
 			log.Error(
 				"failed to process withdrawal",
 				"error",
@@ -474,24 +730,30 @@ func Withdraw(
 					"Stripe Connect onboarding required",
 					err,
 					"Please complete Stripe Connect onboarding before making a withdrawal",
-					fiber.StatusForbidden,
 				)
 			}
 
 			// Handle insufficient funds error
-			if strings.Contains(err.Error(), "insufficient funds") {
+			if errors.Is(err, accountdomain.ErrInsufficientFunds) {
 				return common.ProblemDetailsJSON(
 					c,
 					"Insufficient funds",
 					err,
 					"Your account does not have sufficient funds for this withdrawal",
-					fiber.StatusBadRequest,
 				)
 			}
 
 			return common.ProblemDetailsJSON(c, "Failed to process withdrawal", err)
 		}
 
+		if pending != nil {
+			return common.SuccessResponseJSON(
+				c,
+				fiber.StatusAccepted,
+				"Withdrawal is awaiting approval",
+				ToPendingWithdrawalDTO(pending),
+			)
+		}
 		return common.SuccessResponseJSON(
 			c,
 			fiber.StatusAccepted,
@@ -506,26 +768,32 @@ func Withdraw(
 // @Summary Transfer funds between accounts
 // @Description Transfers a specified amount from one account to another.
 // Specify the source and destination account IDs, amount, and currency.
-// Returns the transaction details.
+// Set execute_at to schedule the transfer for a future time instead, or
+// require_approval to debit the source now and credit the destination once
+// an admin approves the transfer.
+// Returns the transaction details, the scheduled transfer or the transfer
+// awaiting approval.
 // @Tags accounts
 // @Accept json
 // @Produce json
-// @Param id path string true "Source Account ID"
+// @Param ref path string true "Source account ID or reference"
 // @Param request body TransferRequest true "Transfer details"
+// @Param Idempotency-Key header string false "Makes a retried request take effect only once"
 // @Success 200 {object} common.Response "Transfer successful"
 // @Failure 400 {object} common.ProblemDetails "Invalid request"
 // @Failure 401 {object} common.ProblemDetails "Unauthorized"
 // @Failure 422 {object} common.ProblemDetails "Unprocessable entity"
 // @Failure 429 {object} common.ProblemDetails "Too many requests"
 // @Failure 500 {object} common.ProblemDetails "Internal server error"
-// @Router /account/{id}/transfer [post]
+// @Router /account/{ref}/transfer [post]
 // @Security Bearer
 func Transfer(
 	accountSvc *accountsvc.Service,
+	commandBus commandbus.Bus,
 	authSvc *authsvc.Service,
 ) fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		log.Info("transfer handler called", "account_id", c.Params("id"))
+		log.Info("transfer handler called", "account_ref", c.Params("ref"))
 		token, ok := c.Locals("user").(*jwt.Token)
 		if !ok {
 			return common.ProblemDetailsJSON(c, "Unauthorized", nil, "missing user context")
@@ -535,16 +803,9 @@ func Transfer(
 			log.Error("failed to get user ID from token", "error", err)
 			return common.ProblemDetailsJSON(c, "Invalid user ID", err)
 		}
-		sourceAccountID, err := uuid.Parse(c.Params("id"))
-		if err != nil {
-			log.Error("invalid source account ID for transfer", "error", err)
-			return common.ProblemDetailsJSON(
-				c,
-				"Invalid account ID",
-				err,
-				"Account ID must be a valid UUID",
-				fiber.StatusBadRequest,
-			)
+		sourceAccountID, err := resolveAccountID(c, accountSvc)
+		if sourceAccountID == uuid.Nil {
+			return err // error response already written
 		}
 		input, err := common.BindAndValidate[TransferRequest](c)
 		if input == nil {
@@ -567,13 +828,61 @@ func Transfer(
 		}
 		// Construct transfer command
 		cmd := commands.Transfer{
-			UserID:      userID,
-			AccountID:   sourceAccountID,
-			ToAccountID: destAccountID,
-			Amount:      input.Amount,
-			Currency:    currencyCode.String(),
+			UserID:         userID,
+			AccountID:      sourceAccountID,
+			ToAccountID:    destAccountID,
+			Amount:         input.Amount,
+			Currency:       currencyCode.String(),
+			Description:    input.Description,
+			IdempotencyKey: c.Get(common.HeaderIdempotencyKey),
+		}
+		if input.RequireApproval {
+			if input.ExecuteAt != nil {
+				cmd.ExecuteAt = *input.ExecuteAt
+			}
+			pending, err := accountSvc.RequestTransferApproval(common.DetachedContext(c), cmd)
+			if err != nil {
+				log.Error(
+					"failed to request transfer approval",
+					"error",
+					err,
+					"user_id",
+					userID,
+					"account_id",
+					sourceAccountID,
+				)
+				return common.ProblemDetailsJSON(c, "Failed to transfer", err)
+			}
+			return common.SuccessResponseJSON(
+				c,
+				fiber.StatusAccepted,
+				"Transfer is awaiting approval",
+				ToPendingTransferDTO(pending),
+			)
 		}
-		err = accountSvc.Transfer(c.Context(), cmd)
+		if input.ExecuteAt != nil {
+			cmd.ExecuteAt = *input.ExecuteAt
+			scheduled, err := accountSvc.ScheduleTransfer(c.UserContext(), cmd)
+			if err != nil {
+				log.Error(
+					"failed to schedule transfer",
+					"error",
+					err,
+					"user_id",
+					userID,
+					"account_id",
+					sourceAccountID,
+				)
+				return common.ProblemDetailsJSON(c, "Failed to schedule transfer", err)
+			}
+			return common.SuccessResponseJSON(
+				c,
+				fiber.StatusAccepted,
+				"Transfer scheduled",
+				ToScheduledTransferDTO(scheduled),
+			)
+		}
+		err = commandBus.Dispatch(common.DetachedContext(c), cmd)
 		if err != nil {
 			log.Error(
 				"failed to transfer funds",
@@ -618,13 +927,13 @@ func Transfer(
 // @Tags accounts
 // @Accept json
 // @Produce json
-// @Param id path string true "Account ID"
+// @Param ref path string true "Account ID or reference"
 // @Success 200 {object} common.Response "Transactions fetched"
 // @Failure 400 {object} common.ProblemDetails "Invalid request"
 // @Failure 401 {object} common.ProblemDetails "Unauthorized"
 // @Failure 429 {object} common.ProblemDetails "Too many requests"
 // @Failure 500 {object} common.ProblemDetails "Internal server error"
-// @Router /account/{id}/transactions [get]
+// @Router /account/{ref}/transactions [get]
 // @Security Bearer
 func GetTransactions(
 	accountSvc *accountsvc.Service,
@@ -640,19 +949,16 @@ func GetTransactions(
 			log.Error("failed to get user ID from token", "error", err)
 			return common.ProblemDetailsJSON(c, "Invalid user ID", err)
 		}
-		id, err := uuid.Parse(c.Params("id"))
-		if err != nil {
-			log.Error("invalid account ID for transactions", "error", err)
-			return common.ProblemDetailsJSON(
-				c,
-				"Invalid account ID",
-				err,
-				"Account ID must be a valid UUID",
-				fiber.StatusBadRequest,
-			)
+		id, err := resolveAccountID(c, accountSvc)
+		if id == uuid.Nil {
+			return err // error response already written
 		}
 
-		tx, err := accountSvc.GetTransactions(c.Context(), userID, id)
+		tx, err := accountSvc.GetTransactions(
+			repository.WithReplicaReads(c.UserContext()),
+			userID,
+			id,
+		)
 		if err != nil {
 			log.Error(
 				"failed to list transactions for account ID %s",
@@ -663,23 +969,11 @@ func GetTransactions(
 			)
 			return common.ProblemDetailsJSON(c, "Failed to list transactions", err)
 		}
-		dtos := make([]*TransactionDTO, 0, len(tx))
-		for _, t := range tx {
-			dtos = append(dtos, &TransactionDTO{
-				ID:        t.ID.String(),
-				UserID:    t.UserID.String(),
-				AccountID: t.AccountID.String(),
-				Amount:    t.Amount,
-				Currency:  string(t.Currency),
-				Balance:   t.Balance,
-				CreatedAt: t.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
-			})
-		}
 		return common.SuccessResponseJSON(
 			c,
 			fiber.StatusOK,
 			"Transactions fetched",
-			dtos,
+			transactionsResponse(c, tx),
 		)
 	}
 }
@@ -688,25 +982,33 @@ func GetTransactions(
 // It expects a UnitOfWork factory function as a dependency for service instantiation.
 // The handler extracts the current user ID from the request context and
 // parses the account ID from the URL parameters.
-// On success, it returns the account balance as a JSON response.
+// On success, it returns the account balance formatted to the decimals of the
+// account currency, with the currency symbol from the currency registry.
 // On error, it logs the error and returns an appropriate JSON error response.
 // @Summary Get account balance
 // @Description Retrieves the current balance for the specified account.
-// Returns the balance amount and currency.
+// amount is a display string with the currency's decimal places (e.g. "1234.50" for USD,
+// "1235" for JPY); money is the exact balance in the smallest currency unit; balance is
+// a deprecated float kept for backward compatibility. With display, the balance is also
+// converted to that currency; if the conversion is unavailable, display only reports
+// conversion_available false.
 // @Tags accounts
 // @Accept json
 // @Produce json
-// @Param id path string true "Account ID"
-// @Success 200 {object} common.Response "Balance fetched"
+// @Param ref path string true "Account ID or reference"
+// @Param display query string false "Currency to also show the balance in (e.g. EUR)"
+// @Success 200 {object} common.Response{data=BalanceResponse} "Balance fetched"
 // @Failure 400 {object} common.ProblemDetails "Invalid request"
 // @Failure 401 {object} common.ProblemDetails "Unauthorized"
+// @Failure 404 {object} common.ProblemDetails "Account not found"
 // @Failure 429 {object} common.ProblemDetails "Too many requests"
 // @Failure 500 {object} common.ProblemDetails "Internal server error"
-// @Router /account/{id}/balance [get]
+// @Router /account/{ref}/balance [get]
 // @Security Bearer
 func GetBalance(
 	accountSvc *accountsvc.Service,
 	authSvc *authsvc.Service,
+	currencySvc *currencysvc.Service,
 ) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		token, ok := c.Locals("user").(*jwt.Token)
@@ -718,19 +1020,29 @@ func GetBalance(
 			log.Error("failed to get user ID from token", "error", err)
 			return common.ProblemDetailsJSON(c, "Invalid user ID", err)
 		}
-		id, err := uuid.Parse(c.Params("id"))
-		if err != nil {
-			log.Error("invalid account ID for balance", "error", err)
+		id, err := resolveAccountID(c, accountSvc)
+		if id == uuid.Nil {
+			return err // error response already written
+		}
+		display := money.Code(strings.ToUpper(strings.TrimSpace(c.Query("display"))))
+		if display != "" && !display.IsValid() {
 			return common.ProblemDetailsJSON(
 				c,
-				"Invalid account ID",
-				err,
-				"Account ID must be a valid UUID",
+				"Invalid display currency",
+				nil,
+				"display must be a 3-letter ISO 4217 currency code",
 				fiber.StatusBadRequest,
 			)
 		}
 
-		balance, err := accountSvc.GetBalance(c.Context(), userID, id)
+		acc, err := accountSvc.GetAccount(
+			repository.WithReplicaReads(c.UserContext()),
+			userID,
+			id,
+		)
+		if err == nil && (acc == nil || acc.UserID != userID) {
+			err = accountdomain.ErrAccountNotFound
+		}
 		if err != nil {
 			log.Errorf("Failed to fetch balance for account ID %s: %v", id, err)
 			return common.ProblemDetailsJSON(
@@ -739,11 +1051,146 @@ func GetBalance(
 				err,
 			)
 		}
+		balance := toBalanceResponse(c, currencySvc, acc)
+		if display != "" {
+			balance.Display = toDisplayBalance(c, accountSvc, balance.Money, display)
+		}
 		return common.SuccessResponseJSON(
 			c,
 			fiber.StatusOK,
 			"Balance fetched",
-			fiber.Map{"balance": balance},
+			balanceResponse(c, balance),
 		)
 	}
 }
+
+// UpdateAccount returns a Fiber handler that updates the settings of the
+// current user's account.
+// @Summary Update account settings
+// @Description Sets whether deposits in another currency are converted to the account
+// currency (auto_convert, the default) or rejected.
+// @Tags accounts
+// @Accept json
+// @Produce json
+// @Param ref path string true "Account ID or reference"
+// @Param request body UpdateAccountRequest true "Account settings"
+// @Success 200 {object} common.Response{data=dto.AccountRead} "Account updated"
+// @Failure 400 {object} common.ProblemDetails "Invalid request"
+// @Failure 401 {object} common.ProblemDetails "Unauthorized"
+// @Failure 404 {object} common.ProblemDetails "Account not found"
+// @Failure 500 {object} common.ProblemDetails "Internal server error"
+// @Router /account/{ref} [patch]
+// @Security Bearer
+func UpdateAccount(
+	accountSvc *accountsvc.Service,
+	authSvc *authsvc.Service,
+) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		token, ok := c.Locals("user").(*jwt.Token)
+		if !ok {
+			return common.ProblemDetailsJSON(c, "Unauthorized", nil, "missing user context")
+		}
+		userID, err := authSvc.GetCurrentUserId(token)
+		if err != nil {
+			log.Error("failed to get user ID from token", "error", err)
+			return common.ProblemDetailsJSON(c, "Invalid user ID", err)
+		}
+		id, err := resolveAccountID(c, accountSvc)
+		if id == uuid.Nil {
+			return err // error response already written
+		}
+		input, err := common.BindAndValidate[UpdateAccountRequest](c)
+		if input == nil {
+			return err // error response already written
+		}
+		acc, err := accountSvc.SetAutoConvert(c.UserContext(), userID, id, *input.AutoConvert)
+		if err != nil {
+			log.Error("failed to update account", "error", err, "account_id", id)
+			return common.ProblemDetailsJSON(c, "Failed to update account", err)
+		}
+		return common.SuccessResponseJSON(
+			c,
+			fiber.StatusOK,
+			"Account updated",
+			accountResponse(c, acc),
+		)
+	}
+}
+
+// toBalanceResponse formats the balance of acc with the decimals and symbol of
+// its currency in the registry. If the currency is not registered it falls
+// back to the default decimals for the code and leaves the symbol empty.
+func toBalanceResponse(
+	c *fiber.Ctx,
+	currencySvc *currencysvc.Service,
+	acc *dto.AccountRead,
+) BalanceResponse {
+	cur, symbol := registryCurrency(c, currencySvc, acc.Currency)
+	exact, err := money.New(acc.Balance, cur)
+	if acc.BalanceMoney != nil {
+		// Read the exact balance with the registry's decimals, which may
+		// differ from the defaults the repository assumes.
+		exact, err = money.NewFromSmallestUnit(acc.BalanceMoney.Amount(), cur)
+	}
+	resp := BalanceResponse{
+		Amount:   strconv.FormatFloat(acc.Balance, 'f', cur.Decimals, 64),
+		Currency: acc.Currency,
+		Symbol:   symbol,
+		Balance:  acc.Balance,
+	}
+	if err == nil {
+		resp.Amount = exact.AmountString()
+		resp.Money = exact
+	}
+	resp.Interest = toInterestDTO(acc)
+	return resp
+}
+
+// toDisplayBalance converts balance to the display currency. A failed
+// conversion is reported as unavailable rather than failing the request.
+func toDisplayBalance(
+	c *fiber.Ctx,
+	accountSvc *accountsvc.Service,
+	balance *money.Money,
+	display money.Code,
+) *DisplayBalanceDTO {
+	out := &DisplayBalanceDTO{Currency: display.String()}
+	if balance == nil {
+		return out
+	}
+	converted, rate, err := accountSvc.ConvertBalance(c.UserContext(), balance, display)
+	if err != nil {
+		log.Warn("balance display conversion unavailable",
+			"from", balance.Currency(), "to", display, "error", err)
+		return out
+	}
+	out.ConversionAvailable = true
+	out.Amount = converted.AmountString()
+	out.Money = converted
+	if rate != nil {
+		out.Rate = rate.Rate
+	}
+	return out
+}
+
+// registryCurrency returns the currency for code with its decimals and symbol
+// from the registry. If the currency is not registered it falls back to the
+// default decimals for the code and an empty symbol.
+func registryCurrency(
+	c *fiber.Ctx,
+	currencySvc *currencysvc.Service,
+	code string,
+) (money.Currency, string) {
+	cur := money.Code(code).ToCurrency()
+	if currencySvc == nil {
+		return cur, ""
+	}
+	meta, err := currencySvc.GetEntity(c.UserContext(), code)
+	if err != nil {
+		log.Warn("currency not in registry; using default decimals",
+			"currency", code, "error", err)
+		return cur, ""
+	}
+	cur.Decimals = meta.Decimals
+	return cur, meta.Symbol
+}
@@ -89,6 +89,28 @@ func (s *AccountTestSuite) TestDeposit() {
 	s.Contains(depositResponse.Message, "Deposit request is being processed")
 }
 
+func (s *AccountTestSuite) TestDeposit_AmountPrecision() {
+	user := s.CreateTestUser()
+	token := s.LoginUser(user)
+
+	acc, _ := account.New().WithUserID(user.ID).Build()
+
+	depositBody := `{"amount":10.999,"currency":"USD","money_source":"cash"}`
+	resp := s.MakeRequest(
+		"POST",
+		fmt.Sprintf("/account/%s/deposit", acc.ID),
+		depositBody,
+		token,
+	)
+	defer resp.Body.Close() //nolint: errcheck
+	s.Equal(fiber.StatusBadRequest, resp.StatusCode)
+
+	var errorResponse common.ProblemDetails
+	s.Require().NoError(json.NewDecoder(resp.Body).Decode(&errorResponse))
+	s.Equal("Invalid amount", errorResponse.Title)
+	s.Contains(errorResponse.Detail, "USD allows at most 2 decimal places")
+}
+
 func (s *AccountTestSuite) TestWithdraw() {
 	// First create an account and deposit some money
 	createResp := s.MakeRequest("POST", "/account", `{"currency":"USD"}`, s.token)
@@ -197,6 +219,53 @@ func (s *AccountTestSuite) TestGetBalance() {
 		)
 		defer resp.Body.Close() //nolint: errcheck
 		s.Equal(fiber.StatusOK, resp.StatusCode)
+
+		var response common.Response
+		s.Require().NoError(json.NewDecoder(resp.Body).Decode(&response))
+		balance, ok := response.Data.(map[string]any)
+		s.Require().True(ok, "Expected balance data to be a map")
+		s.Equal("0.00", balance["amount"])
+		s.Equal("USD", balance["currency"])
+		s.Equal("$", balance["symbol"])
+		s.InDelta(0.0, balance["balance"], 0.001)
+		s.Equal(
+			map[string]any{"amount": 0.0, "currency": "USD"},
+			balance["money"],
+		)
+	})
+
+	s.Run("Get balance in a display currency", func() {
+		resp := s.MakeRequest(
+			"GET",
+			fmt.Sprintf("/account/%s/balance?display=eur", accountID),
+			"",
+			s.token,
+		)
+		defer resp.Body.Close() //nolint: errcheck
+		s.Equal(fiber.StatusOK, resp.StatusCode)
+
+		var response common.Response
+		s.Require().NoError(json.NewDecoder(resp.Body).Decode(&response))
+		balance, ok := response.Data.(map[string]any)
+		s.Require().True(ok, "Expected balance data to be a map")
+		s.Equal("USD", balance["currency"])
+		display, ok := balance["display"].(map[string]any)
+		s.Require().True(ok, "Expected display balance to be a map")
+		s.Equal("EUR", display["currency"])
+		s.Equal(true, display["conversion_available"])
+		s.Equal("0.00", display["amount"])
+		s.Greater(display["rate"], 0.0)
+	})
+
+	s.Run("Get balance with an invalid display currency", func() {
+		resp := s.MakeRequest(
+			"GET",
+			fmt.Sprintf("/account/%s/balance?display=EURO", accountID),
+			"",
+			s.token,
+		)
+		defer resp.Body.Close() //nolint: errcheck
+		s.Equal(fiber.StatusBadRequest, resp.StatusCode)
 	})
 
 	s.Run("Get balance without auth", func() {
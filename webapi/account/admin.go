@@ -0,0 +1,260 @@
+package account
+
+import (
+	"strings"
+
+	"github.com/amirasaad/fintech/pkg/currency"
+	"github.com/amirasaad/fintech/pkg/dto"
+	"github.com/amirasaad/fintech/pkg/repository"
+	accountsvc "github.com/amirasaad/fintech/pkg/service/account"
+	"github.com/amirasaad/fintech/webapi/common"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/log"
+	"github.com/google/uuid"
+)
+
+// ListAccountsByCurrency returns a Fiber handler that lists the accounts in
+// one currency across all users, for liquidity checks.
+// @Summary List accounts by currency (admin only)
+// @Description Lists one page of the accounts in the given currency across all users,
+// with the count and balance totals of every matching account. Closed accounts are
+// excluded unless include_closed is true.
+// @Tags admin
+// @Produce json
+// @Param currency query string true "ISO 4217 currency code"
+// @Param page query int false "Page number, starting at 1" default(1)
+// @Param page_size query int false "Page size (max 500)" default(50)
+// @Param include_closed query bool false "Include closed accounts" default(false)
+// @Success 200 {object} common.Response{data=AccountsByCurrencyResponse} "Accounts"
+// @Failure 400 {object} common.ProblemDetails "Invalid currency"
+// @Failure 401 {object} common.ProblemDetails "Unauthorized"
+// @Failure 403 {object} common.ProblemDetails "Not an admin"
+// @Failure 500 {object} common.ProblemDetails "Internal server error"
+// @Router /admin/accounts [get]
+// @Security Bearer
+func ListAccountsByCurrency(accountSvc *accountsvc.Service) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		code := strings.ToUpper(c.Query("currency"))
+		if !currency.IsValidFormat(code) {
+			return common.ProblemDetailsJSON(
+				c,
+				"Invalid currency code",
+				nil,
+				"Please provide a valid 3-letter ISO 4217 currency code",
+				fiber.StatusBadRequest,
+			)
+		}
+		page := max(c.QueryInt("page", 1), 1)
+		pageSize := c.QueryInt("page_size", accountsvc.DefaultAccountPageSize)
+		if pageSize < 1 {
+			pageSize = accountsvc.DefaultAccountPageSize
+		}
+		pageSize = min(pageSize, accountsvc.MaxAccountPageSize)
+		includeClosed := c.QueryBool("include_closed", false)
+
+		accounts, totals, err := accountSvc.ListAccountsByCurrency(
+			repository.WithReplicaReads(c.UserContext()),
+			code,
+			includeClosed,
+			page,
+			pageSize,
+		)
+		if err != nil {
+			log.Error("failed to list accounts by currency", "error", err, "currency", code)
+			return common.ProblemDetailsJSON(c, "Failed to list accounts", err)
+		}
+		if accounts == nil {
+			accounts = []*dto.AccountRead{}
+		}
+		return common.SuccessResponseJSON(
+			c,
+			fiber.StatusOK,
+			"Accounts retrieved successfully",
+			AccountsByCurrencyResponse{
+				Accounts: accounts,
+				Totals: AccountTotalsDTO{
+					Currency: totals.Currency,
+					Count:    totals.Count,
+					Balance:  totals.Balance,
+				},
+				Page:     page,
+				PageSize: pageSize,
+			},
+		)
+	}
+}
+
+// SetOverdraftLimit returns a Fiber handler that sets how far below zero an
+// account's balance may go.
+// @Summary Set an account's overdraft limit (admin only)
+// @Description Lets the account's balance go down to -limit, in the account currency.
+// A limit of 0 removes the overdraft. Withdrawals and transfers beyond the limit are
+// rejected; a balance already below a lowered limit is left as is.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Account ID"
+// @Param request body SetOverdraftLimitRequest true "Overdraft limit"
+// @Success 200 {object} common.Response{data=dto.AccountRead} "Overdraft limit updated"
+// @Failure 400 {object} common.ProblemDetails "Invalid account ID or limit"
+// @Failure 401 {object} common.ProblemDetails "Unauthorized"
+// @Failure 403 {object} common.ProblemDetails "Not an admin"
+// @Failure 404 {object} common.ProblemDetails "Account not found"
+// @Failure 500 {object} common.ProblemDetails "Internal server error"
+// @Router /admin/accounts/{id}/overdraft [put]
+// @Security Bearer
+func SetOverdraftLimit(accountSvc *accountsvc.Service) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		accountID, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return common.ProblemDetailsJSON(
+				c,
+				"Invalid account ID",
+				err,
+				"Account ID must be a valid UUID",
+				fiber.StatusBadRequest,
+			)
+		}
+		input, err := common.BindAndValidate[SetOverdraftLimitRequest](c)
+		if input == nil {
+			return err // error response already written
+		}
+		acc, err := accountSvc.SetOverdraftLimit(c.UserContext(), accountID, *input.Limit)
+		if err != nil {
+			log.Error("failed to set overdraft limit", "error", err, "account_id", accountID)
+			return common.ProblemDetailsJSON(c, "Failed to set overdraft limit", err)
+		}
+		return common.SuccessResponseJSON(
+			c,
+			fiber.StatusOK,
+			"Overdraft limit updated",
+			acc,
+		)
+	}
+}
+
+// SetFeePolicy returns a Fiber handler that sets who bears the provider fee
+// on deposits to an account.
+// @Summary Set an account's fee policy (admin only)
+// @Description Sets who bears the provider fee on deposits to the account. absorb
+// credits the full amount and records the fee as a platform cost; pass_through
+// deducts it from the credited amount. An empty policy follows FEE_PROVIDER_FEE_POLICY.
+// Applies to fees charged after the change.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Account ID"
+// @Param request body SetFeePolicyRequest true "Fee policy"
+// @Success 200 {object} common.Response{data=dto.AccountRead} "Fee policy updated"
+// @Failure 400 {object} common.ProblemDetails "Invalid account ID or policy"
+// @Failure 401 {object} common.ProblemDetails "Unauthorized"
+// @Failure 403 {object} common.ProblemDetails "Not an admin"
+// @Failure 404 {object} common.ProblemDetails "Account not found"
+// @Failure 500 {object} common.ProblemDetails "Internal server error"
+// @Router /admin/accounts/{id}/fee-policy [put]
+// @Security Bearer
+func SetFeePolicy(accountSvc *accountsvc.Service) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		accountID, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return common.ProblemDetailsJSON(
+				c,
+				"Invalid account ID",
+				err,
+				"Account ID must be a valid UUID",
+				fiber.StatusBadRequest,
+			)
+		}
+		input, err := common.BindAndValidate[SetFeePolicyRequest](c)
+		if input == nil {
+			return err // error response already written
+		}
+		acc, err := accountSvc.SetFeePolicy(c.UserContext(), accountID, *input.Policy)
+		if err != nil {
+			log.Error("failed to set fee policy", "error", err, "account_id", accountID)
+			return common.ProblemDetailsJSON(c, "Failed to set fee policy", err)
+		}
+		return common.SuccessResponseJSON(
+			c,
+			fiber.StatusOK,
+			"Fee policy updated",
+			acc,
+		)
+	}
+}
+
+// ImportTransactions returns a Fiber handler that seeds an account with
+// historical transactions from another system.
+// @Summary Import historical transactions (admin only)
+// @Description Writes a batch of completed transactions, oldest first, straight to the
+// account and recomputes its balance, without emitting events or calling payment
+// providers. Rows must be in the account currency and in chronological order. Rows
+// whose reference was already imported into the account are skipped, so the import
+// can be retried. Nothing is imported if any row is invalid.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Account ID"
+// @Param request body ImportTransactionsRequest true "Transactions to import"
+// @Success 200 {object} common.Response{data=ImportTransactionsResponse} "Transactions imported"
+// @Failure 400 {object} common.ProblemDetails "Invalid account ID or rows"
+// @Failure 401 {object} common.ProblemDetails "Unauthorized"
+// @Failure 403 {object} common.ProblemDetails "Not an admin"
+// @Failure 404 {object} common.ProblemDetails "Account not found"
+// @Failure 422 {object} common.ProblemDetails "Currency mismatch or insufficient funds"
+// @Failure 500 {object} common.ProblemDetails "Internal server error"
+// @Router /admin/accounts/{id}/transactions/import [post]
+// @Security Bearer
+func ImportTransactions(accountSvc *accountsvc.Service) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		accountID, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return common.ProblemDetailsJSON(
+				c,
+				"Invalid account ID",
+				err,
+				"Account ID must be a valid UUID",
+				fiber.StatusBadRequest,
+			)
+		}
+		input, err := common.BindAndValidate[ImportTransactionsRequest](c)
+		if input == nil {
+			return err // error response already written
+		}
+		rows := make([]dto.TransactionImport, 0, len(input.Transactions))
+		for _, row := range input.Transactions {
+			rows = append(rows, dto.TransactionImport{
+				Reference: row.Reference,
+				Type:      row.Type,
+				Amount:    row.Amount,
+				Currency:  row.Currency,
+				Timestamp: row.Timestamp,
+				Memo:      row.Memo,
+			})
+		}
+		result, err := accountSvc.ImportTransactions(c.UserContext(), accountID, rows)
+		if err != nil {
+			log.Error("failed to import transactions", "error", err, "account_id", accountID)
+			return common.ProblemDetailsJSON(c, "Failed to import transactions", err)
+		}
+		imported := make([]*TransactionDTO, 0, len(result.Imported))
+		for _, tx := range result.Imported {
+			imported = append(imported, ToTransactionDTO(tx))
+		}
+		skipped := result.Skipped
+		if skipped == nil {
+			skipped = []string{}
+		}
+		return common.SuccessResponseJSON(
+			c,
+			fiber.StatusOK,
+			"Transactions imported",
+			ImportTransactionsResponse{
+				Imported: imported,
+				Skipped:  skipped,
+				Balance:  result.Balance,
+			},
+		)
+	}
+}
@@ -0,0 +1,156 @@
+package account
+
+import (
+	"context"
+
+	"github.com/amirasaad/fintech/pkg/dto"
+	accountsvc "github.com/amirasaad/fintech/pkg/service/account"
+	authsvc "github.com/amirasaad/fintech/pkg/service/auth"
+	"github.com/amirasaad/fintech/pkg/service/checkout"
+	"github.com/amirasaad/fintech/webapi/common"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/log"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// ListPendingDeposits returns a Fiber handler that lists the deposits of an
+// account that have been requested but not paid yet.
+// @Summary List pending deposits
+// @Description Lists the deposits of the account that have been requested but not
+// paid yet, with the URL of their checkout while it can still be paid.
+// @Tags accounts
+// @Produce json
+// @Param ref path string true "Account ID or reference"
+// @Success 200 {object} common.Response{data=[]DepositDTO} "Pending deposits fetched"
+// @Failure 400 {object} common.ProblemDetails "Invalid request"
+// @Failure 401 {object} common.ProblemDetails "Unauthorized"
+// @Failure 404 {object} common.ProblemDetails "Account not found"
+// @Failure 500 {object} common.ProblemDetails "Internal server error"
+// @Router /account/{ref}/deposits/pending [get]
+// @Security Bearer
+func ListPendingDeposits(
+	accountSvc *accountsvc.Service,
+	checkoutSvc *checkout.Service,
+	authSvc *authsvc.Service,
+) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		token, ok := c.Locals("user").(*jwt.Token)
+		if !ok {
+			return common.ProblemDetailsJSON(c, "Unauthorized", nil, "missing user context")
+		}
+		userID, err := authSvc.GetCurrentUserId(token)
+		if err != nil {
+			log.Error("failed to get user ID from token", "error", err)
+			return common.ProblemDetailsJSON(c, "Invalid user ID", err)
+		}
+		id, err := resolveAccountID(c, accountSvc)
+		if id == uuid.Nil {
+			return err // error response already written
+		}
+
+		txs, err := accountSvc.ListPendingDeposits(c.UserContext(), userID, id)
+		if err != nil {
+			log.Error("failed to list pending deposits", "error", err, "account_id", id)
+			return common.ProblemDetailsJSON(c, "Failed to list pending deposits", err)
+		}
+		out := make([]*DepositDTO, 0, len(txs))
+		for _, tx := range txs {
+			out = append(out, toDepositDTO(c.UserContext(), checkoutSvc, tx))
+		}
+		return common.SuccessResponseJSON(c, fiber.StatusOK, "Pending deposits fetched", out)
+	}
+}
+
+// CancelDeposit returns a Fiber handler that cancels a deposit that has not
+// been paid yet.
+// @Summary Cancel a pending deposit
+// @Description Expires the checkout of a deposit that has not been paid yet, so it can
+// no longer be paid, and marks the deposit canceled.
+// @Tags accounts
+// @Produce json
+// @Param txId path string true "Deposit transaction ID"
+// @Success 200 {object} common.Response{data=DepositDTO} "Deposit canceled"
+// @Failure 400 {object} common.ProblemDetails "Invalid request"
+// @Failure 401 {object} common.ProblemDetails "Unauthorized"
+// @Failure 404 {object} common.ProblemDetails "Deposit not found"
+// @Failure 409 {object} common.ProblemDetails "Deposit already paid, failed or canceled"
+// @Failure 501 {object} common.ProblemDetails "Canceling deposits not supported"
+// @Failure 500 {object} common.ProblemDetails "Internal server error"
+// @Router /deposits/{txId}/cancel [post]
+// @Security Bearer
+func CancelDeposit(
+	accountSvc *accountsvc.Service,
+	authSvc *authsvc.Service,
+) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		token, ok := c.Locals("user").(*jwt.Token)
+		if !ok {
+			return common.ProblemDetailsJSON(c, "Unauthorized", nil, "missing user context")
+		}
+		userID, err := authSvc.GetCurrentUserId(token)
+		if err != nil {
+			log.Error("failed to get user ID from token", "error", err)
+			return common.ProblemDetailsJSON(c, "Invalid user ID", err)
+		}
+		txID, err := uuid.Parse(c.Params("txId"))
+		if err != nil {
+			return common.ProblemDetailsJSON(
+				c,
+				"Invalid transaction ID",
+				err,
+				"Transaction ID must be a valid UUID",
+				fiber.StatusBadRequest,
+			)
+		}
+		tx, err := accountSvc.CancelDeposit(common.DetachedContext(c), userID, txID)
+		if err != nil {
+			log.Error(
+				"failed to cancel deposit",
+				"error", err,
+				"user_id", userID,
+				"transaction_id", txID,
+			)
+			return common.ProblemDetailsJSON(c, "Failed to cancel deposit", err)
+		}
+		return common.SuccessResponseJSON(
+			c,
+			fiber.StatusOK,
+			"Deposit canceled",
+			toDepositDTO(c.UserContext(), nil, tx),
+		)
+	}
+}
+
+// toDepositDTO maps tx to a DepositDTO, with its open checkout from
+// checkoutSvc if any. A failed checkout lookup only omits the checkout.
+func toDepositDTO(
+	ctx context.Context,
+	checkoutSvc *checkout.Service,
+	tx *dto.TransactionRead,
+) *DepositDTO {
+	out := &DepositDTO{
+		TransactionID: tx.ID.String(),
+		AccountID:     tx.AccountID.String(),
+		Amount:        exactMoney(tx.AmountMoney, tx.Amount, tx.Currency),
+		Status:        tx.Status,
+		Description:   tx.Description,
+		CreatedAt:     tx.CreatedAt,
+	}
+	if checkoutSvc == nil {
+		return out
+	}
+	session, err := checkoutSvc.GetActiveSessionByTransactionID(ctx, tx.ID)
+	if err != nil {
+		log.Error("failed to get checkout session", "error", err, "transaction_id", tx.ID)
+		return out
+	}
+	if session != nil {
+		out.CheckoutURL = session.CheckoutURL
+		if !session.ExpiresAt.IsZero() {
+			out.ExpiresAt = &session.ExpiresAt
+		}
+	}
+	return out
+}
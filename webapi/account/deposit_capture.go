@@ -0,0 +1,71 @@
+package account
+
+import (
+	accountsvc "github.com/amirasaad/fintech/pkg/service/account"
+	authsvc "github.com/amirasaad/fintech/pkg/service/auth"
+	"github.com/amirasaad/fintech/webapi/common"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/log"
+	"github.com/google/uuid"
+)
+
+// CaptureDeposit returns a Fiber handler that captures a deposit authorized
+// with manual capture.
+// @Summary Capture a deposit (admin only)
+// @Description Captures part or all of a deposit made with capture_method manual,
+// once its checkout has been paid. The rest of the authorization is released.
+// The deposit is completed, and the account credited with the captured amount,
+// once the payment provider confirms the capture.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Deposit transaction ID"
+// @Param request body CaptureRequest false "Amount to capture; the full authorization if omitted"
+// @Success 202 {object} common.Response{data=DepositDTO} "Capture requested"
+// @Failure 400 {object} common.ProblemDetails "Invalid request"
+// @Failure 401 {object} common.ProblemDetails "Unauthorized"
+// @Failure 403 {object} common.ProblemDetails "Not an admin"
+// @Failure 404 {object} common.ProblemDetails "Deposit not found"
+// @Failure 409 {object} common.ProblemDetails "Deposit not awaiting capture"
+// @Failure 422 {object} common.ProblemDetails "Amount exceeds the authorization"
+// @Failure 501 {object} common.ProblemDetails "Capturing deposits not supported"
+// @Failure 500 {object} common.ProblemDetails "Internal server error"
+// @Router /admin/deposits/{id}/capture [post]
+// @Security Bearer
+func CaptureDeposit(
+	accountSvc *accountsvc.Service,
+	authSvc *authsvc.Service,
+) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		adminID, txID, err := parseDecision(c, authSvc, "deposit")
+		if txID == uuid.Nil {
+			return err // error response already written
+		}
+		var input CaptureRequest
+		if len(c.Body()) > 0 {
+			in, err := common.BindAndValidate[CaptureRequest](c)
+			if in == nil {
+				return err // error response already written
+			}
+			input = *in
+		}
+		tx, err := accountSvc.CaptureDeposit(common.DetachedContext(c), txID, input.Amount)
+		if err != nil {
+			log.Error(
+				"failed to capture deposit",
+				"error", err,
+				"admin_id", adminID,
+				"transaction_id", txID,
+			)
+			return common.ProblemDetailsJSON(c, "Failed to capture deposit", err)
+		}
+		log.Info("capture requested", "admin_id", adminID, "transaction_id", txID)
+		return common.SuccessResponseJSON(
+			c,
+			fiber.StatusAccepted,
+			"Capture requested",
+			toDepositDTO(c.UserContext(), nil, tx),
+		)
+	}
+}
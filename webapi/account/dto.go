@@ -1,8 +1,13 @@
 package account
 
 import (
+	"math"
+	"time"
+
 	"github.com/amirasaad/fintech/pkg/dto"
+	"github.com/amirasaad/fintech/pkg/money"
 	"github.com/amirasaad/fintech/pkg/provider/exchange"
+	accountsvc "github.com/amirasaad/fintech/pkg/service/account"
 )
 
 //revive:disable
@@ -10,6 +15,8 @@ import (
 // CreateAccountRequest represents the request body for creating a new account.
 type CreateAccountRequest struct {
 	Currency string `json:"currency" validate:"omitempty,len=3,uppercase,alpha"`
+	// Type is the kind of account: checking (the default), savings or wallet.
+	Type string `json:"type" validate:"omitempty,oneof=checking savings wallet"`
 }
 
 // DepositRequest represents the request body for depositing funds into an account.
@@ -17,20 +24,102 @@ type DepositRequest struct {
 	Amount      float64 `json:"amount" xml:"amount" form:"amount" validate:"required,gt=0"`
 	Currency    string  `json:"currency" validate:"omitempty,len=3,uppercase"`
 	MoneySource string  `json:"money_source" validate:"required,min=2,max=64"`
+	// SuccessURL and CancelURL override the checkout redirects. Their hosts
+	// must be in the configured allowlist.
+	SuccessURL string `json:"success_url,omitempty" validate:"omitempty,url"`
+	CancelURL  string `json:"cancel_url,omitempty" validate:"omitempty,url"`
+	// Description is an optional note shown in transaction listings.
+	Description string `json:"description,omitempty" validate:"omitempty,max=140"`
+	// StatementDescriptor overrides the text on the card statement, at most
+	// 22 Latin characters including a letter and none of < > \ ' " *.
+	StatementDescriptor string `json:"statement_descriptor,omitempty" validate:"omitempty,max=22"`
+	// CaptureMethod is "manual" to only authorize the payment and capture it
+	// later through the admin API; it defaults to "automatic".
+	CaptureMethod string `json:"capture_method,omitempty" validate:"omitempty,oneof=automatic manual"`
+}
+
+// UpdateAccountRequest represents the request body for updating an account's
+// settings. AutoConvert is whether deposits in another currency are converted
+// to the account currency; when false they are rejected.
+type UpdateAccountRequest struct {
+	AutoConvert *bool `json:"auto_convert" validate:"required"`
+}
+
+// SetOverdraftLimitRequest represents the request body for setting an account's
+// overdraft limit. Zero removes the overdraft.
+type SetOverdraftLimitRequest struct {
+	Limit *float64 `json:"limit" validate:"required,gte=0"`
+}
+
+// SetFeePolicyRequest represents the request body for setting who bears the
+// provider fee on deposits to an account. An empty policy makes the account
+// follow the global policy.
+type SetFeePolicyRequest struct {
+	Policy *string `json:"policy" validate:"required,oneof=absorb pass_through ''"`
+}
+
+// ImportTransactionsRequest represents the request body for importing
+// historical transactions into an account, oldest first.
+type ImportTransactionsRequest struct {
+	Transactions []ImportTransactionRow `json:"transactions" validate:"required,min=1,max=1000,dive"`
+}
+
+// ImportTransactionRow is one historical transaction to import. reference
+// identifies it in the source system; rows already imported are skipped.
+type ImportTransactionRow struct {
+	Reference string    `json:"reference" validate:"required,max=64"`
+	Type      string    `json:"type" validate:"required,oneof=deposit withdraw transfer_in transfer_out"`
+	Amount    float64   `json:"amount" validate:"required,gt=0"`
+	Currency  string    `json:"currency" validate:"required,len=3,uppercase,alpha"`
+	Timestamp time.Time `json:"timestamp" validate:"required"`
+	Memo      string    `json:"memo,omitempty" validate:"omitempty,max=140"`
+}
+
+// ImportTransactionsResponse is the API response of a transaction import.
+type ImportTransactionsResponse struct {
+	Imported []*TransactionDTO `json:"imported"`
+	// Skipped lists the references that had already been imported.
+	Skipped []string `json:"skipped"`
+	Balance float64  `json:"balance"`
 }
 
 // ExternalTarget represents the destination for an external withdrawal, such as a bank account or wallet.
 type ExternalTarget struct {
 	BankAccountNumber     string `json:"bank_account_number,omitempty" validate:"omitempty,min=6,max=34"`
 	RoutingNumber         string `json:"routing_number,omitempty" validate:"omitempty,min=6,max=12"`
+	BIC                   string `json:"bic,omitempty" validate:"omitempty,max=11"`
 	ExternalWalletAddress string `json:"external_wallet_address,omitempty" validate:"omitempty,min=6,max=128"`
+	Network               string `json:"network,omitempty" validate:"omitempty,max=32"`
 }
 
 // WithdrawRequest represents the request body for withdrawing funds from an account.
 type WithdrawRequest struct {
 	Amount         float64         `json:"amount" xml:"amount" form:"amount" validate:"required,gt=0"`
 	Currency       string          `json:"currency" validate:"omitempty,len=3,uppercase"`
-	ExternalTarget *ExternalTarget `json:"external_target" validate:"required"`
+	ExternalTarget *ExternalTarget `json:"external_target" validate:"required_without=DestinationID,excluded_with=DestinationID"`
+	// DestinationID withdraws to a saved payout destination instead of external_target.
+	DestinationID string `json:"destination_id,omitempty" validate:"omitempty,uuid"`
+	// Description is an optional note, also sent to the bank in payout exports.
+	Description string `json:"description,omitempty" validate:"omitempty,max=140"`
+}
+
+// CreatePayoutDestinationRequest represents the request body for saving a payout destination.
+type CreatePayoutDestinationRequest struct {
+	Label string `json:"label,omitempty" validate:"omitempty,max=64"`
+	ExternalTarget
+}
+
+// RefundRequest represents the request body for refunding a deposit. Amount is
+// in the deposit's currency and may be less than the deposit.
+type RefundRequest struct {
+	Amount float64 `json:"amount" validate:"required,gt=0"`
+}
+
+// CaptureRequest represents the request body for capturing a deposit
+// authorized with manual capture. Amount is in the deposit's currency; zero or
+// omitted captures the full authorized amount.
+type CaptureRequest struct {
+	Amount float64 `json:"amount" validate:"omitempty,gt=0"`
 }
 
 // TransferRequest represents the request body for transferring funds between accounts.
@@ -38,18 +127,163 @@ type TransferRequest struct {
 	Amount               float64 `json:"amount" validate:"required,gt=0"`
 	Currency             string  `json:"currency" validate:"omitempty,len=3,uppercase,alpha"`
 	DestinationAccountID string  `json:"destination_account_id" validate:"required,uuid4"`
+	// ExecuteAt schedules the transfer for a future time (RFC 3339). Omit to transfer immediately.
+	ExecuteAt *time.Time `json:"execute_at,omitempty"`
+	// Description is an optional note recorded on both the sender's and the
+	// recipient's transaction.
+	Description string `json:"description,omitempty" validate:"omitempty,max=140"`
+	// RequireApproval debits the source account now but only credits the
+	// destination once an admin approves the transfer. Cannot be combined
+	// with ExecuteAt.
+	RequireApproval bool `json:"require_approval,omitempty"`
+}
+
+// RejectTransferRequest represents the request body for rejecting a transfer
+// awaiting approval.
+type RejectTransferRequest struct {
+	Reason string `json:"reason,omitempty" validate:"omitempty,max=255"`
+}
+
+// PendingTransferDTO is the API response representation of a transfer
+// awaiting approval, or one that has been approved or rejected.
+type PendingTransferDTO struct {
+	ID                   string  `json:"id"`
+	UserID               string  `json:"user_id"`
+	AccountID            string  `json:"account_id"`
+	DestinationAccountID string  `json:"destination_account_id"`
+	TransactionID        string  `json:"transaction_id"`
+	Amount               float64 `json:"amount"`
+	Currency             string  `json:"currency"`
+	Description          string  `json:"description,omitempty"`
+	Status               string  `json:"status"`
+	DecidedBy            string  `json:"decided_by,omitempty"`
+	DecidedAt            string  `json:"decided_at,omitempty"`
+	Reason               string  `json:"reason,omitempty"`
+	CreatedAt            string  `json:"created_at"`
+}
+
+// RejectWithdrawalRequest represents the request body for rejecting a
+// withdrawal awaiting approval.
+type RejectWithdrawalRequest struct {
+	Reason string `json:"reason,omitempty" validate:"omitempty,max=255"`
+}
+
+// PendingWithdrawalDTO is the API response representation of a withdrawal
+// awaiting approval, or one that has been approved or rejected. The payout
+// target is masked to its last four characters.
+type PendingWithdrawalDTO struct {
+	ID                string   `json:"id"`
+	UserID            string   `json:"user_id"`
+	AccountID         string   `json:"account_id"`
+	TransactionID     string   `json:"transaction_id"`
+	Amount            float64  `json:"amount"`
+	Currency          string   `json:"currency"`
+	HoldAmount        float64  `json:"hold_amount"`
+	HoldCurrency      string   `json:"hold_currency"`
+	Description       string   `json:"description,omitempty"`
+	TargetLast4       string   `json:"target_last4,omitempty"`
+	Network           string   `json:"network,omitempty"`
+	RequiredApprovals int      `json:"required_approvals"`
+	Approvals         []string `json:"approvals"`
+	Status            string   `json:"status"`
+	DecidedBy         string   `json:"decided_by,omitempty"`
+	DecidedAt         string   `json:"decided_at,omitempty"`
+	Reason            string   `json:"reason,omitempty"`
+	CreatedAt         string   `json:"created_at"`
+}
+
+// ScheduledTransferDTO is the API response representation of a scheduled transfer.
+type ScheduledTransferDTO struct {
+	ID                   string  `json:"id"`
+	AccountID            string  `json:"account_id"`
+	DestinationAccountID string  `json:"destination_account_id"`
+	Amount               float64 `json:"amount"`
+	Currency             string  `json:"currency"`
+	Description          string  `json:"description,omitempty"`
+	Status               string  `json:"status"`
+	ExecuteAt            string  `json:"execute_at"`
+	ExecutedAt           string  `json:"executed_at,omitempty"`
+	FailureReason        string  `json:"failure_reason,omitempty"`
+	CreatedAt            string  `json:"created_at"`
+}
+
+// PayoutDestinationDTO is the API response representation of a saved payout
+// destination. Only the last four characters of the account number or wallet
+// address are ever exposed.
+type PayoutDestinationDTO struct {
+	ID        string `json:"id"`
+	Type      string `json:"type"`
+	Label     string `json:"label,omitempty"`
+	Last4     string `json:"last4"`
+	Network   string `json:"network,omitempty"`
+	CreatedAt string `json:"created_at"`
 }
 
 // TransactionDTO is the API response representation of a transaction.
+// amount and balance are deprecated floats kept for existing clients;
+// amount_money and balance_money are exact, in the smallest currency unit.
 type TransactionDTO struct {
 	ID          string  `json:"id"`
 	UserID      string  `json:"user_id"`
 	AccountID   string  `json:"account_id"`
-	Amount      float64 `json:"amount"`
-	Balance     float64 `json:"balance"`
+	Amount      float64 `json:"amount"`  // Deprecated: use AmountMoney
+	Balance     float64 `json:"balance"` // Deprecated: use BalanceMoney
 	CreatedAt   string  `json:"created_at"`
 	Currency    string  `json:"currency"`
 	MoneySource string  `json:"money_source"`
+	Description string  `json:"description,omitempty"`
+	// ExternalReference identifies an imported transaction in the system it
+	// was imported from; omitted otherwise.
+	ExternalReference string `json:"external_reference,omitempty"`
+	// PaymentMethod is how a deposit was funded; omitted when unknown.
+	PaymentMethod *PaymentMethodDTO `json:"payment_method,omitempty"`
+	// RefundedTransactionID is the deposit a refund refunds; omitted otherwise.
+	RefundedTransactionID string `json:"refunded_transaction_id,omitempty"`
+	// Fee is what was deducted from the account and PlatformFee the
+	// provider fee the platform absorbed instead, under FeePolicy (absorb
+	// or pass_through); omitted when zero or, for FeePolicy, when no
+	// provider fee was charged.
+	Fee          float64      `json:"fee,omitempty"`
+	PlatformFee  float64      `json:"platform_fee,omitempty"`
+	FeePolicy    string       `json:"fee_policy,omitempty"`
+	AmountMoney  *money.Money `json:"amount_money,omitempty"`
+	BalanceMoney *money.Money `json:"balance_money,omitempty"`
+}
+
+// DepositDTO is a deposit with its status and, while it can still be paid,
+// the URL of its checkout. checkout_url and expires_at are omitted if it has
+// no open checkout.
+type DepositDTO struct {
+	TransactionID string       `json:"transaction_id"`
+	AccountID     string       `json:"account_id"`
+	Amount        *money.Money `json:"amount"`
+	Status        string       `json:"status"`
+	Description   string       `json:"description,omitempty"`
+	CheckoutURL   string       `json:"checkout_url,omitempty"`
+	CreatedAt     time.Time    `json:"created_at"`
+	ExpiresAt     *time.Time   `json:"expires_at,omitempty"`
+}
+
+// PaymentMethodDTO is the API representation of the payment method that funded
+// a deposit. brand and funding are only present for cards.
+type PaymentMethodDTO struct {
+	Type    string `json:"type"`
+	Brand   string `json:"brand,omitempty"`
+	Last4   string `json:"last4,omitempty"`
+	Funding string `json:"funding,omitempty"`
+}
+
+// toPaymentMethodDTO maps a dto.PaymentMethod to a PaymentMethodDTO.
+func toPaymentMethodDTO(pm *dto.PaymentMethod) *PaymentMethodDTO {
+	if pm == nil {
+		return nil
+	}
+	return &PaymentMethodDTO{
+		Type:    pm.Type,
+		Brand:   pm.Brand,
+		Last4:   pm.Last4,
+		Funding: pm.Funding,
+	}
 }
 
 // ConversionInfoDTO holds conversion details for API responses.
@@ -59,6 +293,8 @@ type ConversionInfoDTO struct {
 	ConvertedAmount   float64 `json:"converted_amount"`
 	ConvertedCurrency string  `json:"converted_currency"`
 	ConversionRate    float64 `json:"conversion_rate"`
+	// RoundingMode is how the converted amount was rounded, e.g. half_even.
+	RoundingMode string `json:"rounding_mode,omitempty"`
 }
 
 // TransferResponseDTO is the API response for a transfer operation, containing both transactions and a single conversion_info field (like deposit/withdraw).
@@ -71,24 +307,110 @@ type TransferResponseDTO struct {
 // ListUserAccountsResponse is the response payload for listing user accounts.
 type ListUserAccountsResponse []*dto.AccountRead
 
+// BalanceResponse is the response payload for an account balance. Amount and
+// Symbol are for display; Money is the exact value for clients that compute
+// with it. Balance is the same value as a float, kept for existing clients.
+type BalanceResponse struct {
+	Amount   string       `json:"amount"`           // Formatted to the currency's decimals
+	Currency string       `json:"currency"`         // ISO 4217 code
+	Symbol   string       `json:"symbol,omitempty"` // Empty if the currency is not registered
+	Balance  float64      `json:"balance"`          // Deprecated: use Money
+	Money    *money.Money `json:"money,omitempty"`  // Exact balance in the smallest unit
+	// Display is the balance converted to the currency of the display query
+	// parameter, if requested.
+	Display *DisplayBalanceDTO `json:"display,omitempty"`
+	// Interest is the interest a savings account earns and has accrued.
+	Interest *InterestDTO `json:"interest,omitempty"`
+}
+
+// InterestDTO is the interest an account earns and has accrued. APY is the
+// annual percentage yield, e.g. 0.025 for 2.5%; AccruedOn is the last day
+// (UTC, YYYY-MM-DD) interest was accrued for.
+type InterestDTO struct {
+	APY       float64      `json:"apy"`
+	Accrued   *money.Money `json:"accrued"`
+	AccruedOn string       `json:"accrued_on,omitempty"`
+}
+
+// DisplayBalanceDTO is an account balance converted to a display currency.
+// If the conversion is unavailable, ConversionAvailable is false and the
+// amount fields are empty, leaving clients with the native balance.
+type DisplayBalanceDTO struct {
+	Currency            string       `json:"currency"`
+	ConversionAvailable bool         `json:"conversion_available"`
+	Amount              string       `json:"amount,omitempty"` // Formatted to the currency's decimals
+	Money               *money.Money `json:"money,omitempty"`
+	Rate                float64      `json:"rate,omitempty"`
+}
+
 // AggregatedBalanceResponse is the response payload for aggregated balances.
 type AggregatedBalanceResponse struct {
 	Totals map[string]float64 `json:"totals"`
 }
 
+// AccountsByCurrencyResponse is the response payload for the admin listing of
+// accounts in one currency. Totals cover every matching account, not just the page.
+type AccountsByCurrencyResponse struct {
+	Accounts []*dto.AccountRead `json:"accounts"`
+	Totals   AccountTotalsDTO   `json:"totals"`
+	Page     int                `json:"page"`
+	PageSize int                `json:"page_size"`
+}
+
+// PayoutDTO is the API response representation of one of the user's
+// withdrawals. The destination is masked and routing details are omitted.
+type PayoutDTO struct {
+	ID               string  `json:"id"`
+	AccountID        string  `json:"account_id"`
+	Amount           float64 `json:"amount"`
+	Currency         string  `json:"currency"`
+	Fee              float64 `json:"fee"`
+	Status           string  `json:"status"`
+	Destination      string  `json:"destination,omitempty"`
+	StripeTransferID string  `json:"stripe_transfer_id,omitempty"`
+	EstimatedArrival string  `json:"estimated_arrival,omitempty"`
+	Description      string  `json:"description,omitempty"`
+	CreatedAt        string  `json:"created_at"`
+}
+
+// PayoutsResponse is the response payload of the user's payout history.
+type PayoutsResponse struct {
+	Payouts  []*PayoutDTO `json:"payouts"`
+	Page     int          `json:"page"`
+	PageSize int          `json:"page_size"`
+}
+
+// AccountTotalsDTO aggregates the accounts matched by an admin listing.
+type AccountTotalsDTO struct {
+	Currency string  `json:"currency"`
+	Count    int64   `json:"count"`
+	Balance  float64 `json:"balance"`
+}
+
 // ToTransactionDTO maps a dto.TransactionRead to a TransactionDTO.
 func ToTransactionDTO(tx *dto.TransactionRead) *TransactionDTO {
 	if tx == nil {
 		return nil
 	}
 	dto := &TransactionDTO{
-		ID:        tx.ID.String(),
-		UserID:    tx.UserID.String(),
-		AccountID: tx.AccountID.String(),
-		Amount:    tx.Amount,
-		Currency:  tx.Currency,
-		Balance:   tx.Balance,
-		CreatedAt: tx.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		ID:          tx.ID.String(),
+		UserID:      tx.UserID.String(),
+		AccountID:   tx.AccountID.String(),
+		Amount:      tx.Amount,
+		Currency:    tx.Currency,
+		Balance:     tx.Balance,
+		Description: tx.Description,
+		CreatedAt:   tx.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+	dto.AmountMoney = tx.AmountMoney
+	dto.BalanceMoney = tx.BalanceMoney
+	dto.ExternalReference = tx.ExternalReference
+	dto.PaymentMethod = toPaymentMethodDTO(tx.PaymentMethod)
+	dto.Fee = tx.Fee
+	dto.PlatformFee = tx.PlatformFee
+	dto.FeePolicy = tx.FeePolicy
+	if tx.RefundedTransactionID != nil {
+		dto.RefundedTransactionID = tx.RefundedTransactionID.String()
 	}
 
 	return dto
@@ -105,6 +427,7 @@ func ToConversionInfoDTO(convInfo *exchange.RateInfo) *ConversionInfoDTO {
 		ConvertedAmount:   0, // Not directly available from RateInfo
 		ConvertedCurrency: convInfo.ToCurrency,
 		ConversionRate:    convInfo.Rate,
+		RoundingMode:      convInfo.RoundingMode,
 	}
 }
 
@@ -118,3 +441,136 @@ func ToTransferResponseDTO(txOut, txIn *dto.TransactionRead, convInfo *exchange.
 }
 
 //revive:enable
+
+// ToScheduledTransferDTO maps a dto.ScheduledTransferRead to a ScheduledTransferDTO.
+func ToScheduledTransferDTO(st *dto.ScheduledTransferRead) *ScheduledTransferDTO {
+	if st == nil {
+		return nil
+	}
+	out := &ScheduledTransferDTO{
+		ID:                   st.ID.String(),
+		AccountID:            st.FromAccountID.String(),
+		DestinationAccountID: st.ToAccountID.String(),
+		Amount:               st.Amount,
+		Currency:             st.Currency,
+		Description:          st.Description,
+		Status:               st.Status,
+		ExecuteAt:            st.ExecuteAt.Format(time.RFC3339),
+		FailureReason:        st.FailureReason,
+		CreatedAt:            st.CreatedAt.Format(time.RFC3339),
+	}
+	if st.ExecutedAt != nil {
+		out.ExecutedAt = st.ExecutedAt.Format(time.RFC3339)
+	}
+	return out
+}
+
+// ToPendingTransferDTO maps a dto.PendingTransferRead to a PendingTransferDTO.
+func ToPendingTransferDTO(pt *dto.PendingTransferRead) *PendingTransferDTO {
+	if pt == nil {
+		return nil
+	}
+	out := &PendingTransferDTO{
+		ID:                   pt.ID.String(),
+		UserID:               pt.UserID.String(),
+		AccountID:            pt.FromAccountID.String(),
+		DestinationAccountID: pt.ToAccountID.String(),
+		TransactionID:        pt.TransactionID.String(),
+		Amount:               pt.Amount,
+		Currency:             pt.Currency,
+		Description:          pt.Description,
+		Status:               pt.Status,
+		Reason:               pt.Reason,
+		CreatedAt:            pt.CreatedAt.Format(time.RFC3339),
+	}
+	if pt.DecidedBy != nil {
+		out.DecidedBy = pt.DecidedBy.String()
+	}
+	if pt.DecidedAt != nil {
+		out.DecidedAt = pt.DecidedAt.Format(time.RFC3339)
+	}
+	return out
+}
+
+// ToPayoutDTO maps a withdrawal dto.TransactionRead to a PayoutDTO. The
+// amount is reported as a positive number.
+func ToPayoutDTO(tx *dto.TransactionRead) *PayoutDTO {
+	if tx == nil {
+		return nil
+	}
+	out := &PayoutDTO{
+		ID:          tx.ID.String(),
+		AccountID:   tx.AccountID.String(),
+		Amount:      math.Abs(tx.Amount),
+		Currency:    tx.Currency,
+		Fee:         tx.Fee,
+		Status:      accountsvc.PayoutStatus(tx.Status),
+		Destination: tx.ExternalTargetMasked,
+		Description: tx.Description,
+		CreatedAt:   tx.CreatedAt.Format(time.RFC3339),
+	}
+	if out.Destination == "" && tx.ExternalBankAccount != nil {
+		// Withdrawals recorded before the masked target was stored.
+		number := tx.ExternalBankAccount.Number
+		out.Destination = "****" + number[max(len(number)-4, 0):]
+	}
+	if tx.PaymentID != nil {
+		out.StripeTransferID = *tx.PaymentID
+	}
+	if tx.PayoutEstimatedArrival != nil {
+		out.EstimatedArrival = tx.PayoutEstimatedArrival.Format(time.RFC3339)
+	}
+	return out
+}
+
+// ToPendingWithdrawalDTO maps a dto.PendingWithdrawalRead to a
+// PendingWithdrawalDTO.
+func ToPendingWithdrawalDTO(pw *dto.PendingWithdrawalRead) *PendingWithdrawalDTO {
+	if pw == nil {
+		return nil
+	}
+	out := &PendingWithdrawalDTO{
+		ID:                pw.ID.String(),
+		UserID:            pw.UserID.String(),
+		AccountID:         pw.AccountID.String(),
+		TransactionID:     pw.TransactionID.String(),
+		Amount:            pw.Amount,
+		Currency:          pw.Currency,
+		HoldAmount:        pw.HoldAmount,
+		HoldCurrency:      pw.HoldCurrency,
+		Description:       pw.Description,
+		TargetLast4:       pw.Last4(),
+		Network:           pw.Network,
+		RequiredApprovals: pw.RequiredApprovals,
+		Approvals:         make([]string, 0, len(pw.Approvals)),
+		Status:            pw.Status,
+		Reason:            pw.Reason,
+		CreatedAt:         pw.CreatedAt.Format(time.RFC3339),
+	}
+	for _, id := range pw.Approvals {
+		out.Approvals = append(out.Approvals, id.String())
+	}
+	if pw.DecidedBy != nil {
+		out.DecidedBy = pw.DecidedBy.String()
+	}
+	if pw.DecidedAt != nil {
+		out.DecidedAt = pw.DecidedAt.Format(time.RFC3339)
+	}
+	return out
+}
+
+// ToPayoutDestinationDTO maps a dto.PayoutDestinationRead to a masked
+// PayoutDestinationDTO.
+func ToPayoutDestinationDTO(pd *dto.PayoutDestinationRead) *PayoutDestinationDTO {
+	if pd == nil {
+		return nil
+	}
+	return &PayoutDestinationDTO{
+		ID:        pd.ID.String(),
+		Type:      pd.Type(),
+		Label:     pd.Label,
+		Last4:     pd.Last4(),
+		Network:   pd.Network,
+		CreatedAt: pd.CreatedAt.Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,141 @@
+package account_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/amirasaad/fintech/pkg/dto"
+	"github.com/amirasaad/fintech/pkg/money"
+	"github.com/amirasaad/fintech/webapi/account"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToTransactionDTO_ExactAmounts(t *testing.T) {
+	t.Parallel()
+	amount, err := money.NewFromSmallestUnit(1999, money.USD)
+	require.NoError(t, err)
+	balance, err := money.NewFromSmallestUnit(123456789012345678, money.USD)
+	require.NoError(t, err)
+
+	out, err := json.Marshal(account.ToTransactionDTO(&dto.TransactionRead{
+		ID:           uuid.New(),
+		Amount:       amount.AmountFloat(),
+		Balance:      balance.AmountFloat(),
+		Currency:     "USD",
+		AmountMoney:  amount,
+		BalanceMoney: balance,
+	}))
+	require.NoError(t, err)
+
+	var got struct {
+		AmountMoney  *money.Money `json:"amount_money"`
+		BalanceMoney *money.Money `json:"balance_money"`
+	}
+	require.NoError(t, json.Unmarshal(out, &got))
+	require.NotNil(t, got.AmountMoney)
+	assert.True(t, amount.Equals(got.AmountMoney))
+	// Larger than float64 can hold exactly.
+	require.NotNil(t, got.BalanceMoney)
+	assert.Equal(t, money.Amount(123456789012345678), got.BalanceMoney.Amount())
+}
+
+func TestToTransactionV2DTO(t *testing.T) {
+	t.Parallel()
+	out, err := json.Marshal(account.ToTransactionV2DTO(&dto.TransactionRead{
+		ID:          uuid.New(),
+		AccountID:   uuid.New(),
+		Amount:      -12.34,
+		Balance:     87.66,
+		Fee:         0.5,
+		Currency:    "USD",
+		Status:      "completed",
+		MoneySource: "transfer",
+	}))
+	require.NoError(t, err)
+
+	var got map[string]any
+	require.NoError(t, json.Unmarshal(out, &got))
+	assert.Equal(t, map[string]any{"amount": float64(-1234), "currency": "USD"}, got["amount"])
+	assert.Equal(t, map[string]any{"amount": float64(8766), "currency": "USD"}, got["balance"])
+	assert.Equal(t, map[string]any{"amount": float64(50), "currency": "USD"}, got["fee"])
+	assert.Equal(t, "completed", got["status"])
+	assert.Equal(t, "Transfer out", got["label"])
+	assert.NotContains(t, got, "platform_fee")
+	assert.NotContains(t, got, "fee_policy")
+}
+
+func TestToTransactionV2DTO_AbsorbedFee(t *testing.T) {
+	t.Parallel()
+	out, err := json.Marshal(account.ToTransactionV2DTO(&dto.TransactionRead{
+		ID:          uuid.New(),
+		AccountID:   uuid.New(),
+		Amount:      100,
+		Balance:     100,
+		PlatformFee: 3.2,
+		FeePolicy:   "absorb",
+		Currency:    "USD",
+		Status:      "completed",
+		MoneySource: "Stripe",
+	}))
+	require.NoError(t, err)
+
+	var got map[string]any
+	require.NoError(t, json.Unmarshal(out, &got))
+	assert.NotContains(t, got, "fee")
+	assert.Equal(t, map[string]any{"amount": float64(320), "currency": "USD"}, got["platform_fee"])
+	assert.Equal(t, "absorb", got["fee_policy"])
+}
+
+func TestToAccountV2DTO(t *testing.T) {
+	t.Parallel()
+	balance, err := money.NewFromSmallestUnit(500, money.JPY)
+	require.NoError(t, err)
+	out, err := json.Marshal(account.ToAccountV2DTO(&dto.AccountRead{
+		ID:           uuid.New(),
+		UserID:       uuid.New(),
+		Balance:      500,
+		BalanceMoney: balance,
+		Currency:     "JPY",
+		Status:       "active",
+	}))
+	require.NoError(t, err)
+
+	var got map[string]any
+	require.NoError(t, json.Unmarshal(out, &got))
+	assert.Equal(t, map[string]any{"amount": float64(500), "currency": "JPY"}, got["balance"])
+	assert.Equal(t, map[string]any{"amount": float64(0), "currency": "JPY"}, got["overdraft_limit"])
+	assert.Equal(t, "JPY account", got["label"])
+	assert.Equal(t, "active", got["status"])
+}
+
+func TestToPayoutDTO(t *testing.T) {
+	t.Parallel()
+	transferID := "tr_123"
+	arrival := time.Date(2026, 3, 4, 0, 0, 0, 0, time.UTC)
+	got := account.ToPayoutDTO(&dto.TransactionRead{
+		ID:                     uuid.New(),
+		Amount:                 -99.5,
+		Currency:               "USD",
+		Fee:                    1.25,
+		Status:                 "processed",
+		PaymentID:              &transferID,
+		PayoutEstimatedArrival: &arrival,
+		ExternalBankAccount: &dto.BankAccount{
+			Number:        "000123456789",
+			RoutingNumber: "011000015",
+		},
+	})
+	assert.InDelta(t, 99.5, got.Amount, 1e-9)
+	assert.Equal(t, "pending", got.Status)
+	assert.Equal(t, "tr_123", got.StripeTransferID)
+	assert.Equal(t, "2026-03-04T00:00:00Z", got.EstimatedArrival)
+	// Only the last four digits of the account, and no routing number.
+	assert.Equal(t, "****6789", got.Destination)
+	out, err := json.Marshal(got)
+	require.NoError(t, err)
+	assert.NotContains(t, string(out), "011000015")
+	assert.NotContains(t, string(out), "000123456789")
+}
@@ -0,0 +1,132 @@
+package account_test
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/amirasaad/fintech/webapi/account"
+	"github.com/amirasaad/fintech/webapi/common"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// bindRoute registers a route that only binds and validates T.
+func bindRoute[T any](app *fiber.App, path string) {
+	app.Post(path, func(c *fiber.Ctx) error {
+		input, err := common.BindAndValidate[T](c)
+		if input == nil {
+			return err
+		}
+		return c.SendStatus(fiber.StatusNoContent)
+	})
+}
+
+func TestRequestValidationErrors(t *testing.T) {
+	app := fiber.New()
+	bindRoute[account.CreateAccountRequest](app, "/account")
+	bindRoute[account.DepositRequest](app, "/deposit")
+	bindRoute[account.WithdrawRequest](app, "/withdraw")
+	bindRoute[account.TransferRequest](app, "/transfer")
+	bindRoute[account.UpdateAccountRequest](app, "/account/settings")
+
+	tests := []struct {
+		name string
+		path string
+		body string
+		want []common.FieldError
+	}{
+		{
+			name: "create account",
+			path: "/account",
+			body: `{"currency":"usd"}`,
+			want: []common.FieldError{
+				{Field: "currency", Rule: "uppercase", Message: "must be uppercase"},
+			},
+		},
+		{
+			name: "deposit",
+			path: "/deposit",
+			body: `{"amount":-1,"money_source":"x"}`,
+			want: []common.FieldError{
+				{Field: "amount", Rule: "gt", Param: "0", Message: "must be greater than 0"},
+				{
+					Field: "money_source", Rule: "min", Param: "2",
+					Message: "must be at least 2 characters long",
+				},
+			},
+		},
+		{
+			name: "withdraw nested target",
+			path: "/withdraw",
+			body: `{"amount":5,"external_target":{"routing_number":"12"}}`,
+			want: []common.FieldError{
+				{
+					Field: "external_target.routing_number", Rule: "min", Param: "6",
+					Message: "must be at least 6 characters long",
+				},
+			},
+		},
+		{
+			name: "withdraw without target",
+			path: "/withdraw",
+			body: `{"amount":5}`,
+			want: []common.FieldError{
+				{
+					Field: "external_target", Rule: "required_without",
+					Param: "DestinationID", Message: "is required",
+				},
+			},
+		},
+		{
+			name: "transfer",
+			path: "/transfer",
+			body: `{"amount":5,"destination_account_id":"nope"}`,
+			want: []common.FieldError{
+				{
+					Field: "destination_account_id", Rule: "uuid4",
+					Message: "must be a valid UUID",
+				},
+			},
+		},
+		{
+			name: "update account without settings",
+			path: "/account/settings",
+			body: `{}`,
+			want: []common.FieldError{
+				{Field: "auto_convert", Rule: "required", Message: "is required"},
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(fiber.MethodPost, tc.path, strings.NewReader(tc.body))
+			req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			defer resp.Body.Close() //nolint:errcheck
+			assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+
+			var pd struct {
+				Title  string              `json:"title"`
+				Errors []common.FieldError `json:"errors"`
+			}
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&pd))
+			assert.Equal(t, "Validation failed", pd.Title)
+			assert.Equal(t, tc.want, pd.Errors)
+		})
+	}
+
+	t.Run("valid request passes", func(t *testing.T) {
+		req := httptest.NewRequest(
+			fiber.MethodPost, "/account", strings.NewReader(`{"currency":"EUR"}`),
+		)
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		defer resp.Body.Close() //nolint:errcheck
+		assert.Equal(t, fiber.StatusNoContent, resp.StatusCode)
+	})
+}
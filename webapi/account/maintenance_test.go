@@ -0,0 +1,48 @@
+package account_test
+
+import (
+	"log/slog"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/amirasaad/fintech/pkg/config"
+	"github.com/amirasaad/fintech/pkg/domain/user"
+	"github.com/amirasaad/fintech/pkg/service/maintenance"
+	"github.com/amirasaad/fintech/webapi/account"
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdminMoneyRoutesRejectedDuringMaintenance(t *testing.T) {
+	cfg := &config.App{Auth: &config.Auth{Jwt: &config.Jwt{Secret: "secret"}}}
+	app := fiber.New()
+	account.Routes(
+		app, nil, nil, nil, nil, nil, nil,
+		maintenance.New(maintenance.NewMemoryStore(), true, slog.Default()),
+		cfg,
+	)
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"user_id": uuid.NewString(),
+		"role":    user.RoleAdmin,
+		"exp":     time.Now().Add(time.Hour).Unix(),
+	}).SignedString([]byte(cfg.Auth.Jwt.Secret))
+	require.NoError(t, err)
+
+	for _, path := range []string{
+		"/admin/accounts/" + uuid.NewString() + "/transactions/import",
+		"/admin/transactions/" + uuid.NewString() + "/reprocess",
+	} {
+		t.Run(path, func(t *testing.T) {
+			req := httptest.NewRequest(fiber.MethodPost, path, nil)
+			req.Header.Set(fiber.HeaderAuthorization, "Bearer "+token)
+			resp, err := app.Test(req, -1)
+			require.NoError(t, err)
+			defer resp.Body.Close() //nolint:errcheck
+			assert.Equal(t, fiber.StatusServiceUnavailable, resp.StatusCode)
+		})
+	}
+}
@@ -0,0 +1,190 @@
+package account
+
+import (
+	"errors"
+
+	"github.com/amirasaad/fintech/pkg/commands"
+	accountsvc "github.com/amirasaad/fintech/pkg/service/account"
+	authsvc "github.com/amirasaad/fintech/pkg/service/auth"
+	"github.com/amirasaad/fintech/pkg/validation"
+	"github.com/amirasaad/fintech/webapi/common"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/log"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// ListPayoutDestinations returns a Fiber handler that lists the authenticated
+// user's saved payout destinations.
+// @Summary List payout destinations
+// @Description Lists the user's saved bank accounts and wallets. Account numbers
+// and wallet addresses are masked to their last four characters.
+// @Tags accounts
+// @Accept json
+// @Produce json
+// @Success 200 {object} common.Response{data=[]PayoutDestinationDTO} "Payout destinations"
+// @Failure 401 {object} common.ProblemDetails "Unauthorized"
+// @Failure 500 {object} common.ProblemDetails "Internal server error"
+// @Router /payout-destinations [get]
+// @Router /stripe/destinations [get]
+// @Security Bearer
+func ListPayoutDestinations(
+	accountSvc *accountsvc.Service,
+	authSvc *authsvc.Service,
+) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		token, ok := c.Locals("user").(*jwt.Token)
+		if !ok {
+			return common.ProblemDetailsJSON(c, "Unauthorized", nil, "missing user context")
+		}
+		userID, err := authSvc.GetCurrentUserId(token)
+		if err != nil {
+			log.Error("failed to get user ID from token", "error", err)
+			return common.ProblemDetailsJSON(c, "Invalid user ID", err)
+		}
+		destinations, err := accountSvc.ListPayoutDestinations(c.UserContext(), userID)
+		if err != nil {
+			log.Error("failed to list payout destinations", "error", err, "user_id", userID)
+			return common.ProblemDetailsJSON(c, "Failed to list payout destinations", err)
+		}
+		dtos := make([]*PayoutDestinationDTO, 0, len(destinations))
+		for _, pd := range destinations {
+			dtos = append(dtos, ToPayoutDestinationDTO(pd))
+		}
+		return common.SuccessResponseJSON(
+			c,
+			fiber.StatusOK,
+			"Payout destinations fetched",
+			dtos,
+		)
+	}
+}
+
+// CreatePayoutDestination returns a Fiber handler that saves a payout
+// destination for the authenticated user.
+// @Summary Save a payout destination
+// @Description Saves a bank account or wallet so withdrawals can reference it by
+// destination_id. The target is validated like a withdrawal's external target.
+// @Tags accounts
+// @Accept json
+// @Produce json
+// @Param request body CreatePayoutDestinationRequest true "Payout destination"
+// @Success 201 {object} common.Response{data=PayoutDestinationDTO} "Payout destination saved"
+// @Failure 400 {object} common.ProblemDetails "Invalid request"
+// @Failure 401 {object} common.ProblemDetails "Unauthorized"
+// @Failure 500 {object} common.ProblemDetails "Internal server error"
+// @Router /payout-destinations [post]
+// @Security Bearer
+func CreatePayoutDestination(
+	accountSvc *accountsvc.Service,
+	authSvc *authsvc.Service,
+	targetValidator *validation.ExternalTargetValidator,
+) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		token, ok := c.Locals("user").(*jwt.Token)
+		if !ok {
+			return common.ProblemDetailsJSON(c, "Unauthorized", nil, "missing user context")
+		}
+		userID, err := authSvc.GetCurrentUserId(token)
+		if err != nil {
+			log.Error("failed to get user ID from token", "error", err)
+			return common.ProblemDetailsJSON(c, "Invalid user ID", err)
+		}
+		input, err := common.BindAndValidate[CreatePayoutDestinationRequest](c)
+		if input == nil {
+			return err // error response already written
+		}
+		target := commands.ExternalTarget{
+			BankAccountNumber:     input.BankAccountNumber,
+			RoutingNumber:         input.RoutingNumber,
+			BIC:                   input.BIC,
+			ExternalWalletAddress: input.ExternalWalletAddress,
+			Network:               input.Network,
+		}
+		// No currency is known yet, so wallets need an explicit or detectable network.
+		if err = targetValidator.Validate(&target, ""); err != nil {
+			var fieldErrs validation.Errors
+			if errors.As(err, &fieldErrs) {
+				return common.ProblemDetailsJSON(
+					c,
+					"Invalid payout destination",
+					nil,
+					"One or more external target fields are invalid",
+					fieldErrs.Fields(),
+					fiber.StatusBadRequest,
+				)
+			}
+			return common.ProblemDetailsJSON(c, "Invalid payout destination", err)
+		}
+
+		pd, err := accountSvc.AddPayoutDestination(c.UserContext(), userID, input.Label, target)
+		if err != nil {
+			log.Error("failed to save payout destination", "error", err, "user_id", userID)
+			return common.ProblemDetailsJSON(c, "Failed to save payout destination", err)
+		}
+		return common.SuccessResponseJSON(
+			c,
+			fiber.StatusCreated,
+			"Payout destination saved",
+			ToPayoutDestinationDTO(pd),
+		)
+	}
+}
+
+// DeletePayoutDestination returns a Fiber handler that removes a saved payout
+// destination.
+// @Summary Remove a payout destination
+// @Description Removes one of the user's saved payout destinations.
+// @Tags accounts
+// @Accept json
+// @Produce json
+// @Param id path string true "Payout destination ID"
+// @Success 200 {object} common.Response "Payout destination removed"
+// @Failure 400 {object} common.ProblemDetails "Invalid request"
+// @Failure 401 {object} common.ProblemDetails "Unauthorized"
+// @Failure 404 {object} common.ProblemDetails "Payout destination not found"
+// @Failure 500 {object} common.ProblemDetails "Internal server error"
+// @Router /payout-destinations/{id} [delete]
+// @Security Bearer
+func DeletePayoutDestination(
+	accountSvc *accountsvc.Service,
+	authSvc *authsvc.Service,
+) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		token, ok := c.Locals("user").(*jwt.Token)
+		if !ok {
+			return common.ProblemDetailsJSON(c, "Unauthorized", nil, "missing user context")
+		}
+		userID, err := authSvc.GetCurrentUserId(token)
+		if err != nil {
+			log.Error("failed to get user ID from token", "error", err)
+			return common.ProblemDetailsJSON(c, "Invalid user ID", err)
+		}
+		id, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return common.ProblemDetailsJSON(
+				c,
+				"Invalid payout destination ID",
+				err,
+				"Payout destination ID must be a valid UUID",
+				fiber.StatusBadRequest,
+			)
+		}
+		if err := accountSvc.RemovePayoutDestination(c.UserContext(), userID, id); err != nil {
+			log.Error(
+				"failed to remove payout destination",
+				"error", err,
+				"payout_destination_id", id,
+				"user_id", userID,
+			)
+			return common.ProblemDetailsJSON(c, "Failed to remove payout destination", err)
+		}
+		return common.SuccessResponseJSON(
+			c,
+			fiber.StatusOK,
+			"Payout destination removed",
+			fiber.Map{"id": id.String()},
+		)
+	}
+}
@@ -0,0 +1,82 @@
+package account
+
+import (
+	accountsvc "github.com/amirasaad/fintech/pkg/service/account"
+	authsvc "github.com/amirasaad/fintech/pkg/service/auth"
+	"github.com/amirasaad/fintech/webapi/common"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/log"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// ListPayouts returns a Fiber handler that lists the authenticated user's
+// payouts, i.e. their withdrawals across all accounts.
+// @Summary List payouts
+// @Description Lists one page of the user's withdrawals, newest first, with their
+// payout status, masked destination, amount, fee, estimated arrival and the Stripe
+// transfer ID for support. Pass status (pending, completed or failed) to filter.
+// @Tags accounts
+// @Produce json
+// @Param status query string false "Payout status filter"
+// @Param page query int false "Page number, starting at 1" default(1)
+// @Param page_size query int false "Page size (max 100)" default(20)
+// @Success 200 {object} common.Response{data=PayoutsResponse} "Payouts"
+// @Failure 400 {object} common.ProblemDetails "Invalid status"
+// @Failure 401 {object} common.ProblemDetails "Unauthorized"
+// @Failure 500 {object} common.ProblemDetails "Internal server error"
+// @Router /payouts [get]
+// @Security Bearer
+func ListPayouts(
+	accountSvc *accountsvc.Service,
+	authSvc *authsvc.Service,
+) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		token, ok := c.Locals("user").(*jwt.Token)
+		if !ok {
+			return common.ProblemDetailsJSON(c, "Unauthorized", nil, "missing user context")
+		}
+		userID, err := authSvc.GetCurrentUserId(token)
+		if err != nil {
+			log.Error("failed to get user ID from token", "error", err)
+			return common.ProblemDetailsJSON(c, "Invalid user ID", err)
+		}
+		status := c.Query("status")
+		switch status {
+		case "",
+			accountsvc.PayoutStatusPending,
+			accountsvc.PayoutStatusCompleted,
+			accountsvc.PayoutStatusFailed:
+		default:
+			return common.ProblemDetailsJSON(
+				c,
+				"Invalid status",
+				nil,
+				"Status must be one of pending, completed or failed",
+				fiber.StatusBadRequest,
+			)
+		}
+		page := max(c.QueryInt("page", 1), 1)
+		pageSize := c.QueryInt("page_size", accountsvc.DefaultPayoutPageSize)
+		if pageSize < 1 {
+			pageSize = accountsvc.DefaultPayoutPageSize
+		}
+		pageSize = min(pageSize, accountsvc.MaxPayoutPageSize)
+
+		payouts, err := accountSvc.ListPayouts(c.UserContext(), userID, status, page, pageSize)
+		if err != nil {
+			log.Error("failed to list payouts", "error", err, "user_id", userID)
+			return common.ProblemDetailsJSON(c, "Failed to list payouts", err)
+		}
+		dtos := make([]*PayoutDTO, 0, len(payouts))
+		for _, tx := range payouts {
+			dtos = append(dtos, ToPayoutDTO(tx))
+		}
+		return common.SuccessResponseJSON(
+			c,
+			fiber.StatusOK,
+			"Payouts fetched",
+			PayoutsResponse{Payouts: dtos, Page: page, PageSize: pageSize},
+		)
+	}
+}
@@ -0,0 +1,190 @@
+package account
+
+import (
+	"strings"
+
+	"github.com/amirasaad/fintech/pkg/dto"
+	accountsvc "github.com/amirasaad/fintech/pkg/service/account"
+	authsvc "github.com/amirasaad/fintech/pkg/service/auth"
+	"github.com/amirasaad/fintech/webapi/common"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/log"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// ListPendingTransfers returns a Fiber handler that lists the transfers
+// awaiting approval.
+// @Summary List transfers awaiting approval (admin only)
+// @Description Lists transfers that require approval, oldest first. Pass status
+// (pending, approved or rejected) to list decided transfers instead; it defaults
+// to pending.
+// @Tags admin
+// @Produce json
+// @Param status query string false "Status filter" default(pending)
+// @Success 200 {object} common.Response{data=[]PendingTransferDTO} "Pending transfers"
+// @Failure 400 {object} common.ProblemDetails "Invalid status"
+// @Failure 401 {object} common.ProblemDetails "Unauthorized"
+// @Failure 403 {object} common.ProblemDetails "Not an admin"
+// @Failure 500 {object} common.ProblemDetails "Internal server error"
+// @Router /admin/transfers/pending [get]
+// @Security Bearer
+func ListPendingTransfers(accountSvc *accountsvc.Service) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		status := c.Query("status", dto.PendingTransferPending)
+		switch status {
+		case dto.PendingTransferPending,
+			dto.PendingTransferApproved,
+			dto.PendingTransferRejected:
+		default:
+			return common.ProblemDetailsJSON(
+				c,
+				"Invalid status",
+				nil,
+				"Status must be one of pending, approved or rejected",
+				fiber.StatusBadRequest,
+			)
+		}
+		pending, err := accountSvc.ListPendingTransfers(c.UserContext(), status)
+		if err != nil {
+			log.Error("failed to list pending transfers", "error", err)
+			return common.ProblemDetailsJSON(c, "Failed to list pending transfers", err)
+		}
+		dtos := make([]*PendingTransferDTO, 0, len(pending))
+		for _, pt := range pending {
+			dtos = append(dtos, ToPendingTransferDTO(pt))
+		}
+		return common.SuccessResponseJSON(
+			c,
+			fiber.StatusOK,
+			"Pending transfers fetched",
+			dtos,
+		)
+	}
+}
+
+// ApproveTransfer returns a Fiber handler that approves a transfer awaiting
+// approval, crediting its destination account.
+// @Summary Approve a pending transfer (admin only)
+// @Description Credits the destination account of a transfer awaiting approval.
+// @Tags admin
+// @Produce json
+// @Param id path string true "Pending transfer ID"
+// @Success 200 {object} common.Response{data=PendingTransferDTO} "Transfer approved"
+// @Failure 400 {object} common.ProblemDetails "Invalid pending transfer ID"
+// @Failure 401 {object} common.ProblemDetails "Unauthorized"
+// @Failure 403 {object} common.ProblemDetails "Not an admin"
+// @Failure 404 {object} common.ProblemDetails "Pending transfer not found"
+// @Failure 409 {object} common.ProblemDetails "Transfer already approved or rejected"
+// @Failure 500 {object} common.ProblemDetails "Internal server error"
+// @Router /admin/transfers/{id}/approve [post]
+// @Security Bearer
+func ApproveTransfer(
+	accountSvc *accountsvc.Service,
+	authSvc *authsvc.Service,
+) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		approverID, id, err := parseDecision(c, authSvc, "pending transfer")
+		if id == uuid.Nil {
+			return err // error response already written
+		}
+		pt, err := accountSvc.ApproveTransfer(common.DetachedContext(c), approverID, id)
+		if err != nil {
+			log.Error("failed to approve transfer", "error", err, "pending_transfer_id", id)
+			return common.ProblemDetailsJSON(c, "Failed to approve transfer", err)
+		}
+		return common.SuccessResponseJSON(
+			c,
+			fiber.StatusOK,
+			"Transfer approved",
+			ToPendingTransferDTO(pt),
+		)
+	}
+}
+
+// RejectTransfer returns a Fiber handler that rejects a transfer awaiting
+// approval, returning its amount to the source account.
+// @Summary Reject a pending transfer (admin only)
+// @Description Returns the amount of a transfer awaiting approval to its source
+// account and marks the outgoing transaction failed.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Pending transfer ID"
+// @Param request body RejectTransferRequest false "Rejection reason"
+// @Success 200 {object} common.Response{data=PendingTransferDTO} "Transfer rejected"
+// @Failure 400 {object} common.ProblemDetails "Invalid request"
+// @Failure 401 {object} common.ProblemDetails "Unauthorized"
+// @Failure 403 {object} common.ProblemDetails "Not an admin"
+// @Failure 404 {object} common.ProblemDetails "Pending transfer not found"
+// @Failure 409 {object} common.ProblemDetails "Transfer already approved or rejected"
+// @Failure 500 {object} common.ProblemDetails "Internal server error"
+// @Router /admin/transfers/{id}/reject [post]
+// @Security Bearer
+func RejectTransfer(
+	accountSvc *accountsvc.Service,
+	authSvc *authsvc.Service,
+) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		approverID, id, err := parseDecision(c, authSvc, "pending transfer")
+		if id == uuid.Nil {
+			return err // error response already written
+		}
+		var input RejectTransferRequest
+		if len(c.Body()) > 0 {
+			in, err := common.BindAndValidate[RejectTransferRequest](c)
+			if in == nil {
+				return err // error response already written
+			}
+			input = *in
+		}
+		pt, err := accountSvc.RejectTransfer(
+			common.DetachedContext(c), approverID, id, input.Reason,
+		)
+		if err != nil {
+			log.Error("failed to reject transfer", "error", err, "pending_transfer_id", id)
+			return common.ProblemDetailsJSON(c, "Failed to reject transfer", err)
+		}
+		return common.SuccessResponseJSON(
+			c,
+			fiber.StatusOK,
+			"Transfer rejected",
+			ToPendingTransferDTO(pt),
+		)
+	}
+}
+
+// parseDecision returns the user deciding on a pending transfer or
+// withdrawal, named by what, and its ID. If either is missing or invalid it
+// writes the error response and returns uuid.Nil IDs with the result of
+// writing it.
+func parseDecision(
+	c *fiber.Ctx,
+	authSvc *authsvc.Service,
+	what string,
+) (approverID, id uuid.UUID, err error) {
+	token, ok := c.Locals("user").(*jwt.Token)
+	if !ok {
+		return uuid.Nil, uuid.Nil, common.ProblemDetailsJSON(
+			c, "Unauthorized", nil, "missing user context",
+		)
+	}
+	approverID, err = authSvc.GetCurrentUserId(token)
+	if err != nil {
+		log.Error("failed to get user ID from token", "error", err)
+		return uuid.Nil, uuid.Nil, common.ProblemDetailsJSON(c, "Invalid user ID", err)
+	}
+	id, err = uuid.Parse(c.Params("id"))
+	if err != nil {
+		title := strings.ToUpper(what[:1]) + what[1:]
+		return uuid.Nil, uuid.Nil, common.ProblemDetailsJSON(
+			c,
+			"Invalid "+what+" ID",
+			err,
+			title+" ID must be a valid UUID",
+			fiber.StatusBadRequest,
+		)
+	}
+	return approverID, id, nil
+}
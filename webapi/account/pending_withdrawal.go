@@ -0,0 +1,160 @@
+package account
+
+import (
+	"github.com/amirasaad/fintech/pkg/dto"
+	accountsvc "github.com/amirasaad/fintech/pkg/service/account"
+	authsvc "github.com/amirasaad/fintech/pkg/service/auth"
+	"github.com/amirasaad/fintech/webapi/common"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/log"
+	"github.com/google/uuid"
+)
+
+// ListPendingWithdrawals returns a Fiber handler that lists the withdrawals
+// awaiting approval.
+// @Summary List withdrawals awaiting approval (admins and approvers)
+// @Description Lists withdrawals held for approval, oldest first. Pass status
+// (pending, approved or rejected) to list decided withdrawals instead; it
+// defaults to pending.
+// @Tags admin
+// @Produce json
+// @Param status query string false "Status filter" default(pending)
+// @Success 200 {object} common.Response{data=[]PendingWithdrawalDTO} "Pending withdrawals"
+// @Failure 400 {object} common.ProblemDetails "Invalid status"
+// @Failure 401 {object} common.ProblemDetails "Unauthorized"
+// @Failure 403 {object} common.ProblemDetails "Not an admin or approver"
+// @Failure 500 {object} common.ProblemDetails "Internal server error"
+// @Router /admin/withdrawals/pending [get]
+// @Security Bearer
+func ListPendingWithdrawals(accountSvc *accountsvc.Service) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		status := c.Query("status", dto.PendingWithdrawalPending)
+		switch status {
+		case dto.PendingWithdrawalPending,
+			dto.PendingWithdrawalApproved,
+			dto.PendingWithdrawalRejected:
+		default:
+			return common.ProblemDetailsJSON(
+				c,
+				"Invalid status",
+				nil,
+				"Status must be one of pending, approved or rejected",
+				fiber.StatusBadRequest,
+			)
+		}
+		pending, err := accountSvc.ListPendingWithdrawals(c.UserContext(), status)
+		if err != nil {
+			log.Error("failed to list pending withdrawals", "error", err)
+			return common.ProblemDetailsJSON(c, "Failed to list pending withdrawals", err)
+		}
+		dtos := make([]*PendingWithdrawalDTO, 0, len(pending))
+		for _, pw := range pending {
+			dtos = append(dtos, ToPendingWithdrawalDTO(pw))
+		}
+		return common.SuccessResponseJSON(
+			c,
+			fiber.StatusOK,
+			"Pending withdrawals fetched",
+			dtos,
+		)
+	}
+}
+
+// ApproveWithdrawal returns a Fiber handler that records an approval of a
+// withdrawal awaiting approval. Once it has all the approvals it needs, the
+// hold is released and the withdrawal proceeds.
+// @Summary Approve a pending withdrawal (admins and approvers)
+// @Description Records the caller's approval of a held withdrawal. The user who
+// requested the withdrawal cannot approve it. Once the required number of
+// approvals is reached the withdrawal is executed; until then it stays pending.
+// @Tags admin
+// @Produce json
+// @Param id path string true "Pending withdrawal ID"
+// @Success 200 {object} common.Response{data=PendingWithdrawalDTO} "Approval recorded"
+// @Failure 400 {object} common.ProblemDetails "Invalid pending withdrawal ID"
+// @Failure 401 {object} common.ProblemDetails "Unauthorized"
+// @Failure 403 {object} common.ProblemDetails "Not an admin or approver, or approving own withdrawal"
+// @Failure 404 {object} common.ProblemDetails "Pending withdrawal not found"
+// @Failure 409 {object} common.ProblemDetails "Already approved by caller, or already decided"
+// @Failure 500 {object} common.ProblemDetails "Internal server error"
+// @Router /admin/withdrawals/{id}/approve [post]
+// @Security Bearer
+func ApproveWithdrawal(
+	accountSvc *accountsvc.Service,
+	authSvc *authsvc.Service,
+) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		approverID, id, err := parseDecision(c, authSvc, "pending withdrawal")
+		if id == uuid.Nil {
+			return err // error response already written
+		}
+		pw, err := accountSvc.ApproveWithdrawal(common.DetachedContext(c), approverID, id)
+		if err != nil {
+			log.Error("failed to approve withdrawal", "error", err, "pending_withdrawal_id", id)
+			return common.ProblemDetailsJSON(c, "Failed to approve withdrawal", err)
+		}
+		message := "Withdrawal approval recorded"
+		if pw.Status == dto.PendingWithdrawalApproved {
+			message = "Withdrawal approved"
+		}
+		return common.SuccessResponseJSON(
+			c,
+			fiber.StatusOK,
+			message,
+			ToPendingWithdrawalDTO(pw),
+		)
+	}
+}
+
+// RejectWithdrawal returns a Fiber handler that rejects a withdrawal awaiting
+// approval, releasing its hold.
+// @Summary Reject a pending withdrawal (admins and approvers)
+// @Description Releases the funds held for a withdrawal awaiting approval back
+// to its account and marks the hold transaction canceled.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Pending withdrawal ID"
+// @Param request body RejectWithdrawalRequest false "Rejection reason"
+// @Success 200 {object} common.Response{data=PendingWithdrawalDTO} "Withdrawal rejected"
+// @Failure 400 {object} common.ProblemDetails "Invalid request"
+// @Failure 401 {object} common.ProblemDetails "Unauthorized"
+// @Failure 403 {object} common.ProblemDetails "Not an admin or approver"
+// @Failure 404 {object} common.ProblemDetails "Pending withdrawal not found"
+// @Failure 409 {object} common.ProblemDetails "Withdrawal already approved or rejected"
+// @Failure 500 {object} common.ProblemDetails "Internal server error"
+// @Router /admin/withdrawals/{id}/reject [post]
+// @Security Bearer
+func RejectWithdrawal(
+	accountSvc *accountsvc.Service,
+	authSvc *authsvc.Service,
+) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		approverID, id, err := parseDecision(c, authSvc, "pending withdrawal")
+		if id == uuid.Nil {
+			return err // error response already written
+		}
+		var input RejectWithdrawalRequest
+		if len(c.Body()) > 0 {
+			in, err := common.BindAndValidate[RejectWithdrawalRequest](c)
+			if in == nil {
+				return err // error response already written
+			}
+			input = *in
+		}
+		pw, err := accountSvc.RejectWithdrawal(
+			common.DetachedContext(c), approverID, id, input.Reason,
+		)
+		if err != nil {
+			log.Error("failed to reject withdrawal", "error", err, "pending_withdrawal_id", id)
+			return common.ProblemDetailsJSON(c, "Failed to reject withdrawal", err)
+		}
+		return common.SuccessResponseJSON(
+			c,
+			fiber.StatusOK,
+			"Withdrawal rejected",
+			ToPendingWithdrawalDTO(pw),
+		)
+	}
+}
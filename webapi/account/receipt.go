@@ -0,0 +1,208 @@
+package account
+
+import (
+	"html/template"
+	"strings"
+	"time"
+
+	accountsvc "github.com/amirasaad/fintech/pkg/service/account"
+	authsvc "github.com/amirasaad/fintech/pkg/service/auth"
+	"github.com/amirasaad/fintech/webapi/common"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/log"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// receiptHTML renders a ReceiptDTO for a browser.
+var receiptHTML = template.Must(template.New("receipt").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="robots" content="noindex">
+<title>Receipt {{.TransactionID}}</title>
+</head>
+<body>
+<h1>Receipt</h1>
+<dl>
+<dt>Transaction</dt><dd>{{.TransactionID}}</dd>
+<dt>Type</dt><dd>{{.Type}}</dd>
+<dt>Status</dt><dd>{{.Status}}</dd>
+<dt>Amount</dt><dd>{{.Amount}} {{.Currency}} ({{.Direction}})</dd>
+{{- if .Fee}}
+<dt>Fee</dt><dd>{{.Fee}} {{.Currency}}</dd>
+{{- end}}
+{{- if .ConvertedAmount}}
+<dt>Converted</dt><dd>{{.ConvertedAmount}} {{.TargetCurrency}}</dd>
+{{- end}}
+{{- if .Description}}
+<dt>Description</dt><dd>{{.Description}}</dd>
+{{- end}}
+<dt>Account</dt><dd>{{.Account}}</dd>
+{{- if .Counterparty}}
+<dt>Counterparty</dt><dd>{{.Counterparty}}</dd>
+{{- end}}
+<dt>Date</dt><dd>{{.CreatedAt.Format "2006-01-02 15:04:05 MST"}}</dd>
+</dl>
+<p>This link expires {{.ExpiresAt.Format "2006-01-02 15:04:05 MST"}}.</p>
+</body>
+</html>
+`))
+
+// CreateReceiptLink returns a Fiber handler that creates a shareable link to
+// the receipt of one of the user's completed transactions.
+// @Summary Create a receipt link
+// @Description Signs a link that shows the receipt of a completed transaction to
+// anyone who has it, without logging in, until it expires after RECEIPT_TTL. The
+// link reveals only that transaction; account and counterparty are masked.
+// Links are not stored, so each call creates a new one.
+// @Tags accounts
+// @Produce json
+// @Param id path string true "Transaction ID"
+// @Success 201 {object} common.Response{data=ReceiptLinkDTO} "Receipt link created"
+// @Failure 400 {object} common.ProblemDetails "Invalid transaction ID"
+// @Failure 401 {object} common.ProblemDetails "Unauthorized"
+// @Failure 404 {object} common.ProblemDetails "Transaction not found"
+// @Failure 409 {object} common.ProblemDetails "Transaction not completed"
+// @Failure 500 {object} common.ProblemDetails "Internal server error"
+// @Router /transactions/{id}/receipt [post]
+// @Security Bearer
+func CreateReceiptLink(
+	accountSvc *accountsvc.Service,
+	authSvc *authsvc.Service,
+) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		token, ok := c.Locals("user").(*jwt.Token)
+		if !ok {
+			return common.ProblemDetailsJSON(c, "Unauthorized", nil, "missing user context")
+		}
+		userID, err := authSvc.GetCurrentUserId(token)
+		if err != nil {
+			log.Error("failed to get user ID from token", "error", err)
+			return common.ProblemDetailsJSON(c, "Invalid user ID", err)
+		}
+		txID, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return common.ProblemDetailsJSON(
+				c,
+				"Invalid transaction ID",
+				err,
+				"Transaction ID must be a valid UUID",
+				fiber.StatusBadRequest,
+			)
+		}
+		receiptToken, expiresAt, err := accountSvc.ReceiptLink(c.UserContext(), userID, txID)
+		if err != nil {
+			log.Error(
+				"failed to create receipt link",
+				"error", err,
+				"user_id", userID,
+				"transaction_id", txID,
+			)
+			return common.ProblemDetailsJSON(c, "Failed to create receipt link", err)
+		}
+		// The receipt route is registered next to this one, so its URL is
+		// this one's with /transactions/... replaced.
+		prefix, _, _ := strings.Cut(c.Path(), "/transactions/")
+		return common.SuccessResponseJSON(
+			c,
+			fiber.StatusCreated,
+			"Receipt link created",
+			ReceiptLinkDTO{
+				URL:       c.BaseURL() + prefix + "/receipts/" + receiptToken,
+				Token:     receiptToken,
+				ExpiresAt: expiresAt,
+			},
+		)
+	}
+}
+
+// GetReceipt returns a Fiber handler that shows the receipt a receipt link
+// points to. The token authorizes the request; no login is required.
+// @Summary Get a shared receipt
+// @Description Returns the receipt of the transaction the token was created for,
+// as JSON, or as an HTML page when the client prefers text/html or format=html.
+// @Tags accounts
+// @Produce json,html
+// @Param token path string true "Receipt token"
+// @Param format query string false "html for an HTML page"
+// @Success 200 {object} common.Response{data=ReceiptDTO} "Receipt"
+// @Failure 404 {object} common.ProblemDetails "Receipt not found"
+// @Failure 410 {object} common.ProblemDetails "Receipt link expired"
+// @Router /receipts/{token} [get]
+func GetReceipt(accountSvc *accountsvc.Service) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		// The URL is the credential: keep it out of caches and referrers.
+		c.Set(fiber.HeaderCacheControl, "no-store")
+		c.Set(fiber.HeaderReferrerPolicy, "no-referrer")
+		receipt, err := accountSvc.Receipt(c.UserContext(), c.Params("token"))
+		if err != nil {
+			return common.ProblemDetailsJSON(c, "Failed to get receipt", err)
+		}
+		dto := ToReceiptDTO(receipt)
+		if c.Query("format") == "html" ||
+			c.Accepts(fiber.MIMEApplicationJSON, fiber.MIMETextHTML) == fiber.MIMETextHTML {
+			var b strings.Builder
+			if err := receiptHTML.Execute(&b, dto); err != nil {
+				return common.ProblemDetailsJSON(c, "Failed to render receipt", err)
+			}
+			c.Type("html", "utf-8")
+			return c.SendString(b.String())
+		}
+		return common.SuccessResponseJSON(c, fiber.StatusOK, "Receipt", dto)
+	}
+}
+
+// ToReceiptDTO maps a receipt to its response.
+func ToReceiptDTO(r *accountsvc.Receipt) ReceiptDTO {
+	dto := ReceiptDTO{
+		TransactionID: r.TransactionID.String(),
+		Type:          r.Type,
+		Status:        r.Status,
+		Direction:     r.Direction,
+		Description:   r.Description,
+		Account:       r.Account,
+		Counterparty:  r.Counterparty,
+		CreatedAt:     r.CreatedAt,
+		ExpiresAt:     r.ExpiresAt,
+	}
+	if r.Amount != nil {
+		dto.Amount = r.Amount.AmountString()
+		dto.Currency = r.Amount.CurrencyCode().String()
+	}
+	if r.Fee != nil {
+		dto.Fee = r.Fee.AmountString()
+	}
+	if r.ConvertedAmount != nil {
+		dto.ConvertedAmount = r.ConvertedAmount.AmountString()
+		dto.TargetCurrency = r.ConvertedAmount.CurrencyCode().String()
+	}
+	return dto
+}
+
+// ReceiptLinkDTO is a shareable receipt link.
+type ReceiptLinkDTO struct {
+	URL       string    `json:"url"`
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// ReceiptDTO is the receipt of a completed transaction. Amount is absolute;
+// Direction is credit or debit.
+type ReceiptDTO struct {
+	TransactionID   string    `json:"transaction_id"`
+	Type            string    `json:"type"`
+	Status          string    `json:"status"`
+	Amount          string    `json:"amount"`
+	Currency        string    `json:"currency"`
+	Direction       string    `json:"direction"`
+	Fee             string    `json:"fee,omitempty"`
+	ConvertedAmount string    `json:"converted_amount,omitempty"`
+	TargetCurrency  string    `json:"target_currency,omitempty"`
+	Description     string    `json:"description,omitempty"`
+	Account         string    `json:"account"`
+	Counterparty    string    `json:"counterparty,omitempty"`
+	CreatedAt       time.Time `json:"created_at"`
+	ExpiresAt       time.Time `json:"expires_at"`
+}
@@ -0,0 +1,34 @@
+package account
+
+import (
+	"errors"
+
+	accountdomain "github.com/amirasaad/fintech/pkg/domain/account"
+	accountsvc "github.com/amirasaad/fintech/pkg/service/account"
+	"github.com/amirasaad/fintech/webapi/common"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/log"
+	"github.com/google/uuid"
+)
+
+// resolveAccountID resolves the :ref route parameter, an account UUID or
+// reference, to the account ID. It returns uuid.Nil once it has written an
+// error response.
+func resolveAccountID(c *fiber.Ctx, accountSvc *accountsvc.Service) (uuid.UUID, error) {
+	ref := c.Params("ref")
+	id, err := accountSvc.ResolveAccountID(c.UserContext(), ref)
+	if err == nil {
+		return id, nil
+	}
+	log.Error("failed to resolve account", "account_ref", ref, "error", err)
+	if errors.Is(err, accountdomain.ErrInvalidReference) {
+		return uuid.Nil, common.ProblemDetailsJSON(
+			c,
+			"Invalid account ID",
+			err,
+			"Account ID must be a valid UUID or account reference",
+		)
+	}
+	return uuid.Nil, common.ProblemDetailsJSON(c, "Failed to resolve account", err)
+}
@@ -0,0 +1,79 @@
+package account
+
+import (
+	accountsvc "github.com/amirasaad/fintech/pkg/service/account"
+	authsvc "github.com/amirasaad/fintech/pkg/service/auth"
+	"github.com/amirasaad/fintech/webapi/common"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/log"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// RefundDeposit returns a Fiber handler that refunds part or all of a
+// completed deposit to the payment method that funded it.
+// @Summary Refund a deposit
+// @Description Refunds an amount of a completed deposit, up to what has not
+// been refunded yet. The refund is returned as a pending transaction; the
+// balance is debited once the payment provider confirms it.
+// @Tags accounts
+// @Accept json
+// @Produce json
+// @Param id path string true "Deposit transaction ID"
+// @Param request body RefundRequest true "Refund details"
+// @Success 202 {object} common.Response{data=TransactionDTO} "Refund initiated"
+// @Failure 400 {object} common.ProblemDetails "Invalid request"
+// @Failure 401 {object} common.ProblemDetails "Unauthorized"
+// @Failure 404 {object} common.ProblemDetails "Transaction not found"
+// @Failure 422 {object} common.ProblemDetails "Not refundable or exceeds the deposit"
+// @Failure 501 {object} common.ProblemDetails "Refunds not supported"
+// @Failure 500 {object} common.ProblemDetails "Internal server error"
+// @Router /transactions/{id}/refund [post]
+// @Security Bearer
+func RefundDeposit(
+	accountSvc *accountsvc.Service,
+	authSvc *authsvc.Service,
+) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		token, ok := c.Locals("user").(*jwt.Token)
+		if !ok {
+			return common.ProblemDetailsJSON(c, "Unauthorized", nil, "missing user context")
+		}
+		userID, err := authSvc.GetCurrentUserId(token)
+		if err != nil {
+			log.Error("failed to get user ID from token", "error", err)
+			return common.ProblemDetailsJSON(c, "Invalid user ID", err)
+		}
+		txID, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return common.ProblemDetailsJSON(
+				c,
+				"Invalid transaction ID",
+				err,
+				"Transaction ID must be a valid UUID",
+				fiber.StatusBadRequest,
+			)
+		}
+		input, err := common.BindAndValidate[RefundRequest](c)
+		if input == nil {
+			return err // error response already written
+		}
+		tx, err := accountSvc.RefundDeposit(common.DetachedContext(c), userID, txID, input.Amount)
+		if err != nil {
+			log.Error(
+				"failed to refund deposit",
+				"error", err,
+				"user_id", userID,
+				"transaction_id", txID,
+			)
+			return common.ProblemDetailsJSON(c, "Failed to refund deposit", err)
+		}
+		return common.SuccessResponseJSON(
+			c,
+			fiber.StatusAccepted,
+			"Refund initiated",
+			ToTransactionDTO(tx),
+		)
+	}
+}
@@ -0,0 +1,90 @@
+package account
+
+import (
+	"github.com/amirasaad/fintech/pkg/domain/events"
+	accountsvc "github.com/amirasaad/fintech/pkg/service/account"
+	authsvc "github.com/amirasaad/fintech/pkg/service/auth"
+	"github.com/amirasaad/fintech/webapi/common"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/log"
+	"github.com/google/uuid"
+)
+
+// ReprocessTransaction returns a Fiber handler that re-emits the event that
+// settled a transaction so its handlers run again.
+// @Summary Reprocess a transaction's events (admin only)
+// @Description Repairs a transaction whose event was handled but whose side effects
+// failed by re-emitting the event rebuilt from the stored transaction:
+// Payment.Completed for completed deposits and withdrawals, Refund.Completed or
+// Refund.Failed for refunds. Handlers that already applied the event skip it, but
+// misuse can process a transaction twice, so each call is logged with the admin
+// and reason. With dry_run the event is returned without being emitted.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Transaction ID"
+// @Param request body ReprocessRequest true "Reason, and whether to only show the event"
+// @Success 200 {object} common.Response{data=ReprocessDTO} "Event that would be re-emitted"
+// @Success 202 {object} common.Response{data=ReprocessDTO} "Event re-emitted"
+// @Failure 400 {object} common.ProblemDetails "Invalid request"
+// @Failure 401 {object} common.ProblemDetails "Unauthorized"
+// @Failure 403 {object} common.ProblemDetails "Not an admin"
+// @Failure 404 {object} common.ProblemDetails "Transaction not found"
+// @Failure 409 {object} common.ProblemDetails "Transaction has no event to re-emit"
+// @Failure 500 {object} common.ProblemDetails "Internal server error"
+// @Router /admin/transactions/{id}/reprocess [post]
+// @Security Bearer
+func ReprocessTransaction(
+	accountSvc *accountsvc.Service,
+	authSvc *authsvc.Service,
+) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		adminID, txID, err := parseDecision(c, authSvc, "transaction")
+		if txID == uuid.Nil {
+			return err // error response already written
+		}
+		input, err := common.BindAndValidate[ReprocessRequest](c)
+		if input == nil {
+			return err // error response already written
+		}
+		evt, err := accountSvc.ReprocessTransaction(
+			common.DetachedContext(c), adminID, txID, input.Reason, input.DryRun,
+		)
+		if err != nil {
+			log.Error(
+				"failed to reprocess transaction",
+				"error", err,
+				"admin_id", adminID,
+				"transaction_id", txID,
+			)
+			return common.ProblemDetailsJSON(c, "Failed to reprocess transaction", err)
+		}
+		status, message := fiber.StatusAccepted, "Transaction event re-emitted"
+		if input.DryRun {
+			status, message = fiber.StatusOK, "Transaction event not re-emitted (dry run)"
+		}
+		return common.SuccessResponseJSON(c, status, message, ReprocessDTO{
+			TransactionID: txID.String(),
+			EventType:     evt.Type(),
+			DryRun:        input.DryRun,
+			Event:         evt,
+		})
+	}
+}
+
+// ReprocessRequest is the request body for reprocessing a transaction's
+// events. Reason is recorded in the audit log.
+type ReprocessRequest struct {
+	Reason string `json:"reason" validate:"required,max=255"`
+	DryRun bool   `json:"dry_run"`
+}
+
+// ReprocessDTO is the event a reprocess re-emitted, or would re-emit on a
+// dry run.
+type ReprocessDTO struct {
+	TransactionID string       `json:"transaction_id"`
+	EventType     string       `json:"event_type"`
+	DryRun        bool         `json:"dry_run"`
+	Event         events.Event `json:"event"`
+}
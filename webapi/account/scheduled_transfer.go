@@ -0,0 +1,135 @@
+package account
+
+import (
+	"github.com/amirasaad/fintech/pkg/dto"
+	accountsvc "github.com/amirasaad/fintech/pkg/service/account"
+	authsvc "github.com/amirasaad/fintech/pkg/service/auth"
+	"github.com/amirasaad/fintech/webapi/common"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/log"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// ListScheduledTransfers returns a Fiber handler that lists the authenticated
+// user's scheduled transfers.
+// @Summary List scheduled transfers
+// @Description Lists the user's scheduled transfers, optionally filtered by status
+// (pending, executed, canceled or failed).
+// @Tags accounts
+// @Accept json
+// @Produce json
+// @Param status query string false "Status filter"
+// @Success 200 {object} common.Response{data=[]ScheduledTransferDTO} "Scheduled transfers"
+// @Failure 400 {object} common.ProblemDetails "Invalid request"
+// @Failure 401 {object} common.ProblemDetails "Unauthorized"
+// @Failure 500 {object} common.ProblemDetails "Internal server error"
+// @Router /transfers/scheduled [get]
+// @Security Bearer
+func ListScheduledTransfers(
+	accountSvc *accountsvc.Service,
+	authSvc *authsvc.Service,
+) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		token, ok := c.Locals("user").(*jwt.Token)
+		if !ok {
+			return common.ProblemDetailsJSON(c, "Unauthorized", nil, "missing user context")
+		}
+		userID, err := authSvc.GetCurrentUserId(token)
+		if err != nil {
+			log.Error("failed to get user ID from token", "error", err)
+			return common.ProblemDetailsJSON(c, "Invalid user ID", err)
+		}
+		status := c.Query("status")
+		switch status {
+		case "",
+			dto.ScheduledTransferPending,
+			dto.ScheduledTransferExecuted,
+			dto.ScheduledTransferCanceled,
+			dto.ScheduledTransferFailed:
+		default:
+			return common.ProblemDetailsJSON(
+				c,
+				"Invalid status",
+				nil,
+				"Status must be one of pending, executed, canceled or failed",
+				fiber.StatusBadRequest,
+			)
+		}
+
+		scheduled, err := accountSvc.ListScheduledTransfers(c.UserContext(), userID, status)
+		if err != nil {
+			log.Error("failed to list scheduled transfers", "error", err, "user_id", userID)
+			return common.ProblemDetailsJSON(c, "Failed to list scheduled transfers", err)
+		}
+		dtos := make([]*ScheduledTransferDTO, 0, len(scheduled))
+		for _, st := range scheduled {
+			dtos = append(dtos, ToScheduledTransferDTO(st))
+		}
+		return common.SuccessResponseJSON(
+			c,
+			fiber.StatusOK,
+			"Scheduled transfers fetched",
+			dtos,
+		)
+	}
+}
+
+// CancelScheduledTransfer returns a Fiber handler that cancels a pending
+// scheduled transfer.
+// @Summary Cancel a scheduled transfer
+// @Description Cancels a scheduled transfer that has not been executed yet.
+// @Tags accounts
+// @Accept json
+// @Produce json
+// @Param id path string true "Scheduled transfer ID"
+// @Success 200 {object} common.Response "Scheduled transfer canceled"
+// @Failure 400 {object} common.ProblemDetails "Invalid request"
+// @Failure 401 {object} common.ProblemDetails "Unauthorized"
+// @Failure 404 {object} common.ProblemDetails "Scheduled transfer not found"
+// @Failure 409 {object} common.ProblemDetails "Scheduled transfer is no longer pending"
+// @Failure 500 {object} common.ProblemDetails "Internal server error"
+// @Router /transfers/scheduled/{id} [delete]
+// @Security Bearer
+func CancelScheduledTransfer(
+	accountSvc *accountsvc.Service,
+	authSvc *authsvc.Service,
+) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		token, ok := c.Locals("user").(*jwt.Token)
+		if !ok {
+			return common.ProblemDetailsJSON(c, "Unauthorized", nil, "missing user context")
+		}
+		userID, err := authSvc.GetCurrentUserId(token)
+		if err != nil {
+			log.Error("failed to get user ID from token", "error", err)
+			return common.ProblemDetailsJSON(c, "Invalid user ID", err)
+		}
+		id, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return common.ProblemDetailsJSON(
+				c,
+				"Invalid scheduled transfer ID",
+				err,
+				"Scheduled transfer ID must be a valid UUID",
+				fiber.StatusBadRequest,
+			)
+		}
+		if err := accountSvc.CancelScheduledTransfer(c.UserContext(), userID, id); err != nil {
+			log.Error(
+				"failed to cancel scheduled transfer",
+				"error", err,
+				"scheduled_transfer_id", id,
+				"user_id", userID,
+			)
+			return common.ProblemDetailsJSON(c, "Failed to cancel scheduled transfer", err)
+		}
+		return common.SuccessResponseJSON(
+			c,
+			fiber.StatusOK,
+			"Scheduled transfer canceled",
+			fiber.Map{"id": id.String()},
+		)
+	}
+}
@@ -0,0 +1,322 @@
+package account
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	accountdomain "github.com/amirasaad/fintech/pkg/domain/account"
+	"github.com/amirasaad/fintech/pkg/dto"
+	"github.com/amirasaad/fintech/pkg/money"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Version is the version of the account API a request was routed through. It
+// selects the response shape of the account and transaction reads; other
+// endpoints respond the same in every version.
+type Version int
+
+const (
+	// V1 returns amounts as floats in the main currency unit, with the exact
+	// amounts in separate *_money fields. Unversioned routes serve V1.
+	V1 Version = 1
+	// V2 returns amounts as {amount, currency} objects in the smallest
+	// currency unit, and adds the status and a display label.
+	V2 Version = 2
+)
+
+// versionLocal is the fiber.Ctx local holding the request's Version.
+const versionLocal = "account_api_version"
+
+// UseVersion returns a middleware that routes requests to version v. It is
+// meant for route groups such as /v2.
+func UseVersion(v Version) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		c.Locals(versionLocal, v)
+		return c.Next()
+	}
+}
+
+// requestVersion returns the version the request was routed through, V1 if
+// none.
+func requestVersion(c *fiber.Ctx) Version {
+	if v, ok := c.Locals(versionLocal).(Version); ok {
+		return v
+	}
+	return V1
+}
+
+// AccountV2DTO is the v2 representation of an account.
+type AccountV2DTO struct {
+	ID        string `json:"id"`
+	Reference string `json:"reference,omitempty"`
+	UserID    string `json:"user_id"`
+	// Label is a display name such as "USD account".
+	Label          string       `json:"label"`
+	Type           string       `json:"type"`
+	Status         string       `json:"status"`
+	Balance        *money.Money `json:"balance"`
+	OverdraftLimit *money.Money `json:"overdraft_limit"`
+	AutoConvert    bool         `json:"auto_convert"`
+	Interest       *InterestDTO `json:"interest,omitempty"`
+	// FeePolicy is who bears the provider fee on deposits, absorb or
+	// pass_through; omitted if the account follows the global policy.
+	FeePolicy string    `json:"fee_policy,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TransactionV2DTO is the v2 representation of a transaction. Amount is
+// negative for debits.
+type TransactionV2DTO struct {
+	ID        string `json:"id"`
+	AccountID string `json:"account_id"`
+	// Label is the description, or a display name for the kind of
+	// transaction such as "Deposit" or "Transfer out" if it has none.
+	Label   string       `json:"label"`
+	Status  string       `json:"status"`
+	Amount  *money.Money `json:"amount"`
+	Balance *money.Money `json:"balance"`
+	Fee     *money.Money `json:"fee,omitempty"`
+	// PlatformFee is the provider fee the platform absorbed, not deducted
+	// from the account, and FeePolicy the policy applied to the provider
+	// fee; both omitted if none was charged.
+	PlatformFee       *money.Money      `json:"platform_fee,omitempty"`
+	FeePolicy         string            `json:"fee_policy,omitempty"`
+	MoneySource       string            `json:"money_source"`
+	Description       string            `json:"description,omitempty"`
+	ExternalReference string            `json:"external_reference,omitempty"`
+	PaymentMethod     *PaymentMethodDTO `json:"payment_method,omitempty"`
+	// RefundedTransactionID is the deposit a refund refunds; omitted otherwise.
+	RefundedTransactionID string    `json:"refunded_transaction_id,omitempty"`
+	CreatedAt             time.Time `json:"created_at"`
+}
+
+// BalanceV2Response is the v2 response payload for an account balance.
+type BalanceV2Response struct {
+	Balance   *money.Money `json:"balance"`
+	Formatted string       `json:"formatted"`        // Formatted to the currency's decimals
+	Symbol    string       `json:"symbol,omitempty"` // Empty if the currency is not registered
+	// Display is the balance converted to the currency of the display query
+	// parameter, if requested.
+	Display *DisplayBalanceDTO `json:"display,omitempty"`
+	// Interest is the interest a savings account earns and has accrued.
+	Interest *InterestDTO `json:"interest,omitempty"`
+}
+
+// AggregatedBalanceV2Response is the v2 response payload for aggregated
+// balances, one total per currency sorted by currency.
+type AggregatedBalanceV2Response struct {
+	Totals []*money.Money `json:"totals"`
+}
+
+// ToAccountV2DTO maps a dto.AccountRead to an AccountV2DTO.
+func ToAccountV2DTO(acc *dto.AccountRead) *AccountV2DTO {
+	if acc == nil {
+		return nil
+	}
+	return &AccountV2DTO{
+		ID:             acc.ID.String(),
+		Reference:      acc.Reference,
+		UserID:         acc.UserID.String(),
+		Label:          strings.ToUpper(acc.Currency) + " account",
+		Type:           acc.Type,
+		Status:         acc.Status,
+		Balance:        exactMoney(acc.BalanceMoney, acc.Balance, acc.Currency),
+		OverdraftLimit: exactMoney(nil, acc.OverdraftLimit, acc.Currency),
+		AutoConvert:    acc.AutoConvert,
+		Interest:       toInterestDTO(acc),
+		FeePolicy:      acc.FeePolicy,
+		CreatedAt:      acc.CreatedAt,
+		UpdatedAt:      acc.UpdatedAt,
+	}
+}
+
+// ToTransactionV2DTO maps a dto.TransactionRead to a TransactionV2DTO.
+func ToTransactionV2DTO(tx *dto.TransactionRead) *TransactionV2DTO {
+	if tx == nil {
+		return nil
+	}
+	out := &TransactionV2DTO{
+		ID:                tx.ID.String(),
+		AccountID:         tx.AccountID.String(),
+		Label:             transactionLabel(tx),
+		Status:            tx.Status,
+		Amount:            exactMoney(tx.AmountMoney, tx.Amount, tx.Currency),
+		Balance:           exactMoney(tx.BalanceMoney, tx.Balance, tx.Currency),
+		MoneySource:       tx.MoneySource,
+		Description:       tx.Description,
+		ExternalReference: tx.ExternalReference,
+		PaymentMethod:     toPaymentMethodDTO(tx.PaymentMethod),
+		CreatedAt:         tx.CreatedAt,
+	}
+	if tx.Fee != 0 {
+		out.Fee = exactMoney(nil, tx.Fee, tx.Currency)
+	}
+	if tx.PlatformFee != 0 {
+		out.PlatformFee = exactMoney(nil, tx.PlatformFee, tx.Currency)
+	}
+	out.FeePolicy = tx.FeePolicy
+	if tx.RefundedTransactionID != nil {
+		out.RefundedTransactionID = tx.RefundedTransactionID.String()
+	}
+	return out
+}
+
+// accountResponse returns acc in the shape of the request's version.
+func accountResponse(c *fiber.Ctx, acc *dto.AccountRead) any {
+	if requestVersion(c) == V1 {
+		return acc
+	}
+	return ToAccountV2DTO(acc)
+}
+
+// accountsResponse returns accounts in the shape of the request's version.
+func accountsResponse(c *fiber.Ctx, accounts []*dto.AccountRead) any {
+	if requestVersion(c) == V1 {
+		return accounts
+	}
+	out := make([]*AccountV2DTO, 0, len(accounts))
+	for _, acc := range accounts {
+		out = append(out, ToAccountV2DTO(acc))
+	}
+	return out
+}
+
+// transactionsResponse returns txs in the shape of the request's version.
+func transactionsResponse(c *fiber.Ctx, txs []*dto.TransactionRead) any {
+	if requestVersion(c) == V1 {
+		out := make([]*TransactionDTO, 0, len(txs))
+		for _, tx := range txs {
+			out = append(out, ToTransactionDTO(tx))
+		}
+		return out
+	}
+	out := make([]*TransactionV2DTO, 0, len(txs))
+	for _, tx := range txs {
+		out = append(out, ToTransactionV2DTO(tx))
+	}
+	return out
+}
+
+// balanceResponse returns balance in the shape of the request's version.
+func balanceResponse(c *fiber.Ctx, balance BalanceResponse) any {
+	if requestVersion(c) == V1 {
+		return balance
+	}
+	return BalanceV2Response{
+		Balance:   balance.Money,
+		Formatted: balance.Amount,
+		Symbol:    balance.Symbol,
+		Display:   balance.Display,
+		Interest:  balance.Interest,
+	}
+}
+
+// aggregatedBalanceResponse totals the balances of accounts per currency in
+// the shape of the request's version.
+func aggregatedBalanceResponse(c *fiber.Ctx, accounts []*dto.AccountRead) (any, error) {
+	if requestVersion(c) == V1 {
+		totals := make(map[string]float64)
+		for _, acc := range accounts {
+			if acc == nil {
+				continue
+			}
+			curr := strings.ToUpper(strings.TrimSpace(acc.Currency))
+			if curr == "" {
+				curr = "UNKNOWN"
+			}
+			totals[curr] += acc.Balance
+		}
+		return AggregatedBalanceResponse{Totals: totals}, nil
+	}
+
+	totals := make(map[string]*money.Money)
+	for _, acc := range accounts {
+		if acc == nil {
+			continue
+		}
+		balance := exactMoney(acc.BalanceMoney, acc.Balance, acc.Currency)
+		if balance == nil {
+			return nil, fmt.Errorf("invalid balance of account %s", acc.ID)
+		}
+		curr := balance.Currency().String()
+		if totals[curr] == nil {
+			totals[curr] = balance
+			continue
+		}
+		sum, err := totals[curr].Add(balance)
+		if err != nil {
+			return nil, err
+		}
+		totals[curr] = sum
+	}
+	out := make([]*money.Money, 0, len(totals))
+	for _, total := range totals {
+		out = append(out, total)
+	}
+	slices.SortFunc(out, func(a, b *money.Money) int {
+		return cmp.Compare(a.Currency().String(), b.Currency().String())
+	})
+	return AggregatedBalanceV2Response{Totals: out}, nil
+}
+
+// exactMoney returns exact, or amount in currency if exact is nil. It
+// returns nil if amount is not a valid amount of currency.
+func exactMoney(exact *money.Money, amount float64, currency string) *money.Money {
+	if exact != nil {
+		return exact
+	}
+	m, err := money.New(amount, money.Code(currency))
+	if err != nil {
+		return nil
+	}
+	return m
+}
+
+// toInterestDTO returns the interest of acc, or nil if it is not a savings
+// account and has never accrued interest.
+func toInterestDTO(acc *dto.AccountRead) *InterestDTO {
+	accrued := acc.InterestAccrued
+	if acc.Type != accountdomain.TypeSavings.String() &&
+		(accrued == nil || accrued.IsZero()) {
+		return nil
+	}
+	if accrued == nil {
+		accrued = exactMoney(nil, 0, acc.Currency)
+	}
+	out := &InterestDTO{APY: acc.InterestAPY, Accrued: accrued}
+	if acc.InterestAccruedOn != nil {
+		out.AccruedOn = acc.InterestAccruedOn.Format(time.DateOnly)
+	}
+	return out
+}
+
+// transactionLabel returns the description of tx, or a display name for its
+// kind if it has none.
+func transactionLabel(tx *dto.TransactionRead) string {
+	if tx.Description != "" {
+		return tx.Description
+	}
+	switch strings.ToLower(tx.MoneySource) {
+	case "deposit", "stripe":
+		return "Deposit"
+	case "withdraw", "withdraw_hold":
+		return "Withdrawal"
+	case "transfer":
+		if tx.Amount < 0 {
+			return "Transfer out"
+		}
+		return "Transfer in"
+	case "refund":
+		return "Refund"
+	case "reversal":
+		return "Reversal"
+	case "import":
+		return "Imported transaction"
+	}
+	return "Transaction"
+}
@@ -0,0 +1,93 @@
+package webapi_test
+
+import (
+	"log/slog"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/amirasaad/fintech/infra/eventbus"
+	exchangerateapi "github.com/amirasaad/fintech/infra/provider/exchangerateapi"
+	mockpayment "github.com/amirasaad/fintech/infra/provider/mockpayment"
+	"github.com/amirasaad/fintech/pkg/app"
+	"github.com/amirasaad/fintech/pkg/config"
+	"github.com/amirasaad/fintech/webapi"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupPrefixedApp(api *config.API) *fiber.App {
+	cfg := &config.App{
+		API:       api,
+		RateLimit: &config.RateLimit{MaxRequests: 100},
+		Auth: &config.Auth{
+			Jwt: &config.Jwt{Secret: "secret"},
+		},
+	}
+	return webapi.SetupApp(app.New(&app.Deps{
+		EventBus: eventbus.NewWithMemory(slog.Default()),
+		ExchangeRateProvider: exchangerateapi.NewExchangeRateAPIProvider(
+			&config.ExchangeRateApi{},
+			slog.Default(),
+		),
+		PaymentProvider: mockpayment.NewMockPaymentProvider(),
+		Logger:          slog.Default(),
+	}, cfg))
+}
+
+func TestAPIPrefix(t *testing.T) {
+	tests := []struct {
+		name   string
+		api    *config.API
+		method string
+		path   string
+		want   int
+	}{
+		{"prefixed route requires a token", &config.API{Prefix: "/api/v1", OpsAtRoot: true},
+			fiber.MethodGet, "/api/v1/accounts", fiber.StatusUnauthorized},
+		{"health check at root", &config.API{Prefix: "/api/v1", OpsAtRoot: true},
+			fiber.MethodGet, "/", fiber.StatusOK},
+		{"metrics at root", &config.API{Prefix: "/api/v1", OpsAtRoot: true},
+			fiber.MethodGet, "/metrics", fiber.StatusOK},
+		{"health check under prefix", &config.API{Prefix: "/api/v1"},
+			fiber.MethodGet, "/api/v1", fiber.StatusOK},
+		{"metrics under prefix", &config.API{Prefix: "/api/v1"},
+			fiber.MethodGet, "/api/v1/metrics", fiber.StatusOK},
+		{"no prefix", nil,
+			fiber.MethodGet, "/accounts", fiber.StatusUnauthorized},
+		{"versioned route", nil,
+			fiber.MethodGet, "/v2/accounts", fiber.StatusUnauthorized},
+		{"versioned route under prefix", &config.API{Prefix: "/api"},
+			fiber.MethodGet, "/api/v1/accounts", fiber.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := setupPrefixedApp(tt.api)
+			resp, err := app.Test(httptest.NewRequest(tt.method, tt.path, nil))
+			require.NoError(t, err)
+			defer resp.Body.Close() //nolint: errcheck
+			assert.Equal(t, tt.want, resp.StatusCode)
+		})
+	}
+}
+
+func TestAPIPrefix_RoutesMoved(t *testing.T) {
+	paths := func(app *fiber.App) map[string]bool {
+		registered := map[string]bool{}
+		for _, route := range app.GetRoutes() {
+			registered[route.Path] = true
+		}
+		return registered
+	}
+
+	registered := paths(setupPrefixedApp(&config.API{Prefix: "/api/v1", OpsAtRoot: true}))
+	assert.True(t, registered["/api/v1/account/:ref/deposit"])
+	assert.False(t, registered["/account/:ref/deposit"])
+	assert.True(t, registered["/metrics"])
+	// The Stripe webhook keeps its path.
+	assert.True(t, registered["/api/v1/webhooks/stripe"])
+
+	registered = paths(setupPrefixedApp(&config.API{Prefix: "/api/v1"}))
+	assert.True(t, registered["/api/v1/metrics"])
+	assert.False(t, registered["/metrics"])
+}
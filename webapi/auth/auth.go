@@ -8,7 +8,7 @@ import (
 
 // Routes registers HTTP routes for authentication operations.
 // Sets up endpoints for user login and authentication.
-func Routes(app *fiber.App, authSvc *authsvc.Service) {
+func Routes(app fiber.Router, authSvc *authsvc.Service) {
 	app.Post("/auth/login", Login(authSvc))
 }
 
@@ -31,7 +31,7 @@ func Login(authSvc *authsvc.Service) fiber.Handler {
 		if input == nil {
 			return err // Error already written by BindAndValidate
 		}
-		user, err := authSvc.Login(c.Context(), input.Identity, input.Password)
+		user, err := authSvc.Login(c.UserContext(), input.Identity, input.Password)
 		if err != nil {
 			// Check if it's an unauthorized error
 			if err.Error() == "user unauthorized" {
@@ -58,7 +58,7 @@ func Login(authSvc *authsvc.Service) fiber.Handler {
 				fiber.StatusUnauthorized,
 			)
 		}
-		token, err := authSvc.GenerateToken(c.Context(), user)
+		token, err := authSvc.GenerateToken(c.UserContext(), user)
 		if err != nil {
 			return common.ProblemDetailsJSON(
 				c,
@@ -13,7 +13,7 @@ import (
 
 // Routes registers HTTP routes for checkout-related operations.
 func Routes(
-	app *fiber.App,
+	app fiber.Router,
 	checkoutSvc *checkout.Service,
 	authSvc *authsvc.Service,
 	cfg *config.App,
@@ -50,7 +50,7 @@ func GetPendingSessions(checkoutSvc *checkout.Service, authSvc *authsvc.Service)
 			return common.ProblemDetailsJSON(c, "Invalid user ID", err)
 		}
 
-		sessions, err := checkoutSvc.GetSessionsByUserID(c.Context(), userID)
+		sessions, err := checkoutSvc.GetSessionsByUserID(c.UserContext(), userID)
 		if err != nil {
 			log.Errorf("Failed to get pending sessions: %v", err)
 			return common.ProblemDetailsJSON(c, "Failed to get pending sessions", err)
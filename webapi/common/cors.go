@@ -0,0 +1,27 @@
+package common
+
+import (
+	"strings"
+
+	"github.com/amirasaad/fintech/pkg/config"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/cors"
+)
+
+// CORS answers preflight requests and sets the CORS response headers for
+// the configured origins. Without configured origins it sets no headers, so
+// browsers keep refusing cross-origin requests.
+func CORS(cfg *config.CORS) fiber.Handler {
+	if cfg == nil || len(cfg.AllowOrigins) == 0 {
+		return func(c *fiber.Ctx) error {
+			return c.Next()
+		}
+	}
+	return cors.New(cors.Config{
+		AllowOrigins:     strings.Join(cfg.AllowOrigins, ","),
+		AllowMethods:     strings.Join(cfg.AllowMethods, ","),
+		AllowHeaders:     strings.Join(cfg.AllowHeaders, ","),
+		AllowCredentials: cfg.AllowCredentials,
+		MaxAge:           int(cfg.MaxAge.Seconds()),
+	})
+}
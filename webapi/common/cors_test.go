@@ -0,0 +1,64 @@
+package common
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/amirasaad/fintech/pkg/config"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCORS(t *testing.T) {
+	newApp := func(cfg *config.CORS) *fiber.App {
+		app := fiber.New()
+		app.Use(CORS(cfg))
+		app.Post("/api/v1/account/:id/deposit", func(c *fiber.Ctx) error {
+			return c.SendStatus(fiber.StatusAccepted)
+		})
+		return app
+	}
+	preflight := func(origin string) *http.Response {
+		req := httptest.NewRequest(fiber.MethodOptions, "/api/v1/account/1/deposit", nil)
+		req.Header.Set(fiber.HeaderOrigin, origin)
+		req.Header.Set(fiber.HeaderAccessControlRequestMethod, fiber.MethodPost)
+		req.Header.Set(fiber.HeaderAccessControlRequestHeaders, "Authorization,Content-Type")
+		resp, err := newApp(&config.CORS{
+			AllowOrigins:     []string{"https://dashboard.example.com"},
+			AllowMethods:     []string{"GET", "POST"},
+			AllowHeaders:     []string{"Content-Type", "Authorization"},
+			AllowCredentials: true,
+			MaxAge:           10 * time.Minute,
+		}).Test(req)
+		require.NoError(t, err)
+		return resp
+	}
+
+	t.Run("answers preflight requests from allowed origins", func(t *testing.T) {
+		resp := preflight("https://dashboard.example.com")
+		assert.Equal(t, fiber.StatusNoContent, resp.StatusCode)
+		h := resp.Header
+		assert.Equal(t, "https://dashboard.example.com", h.Get(fiber.HeaderAccessControlAllowOrigin))
+		assert.Equal(t, "true", h.Get(fiber.HeaderAccessControlAllowCredentials))
+		assert.Contains(t, h.Get(fiber.HeaderAccessControlAllowHeaders), "Authorization")
+		assert.Contains(t, h.Get(fiber.HeaderAccessControlAllowMethods), fiber.MethodPost)
+		assert.Equal(t, "600", h.Get(fiber.HeaderAccessControlMaxAge))
+	})
+
+	t.Run("refuses other origins", func(t *testing.T) {
+		resp := preflight("https://evil.example.com")
+		assert.Empty(t, resp.Header.Get(fiber.HeaderAccessControlAllowOrigin))
+	})
+
+	t.Run("allows no origins by default", func(t *testing.T) {
+		req := httptest.NewRequest(fiber.MethodPost, "/api/v1/account/1/deposit", nil)
+		req.Header.Set(fiber.HeaderOrigin, "https://dashboard.example.com")
+		resp, err := newApp(nil).Test(req)
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusAccepted, resp.StatusCode)
+		assert.Empty(t, resp.Header.Get(fiber.HeaderAccessControlAllowOrigin))
+	})
+}
@@ -0,0 +1,32 @@
+package common
+
+import (
+	"context"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// MaintenanceChecker reports whether money movements are allowed.
+// *maintenance.Service satisfies it.
+type MaintenanceChecker interface {
+	CheckWrites(ctx context.Context) error
+}
+
+// RejectDuringMaintenance answers requests with 503 Service Unavailable while
+// maintenance mode is on. It guards the routes that move money: deposits,
+// withdrawals, transfers, their approvals, refunds, captures, transaction
+// imports and reprocessing. Routes without it, including reads, are
+// unaffected.
+func RejectDuringMaintenance(checker MaintenanceChecker) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if err := checker.CheckWrites(c.UserContext()); err != nil {
+			return ProblemDetailsJSON(
+				c,
+				"Service under maintenance",
+				err,
+				"Money movements are paused for maintenance; try again later",
+			)
+		}
+		return c.Next()
+	}
+}
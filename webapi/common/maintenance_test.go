@@ -0,0 +1,52 @@
+package common
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/amirasaad/fintech/pkg/service/maintenance"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type checkerFunc func(ctx context.Context) error
+
+func (f checkerFunc) CheckWrites(ctx context.Context) error { return f(ctx) }
+
+func TestRejectDuringMaintenance(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		err    error
+		status int
+	}{
+		{"open", nil, fiber.StatusOK},
+		{"under maintenance", maintenance.ErrUnderMaintenance, fiber.StatusServiceUnavailable},
+		{
+			"state unreadable",
+			fmt.Errorf("%w: connection refused", maintenance.ErrUnderMaintenance),
+			fiber.StatusServiceUnavailable,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			app := fiber.New()
+			app.Post("/deposit",
+				RejectDuringMaintenance(checkerFunc(func(context.Context) error { return tc.err })),
+				func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusOK) },
+			)
+			resp, err := app.Test(httptest.NewRequest(fiber.MethodPost, "/deposit", nil), -1)
+			require.NoError(t, err)
+			assert.Equal(t, tc.status, resp.StatusCode)
+			if tc.err == nil {
+				return
+			}
+			var problem ProblemDetails
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&problem))
+			assert.Equal(t, "under_maintenance", problem.Code)
+			assert.NotContains(t, problem.Detail, "connection refused")
+		})
+	}
+}
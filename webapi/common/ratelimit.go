@@ -0,0 +1,63 @@
+package common
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/amirasaad/fintech/pkg/config"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/limiter"
+)
+
+// RateLimit limits each client to cfg.MaxRequests requests per cfg.Window.
+// A rejected request gets 429 with a Retry-After header giving the seconds
+// until the client's window resets, and a problem detail stating the limit.
+// Without a config it limits nothing.
+func RateLimit(cfg *config.RateLimit) fiber.Handler {
+	if cfg == nil {
+		return func(c *fiber.Ctx) error {
+			return c.Next()
+		}
+	}
+	maxRequests, window := cfg.MaxRequests, cfg.Window
+	if maxRequests <= 0 {
+		maxRequests = limiter.ConfigDefault.Max
+	}
+	if window <= 0 {
+		window = limiter.ConfigDefault.Expiration
+	}
+	return limiter.New(limiter.Config{
+		Max:          maxRequests,
+		Expiration:   window,
+		KeyGenerator: clientKey,
+		LimitReached: func(c *fiber.Ctx) error {
+			// The limiter sets Retry-After to the whole seconds left in the
+			// window, which is 0 in its last second.
+			retryAfter, err := strconv.Atoi(c.GetRespHeader(fiber.HeaderRetryAfter))
+			if err != nil || retryAfter < 1 {
+				retryAfter = 1
+			}
+			c.Set(fiber.HeaderRetryAfter, strconv.Itoa(retryAfter))
+			return ProblemDetailsJSON(
+				c,
+				"Too Many Requests",
+				nil,
+				fmt.Sprintf(
+					"rate limit of %d requests per %s exceeded; retry after %d seconds",
+					maxRequests, window, retryAfter,
+				),
+				fiber.StatusTooManyRequests,
+			)
+		},
+	})
+}
+
+// clientKey identifies the client of a request by its IP. Behind a trusted
+// proxy, configured with Fiber's TrustedProxies and ProxyHeader, that is the
+// address the proxy reports; forwarding headers sent by anyone else are
+// ignored. The key is copied: Fiber reuses the memory of header values for
+// later requests, and the limiter keeps the key.
+func clientKey(c *fiber.Ctx) string {
+	return strings.Clone(c.IP())
+}
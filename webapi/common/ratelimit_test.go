@@ -0,0 +1,86 @@
+package common
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/amirasaad/fintech/pkg/config"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// behindProxy is the config of an app whose requests in tests, which come
+// from 0.0.0.0, are forwarded by a trusted proxy.
+var behindProxy = fiber.Config{
+	EnableTrustedProxyCheck: true,
+	EnableIPValidation:      true,
+	TrustedProxies:          []string{"0.0.0.0"},
+	ProxyHeader:             fiber.HeaderXForwardedFor,
+}
+
+func TestRateLimit(t *testing.T) {
+	app := fiber.New(behindProxy)
+	app.Use(RateLimit(&config.RateLimit{MaxRequests: 2, Window: 30 * time.Second}))
+	app.Get("/account/:id/balance", func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+	get := func(forwardedFor string) *http.Response {
+		req := httptest.NewRequest(fiber.MethodGet, "/account/1/balance", nil)
+		req.Header.Set("X-Forwarded-For", forwardedFor)
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		return resp
+	}
+
+	for range 2 {
+		assert.Equal(t, fiber.StatusOK, get("203.0.113.1, 10.0.0.1").StatusCode)
+	}
+
+	resp := get("203.0.113.1")
+	assert.Equal(t, fiber.StatusTooManyRequests, resp.StatusCode)
+	retryAfter, err := strconv.Atoi(resp.Header.Get(fiber.HeaderRetryAfter))
+	require.NoError(t, err)
+	assert.InDelta(t, 30, retryAfter, 2)
+	var pd ProblemDetails
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&pd))
+	assert.Equal(t, fiber.StatusTooManyRequests, pd.Status)
+	assert.Contains(t, pd.Detail, "2 requests per 30s")
+
+	// Other clients have their own window.
+	assert.Equal(t, fiber.StatusOK, get("203.0.113.2").StatusCode)
+
+	t.Run("ignores forwarding headers from untrusted clients", func(t *testing.T) {
+		app := fiber.New(fiber.Config{EnableTrustedProxyCheck: true})
+		app.Use(RateLimit(&config.RateLimit{MaxRequests: 2, Window: 30 * time.Second}))
+		app.Get("/", func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusOK) })
+		statuses := make([]int, 0, 3)
+		for _, ip := range []string{"203.0.113.1", "203.0.113.2", "203.0.113.3"} {
+			req := httptest.NewRequest(fiber.MethodGet, "/", nil)
+			req.Header.Set(fiber.HeaderXForwardedFor, ip)
+			req.Header.Set("X-Real-IP", ip)
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			statuses = append(statuses, resp.StatusCode)
+		}
+		assert.Equal(t,
+			[]int{fiber.StatusOK, fiber.StatusOK, fiber.StatusTooManyRequests},
+			statuses,
+		)
+	})
+
+	t.Run("limits nothing without a config", func(t *testing.T) {
+		app := fiber.New()
+		app.Use(RateLimit(nil))
+		app.Get("/", func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusOK) })
+		for range 10 {
+			resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+			require.NoError(t, err)
+			assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+		}
+	})
+}
@@ -0,0 +1,51 @@
+package common
+
+import (
+	"context"
+	"errors"
+
+	"github.com/amirasaad/fintech/pkg/config"
+	"github.com/gofiber/fiber/v2"
+)
+
+// RequestTimeout bounds each request by the timeout configured for its path.
+// The deadline is set on the request's user context, which handlers pass to
+// the services, so database and provider calls give up once it passes.
+//
+// A request that failed after its deadline is answered with 504 Gateway
+// Timeout instead of the handler's error. Successful responses are kept, so
+// work that completed just after the deadline is still reported as done.
+func RequestTimeout(cfg *config.RequestTimeout) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		timeout := cfg.For(c.Path())
+		if timeout <= 0 {
+			return c.Next()
+		}
+		ctx, cancel := context.WithTimeout(c.UserContext(), timeout)
+		defer cancel()
+		c.SetUserContext(ctx)
+
+		err := c.Next()
+		if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return err
+		}
+		if err == nil && c.Response().StatusCode() < fiber.StatusInternalServerError {
+			return nil
+		}
+		return ProblemDetailsJSON(
+			c,
+			"Request timed out",
+			context.DeadlineExceeded,
+			"Request did not complete within "+timeout.String(),
+			fiber.StatusGatewayTimeout,
+		)
+	}
+}
+
+// DetachedContext returns the request's user context without its deadline.
+// Deposits, withdrawals, transfers, refunds and payment webhooks use it to run
+// their flows: abandoning a flow halfway because the request timed out would
+// leave it inconsistent, so it runs to completion.
+func DetachedContext(c *fiber.Ctx) context.Context {
+	return context.WithoutCancel(c.UserContext())
+}
@@ -0,0 +1,61 @@
+package common
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/amirasaad/fintech/pkg/config"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestTimeout(t *testing.T) {
+	app := fiber.New()
+	app.Use(RequestTimeout(&config.RequestTimeout{
+		Default:   20 * time.Millisecond,
+		Overrides: map[string]time.Duration{"/unbounded": 0},
+	}))
+	// waitForDeadline blocks like a slow query that honors its context.
+	waitForDeadline := func(c *fiber.Ctx) error {
+		select {
+		case <-c.UserContext().Done():
+			return c.UserContext().Err()
+		case <-time.After(time.Second):
+			return c.SendStatus(fiber.StatusOK)
+		}
+	}
+	app.Get("/slow", waitForDeadline)
+	app.Get("/slow-failure", func(c *fiber.Ctx) error {
+		err := waitForDeadline(c)
+		return ProblemDetailsJSON(c, "Failed", err)
+	})
+	app.Post("/accepted", func(c *fiber.Ctx) error {
+		<-c.UserContext().Done()
+		// The flow was started on a detached context and is not cancelled.
+		require.NoError(t, DetachedContext(c).Err())
+		return c.SendStatus(fiber.StatusAccepted)
+	})
+	app.Get("/unbounded", func(c *fiber.Ctx) error {
+		_, ok := c.UserContext().Deadline()
+		assert.False(t, ok)
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	for _, tc := range []struct {
+		method, path string
+		status       int
+	}{
+		{fiber.MethodGet, "/slow", fiber.StatusGatewayTimeout},
+		{fiber.MethodGet, "/slow-failure", fiber.StatusGatewayTimeout},
+		{fiber.MethodPost, "/accepted", fiber.StatusAccepted},
+		{fiber.MethodGet, "/unbounded", fiber.StatusOK},
+	} {
+		t.Run(tc.path, func(t *testing.T) {
+			resp, err := app.Test(httptest.NewRequest(tc.method, tc.path, nil), -1)
+			require.NoError(t, err)
+			assert.Equal(t, tc.status, resp.StatusCode)
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package common
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/amirasaad/fintech/webapi"
+
+// Tracing starts a server span for each request, continuing the trace of the
+// caller if the request carries a traceparent header. The span is set on the
+// request's user context, so the services, the event bus and the providers
+// called while handling the request record their spans in the same trace.
+// The trace ID is returned in the X-Trace-ID response header.
+func Tracing() fiber.Handler {
+	tracer := otel.Tracer(tracerName)
+	return func(c *fiber.Ctx) error {
+		ctx := otel.GetTextMapPropagator().Extract(c.UserContext(), headerCarrier{c})
+		// Fiber reuses the memory behind c.Method() once the request is done,
+		// so it is copied before being kept as an attribute.
+		method := strings.Clone(c.Method())
+		ctx, span := tracer.Start(ctx, method,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				attribute.String("http.request.method", method),
+				attribute.String("url.path", strings.Clone(c.Path())),
+			),
+		)
+		defer span.End()
+		c.SetUserContext(ctx)
+		if span.SpanContext().HasTraceID() {
+			c.Set("X-Trace-ID", span.SpanContext().TraceID().String())
+		}
+
+		err := c.Next()
+
+		status := ResponseStatus(c, err)
+		span.SetAttributes(attribute.Int("http.response.status_code", status))
+		if route := MatchedRoute(c, err); route != "" {
+			span.SetName(method + " " + route)
+			span.SetAttributes(attribute.String("http.route", route))
+		}
+		if status >= fiber.StatusInternalServerError {
+			span.SetStatus(codes.Error, fiber.ErrInternalServerError.Message)
+			if err != nil {
+				span.RecordError(err)
+			}
+		}
+		return err
+	}
+}
+
+// ResponseStatus returns the status code of the response to a request whose
+// handlers returned err. A returned error is only turned into a response by
+// the error handler, after the middleware has returned.
+func ResponseStatus(c *fiber.Ctx, err error) int {
+	if err == nil {
+		return c.Response().StatusCode()
+	}
+	var fe *fiber.Error
+	if errors.As(err, &fe) {
+		return fe.Code
+	}
+	return fiber.StatusInternalServerError
+}
+
+// MatchedRoute returns the template of the route that handled the request,
+// e.g. /account/:ref/deposit, or "" if no route matched. Middleware calls it
+// after c.Next() with the error it returned.
+func MatchedRoute(c *fiber.Ctx, err error) string {
+	// When no route matches, Fiber's router returns 404 "Cannot GET /path" or
+	// 405 and c.Route() is the last middleware the request passed through.
+	var fe *fiber.Error
+	if errors.As(err, &fe) && (fe.Code == fiber.StatusMethodNotAllowed ||
+		fe.Code == fiber.StatusNotFound && strings.HasPrefix(fe.Message, "Cannot ")) {
+		return ""
+	}
+	return c.Route().Path
+}
+
+// headerCarrier reads trace context from the request headers.
+type headerCarrier struct{ c *fiber.Ctx }
+
+func (h headerCarrier) Get(key string) string { return h.c.Get(key) }
+
+// Set is not used when extracting.
+func (h headerCarrier) Set(string, string) {}
+
+func (h headerCarrier) Keys() []string {
+	var keys []string
+	h.c.Request().Header.VisitAll(func(key, _ []byte) {
+		keys = append(keys, string(key))
+	})
+	return keys
+}
+
+var _ propagation.TextMapCarrier = headerCarrier{}
@@ -0,0 +1,54 @@
+package common
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTracingContinuesCallerTrace(t *testing.T) {
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	app := fiber.New()
+	app.Use(Tracing())
+	var got trace.SpanContext
+	app.Get("/account/:ref", func(c *fiber.Ctx) error {
+		got = trace.SpanContextFromContext(c.UserContext())
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	req := httptest.NewRequest(fiber.MethodGet, "/account/abc", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	// Without a tracer provider no span is recorded, but the caller's trace
+	// is passed on to the handler and returned to the caller.
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", got.TraceID().String())
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", resp.Header.Get("X-Trace-ID"))
+}
+
+func TestMatchedRoute(t *testing.T) {
+	app := fiber.New()
+	var route string
+	app.Use(func(c *fiber.Ctx) error {
+		err := c.Next()
+		route = MatchedRoute(c, err)
+		return err
+	})
+	app.Get("/account/:ref", func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusOK) })
+
+	_, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/account/abc", nil))
+	require.NoError(t, err)
+	assert.Equal(t, "/account/:ref", route)
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/missing", nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+	assert.Empty(t, route)
+}
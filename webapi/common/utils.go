@@ -4,15 +4,15 @@ import (
 	"errors"
 
 	"github.com/amirasaad/fintech/pkg/domain"
-	"github.com/amirasaad/fintech/pkg/domain/account"
-	"github.com/amirasaad/fintech/pkg/domain/user"
-	"github.com/amirasaad/fintech/pkg/money"
-	"github.com/amirasaad/fintech/pkg/provider/exchange"
-	"github.com/go-playground/validator/v10"
+	"github.com/amirasaad/fintech/pkg/errcode"
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/log"
 )
 
+// HeaderIdempotencyKey is the request header clients set to make a retried
+// deposit, withdrawal or transfer take effect only once.
+const HeaderIdempotencyKey = "Idempotency-Key"
+
 // Response defines the standard API response structure for success cases.
 type Response struct {
 	Status  int    `json:"status"`         // HTTP status code
@@ -27,11 +27,13 @@ type ProblemDetails struct {
 	Status   int    `json:"status"`             // HTTP status code
 	Detail   string `json:"detail,omitempty"`   // Human-readable explanation
 	Instance string `json:"instance,omitempty"` // URI reference
+	Code     string `json:"code,omitempty"`     // Stable error code, see package errcode
 	Errors   any    `json:"errors,omitempty"`   // Optional: additional error details
 }
 
-// ProblemDetailsJSON writes a problem+json error response with a status
-// code inferred from the error (if present).
+// ProblemDetailsJSON writes a problem+json error response with the status
+// code and error code registered for the error in package errcode (if
+// present); unregistered errors are a 500.
 // The title is set to the error message (if error),
 // and detail can be a string, error, or structured object.
 // Optionally, a status code can be provided as the last argument (int)
@@ -46,9 +48,11 @@ func ProblemDetailsJSON(
 	pdDetail := ""
 	var pdErrors any
 	var customStatus *int
+	code := ""
 
 	if err != nil {
-		status = errorToStatusCode(err)
+		status = errcode.Status(err)
+		code = errcode.Code(err)
 		// Use generic message for duplicate key errors
 		if errors.Is(err, domain.ErrAlreadyExists) {
 			pdDetail = "Unprocessable entity"
@@ -71,6 +75,9 @@ func ProblemDetailsJSON(
 	}
 	// Use custom status if provided
 	if customStatus != nil {
+		if code == errcode.Internal && *customStatus != status {
+			code = ""
+		}
 		status = *customStatus
 	}
 	pd := ProblemDetails{
@@ -80,6 +87,7 @@ func ProblemDetailsJSON(
 		Detail:   pdDetail,
 		Errors:   pdErrors,
 		Instance: c.Path(),
+		Code:     code,
 	}
 	c.Set(fiber.HeaderContentType, "application/problem+json")
 	if err := c.Status(status).JSON(pd); err != nil {
@@ -90,6 +98,7 @@ func ProblemDetailsJSON(
 
 // BindAndValidate parses the request body and validates it using go-playground/validator.
 // Returns a pointer to the struct (populated), or writes an error response and returns nil.
+// Validation failures list a FieldError per failed rule in the problem's errors array.
 func BindAndValidate[T any](c *fiber.Ctx) (*T, error) {
 	var input T
 	if err := c.BodyParser(&input); err != nil {
@@ -102,22 +111,15 @@ func BindAndValidate[T any](c *fiber.Ctx) (*T, error) {
 		) //nolint:errcheck
 	}
 
-	validate := validator.New()
 	if err := validate.Struct(input); err != nil {
-		if ve, ok := err.(validator.ValidationErrors); ok {
-			details := make(map[string]string)
-			for _, fe := range ve {
-				field := fe.Field()
-				msg := fe.Tag()
-				details[field] = msg
-			}
+		if fields := ValidationFieldErrors(input, err); fields != nil {
 			//revive:disable
 			return nil, ProblemDetailsJSON( //nolint:errcheck
 				c,
 				"Validation failed",
 				nil,
 				"Request validation failed",
-				details,
+				fields,
 				fiber.StatusBadRequest,
 			)
 			//revive:enable
@@ -145,39 +147,3 @@ func SuccessResponseJSON(c *fiber.Ctx, status int, message string, data any) err
 		Data:    data,
 	})
 }
-
-// errorToStatusCode maps domain errors to appropriate HTTP status codes.
-func errorToStatusCode(err error) int {
-	switch {
-	// Domain errors
-	case errors.Is(err, domain.ErrAlreadyExists):
-		return fiber.StatusUnprocessableEntity
-	// Account errors
-	case errors.Is(err, account.ErrAccountNotFound):
-		return fiber.StatusNotFound
-	case errors.Is(err, account.ErrDepositAmountExceedsMaxSafeInt):
-		return fiber.StatusBadRequest
-	case errors.Is(err, account.ErrTransactionAmountMustBePositive):
-		return fiber.StatusBadRequest
-	case errors.Is(err, account.ErrInsufficientFunds):
-		return fiber.StatusUnprocessableEntity
-	// Common errors
-	case errors.Is(err, money.ErrInvalidCurrency):
-		return fiber.StatusBadRequest
-	case errors.Is(err, money.ErrAmountExceedsMaxSafeInt):
-		return fiber.StatusBadRequest
-	case errors.Is(err, exchange.ErrUnsupportedPair):
-		return fiber.StatusUnprocessableEntity
-	// Money/currency conversion errors
-	case errors.Is(err, exchange.ErrProviderUnavailable):
-		return fiber.StatusServiceUnavailable
-
-	// User errors
-	case errors.Is(err, user.ErrUserNotFound):
-		return fiber.StatusNotFound
-	case errors.Is(err, user.ErrUserUnauthorized):
-		return fiber.StatusUnauthorized
-	default:
-		return fiber.StatusInternalServerError
-	}
-}
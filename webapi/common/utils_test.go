@@ -0,0 +1,55 @@
+package common
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/amirasaad/fintech/pkg/domain/account"
+	"github.com/amirasaad/fintech/pkg/errcode"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProblemDetailsJSON(t *testing.T) {
+	app := fiber.New()
+	app.Get("/registered", func(c *fiber.Ctx) error {
+		err := fmt.Errorf("withdraw: %w", account.ErrInsufficientFunds)
+		return ProblemDetailsJSON(c, "Withdrawal failed", err)
+	})
+	app.Get("/unregistered", func(c *fiber.Ctx) error {
+		return ProblemDetailsJSON(c, "Failed", errors.New("boom"))
+	})
+	app.Get("/override", func(c *fiber.Ctx) error {
+		return ProblemDetailsJSON(c, "Bad input", errors.New("boom"), fiber.StatusBadRequest)
+	})
+	app.Get("/no-error", func(c *fiber.Ctx) error {
+		return ProblemDetailsJSON(c, "Invalid", nil, "missing id")
+	})
+
+	tests := []struct {
+		path   string
+		status int
+		code   string
+	}{
+		{"/registered", fiber.StatusUnprocessableEntity, "insufficient_funds"},
+		{"/unregistered", fiber.StatusInternalServerError, errcode.Internal},
+		{"/override", fiber.StatusBadRequest, ""},
+		{"/no-error", fiber.StatusBadRequest, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, tt.path, nil))
+			require.NoError(t, err)
+			defer resp.Body.Close() //nolint:errcheck
+			assert.Equal(t, tt.status, resp.StatusCode)
+			var pd ProblemDetails
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&pd))
+			assert.Equal(t, tt.status, pd.Status)
+			assert.Equal(t, tt.code, pd.Code)
+		})
+	}
+}
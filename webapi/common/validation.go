@@ -0,0 +1,139 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// FieldError describes one failed validation rule of a request field. It is
+// returned in the errors array of a "Validation failed" problem response.
+type FieldError struct {
+	// Field is the JSON path of the field, e.g. external_target.routing_number.
+	Field string `json:"field"`
+	// Rule is the validator tag that failed, e.g. required or max.
+	Rule string `json:"rule"`
+	// Param is the rule's parameter, e.g. 64 for max=64.
+	Param   string `json:"param,omitempty"`
+	Message string `json:"message"`
+}
+
+// validate is shared so struct metadata is parsed once per type.
+var validate = newValidator()
+
+// newValidator returns a validator that reports fields by their JSON names.
+func newValidator() *validator.Validate {
+	v := validator.New()
+	v.RegisterTagNameFunc(func(f reflect.StructField) string {
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "" || name == "-" {
+			return f.Name
+		}
+		return name
+	})
+	return v
+}
+
+// ValidationFieldErrors converts validator errors for input into FieldErrors.
+// It returns nil if err is not a validator.ValidationErrors.
+func ValidationFieldErrors(input any, err error) []FieldError {
+	var ve validator.ValidationErrors
+	if !errors.As(err, &ve) {
+		return nil
+	}
+	out := make([]FieldError, 0, len(ve))
+	for _, fe := range ve {
+		out = append(out, FieldError{
+			Field:   fieldPath(reflect.TypeOf(input), fe),
+			Rule:    fe.Tag(),
+			Param:   fe.Param(),
+			Message: fieldMessage(fe),
+		})
+	}
+	return out
+}
+
+// fieldPath builds the JSON path of fe, dropping the root struct and embedded
+// structs, whose fields JSON flattens into their parent.
+func fieldPath(t reflect.Type, fe validator.FieldError) string {
+	goNames := strings.Split(fe.StructNamespace(), ".")
+	jsonNames := strings.Split(fe.Namespace(), ".")
+	if len(goNames) != len(jsonNames) || len(goNames) < 2 {
+		return fe.Field()
+	}
+	var path []string
+	for i := 1; i < len(goNames); i++ {
+		for t != nil && (t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice ||
+			t.Kind() == reflect.Array || t.Kind() == reflect.Map) {
+			t = t.Elem()
+		}
+		name, _, _ := strings.Cut(goNames[i], "[")
+		var sf reflect.StructField
+		found := false
+		if t != nil && t.Kind() == reflect.Struct {
+			sf, found = t.FieldByName(name)
+		}
+		if found {
+			t = sf.Type
+			if sf.Anonymous {
+				continue
+			}
+		} else {
+			t = nil
+		}
+		path = append(path, jsonNames[i])
+	}
+	return strings.Join(path, ".")
+}
+
+// fieldMessage returns a human-readable message for a failed rule.
+func fieldMessage(fe validator.FieldError) string {
+	param := fe.Param()
+	isString := fe.Kind() == reflect.String
+	switch fe.Tag() {
+	case "required", "required_without", "required_with", "required_if":
+		return "is required"
+	case "excluded_with":
+		return "must not be set together with " + param
+	case "min":
+		if isString {
+			return fmt.Sprintf("must be at least %s characters long", param)
+		}
+		return "must be at least " + param
+	case "max":
+		if isString {
+			return fmt.Sprintf("must be at most %s characters long", param)
+		}
+		return "must be at most " + param
+	case "len":
+		if isString {
+			return fmt.Sprintf("must be exactly %s characters long", param)
+		}
+		return "must have length " + param
+	case "gt":
+		return "must be greater than " + param
+	case "gte":
+		return "must be greater than or equal to " + param
+	case "lt":
+		return "must be less than " + param
+	case "lte":
+		return "must be less than or equal to " + param
+	case "oneof":
+		return "must be one of " + strings.Join(strings.Fields(param), ", ")
+	case "uppercase":
+		return "must be uppercase"
+	case "alpha":
+		return "must contain only letters"
+	case "email":
+		return "must be a valid email address"
+	case "url":
+		return "must be a valid URL"
+	case "uuid", "uuid4":
+		return "must be a valid UUID"
+	default:
+		return fmt.Sprintf("failed the %s rule", fe.Tag())
+	}
+}
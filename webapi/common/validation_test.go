@@ -0,0 +1,33 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type embeddedTarget struct {
+	Account string `json:"account" validate:"required"`
+}
+
+type embeddingRequest struct {
+	Label string `json:"label" validate:"max=3"`
+	embeddedTarget
+	Items []embeddedTarget `json:"items" validate:"dive"`
+}
+
+func TestValidationFieldErrors(t *testing.T) {
+	input := embeddingRequest{Label: "long", Items: []embeddedTarget{{}}}
+	err := validate.Struct(input)
+	require.Error(t, err)
+
+	fields := ValidationFieldErrors(input, err)
+	assert.Equal(t, []FieldError{
+		{Field: "label", Rule: "max", Param: "3", Message: "must be at most 3 characters long"},
+		{Field: "account", Rule: "required", Message: "is required"},
+		{Field: "items[0].account", Rule: "required", Message: "is required"},
+	}, fields)
+
+	assert.Nil(t, ValidationFieldErrors(input, assert.AnError))
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/amirasaad/fintech/pkg/money"
 	authsvc "github.com/amirasaad/fintech/pkg/service/auth"
 	currencysvc "github.com/amirasaad/fintech/pkg/service/currency"
+	exchangesvc "github.com/amirasaad/fintech/pkg/service/exchange"
 	"github.com/amirasaad/fintech/webapi/common"
 	"github.com/gofiber/fiber/v2"
 )
@@ -17,6 +18,7 @@ import (
 func Routes(
 	r fiber.Router,
 	currencySvc *currencysvc.Service,
+	exchangeSvc *exchangesvc.Service,
 	authSvc *authsvc.Service,
 	cfg *config.App,
 ) {
@@ -31,6 +33,13 @@ func Routes(
 		"/supported",
 		ListSupportedCurrencies(currencySvc),
 	)
+	// Registered before /:code, which would match it.
+	if exchangeSvc != nil {
+		currencyGroup.Get(
+			"/rates",
+			ListRates(currencySvc, exchangeSvc),
+		)
+	}
 	currencyGroup.Get(
 		"/:code",
 		GetCurrency(currencySvc),
@@ -97,7 +106,7 @@ func ListCurrencies(
 	currencySvc *currencysvc.Service,
 ) fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		currencies, err := currencySvc.ListAll(c.Context())
+		currencies, err := currencySvc.ListAll(c.UserContext())
 		if err != nil {
 			return common.ProblemDetailsJSON(
 				c,
@@ -127,7 +136,7 @@ func ListSupportedCurrencies(
 	currencySvc *currencysvc.Service,
 ) fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		currencies, err := currencySvc.ListSupported(c.Context())
+		currencies, err := currencySvc.ListSupported(c.UserContext())
 		if err != nil {
 			return common.ProblemDetailsJSON(
 				c,
@@ -172,7 +181,7 @@ func GetCurrency(
 		}
 
 		// Validate currency code format
-		if err := currencySvc.ValidateCode(c.Context(), code); err != nil {
+		if err := currencySvc.ValidateCode(c.UserContext(), code); err != nil {
 			return common.ProblemDetailsJSON(
 				c,
 				"Invalid currency code",
@@ -182,7 +191,7 @@ func GetCurrency(
 			)
 		}
 
-		currency, err := currencySvc.Get(c.Context(), code)
+		currency, err := currencySvc.Get(c.UserContext(), code)
 		if err != nil {
 			return common.ProblemDetailsJSON(
 				c,
@@ -225,7 +234,7 @@ func CheckCurrencySupported(
 		}
 
 		// Validate currency code format
-		if err := currencySvc.ValidateCode(c.Context(), code); err != nil {
+		if err := currencySvc.ValidateCode(c.UserContext(), code); err != nil {
 			return common.ProblemDetailsJSON(
 				c,
 				"Invalid currency code",
@@ -235,7 +244,7 @@ func CheckCurrencySupported(
 			)
 		}
 
-		supported := currencySvc.IsSupported(c.Context(), code)
+		supported := currencySvc.IsSupported(c.UserContext(), code)
 		return common.SuccessResponseJSON(
 			c,
 			fiber.StatusOK,
@@ -271,7 +280,7 @@ func SearchCurrencies(
 			)
 		}
 
-		currencies, err := currencySvc.Search(c.Context(), query)
+		currencies, err := currencySvc.Search(c.UserContext(), query)
 		if err != nil {
 			return common.ProblemDetailsJSON(
 				c,
@@ -314,7 +323,7 @@ func SearchCurrenciesByRegion(
 			)
 		}
 
-		currencies, err := currencySvc.SearchByRegion(c.Context(), region)
+		currencies, err := currencySvc.SearchByRegion(c.UserContext(), region)
 		if err != nil {
 			return common.ProblemDetailsJSON(
 				c,
@@ -344,7 +353,7 @@ func GetCurrencyStatistics(
 	currencySvc *currencysvc.Service,
 ) fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		stats, err := currencySvc.GetStatistics(c.Context())
+		stats, err := currencySvc.GetStatistics(c.UserContext())
 		if err != nil {
 			return common.ProblemDetailsJSON(
 				c,
@@ -374,7 +383,7 @@ func GetDefaultCurrency(
 	currencySvc *currencysvc.Service,
 ) fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		defaultCurrency, err := currencySvc.GetDefault(c.Context())
+		defaultCurrency, err := currencySvc.GetDefault(c.UserContext())
 		if err != nil {
 			return common.ProblemDetailsJSON(
 				c,
@@ -414,7 +423,7 @@ func RegisterCurrency(
 		}
 
 		// Validate currency code format
-		if err = currencySvc.ValidateCode(c.Context(), input.Code); err != nil {
+		if err = currencySvc.ValidateCode(c.UserContext(), input.Code); err != nil {
 			return common.ProblemDetailsJSON(
 				c,
 				"Invalid currency code",
@@ -424,7 +433,7 @@ func RegisterCurrency(
 		}
 
 		// Check if currency already exists
-		if _, err := currencySvc.Get(c.Context(), input.Code); err == nil {
+		if _, err := currencySvc.Get(c.UserContext(), input.Code); err == nil {
 			return common.ProblemDetailsJSON(
 				c,
 				"Currency already exists",
@@ -443,7 +452,7 @@ func RegisterCurrency(
 			Region:   input.Region,
 			Active:   input.Active,
 		}
-		if err = currencySvc.Register(c.Context(), currEntity); err != nil {
+		if err = currencySvc.Register(c.UserContext(), currEntity); err != nil {
 			return common.ProblemDetailsJSON(
 				c,
 				"Failed to register currency",
@@ -491,7 +500,7 @@ func UnregisterCurrency(
 		}
 
 		// Validate currency code format
-		if err := currencySvc.ValidateCode(c.Context(), code); err != nil {
+		if err := currencySvc.ValidateCode(c.UserContext(), code); err != nil {
 			return common.ProblemDetailsJSON(
 				c,
 				"Invalid currency code",
@@ -499,7 +508,7 @@ func UnregisterCurrency(
 			)
 		}
 
-		if err := currencySvc.Unregister(c.Context(), code); err != nil {
+		if err := currencySvc.Unregister(c.UserContext(), code); err != nil {
 			if strings.Contains(err.Error(), "not found") {
 				return common.ProblemDetailsJSON(
 					c,
@@ -549,7 +558,7 @@ func ActivateCurrency(
 		}
 
 		// Validate currency code format
-		if err := currencySvc.ValidateCode(c.Context(), code); err != nil {
+		if err := currencySvc.ValidateCode(c.UserContext(), code); err != nil {
 			return common.ProblemDetailsJSON(
 				c,
 				"Invalid currency code",
@@ -557,7 +566,7 @@ func ActivateCurrency(
 			)
 		}
 
-		if err := currencySvc.Activate(c.Context(), code); err != nil {
+		if err := currencySvc.Activate(c.UserContext(), code); err != nil {
 			if strings.Contains(err.Error(), "not found") {
 				return common.ProblemDetailsJSON(
 					c,
@@ -607,7 +616,7 @@ func DeactivateCurrency(
 		}
 
 		// Validate currency code format
-		if err := currencySvc.ValidateCode(c.Context(), code); err != nil {
+		if err := currencySvc.ValidateCode(c.UserContext(), code); err != nil {
 			return common.ProblemDetailsJSON(
 				c,
 				"Invalid currency code",
@@ -615,7 +624,7 @@ func DeactivateCurrency(
 			)
 		}
 
-		if err := currencySvc.Deactivate(c.Context(), code); err != nil {
+		if err := currencySvc.Deactivate(c.UserContext(), code); err != nil {
 			if strings.Contains(err.Error(), "not found") {
 				return common.ProblemDetailsJSON(
 					c,
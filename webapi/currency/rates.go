@@ -0,0 +1,92 @@
+package currency
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/amirasaad/fintech/pkg/money"
+	currencysvc "github.com/amirasaad/fintech/pkg/service/currency"
+	exchangesvc "github.com/amirasaad/fintech/pkg/service/exchange"
+	"github.com/amirasaad/fintech/webapi/common"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ratesMaxAge is how long clients and shared caches may reuse a rate board.
+const ratesMaxAge = time.Minute
+
+// RateBoardResponse lists the active currencies with their rate from Base.
+type RateBoardResponse struct {
+	Base  string          `json:"base"`
+	Rates []RateBoardItem `json:"rates"`
+	// Truncated reports that only the first MaxRateBoardPairs currencies
+	// are listed.
+	Truncated bool `json:"truncated"`
+}
+
+// RateBoardItem is the rate from the base to one currency. Rate and
+// Timestamp are null when the rate is unavailable.
+type RateBoardItem struct {
+	Currency  string     `json:"currency"`
+	Rate      *float64   `json:"rate"`
+	Timestamp *time.Time `json:"timestamp"`
+	Available bool       `json:"available"`
+	Stale     bool       `json:"stale"`
+}
+
+// ListRates returns a Fiber handler that lists the active currencies with
+// their current rate from a base currency.
+// @Summary List active currencies with rates
+// @Description Lists the active currencies, in code order, with the current exchange
+// rate from base and the time of the rate, e.g. for a rate board. Rates are served from
+// the exchange rate cache; only a few missing rates are fetched from the provider per
+// request, the others are marked unavailable. Rates older than the cache TTL are
+// marked stale. At most 200 currencies are listed.
+// @Tags currencies
+// @Produce json
+// @Param base query string false "ISO 4217 code of the base currency" default(USD)
+// @Success 200 {object} common.Response{data=RateBoardResponse} "Rate board"
+// @Failure 400 {object} common.ProblemDetails "Invalid or inactive base currency"
+// @Failure 500 {object} common.ProblemDetails "Internal server error"
+// @Router /api/currencies/rates [get]
+func ListRates(
+	currencySvc *currencysvc.Service,
+	exchangeSvc *exchangesvc.Service,
+) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		base := money.Code(strings.ToUpper(strings.TrimSpace(c.Query("base", "USD"))))
+		if !base.IsValid() || !currencySvc.IsSupported(c.UserContext(), base.String()) {
+			return common.ProblemDetailsJSON(
+				c,
+				"Invalid base currency",
+				nil,
+				"base must be the code of an active currency",
+				fiber.StatusBadRequest,
+			)
+		}
+		codes, err := currencySvc.ListSupported(c.UserContext())
+		if err != nil {
+			return common.ProblemDetailsJSON(c, "Failed to list currencies", err)
+		}
+		slices.Sort(codes)
+
+		resp := RateBoardResponse{
+			Base:      base.String(),
+			Truncated: len(codes) > exchangesvc.MaxRateBoardPairs,
+		}
+		for _, br := range exchangeSvc.RateBoard(c.UserContext(), base.String(), codes) {
+			item := RateBoardItem{Currency: br.Currency, Stale: br.Stale}
+			if br.Rate != nil {
+				item.Available = true
+				item.Rate = &br.Rate.Rate
+				item.Timestamp = &br.Rate.Timestamp
+			}
+			resp.Rates = append(resp.Rates, item)
+		}
+		c.Set(fiber.HeaderCacheControl,
+			"public, max-age="+strconv.Itoa(int(ratesMaxAge.Seconds())))
+		return common.SuccessResponseJSON(c, fiber.StatusOK, "Rates fetched successfully", resp)
+	}
+}
@@ -0,0 +1,70 @@
+// Package diagnostics lets support see how an instance is configured without
+// access to its environment.
+package diagnostics
+
+import (
+	"github.com/amirasaad/fintech/pkg/config"
+	"github.com/amirasaad/fintech/pkg/domain/user"
+	"github.com/amirasaad/fintech/pkg/middleware"
+	featureflagsvc "github.com/amirasaad/fintech/pkg/service/featureflag"
+	"github.com/amirasaad/fintech/webapi/common"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/log"
+)
+
+// ConfigResponse is the effective configuration of the instance, with
+// secrets masked, and its feature flags.
+type ConfigResponse struct {
+	config.Diagnostics
+	FeatureFlags []*featureflagsvc.Flag `json:"feature_flags"`
+}
+
+// Routes registers the diagnostics admin routes.
+//
+// Routes:
+//   - GET /admin/config : Get the effective configuration, secrets redacted.
+func Routes(
+	app fiber.Router,
+	flagSvc *featureflagsvc.Service,
+	cfg *config.App,
+) {
+	app.Get(
+		"/admin/config",
+		middleware.JwtProtected(cfg.Auth.Jwt),
+		middleware.RequireRole(user.RoleAdmin),
+		GetConfig(flagSvc, cfg),
+	)
+}
+
+// GetConfig returns a Fiber handler that returns the effective configuration.
+// @Summary Get the effective configuration (admin only)
+// @Description Returns the configuration the instance runs with, for diagnosing
+// environment-specific issues: the selected event bus, payment and payout providers,
+// every setting keyed by its environment variable, and the feature flags. Secrets such
+// as the Stripe key, JWT secret, API keys and tokens are "****" when set and empty
+// when not, and passwords in database and Redis URLs are masked.
+// @Tags admin
+// @Produce json
+// @Success 200 {object} common.Response{data=ConfigResponse} "Effective configuration"
+// @Failure 401 {object} common.ProblemDetails "Unauthorized"
+// @Failure 403 {object} common.ProblemDetails "Not an admin"
+// @Failure 500 {object} common.ProblemDetails "Internal server error"
+// @Router /admin/config [get]
+// @Security Bearer
+func GetConfig(flagSvc *featureflagsvc.Service, cfg *config.App) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		flags, err := flagSvc.List(c.UserContext())
+		if err != nil {
+			log.Error("failed to list feature flags", "error", err)
+			return common.ProblemDetailsJSON(c, "Failed to get configuration", err)
+		}
+		c.Set(fiber.HeaderCacheControl, "no-store")
+		return common.SuccessResponseJSON(
+			c,
+			fiber.StatusOK,
+			"Configuration retrieved successfully",
+			ConfigResponse{Diagnostics: cfg.Diagnostics(), FeatureFlags: flags},
+		)
+	}
+}
@@ -118,7 +118,7 @@ func (s *E2EFlowsTestSuite) TestWithdrawE2E() {
 	withdrawBody := `
 	{"amount":100,"currency":"USD",
 	"external_target":
-	{"bank_account_number":"123456789","routing_number":"987654321"},
+	{"bank_account_number":"123456789","routing_number":"021000021"},
 	"money_source": "cash"}`
 	resp = s.MakeRequest(
 		"POST",
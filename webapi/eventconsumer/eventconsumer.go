@@ -0,0 +1,136 @@
+// Package eventconsumer lets operators pause and resume the event bus
+// consumers of individual event types.
+package eventconsumer
+
+import (
+	"github.com/amirasaad/fintech/pkg/config"
+	"github.com/amirasaad/fintech/pkg/domain/events"
+	"github.com/amirasaad/fintech/pkg/domain/user"
+	"github.com/amirasaad/fintech/pkg/eventbus"
+	"github.com/amirasaad/fintech/pkg/middleware"
+	"github.com/amirasaad/fintech/webapi/common"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/log"
+)
+
+// ConsumersResponse lists the paused event types.
+type ConsumersResponse struct {
+	Paused []events.EventType `json:"paused"`
+}
+
+// Routes registers the event consumer admin routes.
+//
+// Routes:
+//   - GET  /admin/event-consumers              : List paused event types.
+//   - POST /admin/event-consumers/:type/pause  : Pause the consumer of an event type.
+//   - POST /admin/event-consumers/:type/resume : Resume the consumer of an event type.
+func Routes(
+	app fiber.Router,
+	bus eventbus.PausableBus,
+	cfg *config.App,
+) {
+	app.Get(
+		"/admin/event-consumers",
+		middleware.JwtProtected(cfg.Auth.Jwt),
+		middleware.RequireRole(user.RoleAdmin),
+		ListPaused(bus),
+	)
+	app.Post(
+		"/admin/event-consumers/:type/pause",
+		middleware.JwtProtected(cfg.Auth.Jwt),
+		middleware.RequireRole(user.RoleAdmin),
+		Pause(bus),
+	)
+	app.Post(
+		"/admin/event-consumers/:type/resume",
+		middleware.JwtProtected(cfg.Auth.Jwt),
+		middleware.RequireRole(user.RoleAdmin),
+		Resume(bus),
+	)
+}
+
+// ListPaused returns a Fiber handler that lists the paused event types.
+// @Summary List paused event consumers (admin only)
+// @Description Lists the event types whose consumer is paused on this instance.
+// @Tags admin
+// @Produce json
+// @Success 200 {object} common.Response{data=ConsumersResponse} "Paused event types"
+// @Failure 401 {object} common.ProblemDetails "Unauthorized"
+// @Failure 403 {object} common.ProblemDetails "Not an admin"
+// @Router /admin/event-consumers [get]
+// @Security Bearer
+func ListPaused(bus eventbus.PausableBus) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		return common.SuccessResponseJSON(
+			c,
+			fiber.StatusOK,
+			"Paused event consumers retrieved successfully",
+			ConsumersResponse{Paused: bus.PausedConsumers()},
+		)
+	}
+}
+
+// Pause returns a Fiber handler that pauses the consumer of an event type.
+// @Summary Pause an event consumer (admin only)
+// @Description Stops handling events of the given type on this instance, e.g. while
+// the system its handlers call is under maintenance. New events stay in the stream
+// and are handled in order once the consumer is resumed. Pausing waits for the
+// events already read to be handled, which can take a few seconds.
+// @Tags admin
+// @Produce json
+// @Param type path string true "Event type, e.g. Payment.Completed"
+// @Success 200 {object} common.Response{data=ConsumersResponse} "Consumer paused"
+// @Failure 401 {object} common.ProblemDetails "Unauthorized"
+// @Failure 403 {object} common.ProblemDetails "Not an admin"
+// @Failure 404 {object} common.ProblemDetails "No consumer for the event type"
+// @Failure 500 {object} common.ProblemDetails "Internal server error"
+// @Router /admin/event-consumers/{type}/pause [post]
+// @Security Bearer
+func Pause(bus eventbus.PausableBus) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		eventType := events.EventType(c.Params("type"))
+		if err := bus.PauseConsumer(eventType); err != nil {
+			log.Error("failed to pause event consumer", "error", err, "event_type", eventType)
+			return common.ProblemDetailsJSON(c, "Failed to pause event consumer", err)
+		}
+		log.Info("event consumer paused", "event_type", eventType)
+		return common.SuccessResponseJSON(
+			c,
+			fiber.StatusOK,
+			"Event consumer paused",
+			ConsumersResponse{Paused: bus.PausedConsumers()},
+		)
+	}
+}
+
+// Resume returns a Fiber handler that resumes the consumer of an event type.
+// @Summary Resume an event consumer (admin only)
+// @Description Resumes handling events of the given type. The events that arrived
+// while it was paused are handled first, in order.
+// @Tags admin
+// @Produce json
+// @Param type path string true "Event type, e.g. Payment.Completed"
+// @Success 200 {object} common.Response{data=ConsumersResponse} "Consumer resumed"
+// @Failure 401 {object} common.ProblemDetails "Unauthorized"
+// @Failure 403 {object} common.ProblemDetails "Not an admin"
+// @Failure 404 {object} common.ProblemDetails "No consumer for the event type"
+// @Failure 500 {object} common.ProblemDetails "Internal server error"
+// @Router /admin/event-consumers/{type}/resume [post]
+// @Security Bearer
+func Resume(bus eventbus.PausableBus) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		eventType := events.EventType(c.Params("type"))
+		if err := bus.ResumeConsumer(eventType); err != nil {
+			log.Error("failed to resume event consumer", "error", err, "event_type", eventType)
+			return common.ProblemDetailsJSON(c, "Failed to resume event consumer", err)
+		}
+		log.Info("event consumer resumed", "event_type", eventType)
+		return common.SuccessResponseJSON(
+			c,
+			fiber.StatusOK,
+			"Event consumer resumed",
+			ConsumersResponse{Paused: bus.PausedConsumers()},
+		)
+	}
+}
@@ -0,0 +1,108 @@
+package exchangerate
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/amirasaad/fintech/pkg/money"
+	"github.com/amirasaad/fintech/pkg/provider/exchange"
+	"github.com/amirasaad/fintech/webapi/common"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/log"
+)
+
+// quoteMaxAge is how long clients and shared caches may reuse a conversion.
+// Rates are cached for longer, so this only saves round trips.
+const quoteMaxAge = time.Minute
+
+// Converter converts amounts between currencies. The exchange service
+// satisfies it, serving cached rates before asking the provider.
+type Converter interface {
+	Convert(
+		ctx context.Context,
+		amount *money.Money,
+		to money.Code,
+	) (*money.Money, *exchange.RateInfo, error)
+}
+
+// ConversionResponse is an amount converted at the current exchange rate.
+type ConversionResponse struct {
+	From      string    `json:"from"`
+	To        string    `json:"to"`
+	Amount    string    `json:"amount"`
+	Converted string    `json:"converted"`
+	Rate      float64   `json:"rate"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Convert returns a Fiber handler that converts an amount between two
+// currencies without an account.
+// @Summary Convert an amount between currencies
+// @Description Converts amount from one currency to another at the current exchange
+// rate, e.g. for a currency converter widget. No authentication is required; each
+// client IP is rate limited by CONVERT_MAX_REQUESTS per CONVERT_WINDOW. The quote is
+// informational: deposits and transfers are converted at the rate when they run.
+// @Tags currencies
+// @Produce json
+// @Param from query string true "ISO 4217 code of the amount's currency"
+// @Param to query string true "ISO 4217 code to convert to"
+// @Param amount query number true "Amount in from, e.g. 100 or 12.50"
+// @Success 200 {object} common.Response{data=ConversionResponse} "Converted amount"
+// @Failure 400 {object} common.ProblemDetails "Invalid currency or amount"
+// @Failure 422 {object} common.ProblemDetails "Unsupported currency pair"
+// @Failure 429 {object} common.ProblemDetails "Too many requests"
+// @Failure 503 {object} common.ProblemDetails "Exchange rates unavailable"
+// @Router /convert [get]
+func Convert(converter Converter) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		from := money.Code(strings.ToUpper(strings.TrimSpace(c.Query("from"))))
+		to := money.Code(strings.ToUpper(strings.TrimSpace(c.Query("to"))))
+		if !from.IsValid() || !to.IsValid() {
+			return common.ProblemDetailsJSON(
+				c,
+				"Invalid currency code",
+				nil,
+				"from and to must be 3-letter ISO 4217 currency codes",
+				fiber.StatusBadRequest,
+			)
+		}
+		value, err := strconv.ParseFloat(c.Query("amount"), 64)
+		if err != nil || value <= 0 {
+			return common.ProblemDetailsJSON(
+				c,
+				"Invalid amount",
+				nil,
+				"amount must be a positive number",
+				fiber.StatusBadRequest,
+			)
+		}
+		amount, err := money.New(value, from)
+		if err != nil {
+			return common.ProblemDetailsJSON(c, "Invalid amount", err)
+		}
+
+		converted, rate, err := converter.Convert(c.UserContext(), amount, to)
+		if err != nil {
+			log.Warn("conversion quote failed", "from", from, "to", to, "error", err)
+			return common.ProblemDetailsJSON(c, "Failed to convert amount", err)
+		}
+		c.Set(fiber.HeaderCacheControl,
+			"public, max-age="+strconv.Itoa(int(quoteMaxAge.Seconds())))
+		return common.SuccessResponseJSON(
+			c,
+			fiber.StatusOK,
+			"Amount converted",
+			ConversionResponse{
+				From:      from.String(),
+				To:        to.String(),
+				Amount:    amount.AmountString(),
+				Converted: converted.AmountString(),
+				Rate:      rate.Rate,
+				Timestamp: rate.Timestamp,
+			},
+		)
+	}
+}
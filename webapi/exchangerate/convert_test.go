@@ -0,0 +1,105 @@
+package exchangerate_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/amirasaad/fintech/pkg/config"
+	"github.com/amirasaad/fintech/pkg/money"
+	"github.com/amirasaad/fintech/pkg/provider/exchange"
+	"github.com/amirasaad/fintech/webapi/exchangerate"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeConverter converts USD to EUR at 0.9 and rejects other pairs.
+type fakeConverter struct{}
+
+func (fakeConverter) Convert(
+	_ context.Context,
+	amount *money.Money,
+	to money.Code,
+) (*money.Money, *exchange.RateInfo, error) {
+	if amount.CurrencyCode() != money.USD || to != money.EUR {
+		return nil, nil, fmt.Errorf(
+			"%w: %s to %s", exchange.ErrUnsupportedPair, amount.CurrencyCode(), to,
+		)
+	}
+	converted, err := amount.Convert(0.9, to.ToCurrency(), money.DefaultRoundingMode)
+	if err != nil {
+		return nil, nil, err
+	}
+	return converted, &exchange.RateInfo{Rate: 0.9, Timestamp: time.Now()}, nil
+}
+
+func TestConvert(t *testing.T) {
+	app := fiber.New()
+	exchangerate.Routes(app, fakeConverter{}, nil, &config.App{
+		Convert: &config.Convert{MaxRequests: 100, Window: time.Minute},
+	})
+
+	tests := []struct {
+		query     string
+		status    int
+		converted string
+	}{
+		{"from=usd&to=eur&amount=100", fiber.StatusOK, "90.00"},
+		{"from=USD&to=EUR&amount=12.5", fiber.StatusOK, "11.25"},
+		{"from=USD&to=XXX&amount=100", fiber.StatusUnprocessableEntity, ""},
+		{"from=USD&to=EURO&amount=100", fiber.StatusBadRequest, ""},
+		{"from=USD&to=EUR&amount=-1", fiber.StatusBadRequest, ""},
+		{"from=USD&to=EUR&amount=abc", fiber.StatusBadRequest, ""},
+		{"to=EUR&amount=1", fiber.StatusBadRequest, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/convert?"+tt.query, nil))
+			require.NoError(t, err)
+			defer resp.Body.Close() //nolint:errcheck
+			require.Equal(t, tt.status, resp.StatusCode)
+			if tt.converted == "" {
+				return
+			}
+			assert.Equal(t, "public, max-age=60", resp.Header.Get(fiber.HeaderCacheControl))
+			var body struct {
+				Data exchangerate.ConversionResponse `json:"data"`
+			}
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+			assert.Equal(t, "USD", body.Data.From)
+			assert.Equal(t, "EUR", body.Data.To)
+			assert.Equal(t, tt.converted, body.Data.Converted)
+			assert.InDelta(t, 0.9, body.Data.Rate, 1e-9)
+		})
+	}
+}
+
+func TestConvert_RateLimitedPerClient(t *testing.T) {
+	// Requests in tests come from 0.0.0.0, here a trusted proxy.
+	app := fiber.New(fiber.Config{
+		EnableTrustedProxyCheck: true,
+		EnableIPValidation:      true,
+		TrustedProxies:          []string{"0.0.0.0"},
+		ProxyHeader:             fiber.HeaderXForwardedFor,
+	})
+	exchangerate.Routes(app, fakeConverter{}, nil, &config.App{
+		Convert: &config.Convert{MaxRequests: 2, Window: time.Minute},
+	})
+	get := func(ip string) int {
+		req := httptest.NewRequest(fiber.MethodGet, "/convert?from=USD&to=EUR&amount=1", nil)
+		req.Header.Set("X-Forwarded-For", ip)
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		defer resp.Body.Close() //nolint:errcheck
+		return resp.StatusCode
+	}
+
+	assert.Equal(t, fiber.StatusOK, get("203.0.113.1"))
+	assert.Equal(t, fiber.StatusOK, get("203.0.113.1"))
+	assert.Equal(t, fiber.StatusTooManyRequests, get("203.0.113.1"))
+	assert.Equal(t, fiber.StatusOK, get("203.0.113.2"))
+}
@@ -0,0 +1,65 @@
+// Package exchangerate serves public currency conversion quotes and exposes
+// the exchange rate provider's quota usage to operators.
+package exchangerate
+
+import (
+	"github.com/amirasaad/fintech/pkg/config"
+	"github.com/amirasaad/fintech/pkg/domain/user"
+	"github.com/amirasaad/fintech/pkg/middleware"
+	"github.com/amirasaad/fintech/pkg/provider/exchange"
+	"github.com/amirasaad/fintech/webapi/common"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Routes registers the exchange rate routes. The quota route is registered
+// only with a quota provider.
+//
+// Routes:
+//   - GET /convert : Convert an amount between currencies (public, rate limited per IP).
+//   - GET /admin/exchange-rates/quota : Provider calls made in the current quota window.
+func Routes(
+	app fiber.Router,
+	converter Converter,
+	quota *exchange.QuotaProvider,
+	cfg *config.App,
+) {
+	limit := &config.RateLimit{}
+	if cfg.Convert != nil {
+		limit.MaxRequests, limit.Window = cfg.Convert.MaxRequests, cfg.Convert.Window
+	}
+	app.Get("/convert", common.RateLimit(limit), Convert(converter))
+
+	if quota == nil {
+		return
+	}
+	app.Get(
+		"/admin/exchange-rates/quota",
+		middleware.JwtProtected(cfg.Auth.Jwt),
+		middleware.RequireRole(user.RoleAdmin),
+		QuotaUsage(quota),
+	)
+}
+
+// QuotaUsage returns a Fiber handler that reports the calls made to the
+// exchange rate provider in the current quota window.
+// @Summary Exchange rate provider quota usage (admin only)
+// @Description Reports the provider calls made in the current monthly window,
+// the quota and soft limit, and whether conversions are limited to cached rates.
+// @Tags admin
+// @Produce json
+// @Success 200 {object} common.Response{data=exchange.QuotaUsage} "Quota usage"
+// @Failure 401 {object} common.ProblemDetails "Unauthorized"
+// @Failure 403 {object} common.ProblemDetails "Not an admin"
+// @Router /admin/exchange-rates/quota [get]
+// @Security Bearer
+func QuotaUsage(quota *exchange.QuotaProvider) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		return common.SuccessResponseJSON(
+			c,
+			fiber.StatusOK,
+			"Exchange rate quota usage fetched",
+			quota.Usage(),
+		)
+	}
+}
@@ -0,0 +1,153 @@
+// Package featureflag exposes feature flag administration to operators.
+package featureflag
+
+import (
+	"github.com/amirasaad/fintech/pkg/config"
+	"github.com/amirasaad/fintech/pkg/domain/user"
+	"github.com/amirasaad/fintech/pkg/middleware"
+	featureflagsvc "github.com/amirasaad/fintech/pkg/service/featureflag"
+	"github.com/amirasaad/fintech/webapi/common"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/log"
+	"github.com/google/uuid"
+)
+
+// SetFlagRequest is the request body for creating or replacing a feature flag.
+type SetFlagRequest struct {
+	Enabled    bool        `json:"enabled"`
+	Percentage int         `json:"percentage" validate:"gte=0,lte=100"`
+	Allow      []uuid.UUID `json:"allow"`
+	Deny       []uuid.UUID `json:"deny"`
+}
+
+// Routes registers the feature flag admin routes.
+//
+// Routes:
+//   - GET /admin/feature-flags       : List feature flags.
+//   - GET /admin/feature-flags/:name : Get one feature flag.
+//   - PUT /admin/feature-flags/:name : Create or replace a feature flag.
+func Routes(
+	app fiber.Router,
+	svc *featureflagsvc.Service,
+	cfg *config.App,
+) {
+	app.Get(
+		"/admin/feature-flags",
+		middleware.JwtProtected(cfg.Auth.Jwt),
+		middleware.RequireRole(user.RoleAdmin),
+		ListFlags(svc),
+	)
+	app.Get(
+		"/admin/feature-flags/:name",
+		middleware.JwtProtected(cfg.Auth.Jwt),
+		middleware.RequireRole(user.RoleAdmin),
+		GetFlag(svc),
+	)
+	app.Put(
+		"/admin/feature-flags/:name",
+		middleware.JwtProtected(cfg.Auth.Jwt),
+		middleware.RequireRole(user.RoleAdmin),
+		SetFlag(svc),
+	)
+}
+
+// ListFlags returns a Fiber handler that lists every feature flag.
+// @Summary List feature flags (admin only)
+// @Description Lists every feature flag with its rollout rules, sorted by name.
+// @Tags admin
+// @Produce json
+// @Success 200 {object} common.Response{data=[]featureflag.Flag} "Feature flags"
+// @Failure 401 {object} common.ProblemDetails "Unauthorized"
+// @Failure 403 {object} common.ProblemDetails "Not an admin"
+// @Failure 500 {object} common.ProblemDetails "Internal server error"
+// @Router /admin/feature-flags [get]
+// @Security Bearer
+func ListFlags(svc *featureflagsvc.Service) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		flags, err := svc.List(c.UserContext())
+		if err != nil {
+			log.Error("failed to list feature flags", "error", err)
+			return common.ProblemDetailsJSON(c, "Failed to list feature flags", err)
+		}
+		return common.SuccessResponseJSON(
+			c,
+			fiber.StatusOK,
+			"Feature flags retrieved successfully",
+			flags,
+		)
+	}
+}
+
+// GetFlag returns a Fiber handler that returns one feature flag.
+// @Summary Get a feature flag (admin only)
+// @Description Returns the named feature flag with its rollout rules.
+// @Tags admin
+// @Produce json
+// @Param name path string true "Flag name"
+// @Success 200 {object} common.Response{data=featureflag.Flag} "Feature flag"
+// @Failure 401 {object} common.ProblemDetails "Unauthorized"
+// @Failure 403 {object} common.ProblemDetails "Not an admin"
+// @Failure 404 {object} common.ProblemDetails "Feature flag not found"
+// @Failure 500 {object} common.ProblemDetails "Internal server error"
+// @Router /admin/feature-flags/{name} [get]
+// @Security Bearer
+func GetFlag(svc *featureflagsvc.Service) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		flag, err := svc.Get(c.UserContext(), c.Params("name"))
+		if err != nil {
+			return common.ProblemDetailsJSON(c, "Failed to get feature flag", err)
+		}
+		return common.SuccessResponseJSON(
+			c,
+			fiber.StatusOK,
+			"Feature flag retrieved successfully",
+			flag,
+		)
+	}
+}
+
+// SetFlag returns a Fiber handler that creates or replaces a feature flag.
+// @Summary Set a feature flag (admin only)
+// @Description Creates or replaces the named feature flag. The change takes effect
+// immediately, without a redeploy. Disabled flags are off for everyone; otherwise
+// denied users never get the feature, allowed users always do, and the given
+// percentage of the remaining users do.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param name path string true "Flag name"
+// @Param request body SetFlagRequest true "Rollout rules"
+// @Success 200 {object} common.Response{data=featureflag.Flag} "Feature flag updated"
+// @Failure 400 {object} common.ProblemDetails "Invalid request"
+// @Failure 401 {object} common.ProblemDetails "Unauthorized"
+// @Failure 403 {object} common.ProblemDetails "Not an admin"
+// @Failure 500 {object} common.ProblemDetails "Internal server error"
+// @Router /admin/feature-flags/{name} [put]
+// @Security Bearer
+func SetFlag(svc *featureflagsvc.Service) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		input, err := common.BindAndValidate[SetFlagRequest](c)
+		if input == nil {
+			return err // error response already written
+		}
+		name := c.Params("name")
+		flag, err := svc.Set(c.UserContext(), featureflagsvc.Flag{
+			Name:       name,
+			Enabled:    input.Enabled,
+			Percentage: input.Percentage,
+			Allow:      input.Allow,
+			Deny:       input.Deny,
+		})
+		if err != nil {
+			log.Error("failed to set feature flag", "error", err, "flag", name)
+			return common.ProblemDetailsJSON(c, "Failed to set feature flag", err)
+		}
+		return common.SuccessResponseJSON(
+			c,
+			fiber.StatusOK,
+			"Feature flag updated",
+			flag,
+		)
+	}
+}
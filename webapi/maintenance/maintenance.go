@@ -0,0 +1,122 @@
+// Package maintenance exposes the maintenance mode switch to operators.
+package maintenance
+
+import (
+	"github.com/amirasaad/fintech/pkg/config"
+	"github.com/amirasaad/fintech/pkg/domain/user"
+	"github.com/amirasaad/fintech/pkg/middleware"
+	authsvc "github.com/amirasaad/fintech/pkg/service/auth"
+	maintenancesvc "github.com/amirasaad/fintech/pkg/service/maintenance"
+	"github.com/amirasaad/fintech/webapi/common"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/log"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// SetMaintenanceRequest is the request body for switching maintenance mode.
+type SetMaintenanceRequest struct {
+	Enabled bool   `json:"enabled"`
+	Reason  string `json:"reason" validate:"max=255"`
+}
+
+// Routes registers the maintenance mode admin routes.
+//
+// Routes:
+//   - GET /admin/maintenance : Get the maintenance mode state.
+//   - PUT /admin/maintenance : Switch maintenance mode on or off.
+func Routes(
+	app fiber.Router,
+	svc *maintenancesvc.Service,
+	authSvc *authsvc.Service,
+	cfg *config.App,
+) {
+	app.Get(
+		"/admin/maintenance",
+		middleware.JwtProtected(cfg.Auth.Jwt),
+		middleware.RequireRole(user.RoleAdmin),
+		GetMaintenance(svc),
+	)
+	app.Put(
+		"/admin/maintenance",
+		middleware.JwtProtected(cfg.Auth.Jwt),
+		middleware.RequireRole(user.RoleAdmin),
+		SetMaintenance(svc, authSvc),
+	)
+}
+
+// GetMaintenance returns a Fiber handler that returns the maintenance state.
+// @Summary Get maintenance mode (admin only)
+// @Description Returns whether maintenance mode is on, why, and who last switched it.
+// @Tags admin
+// @Produce json
+// @Success 200 {object} common.Response{data=maintenance.State} "Maintenance state"
+// @Failure 401 {object} common.ProblemDetails "Unauthorized"
+// @Failure 403 {object} common.ProblemDetails "Not an admin"
+// @Failure 500 {object} common.ProblemDetails "Internal server error"
+// @Router /admin/maintenance [get]
+// @Security Bearer
+func GetMaintenance(svc *maintenancesvc.Service) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		state, err := svc.Status(c.UserContext())
+		if err != nil {
+			log.Error("failed to get maintenance state", "error", err)
+			return common.ProblemDetailsJSON(c, "Failed to get maintenance state", err)
+		}
+		return common.SuccessResponseJSON(
+			c,
+			fiber.StatusOK,
+			"Maintenance state retrieved successfully",
+			state,
+		)
+	}
+}
+
+// SetMaintenance returns a Fiber handler that switches maintenance mode.
+// @Summary Switch maintenance mode (admin only)
+// @Description Switches maintenance mode on or off. While it is on, deposits,
+// withdrawals and transfers are rejected with 503 Service Unavailable; reads keep
+// working and flows already accepted complete. Maintenance mode forced on with
+// MAINTENANCE_ENABLED stays on.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body SetMaintenanceRequest true "Maintenance mode"
+// @Success 200 {object} common.Response{data=maintenance.State} "Maintenance mode updated"
+// @Failure 400 {object} common.ProblemDetails "Invalid request"
+// @Failure 401 {object} common.ProblemDetails "Unauthorized"
+// @Failure 403 {object} common.ProblemDetails "Not an admin"
+// @Failure 500 {object} common.ProblemDetails "Internal server error"
+// @Router /admin/maintenance [put]
+// @Security Bearer
+func SetMaintenance(
+	svc *maintenancesvc.Service,
+	authSvc *authsvc.Service,
+) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		token, ok := c.Locals("user").(*jwt.Token)
+		if !ok {
+			return common.ProblemDetailsJSON(c, "Unauthorized", nil, "missing user context")
+		}
+		adminID, err := authSvc.GetCurrentUserId(token)
+		if err != nil {
+			log.Error("failed to get user ID from token", "error", err)
+			return common.ProblemDetailsJSON(c, "Invalid user ID", err)
+		}
+		input, err := common.BindAndValidate[SetMaintenanceRequest](c)
+		if input == nil {
+			return err // error response already written
+		}
+		state, err := svc.Set(c.UserContext(), input.Enabled, input.Reason, adminID)
+		if err != nil {
+			log.Error("failed to set maintenance mode", "error", err, "admin_id", adminID)
+			return common.ProblemDetailsJSON(c, "Failed to set maintenance mode", err)
+		}
+		return common.SuccessResponseJSON(
+			c,
+			fiber.StatusOK,
+			"Maintenance mode updated",
+			state,
+		)
+	}
+}
@@ -0,0 +1,176 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/amirasaad/fintech/webapi/common"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Request kinds label the HTTP metrics so money-moving endpoints can be told
+// apart from reads and other writes.
+const (
+	KindMoney = "money"
+	KindRead  = "read"
+	KindWrite = "write"
+)
+
+// unmatchedRoute labels requests that matched no route, so scans of unknown
+// paths do not each create a new series.
+const unmatchedRoute = "unmatched"
+
+// moneyRoutes are the endpoints that move money, as method and route
+// template.
+var moneyRoutes = map[string]bool{
+	"POST /account/:ref/deposit":    true,
+	"POST /account/:ref/withdraw":   true,
+	"POST /account/:ref/transfer":   true,
+	"POST /transactions/:id/refund": true,
+	"POST /api/v1/webhooks/stripe":  true,
+}
+
+// durationBuckets are the upper bounds, in seconds, of the request duration
+// histogram.
+var durationBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// HTTPMetrics counts HTTP requests and their durations per route template,
+// method and status, and tracks the requests in flight.
+type HTTPMetrics struct {
+	mu       sync.Mutex
+	requests map[requestKey]*requestStats
+	inFlight int64
+}
+
+type requestKey struct {
+	method, route, status, kind string
+}
+
+type requestStats struct {
+	count uint64
+	sum   float64
+	// buckets counts the requests per bucket of durationBuckets; they are
+	// made cumulative when written.
+	buckets []uint64
+}
+
+// NewHTTPMetrics creates an empty HTTPMetrics.
+func NewHTTPMetrics() *HTTPMetrics {
+	return &HTTPMetrics{requests: make(map[requestKey]*requestStats)}
+}
+
+// Middleware records every request handled through it. Requests are labeled
+// with the template of the matched route, e.g. /account/:ref/deposit, never
+// the concrete URL. The in-flight gauge is not labeled by route, as the route
+// is only known once the request has been routed.
+func (m *HTTPMetrics) Middleware() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		m.mu.Lock()
+		m.inFlight++
+		m.mu.Unlock()
+
+		start := time.Now()
+		err := c.Next()
+		d := time.Since(start)
+
+		// Fiber reuses the memory behind c.Method() once the request is done,
+		// so it is copied before being kept as a label.
+		method, route := strings.Clone(c.Method()), common.MatchedRoute(c, err)
+		if route == "" {
+			route = unmatchedRoute
+		}
+		m.record(requestKey{
+			method: method,
+			route:  route,
+			status: strconv.Itoa(common.ResponseStatus(c, err)),
+			kind:   requestKind(method, route),
+		}, d)
+		return err
+	}
+}
+
+func requestKind(method, route string) string {
+	switch {
+	case moneyRoutes[method+" "+route]:
+		return KindMoney
+	case method == fiber.MethodGet || method == fiber.MethodHead ||
+		method == fiber.MethodOptions:
+		return KindRead
+	default:
+		return KindWrite
+	}
+}
+
+func (m *HTTPMetrics) record(key requestKey, d time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.inFlight--
+	s, ok := m.requests[key]
+	if !ok {
+		s = &requestStats{buckets: make([]uint64, len(durationBuckets))}
+		m.requests[key] = s
+	}
+	s.count++
+	s.sum += d.Seconds()
+	for i, le := range durationBuckets {
+		if d.Seconds() <= le {
+			s.buckets[i]++
+			break
+		}
+	}
+}
+
+// WritePrometheus writes the request metrics in the Prometheus text format.
+func (m *HTTPMetrics) WritePrometheus(w io.Writer) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	keys := make([]requestKey, 0, len(m.requests))
+	for key := range m.requests {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := keys[i], keys[j]
+		if a.route != b.route {
+			return a.route < b.route
+		}
+		if a.method != b.method {
+			return a.method < b.method
+		}
+		return a.status < b.status
+	})
+
+	fmt.Fprint(w, "# HELP fintech_http_requests_total Total number of HTTP requests.\n"+
+		"# TYPE fintech_http_requests_total counter\n")
+	for _, key := range keys {
+		fmt.Fprintf(w, "fintech_http_requests_total{%s} %d\n", key.labels(), m.requests[key].count)
+	}
+
+	fmt.Fprint(w, "# HELP fintech_http_request_duration_seconds Duration of HTTP requests.\n"+
+		"# TYPE fintech_http_request_duration_seconds histogram\n")
+	for _, key := range keys {
+		s, labels := m.requests[key], key.labels()
+		var cumulative uint64
+		for i, le := range durationBuckets {
+			cumulative += s.buckets[i]
+			fmt.Fprintf(w, "fintech_http_request_duration_seconds_bucket{%s,le=\"%g\"} %d\n",
+				labels, le, cumulative)
+		}
+		fmt.Fprintf(w, "fintech_http_request_duration_seconds_bucket{%s,le=\"+Inf\"} %d\n",
+			labels, s.count)
+		fmt.Fprintf(w, "fintech_http_request_duration_seconds_sum{%s} %g\n", labels, s.sum)
+		fmt.Fprintf(w, "fintech_http_request_duration_seconds_count{%s} %d\n", labels, s.count)
+	}
+
+	fmt.Fprintf(w, "# HELP fintech_http_requests_in_flight Number of HTTP requests being served.\n"+
+		"# TYPE fintech_http_requests_in_flight gauge\nfintech_http_requests_in_flight %d\n",
+		m.inFlight)
+}
+
+func (k requestKey) labels() string {
+	return fmt.Sprintf("method=%q,route=%q,status=%q,kind=%q", k.method, k.route, k.status, k.kind)
+}
@@ -0,0 +1,117 @@
+// Package metrics exposes operational metrics in the Prometheus text format.
+package metrics
+
+import (
+	"database/sql"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/amirasaad/fintech/pkg/app"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Collector writes additional metrics in the Prometheus text format.
+// *commandbus.Metrics, *HTTPMetrics, *exchange.QuotaProvider and
+// DBReplicaPools satisfy it.
+type Collector interface {
+	WritePrometheus(w io.Writer)
+}
+
+// Routes registers the metrics endpoint.
+//
+// Routes:
+//   - GET /metrics : Database connection pool statistics and collector metrics.
+func Routes(fiberApp fiber.Router, dbPool app.DBStatsProvider, collectors ...Collector) {
+	fiberApp.Get("/metrics", Handler(dbPool, collectors...))
+}
+
+// Handler returns a Fiber handler that writes metrics in the Prometheus text
+// exposition format. A nil dbPool and no collectors yield an empty body.
+// @Summary Metrics
+// @Description Exposes database connection pool statistics and command counters
+// in the Prometheus text format.
+// @Tags metrics
+// @Produce plain
+// @Success 200 {string} string "Metrics"
+// @Router /metrics [get]
+func Handler(dbPool app.DBStatsProvider, collectors ...Collector) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var b strings.Builder
+		if dbPool != nil {
+			writeDBStats(&b, dbPool)
+		}
+		for _, col := range collectors {
+			col.WritePrometheus(&b)
+		}
+		c.Set(fiber.HeaderContentType, "text/plain; version=0.0.4; charset=utf-8")
+		return c.SendString(b.String())
+	}
+}
+
+// DBReplicaPools writes the connection pool statistics of the read replicas
+// as fintech_db_replica_* metrics, labeled with the replica's index in
+// DATABASE_REPLICA_URLS.
+type DBReplicaPools []app.DBStatsProvider
+
+// WritePrometheus implements Collector.
+func (p DBReplicaPools) WritePrometheus(w io.Writer) {
+	if len(p) == 0 {
+		return
+	}
+	replicas := make([][]dbMetric, len(p))
+	for i, pool := range p {
+		replicas[i] = dbMetrics(pool.Stats())
+	}
+	for j, m := range replicas[0] {
+		name := strings.Replace(m.name, "fintech_db_", "fintech_db_replica_", 1)
+		fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, m.help, name, m.kind)
+		for i := range replicas {
+			fmt.Fprintf(w, "%s{replica=\"%d\"} %g\n", name, i, replicas[i][j].value)
+		}
+	}
+}
+
+type dbMetric struct {
+	name, kind, help string
+	value            float64
+}
+
+func writeDBStats(b *strings.Builder, dbPool app.DBStatsProvider) {
+	for _, m := range dbMetrics(dbPool.Stats()) {
+		fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s %s\n%s %g\n",
+			m.name, m.help, m.name, m.kind, m.name, m.value)
+	}
+}
+
+func dbMetrics(s sql.DBStats) []dbMetric {
+	return []dbMetric{
+		{"fintech_db_max_open_connections", "gauge",
+			"Maximum number of open connections to the database.",
+			float64(s.MaxOpenConnections)},
+		{"fintech_db_open_connections", "gauge",
+			"Number of established connections, both in use and idle.",
+			float64(s.OpenConnections)},
+		{"fintech_db_in_use_connections", "gauge",
+			"Number of connections currently in use.",
+			float64(s.InUse)},
+		{"fintech_db_idle_connections", "gauge",
+			"Number of idle connections.",
+			float64(s.Idle)},
+		{"fintech_db_wait_count_total", "counter",
+			"Total number of connections waited for.",
+			float64(s.WaitCount)},
+		{"fintech_db_wait_duration_seconds_total", "counter",
+			"Total time blocked waiting for a new connection.",
+			s.WaitDuration.Seconds()},
+		{"fintech_db_max_idle_closed_total", "counter",
+			"Total number of connections closed due to SetMaxIdleConns.",
+			float64(s.MaxIdleClosed)},
+		{"fintech_db_max_idle_time_closed_total", "counter",
+			"Total number of connections closed due to SetConnMaxIdleTime.",
+			float64(s.MaxIdleTimeClosed)},
+		{"fintech_db_max_lifetime_closed_total", "counter",
+			"Total number of connections closed due to SetConnMaxLifetime.",
+			float64(s.MaxLifetimeClosed)},
+	}
+}
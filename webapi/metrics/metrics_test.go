@@ -0,0 +1,130 @@
+package metrics_test
+
+import (
+	"database/sql"
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/amirasaad/fintech/webapi/metrics"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakePool struct{ stats sql.DBStats }
+
+type fakeCollector struct{}
+
+func (fakeCollector) WritePrometheus(w io.Writer) {
+	fmt.Fprint(w, "fintech_commands_total{command=\"account.deposit\"} 4\n")
+}
+
+func (p fakePool) Stats() sql.DBStats { return p.stats }
+
+func TestMetricsHandler(t *testing.T) {
+	app := fiber.New()
+	metrics.Routes(app, fakePool{stats: sql.DBStats{
+		MaxOpenConnections: 25,
+		OpenConnections:    7,
+		InUse:              5,
+		Idle:               2,
+		WaitCount:          3,
+		WaitDuration:       1500 * time.Millisecond,
+	}})
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/metrics", nil))
+	require.NoError(t, err)
+	defer resp.Body.Close() //nolint:errcheck
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	assert.Contains(t, resp.Header.Get(fiber.HeaderContentType), "text/plain")
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Contains(t, string(body), "# TYPE fintech_db_in_use_connections gauge\n")
+	assert.Contains(t, string(body), "fintech_db_in_use_connections 5\n")
+	assert.Contains(t, string(body), "fintech_db_idle_connections 2\n")
+	assert.Contains(t, string(body), "fintech_db_wait_count_total 3\n")
+	assert.Contains(t, string(body), "fintech_db_wait_duration_seconds_total 1.5\n")
+}
+
+func TestMetricsHandler_Collectors(t *testing.T) {
+	app := fiber.New()
+	metrics.Routes(app, nil, fakeCollector{})
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/metrics", nil))
+	require.NoError(t, err)
+	defer resp.Body.Close() //nolint:errcheck
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "fintech_commands_total{command=\"account.deposit\"} 4\n", string(body))
+}
+
+func TestHTTPMetrics(t *testing.T) {
+	app := fiber.New()
+	httpMetrics := metrics.NewHTTPMetrics()
+	app.Use(httpMetrics.Middleware())
+	// Unmatched requests end at the last middleware they pass through.
+	app.Use(func(c *fiber.Ctx) error { return c.Next() })
+	app.Post("/account/:ref/deposit", func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusAccepted)
+	})
+	app.Get("/account/:ref/balance", func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+	metrics.Routes(app, nil, httpMetrics)
+
+	for _, req := range []struct{ method, path string }{
+		{fiber.MethodPost, "/account/1/deposit"},
+		{fiber.MethodPost, "/account/2/deposit"},
+		{fiber.MethodGet, "/account/1/balance"},
+		{fiber.MethodGet, "/account/1/unknown"},
+	} {
+		resp, err := app.Test(httptest.NewRequest(req.method, req.path, nil))
+		require.NoError(t, err)
+		resp.Body.Close() //nolint:errcheck
+	}
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/metrics", nil))
+	require.NoError(t, err)
+	defer resp.Body.Close() //nolint:errcheck
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	assert.Contains(t, string(body), "fintech_http_requests_total{method=\"POST\","+
+		"route=\"/account/:ref/deposit\",status=\"202\",kind=\"money\"} 2\n")
+	assert.Contains(t, string(body), "fintech_http_requests_total{method=\"GET\","+
+		"route=\"/account/:ref/balance\",status=\"200\",kind=\"read\"} 1\n")
+	assert.Contains(t, string(body), "fintech_http_requests_total{method=\"GET\","+
+		"route=\"unmatched\",status=\"404\",kind=\"read\"} 1\n")
+	assert.Contains(t, string(body), "fintech_http_request_duration_seconds_count{method=\"POST\","+
+		"route=\"/account/:ref/deposit\",status=\"202\",kind=\"money\"} 2\n")
+	assert.Contains(t, string(body), "fintech_http_request_duration_seconds_bucket{method=\"POST\","+
+		"route=\"/account/:ref/deposit\",status=\"202\",kind=\"money\",le=\"+Inf\"} 2\n")
+	assert.NotContains(t, string(body), "/account/1/")
+	// The scrape itself is in flight while the metrics are written.
+	assert.Contains(t, string(body), "fintech_http_requests_in_flight 1\n")
+}
+
+func TestMetricsHandler_DBReplicaPools(t *testing.T) {
+	app := fiber.New()
+	metrics.Routes(app, nil, metrics.DBReplicaPools{
+		fakePool{stats: sql.DBStats{InUse: 1}},
+		fakePool{stats: sql.DBStats{InUse: 4}},
+	})
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/metrics", nil))
+	require.NoError(t, err)
+	defer resp.Body.Close() //nolint:errcheck
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Contains(t, string(body),
+		"# TYPE fintech_db_replica_in_use_connections gauge\n"+
+			"fintech_db_replica_in_use_connections{replica=\"0\"} 1\n"+
+			"fintech_db_replica_in_use_connections{replica=\"1\"} 4\n")
+	assert.NotContains(t, string(body), "fintech_db_in_use_connections")
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/amirasaad/fintech/pkg/provider/payment"
+	"github.com/amirasaad/fintech/webapi/common"
 	"github.com/gofiber/fiber/v2"
 )
 
@@ -29,7 +30,7 @@ func StripeWebhookHandler(
 		}
 
 		// Process the webhook event
-		_, err := paymentProvider.HandleWebhook(c.Context(), payload, signature)
+		_, err := paymentProvider.HandleWebhook(common.DetachedContext(c), payload, signature)
 		if err != nil {
 			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 				"error": fmt.Sprintf("Error processing webhook: %v", err),
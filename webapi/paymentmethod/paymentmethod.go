@@ -0,0 +1,94 @@
+// Package paymentmethod lists the payment methods offered at checkout.
+package paymentmethod
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/amirasaad/fintech/pkg/config"
+	"github.com/amirasaad/fintech/pkg/currency"
+	"github.com/amirasaad/fintech/webapi/common"
+	"github.com/gofiber/fiber/v2"
+)
+
+var countryPattern = regexp.MustCompile(`^[A-Z]{2}$`)
+
+// Response lists the payment method types offered for a currency and
+// country.
+type Response struct {
+	Currency string   `json:"currency"`
+	Country  string   `json:"country,omitempty"`
+	Methods  []string `json:"methods"`
+}
+
+// Routes registers the payment methods endpoint. Without Stripe
+// configuration only cards are offered, matching checkout.
+//
+// Routes:
+//   - GET /payment-methods : Payment methods for a currency and country.
+func Routes(r fiber.Router, cfg *config.App) {
+	methods := config.PaymentMethods{"card": "*"}
+	if cfg.PaymentProviders != nil && cfg.PaymentProviders.Stripe != nil &&
+		len(cfg.PaymentProviders.Stripe.PaymentMethods) > 0 {
+		methods = cfg.PaymentProviders.Stripe.PaymentMethods
+	}
+	r.Get("/payment-methods", ListPaymentMethods(methods))
+}
+
+// ListPaymentMethods returns a Fiber handler that lists the payment method
+// types enabled for a currency and, optionally, the payer's country.
+// @Summary List payment methods
+// @Description Lists the Stripe payment method types enabled for the currency and
+// country, so clients know what to offer before checkout. Without a country, every
+// method enabled for the currency is listed.
+// @Tags payment-methods
+// @Produce json
+// @Param currency query string true "ISO 4217 currency code"
+// @Param country query string false "ISO 3166-1 alpha-2 country code"
+// @Success 200 {object} common.Response{data=Response} "Payment methods"
+// @Failure 400 {object} common.ProblemDetails "Invalid currency or country"
+// @Failure 500 {object} common.ProblemDetails "Internal server error"
+// @Router /payment-methods [get]
+func ListPaymentMethods(methods config.PaymentMethods) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		code := strings.ToUpper(c.Query("currency"))
+		if !currency.IsValidFormat(code) {
+			return common.ProblemDetailsJSON(
+				c,
+				"Invalid currency code",
+				nil,
+				"Please provide a valid 3-letter ISO 4217 currency code",
+				fiber.StatusBadRequest,
+			)
+		}
+		country := strings.ToUpper(c.Query("country"))
+		if country != "" && !countryPattern.MatchString(country) {
+			return common.ProblemDetailsJSON(
+				c,
+				"Invalid country code",
+				nil,
+				"Please provide a valid 2-letter ISO 3166-1 country code",
+				fiber.StatusBadRequest,
+			)
+		}
+
+		types, err := methods.For(code, country)
+		if err != nil {
+			return common.ProblemDetailsJSON(
+				c,
+				"Failed to list payment methods",
+				err,
+				fiber.StatusInternalServerError,
+			)
+		}
+		if types == nil {
+			types = []string{}
+		}
+		return common.SuccessResponseJSON(
+			c,
+			fiber.StatusOK,
+			"Payment methods fetched successfully",
+			Response{Currency: code, Country: country, Methods: types},
+		)
+	}
+}
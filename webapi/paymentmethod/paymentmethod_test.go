@@ -0,0 +1,69 @@
+package paymentmethod_test
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/amirasaad/fintech/pkg/config"
+	"github.com/amirasaad/fintech/webapi/paymentmethod"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListPaymentMethods(t *testing.T) {
+	app := fiber.New()
+	paymentmethod.Routes(app, &config.App{PaymentProviders: &config.PaymentProviders{
+		Stripe: &config.Stripe{PaymentMethods: config.PaymentMethods{
+			"card":       "*",
+			"sepa_debit": "EUR/AT|DE",
+			"ideal":      "EUR/NL",
+		}},
+	}})
+
+	tests := []struct {
+		query   string
+		status  int
+		methods []string
+	}{
+		{"currency=eur&country=de", fiber.StatusOK, []string{"card", "sepa_debit"}},
+		{"currency=EUR&country=NL", fiber.StatusOK, []string{"card", "ideal"}},
+		{"currency=EUR", fiber.StatusOK, []string{"card", "ideal", "sepa_debit"}},
+		{"currency=USD&country=DE", fiber.StatusOK, []string{"card"}},
+		{"", fiber.StatusBadRequest, nil},
+		{"currency=EUR&country=DEU", fiber.StatusBadRequest, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/payment-methods?"+tt.query, nil))
+			require.NoError(t, err)
+			defer resp.Body.Close() //nolint:errcheck
+			require.Equal(t, tt.status, resp.StatusCode)
+			if tt.methods == nil {
+				return
+			}
+			var body struct {
+				Data paymentmethod.Response `json:"data"`
+			}
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+			assert.Equal(t, tt.methods, body.Data.Methods)
+		})
+	}
+}
+
+func TestListPaymentMethods_DefaultsToCard(t *testing.T) {
+	app := fiber.New()
+	paymentmethod.Routes(app, &config.App{})
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/payment-methods?currency=JPY", nil))
+	require.NoError(t, err)
+	defer resp.Body.Close() //nolint:errcheck
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	var body struct {
+		Data paymentmethod.Response `json:"data"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, []string{"card"}, body.Data.Methods)
+}
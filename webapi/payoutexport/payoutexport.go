@@ -0,0 +1,112 @@
+// Package payoutexport exposes ISO 20022 payout exports to operators.
+package payoutexport
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/amirasaad/fintech/pkg/config"
+	"github.com/amirasaad/fintech/pkg/domain/user"
+	"github.com/amirasaad/fintech/pkg/iso20022"
+	"github.com/amirasaad/fintech/pkg/middleware"
+	payoutexportsvc "github.com/amirasaad/fintech/pkg/service/payoutexport"
+	"github.com/amirasaad/fintech/webapi/common"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/log"
+	"github.com/google/uuid"
+)
+
+// dateLayout is the format of the execution_date field.
+const dateLayout = "2006-01-02"
+
+// Pain001Request is the request body for exporting withdrawals as pain.001.
+type Pain001Request struct {
+	TransactionIDs []uuid.UUID `json:"transaction_ids" validate:"required,min=1,max=1000"`
+	// ExecutionDate is the requested execution date (YYYY-MM-DD); defaults to today.
+	ExecutionDate string `json:"execution_date,omitempty"`
+}
+
+// Routes registers the payout export admin routes.
+//
+// Routes:
+//   - POST /admin/payouts/pain001 : Export withdrawals as an ISO 20022 pain.001 file.
+func Routes(
+	app fiber.Router,
+	svc *payoutexportsvc.Service,
+	cfg *config.App,
+) {
+	app.Post(
+		"/admin/payouts/pain001",
+		middleware.JwtProtected(cfg.Auth.Jwt),
+		middleware.RequireRole(user.RoleAdmin),
+		ExportPain001(svc),
+	)
+}
+
+// ExportPain001 returns a Fiber handler that exports withdrawals as a pain.001
+// credit transfer initiation file.
+// @Summary Export payouts as ISO 20022 pain.001 (admin only)
+// @Description Renders the given withdrawals as one pain.001.001.03 document for upload
+// to the platform's bank. Each withdrawal is paid net of its fee from the configured
+// debtor account, with the transaction ID as end-to-end ID. No file is generated unless
+// every withdrawal can be paid; otherwise the errors list each problem.
+// @Tags admin
+// @Accept json
+// @Produce xml
+// @Param request body Pain001Request true "Withdrawals to export"
+// @Success 200 {file} file "pain.001 document"
+// @Failure 400 {object} common.ProblemDetails "Invalid request"
+// @Failure 401 {object} common.ProblemDetails "Unauthorized"
+// @Failure 403 {object} common.ProblemDetails "Not an admin"
+// @Failure 422 {object} common.ProblemDetails "Incomplete payout destinations"
+// @Failure 500 {object} common.ProblemDetails "Internal server error"
+// @Failure 501 {object} common.ProblemDetails "Payout export not configured"
+// @Router /admin/payouts/pain001 [post]
+// @Security Bearer
+func ExportPain001(svc *payoutexportsvc.Service) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		input, err := common.BindAndValidate[Pain001Request](c)
+		if input == nil {
+			return err // error response already written
+		}
+		var executionDate time.Time
+		if input.ExecutionDate != "" {
+			executionDate, err = time.Parse(dateLayout, input.ExecutionDate)
+			if err != nil {
+				return common.ProblemDetailsJSON(
+					c,
+					"Invalid date",
+					err,
+					"execution_date must be a date in YYYY-MM-DD format",
+					fiber.StatusBadRequest,
+				)
+			}
+		}
+
+		export, err := svc.ExportPain001(c.UserContext(), input.TransactionIDs, executionDate)
+		var verr *iso20022.ValidationError
+		if errors.As(err, &verr) {
+			return common.ProblemDetailsJSON(
+				c,
+				"Incomplete payout destinations",
+				err,
+				"Some withdrawals cannot be paid by credit transfer",
+				verr.Problems,
+				fiber.StatusUnprocessableEntity,
+			)
+		}
+		if err != nil {
+			log.Error("failed to export payouts", "error", err)
+			return common.ProblemDetailsJSON(c, "Failed to export payouts", err)
+		}
+
+		c.Set(fiber.HeaderContentType, fiber.MIMEApplicationXMLCharsetUTF8)
+		c.Set(
+			fiber.HeaderContentDisposition,
+			fmt.Sprintf(`attachment; filename="%s.xml"`, export.MessageID),
+		)
+		return c.Status(fiber.StatusOK).Send(export.Document)
+	}
+}
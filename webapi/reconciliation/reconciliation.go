@@ -0,0 +1,105 @@
+// Package reconciliation exposes payout reconciliation reports to operators.
+package reconciliation
+
+import (
+	"errors"
+	"time"
+
+	"github.com/amirasaad/fintech/pkg/config"
+	"github.com/amirasaad/fintech/pkg/domain/user"
+	"github.com/amirasaad/fintech/pkg/middleware"
+	reconciliationsvc "github.com/amirasaad/fintech/pkg/service/reconciliation"
+	"github.com/amirasaad/fintech/webapi/common"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/log"
+)
+
+// dateLayout is the format of the from and to query parameters.
+const dateLayout = "2006-01-02"
+
+// Routes registers the reconciliation admin routes.
+//
+// Routes:
+//   - GET /admin/reconciliation/stripe : Reconcile Stripe payouts against withdrawals.
+func Routes(
+	app fiber.Router,
+	svc *reconciliationsvc.Service,
+	cfg *config.App,
+) {
+	app.Get(
+		"/admin/reconciliation/stripe",
+		middleware.JwtProtected(cfg.Auth.Jwt),
+		middleware.RequireRole(user.RoleAdmin),
+		StripeReport(svc),
+	)
+}
+
+// StripeReport returns a Fiber handler that reconciles Stripe payouts against
+// internal withdrawal transactions.
+// @Summary Reconcile Stripe payouts (admin only)
+// @Description Matches Stripe transfers to internal withdrawals by their transaction_id
+// metadata and reports matched, unmatched-in-Stripe and unmatched-internally entries.
+// Dates are UTC and inclusive; both default to yesterday.
+// @Tags admin
+// @Produce json
+// @Param from query string false "First day (YYYY-MM-DD)"
+// @Param to query string false "Last day (YYYY-MM-DD)"
+// @Success 200 {object} common.Response{data=reconciliation.Report} "Reconciliation report"
+// @Failure 400 {object} common.ProblemDetails "Invalid date range"
+// @Failure 401 {object} common.ProblemDetails "Unauthorized"
+// @Failure 403 {object} common.ProblemDetails "Not an admin"
+// @Failure 500 {object} common.ProblemDetails "Internal server error"
+// @Router /admin/reconciliation/stripe [get]
+// @Security Bearer
+func StripeReport(svc *reconciliationsvc.Service) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		yesterday := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, -1)
+		from, err := parseDate(c.Query("from"), yesterday)
+		if err != nil {
+			return invalidDate(c, "from", err)
+		}
+		last, err := parseDate(c.Query("to"), from)
+		if err != nil {
+			return invalidDate(c, "to", err)
+		}
+		to := last.AddDate(0, 0, 1)
+
+		report, err := svc.Reconcile(c.UserContext(), from, to)
+		if errors.Is(err, reconciliationsvc.ErrInvalidRange) {
+			return common.ProblemDetailsJSON(
+				c,
+				"Invalid date range",
+				err,
+				"to must not be before from and the range must not exceed 31 days",
+			)
+		}
+		if err != nil {
+			log.Error("failed to reconcile stripe payouts", "error", err)
+			return common.ProblemDetailsJSON(c, "Failed to reconcile payouts", err)
+		}
+		return common.SuccessResponseJSON(
+			c,
+			fiber.StatusOK,
+			"Reconciliation report generated",
+			report,
+		)
+	}
+}
+
+func parseDate(value string, fallback time.Time) (time.Time, error) {
+	if value == "" {
+		return fallback, nil
+	}
+	return time.Parse(dateLayout, value)
+}
+
+func invalidDate(c *fiber.Ctx, param string, err error) error {
+	return common.ProblemDetailsJSON(
+		c,
+		"Invalid date",
+		err,
+		param+" must be a date in YYYY-MM-DD format",
+		fiber.StatusBadRequest,
+	)
+}
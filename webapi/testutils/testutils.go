@@ -17,7 +17,6 @@ import (
 
 	"github.com/amirasaad/fintech/pkg/app"
 	"github.com/amirasaad/fintech/pkg/config"
-	pkgeventbus "github.com/amirasaad/fintech/pkg/eventbus"
 	"github.com/amirasaad/fintech/pkg/registry"
 
 	"github.com/amirasaad/fintech/infra/eventbus"
@@ -162,12 +161,7 @@ func (s *E2ETestSuite) setupApp() {
 		driver = strings.TrimSpace(strings.ToLower(s.cfg.EventBus.Driver))
 	}
 
-	var eventBus pkgeventbus.Bus
-
-	switch driver {
-	case "", "memory":
-		eventBus = eventbus.NewWithMemoryAsync(logger)
-	case "redis":
+	if driver == eventbus.DriverRedis {
 		redisContainer, containerErr := testcontainers.GenericContainer(
 			ctx,
 			testcontainers.GenericContainerRequest{
@@ -185,35 +179,19 @@ func (s *E2ETestSuite) setupApp() {
 
 		endpoint, endpointErr := redisContainer.Endpoint(ctx, "")
 		s.Require().NoError(endpointErr)
-
-		eventBus, err = eventbus.NewWithRedis(
-			"redis://"+endpoint,
-			logger,
-			&eventbus.RedisEventBusConfig{
-				DLQRetryInterval: 5 * time.Minute,
-				DLQBatchSize:     10,
-			},
-		)
-		s.Require().NoError(err)
+		s.cfg.EventBus.RedisURL = "redis://" + endpoint
 
 		s.T().Cleanup(func() {
 			_ = redisContainer.Terminate(ctx)
 		})
-	case "kafka":
-		kafkaBus, kafkaBusErr := eventbus.NewWithKafka(
-			s.cfg.EventBus.KafkaBrokers,
-			logger,
-			&eventbus.KafkaEventBusConfig{
-				GroupID:          s.cfg.EventBus.KafkaGroupID,
-				TopicPrefix:      s.cfg.EventBus.KafkaTopic,
-				DLQRetryInterval: 5 * time.Minute,
-				DLQBatchSize:     10,
-			},
-		)
-		s.Require().NoError(kafkaBusErr)
-		eventBus = kafkaBus
-	default:
-		s.T().Fatalf("unsupported event bus driver: %s", driver)
+	}
+
+	eventBus, err := eventbus.NewFromConfig(s.cfg, logger)
+	s.Require().NoError(err)
+	if driver != "" && driver != eventbus.DriverMemory {
+		// The factory falls back to memory when the backend is unreachable.
+		_, isMemory := eventBus.(*eventbus.MemoryAsyncEventBus)
+		s.Require().False(isMemory, "event bus %s is unreachable", driver)
 	}
 
 	// Create registry providers for each service with in-memory storage
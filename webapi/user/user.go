@@ -16,7 +16,7 @@ import (
 // Routes registers HTTP routes for user-related operations.
 // Sets up endpoints for user creation, retrieval, update, and deletion.
 func Routes(
-	app *fiber.App,
+	app fiber.Router,
 	userSvc *usersvc.Service,
 	authSvc *authsvc.Service,
 	cfg *config.App,
@@ -58,7 +58,7 @@ func GetUser(userSvc *usersvc.Service) fiber.Handler {
 			)
 		}
 		user, err := userSvc.GetUser(
-			c.Context(),
+			c.UserContext(),
 			id.String(),
 		)
 		if err != nil || user == nil {
@@ -106,7 +106,7 @@ func CreateUser(userSvc *usersvc.Service) fiber.Handler {
 				fiber.StatusBadRequest)
 		}
 		user, err := userSvc.CreateUser(
-			c.Context(),
+			c.UserContext(),
 			input.Username,
 			input.Email,
 			input.Password)
@@ -176,7 +176,7 @@ func UpdateUser(
 			return common.ProblemDetailsJSON(c, "Forbidden", nil,
 				fiber.StatusUnauthorized)
 		}
-		err = userSvc.UpdateUser(c.Context(), id.String(), &dto.UserUpdate{
+		err = userSvc.UpdateUser(c.UserContext(), id.String(), &dto.UserUpdate{
 			Names: &input.Names,
 		})
 		if err != nil {
@@ -188,7 +188,7 @@ func UpdateUser(
 				"missing user context", fiber.StatusUnauthorized)
 		}
 		// Get the updated user to return in response
-		updatedUser, err := userSvc.GetUser(c.Context(), id.String())
+		updatedUser, err := userSvc.GetUser(c.UserContext(), id.String())
 		if err != nil || updatedUser == nil {
 			return common.ProblemDetailsJSON(
 				c,
@@ -270,7 +270,7 @@ func DeleteUser(
 			)
 		}
 		// Retrieve user to get email for password validation
-		user, err := userSvc.GetUser(c.Context(), id.String())
+		user, err := userSvc.GetUser(c.UserContext(), id.String())
 		if err != nil {
 			log.Errorf("Error getting user for password validation: %v", err)
 			return common.ProblemDetailsJSON(
@@ -292,7 +292,7 @@ func DeleteUser(
 		}
 
 		if isValid, validErr := userSvc.ValidUser(
-			c.Context(),
+			c.UserContext(),
 			user.Email,
 			input.Password,
 		); validErr != nil || !isValid {
@@ -314,7 +314,7 @@ func DeleteUser(
 				fiber.StatusInternalServerError,
 			)
 		}
-		err = userSvc.DeleteUser(c.Context(), id.String())
+		err = userSvc.DeleteUser(c.UserContext(), id.String())
 		if err != nil {
 			return common.ProblemDetailsJSON(
 				c,
@@ -4,22 +4,36 @@
 // - auth: Authentication endpoints
 // - user: User management endpoints
 // - currency: Currency and exchange rate endpoints
+// - metrics: Operational metrics
+// - reconciliation: Payout reconciliation reports
+// - featureflag: Feature flag administration
+// - payoutexport: ISO 20022 payout exports
+// - paymentmethod: Payment methods offered at checkout
+// - exchangerate: Public conversion quotes and provider quota usage
+// - eventconsumer: Pausing and resuming event bus consumers
+// - diagnostics: The effective configuration, secrets redacted
 package webapi
 
 import (
-	"errors"
-	"strings"
-
 	"github.com/amirasaad/fintech/pkg/app"
+	"github.com/amirasaad/fintech/pkg/eventbus"
 	accountweb "github.com/amirasaad/fintech/webapi/account"
 	authweb "github.com/amirasaad/fintech/webapi/auth"
 	checkoutweb "github.com/amirasaad/fintech/webapi/checkout"
 	"github.com/amirasaad/fintech/webapi/common"
 	currencyweb "github.com/amirasaad/fintech/webapi/currency"
+	diagnosticsweb "github.com/amirasaad/fintech/webapi/diagnostics"
+	eventconsumerweb "github.com/amirasaad/fintech/webapi/eventconsumer"
+	exchangerateweb "github.com/amirasaad/fintech/webapi/exchangerate"
+	featureflagweb "github.com/amirasaad/fintech/webapi/featureflag"
+	maintenanceweb "github.com/amirasaad/fintech/webapi/maintenance"
+	metricsweb "github.com/amirasaad/fintech/webapi/metrics"
 	"github.com/amirasaad/fintech/webapi/payment"
+	paymentmethodweb "github.com/amirasaad/fintech/webapi/paymentmethod"
+	payoutexportweb "github.com/amirasaad/fintech/webapi/payoutexport"
+	reconciliationweb "github.com/amirasaad/fintech/webapi/reconciliation"
 	userweb "github.com/amirasaad/fintech/webapi/user"
 	"github.com/gofiber/fiber/v2"
-	"github.com/gofiber/fiber/v2/middleware/limiter"
 	"github.com/gofiber/fiber/v2/middleware/logger"
 	"github.com/gofiber/fiber/v2/middleware/recover"
 
@@ -34,53 +48,59 @@ func SetupApp(app *app.App) *fiber.App {
 	currencySvc := app.CurrencyService
 	checkoutSvc := app.CheckoutService
 
-	fiberApp := fiber.New(fiber.Config{
+	fiberCfg := fiber.Config{
 		ErrorHandler: func(c *fiber.Ctx, err error) error {
 			return common.ProblemDetailsJSON(c, "Internal Server Error", err)
 		},
-	})
-	fiberApp.Get("/swagger/*", swagger.New(swagger.Config{
+		// c.IP() reads the client address from the proxy header only for
+		// requests from a trusted proxy.
+		EnableTrustedProxyCheck: true,
+		EnableIPValidation:      true,
+	}
+	if srv := app.Config.Server; srv != nil {
+		fiberCfg.TrustedProxies = srv.TrustedProxies
+		fiberCfg.ProxyHeader = srv.ProxyHeader
+	}
+	fiberApp := fiber.New(fiberCfg)
+
+	// Routes are mounted under API_PREFIX. The health check, metrics, Swagger
+	// UI and debug routes stay at the root unless API_OPS_AT_ROOT is false.
+	var api, ops fiber.Router = fiberApp, fiberApp
+	prefix := ""
+	if cfg := app.Config.API; cfg != nil && cfg.Prefix != "" {
+		prefix = cfg.Prefix
+		api = fiberApp.Group(prefix)
+		if !cfg.OpsAtRoot {
+			ops = api
+		}
+	}
+
+	ops.Get("/swagger/*", swagger.New(swagger.Config{
 		TryItOutEnabled:      true,
 		WithCredentials:      true,
 		PersistAuthorization: true,
-		OAuth2RedirectUrl:    "/auth/login",
+		OAuth2RedirectUrl:    prefix + "/auth/login",
 	}))
 
-	// Configure rate limiting middleware
-	// Uses X-Forwarded-For header when behind a proxy
-	// Falls back to X-Real-IP or direct IP if needed
-	fiberApp.Use(limiter.New(limiter.Config{
-		Max:        app.Config.RateLimit.MaxRequests,
-		Expiration: app.Config.RateLimit.Window,
-		KeyGenerator: func(c *fiber.Ctx) string {
-			// Use X-Forwarded-For header if available (for load balancers/proxies)
-			// Fall back to X-Real-IP, then to direct IP
-			if forwardedFor := c.Get("X-Forwarded-For"); forwardedFor != "" {
-				// Take the first IP in the chain
-				if commaIndex := strings.Index(forwardedFor, ","); commaIndex != -1 {
-					return strings.TrimSpace(forwardedFor[:commaIndex])
-				}
-				return strings.TrimSpace(forwardedFor)
-			}
-			if realIP := c.Get("X-Real-IP"); realIP != "" {
-				return realIP
-			}
-			return c.IP()
-		},
-		LimitReached: func(c *fiber.Ctx) error {
-			return common.ProblemDetailsJSON(
-				c,
-				"Too Many Requests",
-				errors.New("rate limit exceeded"),
-				fiber.StatusTooManyRequests,
-			)
-		},
-	}))
+	// Tracing and request metrics come first so rejected requests are traced
+	// and counted too.
+	fiberApp.Use(common.Tracing())
+	httpMetrics := metricsweb.NewHTTPMetrics()
+	fiberApp.Use(httpMetrics.Middleware())
+
+	// CORS runs first so preflight requests are answered before rate limiting
+	// and error responses carry the headers browsers need to read them.
+	fiberApp.Use(common.CORS(app.Config.CORS))
+
+	// Rate limit each client, identified by its address as seen by the
+	// trusted proxies in front of the server.
+	fiberApp.Use(common.RateLimit(app.Config.RateLimit))
 	fiberApp.Use(recover.New())
 	fiberApp.Use(logger.New())
+	fiberApp.Use(common.RequestTimeout(app.Config.RequestTimeout))
 
 	// Health check endpoint
-	fiberApp.Get(
+	ops.Get(
 		"/",
 		func(c *fiber.Ctx) error {
 			return c.SendString("FinTech API is running! 🚀")
@@ -88,7 +108,7 @@ func SetupApp(app *app.App) *fiber.App {
 	)
 
 	// Debug endpoint to list all routes
-	fiberApp.Get("/debug/routes", func(c *fiber.Ctx) error {
+	ops.Get("/debug/routes", func(c *fiber.Ctx) error {
 		routes := fiberApp.GetRoutes()
 		var routeList []map[string]interface{}
 		for _, route := range routes {
@@ -102,17 +122,63 @@ func SetupApp(app *app.App) *fiber.App {
 		return c.JSON(routeList)
 	})
 
-	// Payment event processor for Stripe webhooks
+	// Database connection pool, HTTP request, command bus and exchange rate
+	// quota metrics
+	collectors := []metricsweb.Collector{httpMetrics}
+	if app.CommandMetrics != nil {
+		collectors = append(collectors, app.CommandMetrics)
+	}
+	if app.Deps.ExchangeRateQuota != nil {
+		collectors = append(collectors, app.Deps.ExchangeRateQuota)
+	}
+	if len(app.Deps.DBReplicaPools) > 0 {
+		collectors = append(collectors, metricsweb.DBReplicaPools(app.Deps.DBReplicaPools))
+	}
+	metricsweb.Routes(ops, app.Deps.DBPool, collectors...)
+
+	// Payment event processor for Stripe webhooks. Its path is registered
+	// with Stripe and already versioned, so it ignores API_PREFIX.
 	fiberApp.Post(
 		"/api/v1/webhooks/stripe",
 		payment.StripeWebhookHandler(app.Deps.PaymentProvider),
 	)
 
-	// Initialize account routes which include Stripe Connect routes
-	accountweb.Routes(fiberApp, accountSvc, authSvc, app.StripeConnectService, app.Config)
-	userweb.Routes(fiberApp, userSvc, authSvc, app.Config)
-	authweb.Routes(fiberApp, authSvc)
-	currencyweb.Routes(fiberApp, currencySvc, authSvc, app.Config)
-	checkoutweb.Routes(fiberApp, checkoutSvc, authSvc, app.Config)
+	// Initialize account routes which include Stripe Connect routes. They are
+	// served unversioned, in the v1 shape, and under /v1 and /v2.
+	for _, r := range []fiber.Router{
+		api,
+		api.Group("/v1", accountweb.UseVersion(accountweb.V1)),
+		api.Group("/v2", accountweb.UseVersion(accountweb.V2)),
+	} {
+		accountweb.Routes(
+			r,
+			accountSvc,
+			app.CommandBus,
+			authSvc,
+			app.StripeConnectService,
+			currencySvc,
+			checkoutSvc,
+			app.MaintenanceService,
+			app.Config,
+		)
+	}
+	userweb.Routes(api, userSvc, authSvc, app.Config)
+	authweb.Routes(api, authSvc)
+	currencyweb.Routes(api, currencySvc, app.ExchangeRateService, authSvc, app.Config)
+	checkoutweb.Routes(api, checkoutSvc, authSvc, app.Config)
+	paymentmethodweb.Routes(api, app.Config)
+	featureflagweb.Routes(api, app.FeatureFlagService, app.Config)
+	diagnosticsweb.Routes(api, app.FeatureFlagService, app.Config)
+	maintenanceweb.Routes(api, app.MaintenanceService, authSvc, app.Config)
+	payoutexportweb.Routes(api, app.PayoutExportService, app.Config)
+	if app.ReconciliationService != nil {
+		reconciliationweb.Routes(api, app.ReconciliationService, app.Config)
+	}
+	exchangerateweb.Routes(
+		api, app.ExchangeRateService, app.Deps.ExchangeRateQuota, app.Config,
+	)
+	if bus, ok := app.Deps.EventBus.(eventbus.PausableBus); ok {
+		eventconsumerweb.Routes(api, bus, app.Config)
+	}
 	return fiberApp
 }